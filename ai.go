@@ -1,9 +1,12 @@
 package main
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"strings"
@@ -12,77 +15,279 @@ import (
 )
 
 type Summarizer struct {
-	baseURL string
-	apiKey  string
-	model   string
-	client  *http.Client
+	name           string
+	api            string
+	baseURL        string
+	apiKey         string
+	model          string
+	embeddingModel string
+	language       string
+	maxInput       int
+	maxInputTokens int
+	strategy       string
+	retries        int
+	limiter        *summaryRateLimiter
+	temperature    *float64
+	client         *http.Client
+	fallbacks      []*Summarizer
+	profiles       map[string]*Summarizer
+	recordUsage    func(UsageRecord)
+	ctx            context.Context
 }
 
+const defaultTemperature = 0.2
+
+const defaultMaxInputChars = 10000
+
 var aiJSONMarshal = json.Marshal
 
+type summarizerPreset struct {
+	api       string
+	baseURL   string
+	model     string
+	apiKeyEnv string
+	timeout   time.Duration
+	local     bool
+}
+
+var summarizerPresets = map[string]summarizerPreset{
+	"ollama":    {baseURL: "http://localhost:11434/v1", model: "llama3.2", timeout: 5 * time.Minute, local: true},
+	"llamacpp":  {baseURL: "http://localhost:8080/v1", model: "local", timeout: 5 * time.Minute, local: true},
+	"anthropic": {api: summarizerAPIAnthropic, baseURL: "https://api.anthropic.com/v1", model: "claude-3-5-haiku-latest", apiKeyEnv: "ANTHROPIC_API_KEY"},
+	"gemini":    {api: summarizerAPIGemini, baseURL: "https://generativelanguage.googleapis.com/v1beta", model: "gemini-2.0-flash", apiKeyEnv: "GEMINI_API_KEY"},
+}
+
 func NewSummarizerFromEnv() *Summarizer {
-	base := strings.TrimSpace(os.Getenv("LM_BASE_URL"))
+	return NewSummarizer(Config{})
+}
+
+func NewSummarizer(cfg Config) *Summarizer {
+	s := newSummarizerChain(cfg)
+	if s == nil || len(cfg.ProviderSettings) == 0 {
+		return s
+	}
+	s.profiles = map[string]*Summarizer{}
+	for name, provider := range cfg.ProviderSettings {
+		provider.Name = name
+		if profile := newProviderSummarizer(provider, cfg); profile != nil {
+			s.profiles[name] = profile
+		}
+	}
+	return s
+}
+
+func newSummarizerChain(cfg Config) *Summarizer {
+	if len(cfg.Providers) == 0 || strings.TrimSpace(os.Getenv("LM_BASE_URL")) != "" {
+		return newProviderSummarizer(ProviderConfig{
+			Name:           "default",
+			Preset:         cfg.LMPreset,
+			API:            cfg.LMAPI,
+			BaseURL:        firstNonEmpty(strings.TrimSpace(os.Getenv("LM_BASE_URL")), cfg.LMBaseURL),
+			Model:          firstNonEmpty(os.Getenv("LM_MODEL"), cfg.LMModel),
+			APIKey:         firstNonEmpty(os.Getenv("LM_API_KEY"), cfg.LMAPIKey),
+			TimeoutSeconds: cfg.LMTimeoutSeconds,
+			Temperature:    cfg.LMTemperature,
+		}, cfg)
+	}
+	var chain *Summarizer
+	for _, name := range cfg.Providers {
+		provider, ok := cfg.ProviderSettings[name]
+		if !ok {
+			continue
+		}
+		provider.Name = name
+		s := newProviderSummarizer(provider, cfg)
+		if s == nil {
+			continue
+		}
+		if chain == nil {
+			chain = s
+		} else {
+			chain.fallbacks = append(chain.fallbacks, s)
+		}
+	}
+	return chain
+}
+
+func newProviderSummarizer(provider ProviderConfig, cfg Config) *Summarizer {
+	preset := summarizerPresets[provider.Preset]
+	base := strings.TrimSpace(firstNonEmpty(provider.BaseURL, preset.baseURL))
 	if base == "" {
 		return nil
 	}
-	model := strings.TrimSpace(os.Getenv("LM_MODEL"))
+	model := strings.TrimSpace(firstNonEmpty(provider.Model, preset.model))
 	if model == "" {
 		model = "gpt-4o-mini"
 	}
+	timeout := preset.timeout
+	if provider.TimeoutSeconds > 0 {
+		timeout = time.Duration(provider.TimeoutSeconds) * time.Second
+	}
+	if timeout == 0 {
+		timeout = httpTimeout(cfg, 60*time.Second)
+	}
+	apiKey := strings.TrimSpace(provider.APIKey)
+	if apiKey == "" && preset.apiKeyEnv != "" {
+		apiKey = strings.TrimSpace(os.Getenv(preset.apiKeyEnv))
+	}
 	return &Summarizer{
-		baseURL: strings.TrimRight(base, "/"),
-		apiKey:  strings.TrimSpace(os.Getenv("LM_API_KEY")),
-		model:   model,
-		client:  &http.Client{Timeout: 60 * time.Second},
+		name:           provider.Name,
+		api:            firstNonEmpty(provider.API, preset.api),
+		baseURL:        strings.TrimRight(base, "/"),
+		apiKey:         apiKey,
+		model:          model,
+		embeddingModel: strings.TrimSpace(firstNonEmpty(os.Getenv("LM_EMBEDDING_MODEL"), cfg.EmbeddingModel)),
+		language:       strings.TrimSpace(cfg.SummaryLanguage),
+		maxInput:       cfg.MaxInputChars,
+		maxInputTokens: cfg.MaxInputTokens,
+		strategy:       cfg.SummaryStrategy,
+		retries:        cfg.SummaryRetries,
+		limiter:        newSummaryRateLimiter(cfg.SummaryRequestsPerMinute),
+		temperature:    provider.Temperature,
+		client:         newHTTPClient(cfg, timeout),
 	}
 }
 
-func (s *Summarizer) GenerateSummary(title, content string) (string, string, error) {
+func (s *Summarizer) withContext(ctx context.Context) *Summarizer {
 	if s == nil {
-		return "", "", errors.New("summarizer not configured")
-	}
-	content = truncateText(content, 10000)
-	prompt := "Please summarize the following article:\n\nTitle: " + title + "\n\nContent:\n" + content
-	payload := chatRequest{
-		Model: s.model,
-		Messages: []chatMessage{
-			{Role: "system", Content: summarySystemPrompt()},
-			{Role: "user", Content: prompt},
-		},
-		Temperature: 0.2,
-	}
-	blob, err := aiJSONMarshal(payload)
-	if err != nil {
-		return "", "", err
+		return nil
 	}
-	endpoint := s.baseURL + "/v1/chat/completions"
-	if strings.Contains(s.baseURL, "/v1") {
-		endpoint = s.baseURL + "/chat/completions"
+	bound := *s
+	bound.ctx = ctx
+	bound.fallbacks = make([]*Summarizer, len(s.fallbacks))
+	for i, fallback := range s.fallbacks {
+		bound.fallbacks[i] = fallback.withContext(ctx)
 	}
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(blob))
-	if err != nil {
-		return "", "", err
+	return &bound
+}
+
+func (s *Summarizer) requestContext() context.Context {
+	if s.ctx == nil {
+		return context.Background()
 	}
-	req.Header.Set("content-type", "application/json")
-	if s.apiKey != "" {
-		req.Header.Set("authorization", "Bearer "+s.apiKey)
+	return s.ctx
+}
+
+func (s *Summarizer) forProfile(name string) *Summarizer {
+	if s == nil || name == "" || name == s.name {
+		return s
+	}
+	profile, ok := s.profiles[name]
+	if !ok {
+		logFor("summarizer").Warn("unknown profile, using default providers", "profile", name)
+		return s
 	}
-	resp, err := s.client.Do(req)
+	bound := *profile
+	bound.ctx = s.ctx
+	bound.fallbacks = nil
+	for _, provider := range s.providers() {
+		if provider.name != name {
+			bound.fallbacks = append(bound.fallbacks, provider)
+		}
+	}
+	return &bound
+}
+
+func (s *Summarizer) setUsageRecorder(record func(UsageRecord)) {
+	if s == nil {
+		return
+	}
+	for _, provider := range s.providers() {
+		provider.recordUsage = record
+	}
+	for _, profile := range s.profiles {
+		profile.recordUsage = record
+	}
+}
+
+func (s *Summarizer) logUsage(model string, promptTokens int, completionTokens int) {
+	if s.recordUsage != nil {
+		s.recordUsage(UsageRecord{Provider: s.name, Model: model, PromptTokens: promptTokens, CompletionTokens: completionTokens})
+	}
+}
+
+func (s *Summarizer) providers() []*Summarizer {
+	return append([]*Summarizer{s}, s.fallbacks...)
+}
+
+func (s *Summarizer) GenerateSummary(title, content string) (string, string, error) {
+	summary, err := s.Summarize(title, content)
 	if err != nil {
 		return "", "", err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", "", errors.New("summarizer http error")
+	return summary.Content, summary.Model, nil
+}
+
+func (s *Summarizer) Summarize(title, content string) (Summary, error) {
+	return s.SummarizeWith(title, content, SummaryOptions{})
+}
+
+func (s *Summarizer) SummarizeWith(title, content string, opts SummaryOptions) (Summary, error) {
+	if s == nil {
+		return Summary{}, errors.New("summarizer not configured")
 	}
-	var parsed chatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-		return "", "", err
+	s = s.forProfile(opts.Profile)
+	if opts.Model != "" && opts.Model != s.model {
+		override := *s
+		override.model = opts.Model
+		s = &override
+	}
+	system := s.withLanguage(summarySystemPromptFor(opts))
+	if s.strategy == summaryStrategyChunk && len(content) > s.inputBudget() {
+		notes, usage, err := s.summarizeChunks(title, content, system)
+		if err != nil {
+			return Summary{}, err
+		}
+		prompt := "Please summarize the following article from these notes on each of its parts:\n\n" + wrapArticle(title, notes)
+		summary, err := s.complete(system, prompt)
+		summary.PromptTokens += usage.PromptTokens
+		summary.CompletionTokens += usage.CompletionTokens
+		return summary, err
+	}
+	prompt := "Please summarize the following article:\n\n" + wrapArticle(title, s.limitInput(content))
+	return s.complete(system, prompt)
+}
+
+func (s *Summarizer) complete(system string, prompt string) (Summary, error) {
+	return s.chat([]chatMessage{
+		{Role: "system", Content: guardSystemPrompt(system)},
+		{Role: "user", Content: prompt},
+	})
+}
+
+func (s *Summarizer) chat(messages []chatMessage) (Summary, error) {
+	var errs []error
+	log := logFor("summarizer")
+	for _, provider := range s.providers() {
+		start := time.Now()
+		summary, err := provider.chatWithRetry(messages)
+		if err == nil {
+			log.Info("completion", "provider", provider.name, "model", provider.model, "duration", time.Since(start), "prompt_tokens", summary.PromptTokens, "completion_tokens", summary.CompletionTokens)
+			provider.logUsage(firstNonEmpty(summary.Model, provider.model), summary.PromptTokens, summary.CompletionTokens)
+			return summary, nil
+		}
+		log.Warn("completion failed", "provider", provider.name, "model", provider.model, "duration", time.Since(start), "err", err)
+		errs = append(errs, err)
+	}
+	if len(errs) == 1 {
+		return Summary{}, errs[0]
 	}
-	if len(parsed.Choices) == 0 {
-		return "", "", errors.New("empty summary response")
+	return Summary{}, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+}
+
+func (s *Summarizer) chatOnce(messages []chatMessage) (Summary, error) {
+	temperature := defaultTemperature
+	if s.temperature != nil {
+		temperature = *s.temperature
+	}
+	return s.backend().chat(s, messages, temperature)
+}
+
+func (s *Summarizer) endpoint(path string) string {
+	if strings.Contains(s.baseURL, "/v1") {
+		return s.baseURL + path
 	}
-	return strings.TrimSpace(parsed.Choices[0].Message.Content), s.model, nil
+	return s.baseURL + "/v1" + path
 }
 
 type chatRequest struct {
@@ -100,13 +305,69 @@ type chatResponse struct {
 	Choices []struct {
 		Message chatMessage `json:"message"`
 	} `json:"choices"`
+	Usage chatUsage `json:"usage"`
 }
 
-func summarySystemPrompt() string {
-	return "Summarize this article as 3-5 bullet points.\n" +
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+var summaryStyles = map[string]string{
+	"bullets": "Summarize this article as 3-5 bullet points.\n" +
 		"Output ONLY the bullet points - no introductions, conclusions, or commentary.\n" +
 		"Start each line with \"- \" and state one key fact or finding.\n" +
-		"Never write phrases like \"Here are the key points\" or \"In summary\" - just the bullets."
+		"Never write phrases like \"Here are the key points\" or \"In summary\" - just the bullets.",
+	"paragraph": "Summarize this article in one short paragraph of 3-4 sentences.\n" +
+		"Output ONLY the paragraph - no introductions, headings, or commentary.\n" +
+		"Capture the author's main argument and how they support it.",
+	"tldr": "Summarize this article in a single sentence of at most 30 words.\n" +
+		"Output ONLY the sentence - no introductions or commentary.",
+}
+
+func summarySystemPrompt() string {
+	return summaryStyles["bullets"]
+}
+
+func summarySystemPromptFor(opts SummaryOptions) string {
+	if strings.TrimSpace(opts.Prompt) != "" {
+		return opts.Prompt
+	}
+	if style, ok := summaryStyles[opts.Style]; ok {
+		return style
+	}
+	return summarySystemPrompt()
+}
+
+func (s *Summarizer) withLanguage(system string) string {
+	if s.language == "" {
+		return system
+	}
+	return system + "\nAlways write your response in " + s.language + ", even if the article is in another language."
+}
+
+func articleContentHash(article Article) string {
+	sum := sha256.Sum256([]byte(article.Title + "\n" + firstNonEmpty(article.ContentText, article.Content)))
+	return hex.EncodeToString(sum[:])
+}
+
+// summaryContentHash extends the article hash with any per-feed summary
+// options, so changing a feed's model, prompt or style marks its summaries
+// stale. Feeds without overrides keep the plain article hash.
+func summaryContentHash(article Article, opts SummaryOptions) string {
+	if opts == (SummaryOptions{}) {
+		return articleContentHash(article)
+	}
+	sum := sha256.Sum256([]byte(articleContentHash(article) + "\n" + opts.Model + "\n" + opts.Prompt + "\n" + opts.Style + "\n" + opts.Profile))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *Summarizer) limitInput(content string) string {
+	budget := s.inputBudget()
+	if len(content) <= budget {
+		return content
+	}
+	return smartTruncate(content, budget)
 }
 
 func truncateText(value string, max int) string {
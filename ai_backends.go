@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	summarizerAPIOpenAI    = "openai"
+	summarizerAPIAnthropic = "anthropic"
+	summarizerAPIGemini    = "gemini"
+	anthropicVersion       = "2023-06-01"
+	anthropicMaxTokens     = 1024
+)
+
+type chatBackend interface {
+	chat(s *Summarizer, messages []chatMessage, temperature float64) (Summary, error)
+	models(s *Summarizer) ([]string, error)
+	authorize(req *http.Request, apiKey string)
+}
+
+type openAIBackend struct{}
+
+type anthropicBackend struct{}
+
+type geminiBackend struct{}
+
+var summarizerBackends = map[string]chatBackend{
+	summarizerAPIOpenAI:    openAIBackend{},
+	summarizerAPIAnthropic: anthropicBackend{},
+	summarizerAPIGemini:    geminiBackend{},
+}
+
+func parseSummarizerAPI(value string) (string, error) {
+	api := strings.ToLower(trimQuotes(value))
+	if _, ok := summarizerBackends[api]; !ok && api != "" {
+		return "", fmt.Errorf("invalid api: %q (expected openai, anthropic, or gemini)", value)
+	}
+	return api, nil
+}
+
+func (s *Summarizer) backend() chatBackend {
+	if backend, ok := summarizerBackends[s.api]; ok {
+		return backend
+	}
+	return openAIBackend{}
+}
+
+func (s *Summarizer) doJSON(method string, path string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		blob, err := aiJSONMarshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(blob)
+	}
+	req, err := http.NewRequestWithContext(s.requestContext(), method, s.endpoint(path), body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("content-type", "application/json")
+	}
+	if s.apiKey != "" {
+		s.backend().authorize(req, s.apiKey)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newSummarizerHTTPError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && (payload != nil || !errors.Is(err, io.EOF)) {
+		return err
+	}
+	return nil
+}
+
+func (openAIBackend) authorize(req *http.Request, apiKey string) {
+	req.Header.Set("authorization", "Bearer "+apiKey)
+}
+
+func (openAIBackend) chat(s *Summarizer, messages []chatMessage, temperature float64) (Summary, error) {
+	var parsed chatResponse
+	if err := s.doJSON(http.MethodPost, "/chat/completions", chatRequest{Model: s.model, Messages: messages, Temperature: temperature}, &parsed); err != nil {
+		return Summary{}, err
+	}
+	if len(parsed.Choices) == 0 {
+		return Summary{}, errors.New("empty summary response")
+	}
+	return Summary{
+		Content:          strings.TrimSpace(parsed.Choices[0].Message.Content),
+		Model:            s.model,
+		PromptTokens:     parsed.Usage.PromptTokens,
+		CompletionTokens: parsed.Usage.CompletionTokens,
+	}, nil
+}
+
+func (openAIBackend) models(s *Summarizer) ([]string, error) {
+	return listModelIDs(s)
+}
+
+func listModelIDs(s *Summarizer) ([]string, error) {
+	var parsed modelsResponse
+	if err := s.doJSON(http.MethodGet, "/models", nil, &parsed); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(parsed.Data))
+	for _, item := range parsed.Data {
+		models = append(models, item.ID)
+	}
+	return models, nil
+}
+
+type anthropicRequest struct {
+	Model       string        `json:"model"`
+	System      string        `json:"system,omitempty"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature float64       `json:"temperature"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+func (anthropicBackend) authorize(req *http.Request, apiKey string) {
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+}
+
+func (anthropicBackend) chat(s *Summarizer, messages []chatMessage, temperature float64) (Summary, error) {
+	system, turns := splitSystemMessages(messages)
+	payload := anthropicRequest{Model: s.model, System: system, Messages: turns, MaxTokens: anthropicMaxTokens, Temperature: temperature}
+	var parsed anthropicResponse
+	if err := s.doJSON(http.MethodPost, "/messages", payload, &parsed); err != nil {
+		return Summary{}, err
+	}
+	parts := []string{}
+	for _, block := range parsed.Content {
+		if block.Type == "text" {
+			parts = append(parts, block.Text)
+		}
+	}
+	if len(parts) == 0 {
+		return Summary{}, errors.New("empty summary response")
+	}
+	return Summary{
+		Content:          strings.TrimSpace(strings.Join(parts, "")),
+		Model:            s.model,
+		PromptTokens:     parsed.Usage.InputTokens,
+		CompletionTokens: parsed.Usage.OutputTokens,
+	}, nil
+}
+
+func (anthropicBackend) models(s *Summarizer) ([]string, error) {
+	return listModelIDs(s)
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent  `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent `json:"contents"`
+	GenerationConfig  struct {
+		Temperature float64 `json:"temperature"`
+	} `json:"generationConfig"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+type geminiModelsResponse struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+func (geminiBackend) authorize(req *http.Request, apiKey string) {
+	req.Header.Set("x-goog-api-key", apiKey)
+}
+
+func (geminiBackend) chat(s *Summarizer, messages []chatMessage, temperature float64) (Summary, error) {
+	system, turns := splitSystemMessages(messages)
+	payload := geminiRequest{}
+	if system != "" {
+		payload.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: system}}}
+	}
+	for _, turn := range turns {
+		role := turn.Role
+		if role == "assistant" {
+			role = "model"
+		}
+		payload.Contents = append(payload.Contents, geminiContent{Role: role, Parts: []geminiPart{{Text: turn.Content}}})
+	}
+	payload.GenerationConfig.Temperature = temperature
+	var parsed geminiResponse
+	if err := s.doJSON(http.MethodPost, "/models/"+s.model+":generateContent", payload, &parsed); err != nil {
+		return Summary{}, err
+	}
+	if len(parsed.Candidates) == 0 || len(parsed.Candidates[0].Content.Parts) == 0 {
+		return Summary{}, errors.New("empty summary response")
+	}
+	parts := []string{}
+	for _, part := range parsed.Candidates[0].Content.Parts {
+		parts = append(parts, part.Text)
+	}
+	return Summary{
+		Content:          strings.TrimSpace(strings.Join(parts, "")),
+		Model:            s.model,
+		PromptTokens:     parsed.UsageMetadata.PromptTokenCount,
+		CompletionTokens: parsed.UsageMetadata.CandidatesTokenCount,
+	}, nil
+}
+
+func (geminiBackend) models(s *Summarizer) ([]string, error) {
+	var parsed geminiModelsResponse
+	if err := s.doJSON(http.MethodGet, "/models", nil, &parsed); err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(parsed.Models))
+	for _, item := range parsed.Models {
+		models = append(models, strings.TrimPrefix(item.Name, "models/"))
+	}
+	return models, nil
+}
+
+func splitSystemMessages(messages []chatMessage) (string, []chatMessage) {
+	system := []string{}
+	turns := make([]chatMessage, 0, len(messages))
+	for _, message := range messages {
+		if message.Role == "system" {
+			system = append(system, message.Content)
+			continue
+		}
+		turns = append(turns, message)
+	}
+	return strings.Join(system, "\n\n"), turns
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func captureBackend(t *testing.T, api string, response string, seen *map[string]any, headers *http.Header, path *string) *Summarizer {
+	t.Helper()
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		*path = r.URL.Path
+		*headers = r.Header.Clone()
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(seen)
+		}
+		return newResponse(http.StatusOK, response, map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	preset := summarizerPresets[api]
+	return &Summarizer{api: api, baseURL: preset.baseURL, model: preset.model, apiKey: "secret", client: client}
+}
+
+func TestAnthropicBackend(t *testing.T) {
+	var seen map[string]any
+	var headers http.Header
+	var path string
+	s := captureBackend(t, summarizerAPIAnthropic, `{"content":[{"type":"text","text":"- one"},{"type":"text","text":"\n- two"}],"usage":{"input_tokens":12,"output_tokens":4}}`, &seen, &headers, &path)
+	summary, err := s.Summarize("Title", "Body")
+	if err != nil || summary.Content != "- one\n- two" || summary.PromptTokens != 12 || summary.CompletionTokens != 4 || summary.Model != "claude-3-5-haiku-latest" {
+		t.Fatalf("unexpected summary %+v %v", summary, err)
+	}
+	if path != "/v1/messages" || headers.Get("x-api-key") != "secret" || headers.Get("anthropic-version") != anthropicVersion || headers.Get("authorization") != "" {
+		t.Fatalf("unexpected request %s %v", path, headers)
+	}
+	messages, _ := seen["messages"].([]any)
+	if len(messages) != 1 || !strings.Contains(seen["system"].(string), "Summarize") || seen["max_tokens"].(float64) != anthropicMaxTokens {
+		t.Fatalf("unexpected payload %v", seen)
+	}
+	if first, _ := messages[0].(map[string]any); first["role"] != "user" {
+		t.Fatalf("expected system prompt lifted out of messages, got %v", messages)
+	}
+}
+
+func TestGeminiBackend(t *testing.T) {
+	var seen map[string]any
+	var headers http.Header
+	var path string
+	s := captureBackend(t, summarizerAPIGemini, `{"candidates":[{"content":{"role":"model","parts":[{"text":"- gem"}]}}],"usageMetadata":{"promptTokenCount":9,"candidatesTokenCount":3}}`, &seen, &headers, &path)
+	summary, err := s.chat([]chatMessage{{Role: "system", Content: "Be brief"}, {Role: "user", Content: "Hi"}, {Role: "assistant", Content: "Hello"}, {Role: "user", Content: "Sum up"}})
+	if err != nil || summary.Content != "- gem" || summary.PromptTokens != 9 || summary.CompletionTokens != 3 {
+		t.Fatalf("unexpected summary %+v %v", summary, err)
+	}
+	if path != "/v1beta/models/gemini-2.0-flash:generateContent" || headers.Get("x-goog-api-key") != "secret" {
+		t.Fatalf("unexpected request %s %v", path, headers)
+	}
+	contents, _ := seen["contents"].([]any)
+	if len(contents) != 3 || contents[1].(map[string]any)["role"] != "model" {
+		t.Fatalf("unexpected contents %v", contents)
+	}
+	instruction := seen["systemInstruction"].(map[string]any)["parts"].([]any)[0].(map[string]any)
+	if !strings.Contains(instruction["text"].(string), "Be brief") {
+		t.Fatalf("unexpected system instruction %v", instruction)
+	}
+
+	s = captureBackend(t, summarizerAPIGemini, `{"models":[{"name":"models/gemini-2.0-flash"},{"name":"models/gemini-1.5-pro"}]}`, &seen, &headers, &path)
+	models, err := s.ListModels()
+	if err != nil || len(models) != 2 || models[0] != "gemini-2.0-flash" || path != "/v1beta/models" {
+		t.Fatalf("unexpected models %v %v %s", models, err, path)
+	}
+	if _, err := s.Embed([]string{"x"}); err == nil {
+		t.Fatalf("expected embeddings to be unsupported")
+	}
+}
+
+func TestBackendEmptyResponses(t *testing.T) {
+	var seen map[string]any
+	var headers http.Header
+	var path string
+	for _, api := range []string{summarizerAPIAnthropic, summarizerAPIGemini} {
+		s := captureBackend(t, api, `{}`, &seen, &headers, &path)
+		if _, err := s.Summarize("Title", "Body"); err == nil || !strings.Contains(err.Error(), "empty summary response") {
+			t.Fatalf("%s: expected empty response error, got %v", api, err)
+		}
+	}
+}
+
+func TestConfigSummarizerAPI(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "from-env")
+	cfg := DefaultConfig()
+	input := "lm_api = \"Gemini\"\n\n[provider.claude]\npreset = \"anthropic\"\n\n[provider.proxy]\napi = \"anthropic\"\nbase_url = \"https://llm.example.com/v1\""
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.LMAPI != summarizerAPIGemini || cfg.ProviderSettings["proxy"].API != summarizerAPIAnthropic {
+		t.Fatalf("unexpected api settings %+v", cfg)
+	}
+	rendered := renderConfig(cfg)
+	if !strings.Contains(rendered, "lm_api = \"gemini\"") || !strings.Contains(rendered, "[provider.proxy]\napi = \"anthropic\"\nbase_url") {
+		t.Fatalf("unexpected rendered config %s", rendered)
+	}
+	claude := newProviderSummarizer(cfg.ProviderSettings["claude"], cfg)
+	if claude.api != summarizerAPIAnthropic || claude.apiKey != "from-env" || claude.baseURL != "https://api.anthropic.com/v1" {
+		t.Fatalf("unexpected anthropic preset %+v", claude)
+	}
+	if err := parseConfig("[provider.x]\napi = \"cohere\"", &cfg); err == nil || !strings.Contains(err.Error(), "invalid api for provider x") {
+		t.Fatalf("expected api error, got %v", err)
+	}
+}
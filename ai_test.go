@@ -1,11 +1,13 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestSummarizerFromEnv(t *testing.T) {
@@ -151,3 +153,257 @@ func TestSummarizerAPIKeyHeader(t *testing.T) {
 		t.Fatalf("expected summary success: %v", err)
 	}
 }
+
+func TestSummarizerUsage(t *testing.T) {
+	client := clientForResponse(http.StatusOK, `{"choices":[{"message":{"content":"- ok"}}],"usage":{"prompt_tokens":120,"completion_tokens":30}}`, map[string]string{"content-type": "application/json"})
+	s := &Summarizer{name: "local", baseURL: "http://example.test", model: "m", client: client}
+	records := []UsageRecord{}
+	s.setUsageRecorder(func(record UsageRecord) { records = append(records, record) })
+	summary, err := s.Summarize("Title", "Body")
+	if err != nil {
+		t.Fatalf("Summarize error: %v", err)
+	}
+	if summary.PromptTokens != 120 || summary.CompletionTokens != 30 || summary.Model != "m" {
+		t.Fatalf("unexpected usage: %+v", summary)
+	}
+	if _, err := s.Ask("Title", "Body", nil, "Why?"); err != nil {
+		t.Fatalf("Ask error: %v", err)
+	}
+	want := UsageRecord{Provider: "local", Model: "m", PromptTokens: 120, CompletionTokens: 30}
+	if len(records) != 2 || records[0] != want || records[1] != want {
+		t.Fatalf("expected one usage record per completion, got %+v", records)
+	}
+}
+
+func TestNewSummarizerPresets(t *testing.T) {
+	os.Unsetenv("LM_BASE_URL")
+	os.Unsetenv("LM_MODEL")
+	os.Unsetenv("LM_API_KEY")
+	s := NewSummarizer(Config{LMPreset: "ollama"})
+	if s == nil || s.baseURL != "http://localhost:11434/v1" || s.model != "llama3.2" || s.apiKey != "" {
+		t.Fatalf("unexpected ollama summarizer: %+v", s)
+	}
+	if s.client.Timeout != 5*time.Minute {
+		t.Fatalf("expected long preset timeout, got %v", s.client.Timeout)
+	}
+	s = NewSummarizer(Config{LMPreset: "llamacpp", LMModel: "qwen", LMAPIKey: "secret"})
+	if s.baseURL != "http://localhost:8080/v1" || s.model != "qwen" || s.apiKey != "secret" {
+		t.Fatalf("unexpected llamacpp summarizer: %+v", s)
+	}
+	s = NewSummarizer(Config{LMBaseURL: "http://gpu.local:8000/"})
+	if s.baseURL != "http://gpu.local:8000" || s.model != "gpt-4o-mini" || s.client.Timeout != 60*time.Second {
+		t.Fatalf("unexpected configured summarizer: %+v", s)
+	}
+
+	os.Setenv("LM_BASE_URL", "http://env.test")
+	os.Setenv("LM_MODEL", "env-model")
+	defer os.Unsetenv("LM_BASE_URL")
+	defer os.Unsetenv("LM_MODEL")
+	s = NewSummarizer(Config{LMPreset: "ollama"})
+	if s.baseURL != "http://env.test" || s.model != "env-model" {
+		t.Fatalf("expected env to override preset: %+v", s)
+	}
+	if got := s.endpoint("/models"); got != "http://env.test/v1/models" {
+		t.Fatalf("unexpected endpoint: %s", got)
+	}
+}
+
+func TestSummarizerSummarizeWithOverrides(t *testing.T) {
+	var captured chatRequest
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		return newResponse(http.StatusOK, `{"choices":[{"message":{"content":"ok"}}]}`, map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	s := &Summarizer{baseURL: "http://example.test", model: "default", client: client}
+
+	summary, err := s.SummarizeWith("Title", "Body", SummaryOptions{Model: "big", Style: "tldr"})
+	if err != nil {
+		t.Fatalf("SummarizeWith error: %v", err)
+	}
+	if summary.Model != "big" || captured.Model != "big" || !strings.HasPrefix(captured.Messages[0].Content, summaryStyles["tldr"]) {
+		t.Fatalf("unexpected request: %+v", captured)
+	}
+	if s.model != "default" {
+		t.Fatalf("override should not change summarizer model")
+	}
+
+	if _, err := s.SummarizeWith("Title", "Body", SummaryOptions{Prompt: "Custom prompt", Style: "tldr"}); err != nil {
+		t.Fatalf("SummarizeWith error: %v", err)
+	}
+	if captured.Model != "default" || !strings.HasPrefix(captured.Messages[0].Content, "Custom prompt\n") {
+		t.Fatalf("expected custom prompt, got %+v", captured)
+	}
+	if summarySystemPromptFor(SummaryOptions{Style: "unknown"}) != summarySystemPrompt() {
+		t.Fatalf("expected default prompt for unknown style")
+	}
+}
+
+func TestSummarizerLanguage(t *testing.T) {
+	var captured chatRequest
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		return newResponse(http.StatusOK, `{"choices":[{"message":{"content":"- ok"}}]}`, map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	s := &Summarizer{baseURL: "http://example.test", model: "m", client: client}
+	if _, err := s.Summarize("Titre", "Corps"); err != nil {
+		t.Fatalf("Summarize error: %v", err)
+	}
+	if strings.Contains(captured.Messages[0].Content, "Always write your response in") {
+		t.Fatalf("unexpected language instruction: %s", captured.Messages[0].Content)
+	}
+	s.language = "English"
+	if _, err := s.Summarize("Titre", "Corps"); err != nil {
+		t.Fatalf("Summarize error: %v", err)
+	}
+	if !strings.Contains(captured.Messages[0].Content, "Always write your response in English") {
+		t.Fatalf("expected language instruction: %s", captured.Messages[0].Content)
+	}
+	if _, err := s.GenerateDigest([]Article{{Title: "Titre"}}, nil, DigestOptions{}); err != nil {
+		t.Fatalf("GenerateDigest error: %v", err)
+	}
+	if !strings.Contains(captured.Messages[0].Content, "in English") {
+		t.Fatalf("expected digest language instruction: %s", captured.Messages[0].Content)
+	}
+
+	t.Setenv("LM_BASE_URL", "http://example.test")
+	if configured := NewSummarizer(Config{SummaryLanguage: " German "}); configured.language != "German" {
+		t.Fatalf("unexpected language: %q", configured.language)
+	}
+}
+
+func TestSummarizerTimeoutAndInputLimit(t *testing.T) {
+	t.Setenv("LM_BASE_URL", "http://example.test")
+	s := NewSummarizer(Config{LMPreset: "ollama", LMTimeoutSeconds: 15, MaxInputChars: 20})
+	if s.client.Timeout != 15*time.Second || s.maxInput != 20 {
+		t.Fatalf("unexpected summarizer settings: timeout=%v max=%d", s.client.Timeout, s.maxInput)
+	}
+	if got := s.limitInput("short"); got != "short" {
+		t.Fatalf("expected short content unchanged, got %q", got)
+	}
+	got := s.limitInput(strings.Repeat("a", 50))
+	if !strings.HasPrefix(got, strings.Repeat("a", 20)+"\n\n[... article truncated: first 20 of 50 characters shown]") {
+		t.Fatalf("unexpected truncated content: %q", got)
+	}
+	unlimited := &Summarizer{}
+	if got := unlimited.limitInput(strings.Repeat("b", defaultMaxInputChars+1)); !strings.Contains(got, "first 10000 of 10001") {
+		t.Fatalf("expected default limit, got suffix %q", got[len(got)-60:])
+	}
+
+	var captured chatRequest
+	s.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		return newResponse(http.StatusOK, `{"choices":[{"message":{"content":"- ok"}}]}`, map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	if _, err := s.Summarize("Title", strings.Repeat("c", 40)); err != nil {
+		t.Fatalf("Summarize error: %v", err)
+	}
+	if !strings.Contains(captured.Messages[1].Content, "article truncated") || strings.Contains(captured.Messages[1].Content, strings.Repeat("c", 21)) {
+		t.Fatalf("expected truncated prompt: %s", captured.Messages[1].Content)
+	}
+}
+
+func TestNewSummarizerProviderChain(t *testing.T) {
+	os.Unsetenv("LM_BASE_URL")
+	cfg := Config{
+		Providers: []string{"local", "missing", "empty", "hosted"},
+		ProviderSettings: map[string]ProviderConfig{
+			"local":  {Preset: "ollama"},
+			"empty":  {Model: "nothing"},
+			"hosted": {BaseURL: "https://api.example.com/v1", Model: "gpt-4o-mini", APIKey: "key", TimeoutSeconds: 30},
+		},
+	}
+	s := NewSummarizer(cfg)
+	if s == nil || s.name != "local" || s.baseURL != "http://localhost:11434/v1" || len(s.fallbacks) != 1 {
+		t.Fatalf("unexpected chain: %+v", s)
+	}
+	hosted := s.fallbacks[0]
+	if hosted.name != "hosted" || hosted.apiKey != "key" || hosted.client.Timeout != 30*time.Second {
+		t.Fatalf("unexpected fallback: %+v", hosted)
+	}
+	if NewSummarizer(Config{Providers: []string{"missing"}}) != nil {
+		t.Fatalf("expected nil summarizer without usable providers")
+	}
+	t.Setenv("LM_BASE_URL", "http://env.test")
+	if s := NewSummarizer(cfg); s.baseURL != "http://env.test" || len(s.fallbacks) != 0 {
+		t.Fatalf("expected env to replace provider chain: %+v", s)
+	}
+}
+
+func TestSummarizerFallsBackToNextProvider(t *testing.T) {
+	primaryCalls := 0
+	primary := &Summarizer{name: "local", baseURL: "http://local.test", model: "small", client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		primaryCalls++
+		return nil, errors.New("connection refused")
+	})}}
+	primary.fallbacks = []*Summarizer{
+		{name: "broken", baseURL: "http://broken.test", model: "mid", client: clientForResponse(http.StatusBadGateway, "", nil)},
+		{name: "hosted", baseURL: "http://hosted.test", model: "big", client: clientForResponse(http.StatusOK, `{"choices":[{"message":{"content":"- ok"}}]}`, map[string]string{"content-type": "application/json"})},
+	}
+	summary, err := primary.Summarize("Title", "Body")
+	if err != nil || summary.Model != "big" || primaryCalls != 1 {
+		t.Fatalf("expected hosted fallback, got %+v (%v)", summary, err)
+	}
+
+	override, err := primary.SummarizeWith("Title", "Body", SummaryOptions{Model: "custom"})
+	if err != nil || override.Model != "big" {
+		t.Fatalf("expected fallback with override, got %+v (%v)", override, err)
+	}
+
+	primary.fallbacks = primary.fallbacks[:1]
+	_, err = primary.Summarize("Title", "Body")
+	if err == nil || !strings.Contains(err.Error(), "all providers failed") || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("expected combined error, got %v", err)
+	}
+}
+
+func TestSummarizerProfiles(t *testing.T) {
+	os.Unsetenv("LM_BASE_URL")
+	hot := 0.9
+	cfg := Config{
+		LMBaseURL:     "http://local.test/v1",
+		LMModel:       "small",
+		LMTemperature: &hot,
+		ProviderSettings: map[string]ProviderConfig{
+			"longform": {BaseURL: "http://remote.test/v1", Model: "big", TimeoutSeconds: 90},
+		},
+	}
+	s := NewSummarizer(cfg)
+	if s == nil || len(s.profiles) != 1 || s.profiles["longform"].model != "big" || *s.temperature != 0.9 {
+		t.Fatalf("unexpected summarizer: %+v", s)
+	}
+	requests := map[string]chatRequest{}
+	record := func(model string, status int) *http.Client {
+		return &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			var payload chatRequest
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			requests[r.URL.Host] = payload
+			return newResponse(status, `{"choices":[{"message":{"content":"- `+model+`"}}]}`, map[string]string{"content-type": "application/json"}, r), nil
+		})}
+	}
+	s.client = record("small", http.StatusOK)
+	s.profiles["longform"].client = record("big", http.StatusOK)
+
+	summary, err := s.SummarizeWith("Title", "Body", SummaryOptions{Profile: "longform"})
+	if err != nil || summary.Model != "big" || requests["remote.test"].Temperature != defaultTemperature {
+		t.Fatalf("expected longform profile, got %+v %v %+v", summary, err, requests)
+	}
+	summary, err = s.SummarizeWith("Title", "Body", SummaryOptions{})
+	if err != nil || summary.Model != "small" || requests["local.test"].Temperature != 0.9 {
+		t.Fatalf("expected default provider, got %+v %v %+v", summary, err, requests)
+	}
+	if profiled := s.forProfile("missing"); profiled != s {
+		t.Fatalf("expected unknown profile to use the default chain")
+	}
+
+	s.profiles["longform"].client = record("big", http.StatusBadGateway)
+	summary, err = s.SummarizeWith("Title", "Body", SummaryOptions{Profile: "longform", Model: "bigger"})
+	if err != nil || summary.Model != "small" {
+		t.Fatalf("expected fallback to default provider, got %+v %v", summary, err)
+	}
+	if requests["remote.test"].Model != "bigger" {
+		t.Fatalf("expected model override on the profile, got %+v", requests["remote.test"])
+	}
+	if NewSummarizer(Config{ProviderSettings: cfg.ProviderSettings}) != nil {
+		t.Fatalf("expected nil summarizer without a default provider")
+	}
+}
@@ -1,9 +1,10 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,6 +17,7 @@ const (
 	SummaryGenerated    SummaryStatus = "generated"
 	SummaryFailed       SummaryStatus = "failed"
 	SummaryNoConfig     SummaryStatus = "no_config"
+	SummaryStale        SummaryStatus = "stale"
 )
 
 type FilterMode string
@@ -30,21 +32,56 @@ type App struct {
 	config         Config
 	store          *Store
 	fetcher        *FeedFetcher
+	speaker        *Speaker
 	summarizer     *Summarizer
 	raindrop       *RaindropClient
+	pocket         *PocketClient
+	pinboard       *PinboardClient
+	omnivore       *OmnivoreClient
+	mastodon       *MastodonClient
+	archive        *ArchiveClient
+	zotero         *ZoteroClient
+	wayback        *WaybackClient
+	notifiers      []*Notifier
+	accounts       []syncAccount
+	imap           *IMAPClient
+	lastNew        []Article
+	lastFetched    map[int]time.Time
+	lastStats      refreshStats
+	scheduled      bool
+	refreshOnly    int
+	bridgeOffer    string
+	feedChoices    []FeedLink
+	collection     RaindropCollection
 	feeds          []Feed
 	articles       []Article
+	moreArticles   bool
+	pageCursor     *Article
 	current        Summary
+	summaryVersion int
 	summaryStatus  SummaryStatus
 	summaryPending map[int]bool
 	refreshPending bool
 	refreshStatus  string
 	selectedIndex  int
 	filter         FilterMode
+	tagFilter      string
+	scope          feedScope
+	tagged         map[int]bool
+	selection      map[int]bool
+	showMuted      bool
+	queue          map[int]int
+	queueView      bool
+	sortMode       SortMode
+	embeddings     map[int][]float64
 	status         string
-	lastDeleted    *Article
+	lastDeleted    []int
+	credentials    []credentialLookup
 	openURL        func(string) error
 	emailSender    func(string) error
+	metrics        *appMetrics
+	clock          Clock
+	location       *time.Location
 }
 
 func NewApp(cfg Config) (*App, error) {
@@ -52,27 +89,99 @@ func NewApp(cfg Config) (*App, error) {
 	if err != nil {
 		return nil, err
 	}
+	cfg, credentials := resolveCredentials(cfg)
 	app := &App{
-		config:         cfg,
 		store:          store,
 		fetcher:        NewFeedFetcher(),
-		summarizer:     NewSummarizerFromEnv(),
-		raindrop:       NewRaindropClient(cfg.RaindropToken),
 		feeds:          store.Feeds(),
-		articles:       store.SortedArticles(),
 		summaryStatus:  SummaryNotGenerated,
 		summaryPending: map[int]bool{},
 		filter:         FilterUnread,
+		sortMode:       SortNewest,
 		openURL:        defaultOpenURL,
 		emailSender:    defaultSendEmail,
+		metrics:        &appMetrics{},
+		clock:          store.clock,
+		lastFetched:    map[int]time.Time{},
 	}
-	app.store.DeleteOldArticles(7)
+	app.applyConfig(cfg, credentials)
+	if cfg.DefaultFilter != "" {
+		app.filter = FilterMode(cfg.DefaultFilter)
+	}
+	app.applyRetention()
 	_ = app.store.MergeDuplicateArticles()
-	app.articles = app.store.SortedArticles()
-	app.status = fmt.Sprintf("%d feeds loaded", len(app.feeds))
+	app.loadArticles()
+	_ = app.ScoreArticles()
+	app.queue = app.store.QueuePositions()
+	app.status = tr(msgFeedsLoaded, len(app.feeds))
 	return app, nil
 }
 
+func (a *App) applyConfig(cfg Config, credentials []credentialLookup) {
+	offsets := map[string]int64{}
+	for _, notifier := range a.notifiers {
+		offsets[notifier.config.Name] = notifier.telegramOffset
+	}
+	a.config = cfg
+	a.credentials = credentials
+	a.location, _ = parseTimezone(cfg.Timezone)
+	a.summarizer = NewSummarizer(cfg)
+	a.summarizer.setUsageRecorder(a.recordUsage)
+	a.raindrop = NewRaindropClient(cfg.RaindropToken)
+	if a.raindrop != nil {
+		a.raindrop.client.Timeout = httpTimeout(cfg, a.raindrop.client.Timeout)
+		configureHTTPClient(a.raindrop.client, cfg)
+	}
+	a.pocket = NewPocketClient(cfg.PocketConsumerKey, cfg.PocketAccessToken)
+	a.pinboard = NewPinboardClient(cfg.PinboardToken)
+	a.omnivore = NewOmnivoreClient(cfg.OmnivoreAPIKey, cfg.OmnivoreURL)
+	a.mastodon = NewMastodonClient(cfg.MastodonURL, cfg.MastodonToken)
+	a.archive = NewArchiveClient(cfg)
+	a.zotero = NewZoteroClient(cfg.ZoteroAPIKey, cfg.ZoteroUserID)
+	a.wayback = NewWaybackClient(cfg.Wayback)
+	a.speaker.Stop()
+	a.speaker = NewSpeaker(cfg)
+	a.notifiers = NewNotifiers(cfg)
+	for _, notifier := range a.notifiers {
+		notifier.telegramOffset = offsets[notifier.config.Name]
+	}
+	a.accounts = NewSyncAccounts(cfg)
+	a.imap = NewIMAPClient(cfg)
+	applyLogLevel(cfg)
+	applyMessages(cfg)
+	a.fetcher.cache = NewDiskCache(cfg)
+	a.fetcher.auth = feedAuthByKey(cfg.FeedAuth)
+	a.store.dedupSimilarity = cfg.DedupSimilarity
+	fetchTimeout := time.Duration(cfg.FetchTimeoutSeconds) * time.Second
+	if cfg.FetchTimeoutSeconds == defaultFetchTimeoutSeconds {
+		fetchTimeout = httpTimeout(cfg, fetchTimeout)
+	}
+	if fetchTimeout > 0 {
+		a.fetcher.client.Timeout = fetchTimeout
+	}
+	configureHTTPClient(a.fetcher.client, cfg)
+	limitClient(a.fetcher.client, newHTTPLimiter(cfg))
+}
+
+func (a *App) ReloadConfig() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		a.status = tr(msgConfigReloadFailed, err)
+		logFor("config").Error("reload failed", "err", err)
+		return err
+	}
+	status := "config reloaded"
+	if cfg.DBPath != a.config.DBPath {
+		cfg.DBPath = a.config.DBPath
+		status += " (db_path change needs a restart)"
+	}
+	cfg, credentials := resolveCredentials(cfg)
+	a.applyConfig(cfg, credentials)
+	a.status = status
+	logFor("config").Info("reloaded", "db_path", cfg.DBPath)
+	return nil
+}
+
 func (a *App) SelectedArticle() *Article {
 	articles := a.FilteredArticles()
 	if len(articles) == 0 || a.selectedIndex < 0 || a.selectedIndex >= len(articles) {
@@ -82,12 +191,42 @@ func (a *App) SelectedArticle() *Article {
 	return &article
 }
 
+func (a *App) findArticle(id int) *Article {
+	for i := range a.articles {
+		if a.articles[i].ID == id {
+			return &a.articles[i]
+		}
+	}
+	return nil
+}
+
 func (a *App) FilteredArticles() []Article {
-	if a.filter == FilterAll {
+	if a.queueView {
+		return a.queuedArticles()
+	}
+	articles := a.filteredArticles()
+	if a.sortMode == SortRanked {
+		return rankArticles(articles)
+	}
+	return articles
+}
+
+func (a *App) filteredArticles() []Article {
+	if a.filter == FilterAll && a.tagFilter == "" && a.scope.empty() && !a.hidesMuted() {
 		return a.articles
 	}
+	scoped := a.scopedFeeds()
 	filtered := make([]Article, 0, len(a.articles))
 	for _, article := range a.articles {
+		if a.tagFilter != "" && !a.tagged[article.ID] {
+			continue
+		}
+		if scoped != nil && !scoped[article.FeedID] {
+			continue
+		}
+		if a.hidesMuted() && titleMuted(article.Title, a.config.MutedWords) {
+			continue
+		}
 		switch a.filter {
 		case FilterUnread:
 			if !article.IsRead {
@@ -97,12 +236,15 @@ func (a *App) FilteredArticles() []Article {
 			if article.IsStarred {
 				filtered = append(filtered, article)
 			}
+		default:
+			filtered = append(filtered, article)
 		}
 	}
 	return filtered
 }
 
 func (a *App) MoveSelection(delta int) {
+	a.ensureArticlesLoaded(a.selectedIndex + delta)
 	articles := a.FilteredArticles()
 	if len(articles) == 0 {
 		a.selectedIndex = 0
@@ -133,47 +275,192 @@ func (a *App) ToggleFilter() {
 }
 
 func (a *App) RefreshFeeds() error {
-	if len(a.feeds) == 0 {
-		a.status = "no feeds to refresh"
+	if len(a.feeds) == 0 && len(a.accounts) == 0 && a.imap == nil {
+		a.status = tr(msgRefreshNoFeeds)
 		return nil
 	}
+	a.runRefreshStartHook()
+	lastID := a.store.MaxArticleID()
+	statuses := []string{}
+	for _, account := range a.accounts {
+		synced, err := a.syncRemote(account)
+		if err != nil {
+			a.status = tr(msgSyncFailed, err)
+			return err
+		}
+		statuses = append(statuses, synced)
+	}
+	a.feeds = a.store.Feeds()
+	if fetched, failed := a.fetchFeeds(); failed > 0 {
+		statuses = append(statuses, tr(msgRefreshDoneFailed, fetched-failed, failed))
+	} else if fetched > 0 || len(a.accounts) == 0 {
+		statuses = append(statuses, tr(msgRefreshDone, fetched))
+	}
+	if a.lastStats.BackedOff > 0 {
+		statuses = append(statuses, tr(msgRefreshBackedOff, a.lastStats.BackedOff))
+	}
+	status := strings.Join(statuses, "; ")
+	if a.imap != nil {
+		added, err := a.ingestNewsletters()
+		if err != nil {
+			status += "; " + tr(msgRefreshNewslettersError, err)
+		} else if added > 0 {
+			status += "; " + tr(msgRefreshNewsletters, added)
+		}
+	}
+	a.applyRetention()
+	a.feeds = a.store.Feeds()
+	a.loadArticles()
+	a.store.CleanupOrphanSummaries()
+	_ = a.store.MergeDuplicateArticles()
+	a.loadArticles()
+	_ = a.ScoreArticles()
+	a.lastNew = a.store.ArticlesAfterID(lastID)
+	a.status = status
+	if _, err := a.EmbedMissingArticles(); err != nil {
+		a.status += "; " + tr(msgRefreshEmbeddingsError, err)
+	}
+	a.syncSummaryForSelection()
+	a.runRefreshHooks()
+	return nil
+}
+
+func (a *App) refreshWorker(ctx context.Context) *App {
+	return &App{
+		config:         a.config,
+		store:          a.store,
+		fetcher:        a.fetcher.withContext(ctx),
+		summarizer:     a.summarizer.withContext(ctx),
+		accounts:       a.accounts,
+		imap:           a.imap,
+		metrics:        a.metrics,
+		clock:          a.clock,
+		location:       a.location,
+		lastFetched:    a.lastFetched,
+		scheduled:      a.scheduled,
+		feeds:          append([]Feed{}, a.feeds...),
+		articles:       append([]Article{}, a.articles...),
+		summaryPending: map[int]bool{},
+		filter:         a.filter,
+		sortMode:       a.sortMode,
+	}
+}
+
+// applyRefresh takes the worker's results but reloads feeds and articles from
+// the store: the worker's copies predate anything read, starred or deleted
+// while it ran.
+func (a *App) applyRefresh(worker *App) {
+	selected := a.SelectedArticle()
+	a.feeds = a.store.Feeds()
+	a.loadArticles()
+	if selected == nil || !a.selectArticleID(selected.ID) {
+		a.selectedIndex = clamp(a.selectedIndex, 0, max(len(a.FilteredArticles())-1, 0))
+	}
+	a.lastNew = worker.lastNew
+	a.lastStats = worker.lastStats
+	a.status = worker.status
+	a.embeddings = nil
+	a.syncSummaryForSelection()
+}
+
+func (a *App) fetchFeeds() (int, int) {
 	type fetchResult struct {
-		feed   Feed
-		parsed DiscoveredFeed
-		err    error
+		feed       Feed
+		parsed     DiscoveredFeed
+		validators feedValidators
+		err        error
+		duration   time.Duration
+	}
+	if a.lastFetched == nil {
+		a.lastFetched = map[int]time.Time{}
 	}
-	results := make(chan fetchResult, len(a.feeds))
-	sem := make(chan struct{}, 5)
+	now := a.now()
+	started := time.Now()
+	a.lastStats = refreshStats{}
+	feeds := []Feed{}
 	for _, feed := range a.feeds {
+		if feed.Source != "" || strings.HasPrefix(feed.URL, "mailto:") {
+			continue
+		}
+		if a.refreshOnly != 0 && feed.ID != a.refreshOnly {
+			continue
+		}
+		if a.refreshOnly == 0 && a.feedBackedOff(feed, now) {
+			a.lastStats.BackedOff++
+			a.recordFeedResult(feed, feedResultBackedOff, 0, nil, 0)
+			continue
+		}
+		if a.scheduled && !a.feedDue(feed, now) {
+			a.lastStats.Skipped++
+			a.recordFeedResult(feed, feedResultSkipped, 0, nil, 0)
+			continue
+		}
+		a.lastFetched[feed.ID] = now
+		feeds = append(feeds, feed)
+	}
+	results := make(chan fetchResult, len(feeds))
+	concurrency := a.config.FetchConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultFetchConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	for _, feed := range feeds {
 		feed := feed
 		go func() {
 			sem <- struct{}{}
-			parsed, err := a.fetcher.FetchFeed(feed.URL)
+			start := time.Now()
+			parsed, validators, err := a.fetcher.FetchFeedIfModified(feed)
 			<-sem
-			results <- fetchResult{feed: feed, parsed: parsed, err: err}
+			results <- fetchResult{feed: feed, parsed: parsed, validators: validators, err: err, duration: time.Since(start)}
 		}()
 	}
+	log := logFor("fetcher")
 	failed := 0
-	for i := 0; i < len(a.feeds); i++ {
+	for i := 0; i < len(feeds); i++ {
 		result := <-results
+		a.recordFeedHealth(result.feed, result.err)
+		if errors.Is(result.err, errFeedNotModified) {
+			a.metrics.feedsRefreshed.Add(1)
+			a.lastStats.NotModified++
+			a.recordFeedResult(result.feed, feedResultNotModified, 0, nil, result.duration)
+			log.Info("not modified", "feed", result.feed.URL, "duration", result.duration)
+			continue
+		}
 		if result.err != nil {
 			failed++
+			a.metrics.fetchErrors.Add(1)
+			log.Warn("fetch failed", "feed", result.feed.URL, "duration", result.duration, "err", result.err)
+			a.recordFeedResult(result.feed, feedResultFailed, 0, result.err, result.duration)
+			continue
+		}
+		added, err := a.store.InsertArticles(result.feed, result.parsed.Articles)
+		if err != nil {
+			log.Error("store articles failed", "feed", result.feed.URL, "err", err)
+			a.recordFeedResult(result.feed, feedResultFailed, 0, err, result.duration)
 			continue
 		}
-		_, _ = a.store.InsertArticles(result.feed, result.parsed.Articles)
+		if result.validators != (feedValidators{ETag: result.feed.ETag, LastModified: result.feed.LastModified}) {
+			if err := a.store.SetFeedValidators(result.feed.ID, result.validators.ETag, result.validators.LastModified); err != nil {
+				log.Warn("store validators failed", "feed", result.feed.URL, "err", err)
+			}
+		}
+		a.metrics.feedsRefreshed.Add(1)
+		a.lastStats.New += len(added)
+		a.recordFeedResult(result.feed, feedResultOK, len(added), nil, result.duration)
+		log.Info("fetched", "feed", result.feed.URL, "duration", result.duration, "articles", len(result.parsed.Articles), "new", len(added))
 	}
-	a.feeds = a.store.Feeds()
-	a.articles = a.store.SortedArticles()
-	a.store.CleanupOrphanSummaries()
-	_ = a.store.MergeDuplicateArticles()
-	a.articles = a.store.SortedArticles()
-	if failed > 0 {
-		a.status = fmt.Sprintf("refreshed %d feeds (%d failed)", len(a.feeds)-failed, failed)
-	} else {
-		a.status = fmt.Sprintf("refreshed %d feeds", len(a.feeds))
+	a.lastStats.Fetched = len(feeds) - failed
+	a.lastStats.Failed = failed
+	a.lastStats.Duration = time.Since(started)
+	return len(feeds), failed
+}
+
+func (a *App) recordFeedResult(feed Feed, status string, added int, err error, duration time.Duration) {
+	result := feedRefreshResult{FeedID: feed.ID, Title: feed.Title, URL: feed.URL, Status: status, New: added, DurationMS: duration.Milliseconds()}
+	if err != nil {
+		result.Error = err.Error()
 	}
-	a.syncSummaryForSelection()
-	return nil
+	a.lastStats.Results = append(a.lastStats.Results, result)
 }
 
 func (a *App) AddFeed(input string) error {
@@ -181,13 +468,35 @@ func (a *App) AddFeed(input string) error {
 	if input == "" {
 		return errors.New("empty feed url")
 	}
+	if _, _, ok := parseFediverseHandle(input); ok {
+		a.bridgeOffer = ""
+		parsed, err := a.fetcher.ResolveFediverse(input)
+		if err != nil {
+			return err
+		}
+		return a.addDiscoveredFeed(parsed)
+	}
 	if !strings.Contains(input, "://") {
 		input = "https://" + input
 	}
-	parsed, err := a.fetcher.DiscoverFeed(input)
+	a.bridgeOffer = ""
+	a.feedChoices = nil
+	parsed, links, err := a.fetcher.discoverPage(input)
+	if err == nil && len(links) > 1 {
+		a.feedChoices = links
+		a.status = tr(msgFeedChoices, len(links))
+		return nil
+	}
+	if err == nil && len(links) == 1 {
+		parsed, err = a.fetcher.FetchFeed(links[0].URL)
+	}
 	if err != nil {
-		return err
+		return a.offerBridgeFeed(input, err)
 	}
+	return a.addDiscoveredFeed(parsed)
+}
+
+func (a *App) addDiscoveredFeed(parsed DiscoveredFeed) error {
 	feed := Feed{
 		Title:       parsed.Title,
 		URL:         parsed.URL,
@@ -200,11 +509,31 @@ func (a *App) AddFeed(input string) error {
 	a.feeds = a.store.Feeds()
 	_, _ = a.store.InsertArticles(a.feeds[len(a.feeds)-1], parsed.Articles)
 	_ = a.store.MergeDuplicateArticles()
-	a.articles = a.store.SortedArticles()
-	a.status = "feed added"
+	a.loadArticles()
+	a.status = tr(msgFeedAdded)
 	return nil
 }
 
+func (a *App) summaryOptions(article Article) SummaryOptions {
+	if len(a.config.FeedOverrides) == 0 {
+		return SummaryOptions{}
+	}
+	for _, feed := range a.feeds {
+		if feed.ID == article.FeedID {
+			return a.config.FeedOverrides[feed.URL]
+		}
+	}
+	return SummaryOptions{}
+}
+
+func (a *App) summaryHash(article Article) string {
+	return summaryContentHash(article, a.summaryOptions(article))
+}
+
+func (a *App) summaryIsCurrent(summary Summary, article Article) bool {
+	return summary.ContentHash == "" || summary.ContentHash == a.summaryHash(article)
+}
+
 func (a *App) GenerateSummary() error {
 	article := a.SelectedArticle()
 	if article == nil {
@@ -214,29 +543,57 @@ func (a *App) GenerateSummary() error {
 		a.summaryStatus = SummaryNoConfig
 		return nil
 	}
-	if existing, ok := a.store.FindSummary(article.ID); ok {
+	if existing, ok := a.store.FindSummary(article.ID); ok && a.summaryIsCurrent(existing, *article) {
 		a.current = existing
 		a.summaryStatus = SummaryGenerated
 		return nil
 	}
+	return a.generateSummary(*article, a.summaryOptions(*article))
+}
+
+func (a *App) RegenerateSummary(model string) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	if a.summarizer == nil {
+		a.summaryStatus = SummaryNoConfig
+		return nil
+	}
+	opts := a.summaryOptions(*article)
+	if model = strings.TrimSpace(model); model != "" {
+		opts.Model = model
+	}
+	a.summaryVersion = 0
+	return a.generateSummary(*article, opts)
+}
+
+func (a *App) generateSummary(article Article, opts SummaryOptions) error {
 	a.summaryStatus = SummaryGenerating
-	summaryText, model, err := a.summarizer.GenerateSummary(article.Title, firstNonEmpty(article.ContentText, article.Content))
+	summary, err := a.summarizer.SummarizeWith(article.Title, firstNonEmpty(article.ContentText, article.Content), opts)
 	if err != nil {
 		a.summaryStatus = SummaryFailed
 		return err
 	}
-	summary := Summary{
-		ArticleID:   article.ID,
-		Content:     summaryText,
-		Model:       model,
-		GeneratedAt: time.Now().UTC(),
-	}
+	summary.ArticleID = article.ID
+	summary.GeneratedAt = a.now().UTC()
+	summary.ContentHash = a.summaryHash(article)
 	stored, err := a.store.UpsertSummary(summary)
 	if err != nil {
 		return err
 	}
+	a.metrics.summariesGenerated.Add(1)
 	a.current = stored
 	a.summaryStatus = SummaryGenerated
+	a.runSummaryHook(article)
+	if a.config.ExtractTopics {
+		if err := a.ExtractTopics(article); err != nil {
+			a.status = tr(msgTopicsFailed, err)
+		}
+	}
+	if err := a.ScoreWithSummarizer(article); err != nil {
+		a.status = tr(msgRelevanceFailed, err)
+	}
 	return nil
 }
 
@@ -266,6 +623,10 @@ func (a *App) ToggleStar() error {
 		return err
 	}
 	a.updateArticleInList(*article)
+	if article.IsStarred {
+		a.archiveToWayback(waybackEventStar, *article)
+		a.appendToVaultOn(waybackEventStar, *article)
+	}
 	return nil
 }
 
@@ -274,33 +635,57 @@ func (a *App) DeleteSelected() error {
 	if article == nil {
 		return nil
 	}
-	deleted, err := a.store.DeleteArticle(article.ID)
+	_, trashID, err := a.store.TrashArticle(article.ID)
 	if err != nil {
 		return err
 	}
 	delete(a.summaryPending, article.ID)
-	a.lastDeleted = &deleted
-	a.articles = a.store.SortedArticles()
+	a.lastDeleted = []int{trashID}
+	if _, queued := a.queue[article.ID]; queued {
+		_ = a.store.CompactQueue()
+		a.queue = a.store.QueuePositions()
+	}
+	a.loadArticles()
 	if a.selectedIndex >= len(a.FilteredArticles()) {
 		a.selectedIndex = len(a.FilteredArticles()) - 1
 		if a.selectedIndex < 0 {
 			a.selectedIndex = 0
 		}
 	}
-	a.status = "article deleted"
+	a.status = tr(msgArticleDeleted)
 	a.syncSummaryForSelection()
 	return nil
 }
 
+// Undelete restores what the last delete in this session removed, or the
+// newest trash entry when nothing was recorded.
 func (a *App) Undelete() error {
-	article, err := a.store.UndeleteLast()
-	if err != nil {
-		a.status = "nothing to undelete"
+	trashIDs := a.lastDeleted
+	a.lastDeleted = nil
+	restored := 0
+	if len(trashIDs) == 0 {
+		if article, err := a.store.UndeleteLast(); err == nil {
+			delete(a.summaryPending, article.ID)
+			restored++
+		}
+	}
+	for _, id := range trashIDs {
+		article, err := a.store.RestoreDeleted(id)
+		if err != nil {
+			continue
+		}
+		delete(a.summaryPending, article.ID)
+		restored++
+	}
+	if restored == 0 {
+		a.status = tr(msgUndeleteNothing)
 		return nil
 	}
-	delete(a.summaryPending, article.ID)
-	a.articles = a.store.SortedArticles()
-	a.status = "article restored"
+	a.loadArticles()
+	a.status = tr(msgArticleRestored)
+	if restored > 1 {
+		a.status = tr(msgArticlesRestored, restored)
+	}
 	a.syncSummaryForSelection()
 	return nil
 }
@@ -308,16 +693,16 @@ func (a *App) Undelete() error {
 func (a *App) UndeleteByPublishedDays(days int) error {
 	restored, err := a.store.UndeleteByPublishedDays(days)
 	if err != nil {
-		a.status = "undelete failed: " + err.Error()
+		a.status = tr(msgUndeleteFailed, err)
 		return nil
 	}
 	if restored == 0 {
-		a.status = "no deleted articles to restore"
+		a.status = tr(msgUndeleteNoneRecent)
 		return nil
 	}
 	a.lastDeleted = nil
-	a.articles = a.store.SortedArticles()
-	a.status = fmt.Sprintf("restored %d deleted articles from last %d days", restored, days)
+	a.loadArticles()
+	a.status = tr(msgUndeleteRestored, restored, days)
 	a.syncSummaryForSelection()
 	return nil
 }
@@ -327,25 +712,25 @@ func (a *App) OpenSelected() error {
 	if article == nil {
 		return nil
 	}
-	return a.openURL(article.URL)
+	return a.openLink(article.URL)
 }
 
 func (a *App) OpenStarred() error {
 	count := 0
-	for _, article := range a.articles {
+	for _, article := range a.allArticles() {
 		if !article.IsStarred {
 			continue
 		}
-		if err := a.openURL(article.URL); err != nil {
+		if err := a.openLink(article.URL); err != nil {
 			return err
 		}
 		count++
 	}
 	if count == 0 {
-		a.status = "no starred articles to open"
+		a.status = tr(msgStarredNoneToOpen)
 		return nil
 	}
-	a.status = fmt.Sprintf("opened %d starred articles", count)
+	a.status = tr(msgStarredOpened, count)
 	return nil
 }
 
@@ -354,8 +739,7 @@ func (a *App) EmailSelected() error {
 	if article == nil {
 		return nil
 	}
-	mailURL := buildMailto(article, a.current)
-	return a.emailSender(mailURL)
+	return a.deliverEmail(articleEmail(article, a.current, a.config))
 }
 
 func (a *App) SaveToRaindrop(tags []string) error {
@@ -376,11 +760,245 @@ func (a *App) SaveToRaindrop(tags []string) error {
 		Tags:  tags,
 		Note:  summary,
 	}
+	if a.collection.ID != 0 {
+		payload.Collection = &raindropCollectionRef{ID: a.collection.ID}
+	}
 	raindropID, err := a.raindrop.Save(payload)
 	if err != nil {
 		return err
 	}
-	return a.store.SaveToRaindrop(article.ID, raindropID, tags)
+	if err := a.store.SaveBookmark(article.ID, "raindrop", raindropID, tags); err != nil {
+		return err
+	}
+	if a.collection.ID != 0 {
+		a.status = tr(msgSavedRaindrop, a.collection.Title)
+	}
+	return nil
+}
+
+var saveTargetOrder = []string{"raindrop", "pocket", "pinboard", "omnivore", "archive", "mastodon"}
+
+var saveTargetNames = map[string]string{
+	"raindrop": "Raindrop",
+	"pocket":   "Pocket",
+	"pinboard": "Pinboard",
+	"omnivore": "Omnivore",
+	"archive":  "Archive",
+	"mastodon": "Mastodon",
+}
+
+func (a *App) SaveBookmark(tags []string) error {
+	return a.SaveBookmarkTo(a.config.SaveTarget, tags)
+}
+
+func (a *App) SaveBookmarkTo(target string, tags []string) error {
+	if name, ok := pluginFromTarget(target); ok {
+		return a.RunPlugin(name, tags)
+	}
+	var err error
+	switch target {
+	case "pocket":
+		err = a.SaveToPocket(tags)
+	case "pinboard":
+		err = a.SaveToPinboard(tags)
+	case "omnivore":
+		err = a.SaveToOmnivore(tags)
+	case "archive":
+		err = a.SaveToArchive(tags)
+	case "mastodon":
+		err = a.ShareToMastodon(tags)
+	case "", "raindrop":
+		err = a.SaveToRaindrop(tags)
+	default:
+		return fmt.Errorf("unknown save target: %q", target)
+	}
+	if article := a.SelectedArticle(); err == nil && article != nil {
+		a.archiveToWayback(waybackEventBookmark, *article)
+		a.appendToVaultOn(waybackEventBookmark, *article)
+	}
+	return err
+}
+
+func (a *App) ShareTargets() []string {
+	configured := map[string]bool{
+		"raindrop": a.raindrop != nil,
+		"pocket":   a.pocket != nil && a.pocket.accessToken != "",
+		"pinboard": a.pinboard != nil,
+		"omnivore": a.omnivore != nil,
+		"archive":  a.archive != nil,
+		"mastodon": a.mastodon != nil,
+	}
+	targets := []string{}
+	for _, target := range saveTargetOrder {
+		if configured[target] {
+			targets = append(targets, target)
+		}
+	}
+	for _, name := range pluginNames(a.config) {
+		targets = append(targets, pluginTargetPrefix+name)
+	}
+	return targets
+}
+
+func (a *App) SaveToPocket(tags []string) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	if a.pocket == nil || a.pocket.accessToken == "" {
+		return errors.New("pocket not configured (run greeder --pocket-login)")
+	}
+	pocketID, err := a.pocket.Save(RaindropItem{Link: article.URL, Title: article.Title, Tags: tags})
+	if err != nil {
+		return err
+	}
+	if err := a.store.SaveBookmark(article.ID, "pocket", pocketID, tags); err != nil {
+		return err
+	}
+	a.status = tr(msgSavedPocket)
+	return nil
+}
+
+func (a *App) SaveToPinboard(tags []string) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	if a.pinboard == nil {
+		return errors.New("pinboard not configured")
+	}
+	summary := ""
+	if a.current.ArticleID == article.ID {
+		summary = a.current.Content
+	}
+	pinboardID, err := a.pinboard.Save(RaindropItem{Link: article.URL, Title: article.Title, Tags: tags, Note: summary})
+	if err != nil {
+		return err
+	}
+	if err := a.store.SaveBookmark(article.ID, "pinboard", pinboardID, tags); err != nil {
+		return err
+	}
+	a.status = tr(msgSavedPinboard)
+	return nil
+}
+
+func (a *App) SaveToOmnivore(tags []string) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	if a.omnivore == nil {
+		return errors.New("omnivore not configured")
+	}
+	omnivoreID, err := a.omnivore.Save(RaindropItem{Link: article.URL, Title: article.Title, Tags: tags})
+	if err != nil {
+		return err
+	}
+	if err := a.store.SaveBookmark(article.ID, "omnivore", omnivoreID, tags); err != nil {
+		return err
+	}
+	a.status = tr(msgSavedOmnivore)
+	return nil
+}
+
+func (a *App) resolveShareTarget(value string) (string, bool) {
+	targets := a.ShareTargets()
+	if index, err := strconv.Atoi(value); err == nil {
+		if index < 1 || index > len(targets) {
+			return "", false
+		}
+		return targets[index-1], true
+	}
+	for _, target := range targets {
+		if strings.EqualFold(value, target) || strings.EqualFold(value, a.targetName(target)) {
+			return target, true
+		}
+	}
+	return "", false
+}
+
+func (a *App) SaveToArchive(tags []string) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	if a.archive == nil {
+		return errors.New("archive not configured")
+	}
+	summary := ""
+	if a.current.ArticleID == article.ID {
+		summary = a.current.Content
+	}
+	archiveID, err := a.archive.Save(RaindropItem{Link: article.URL, Title: article.Title, Tags: tags, Note: summary})
+	if err != nil {
+		return err
+	}
+	if err := a.store.SaveBookmark(article.ID, strings.ToLower(a.archive.Name()), archiveID, tags); err != nil {
+		return err
+	}
+	a.status = tr(msgSavedArchive, a.archive.Name())
+	return nil
+}
+
+func (a *App) saveTargetName() string {
+	return a.targetName(a.config.SaveTarget)
+}
+
+func (a *App) targetName(target string) string {
+	if target == "archive" && a.archive != nil {
+		return a.archive.Name()
+	}
+	if name, ok := pluginFromTarget(target); ok {
+		return name
+	}
+	if name, ok := saveTargetNames[target]; ok {
+		return name
+	}
+	return "Raindrop"
+}
+
+func (a *App) SetRaindropCollection(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "default") {
+		return a.chooseRaindropCollection(value, nil)
+	}
+	if a.raindrop == nil {
+		return errors.New("raindrop not configured")
+	}
+	collections, err := a.raindrop.ListCollections()
+	if err != nil {
+		return err
+	}
+	return a.chooseRaindropCollection(value, collections)
+}
+
+func (a *App) chooseRaindropCollection(value string, collections []RaindropCollection) error {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "default") {
+		a.collection = RaindropCollection{}
+		a.status = tr(msgCollectionDefault)
+		return nil
+	}
+	id, _ := strconv.Atoi(value)
+	for _, collection := range collections {
+		if (id != 0 && collection.ID == id) || strings.EqualFold(collection.Title, value) {
+			a.collection = collection
+			a.status = tr(msgCollectionSelected, collection.Title)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown raindrop collection: %q", value)
+}
+
+func formatRaindropCollections(collections []RaindropCollection) string {
+	if len(collections) == 0 {
+		return "No Raindrop collections."
+	}
+	lines := make([]string, 0, len(collections))
+	for _, collection := range collections {
+		lines = append(lines, fmt.Sprintf("%d\t%s (%d)", collection.ID, collection.Title, collection.Count))
+	}
+	return strings.Join(lines, "\n")
 }
 
 func (a *App) CopySelectedURL() error {
@@ -391,44 +1009,60 @@ func (a *App) CopySelectedURL() error {
 	if err := copyToClipboard(article.URL); err != nil {
 		return err
 	}
-	a.status = "URL copied to clipboard"
+	a.status = tr(msgURLCopied)
 	return nil
 }
 
 func (a *App) GenerateMissingSummaries() error {
 	if a.summarizer == nil {
-		a.status = "Summarizer not configured"
+		a.status = tr(msgSummarizerMissing)
 		return errors.New("summarizer not configured")
 	}
-	existing := map[int]bool{}
+	existing := map[int]Summary{}
 	for _, summary := range a.store.Summaries() {
-		existing[summary.ArticleID] = true
+		existing[summary.ArticleID] = summary
 	}
-	for _, article := range a.articles {
-		if existing[article.ID] {
+	total, failed := 0, 0
+	var failures []error
+	for _, article := range a.allArticles() {
+		if summary, ok := existing[article.ID]; ok && a.summaryIsCurrent(summary, article) {
 			continue
 		}
-		summaryText, model, err := a.summarizer.GenerateSummary(article.Title, firstNonEmpty(article.ContentText, article.Content))
+		total++
+		summary, err := a.summarizer.SummarizeWith(article.Title, firstNonEmpty(article.ContentText, article.Content), a.summaryOptions(article))
 		if err != nil {
-			a.status = "Batch summary failed: " + err.Error()
-			return err
-		}
-		summary := Summary{
-			ArticleID:   article.ID,
-			Content:     summaryText,
-			Model:       model,
-			GeneratedAt: time.Now().UTC(),
+			logFor("summarizer").Warn("batch summary failed", "article", article.ID, "err", err)
+			failed++
+			failures = append(failures, fmt.Errorf("%s: %w", article.Title, err))
+			continue
 		}
+		summary.ArticleID = article.ID
+		summary.GeneratedAt = a.now().UTC()
+		summary.ContentHash = a.summaryHash(article)
 		if _, err := a.store.UpsertSummary(summary); err != nil {
 			return err
 		}
+		a.metrics.summariesGenerated.Add(1)
+		a.runSummaryHook(article)
+		if a.config.ExtractTopics {
+			_ = a.ExtractTopics(article)
+		}
+		_ = a.ScoreWithSummarizer(article)
 	}
-	a.status = "Batch summaries complete"
 	a.syncSummaryForSelection()
+	if failed > 0 {
+		a.status = tr(msgBatchDoneFailed, total-failed, total, failed)
+		return fmt.Errorf("%d of %d summaries failed: %w", failed, total, errors.Join(failures...))
+	}
+	a.status = tr(msgBatchComplete)
+	if total > 0 {
+		a.status = tr(msgBatchDone, total, total)
+	}
 	return nil
 }
 
 func (a *App) syncSummaryForSelection() {
+	a.summaryVersion = 0
 	article := a.SelectedArticle()
 	if article == nil {
 		a.current = Summary{}
@@ -443,12 +1077,43 @@ func (a *App) syncSummaryForSelection() {
 	if summary, ok := a.store.FindSummary(article.ID); ok {
 		a.current = summary
 		a.summaryStatus = SummaryGenerated
+		if !a.summaryIsCurrent(summary, *article) {
+			a.summaryStatus = SummaryStale
+		}
 		return
 	}
 	a.current = Summary{}
 	a.summaryStatus = SummaryNotGenerated
 }
 
+func (a *App) CycleSummaryVersion(delta int) {
+	article := a.SelectedArticle()
+	if article == nil {
+		return
+	}
+	versions := a.store.SummaryVersions(article.ID)
+	if len(versions) < 2 {
+		a.status = tr(msgSummaryNoVersions)
+		return
+	}
+	a.summaryVersion = ((a.summaryVersion+delta)%len(versions) + len(versions)) % len(versions)
+	version := versions[a.summaryVersion]
+	a.current = version
+	a.summaryStatus = SummaryGenerated
+	if a.summaryVersion == 0 && !a.summaryIsCurrent(version, *article) {
+		a.summaryStatus = SummaryStale
+	}
+	a.status = formatSummaryVersion(a.summaryVersion, len(versions), version)
+}
+
+func formatSummaryVersion(index, total int, summary Summary) string {
+	label := fmt.Sprintf("Version %d/%d", total-index, total)
+	if index == 0 {
+		label += " (latest)"
+	}
+	return fmt.Sprintf("%s: %s, %s", label, valueOrFallback(summary.Model, "unknown model"), formatLocalTime(summary.GeneratedAt))
+}
+
 func (a *App) updateArticleInList(article Article) {
 	for i := range a.articles {
 		if a.articles[i].ID == article.ID {
@@ -458,20 +1123,6 @@ func (a *App) updateArticleInList(article Article) {
 	}
 }
 
-func (a *App) ImportOPML(path string) error {
-	feeds, err := ParseOPML(path)
-	if err != nil {
-		return err
-	}
-	for _, feed := range feeds {
-		if _, err := a.store.InsertFeed(feed); err != nil {
-			continue
-		}
-	}
-	a.feeds = a.store.Feeds()
-	return a.RefreshFeeds()
-}
-
 func (a *App) ExportOPML(path string) error {
 	return ExportOPML(path, a.feeds)
 }
@@ -480,7 +1131,7 @@ func (a *App) ExportState(path string) error {
 	if err := a.store.ExportState(path); err != nil {
 		return err
 	}
-	a.status = "State exported"
+	a.status = tr(msgStateExported)
 	return nil
 }
 
@@ -488,24 +1139,55 @@ func (a *App) ImportState(path string) error {
 	if err := a.store.ImportState(path); err != nil {
 		return err
 	}
+	a.lastDeleted = nil
 	a.feeds = a.store.Feeds()
-	a.articles = a.store.SortedArticles()
+	a.loadArticles()
 	a.selectedIndex = 0
-	a.status = "State imported"
+	a.status = tr(msgStateImported)
 	a.syncSummaryForSelection()
 	return nil
 }
 
-func buildMailto(article *Article, summary Summary) string {
-	params := url.Values{}
-	params.Set("subject", article.Title)
-	body := []string{"Title: " + article.Title, "", "URL: " + article.URL}
-	if summary.ArticleID == article.ID && summary.Content != "" {
-		body = append(body, "", "AI Summary:", summary.Content)
+func (a *App) SummaryUsage(weeks int) []SummaryUsage {
+	if weeks <= 0 {
+		weeks = 1
+	}
+	since := weekStart(a.now()).AddDate(0, 0, -7*(weeks-1))
+	return a.store.SummaryUsageByWeek(since)
+}
+
+func (a *App) recordUsage(record UsageRecord) {
+	if err := a.store.RecordUsage(record); err != nil {
+		logFor("summarizer").Warn("record usage failed", "err", err)
 	}
-	if article.ContentText != "" {
-		body = append(body, "", "Article Content:", article.ContentText)
+}
+
+func formatUsageStats(usage []SummaryUsage, cfg Config) []string {
+	lines := []string{}
+	total := SummaryUsage{}
+	for _, week := range usage {
+		lines = append(lines, fmt.Sprintf("Week of %s: %d requests, %d prompt + %d completion tokens%s",
+			week.WeekStart.Format("2006-01-02"), week.Requests, week.PromptTokens, week.CompletionTokens, formatUsageCost(week, cfg)))
+		total.Requests += week.Requests
+		total.PromptTokens += week.PromptTokens
+		total.CompletionTokens += week.CompletionTokens
+	}
+	if len(lines) == 0 {
+		return []string{"No summarizer requests in this period"}
 	}
-	params.Set("body", strings.Join(body, "\n"))
-	return "mailto:?" + params.Encode()
+	lines = append(lines, fmt.Sprintf("Total: %d requests, %d prompt + %d completion tokens%s",
+		total.Requests, total.PromptTokens, total.CompletionTokens, formatUsageCost(total, cfg)))
+	return lines
+}
+
+func formatUsageCost(usage SummaryUsage, cfg Config) string {
+	if cfg.PromptCostPerMillion == 0 && cfg.CompletionCostPerMillion == 0 {
+		return ""
+	}
+	cost := float64(usage.PromptTokens)*cfg.PromptCostPerMillion/1e6 + float64(usage.CompletionTokens)*cfg.CompletionCostPerMillion/1e6
+	return fmt.Sprintf(" ($%.4f)", cost)
+}
+
+func buildMailto(article *Article, summary Summary) string {
+	return articleEmail(article, summary, Config{}).mailto()
 }
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"bytes"
 	"errors"
+	"io"
 	"net/http"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestAppFiltersAndRefreshErrors(t *testing.T) {
@@ -638,8 +642,8 @@ func TestAppGenerateMissingSummariesFailure(t *testing.T) {
 	if err := app.GenerateMissingSummaries(); err == nil {
 		t.Fatalf("expected batch summary error")
 	}
-	if !strings.Contains(app.status, "Batch summary failed") {
-		t.Fatalf("expected batch summary status")
+	if app.status != "Batch summaries complete: 0/1 (1 failed)" {
+		t.Fatalf("expected batch summary tally, got %q", app.status)
 	}
 }
 
@@ -694,6 +698,46 @@ func TestNewAppStoreError(t *testing.T) {
 	}
 }
 
+func TestNewAppConfigGroups(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DBPath = filepath.Join(t.TempDir(), "store.db")
+	store, err := NewStore(cfg.DBPath)
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	if _, err := store.InsertArticles(feed, []Article{{GUID: "1", Title: "Old", URL: "u1", FetchedAt: old}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	_ = store.db.Close()
+
+	cfg.RetentionDays = 30
+	cfg.FetchTimeoutSeconds = 5
+	cfg.DefaultFilter = "starred"
+	app, err := NewApp(cfg)
+	if err != nil {
+		t.Fatalf("NewApp error: %v", err)
+	}
+	if len(app.articles) != 1 || app.filter != FilterStarred || app.fetcher.client.Timeout != 5*time.Second {
+		t.Fatalf("unexpected app from config: %d articles, filter %q, timeout %v", len(app.articles), app.filter, app.fetcher.client.Timeout)
+	}
+	_ = app.store.db.Close()
+
+	cfg.RetentionDays = 0
+	if app, err = NewApp(cfg); err != nil || len(app.articles) != 1 {
+		t.Fatalf("expected retention 0 to keep articles")
+	}
+	_ = app.store.db.Close()
+	cfg.RetentionDays = 7
+	if app, err = NewApp(cfg); err != nil || len(app.articles) != 0 {
+		t.Fatalf("expected old article removed with default retention")
+	}
+}
+
 func TestAppGenerateSummaryStoreError(t *testing.T) {
 	root := t.TempDir()
 	cfg := DefaultConfig()
@@ -775,3 +819,93 @@ func TestAppSaveToRaindropError(t *testing.T) {
 		t.Fatalf("expected save error")
 	}
 }
+
+func TestAppRaindropCollection(t *testing.T) {
+	app := newTUIApp(t)
+	if err := app.SetRaindropCollection("Reading"); err == nil {
+		t.Fatalf("expected unconfigured raindrop error")
+	}
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "g1", Title: "T", URL: "https://example.com"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	payloads := []string{}
+	collections := raindropCollectionsClient()
+	app.raindrop = &RaindropClient{baseURL: "http://example.test", token: "token", client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			payloads = append(payloads, string(body))
+			return newResponse(http.StatusOK, `{"item":{"_id":9}}`, nil, r), nil
+		}
+		return collections.Transport.RoundTrip(r)
+	})}}
+
+	if err := app.SetRaindropCollection("missing"); err == nil || !strings.Contains(err.Error(), "unknown raindrop collection") {
+		t.Fatalf("expected unknown collection error, got %v", err)
+	}
+	if err := app.SetRaindropCollection("reading"); err != nil || app.collection.ID != 10 {
+		t.Fatalf("expected collection by title: %v %+v", err, app.collection)
+	}
+	if err := app.SaveToRaindrop([]string{"t"}); err != nil {
+		t.Fatalf("SaveToRaindrop error: %v", err)
+	}
+	if !strings.Contains(payloads[0], `"collection":{"$id":10}`) || app.status != "Saved to Raindrop collection Reading" {
+		t.Fatalf("expected collection in payload: %s (%q)", payloads[0], app.status)
+	}
+	if err := app.SetRaindropCollection("11"); err != nil || app.collection.Title != "Go" {
+		t.Fatalf("expected collection by id: %v %+v", err, app.collection)
+	}
+	if err := app.SetRaindropCollection("default"); err != nil || app.collection.ID != 0 {
+		t.Fatalf("expected default collection")
+	}
+	if err := app.SaveToRaindrop(nil); err != nil || strings.Contains(payloads[1], "collection") {
+		t.Fatalf("expected default save without collection: %s", payloads[1])
+	}
+
+	var out bytes.Buffer
+	if err := handleCommand(app, "collections", &out); err != nil || out.String() != "10\tReading (3)\n11\tGo (1)\n" {
+		t.Fatalf("unexpected collections output %q %v", out.String(), err)
+	}
+	if err := handleCommand(app, "collection Go", &out); err != nil || app.collection.ID != 11 {
+		t.Fatalf("expected collection command to select")
+	}
+	if formatRaindropCollections(nil) != "No Raindrop collections." {
+		t.Fatalf("unexpected empty collections output")
+	}
+
+	model := newTUIModel(app)
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	model = updated.(tuiModel)
+	if cmd == nil || model.inputMode != inputNone || app.status != tr(msgCollectionsLoading) {
+		t.Fatalf("expected collections to load in the background, got %v %q", model.inputMode, app.status)
+	}
+	updated, _ = model.Update(cmd())
+	model = updated.(tuiModel)
+	if model.inputMode != inputRaindropCollection || app.status != "Collections: Reading, Go" {
+		t.Fatalf("expected collection input, got %v %q", model.inputMode, app.status)
+	}
+	client := app.raindrop.client
+	app.raindrop.client = &http.Client{Transport: &errorRoundTripper{}}
+	model.input.SetValue("Reading")
+	model = model.commitInput()
+	if app.collection.ID != 10 {
+		t.Fatalf("expected TUI collection selection from the fetched list, got %q", app.status)
+	}
+	app.raindrop.client = client
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	model = updated.(tuiModel)
+	if !strings.Contains(model.input.Placeholder, "-> Reading") {
+		t.Fatalf("expected bookmark prompt to show collection, got %q", model.input.Placeholder)
+	}
+	model.inputMode = inputNone
+	app.raindrop = nil
+	updated, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	updated, _ = updated.(tuiModel).Update(cmd())
+	if updated.(tuiModel).inputMode != inputNone || !strings.HasPrefix(app.status, "Collections unavailable") {
+		t.Fatalf("expected unavailable collections status")
+	}
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAppBasics(t *testing.T) {
@@ -160,3 +163,215 @@ func TestBuildMailto(t *testing.T) {
 		t.Fatalf("expected mailto")
 	}
 }
+
+func TestFormatUsageStats(t *testing.T) {
+	if got := formatUsageStats(nil, Config{}); len(got) != 1 || !strings.Contains(got[0], "No summarizer requests") {
+		t.Fatalf("unexpected empty stats: %v", got)
+	}
+	usage := []SummaryUsage{
+		{WeekStart: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), Requests: 2, PromptTokens: 1000000, CompletionTokens: 0},
+		{WeekStart: time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), Requests: 1, PromptTokens: 0, CompletionTokens: 500000},
+	}
+	lines := formatUsageStats(usage, Config{PromptCostPerMillion: 1, CompletionCostPerMillion: 2})
+	if len(lines) != 3 {
+		t.Fatalf("unexpected lines: %v", lines)
+	}
+	if !strings.Contains(lines[0], "Week of 2026-10-05") || !strings.Contains(lines[0], "($1.0000)") {
+		t.Fatalf("unexpected week line: %s", lines[0])
+	}
+	if !strings.Contains(lines[2], "Total: 3 requests") || !strings.Contains(lines[2], "($2.0000)") {
+		t.Fatalf("unexpected total line: %s", lines[2])
+	}
+	if strings.Contains(formatUsageStats(usage, Config{})[0], "$") {
+		t.Fatalf("expected no cost without pricing")
+	}
+}
+
+func TestAppSummaryStaleAfterContentChange(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1", ContentText: "first"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "- old", ContentHash: articleContentHash(articles[0])}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.syncSummaryForSelection()
+	if app.summaryStatus != SummaryGenerated {
+		t.Fatalf("expected current summary, got %q", app.summaryStatus)
+	}
+
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1", ContentText: "second"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.syncSummaryForSelection()
+	if app.summaryStatus != SummaryStale || app.current.Content != "- old" {
+		t.Fatalf("expected stale summary, got %q", app.summaryStatus)
+	}
+
+	app.summarizer = &Summarizer{baseURL: "http://example.test", model: "m", client: clientForResponse(http.StatusOK, `{"choices":[{"message":{"content":"- new"}}]}`, map[string]string{"content-type": "application/json"})}
+	if err := app.GenerateSummary(); err != nil {
+		t.Fatalf("GenerateSummary error: %v", err)
+	}
+	stored, ok := app.store.FindSummary(articles[0].ID)
+	if !ok || stored.Content != "- new" || !app.summaryIsCurrent(stored, app.articles[0]) {
+		t.Fatalf("expected regenerated summary, got %+v", stored)
+	}
+	if !app.summaryIsCurrent(Summary{}, app.articles[0]) {
+		t.Fatalf("expected legacy summaries without hash to be current")
+	}
+
+	app.feeds = app.store.Feeds()
+	app.config.FeedOverrides = map[string]SummaryOptions{feed.URL: {Style: "tldr"}}
+	app.syncSummaryForSelection()
+	if app.summaryStatus != SummaryStale {
+		t.Fatalf("expected a feed override change to mark the summary stale, got %q", app.summaryStatus)
+	}
+	if err := app.RegenerateSummary("other"); err != nil {
+		t.Fatalf("RegenerateSummary error: %v", err)
+	}
+	app.syncSummaryForSelection()
+	if app.summaryStatus != SummaryGenerated {
+		t.Fatalf("expected a one-off model regeneration to stay current, got %q", app.summaryStatus)
+	}
+}
+
+func TestAppSummaryOptionsPerFeed(t *testing.T) {
+	app := newTUIApp(t)
+	essays, err := app.store.InsertFeed(Feed{Title: "Essays", URL: "https://essays.example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	links, err := app.store.InsertFeed(Feed{Title: "Links", URL: "https://links.example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	if opts := app.summaryOptions(Article{FeedID: essays.ID}); opts != (SummaryOptions{}) {
+		t.Fatalf("expected no overrides, got %+v", opts)
+	}
+	app.config.FeedOverrides = map[string]SummaryOptions{essays.URL: {Model: "big", Style: "paragraph"}}
+	if opts := app.summaryOptions(Article{FeedID: essays.ID}); opts.Model != "big" || opts.Style != "paragraph" {
+		t.Fatalf("unexpected essay overrides: %+v", opts)
+	}
+	if opts := app.summaryOptions(Article{FeedID: links.ID}); opts != (SummaryOptions{}) {
+		t.Fatalf("expected no link overrides, got %+v", opts)
+	}
+	if opts := app.summaryOptions(Article{FeedID: 999}); opts != (SummaryOptions{}) {
+		t.Fatalf("expected no overrides for unknown feed, got %+v", opts)
+	}
+}
+
+func TestAppCycleSummaryVersion(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1", ContentText: "body"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.CycleSummaryVersion(1)
+	if app.status != "No earlier summary versions" {
+		t.Fatalf("unexpected status: %q", app.status)
+	}
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, model := range []string{"old-model", "new-model"} {
+		if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "- " + model, Model: model, GeneratedAt: base.Add(time.Duration(i) * time.Hour), ContentHash: articleContentHash(articles[0])}); err != nil {
+			t.Fatalf("UpsertSummary error: %v", err)
+		}
+	}
+	app.syncSummaryForSelection()
+	if app.current.Content != "- new-model" {
+		t.Fatalf("expected latest summary, got %q", app.current.Content)
+	}
+	app.CycleSummaryVersion(1)
+	if app.current.Content != "- old-model" || app.summaryStatus != SummaryGenerated || !strings.HasPrefix(app.status, "Version 1/2: old-model") {
+		t.Fatalf("expected older version, got %q (%q)", app.current.Content, app.status)
+	}
+	app.CycleSummaryVersion(1)
+	if app.current.Content != "- new-model" || !strings.HasPrefix(app.status, "Version 2/2 (latest): new-model") {
+		t.Fatalf("expected wrap to latest, got %q (%q)", app.current.Content, app.status)
+	}
+	app.CycleSummaryVersion(-1)
+	if app.current.Content != "- old-model" {
+		t.Fatalf("expected wrap to oldest, got %q", app.current.Content)
+	}
+	app.syncSummaryForSelection()
+	if app.summaryVersion != 0 || app.current.Content != "- new-model" {
+		t.Fatalf("expected selection sync to reset version")
+	}
+}
+
+func TestAppGenerateSummaryUsesFeedProfile(t *testing.T) {
+	app, _, news := newFolderApp(t)
+	respond := func(model string) *http.Client {
+		return clientForResponse(http.StatusOK, `{"choices":[{"message":{"content":"- `+model+`"}}]}`, map[string]string{"content-type": "application/json"})
+	}
+	app.summarizer = &Summarizer{name: "default", baseURL: "http://local.test", model: "small", client: respond("small")}
+	app.summarizer.profiles = map[string]*Summarizer{"longform": {name: "longform", baseURL: "http://remote.test", model: "big", client: respond("big")}}
+	app.config.FeedOverrides = map[string]SummaryOptions{news.URL: {Profile: "longform"}}
+	for i, article := range app.FilteredArticles() {
+		app.selectedIndex = i
+		if err := app.GenerateSummary(); err != nil {
+			t.Fatalf("GenerateSummary error: %v", err)
+		}
+		want := "small"
+		if article.FeedID == news.ID {
+			want = "big"
+		}
+		if app.current.Model != want {
+			t.Fatalf("expected %s for %s, got %+v", want, article.Title, app.current)
+		}
+	}
+}
+
+func modelEchoSummarizer() *Summarizer {
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		var payload struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		return newResponse(http.StatusOK, `{"choices":[{"message":{"content":"- by `+payload.Model+`"}}]}`, map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	return &Summarizer{baseURL: "http://example.test", model: "default-model", client: client}
+}
+
+func TestAppRegenerateSummaryKeepsVersions(t *testing.T) {
+	app := newTUIApp(t)
+	app.filter = FilterAll
+	feed, _ := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1", ContentText: "body"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.loadArticles()
+	app.summarizer = modelEchoSummarizer()
+	if err := app.GenerateSummary(); err != nil || app.current.Content != "- by default-model" {
+		t.Fatalf("GenerateSummary: %q %v", app.current.Content, err)
+	}
+	if err := app.GenerateSummary(); err != nil || len(app.store.SummaryVersions(app.current.ArticleID)) != 1 {
+		t.Fatalf("expected enter to reuse the current summary")
+	}
+	if err := app.RegenerateSummary("other-model"); err != nil || app.current.Content != "- by other-model" || app.current.Model != "other-model" {
+		t.Fatalf("RegenerateSummary: %+v %v", app.current, err)
+	}
+	if err := handleCommand(app, "regenerate", io.Discard); err != nil || app.current.Content != "- by default-model" {
+		t.Fatalf("regenerate command: %q %v", app.current.Content, err)
+	}
+	versions := app.store.SummaryVersions(app.current.ArticleID)
+	if len(versions) != 3 || versions[1].Model != "other-model" || versions[2].Model != "default-model" {
+		t.Fatalf("expected every generation kept, got %+v", versions)
+	}
+	app.CycleSummaryVersion(1)
+	if app.current.Content != "- by other-model" {
+		t.Fatalf("expected to flip to the other model, got %q", app.current.Content)
+	}
+}
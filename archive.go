@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ArchiveClient struct {
+	kind     string
+	baseURL  string
+	token    string
+	username string
+	password string
+	client   *http.Client
+}
+
+func NewArchiveClient(cfg Config) *ArchiveClient {
+	kind := strings.TrimSpace(cfg.ArchiveType)
+	base := strings.TrimSpace(cfg.ArchiveURL)
+	if kind == "" || base == "" {
+		return nil
+	}
+	return &ArchiveClient{
+		kind:     kind,
+		baseURL:  strings.TrimRight(base, "/"),
+		token:    strings.TrimSpace(cfg.ArchiveToken),
+		username: strings.TrimSpace(cfg.ArchiveUsername),
+		password: cfg.ArchivePassword,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (c *ArchiveClient) Name() string {
+	if c != nil && c.kind == "shiori" {
+		return "Shiori"
+	}
+	return "Readeck"
+}
+
+func (c *ArchiveClient) Save(item RaindropItem) (int, error) {
+	if c == nil {
+		return 0, errors.New("archive not configured")
+	}
+	if c.kind == "shiori" {
+		return c.saveShiori(item)
+	}
+	return c.saveReadeck(item)
+}
+
+func (c *ArchiveClient) saveReadeck(item RaindropItem) (int, error) {
+	resp, err := c.postJSON("/api/bookmarks", map[string]any{
+		"url":    item.Link,
+		"title":  item.Title,
+		"labels": item.Tags,
+	}, c.token)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	id, _ := strconv.Atoi(resp.Header.Get("bookmark-id"))
+	return id, nil
+}
+
+func (c *ArchiveClient) saveShiori(item RaindropItem) (int, error) {
+	token, err := c.shioriLogin()
+	if err != nil {
+		return 0, err
+	}
+	tags := make([]map[string]string, 0, len(item.Tags))
+	for _, tag := range item.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, map[string]string{"name": tag})
+		}
+	}
+	resp, err := c.postJSON("/api/bookmarks", map[string]any{
+		"url":           item.Link,
+		"title":         item.Title,
+		"excerpt":       item.Note,
+		"tags":          tags,
+		"createArchive": true,
+	}, token)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	var parsed struct {
+		ID int `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return 0, err
+	}
+	return parsed.ID, nil
+}
+
+func (c *ArchiveClient) shioriLogin() (string, error) {
+	if c.token != "" {
+		return c.token, nil
+	}
+	resp, err := c.postJSON("/api/v1/auth/login", map[string]any{"username": c.username, "password": c.password}, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var parsed struct {
+		Message struct {
+			Token string `json:"token"`
+		} `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", err
+	}
+	if parsed.Message.Token == "" {
+		return "", errors.New("shiori login returned no token")
+	}
+	return parsed.Message.Token, nil
+}
+
+func (c *ArchiveClient) postJSON(path string, payload any, token string) (*http.Response, error) {
+	blob, err := servicesJSONMarshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+path, bytes.NewReader(blob))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("accept", "application/json")
+	if token != "" {
+		req.Header.Set("authorization", "Bearer "+token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s http %d", strings.ToLower(c.Name()), resp.StatusCode)
+	}
+	return resp, nil
+}
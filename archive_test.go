@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func archiveTestClient(t *testing.T, bodies *[]map[string]any) *http.Client {
+	return &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		payload := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		payload["_auth"] = r.Header.Get("authorization")
+		payload["_path"] = r.URL.Path
+		*bodies = append(*bodies, payload)
+		switch r.URL.Path {
+		case "/api/v1/auth/login":
+			return newResponse(http.StatusOK, `{"ok":true,"message":{"token":"session"}}`, nil, r), nil
+		case "/api/bookmarks":
+			if r.Header.Get("authorization") == "" {
+				return newResponse(http.StatusUnauthorized, "", nil, r), nil
+			}
+			return newResponse(http.StatusAccepted, `{"id":12}`, map[string]string{"bookmark-id": "34"}, r), nil
+		}
+		return newResponse(http.StatusNotFound, "", nil, r), nil
+	})}
+}
+
+func TestArchiveClientReadeck(t *testing.T) {
+	if NewArchiveClient(Config{ArchiveType: "readeck"}) != nil {
+		t.Fatalf("expected nil client without url")
+	}
+	client := NewArchiveClient(Config{ArchiveType: "readeck", ArchiveURL: "http://readeck.test/", ArchiveToken: "tok"})
+	if client.Name() != "Readeck" || client.baseURL != "http://readeck.test" {
+		t.Fatalf("unexpected client %+v", client)
+	}
+	bodies := []map[string]any{}
+	client.client = archiveTestClient(t, &bodies)
+	id, err := client.Save(RaindropItem{Link: "https://example.com", Title: "Example", Tags: []string{"go"}})
+	if err != nil || id != 34 {
+		t.Fatalf("Save error: %d %v", id, err)
+	}
+	if bodies[0]["url"] != "https://example.com" || bodies[0]["_auth"] != "Bearer tok" || bodies[0]["labels"].([]any)[0] != "go" {
+		t.Fatalf("unexpected readeck request %v", bodies[0])
+	}
+	client.token = ""
+	if _, err := client.Save(RaindropItem{}); err == nil || err.Error() != "readeck http 401" {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+	var nilClient *ArchiveClient
+	if _, err := nilClient.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if _, err := client.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	client.baseURL = "http://[::1"
+	if _, err := client.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected request error")
+	}
+}
+
+func TestArchiveClientShiori(t *testing.T) {
+	client := NewArchiveClient(Config{ArchiveType: "shiori", ArchiveURL: "http://shiori.test", ArchiveUsername: "me", ArchivePassword: "pw"})
+	if client.Name() != "Shiori" {
+		t.Fatalf("unexpected name %q", client.Name())
+	}
+	bodies := []map[string]any{}
+	client.client = archiveTestClient(t, &bodies)
+	id, err := client.Save(RaindropItem{Link: "https://example.com", Title: "Example", Note: "- summary", Tags: []string{"go", " "}})
+	if err != nil || id != 12 {
+		t.Fatalf("Save error: %d %v", id, err)
+	}
+	if bodies[0]["_path"] != "/api/v1/auth/login" || bodies[0]["username"] != "me" || bodies[0]["password"] != "pw" {
+		t.Fatalf("unexpected login request %v", bodies[0])
+	}
+	saved := bodies[1]
+	if saved["_auth"] != "Bearer session" || saved["createArchive"] != true || saved["excerpt"] != "- summary" || len(saved["tags"].([]any)) != 1 {
+		t.Fatalf("unexpected shiori request %v", saved)
+	}
+	client.token = "static"
+	bodies = bodies[:0]
+	if _, err := client.Save(RaindropItem{Link: "https://example.com"}); err != nil || len(bodies) != 1 {
+		t.Fatalf("expected token to skip login: %v %d", err, len(bodies))
+	}
+	client.token = ""
+	client.client = clientForResponse(http.StatusOK, `{"message":{}}`, nil)
+	if _, err := client.Save(RaindropItem{}); err == nil || !strings.Contains(err.Error(), "no token") {
+		t.Fatalf("expected missing token error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusOK, `nope`, nil)
+	if _, err := client.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected login decode error")
+	}
+	client.token = "static"
+	if _, err := client.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected bookmark decode error")
+	}
+	client.client = clientForResponse(http.StatusForbidden, ``, nil)
+	if _, err := client.Save(RaindropItem{}); err == nil || err.Error() != "shiori http 403" {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	client.token = ""
+	if _, err := client.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected login http error")
+	}
+}
+
+func TestAppShareTargets(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "g1", Title: "T", URL: "https://example.com/a"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	model := newTUIModel(app)
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	model = updated.(tuiModel)
+	if model.inputMode != inputNone || app.status != "No save targets configured" {
+		t.Fatalf("expected no targets status, got %q", app.status)
+	}
+	if err := app.SaveToArchive(nil); err == nil {
+		t.Fatalf("expected archive not configured error")
+	}
+	if err := app.SaveBookmarkTo("delicious", nil); err == nil {
+		t.Fatalf("expected unknown target error")
+	}
+
+	bodies := []map[string]any{}
+	app.archive = &ArchiveClient{kind: "readeck", baseURL: "http://readeck.test", token: "tok", client: archiveTestClient(t, &bodies)}
+	app.pinboard = &PinboardClient{baseURL: "http://pinboard.test", token: "t", client: clientForResponse(http.StatusOK, `{"result_code":"done"}`, nil)}
+	if targets := app.ShareTargets(); len(targets) != 2 || targets[0] != "pinboard" || targets[1] != "archive" {
+		t.Fatalf("unexpected targets %v", targets)
+	}
+	if target, ok := app.resolveShareTarget("Readeck"); !ok || target != "archive" {
+		t.Fatalf("expected archive by display name")
+	}
+	if _, ok := app.resolveShareTarget("3"); ok {
+		t.Fatalf("expected out of range index to fail")
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	model = updated.(tuiModel)
+	if model.inputMode != inputShareTarget || model.input.Placeholder != "1 Pinboard, 2 Readeck" {
+		t.Fatalf("unexpected share prompt %q", model.input.Placeholder)
+	}
+	model.input.SetValue("nope")
+	model = model.commitInput()
+	if app.status != "Unknown save target: nope" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	model = model.startInput(inputShareTarget, "")
+	model.input.SetValue("2")
+	model = model.commitInput()
+	if model.inputMode != inputBookmarkTags || model.input.Placeholder != "Readeck tags (comma separated)" {
+		t.Fatalf("expected tags prompt for archive, got %v %q", model.inputMode, model.input.Placeholder)
+	}
+	model.input.SetValue("go, later")
+	model = model.commitInput()
+	if app.status != "Archived to Readeck" || model.shareTarget != "" || len(bodies) != 1 {
+		t.Fatalf("expected archive save, got %q", app.status)
+	}
+
+	var out bytes.Buffer
+	if err := handleCommand(app, "share pinboard go", &out); err != nil || app.status != "Saved to Pinboard" {
+		t.Fatalf("expected REPL share to pinboard: %v %q", err, app.status)
+	}
+	if err := handleCommand(app, "share", &out); err == nil || !strings.Contains(err.Error(), "pinboard, archive") {
+		t.Fatalf("expected missing target error, got %v", err)
+	}
+	if err := handleCommand(app, "S raindrop", &out); err == nil {
+		t.Fatalf("expected unconfigured target error")
+	}
+	app.archive.client = clientForResponse(http.StatusInternalServerError, "", nil)
+	if err := app.SaveToArchive(nil); err == nil {
+		t.Fatalf("expected archive save error")
+	}
+}
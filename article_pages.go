@@ -0,0 +1,75 @@
+package main
+
+const articlePrefetchRows = 20
+
+func (a *App) loadArticles() {
+	limit := a.config.ArticlePageSize
+	if limit > 0 && len(a.articles) > limit {
+		limit = len(a.articles)
+	}
+	a.articles = a.store.SortedArticlesPage(a.sortMode, nil, limit)
+	a.moreArticles = limit > 0 && len(a.articles) == limit
+	a.pageCursor = pageCursor(a.articles)
+}
+
+// pageCursor copies the last row of a page as the store returned it. Reading,
+// starring and scoring edit a.articles in place, and a cursor built from the
+// edited row would page from the wrong spot in unread or ranked order.
+func pageCursor(page []Article) *Article {
+	if len(page) == 0 {
+		return nil
+	}
+	last := page[len(page)-1]
+	return &last
+}
+
+func (a *App) LoadMoreArticles() int {
+	if !a.moreArticles || a.pageCursor == nil {
+		a.moreArticles = false
+		return 0
+	}
+	page := a.store.SortedArticlesPage(a.sortMode, a.pageCursor, a.config.ArticlePageSize)
+	if len(page) > 0 {
+		a.pageCursor = pageCursor(page)
+	}
+	// A row edited since it was loaded can sort again further down; keep the
+	// copy already on screen.
+	loaded := make(map[int]bool, len(a.articles))
+	for _, article := range a.articles {
+		loaded[article.ID] = true
+	}
+	start := len(a.articles)
+	for _, article := range page {
+		if !loaded[article.ID] {
+			a.articles = append(a.articles, article)
+		}
+	}
+	a.moreArticles = a.config.ArticlePageSize > 0 && len(page) == a.config.ArticlePageSize
+	_ = a.scoreArticles(a.articles[start:])
+	return len(page)
+}
+
+// allArticles returns the whole collection in the current sort order. With
+// tui.page_size set a.articles only holds the pages loaded for display, so
+// anything that acts on every article reads the rest from the store.
+func (a *App) allArticles() []Article {
+	if !a.moreArticles {
+		return a.articles
+	}
+	return a.store.SortedArticles(a.sortMode)
+}
+
+// loadAllArticles pages in the rest of the list, for actions that apply to
+// everything the current filter shows rather than just the loaded rows.
+func (a *App) loadAllArticles() {
+	for a.moreArticles && a.LoadMoreArticles() > 0 {
+	}
+}
+
+func (a *App) ensureArticlesLoaded(index int) {
+	for a.moreArticles && index+articlePrefetchRows >= len(a.FilteredArticles()) {
+		if a.LoadMoreArticles() == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAppLoadsArticlesInPages(t *testing.T) {
+	app := newTUIApp(t)
+	insertPagedArticles(t, app.store, 60)
+	app.config.ArticlePageSize = 25
+	app.filter = FilterAll
+	app.articles = nil
+	app.loadArticles()
+	if len(app.articles) != 25 || !app.moreArticles {
+		t.Fatalf("expected first page, got %d more=%v", len(app.articles), app.moreArticles)
+	}
+	if _, total := app.scopeCounts(); total != 60 {
+		t.Fatalf("expected total from store, got %d", total)
+	}
+	app.MoveSelection(5)
+	if len(app.articles) != 50 || app.selectedIndex != 5 {
+		t.Fatalf("expected prefetch near the end of the page, got %d rows at %d", len(app.articles), app.selectedIndex)
+	}
+	app.MoveSelection(100)
+	if len(app.articles) != 60 || app.moreArticles || app.selectedIndex != 59 {
+		t.Fatalf("expected all rows loaded, got %d more=%v at %d", len(app.articles), app.moreArticles, app.selectedIndex)
+	}
+	if app.LoadMoreArticles() != 0 {
+		t.Fatalf("expected nothing left to load")
+	}
+	app.loadArticles()
+	if len(app.articles) != 60 {
+		t.Fatalf("expected reload to keep the scrolled window, got %d", len(app.articles))
+	}
+
+	app.config.ArticlePageSize = 0
+	app.articles = nil
+	app.loadArticles()
+	if len(app.articles) != 60 || app.moreArticles {
+		t.Fatalf("expected everything without paging, got %d", len(app.articles))
+	}
+}
+
+func TestAppPagingSkipsFilteredRows(t *testing.T) {
+	app := newTUIApp(t)
+	insertPagedArticles(t, app.store, 60)
+	app.config.ArticlePageSize = 10
+	app.filter = FilterStarred
+	app.articles = nil
+	app.loadArticles()
+	app.MoveSelection(1)
+	if len(app.articles) != 60 || app.moreArticles || app.selectedIndex != 0 {
+		t.Fatalf("expected paging to run out without starred rows, got %d more=%v", len(app.articles), app.moreArticles)
+	}
+}
+
+func TestAppPagingUnreadFirstAfterMarkingLastRowRead(t *testing.T) {
+	app := newTUIApp(t)
+	insertPagedArticles(t, app.store, 30)
+	app.config.ArticlePageSize = 10
+	app.filter = FilterAll
+	app.sortMode = SortUnreadFirst
+	app.articles = nil
+	app.loadArticles()
+	app.selectedIndex = 9
+	if err := app.ToggleRead(); err != nil || !app.articles[9].IsRead {
+		t.Fatalf("ToggleRead error: %v", err)
+	}
+	if loaded := app.LoadMoreArticles(); loaded != 10 {
+		t.Fatalf("expected the next unread page, got %d rows", loaded)
+	}
+	app.loadAllArticles()
+	seen := map[int]bool{}
+	for _, article := range app.articles {
+		seen[article.ID] = true
+	}
+	if len(app.articles) != 30 || len(seen) != 30 {
+		t.Fatalf("expected every article paged in once, got %d rows (%d distinct)", len(app.articles), len(seen))
+	}
+}
+
+func TestTUIListScrollsWithSelection(t *testing.T) {
+	app := newTUIApp(t)
+	insertPagedArticles(t, app.store, 40)
+	app.filter = FilterAll
+	app.loadArticles()
+	m := newTUIModel(app)
+	m.width, m.height = 100, 16
+	app.selectedIndex = 30
+	articles := app.FilteredArticles()
+	var selectedLine string
+	titles := map[string]bool{}
+	for _, line := range strings.Split(m.renderList(60), "\n") {
+		if strings.Contains(line, "▸") {
+			selectedLine = line
+		}
+		titles[strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "▸"))] = true
+	}
+	if !strings.Contains(selectedLine, articles[30].Title) {
+		t.Fatalf("expected selected row in view, got %q", selectedLine)
+	}
+	if titles[articles[0].Title] {
+		t.Fatalf("expected top rows scrolled out of view")
+	}
+}
+
+func TestConfigPageSize(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("[tui]\npage_size = 500", &cfg); err != nil || cfg.ArticlePageSize != 500 {
+		t.Fatalf("unexpected page size %d %v", cfg.ArticlePageSize, err)
+	}
+	if !strings.Contains(renderConfig(cfg), "[tui]\npage_size = 500") {
+		t.Fatalf("unexpected rendered config %s", renderConfig(cfg))
+	}
+	if err := parseConfig("[tui]\npage_size = -1", &cfg); err == nil || !strings.Contains(err.Error(), "invalid tui.page_size") {
+		t.Fatalf("expected page size error, got %v", err)
+	}
+}
+
+func TestAppPagingWholeCollectionActions(t *testing.T) {
+	app := newTUIApp(t)
+	insertPagedArticles(t, app.store, 30)
+	app.config.ArticlePageSize = 10
+	app.filter = FilterAll
+	app.articles = nil
+	app.loadArticles()
+	oldest := app.store.SortedArticles(SortOldest)[0]
+	if err := app.store.SetArticlesStarred([]int{oldest.ID}, true); err != nil {
+		t.Fatalf("SetArticlesStarred error: %v", err)
+	}
+	if err := app.store.QueueArticle(oldest.ID); err != nil {
+		t.Fatalf("QueueArticle error: %v", err)
+	}
+	app.queue = app.store.QueuePositions()
+	app.loadArticles()
+	if len(app.articles) != 10 || app.findArticle(oldest.ID) != nil {
+		t.Fatalf("expected only the first page loaded, got %d", len(app.articles))
+	}
+
+	if starred := app.starredArticles(); len(starred) != 1 || starred[0].ID != oldest.ID {
+		t.Fatalf("expected starred article beyond the loaded page, got %+v", starred)
+	}
+	if queued := app.queuedArticles(); len(queued) != 1 || queued[0].ID != oldest.ID {
+		t.Fatalf("expected queued article beyond the loaded page, got %+v", queued)
+	}
+	app.ToggleQueueView()
+	if shown := app.FilteredArticles(); len(shown) != 1 || shown[0].ID != oldest.ID || len(app.articles) != 10 {
+		t.Fatalf("expected the queue view without paging everything in, got %d shown and %d loaded", len(shown), len(app.articles))
+	}
+	app.ToggleQueueView()
+	if articles, _ := app.DigestCandidates(); len(articles) != 30 {
+		t.Fatalf("expected digest over every unread article, got %d", len(articles))
+	}
+	if depth := app.summaryQueueDepth(); depth != 30 {
+		t.Fatalf("expected queue depth over every article, got %d", depth)
+	}
+	app.config.MutedWords = []string{"article"}
+	if count := app.mutedCount(); count != 30 {
+		t.Fatalf("expected muted count over every article, got %d", count)
+	}
+	app.config.MutedWords = nil
+	if len(app.articles) != 10 {
+		t.Fatalf("expected whole-collection reads to leave the page alone, got %d", len(app.articles))
+	}
+
+	if err := app.MarkAllRead(); err != nil || app.status != tr(msgBulkMarkedRead, 30) {
+		t.Fatalf("expected every article marked read, got %v %q", err, app.status)
+	}
+	if depth := app.summaryQueueDepth(); depth != 0 {
+		t.Fatalf("expected nothing unread left, got %d", depth)
+	}
+}
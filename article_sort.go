@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type SortMode string
+
+const (
+	SortNewest      SortMode = "newest"
+	SortFetched     SortMode = "fetched"
+	SortUnreadFirst SortMode = "unread"
+	SortFeed        SortMode = "feed"
+	SortOldest      SortMode = "oldest"
+	SortRanked      SortMode = "ranked"
+)
+
+var sortModeCycle = []SortMode{SortNewest, SortFetched, SortUnreadFirst, SortFeed, SortOldest, SortRanked}
+
+var sortModeLabels = map[SortMode]string{
+	SortNewest:      "newest first",
+	SortFetched:     "recently fetched first",
+	SortUnreadFirst: "unread first",
+	SortFeed:        "grouped by feed",
+	SortOldest:      "oldest first",
+	SortRanked:      "ranked by relevance",
+}
+
+type articleSortKey struct {
+	expr  string
+	desc  bool
+	value func(Article) any
+}
+
+func publishedSortValue(article Article) any { return timeToUnix(article.PublishedAt) }
+
+func articleSortKeys(mode SortMode) []articleSortKey {
+	published := articleSortKey{expr: "COALESCE(published_at, 0)", desc: true, value: publishedSortValue}
+	switch mode {
+	case SortFetched:
+		return []articleSortKey{{expr: "COALESCE(fetched_at, 0)", desc: true, value: func(a Article) any { return timeToUnix(a.FetchedAt) }}}
+	case SortUnreadFirst:
+		return []articleSortKey{{expr: "COALESCE(is_read, 0)", value: func(a Article) any { return boolToInt(a.IsRead) }}, published}
+	case SortFeed:
+		return []articleSortKey{
+			{expr: "COALESCE(feed_title, '') COLLATE NOCASE", value: func(a Article) any { return a.FeedTitle }},
+			{expr: "feed_id", value: func(a Article) any { return a.FeedID }},
+			published,
+		}
+	case SortOldest:
+		published.desc = false
+		return []articleSortKey{published}
+	case SortRanked:
+		return []articleSortKey{
+			{expr: "COALESCE(score, 0)", desc: true, value: func(a Article) any { return a.Score }},
+			{expr: "id", desc: true, value: func(a Article) any { return a.ID }},
+		}
+	}
+	return []articleSortKey{published}
+}
+
+func articleOrderSQL(mode SortMode, after *Article) (string, string, []any) {
+	keys := articleSortKeys(mode)
+	if keys[len(keys)-1].expr != "id" {
+		keys = append(keys, articleSortKey{expr: "id", value: func(a Article) any { return a.ID }})
+	}
+	order := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.desc {
+			order = append(order, key.expr+" DESC")
+		} else {
+			order = append(order, key.expr)
+		}
+	}
+	if after == nil {
+		return "", strings.Join(order, ", "), nil
+	}
+	clauses := []string{}
+	args := []any{}
+	for i, key := range keys {
+		terms := []string{}
+		for _, previous := range keys[:i] {
+			terms = append(terms, previous.expr+" = ?")
+			args = append(args, previous.value(*after))
+		}
+		op := " > ?"
+		if key.desc {
+			op = " < ?"
+		}
+		terms = append(terms, key.expr+op)
+		args = append(args, key.value(*after))
+		clauses = append(clauses, "("+strings.Join(terms, " AND ")+")")
+	}
+	return strings.Join(clauses, " OR "), strings.Join(order, ", "), args
+}
+
+func nextSortMode(mode SortMode) SortMode {
+	for i, candidate := range sortModeCycle {
+		if candidate == mode {
+			return sortModeCycle[(i+1)%len(sortModeCycle)]
+		}
+	}
+	return SortNewest
+}
+
+func validSortMode(mode SortMode) bool {
+	_, ok := sortModeLabels[mode]
+	return ok
+}
+
+func relativeAge(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	age := now.Sub(t)
+	switch {
+	case age < time.Minute:
+		return "now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age/time.Hour))
+	case age < 365*24*time.Hour:
+		return fmt.Sprintf("%dd", int(age/(24*time.Hour)))
+	}
+	return fmt.Sprintf("%dy", int(age/(365*24*time.Hour)))
+}
+
+func articleAge(article Article, now time.Time) string {
+	if article.PublishedAt.IsZero() {
+		return relativeAge(article.FetchedAt, now)
+	}
+	return relativeAge(article.PublishedAt, now)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func insertSortArticles(t *testing.T, store *Store) {
+	t.Helper()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for f, title := range []string{"beta", "Alpha", "gamma"} {
+		feed, err := store.InsertFeed(Feed{Title: title, URL: "https://" + title + ".example.com/rss"})
+		if err != nil {
+			t.Fatalf("InsertFeed error: %v", err)
+		}
+		articles := []Article{}
+		for i := 0; i < 4; i++ {
+			articles = append(articles, Article{
+				GUID:        fmt.Sprintf("%s-%d", title, i),
+				Title:       fmt.Sprintf("%s %d", title, i),
+				URL:         fmt.Sprintf("https://%s.example.com/%d", title, i),
+				PublishedAt: base.Add(time.Duration((i*3+f)%5) * time.Hour),
+				FetchedAt:   base.Add(time.Duration((i+f*2)%3) * time.Minute),
+				IsRead:      (i+f)%2 == 0,
+			})
+		}
+		if _, err := store.InsertArticles(feed, articles); err != nil {
+			t.Fatalf("InsertArticles error: %v", err)
+		}
+	}
+}
+
+func TestStoreSortedArticlesModes(t *testing.T) {
+	store := newTestStore(t)
+	insertSortArticles(t, store)
+	scores := map[int]int{}
+	for _, article := range store.SortedArticles() {
+		scores[article.ID] = article.ID % 3 * 10
+	}
+	if err := store.SetArticleScores(scores); err != nil {
+		t.Fatalf("SetArticleScores error: %v", err)
+	}
+	less := map[SortMode]func(a, b Article) bool{
+		SortRanked:  func(a, b Article) bool { return a.Score > b.Score || a.Score == b.Score && a.ID > b.ID },
+		SortNewest:  func(a, b Article) bool { return a.PublishedAt.After(b.PublishedAt) },
+		SortOldest:  func(a, b Article) bool { return a.PublishedAt.Before(b.PublishedAt) },
+		SortFetched: func(a, b Article) bool { return a.FetchedAt.After(b.FetchedAt) },
+		SortUnreadFirst: func(a, b Article) bool {
+			return !a.IsRead && b.IsRead || a.IsRead == b.IsRead && a.PublishedAt.After(b.PublishedAt)
+		},
+		SortFeed: func(a, b Article) bool {
+			if !strings.EqualFold(a.FeedTitle, b.FeedTitle) {
+				return strings.ToLower(a.FeedTitle) < strings.ToLower(b.FeedTitle)
+			}
+			return a.PublishedAt.After(b.PublishedAt)
+		},
+	}
+	for mode, before := range less {
+		all := store.SortedArticles(mode)
+		if len(all) != 12 {
+			t.Fatalf("%s: expected 12 articles, got %d", mode, len(all))
+		}
+		if !sort.SliceIsSorted(all, func(i, j int) bool { return before(all[i], all[j]) }) {
+			t.Fatalf("%s: articles out of order", mode)
+		}
+		paged := []Article{}
+		var after *Article
+		for {
+			page := store.SortedArticlesPage(mode, after, 5)
+			paged = append(paged, page...)
+			if len(page) < 5 {
+				break
+			}
+			after = &page[len(page)-1]
+		}
+		if len(paged) != len(all) {
+			t.Fatalf("%s: expected %d paged articles, got %d", mode, len(all), len(paged))
+		}
+		for i := range all {
+			if paged[i].ID != all[i].ID {
+				t.Fatalf("%s: page order differs at %d", mode, i)
+			}
+		}
+	}
+	if grouped := store.SortedArticles(SortFeed); grouped[0].FeedTitle != "Alpha" || grouped[11].FeedTitle != "gamma" {
+		t.Fatalf("expected feeds grouped case-insensitively, got %s..%s", grouped[0].FeedTitle, grouped[11].FeedTitle)
+	}
+}
+
+func TestToggleSortCyclesModes(t *testing.T) {
+	app := newTUIApp(t)
+	app.filter = FilterAll
+	insertSortArticles(t, app.store)
+	app.loadArticles()
+	seen := []SortMode{}
+	for range sortModeCycle {
+		app.ToggleSort()
+		seen = append(seen, app.sortMode)
+	}
+	if fmt.Sprint(seen) != fmt.Sprint([]SortMode{SortFetched, SortUnreadFirst, SortFeed, SortOldest, SortRanked, SortNewest}) {
+		t.Fatalf("unexpected cycle %v", seen)
+	}
+	app.sortMode = SortUnreadFirst
+	app.ToggleSort()
+	if app.status != "Sort: grouped by feed" || app.FilteredArticles()[0].FeedTitle != "Alpha" {
+		t.Fatalf("expected feed-grouped list, got %q", app.status)
+	}
+	if err := app.store.SaveSession(Session{Filter: FilterAll, SortMode: SortOldest}); err != nil {
+		t.Fatalf("SaveSession error: %v", err)
+	}
+	app.sortMode = SortNewest
+	app.restoreSession()
+	if app.sortMode != SortOldest || !app.FilteredArticles()[0].PublishedAt.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected oldest-first restored, got %s", app.sortMode)
+	}
+}
+
+func TestRelativeAge(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	cases := map[time.Duration]string{
+		30 * time.Second:     "now",
+		45 * time.Minute:     "45m",
+		3 * time.Hour:        "3h",
+		50 * time.Hour:       "2d",
+		800 * 24 * time.Hour: "2y",
+	}
+	for age, want := range cases {
+		if got := relativeAge(now.Add(-age), now); got != want {
+			t.Fatalf("relativeAge(%v) = %q, want %q", age, got, want)
+		}
+	}
+	if got := relativeAge(time.Time{}, now); got != "" {
+		t.Fatalf("expected no age for zero time, got %q", got)
+	}
+	if got := articleAge(Article{FetchedAt: now.Add(-2 * time.Hour)}, now); got != "2h" {
+		t.Fatalf("expected fetched time fallback, got %q", got)
+	}
+}
+
+func TestTUIListShowsArticleAge(t *testing.T) {
+	app := newTUIApp(t)
+	app.filter = FilterAll
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	app.clock = &testClock{now: now}
+	feed, _ := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "g", Title: "Fresh story", URL: "https://example.com/1", PublishedAt: now.Add(-3 * time.Hour)}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.loadArticles()
+	model := newTUIModel(app)
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 100, Height: 24})
+	model = updated.(tuiModel)
+	if view := model.View(); !strings.Contains(view, "3h Fresh story") {
+		t.Fatalf("expected age next to the title, got %s", view)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const zoteroBatchSize = 50
+
+var bibtexWriteFile = os.WriteFile
+
+var bibtexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	"{", `\{`,
+	"}", `\}`,
+	"%", `\%`,
+	"&", `\&`,
+	"#", `\#`,
+	"_", `\_`,
+	"$", `\$`,
+)
+
+func bibtexKey(article Article, used map[string]int) string {
+	source := article.Author
+	if fields := strings.Fields(source); len(fields) > 0 {
+		source = fields[len(fields)-1]
+	} else {
+		source = article.FeedTitle
+	}
+	prefix := bibtexWord(source)
+	if prefix == "" {
+		prefix = "article"
+	}
+	year := ""
+	if !article.PublishedAt.IsZero() {
+		year = article.PublishedAt.UTC().Format("2006")
+	}
+	title := ""
+	for _, word := range strings.Fields(article.Title) {
+		if title = bibtexWord(word); len(title) > 3 {
+			break
+		}
+	}
+	key := prefix + year + title
+	used[key]++
+	if count := used[key]; count > 1 {
+		key += string(rune('a' + count - 2))
+	}
+	return key
+}
+
+func bibtexWord(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func renderBibTeX(articles []Article, summaries map[int]string) string {
+	used := map[string]int{}
+	entries := make([]string, 0, len(articles))
+	for _, article := range articles {
+		fields := [][2]string{{"title", "{" + bibtexEscaper.Replace(article.Title) + "}"}}
+		if article.Author != "" {
+			fields = append(fields, [2]string{"author", bibtexEscaper.Replace(article.Author)})
+		}
+		if article.FeedTitle != "" {
+			fields = append(fields, [2]string{"organization", bibtexEscaper.Replace(article.FeedTitle)})
+		}
+		if !article.PublishedAt.IsZero() {
+			fields = append(fields, [2]string{"date", article.PublishedAt.UTC().Format("2006-01-02")}, [2]string{"year", article.PublishedAt.UTC().Format("2006")})
+		}
+		fields = append(fields, [2]string{"url", article.URL})
+		if !article.FetchedAt.IsZero() {
+			fields = append(fields, [2]string{"urldate", article.FetchedAt.UTC().Format("2006-01-02")})
+		}
+		if summary := strings.TrimSpace(summaries[article.ID]); summary != "" {
+			fields = append(fields, [2]string{"abstract", bibtexEscaper.Replace(strings.Join(strings.Fields(summary), " "))})
+		}
+		lines := []string{"@online{" + bibtexKey(article, used) + ","}
+		for _, field := range fields {
+			lines = append(lines, fmt.Sprintf("  %s = {%s},", field[0], field[1]))
+		}
+		lines = append(lines, "}")
+		entries = append(entries, strings.Join(lines, "\n"))
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	return strings.Join(entries, "\n\n") + "\n"
+}
+
+func (a *App) starredArticles() []Article {
+	starred := []Article{}
+	for _, article := range a.allArticles() {
+		if article.IsStarred {
+			starred = append(starred, article)
+		}
+	}
+	return starred
+}
+
+func (a *App) articleSummaries(articles []Article) map[int]string {
+	summaries := map[int]string{}
+	for _, article := range articles {
+		if summary, ok := a.store.FindSummary(article.ID); ok {
+			summaries[article.ID] = summary.Content
+		}
+	}
+	return summaries
+}
+
+func (a *App) ExportBibTeX(path string) (int, error) {
+	if strings.TrimSpace(path) == "" {
+		return 0, errors.New("missing bibtex path")
+	}
+	starred := a.starredArticles()
+	if err := bibtexWriteFile(path, []byte(renderBibTeX(starred, a.articleSummaries(starred))), 0o644); err != nil {
+		a.status = "BibTeX export failed: " + err.Error()
+		return 0, err
+	}
+	a.status = fmt.Sprintf("Exported %d starred articles to %s", len(starred), path)
+	return len(starred), nil
+}
+
+type ZoteroClient struct {
+	baseURL string
+	apiKey  string
+	userID  string
+	client  *http.Client
+}
+
+type zoteroCreator struct {
+	CreatorType string `json:"creatorType"`
+	Name        string `json:"name"`
+}
+
+type zoteroTag struct {
+	Tag string `json:"tag"`
+}
+
+type zoteroItem struct {
+	ItemType     string          `json:"itemType"`
+	Title        string          `json:"title"`
+	URL          string          `json:"url"`
+	WebsiteTitle string          `json:"websiteTitle,omitempty"`
+	Date         string          `json:"date,omitempty"`
+	AccessDate   string          `json:"accessDate,omitempty"`
+	AbstractNote string          `json:"abstractNote,omitempty"`
+	Creators     []zoteroCreator `json:"creators"`
+	Tags         []zoteroTag     `json:"tags"`
+}
+
+func NewZoteroClient(apiKey, userID string) *ZoteroClient {
+	apiKey = strings.TrimSpace(apiKey)
+	userID = strings.TrimSpace(userID)
+	if apiKey == "" || userID == "" {
+		return nil
+	}
+	base := strings.TrimSpace(os.Getenv("ZOTERO_BASE_URL"))
+	if base == "" {
+		base = "https://api.zotero.org"
+	}
+	return &ZoteroClient{
+		baseURL: strings.TrimRight(base, "/"),
+		apiKey:  apiKey,
+		userID:  userID,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func zoteroItemFor(article Article, summary string, tags []string) zoteroItem {
+	item := zoteroItem{
+		ItemType:     "webpage",
+		Title:        article.Title,
+		URL:          article.URL,
+		WebsiteTitle: article.FeedTitle,
+		AbstractNote: strings.TrimSpace(summary),
+		Creators:     []zoteroCreator{},
+		Tags:         []zoteroTag{},
+	}
+	if !article.PublishedAt.IsZero() {
+		item.Date = article.PublishedAt.UTC().Format("2006-01-02")
+	}
+	if !article.FetchedAt.IsZero() {
+		item.AccessDate = article.FetchedAt.UTC().Format(time.RFC3339)
+	}
+	if article.Author != "" {
+		item.Creators = append(item.Creators, zoteroCreator{CreatorType: "author", Name: article.Author})
+	}
+	for _, tag := range tags {
+		item.Tags = append(item.Tags, zoteroTag{Tag: tag})
+	}
+	return item
+}
+
+func (z *ZoteroClient) Save(items []zoteroItem) (int, error) {
+	if z == nil {
+		return 0, errors.New("zotero not configured")
+	}
+	saved := 0
+	for start := 0; start < len(items); start += zoteroBatchSize {
+		end := min(start+zoteroBatchSize, len(items))
+		blob, err := servicesJSONMarshal(items[start:end])
+		if err != nil {
+			return saved, err
+		}
+		req, err := http.NewRequest(http.MethodPost, z.baseURL+"/users/"+z.userID+"/items", bytes.NewReader(blob))
+		if err != nil {
+			return saved, err
+		}
+		req.Header.Set("Zotero-API-Key", z.apiKey)
+		req.Header.Set("Zotero-API-Version", "3")
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := z.client.Do(req)
+		if err != nil {
+			return saved, err
+		}
+		var parsed struct {
+			Successful map[string]json.RawMessage `json:"successful"`
+			Failed     map[string]struct {
+				Message string `json:"message"`
+			} `json:"failed"`
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			resp.Body.Close()
+			return saved, fmt.Errorf("zotero http %d", resp.StatusCode)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&parsed)
+		resp.Body.Close()
+		if err != nil {
+			return saved, err
+		}
+		saved += len(parsed.Successful)
+		for _, failure := range parsed.Failed {
+			return saved, errors.New("zotero: " + failure.Message)
+		}
+	}
+	return saved, nil
+}
+
+func (a *App) SaveStarredToZotero() (int, error) {
+	if a.zotero == nil {
+		return 0, errors.New("zotero not configured")
+	}
+	starred := a.starredArticles()
+	summaries := a.articleSummaries(starred)
+	items := make([]zoteroItem, 0, len(starred))
+	for _, article := range starred {
+		items = append(items, zoteroItemFor(article, summaries[article.ID], a.noteTags(article)))
+	}
+	saved, err := a.zotero.Save(items)
+	if err != nil {
+		a.status = "Zotero export failed: " + err.Error()
+		return saved, err
+	}
+	a.status = fmt.Sprintf("Saved %d starred articles to Zotero", saved)
+	return saved, nil
+}
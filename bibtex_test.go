@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderBibTeX(t *testing.T) {
+	published := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	articles := []Article{
+		{ID: 1, Title: "A Study of 100% Go_Code & {Braces}", URL: "https://example.com/a", Author: "Jane Q. Doe", FeedTitle: "Go Blog", PublishedAt: published, FetchedAt: published.Add(24 * time.Hour)},
+		{ID: 2, Title: "A Study again", URL: "https://example.com/b", Author: "John Doe", PublishedAt: published},
+		{ID: 3, Title: "", URL: "https://example.com/c"},
+	}
+	rendered := renderBibTeX(articles, map[int]string{1: "- first\n- second"})
+	for _, want := range []string{
+		"@online{doe2024study,\n  title = {{A Study of 100\\% Go\\_Code \\& \\{Braces\\}}},\n  author = {Jane Q. Doe},\n  organization = {Go Blog},\n  date = {2024-03-05},\n  year = {2024},\n  url = {https://example.com/a},\n  urldate = {2024-03-06},\n  abstract = {- first - second},\n}",
+		"@online{doe2024studya,",
+		"@online{article,\n  title = {{}},\n  url = {https://example.com/c},\n}\n",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("bibtex missing %q:\n%s", want, rendered)
+		}
+	}
+	if renderBibTeX(nil, nil) != "" {
+		t.Fatalf("expected empty output")
+	}
+	if key := bibtexKey(Article{FeedTitle: "Ars Technica!", Title: "On it"}, map[string]int{}); key != "arstechnicait" {
+		t.Fatalf("unexpected feed key %q", key)
+	}
+}
+
+func TestAppExportBibTeX(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "Starred Paper", URL: "https://example.com/1", Author: "Ann Smith", IsStarred: true},
+		{GUID: "2", Title: "Unstarred", URL: "https://example.com/2"},
+	}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	if _, err := app.ExportBibTeX(" "); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+	path := filepath.Join(t.TempDir(), "starred.bib")
+	count, err := app.ExportBibTeX(path)
+	if err != nil || count != 1 {
+		t.Fatalf("ExportBibTeX error: %d %v", count, err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "@online{smith") || strings.Contains(string(data), "Unstarred") {
+		t.Fatalf("unexpected bibtex file:\n%s", data)
+	}
+	if err := handleCommand(app, "bibtex "+path, io.Discard); err != nil {
+		t.Fatalf("bibtex command error: %v", err)
+	}
+	if err := handleCommand(app, "bibtex", io.Discard); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+	orig := bibtexWriteFile
+	bibtexWriteFile = func(string, []byte, os.FileMode) error { return errors.New("read-only") }
+	t.Cleanup(func() { bibtexWriteFile = orig })
+	if _, err := app.ExportBibTeX(path); err == nil || app.status != "BibTeX export failed: read-only" {
+		t.Fatalf("expected write error, got %v %q", err, app.status)
+	}
+}
+
+func TestZoteroClient(t *testing.T) {
+	if NewZoteroClient("key", " ") != nil || NewZoteroClient("", "42") != nil {
+		t.Fatalf("expected nil client without credentials")
+	}
+	t.Setenv("ZOTERO_BASE_URL", "http://zotero.test/")
+	client := NewZoteroClient("key", "42")
+	batches := [][]zoteroItem{}
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/users/42/items" || r.Header.Get("Zotero-API-Key") != "key" || r.Header.Get("Zotero-API-Version") != "3" {
+			return newResponse(http.StatusForbidden, "", nil, r), nil
+		}
+		body, _ := io.ReadAll(r.Body)
+		var items []zoteroItem
+		if err := json.Unmarshal(body, &items); err != nil {
+			t.Fatalf("bad payload %s", body)
+		}
+		batches = append(batches, items)
+		successful := make([]string, len(items))
+		for i := range items {
+			successful[i] = `"` + string(rune('0'+i%10)) + string(rune('a'+i/10)) + `":{}`
+		}
+		return newResponse(http.StatusOK, `{"successful":{`+strings.Join(successful, ",")+`},"failed":{}}`, nil, r), nil
+	})}
+	items := make([]zoteroItem, 51)
+	saved, err := client.Save(items)
+	if err != nil || saved != 51 || len(batches) != 2 || len(batches[0]) != 50 {
+		t.Fatalf("unexpected save %d %v %d", saved, err, len(batches))
+	}
+
+	client.client = clientForResponse(http.StatusOK, `{"successful":{},"failed":{"0":{"code":400,"message":"Invalid itemType"}}}`, nil)
+	if _, err := client.Save(items[:1]); err == nil || err.Error() != "zotero: Invalid itemType" {
+		t.Fatalf("expected failed item error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusForbidden, "", nil)
+	if _, err := client.Save(items[:1]); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusOK, "nope", nil)
+	if _, err := client.Save(items[:1]); err == nil {
+		t.Fatalf("expected decode error")
+	}
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if _, err := client.Save(items[:1]); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	var nilClient *ZoteroClient
+	if _, err := nilClient.Save(nil); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+
+	published := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	item := zoteroItemFor(Article{Title: "T", URL: "u", FeedTitle: "Feed", Author: "Ann", PublishedAt: published, FetchedAt: published}, " - s ", []string{"rss"})
+	if item.ItemType != "webpage" || item.Date != "2024-03-05" || item.AccessDate != "2024-03-05T00:00:00Z" || item.AbstractNote != "- s" || item.Creators[0].Name != "Ann" || item.Tags[0].Tag != "rss" || item.WebsiteTitle != "Feed" {
+		t.Fatalf("unexpected zotero item %+v", item)
+	}
+}
+
+func TestAppSaveStarredToZotero(t *testing.T) {
+	app := newTUIApp(t)
+	if _, err := app.SaveStarredToZotero(); err == nil {
+		t.Fatalf("expected not configured error")
+	}
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Starred", URL: "https://example.com/1", IsStarred: true}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "- summary"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.zotero = NewZoteroClient("key", "42")
+	var payload []zoteroItem
+	app.zotero.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		return newResponse(http.StatusOK, `{"successful":{"0":{}},"failed":{}}`, nil, r), nil
+	})}
+	if err := handleCommand(app, "zotero", io.Discard); err != nil {
+		t.Fatalf("zotero command error: %v", err)
+	}
+	if app.status != "Saved 1 starred articles to Zotero" || len(payload) != 1 || payload[0].AbstractNote != "- summary" || payload[0].Tags[0].Tag != "rss" {
+		t.Fatalf("unexpected zotero save %q %+v", app.status, payload)
+	}
+	app.zotero.client = clientForResponse(http.StatusInternalServerError, "", nil)
+	if _, err := app.SaveStarredToZotero(); err == nil || !strings.HasPrefix(app.status, "Zotero export failed") {
+		t.Fatalf("expected zotero failure status, got %q", app.status)
+	}
+}
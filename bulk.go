@@ -0,0 +1,83 @@
+package main
+
+import "strings"
+
+func (a *App) MarkAllRead() error {
+	a.loadAllArticles()
+	ids := []int{}
+	for _, article := range a.FilteredArticles() {
+		if !article.IsRead {
+			ids = append(ids, article.ID)
+		}
+	}
+	if err := a.store.SetArticlesRead(ids, true); err != nil {
+		return err
+	}
+	marked := map[int]bool{}
+	for _, id := range ids {
+		marked[id] = true
+	}
+	for i := range a.articles {
+		if marked[a.articles[i].ID] {
+			a.articles[i].IsRead = true
+		}
+	}
+	a.selectedIndex = 0
+	a.syncSummaryForSelection()
+	a.status = tr(msgBulkMarkedRead, len(ids))
+	return nil
+}
+
+func (a *App) StarMatching(query string) error {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return messageErr(msgBulkEmptyQuery)
+	}
+	a.loadAllArticles()
+	ids := []int{}
+	for _, article := range a.FilteredArticles() {
+		if article.IsStarred {
+			continue
+		}
+		for _, field := range []string{article.Title, article.ContentText, article.Author, article.FeedTitle} {
+			if strings.Contains(strings.ToLower(field), query) {
+				ids = append(ids, article.ID)
+				break
+			}
+		}
+	}
+	if err := a.store.SetArticlesStarred(ids, true); err != nil {
+		return err
+	}
+	starred := map[int]bool{}
+	for _, id := range ids {
+		starred[id] = true
+	}
+	for i := range a.articles {
+		if starred[a.articles[i].ID] {
+			a.articles[i].IsStarred = true
+		}
+	}
+	a.status = tr(msgBulkStarred, len(ids), query)
+	return nil
+}
+
+func (a *App) DeleteReadInFeed(feedID int) error {
+	feed := a.findFeed(feedID)
+	if feed == nil {
+		return messageErr(msgFeedNotFound, feedID)
+	}
+	removed, err := a.store.DeleteReadArticles(feedID)
+	if err != nil {
+		return err
+	}
+	a.lastDeleted = nil
+	a.store.CleanupOrphanSummaries()
+	a.loadArticles()
+	if count := len(a.FilteredArticles()); a.selectedIndex >= count {
+		a.selectedIndex = max(count-1, 0)
+	}
+	a.syncSummaryForSelection()
+	a.status = tr(msgBulkDeletedRead, removed, feed.Title)
+	return nil
+}
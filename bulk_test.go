@@ -0,0 +1,113 @@
+package main
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMarkAllReadFollowsScope(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	app.SetFeedScope(feedScope{FeedID: news.ID})
+	if err := app.MarkAllRead(); err != nil {
+		t.Fatalf("MarkAllRead error: %v", err)
+	}
+	if app.status != "Marked 2 articles read" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	if counts := app.store.UnreadCountByFeed(); counts[news.ID] != 0 || counts[tech.ID] != 1 {
+		t.Fatalf("expected only scoped feed read, got %+v", counts)
+	}
+	for _, article := range app.articles {
+		if article.IsRead != (article.FeedID == news.ID) {
+			t.Fatalf("in-memory state out of sync %+v", article)
+		}
+	}
+	if err := app.MarkAllRead(); err != nil || app.status != "Marked 0 articles read" {
+		t.Fatalf("expected nothing left to mark, got %v %q", err, app.status)
+	}
+}
+
+func TestStarMatching(t *testing.T) {
+	app, _, news := newFolderApp(t)
+	if err := app.StarMatching("  "); err == nil {
+		t.Fatalf("expected empty query error")
+	}
+	if err := handleCommand(app, "star-matching news", io.Discard); err != nil {
+		t.Fatalf("star-matching error: %v", err)
+	}
+	if app.status != `Starred 2 articles matching "news"` {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	for _, article := range app.store.Articles() {
+		if article.IsStarred != (article.FeedID == news.ID) {
+			t.Fatalf("unexpected star state %+v", article)
+		}
+	}
+	if err := app.StarMatching("NEWS two"); err != nil || app.status != `Starred 0 articles matching "news two"` {
+		t.Fatalf("expected already starred articles skipped, got %v %q", err, app.status)
+	}
+	if err := handleCommand(app, "star-matching", io.Discard); err == nil {
+		t.Fatalf("expected usage error")
+	}
+}
+
+func TestDeleteReadInFeed(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	if err := app.DeleteReadInFeed(99); err == nil {
+		t.Fatalf("expected missing feed error")
+	}
+	if err := handleCommand(app, "read-all", io.Discard); err != nil {
+		t.Fatalf("read-all error: %v", err)
+	}
+	app.lastDeleted = []int{1}
+	if err := handleCommand(app, "delete-read "+strconv.Itoa(news.ID), io.Discard); err != nil {
+		t.Fatalf("delete-read error: %v", err)
+	}
+	if app.status != "Deleted 2 read articles from News" || app.lastDeleted != nil {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	if len(app.articles) != 1 || app.articles[0].FeedID != tech.ID || app.selectedIndex != 0 {
+		t.Fatalf("unexpected articles %+v", app.articles)
+	}
+	if err := handleCommand(app, "delete-read", io.Discard); err == nil {
+		t.Fatalf("expected usage error")
+	}
+}
+
+func TestTUIBulkKeys(t *testing.T) {
+	app, _, news := newFolderApp(t)
+	model := newTUIModel(app)
+	model.width, model.height = 120, 30
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(tuiModel)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")})
+	if model.inputMode != inputStarMatching {
+		t.Fatalf("expected star input")
+	}
+	model.input.SetValue("tech")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(model.app.status, "Starred 1 articles") {
+		t.Fatalf("unexpected status %q", model.app.status)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if model.app.status != "Marked 3 articles read" {
+		t.Fatalf("unexpected status %q", model.app.status)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	for model.selectedFeed() != nil && model.selectedFeed().ID != news.ID {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if model.app.status != "Deleted 2 read articles from News" || !model.showFeeds {
+		t.Fatalf("unexpected status %q", model.app.status)
+	}
+	if !strings.Contains(model.View(), "x delete read") {
+		t.Fatalf("expected hint in feed screen:\n%s", model.View())
+	}
+}
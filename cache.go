@@ -0,0 +1,325 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCacheMaxMB      = 100
+	maxCachedImageBytes    = 1 << 20
+	cacheNamespaceHTTP     = "http"
+	cacheNamespaceFavicons = "favicons"
+	cacheNamespaceImages   = "images"
+)
+
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+}
+
+type httpCacheEntry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	ContentType  string `json:"content_type,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+func defaultCacheDir() string {
+	cacheDir := os.Getenv("XDG_CACHE_HOME")
+	if cacheDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		cacheDir = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(cacheDir, "greeder")
+}
+
+func NewDiskCache(cfg Config) *DiskCache {
+	dir := expandHome(cfg.CacheDir)
+	if dir == "" {
+		dir = defaultCacheDir()
+	}
+	if dir == "" || cfg.CacheMaxMB <= 0 {
+		return nil
+	}
+	return &DiskCache{dir: dir, maxBytes: int64(cfg.CacheMaxMB) << 20}
+}
+
+func (c *DiskCache) path(namespace string, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, namespace, hex.EncodeToString(sum[:]))
+}
+
+func (c *DiskCache) Get(namespace string, key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	path := c.path(namespace, key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, true
+}
+
+func (c *DiskCache) Put(namespace string, key string, data []byte) error {
+	if c == nil {
+		return nil
+	}
+	if int64(len(data)) > c.maxBytes {
+		return nil
+	}
+	path := c.path(namespace, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return c.prune()
+}
+
+type cacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (c *DiskCache) files() ([]cacheFile, error) {
+	files := []cacheFile{}
+	err := filepath.WalkDir(c.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, cacheFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+func (c *DiskCache) Size() (int64, int, error) {
+	if c == nil {
+		return 0, 0, nil
+	}
+	files, err := c.files()
+	if err != nil {
+		return 0, 0, err
+	}
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+	return total, len(files), nil
+}
+
+func (c *DiskCache) prune() error {
+	files, err := c.files()
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+	if total <= c.maxBytes {
+		return nil
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	for _, file := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(file.path); err == nil {
+			total -= file.size
+		}
+	}
+	return nil
+}
+
+func (c *DiskCache) Clear() error {
+	if c == nil {
+		return nil
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(c.dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *DiskCache) httpEntry(url string) (httpCacheEntry, bool) {
+	return c.entry(cacheNamespaceHTTP, url)
+}
+
+func (c *DiskCache) entry(namespace string, url string) (httpCacheEntry, bool) {
+	data, ok := c.Get(namespace, url)
+	if !ok {
+		return httpCacheEntry{}, false
+	}
+	var entry httpCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return httpCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *DiskCache) storeHTTP(url string, resp *http.Response, body []byte) {
+	entry := httpCacheEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		Body:         body,
+	}
+	if c == nil || (entry.ETag == "" && entry.LastModified == "") {
+		return
+	}
+	if data, err := json.Marshal(entry); err == nil {
+		_ = c.Put(cacheNamespaceHTTP, url, data)
+	}
+}
+
+// newImageFetcher returns a fetcher for favicons and images with its own
+// client, so a server goroutine can use it while the app's fetcher is
+// reconfigured. It is nil when the cache is off.
+func newImageFetcher(cfg Config) *FeedFetcher {
+	cache := NewDiskCache(cfg)
+	if cache == nil {
+		return nil
+	}
+	fetcher := NewFeedFetcher()
+	fetcher.cache = cache
+	fetcher.client.Timeout = httpTimeout(cfg, fetcher.client.Timeout)
+	configureHTTPClient(fetcher.client, cfg)
+	return fetcher
+}
+
+// Favicon returns the site's /favicon.ico from the cache, fetching it on a
+// miss.
+func (f *FeedFetcher) Favicon(siteURL string) (httpCacheEntry, error) {
+	if strings.TrimSpace(siteURL) == "" {
+		return httpCacheEntry{}, errors.New("favicon: no site url")
+	}
+	return f.cachedImage(cacheNamespaceFavicons, resolveURL(siteURL, "/favicon.ico"))
+}
+
+// Image returns an article image from the cache, fetching it on a miss.
+func (f *FeedFetcher) Image(imageURL string) (httpCacheEntry, error) {
+	return f.cachedImage(cacheNamespaceImages, imageURL)
+}
+
+func (f *FeedFetcher) cachedImage(namespace string, imageURL string) (httpCacheEntry, error) {
+	if entry, ok := f.cache.entry(namespace, imageURL); ok {
+		return entry, nil
+	}
+	req, err := http.NewRequestWithContext(f.requestContext(), http.MethodGet, imageURL, nil)
+	if err != nil {
+		return httpCacheEntry{}, err
+	}
+	req.Header.Set("Accept", "image/*")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return httpCacheEntry{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return httpCacheEntry{}, fmt.Errorf("fetch image: http %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedImageBytes+1))
+	if err != nil {
+		return httpCacheEntry{}, err
+	}
+	if len(body) > maxCachedImageBytes {
+		return httpCacheEntry{}, fmt.Errorf("fetch image: larger than %d bytes", maxCachedImageBytes)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "image/") {
+		contentType = http.DetectContentType(body)
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return httpCacheEntry{}, fmt.Errorf("fetch image: unexpected content type %q", contentType)
+	}
+	entry := httpCacheEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  contentType,
+		Body:         body,
+	}
+	if data, err := json.Marshal(entry); err == nil {
+		_ = f.cache.Put(namespace, imageURL, data)
+	}
+	return entry, nil
+}
+
+func runCacheCommand(cfg Config, args []string, stdout io.Writer) error {
+	cache := NewDiskCache(cfg)
+	if cache == nil {
+		fmt.Fprintln(stdout, tr(msgCLICacheDisabled))
+		return nil
+	}
+	if len(args) >= 1 && args[0] == "clear" {
+		if err := cache.Clear(); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, tr(msgCLICacheCleared, cache.dir))
+		return nil
+	}
+	if len(args) >= 1 {
+		return fmt.Errorf("unknown cache command: %q", args[0])
+	}
+	size, files, err := cache.Size()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s: %d files, %.1f MB of %d MB\n", cache.dir, files, float64(size)/(1<<20), cfg.CacheMaxMB)
+	return nil
+}
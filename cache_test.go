@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDiskCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/tmp/xdg-cache")
+	if cache := NewDiskCache(DefaultConfig()); cache == nil || cache.dir != "/tmp/xdg-cache/greeder" || cache.maxBytes != 100<<20 {
+		t.Fatalf("unexpected default cache %+v", cache)
+	}
+	if cache := NewDiskCache(Config{CacheDir: "/var/cache/g", CacheMaxMB: 1}); cache == nil || cache.dir != "/var/cache/g" || cache.maxBytes != 1<<20 {
+		t.Fatalf("unexpected configured cache %+v", cache)
+	}
+	if NewDiskCache(Config{CacheDir: "/var/cache/g"}) != nil {
+		t.Fatalf("expected cache_max_mb = 0 to disable the cache")
+	}
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HOME", "/home/me")
+	if got := defaultCacheDir(); got != "/home/me/.cache/greeder" {
+		t.Fatalf("unexpected home cache dir %q", got)
+	}
+	var disabled *DiskCache
+	if _, ok := disabled.Get(cacheNamespaceHTTP, "k"); ok || disabled.Put(cacheNamespaceHTTP, "k", nil) != nil || disabled.Clear() != nil {
+		t.Fatalf("expected nil cache to be a no-op")
+	}
+}
+
+func TestDiskCachePutGetPrune(t *testing.T) {
+	cache := &DiskCache{dir: t.TempDir(), maxBytes: 10}
+	if err := cache.Put("images", "a", []byte("aaaa")); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	if data, ok := cache.Get("images", "a"); !ok || string(data) != "aaaa" {
+		t.Fatalf("unexpected cached value %q %v", data, ok)
+	}
+	if _, ok := cache.Get("favicons", "a"); ok {
+		t.Fatalf("expected namespaces to be separate")
+	}
+	old := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(cache.path("images", "a"), old, old)
+	if err := cache.Put("images", "b", []byte("bbbb")); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	if err := cache.Put("images", "c", []byte("cccc")); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	if _, ok := cache.Get("images", "a"); ok {
+		t.Fatalf("expected least recently used entry pruned")
+	}
+	if _, ok := cache.Get("images", "c"); !ok {
+		t.Fatalf("expected newest entry kept")
+	}
+	if err := cache.Put("images", "huge", []byte("0123456789abc")); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	if _, ok := cache.Get("images", "huge"); ok {
+		t.Fatalf("expected entries larger than the cap to be skipped")
+	}
+	if size, files, err := cache.Size(); err != nil || size != 8 || files != 2 {
+		t.Fatalf("unexpected size %d %d %v", size, files, err)
+	}
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if size, files, err := cache.Size(); err != nil || size != 0 || files != 0 {
+		t.Fatalf("expected empty cache, got %d %d %v", size, files, err)
+	}
+	if _, err := os.Stat(cache.dir); err != nil {
+		t.Fatalf("expected cache dir kept after clear: %v", err)
+	}
+	missing := &DiskCache{dir: filepath.Join(t.TempDir(), "missing"), maxBytes: 10}
+	if err := missing.Clear(); err != nil {
+		t.Fatalf("expected clearing a missing dir to succeed: %v", err)
+	}
+}
+
+func TestFetchFeedConditionalCache(t *testing.T) {
+	requests := []*http.Request{}
+	fetcher := &FeedFetcher{cache: &DiskCache{dir: t.TempDir(), maxBytes: 1 << 20}, client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			return newResponse(http.StatusNotModified, "", nil, r), nil
+		}
+		return newResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml", "ETag": `"v1"`, "Last-Modified": "Mon, 04 Mar 2024 09:00:00 GMT"}, r), nil
+	})}}
+	first, err := fetcher.FetchFeed("http://example.test/rss")
+	if err != nil || len(first.Articles) == 0 {
+		t.Fatalf("first fetch error: %v", err)
+	}
+	second, err := fetcher.FetchFeed("http://example.test/rss")
+	if err != nil || len(second.Articles) != len(first.Articles) {
+		t.Fatalf("expected cached body on 304, got %+v %v", second, err)
+	}
+	if requests[0].Header.Get("If-None-Match") != "" || requests[1].Header.Get("If-Modified-Since") != "Mon, 04 Mar 2024 09:00:00 GMT" {
+		t.Fatalf("unexpected conditional headers %+v %+v", requests[0].Header, requests[1].Header)
+	}
+	if _, err := fetcher.FetchFeed("http://example.test/other"); err != nil {
+		t.Fatalf("uncached fetch error: %v", err)
+	}
+	fetcher.client = clientForResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"})
+	if _, err := fetcher.FetchFeed("http://example.test/plain"); err != nil {
+		t.Fatalf("fetch error: %v", err)
+	}
+	if _, ok := fetcher.cache.httpEntry("http://example.test/plain"); ok {
+		t.Fatalf("expected responses without validators not cached")
+	}
+}
+
+func TestRunCacheCommand(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.CacheDir = dir
+	cache := NewDiskCache(cfg)
+	if err := cache.Put(cacheNamespaceHTTP, "k", []byte("data")); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := runCacheCommand(cfg, nil, &out); err != nil || !strings.Contains(out.String(), dir+": 1 files") {
+		t.Fatalf("unexpected cache status %q %v", out.String(), err)
+	}
+	if err := runCacheCommand(cfg, []string{"clear"}, &out); err != nil || !strings.Contains(out.String(), "cache cleared") {
+		t.Fatalf("unexpected clear output %q %v", out.String(), err)
+	}
+	if _, ok := cache.Get(cacheNamespaceHTTP, "k"); ok {
+		t.Fatalf("expected cache cleared")
+	}
+	if err := runCacheCommand(cfg, []string{"shrink"}, &out); err == nil {
+		t.Fatalf("expected unknown command error")
+	}
+	cfg.CacheMaxMB = 0
+	out.Reset()
+	if err := runCacheCommand(cfg, []string{"clear"}, &out); err != nil || out.String() != "cache disabled\n" {
+		t.Fatalf("unexpected disabled output %q %v", out.String(), err)
+	}
+}
+
+func TestRefreshStoresFeedValidators(t *testing.T) {
+	app := newTUIApp(t)
+	if _, err := app.store.InsertFeed(Feed{Title: "Sample RSS", URL: "http://example.test/rss"}); err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	app.fetcher.cache = nil
+	requests := []*http.Request{}
+	app.fetcher.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r)
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Mon, 04 Mar 2024 09:00:00 GMT" {
+			return newResponse(http.StatusNotModified, "", nil, r), nil
+		}
+		return newResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml", "ETag": `"v1"`, "Last-Modified": "Mon, 04 Mar 2024 09:00:00 GMT"}, r), nil
+	})}
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("RefreshFeeds error: %v", err)
+	}
+	feeds := app.store.Feeds()
+	if len(feeds) != 1 || feeds[0].ETag != `"v1"` || feeds[0].LastModified != "Mon, 04 Mar 2024 09:00:00 GMT" {
+		t.Fatalf("expected validators stored, got %+v", feeds)
+	}
+	articles := len(app.store.Articles())
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("second RefreshFeeds error: %v", err)
+	}
+	if len(requests) != 2 || app.lastStats.NotModified != 1 || app.lastStats.New != 0 || len(app.store.Articles()) != articles {
+		t.Fatalf("expected 304 to skip parsing, got %+v", app.lastStats)
+	}
+}
+
+func TestFetchFeedIfModified(t *testing.T) {
+	fetcher := &FeedFetcher{client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Header.Get("If-None-Match") == `"v2"` {
+			return newResponse(http.StatusNotModified, "", nil, r), nil
+		}
+		return newResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml", "ETag": `"v2"`}, r), nil
+	})}}
+	parsed, validators, err := fetcher.FetchFeedIfModified(Feed{URL: "http://example.test/rss", ETag: `"v1"`})
+	if err != nil || len(parsed.Articles) == 0 || validators.ETag != `"v2"` || validators.LastModified != "" {
+		t.Fatalf("unexpected fetch %+v %+v %v", parsed, validators, err)
+	}
+	if _, _, err := fetcher.FetchFeedIfModified(Feed{URL: "http://example.test/rss", ETag: `"v2"`}); !errors.Is(err, errFeedNotModified) {
+		t.Fatalf("expected errFeedNotModified, got %v", err)
+	}
+}
+
+func TestFetcherCachesFaviconsAndImages(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.CacheMaxMB = 1
+	fetcher := newImageFetcher(cfg)
+	requests := []string{}
+	fetcher.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r.URL.String())
+		switch r.URL.Path {
+		case "/favicon.ico":
+			return newResponse(http.StatusOK, "\x00\x00\x01\x00icon", nil, r), nil
+		case "/big.png":
+			return newResponse(http.StatusOK, strings.Repeat("x", maxCachedImageBytes+1), map[string]string{"content-type": "image/png"}, r), nil
+		case "/page.png":
+			return newResponse(http.StatusOK, "<html></html>", map[string]string{"content-type": "text/html"}, r), nil
+		}
+		return newResponse(http.StatusOK, "png", map[string]string{"content-type": "image/png"}, r), nil
+	})}
+	for range 2 {
+		icon, err := fetcher.Favicon("http://site.test/blog/")
+		if err != nil || icon.ContentType != "image/x-icon" || string(icon.Body) != "\x00\x00\x01\x00icon" {
+			t.Fatalf("unexpected favicon %+v %v", icon, err)
+		}
+		if image, err := fetcher.Image("http://site.test/a.png"); err != nil || image.ContentType != "image/png" {
+			t.Fatalf("unexpected image %+v %v", image, err)
+		}
+	}
+	if len(requests) != 2 || requests[0] != "http://site.test/favicon.ico" {
+		t.Fatalf("expected one request per image, got %v", requests)
+	}
+	if _, err := fetcher.Image("http://site.test/big.png"); err == nil {
+		t.Fatalf("expected oversized image rejected")
+	}
+	if _, err := fetcher.Image("http://site.test/page.png"); err == nil {
+		t.Fatalf("expected non-image response rejected")
+	}
+	if _, err := fetcher.Favicon(""); err == nil {
+		t.Fatalf("expected missing site url error")
+	}
+	if _, files, err := fetcher.cache.Size(); err != nil || files != 2 {
+		t.Fatalf("expected favicon and image in the shared cache, got %d %v", files, err)
+	}
+	var out bytes.Buffer
+	if err := runCacheCommand(cfg, []string{"clear"}, &out); err != nil {
+		t.Fatalf("clear error: %v", err)
+	}
+	if _, ok := fetcher.cache.entry(cacheNamespaceFavicons, "http://site.test/favicon.ico"); ok {
+		t.Fatalf("expected cache clear to drop favicons")
+	}
+	if _, ok := fetcher.cache.entry(cacheNamespaceImages, "http://site.test/a.png"); ok {
+		t.Fatalf("expected cache clear to drop images")
+	}
+	cfg.CacheMaxMB = 0
+	if newImageFetcher(cfg) != nil {
+		t.Fatalf("expected no image fetcher with the cache off")
+	}
+}
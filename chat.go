@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+func (s *Summarizer) Ask(title string, content string, history []chatMessage, question string) (Summary, error) {
+	if s == nil {
+		return Summary{}, errors.New("summarizer not configured")
+	}
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return Summary{}, errors.New("empty question")
+	}
+	messages := []chatMessage{
+		{Role: "system", Content: guardSystemPrompt(chatSystemPrompt())},
+		{Role: "user", Content: wrapArticle(title, s.limitInput(content))},
+		{Role: "assistant", Content: "I have read the article. What would you like to know?"},
+	}
+	messages = append(messages, history...)
+	messages = append(messages, chatMessage{Role: "user", Content: question})
+	return s.chat(messages)
+}
+
+func chatSystemPrompt() string {
+	return "You answer questions about a single news article provided by the user.\n" +
+		"Base your answers on the article content. If the article does not contain the answer, say so plainly.\n" +
+		"Keep answers short and direct."
+}
+
+func (a *App) AskSelected(history []chatMessage, question string) (string, error) {
+	article := a.SelectedArticle()
+	if article == nil {
+		return "", errors.New("no article selected")
+	}
+	if a.summarizer == nil {
+		a.status = tr(msgSummarizerMissing)
+		return "", errors.New("summarizer not configured")
+	}
+	answer, err := a.summarizer.Ask(article.Title, firstNonEmpty(article.ContentText, article.Content), history, question)
+	if err != nil {
+		a.status = failureStatus(msgActionQuestion, err)
+		return "", err
+	}
+	return answer.Content, nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func chatSummarizer(t *testing.T, requests *[]chatRequest) *Summarizer {
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		var payload chatRequest
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if requests != nil {
+			*requests = append(*requests, payload)
+		}
+		answer := "Answer " + payload.Messages[len(payload.Messages)-1].Content
+		blob, _ := json.Marshal(chatResponse{Choices: []struct {
+			Message chatMessage `json:"message"`
+		}{{Message: chatMessage{Role: "assistant", Content: answer}}}})
+		return newResponse(http.StatusOK, string(blob), map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	return &Summarizer{baseURL: "http://example.test", model: "m", client: client}
+}
+
+func TestSummarizerAsk(t *testing.T) {
+	var nilSummarizer *Summarizer
+	if _, err := nilSummarizer.Ask("t", "c", nil, "q"); err == nil {
+		t.Fatalf("expected nil summarizer error")
+	}
+	var requests []chatRequest
+	s := chatSummarizer(t, &requests)
+	if _, err := s.Ask("Title", "Body", nil, "  "); err == nil {
+		t.Fatalf("expected empty question error")
+	}
+	history := []chatMessage{{Role: "user", Content: "first"}, {Role: "assistant", Content: "reply"}}
+	answer, err := s.Ask("Title", "Body", history, "second?")
+	if err != nil {
+		t.Fatalf("Ask error: %v", err)
+	}
+	if answer.Content != "Answer second?" {
+		t.Fatalf("unexpected answer: %q", answer.Content)
+	}
+	messages := requests[0].Messages
+	if len(messages) != 6 || messages[0].Role != "system" || !strings.Contains(messages[1].Content, "Body") || messages[3].Content != "first" {
+		t.Fatalf("unexpected chat messages: %+v", messages)
+	}
+}
+
+func TestAppAskSelected(t *testing.T) {
+	app := seedDigestApp(t)
+	if _, err := app.AskSelected(nil, "why?"); err == nil || app.status != tr(msgSummarizerMissing) {
+		t.Fatalf("expected no config error")
+	}
+	app.summarizer = chatSummarizer(t, nil)
+	answer, err := app.AskSelected(nil, "why?")
+	if err != nil || answer != "Answer why?" {
+		t.Fatalf("unexpected answer %q %v", answer, err)
+	}
+	if _, err := app.AskSelected(nil, ""); err == nil || !strings.HasPrefix(app.status, tr(msgActionQuestion)) {
+		t.Fatalf("expected question failure status")
+	}
+	app.articles = nil
+	if _, err := app.AskSelected(nil, "why?"); err == nil {
+		t.Fatalf("expected no article error")
+	}
+}
+
+func TestTUIChatFlow(t *testing.T) {
+	app := seedDigestApp(t)
+	model := newTUIModel(app)
+	model.width = 100
+	model.height = 30
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	model = updated.(tuiModel)
+	if model.showChat || app.status != tr(msgSummarizerMissing) {
+		t.Fatalf("expected chat to require summarizer")
+	}
+
+	app.summarizer = chatSummarizer(t, nil)
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	model = updated.(tuiModel)
+	if !model.showChat || !strings.Contains(model.View(), "Ask a question") {
+		t.Fatalf("expected chat overlay")
+	}
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	if cmd != nil {
+		t.Fatalf("expected no command for empty question")
+	}
+	for _, r := range "what?" {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(tuiModel)
+	}
+	updated, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	if cmd == nil || !model.chatPending || !strings.Contains(model.View(), "Thinking") {
+		t.Fatalf("expected pending question")
+	}
+	updated, _ = model.Update(cmd())
+	model = updated.(tuiModel)
+	if model.chatPending || len(model.chatHistory) != 2 || !strings.Contains(model.View(), "Answer what?") {
+		t.Fatalf("expected answer in conversation: %+v", model.chatHistory)
+	}
+
+	model.input.SetValue("again")
+	cmd = model.askQuestion()
+	updated, _ = model.Update(chatResultMsg{articleID: model.chatArticleID, err: errors.New("offline")})
+	model = updated.(tuiModel)
+	if cmd == nil || len(model.chatHistory) != 2 || model.input.Value() != "again" || app.status != failureStatus(msgActionQuestion, errors.New("offline")) {
+		t.Fatalf("expected failed question restored to input")
+	}
+	updated, _ = model.Update(chatResultMsg{articleID: -1, answer: "stale"})
+	model = updated.(tuiModel)
+	if len(model.chatHistory) != 2 {
+		t.Fatalf("expected stale answer ignored")
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(tuiModel)
+	if model.showChat {
+		t.Fatalf("expected chat closed")
+	}
+	model = model.openChat()
+	if len(model.chatHistory) != 2 {
+		t.Fatalf("expected history kept for same article")
+	}
+	model.chatArticleID = -1
+	model.input.SetValue("orphan")
+	if cmd := model.askQuestion(); cmd != nil || model.showChat {
+		t.Fatalf("expected chat closed when selection changed")
+	}
+}
+
+func TestHandleCommandAsk(t *testing.T) {
+	app := seedDigestApp(t)
+	var out bytes.Buffer
+	if err := handleCommand(app, "ask", &out); err == nil {
+		t.Fatalf("expected missing question error")
+	}
+	if err := handleCommand(app, "ask why", &out); err == nil || err.Error() != "summarizer not configured" {
+		t.Fatalf("expected ask without summarizer to fail, got %v", err)
+	}
+	app.summarizer = chatSummarizer(t, nil)
+	if err := handleCommand(app, "c why now", &out); err != nil {
+		t.Fatalf("ask error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Answer why now") {
+		t.Fatalf("unexpected ask output: %s", out.String())
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	nativeClipboard           = nativeClipboardWrite
+	osc52Output     io.Writer = os.Stdout
+	osc52Available            = func() bool { return isTerminalWriter(os.Stdout) }
+)
+
+func remoteSession() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+func copyOSC52(text string) error {
+	if !osc52Available() {
+		return errors.New("osc52: output is not a terminal")
+	}
+	_, err := io.WriteString(osc52Output, osc52Sequence(text, os.Getenv("TMUX") != "", strings.HasPrefix(os.Getenv("TERM"), "screen")))
+	return err
+}
+
+func osc52Sequence(text string, tmux bool, screen bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case tmux:
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case screen:
+		return "\x1bP" + seq + "\x1b\\"
+	}
+	return seq
+}
+
+func (a *App) selectedSummaryText(article *Article) string {
+	if a.current.ArticleID == article.ID {
+		return strings.TrimSpace(a.current.Content)
+	}
+	return ""
+}
+
+func articleMarkdownSnippet(article Article, summary string) string {
+	snippet := "[" + article.Title + "](" + article.URL + ")"
+	if summary = strings.TrimSpace(summary); summary != "" {
+		snippet += "\n\n" + summary
+	}
+	return snippet
+}
+
+func (a *App) copySelected(text func(*Article) string, empty messageID, copied messageID) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	value := text(article)
+	if strings.TrimSpace(value) == "" {
+		a.status = tr(empty)
+		return nil
+	}
+	if err := copyToClipboard(value); err != nil {
+		a.status = tr(msgCopyFailed, err)
+		return err
+	}
+	a.status = tr(copied)
+	return nil
+}
+
+func (a *App) CopySelectedSummary() error {
+	return a.copySelected(a.selectedSummaryText, msgCopyNoSummary, msgSummaryCopied)
+}
+
+func (a *App) CopySelectedMarkdown() error {
+	return a.copySelected(func(article *Article) string {
+		return articleMarkdownSnippet(*article, a.selectedSummaryText(article))
+	}, msgCopyNoSummary, msgMarkdownCopied)
+}
+
+func (a *App) CopySelectedContent() error {
+	return a.copySelected(func(article *Article) string {
+		return strings.Join(epubParagraphs(*article), "\n\n")
+	}, msgCopyNoContent, msgContentCopied)
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+import "errors"
+
+func nativeClipboardWrite(string) error {
+	return errors.New("no native clipboard")
+}
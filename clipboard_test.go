@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func stubClipboard(t *testing.T, native error, commands []clipboardCommand, terminal bool) (*bytes.Buffer, *[]string) {
+	t.Helper()
+	origNative := nativeClipboard
+	origOutput := osc52Output
+	origAvailable := osc52Available
+	origRun := clipboardRun
+	origCmds := clipboardCommands
+	t.Cleanup(func() {
+		nativeClipboard = origNative
+		osc52Output = origOutput
+		osc52Available = origAvailable
+		clipboardRun = origRun
+		clipboardCommands = origCmds
+	})
+	for _, name := range []string{"SSH_TTY", "SSH_CONNECTION", "TMUX", "TERM"} {
+		t.Setenv(name, "")
+	}
+	var out bytes.Buffer
+	ran := []string{}
+	nativeClipboard = func(string) error {
+		ran = append(ran, "native")
+		return native
+	}
+	osc52Output = &out
+	osc52Available = func() bool { return terminal }
+	clipboardCommands = func(string) []clipboardCommand { return commands }
+	clipboardRun = func(cmd string, args []string, input string) error {
+		ran = append(ran, cmd)
+		return errors.New(cmd + " missing")
+	}
+	return &out, &ran
+}
+
+func TestOSC52Sequence(t *testing.T) {
+	if got := osc52Sequence("hi", false, false); got != "\x1b]52;c;aGk=\a" {
+		t.Fatalf("unexpected sequence %q", got)
+	}
+	if got := osc52Sequence("hi", true, false); got != "\x1bPtmux;\x1b\x1b]52;c;aGk=\a\x1b\\" {
+		t.Fatalf("unexpected tmux sequence %q", got)
+	}
+	if got := osc52Sequence("hi", false, true); got != "\x1bP\x1b]52;c;aGk=\a\x1b\\" {
+		t.Fatalf("unexpected screen sequence %q", got)
+	}
+}
+
+func TestCopyToClipboardNative(t *testing.T) {
+	out, ran := stubClipboard(t, nil, []clipboardCommand{{name: "xclip"}}, true)
+	if err := copyToClipboard("https://example.com"); err != nil {
+		t.Fatalf("copy error: %v", err)
+	}
+	if len(*ran) != 1 || (*ran)[0] != "native" || out.Len() != 0 {
+		t.Fatalf("expected native clipboard only, ran %v", *ran)
+	}
+}
+
+func TestCopyToClipboardOSC52Fallback(t *testing.T) {
+	out, ran := stubClipboard(t, errors.New("no native clipboard"), []clipboardCommand{{name: "xclip"}, {name: "xsel"}}, true)
+	t.Setenv("TERM", "screen-256color")
+	if err := copyToClipboard("hi"); err != nil {
+		t.Fatalf("copy error: %v", err)
+	}
+	if len(*ran) != 3 || out.String() != "\x1bP\x1b]52;c;aGk=\a\x1b\\" {
+		t.Fatalf("expected commands then osc52, ran %v wrote %q", *ran, out.String())
+	}
+}
+
+func TestCopyToClipboardOverSSH(t *testing.T) {
+	out, ran := stubClipboard(t, nil, []clipboardCommand{{name: "xclip"}}, true)
+	t.Setenv("SSH_TTY", "/dev/pts/1")
+	if err := copyToClipboard("hi"); err != nil {
+		t.Fatalf("copy error: %v", err)
+	}
+	if len(*ran) != 0 || out.String() != "\x1b]52;c;aGk=\a" {
+		t.Fatalf("expected osc52 first over ssh, ran %v", *ran)
+	}
+}
+
+func TestCopyToClipboardNoTerminal(t *testing.T) {
+	out, ran := stubClipboard(t, errors.New("no native clipboard"), []clipboardCommand{{name: "xclip"}}, false)
+	t.Setenv("SSH_CONNECTION", "10.0.0.1 22 10.0.0.2 22")
+	if err := copyToClipboard("hi"); err == nil || err.Error() != "xclip missing" {
+		t.Fatalf("expected last command error, got %v", err)
+	}
+	if len(*ran) != 2 || out.Len() != 0 {
+		t.Fatalf("unexpected attempts %v", *ran)
+	}
+	_, _ = stubClipboard(t, errors.New("no native clipboard"), nil, false)
+	if err := copyToClipboard("hi"); err == nil || err.Error() != "clipboard not supported" {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+	if err := copyOSC52("hi"); err == nil {
+		t.Fatalf("expected osc52 error without terminal")
+	}
+	if err := nativeClipboardWrite("hi"); err == nil {
+		t.Fatalf("expected no native clipboard on this platform")
+	}
+}
+
+func TestAppCopySelectedText(t *testing.T) {
+	app := newTUIApp(t)
+	app.articles = []Article{{ID: 1, Title: "Post", URL: "https://example.com/1", Content: "<p>One</p><p>Two</p>"}}
+	app.filter = FilterAll
+	copied := []string{}
+	origNative := nativeClipboard
+	nativeClipboard = func(text string) error {
+		copied = append(copied, text)
+		return nil
+	}
+	t.Cleanup(func() { nativeClipboard = origNative })
+	t.Setenv("SSH_TTY", "")
+	t.Setenv("SSH_CONNECTION", "")
+
+	if err := app.CopySelectedSummary(); err != nil || app.status != "No summary to copy" || len(copied) != 0 {
+		t.Fatalf("expected nothing copied without summary: %v %q %v", err, app.status, copied)
+	}
+	app.current = Summary{ArticleID: 1, Content: "- point\n"}
+	model := newTUIModel(app)
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyCtrlY}, {Type: tea.KeyRunes, Runes: []rune("M")}, {Type: tea.KeyRunes, Runes: []rune("y"), Alt: true}} {
+		updated, _ := model.Update(key)
+		model = updated.(tuiModel)
+	}
+	want := []string{"- point", "[Post](https://example.com/1)\n\n- point", "One\n\nTwo"}
+	if strings.Join(copied, "|") != strings.Join(want, "|") || app.status != "Article text copied to clipboard" {
+		t.Fatalf("unexpected copies %q %q", copied, app.status)
+	}
+
+	var out bytes.Buffer
+	for command, status := range map[string]string{"copy summary": "Summary copied to clipboard", "copy md": "Markdown link copied to clipboard", "copy url": "URL copied to clipboard"} {
+		if err := handleCommand(app, command, &out); err != nil || app.status != status {
+			t.Fatalf("%s: %v %q", command, err, app.status)
+		}
+	}
+	if err := handleCommand(app, "copy title", &out); err == nil {
+		t.Fatalf("expected usage error")
+	}
+	if snippet := articleMarkdownSnippet(Article{Title: "T", URL: "u"}, " "); snippet != "[T](u)" {
+		t.Fatalf("unexpected bare snippet %q", snippet)
+	}
+
+	app.articles[0].Content = ""
+	if err := app.CopySelectedContent(); err != nil || app.status != "No article text to copy" {
+		t.Fatalf("expected empty content status: %v %q", err, app.status)
+	}
+	_, _ = stubClipboard(t, errors.New("no native clipboard"), nil, false)
+	if err := app.CopySelectedSummary(); err == nil || app.status != "Copy failed: clipboard not supported" {
+		t.Fatalf("expected copy failure: %v %q", err, app.status)
+	}
+	app.articles = nil
+	if err := app.CopySelectedMarkdown(); err != nil {
+		t.Fatalf("expected no-op without selection: %v", err)
+	}
+}
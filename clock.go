@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func parseTimezone(value string) (*time.Location, error) {
+	if value == "" || value == "Local" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %q", value)
+	}
+	return location, nil
+}
+
+func (s *Store) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
+
+func (a *App) now() time.Time {
+	now := time.Now()
+	if a.clock != nil {
+		now = a.clock.Now()
+	}
+	if a.location != nil {
+		now = now.In(a.location)
+	}
+	return now
+}
+
+func (a *App) after(d time.Duration) <-chan time.Time {
+	if a.clock == nil {
+		return time.After(d)
+	}
+	return a.clock.After(d)
+}
+
+func (a *App) today() time.Time {
+	return startOfDay(a.now())
+}
+
+func (a *App) setClock(clock Clock) {
+	a.clock = clock
+	a.store.clock = clock
+}
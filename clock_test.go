@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	now   time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) After(d time.Duration) <-chan time.Time {
+	if c.after == nil {
+		ch := make(chan time.Time, 1)
+		ch <- c.now.Add(d)
+		return ch
+	}
+	return c.after(d)
+}
+
+func TestParseTimezone(t *testing.T) {
+	for _, value := range []string{"", "Local"} {
+		if location, err := parseTimezone(value); err != nil || location != time.Local {
+			t.Fatalf("expected local for %q, got %v %v", value, location, err)
+		}
+	}
+	location, err := parseTimezone("Asia/Tokyo")
+	if err != nil || location.String() != "Asia/Tokyo" {
+		t.Fatalf("unexpected location %v %v", location, err)
+	}
+	if _, err := parseTimezone("Mars/Olympus"); err == nil {
+		t.Fatalf("expected invalid timezone")
+	}
+}
+
+func TestAppClockAndTimezone(t *testing.T) {
+	app := newTUIApp(t)
+	clock := &testClock{now: time.Date(2026, 10, 15, 23, 30, 0, 0, time.UTC)}
+	app.setClock(clock)
+	if !app.now().Equal(clock.now) {
+		t.Fatalf("expected injected time")
+	}
+	app.location, _ = parseTimezone("Asia/Tokyo")
+	today := app.today()
+	if today.Format("2006-01-02 15:04") != "2026-10-16 00:00" || today.Location().String() != "Asia/Tokyo" {
+		t.Fatalf("unexpected today %v", today)
+	}
+	if got := <-app.after(time.Minute); !got.Equal(clock.now.Add(time.Minute)) {
+		t.Fatalf("unexpected after %v", got)
+	}
+
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if !feed.CreatedAt.Equal(clock.now) {
+		t.Fatalf("expected store to use injected clock, got %v", feed.CreatedAt)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Old", URL: "https://example.com/1"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if !articles[0].FetchedAt.Equal(clock.now) {
+		t.Fatalf("expected fetched_at from clock, got %v", articles[0].FetchedAt)
+	}
+	clock.now = clock.now.Add(40 * 24 * time.Hour)
+	if removed := app.store.DeleteOldArticles(30); removed != 1 {
+		t.Fatalf("expected retention to use injected clock, removed %d", removed)
+	}
+}
+
+func TestClockDefaults(t *testing.T) {
+	store := &Store{}
+	if time.Since(store.now()) > time.Minute {
+		t.Fatalf("expected wall clock for store without a clock")
+	}
+	app := &App{}
+	if time.Since(app.now()) > time.Minute {
+		t.Fatalf("expected wall clock for app without a clock")
+	}
+	select {
+	case <-app.after(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatalf("expected default after to fire")
+	}
+	if time.Since(systemClock{}.Now()) > time.Minute {
+		t.Fatalf("expected system clock")
+	}
+}
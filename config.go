@@ -5,24 +5,165 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	DBPath                 string
-	RaindropToken          string
-	RefreshIntervalMinutes int
-	DefaultTags            []string
+	DBPath                   string
+	RaindropToken            string
+	PocketConsumerKey        string
+	PocketAccessToken        string
+	PinboardToken            string
+	ArchiveType              string
+	ArchiveURL               string
+	ArchiveToken             string
+	ArchiveUsername          string
+	ArchivePassword          string
+	SaveTarget               string
+	RefreshIntervalMinutes   int
+	DefaultTags              []string
+	SummaryWorkers           int
+	SummaryRetries           int
+	SummaryRequestsPerMinute int
+	PromptCostPerMillion     float64
+	CompletionCostPerMillion float64
+	LMPreset                 string
+	LMAPI                    string
+	LMBaseURL                string
+	LMModel                  string
+	LMAPIKey                 string
+	CredentialCommand        string
+	Keyring                  bool
+	LMTimeoutSeconds         int
+	LMTemperature            *float64
+	MaxInputChars            int
+	MaxInputTokens           int
+	SummaryStrategy          string
+	EmbeddingModel           string
+	SummaryLanguage          string
+	ExtractTopics            bool
+	Interests                []string
+	MutedWords               []string
+	RelevanceScoring         string
+	FeedOverrides            map[string]SummaryOptions
+	Providers                []string
+	ProviderSettings         map[string]ProviderConfig
+	Notifiers                map[string]NotifierConfig
+	DigestTime               string
+	DigestHours              int
+	DigestGroup              string
+	DigestPath               string
+	DigestEmail              bool
+	SyncBackend              string
+	SyncURL                  string
+	SyncUsername             string
+	SyncPassword             string
+	SyncToken                string
+	Accounts                 map[string]AccountConfig
+	NotesDir                 string
+	ExportFormat             string
+	ExportTemplate           string
+	ZoteroAPIKey             string
+	ZoteroUserID             string
+	Wayback                  []string
+	OmnivoreAPIKey           string
+	OmnivoreURL              string
+	RSSBridgeURL             string
+	MastodonURL              string
+	MastodonToken            string
+	MastodonIncludeSummary   bool
+	IMAPHost                 string
+	IMAPUsername             string
+	IMAPPassword             string
+	IMAPFolder               string
+	IMAPPlaintext            bool
+	FetchTimeoutSeconds      int
+	FetchConcurrency         int
+	FetchMaxRequests         int
+	FetchPerHost             int
+	FetchBandwidthKBps       int
+	HTTPProxy                string
+	HTTPUserAgent            string
+	HTTPTimeoutSeconds       int
+	RetentionDays            int
+	RetentionItems           int
+	DefaultFilter            string
+	ArticlePageSize          int
+	CacheDir                 string
+	CacheMaxMB               int
+	DedupSimilarity          float64
+	LogLevel                 string
+	LogFile                  string
+	MetricsAddr              string
+	EmailCommand             string
+	EmailTo                  string
+	EmailSubject             string
+	EmailBody                string
+	SMTPHost                 string
+	SMTPPort                 int
+	SMTPUsername             string
+	SMTPPassword             string
+	SMTPFrom                 string
+	SMTPTLS                  string
+	VaultDir                 string
+	VaultNote                string
+	VaultDailyFormat         string
+	VaultTemplate            string
+	VaultAuto                []string
+	Browser                  string
+	Timezone                 string
+	UILanguage               string
+	TTSEngine                string
+	TTSCommand               string
+	TTSVoice                 string
+	TTSAPIURL                string
+	TTSAPIKey                string
+	TTSModel                 string
+	TTSPlayer                string
+	FeverAddr                string
+	FeverUsername            string
+	FeverPassword            string
+	Messages                 map[string]string
+	Openers                  map[string]string
+	Plugins                  map[string]string
+	Hooks                    map[string]string
+	FeedIntervals            map[string]int
+	FeedRetention            map[string]RetentionPolicy
+	FeedAuth                 map[string]FeedAuth
+	Keys                     map[string][]string
+	Theme                    map[string]string
 }
 
-var saveConfig = SaveConfig
+const (
+	defaultSummaryWorkers      = 4
+	defaultFetchTimeoutSeconds = 30
+	defaultFetchConcurrency    = 5
+	defaultRetentionDays       = 7
+)
+
+var configGroups = []string{"fetcher", "summarizer", "tui", "integrations", "retention", "tts", "fever", "http", "smtp", "vault"}
+
+var (
+	saveConfig = SaveConfig
+	loadConfig = LoadConfig
+)
 
 func DefaultConfig() Config {
 	return Config{
 		DBPath:                 defaultDBPath(),
 		RefreshIntervalMinutes: 30,
 		DefaultTags:            []string{"rss"},
+		SummaryWorkers:         defaultSummaryWorkers,
+		SummaryRetries:         defaultSummaryRetries,
+		FetchTimeoutSeconds:    defaultFetchTimeoutSeconds,
+		FetchConcurrency:       defaultFetchConcurrency,
+		FetchPerHost:           defaultFetchPerHost,
+		RetentionDays:          defaultRetentionDays,
+		CacheMaxMB:             defaultCacheMaxMB,
+		LogLevel:               defaultLogLevel,
 	}
 }
 
@@ -35,6 +176,9 @@ func LoadConfig() (Config, error) {
 			if err := saveConfig(cfg); err != nil {
 				return Config{}, err
 			}
+			if err := applyEnvOverrides(&cfg, os.Environ()); err != nil {
+				return Config{}, err
+			}
 			return cfg, nil
 		}
 		return Config{}, err
@@ -42,11 +186,237 @@ func LoadConfig() (Config, error) {
 
 	cfg := DefaultConfig()
 	if err := parseConfig(string(data), &cfg); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	warnFeedAuthPermissions(path, cfg)
+	if err := applyEnvOverrides(&cfg, os.Environ()); err != nil {
 		return Config{}, err
 	}
 	return cfg, nil
 }
 
+func applyEnvOverrides(cfg *Config, environ []string) error {
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(name, "GREEDER_") || name == "GREEDER_CREDENTIAL" {
+			continue
+		}
+		key := strings.ToLower(strings.TrimPrefix(name, "GREEDER_"))
+		var err error
+		if group, groupKey := splitConfigGroupKey(key); group != "" {
+			err = parseConfigGroup(group, groupKey, value, cfg)
+		} else {
+			err = parseConfigKey(key, value, cfg)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func isConfigGroup(section string) bool {
+	for _, group := range configGroups {
+		if section == group {
+			return true
+		}
+	}
+	return false
+}
+
+func splitConfigGroupKey(key string) (string, string) {
+	for _, group := range configGroups {
+		if rest, ok := strings.CutPrefix(key, group+"_"); ok && rest != "" {
+			return group, rest
+		}
+	}
+	return "", ""
+}
+
+func parseConfigGroup(group string, key string, value string, cfg *Config) error {
+	switch group + "." + key {
+	case "fetcher.timeout_seconds":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid fetcher.timeout_seconds: %q (expected a positive integer)", value)
+		}
+		cfg.FetchTimeoutSeconds = parsed
+		return nil
+	case "fetcher.concurrency":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid fetcher.concurrency: %q (expected a positive integer)", value)
+		}
+		cfg.FetchConcurrency = parsed
+		return nil
+	case "fetcher.max_requests":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid fetcher.max_requests: %q (expected 0 or more requests)", value)
+		}
+		cfg.FetchMaxRequests = parsed
+		return nil
+	case "fetcher.per_host":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid fetcher.per_host: %q (expected 0 or more requests)", value)
+		}
+		cfg.FetchPerHost = parsed
+		return nil
+	case "fetcher.bandwidth_kbps":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid fetcher.bandwidth_kbps: %q (expected 0 or more KiB per second)", value)
+		}
+		cfg.FetchBandwidthKBps = parsed
+		return nil
+	case "tts.engine":
+		engine, err := parseTTSEngine(value)
+		if err != nil {
+			return err
+		}
+		cfg.TTSEngine = engine
+		return nil
+	case "tts.command":
+		cfg.TTSCommand = trimQuotes(value)
+		return nil
+	case "tts.voice":
+		cfg.TTSVoice = trimQuotes(value)
+		return nil
+	case "tts.api_url":
+		cfg.TTSAPIURL = trimQuotes(value)
+		return nil
+	case "tts.api_key":
+		cfg.TTSAPIKey = trimQuotes(value)
+		return nil
+	case "tts.model":
+		cfg.TTSModel = trimQuotes(value)
+		return nil
+	case "tts.player":
+		cfg.TTSPlayer = trimQuotes(value)
+		return nil
+	case "http.proxy":
+		proxy := trimQuotes(value)
+		if _, err := parseHTTPProxy(proxy); err != nil {
+			return err
+		}
+		cfg.HTTPProxy = proxy
+		return nil
+	case "http.user_agent":
+		cfg.HTTPUserAgent = trimQuotes(value)
+		return nil
+	case "http.timeout_seconds":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid http.timeout_seconds: %q (expected 0 or more seconds)", value)
+		}
+		cfg.HTTPTimeoutSeconds = parsed
+		return nil
+	case "fever.addr":
+		cfg.FeverAddr = trimQuotes(value)
+		return nil
+	case "fever.username":
+		cfg.FeverUsername = trimQuotes(value)
+		return nil
+	case "fever.password":
+		cfg.FeverPassword = trimQuotes(value)
+		return nil
+	case "smtp.host":
+		cfg.SMTPHost = trimQuotes(value)
+		return nil
+	case "smtp.port":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > 65535 {
+			return fmt.Errorf("invalid smtp.port: %q (expected a port number)", value)
+		}
+		cfg.SMTPPort = parsed
+		return nil
+	case "smtp.username":
+		cfg.SMTPUsername = trimQuotes(value)
+		return nil
+	case "smtp.password":
+		cfg.SMTPPassword = trimQuotes(value)
+		return nil
+	case "smtp.from":
+		cfg.SMTPFrom = trimQuotes(value)
+		return nil
+	case "smtp.tls":
+		mode, err := parseSMTPTLS(value)
+		if err != nil {
+			return err
+		}
+		cfg.SMTPTLS = mode
+		return nil
+	case "vault.dir":
+		cfg.VaultDir = trimQuotes(value)
+		return nil
+	case "vault.note":
+		cfg.VaultNote = trimQuotes(value)
+		return nil
+	case "vault.daily_format":
+		cfg.VaultDailyFormat = trimQuotes(value)
+		return nil
+	case "vault.template":
+		cfg.VaultTemplate = trimQuotes(value)
+		return nil
+	case "vault.auto":
+		events, err := parseStringArray(value)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if event != waybackEventBookmark && event != waybackEventStar {
+				return fmt.Errorf("invalid vault.auto event: %q (expected bookmark or star)", event)
+			}
+		}
+		cfg.VaultAuto = events
+		return nil
+	case "retention.article_days":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid retention.article_days: %q (expected 0 or more days)", value)
+		}
+		cfg.RetentionDays = parsed
+		return nil
+	case "retention.article_items":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid retention.article_items: %q (expected 0 or more articles)", value)
+		}
+		cfg.RetentionItems = parsed
+		return nil
+	case "summarizer.requests_per_minute":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid summarizer.requests_per_minute: %q (expected 0 or more requests)", value)
+		}
+		cfg.SummaryRequestsPerMinute = parsed
+		return nil
+	case "summarizer.retries":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid summarizer.retries: %q (expected 0 or more retries)", value)
+		}
+		cfg.SummaryRetries = parsed
+		return nil
+	case "tui.page_size":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid tui.page_size: %q (expected 0 or more articles)", value)
+		}
+		cfg.ArticlePageSize = parsed
+		return nil
+	case "tui.default_filter":
+		filter := trimQuotes(value)
+		if filter != "" && filter != string(FilterUnread) && filter != string(FilterStarred) && filter != string(FilterAll) {
+			return fmt.Errorf("invalid tui.default_filter: %q (expected unread, starred, or all)", filter)
+		}
+		cfg.DefaultFilter = filter
+		return nil
+	}
+	return parseConfigKey(key, value, cfg)
+}
+
 func SaveConfig(cfg Config) error {
 	path := configPath()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -56,6 +426,69 @@ func SaveConfig(cfg Config) error {
 	return os.WriteFile(path, []byte(content), 0o600)
 }
 
+// patchConfigFile sets top-level keys in the config file in place. Comments,
+// sections and every other line are kept as written, and values that only
+// came from GREEDER_* environment overrides never reach the file. Values are
+// TOML literals; an empty value removes the key.
+func patchConfigFile(values map[string]string) error {
+	path := configPath()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	lines := []string{}
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+	patched := make([]string, 0, len(lines)+len(values))
+	done := map[string]bool{}
+	insertAt := -1
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if insertAt < 0 && strings.HasPrefix(trimmed, "[") {
+			insertAt = len(patched)
+		}
+		if insertAt < 0 && !strings.HasPrefix(trimmed, "#") {
+			key, _, found := strings.Cut(trimmed, "=")
+			if value, ok := values[strings.TrimSpace(key)]; found && ok {
+				done[strings.TrimSpace(key)] = true
+				if value != "" {
+					patched = append(patched, strings.TrimSpace(key)+" = "+value)
+				}
+				continue
+			}
+		}
+		patched = append(patched, line)
+	}
+	if insertAt < 0 {
+		insertAt = len(patched)
+	}
+	for insertAt > 0 && strings.TrimSpace(patched[insertAt-1]) == "" {
+		insertAt--
+	}
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		if !done[key] && value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	added := make([]string, 0, len(keys))
+	for _, key := range keys {
+		added = append(added, key+" = "+values[key])
+	}
+	patched = append(patched[:insertAt], append(added, patched[insertAt:]...)...)
+	content := strings.Join(patched, "\n") + "\n"
+	check := DefaultConfig()
+	if err := parseConfig(content, &check); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o600)
+}
+
 func configPath() string {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
@@ -80,39 +513,574 @@ func defaultDBPath() string {
 
 func parseConfig(raw string, cfg *Config) error {
 	scanner := bufio.NewScanner(strings.NewReader(raw))
+	section := ""
+	lineNo := 0
 	for scanner.Scan() {
+		lineNo++
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
 		parts := strings.SplitN(line, "=", 2)
 		if len(parts) != 2 {
-			return fmt.Errorf("invalid config line: %q", line)
+			return fmt.Errorf("config line %d: invalid config line: %q", lineNo, line)
 		}
 		key := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
-		switch key {
-		case "db_path":
-			cfg.DBPath = trimQuotes(value)
-		case "raindrop_token":
-			cfg.RaindropToken = trimQuotes(value)
-		case "refresh_interval_minutes":
-			parsed, err := strconv.Atoi(value)
-			if err != nil {
-				return fmt.Errorf("invalid refresh_interval_minutes: %w", err)
+		var err error
+		if section != "" {
+			err = parseConfigSection(section, key, value, cfg)
+		} else {
+			err = parseConfigKey(key, value, cfg)
+		}
+		if err != nil {
+			return fmt.Errorf("config line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return validateKeyBindings(cfg.Keys)
+}
+
+func parseConfigKey(key string, value string, cfg *Config) error {
+	switch key {
+	case "db_path":
+		cfg.DBPath = trimQuotes(value)
+	case "raindrop_token":
+		cfg.RaindropToken = trimQuotes(value)
+	case "pocket_consumer_key":
+		cfg.PocketConsumerKey = trimQuotes(value)
+	case "pocket_access_token":
+		cfg.PocketAccessToken = trimQuotes(value)
+	case "pinboard_token":
+		cfg.PinboardToken = trimQuotes(value)
+	case "mastodon_url":
+		cfg.MastodonURL = trimQuotes(value)
+	case "mastodon_token":
+		cfg.MastodonToken = trimQuotes(value)
+	case "mastodon_include_summary":
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid mastodon_include_summary: %w", err)
+		}
+		cfg.MastodonIncludeSummary = parsed
+	case "imap_host":
+		cfg.IMAPHost = trimQuotes(value)
+	case "imap_username":
+		cfg.IMAPUsername = trimQuotes(value)
+	case "imap_password":
+		cfg.IMAPPassword = trimQuotes(value)
+	case "imap_folder":
+		cfg.IMAPFolder = trimQuotes(value)
+	case "imap_plaintext":
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid imap_plaintext: %w", err)
+		}
+		cfg.IMAPPlaintext = parsed
+	case "rss_bridge_url":
+		cfg.RSSBridgeURL = trimQuotes(value)
+	case "omnivore_api_key":
+		cfg.OmnivoreAPIKey = trimQuotes(value)
+	case "omnivore_url":
+		cfg.OmnivoreURL = trimQuotes(value)
+	case "archive_type":
+		kind := trimQuotes(value)
+		if kind != "" && kind != "readeck" && kind != "shiori" {
+			return fmt.Errorf("invalid archive_type: %q", kind)
+		}
+		cfg.ArchiveType = kind
+	case "archive_url":
+		cfg.ArchiveURL = trimQuotes(value)
+	case "archive_token":
+		cfg.ArchiveToken = trimQuotes(value)
+	case "archive_username":
+		cfg.ArchiveUsername = trimQuotes(value)
+	case "archive_password":
+		cfg.ArchivePassword = trimQuotes(value)
+	case "cache_dir":
+		cfg.CacheDir = trimQuotes(value)
+	case "cache_max_mb":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid cache_max_mb: %q (expected 0 or more megabytes)", value)
+		}
+		cfg.CacheMaxMB = parsed
+	case "dedup_similarity":
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || (parsed != 0 && (parsed < 0.75 || parsed > 1)) {
+			return fmt.Errorf("invalid dedup_similarity: %q (expected 0 to disable or 0.75 to 1)", value)
+		}
+		cfg.DedupSimilarity = parsed
+	case "log_level":
+		level := strings.ToLower(trimQuotes(value))
+		if _, _, err := parseLogLevel(level); err != nil {
+			return err
+		}
+		cfg.LogLevel = level
+	case "log_file":
+		cfg.LogFile = trimQuotes(value)
+	case "metrics_addr":
+		cfg.MetricsAddr = trimQuotes(value)
+	case "browser":
+		command := trimQuotes(value)
+		if command != "" {
+			if _, err := splitCommandLine(command); err != nil {
+				return fmt.Errorf("invalid browser: %w", err)
 			}
-			cfg.RefreshIntervalMinutes = parsed
-		case "default_tags":
-			items, err := parseStringArray(value)
-			if err != nil {
-				return err
+		}
+		cfg.Browser = command
+	case "timezone":
+		timezone := trimQuotes(value)
+		if _, err := parseTimezone(timezone); err != nil {
+			return err
+		}
+		cfg.Timezone = timezone
+	case "ui_language":
+		language, err := parseUILanguage(value)
+		if err != nil {
+			return err
+		}
+		cfg.UILanguage = language
+	case "email_command":
+		cfg.EmailCommand = trimQuotes(value)
+	case "email_to":
+		cfg.EmailTo = trimQuotes(value)
+	case "email_subject":
+		cfg.EmailSubject = trimQuotes(value)
+	case "email_body":
+		cfg.EmailBody = trimQuotes(value)
+	case "notes_dir":
+		cfg.NotesDir = trimQuotes(value)
+	case "export_format":
+		format, err := parseExportFormat(value)
+		if err != nil {
+			return err
+		}
+		cfg.ExportFormat = format
+	case "export_template":
+		cfg.ExportTemplate = trimQuotes(value)
+	case "wayback":
+		events, err := parseStringArray(value)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if event != waybackEventBookmark && event != waybackEventStar {
+				return fmt.Errorf("invalid wayback event: %q", event)
 			}
-			cfg.DefaultTags = items
-		default:
-			// ignore unknown keys for forward compatibility
 		}
+		cfg.Wayback = events
+	case "zotero_api_key":
+		cfg.ZoteroAPIKey = trimQuotes(value)
+	case "zotero_user_id":
+		cfg.ZoteroUserID = trimQuotes(value)
+	case "sync_backend":
+		backend := trimQuotes(value)
+		if backend != "" && backend != "nextcloud" && backend != "miniflux" && backend != "greader" {
+			return fmt.Errorf("invalid sync_backend: %q", backend)
+		}
+		cfg.SyncBackend = backend
+	case "sync_url":
+		cfg.SyncURL = trimQuotes(value)
+	case "sync_username":
+		cfg.SyncUsername = trimQuotes(value)
+	case "sync_password":
+		cfg.SyncPassword = trimQuotes(value)
+	case "sync_token":
+		cfg.SyncToken = trimQuotes(value)
+	case "save_target":
+		target := trimQuotes(value)
+		if _, ok := saveTargetNames[target]; !ok && target != "" && !strings.HasPrefix(target, pluginTargetPrefix) {
+			return fmt.Errorf("invalid save_target: %q", target)
+		}
+		cfg.SaveTarget = target
+	case "refresh_interval_minutes":
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid refresh_interval_minutes: %w", err)
+		}
+		cfg.RefreshIntervalMinutes = parsed
+	case "summary_workers":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return fmt.Errorf("invalid summary_workers: %q", value)
+		}
+		cfg.SummaryWorkers = parsed
+	case "default_tags":
+		items, err := parseStringArray(value)
+		if err != nil {
+			return err
+		}
+		cfg.DefaultTags = items
+	case "lm_preset":
+		preset := trimQuotes(value)
+		if _, ok := summarizerPresets[preset]; !ok && preset != "" {
+			return fmt.Errorf("invalid lm_preset: %q", preset)
+		}
+		cfg.LMPreset = preset
+	case "lm_api":
+		api, err := parseSummarizerAPI(value)
+		if err != nil {
+			return fmt.Errorf("invalid lm_api: %q", trimQuotes(value))
+		}
+		cfg.LMAPI = api
+	case "lm_base_url":
+		cfg.LMBaseURL = trimQuotes(value)
+	case "lm_model":
+		cfg.LMModel = trimQuotes(value)
+	case "lm_api_key":
+		cfg.LMAPIKey = trimQuotes(value)
+	case "credential_command":
+		cfg.CredentialCommand = trimQuotes(value)
+	case "keyring":
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid keyring: %w", err)
+		}
+		cfg.Keyring = parsed
+	case "lm_timeout_seconds":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid lm_timeout_seconds: %q", value)
+		}
+		cfg.LMTimeoutSeconds = parsed
+	case "lm_temperature":
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 2 {
+			return fmt.Errorf("invalid lm_temperature: %q", value)
+		}
+		cfg.LMTemperature = &parsed
+	case "max_input_chars":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid max_input_chars: %q", value)
+		}
+		cfg.MaxInputChars = parsed
+	case "max_input_tokens":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid max_input_tokens: %q", value)
+		}
+		cfg.MaxInputTokens = parsed
+	case "summary_strategy":
+		strategy, err := parseSummaryStrategy(value)
+		if err != nil {
+			return err
+		}
+		cfg.SummaryStrategy = strategy
+	case "embedding_model":
+		cfg.EmbeddingModel = trimQuotes(value)
+	case "summary_language":
+		cfg.SummaryLanguage = trimQuotes(value)
+	case "extract_topics":
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid extract_topics: %w", err)
+		}
+		cfg.ExtractTopics = parsed
+	case "interests":
+		items, err := parseStringArray(value)
+		if err != nil {
+			return err
+		}
+		cfg.Interests = items
+	case "muted_words":
+		items, err := parseStringArray(value)
+		if err != nil {
+			return err
+		}
+		cfg.MutedWords = normalizeMutedWords(items)
+	case "relevance_scoring":
+		mode := trimQuotes(value)
+		if mode != "" && mode != "heuristic" && mode != "llm" {
+			return fmt.Errorf("invalid relevance_scoring: %q", mode)
+		}
+		cfg.RelevanceScoring = mode
+	case "providers":
+		items, err := parseStringArray(value)
+		if err != nil {
+			return err
+		}
+		cfg.Providers = items
+	case "digest_time":
+		digestTime := trimQuotes(value)
+		if _, err := time.Parse("15:04", digestTime); err != nil && digestTime != "" {
+			return fmt.Errorf("invalid digest_time: %q", digestTime)
+		}
+		cfg.DigestTime = digestTime
+	case "digest_hours":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid digest_hours: %q", value)
+		}
+		cfg.DigestHours = parsed
+	case "digest_group":
+		group := trimQuotes(value)
+		if group != "" && group != digestGroupTopic && group != digestGroupFeed {
+			return fmt.Errorf("invalid digest_group: %q (expected topic or feed)", group)
+		}
+		cfg.DigestGroup = group
+	case "digest_path":
+		cfg.DigestPath = trimQuotes(value)
+	case "digest_email":
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid digest_email: %w", err)
+		}
+		cfg.DigestEmail = parsed
+	case "prompt_cost_per_million":
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid prompt_cost_per_million: %w", err)
+		}
+		cfg.PromptCostPerMillion = parsed
+	case "completion_cost_per_million":
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid completion_cost_per_million: %w", err)
+		}
+		cfg.CompletionCostPerMillion = parsed
+	default:
+		// ignore unknown keys for forward compatibility
 	}
-	return scanner.Err()
+	return nil
+}
+
+func parseConfigSection(section string, key string, value string, cfg *Config) error {
+	if isConfigGroup(section) {
+		return parseConfigGroup(section, key, value, cfg)
+	}
+	switch section {
+	case "keys":
+		return parseKeysSection(key, value, cfg)
+	case "theme":
+		return parseThemeSection(key, value, cfg)
+	case "openers":
+		return parseOpenersSection(key, value, cfg)
+	case "plugins":
+		return parsePluginsSection(key, value, cfg)
+	case "hooks":
+		return parseHooksSection(key, value, cfg)
+	case "messages":
+		return parseMessagesSection(key, value, cfg)
+	case "refresh_intervals":
+		return parseRefreshIntervalsSection(key, value, cfg)
+	}
+	if strings.HasPrefix(section, "provider.") {
+		return parseProviderSection(trimQuotes(strings.TrimPrefix(section, "provider.")), key, value, cfg)
+	}
+	if strings.HasPrefix(section, "account.") {
+		return parseAccountSection(trimQuotes(strings.TrimPrefix(section, "account.")), key, value, cfg)
+	}
+	if strings.HasPrefix(section, "notify.") {
+		return parseNotifySection(trimQuotes(strings.TrimPrefix(section, "notify.")), key, value, cfg)
+	}
+	if !strings.HasPrefix(section, "feeds.") {
+		// ignore unknown sections for forward compatibility
+		return nil
+	}
+	feedURL := trimQuotes(strings.TrimPrefix(section, "feeds."))
+	if feedURL == "" {
+		return fmt.Errorf("invalid config section: %q", section)
+	}
+	if cfg.FeedOverrides == nil {
+		cfg.FeedOverrides = map[string]SummaryOptions{}
+	}
+	override := cfg.FeedOverrides[feedURL]
+	switch key {
+	case "model":
+		override.Model = trimQuotes(value)
+	case "prompt":
+		override.Prompt = trimQuotes(value)
+	case "style":
+		style := trimQuotes(value)
+		if _, ok := summaryStyles[style]; !ok && style != "" {
+			return fmt.Errorf("invalid style for %s: %q", feedURL, style)
+		}
+		override.Style = style
+	case "profile":
+		override.Profile = trimQuotes(value)
+	case "retention_days", "retention_items":
+		return parseFeedRetention(feedURL, key, value, cfg)
+	case "username", "password", "header_name", "header_value":
+		return parseFeedAuth(feedURL, key, value, cfg)
+	}
+	cfg.FeedOverrides[feedURL] = override
+	return nil
+}
+
+func parseFeedRetention(feedURL string, key string, value string, cfg *Config) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return fmt.Errorf("invalid %s for %s: %q (expected 0 or more)", key, feedURL, value)
+	}
+	if cfg.FeedRetention == nil {
+		cfg.FeedRetention = map[string]RetentionPolicy{}
+	}
+	policy, ok := cfg.FeedRetention[feedURL]
+	if !ok {
+		policy = RetentionPolicy{Days: -1, Items: -1}
+	}
+	if key == "retention_days" {
+		policy.Days = parsed
+	} else {
+		policy.Items = parsed
+	}
+	cfg.FeedRetention[feedURL] = policy
+	return nil
+}
+
+func parseFeedAuth(feedURL string, key string, value string, cfg *Config) error {
+	if cfg.FeedAuth == nil {
+		cfg.FeedAuth = map[string]FeedAuth{}
+	}
+	auth := cfg.FeedAuth[feedURL]
+	value = trimQuotes(value)
+	switch key {
+	case "username":
+		auth.Username = value
+	case "password":
+		auth.Password = value
+	case "header_name":
+		if strings.ContainsAny(value, " :") {
+			return fmt.Errorf("invalid header_name for %s: %q", feedURL, value)
+		}
+		auth.HeaderName = value
+	case "header_value":
+		auth.HeaderValue = value
+	}
+	cfg.FeedAuth[feedURL] = auth
+	return nil
+}
+
+func parseProviderSection(name string, key string, value string, cfg *Config) error {
+	if name == "" {
+		return fmt.Errorf("invalid provider section: %q", name)
+	}
+	if cfg.ProviderSettings == nil {
+		cfg.ProviderSettings = map[string]ProviderConfig{}
+	}
+	provider := cfg.ProviderSettings[name]
+	provider.Name = name
+	switch key {
+	case "preset":
+		preset := trimQuotes(value)
+		if _, ok := summarizerPresets[preset]; !ok && preset != "" {
+			return fmt.Errorf("invalid preset for provider %s: %q", name, preset)
+		}
+		provider.Preset = preset
+	case "api":
+		api, err := parseSummarizerAPI(value)
+		if err != nil {
+			return fmt.Errorf("invalid api for provider %s: %q", name, trimQuotes(value))
+		}
+		provider.API = api
+	case "base_url":
+		provider.BaseURL = trimQuotes(value)
+	case "model":
+		provider.Model = trimQuotes(value)
+	case "api_key":
+		provider.APIKey = trimQuotes(value)
+	case "timeout_seconds":
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid timeout_seconds for provider %s: %q", name, value)
+		}
+		provider.TimeoutSeconds = parsed
+	case "temperature":
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 2 {
+			return fmt.Errorf("invalid temperature for provider %s: %q", name, value)
+		}
+		provider.Temperature = &parsed
+	}
+	cfg.ProviderSettings[name] = provider
+	return nil
+}
+
+func parseAccountSection(name string, key string, value string, cfg *Config) error {
+	if name == "" {
+		return fmt.Errorf("invalid account section: %q", name)
+	}
+	if cfg.Accounts == nil {
+		cfg.Accounts = map[string]AccountConfig{}
+	}
+	account := cfg.Accounts[name]
+	account.Name = name
+	switch key {
+	case "backend":
+		backend := trimQuotes(value)
+		if backend != "nextcloud" && backend != "miniflux" && backend != "greader" {
+			return fmt.Errorf("invalid backend for account %s: %q", name, backend)
+		}
+		account.Backend = backend
+	case "url":
+		account.URL = trimQuotes(value)
+	case "username":
+		account.Username = trimQuotes(value)
+	case "password":
+		account.Password = trimQuotes(value)
+	case "token":
+		account.Token = trimQuotes(value)
+	}
+	cfg.Accounts[name] = account
+	return nil
+}
+
+func parseNotifySection(name string, key string, value string, cfg *Config) error {
+	if name == "" {
+		return fmt.Errorf("invalid notify section: %q", name)
+	}
+	if cfg.Notifiers == nil {
+		cfg.Notifiers = map[string]NotifierConfig{}
+	}
+	notifier := cfg.Notifiers[name]
+	notifier.Name = name
+	switch key {
+	case "type":
+		kind := trimQuotes(value)
+		if kind != "slack" && kind != "discord" && kind != "matrix" && kind != "telegram" {
+			return fmt.Errorf("invalid type for notifier %s: %q", name, kind)
+		}
+		notifier.Type = kind
+	case "webhook_url":
+		notifier.WebhookURL = trimQuotes(value)
+	case "homeserver":
+		notifier.Homeserver = trimQuotes(value)
+	case "room_id":
+		notifier.RoomID = trimQuotes(value)
+	case "access_token":
+		notifier.AccessToken = trimQuotes(value)
+	case "bot_token":
+		notifier.BotToken = trimQuotes(value)
+	case "chat_id":
+		notifier.ChatID = trimQuotes(value)
+	case "feeds":
+		items, err := parseStringArray(value)
+		if err != nil {
+			return err
+		}
+		notifier.Feeds = items
+	case "events":
+		items, err := parseStringArray(value)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item != notifyEventArticles && item != notifyEventDigest {
+				return fmt.Errorf("invalid event for notifier %s: %q", name, item)
+			}
+		}
+		notifier.Events = items
+	}
+	cfg.Notifiers[name] = notifier
+	return nil
 }
 
 func trimQuotes(value string) string {
@@ -151,9 +1119,564 @@ func renderConfig(cfg Config) string {
 		"refresh_interval_minutes = " + strconv.Itoa(cfg.RefreshIntervalMinutes),
 		"default_tags = " + renderStringArray(cfg.DefaultTags),
 	}
+	if cfg.SummaryWorkers != defaultSummaryWorkers && cfg.SummaryWorkers > 0 {
+		lines = append(lines, "summary_workers = "+strconv.Itoa(cfg.SummaryWorkers))
+	}
 	if cfg.RaindropToken != "" {
 		lines = append(lines, "raindrop_token = \""+cfg.RaindropToken+"\"")
 	}
+	if cfg.PocketConsumerKey != "" {
+		lines = append(lines, "pocket_consumer_key = "+strconv.Quote(cfg.PocketConsumerKey))
+	}
+	if cfg.PocketAccessToken != "" {
+		lines = append(lines, "pocket_access_token = "+strconv.Quote(cfg.PocketAccessToken))
+	}
+	if cfg.PinboardToken != "" {
+		lines = append(lines, "pinboard_token = "+strconv.Quote(cfg.PinboardToken))
+	}
+	if cfg.MastodonURL != "" {
+		lines = append(lines, "mastodon_url = "+strconv.Quote(cfg.MastodonURL))
+	}
+	if cfg.MastodonToken != "" {
+		lines = append(lines, "mastodon_token = "+strconv.Quote(cfg.MastodonToken))
+	}
+	if cfg.MastodonIncludeSummary {
+		lines = append(lines, "mastodon_include_summary = true")
+	}
+	if cfg.IMAPHost != "" {
+		lines = append(lines, "imap_host = "+strconv.Quote(cfg.IMAPHost))
+	}
+	if cfg.IMAPUsername != "" {
+		lines = append(lines, "imap_username = "+strconv.Quote(cfg.IMAPUsername))
+	}
+	if cfg.IMAPPassword != "" {
+		lines = append(lines, "imap_password = "+strconv.Quote(cfg.IMAPPassword))
+	}
+	if cfg.IMAPFolder != "" {
+		lines = append(lines, "imap_folder = "+strconv.Quote(cfg.IMAPFolder))
+	}
+	if cfg.IMAPPlaintext {
+		lines = append(lines, "imap_plaintext = true")
+	}
+	if cfg.RSSBridgeURL != "" {
+		lines = append(lines, "rss_bridge_url = "+strconv.Quote(cfg.RSSBridgeURL))
+	}
+	if cfg.OmnivoreAPIKey != "" {
+		lines = append(lines, "omnivore_api_key = "+strconv.Quote(cfg.OmnivoreAPIKey))
+	}
+	if cfg.OmnivoreURL != "" {
+		lines = append(lines, "omnivore_url = "+strconv.Quote(cfg.OmnivoreURL))
+	}
+	if cfg.ArchiveType != "" {
+		lines = append(lines, "archive_type = "+strconv.Quote(cfg.ArchiveType))
+	}
+	if cfg.ArchiveURL != "" {
+		lines = append(lines, "archive_url = "+strconv.Quote(cfg.ArchiveURL))
+	}
+	if cfg.ArchiveToken != "" {
+		lines = append(lines, "archive_token = "+strconv.Quote(cfg.ArchiveToken))
+	}
+	if cfg.ArchiveUsername != "" {
+		lines = append(lines, "archive_username = "+strconv.Quote(cfg.ArchiveUsername))
+	}
+	if cfg.ArchivePassword != "" {
+		lines = append(lines, "archive_password = "+strconv.Quote(cfg.ArchivePassword))
+	}
+	if cfg.SaveTarget != "" {
+		lines = append(lines, "save_target = "+strconv.Quote(cfg.SaveTarget))
+	}
+	if cfg.CacheDir != "" {
+		lines = append(lines, "cache_dir = "+strconv.Quote(cfg.CacheDir))
+	}
+	if cfg.CacheMaxMB != defaultCacheMaxMB {
+		lines = append(lines, "cache_max_mb = "+strconv.Itoa(cfg.CacheMaxMB))
+	}
+	if cfg.DedupSimilarity != 0 {
+		lines = append(lines, "dedup_similarity = "+strconv.FormatFloat(cfg.DedupSimilarity, 'f', -1, 64))
+	}
+	if cfg.LogLevel != defaultLogLevel {
+		lines = append(lines, "log_level = "+strconv.Quote(cfg.LogLevel))
+	}
+	if cfg.LogFile != "" {
+		lines = append(lines, "log_file = "+strconv.Quote(cfg.LogFile))
+	}
+	if cfg.MetricsAddr != "" {
+		lines = append(lines, "metrics_addr = "+strconv.Quote(cfg.MetricsAddr))
+	}
+	if cfg.Browser != "" {
+		lines = append(lines, "browser = "+strconv.Quote(cfg.Browser))
+	}
+	if cfg.Timezone != "" {
+		lines = append(lines, "timezone = "+strconv.Quote(cfg.Timezone))
+	}
+	if cfg.UILanguage != "" && cfg.UILanguage != defaultUILanguage {
+		lines = append(lines, "ui_language = "+strconv.Quote(cfg.UILanguage))
+	}
+	if cfg.EmailCommand != "" {
+		lines = append(lines, "email_command = "+strconv.Quote(cfg.EmailCommand))
+	}
+	if cfg.EmailTo != "" {
+		lines = append(lines, "email_to = "+strconv.Quote(cfg.EmailTo))
+	}
+	if cfg.EmailSubject != "" {
+		lines = append(lines, "email_subject = "+strconv.Quote(cfg.EmailSubject))
+	}
+	if cfg.EmailBody != "" {
+		lines = append(lines, "email_body = "+strconv.Quote(cfg.EmailBody))
+	}
+	if cfg.NotesDir != "" {
+		lines = append(lines, "notes_dir = "+strconv.Quote(cfg.NotesDir))
+	}
+	if cfg.ExportFormat != "" {
+		lines = append(lines, "export_format = "+strconv.Quote(cfg.ExportFormat))
+	}
+	if cfg.ExportTemplate != "" {
+		lines = append(lines, "export_template = "+strconv.Quote(cfg.ExportTemplate))
+	}
+	if len(cfg.Wayback) > 0 {
+		lines = append(lines, "wayback = "+renderStringArray(cfg.Wayback))
+	}
+	if cfg.ZoteroAPIKey != "" {
+		lines = append(lines, "zotero_api_key = "+strconv.Quote(cfg.ZoteroAPIKey))
+	}
+	if cfg.ZoteroUserID != "" {
+		lines = append(lines, "zotero_user_id = "+strconv.Quote(cfg.ZoteroUserID))
+	}
+	if cfg.SyncBackend != "" {
+		lines = append(lines, "sync_backend = "+strconv.Quote(cfg.SyncBackend))
+	}
+	if cfg.SyncURL != "" {
+		lines = append(lines, "sync_url = "+strconv.Quote(cfg.SyncURL))
+	}
+	if cfg.SyncUsername != "" {
+		lines = append(lines, "sync_username = "+strconv.Quote(cfg.SyncUsername))
+	}
+	if cfg.SyncPassword != "" {
+		lines = append(lines, "sync_password = "+strconv.Quote(cfg.SyncPassword))
+	}
+	if cfg.SyncToken != "" {
+		lines = append(lines, "sync_token = "+strconv.Quote(cfg.SyncToken))
+	}
+	if cfg.LMPreset != "" {
+		lines = append(lines, "lm_preset = \""+cfg.LMPreset+"\"")
+	}
+	if cfg.LMAPI != "" {
+		lines = append(lines, "lm_api = "+strconv.Quote(cfg.LMAPI))
+	}
+	if cfg.LMBaseURL != "" {
+		lines = append(lines, "lm_base_url = \""+cfg.LMBaseURL+"\"")
+	}
+	if cfg.LMModel != "" {
+		lines = append(lines, "lm_model = \""+cfg.LMModel+"\"")
+	}
+	if cfg.LMAPIKey != "" {
+		lines = append(lines, "lm_api_key = \""+cfg.LMAPIKey+"\"")
+	}
+	if cfg.CredentialCommand != "" {
+		lines = append(lines, "credential_command = "+strconv.Quote(cfg.CredentialCommand))
+	}
+	if cfg.Keyring {
+		lines = append(lines, "keyring = true")
+	}
+	if cfg.LMTimeoutSeconds != 0 {
+		lines = append(lines, "lm_timeout_seconds = "+strconv.Itoa(cfg.LMTimeoutSeconds))
+	}
+	if cfg.LMTemperature != nil {
+		lines = append(lines, "lm_temperature = "+strconv.FormatFloat(*cfg.LMTemperature, 'g', -1, 64))
+	}
+	if cfg.MaxInputChars != 0 {
+		lines = append(lines, "max_input_chars = "+strconv.Itoa(cfg.MaxInputChars))
+	}
+	if cfg.MaxInputTokens != 0 {
+		lines = append(lines, "max_input_tokens = "+strconv.Itoa(cfg.MaxInputTokens))
+	}
+	if cfg.SummaryStrategy != "" {
+		lines = append(lines, "summary_strategy = "+strconv.Quote(cfg.SummaryStrategy))
+	}
+	if cfg.EmbeddingModel != "" {
+		lines = append(lines, "embedding_model = \""+cfg.EmbeddingModel+"\"")
+	}
+	if cfg.SummaryLanguage != "" {
+		lines = append(lines, "summary_language = \""+cfg.SummaryLanguage+"\"")
+	}
+	if cfg.ExtractTopics {
+		lines = append(lines, "extract_topics = true")
+	}
+	if len(cfg.Interests) > 0 {
+		lines = append(lines, "interests = "+renderStringArray(cfg.Interests))
+	}
+	if len(cfg.MutedWords) > 0 {
+		lines = append(lines, "muted_words = "+renderStringArray(cfg.MutedWords))
+	}
+	if cfg.RelevanceScoring != "" {
+		lines = append(lines, "relevance_scoring = \""+cfg.RelevanceScoring+"\"")
+	}
+	if len(cfg.Providers) > 0 {
+		lines = append(lines, "providers = "+renderStringArray(cfg.Providers))
+	}
+	if cfg.DigestTime != "" {
+		lines = append(lines, "digest_time = "+strconv.Quote(cfg.DigestTime))
+	}
+	if cfg.DigestHours != 0 {
+		lines = append(lines, "digest_hours = "+strconv.Itoa(cfg.DigestHours))
+	}
+	if cfg.DigestGroup != "" {
+		lines = append(lines, "digest_group = "+strconv.Quote(cfg.DigestGroup))
+	}
+	if cfg.DigestPath != "" {
+		lines = append(lines, "digest_path = "+strconv.Quote(cfg.DigestPath))
+	}
+	if cfg.DigestEmail {
+		lines = append(lines, "digest_email = true")
+	}
+	if cfg.PromptCostPerMillion != 0 {
+		lines = append(lines, "prompt_cost_per_million = "+strconv.FormatFloat(cfg.PromptCostPerMillion, 'f', -1, 64))
+	}
+	if cfg.CompletionCostPerMillion != 0 {
+		lines = append(lines, "completion_cost_per_million = "+strconv.FormatFloat(cfg.CompletionCostPerMillion, 'f', -1, 64))
+	}
+	fetcher := []string{}
+	if cfg.FetchTimeoutSeconds != defaultFetchTimeoutSeconds && cfg.FetchTimeoutSeconds > 0 {
+		fetcher = append(fetcher, "timeout_seconds = "+strconv.Itoa(cfg.FetchTimeoutSeconds))
+	}
+	if cfg.FetchConcurrency != defaultFetchConcurrency && cfg.FetchConcurrency > 0 {
+		fetcher = append(fetcher, "concurrency = "+strconv.Itoa(cfg.FetchConcurrency))
+	}
+	if cfg.FetchMaxRequests > 0 {
+		fetcher = append(fetcher, "max_requests = "+strconv.Itoa(cfg.FetchMaxRequests))
+	}
+	if cfg.FetchPerHost != defaultFetchPerHost && cfg.FetchPerHost >= 0 {
+		fetcher = append(fetcher, "per_host = "+strconv.Itoa(cfg.FetchPerHost))
+	}
+	if cfg.FetchBandwidthKBps > 0 {
+		fetcher = append(fetcher, "bandwidth_kbps = "+strconv.Itoa(cfg.FetchBandwidthKBps))
+	}
+	if len(fetcher) > 0 {
+		lines = append(append(lines, "", "[fetcher]"), fetcher...)
+	}
+	summarizer := []string{}
+	if cfg.SummaryRequestsPerMinute > 0 {
+		summarizer = append(summarizer, "requests_per_minute = "+strconv.Itoa(cfg.SummaryRequestsPerMinute))
+	}
+	if cfg.SummaryRetries != defaultSummaryRetries && cfg.SummaryRetries >= 0 {
+		summarizer = append(summarizer, "retries = "+strconv.Itoa(cfg.SummaryRetries))
+	}
+	if len(summarizer) > 0 {
+		lines = append(append(lines, "", "[summarizer]"), summarizer...)
+	}
+	tui := []string{}
+	if cfg.DefaultFilter != "" {
+		tui = append(tui, "default_filter = "+strconv.Quote(cfg.DefaultFilter))
+	}
+	if cfg.ArticlePageSize > 0 {
+		tui = append(tui, "page_size = "+strconv.Itoa(cfg.ArticlePageSize))
+	}
+	if len(tui) > 0 {
+		lines = append(append(lines, "", "[tui]"), tui...)
+	}
+	tts := []string{}
+	for _, setting := range [][2]string{
+		{"engine", cfg.TTSEngine},
+		{"command", cfg.TTSCommand},
+		{"voice", cfg.TTSVoice},
+		{"api_url", cfg.TTSAPIURL},
+		{"api_key", cfg.TTSAPIKey},
+		{"model", cfg.TTSModel},
+		{"player", cfg.TTSPlayer},
+	} {
+		if setting[1] != "" {
+			tts = append(tts, setting[0]+" = "+strconv.Quote(setting[1]))
+		}
+	}
+	if len(tts) > 0 {
+		lines = append(append(lines, "", "[tts]"), tts...)
+	}
+	fever := []string{}
+	for _, setting := range [][2]string{
+		{"addr", cfg.FeverAddr},
+		{"username", cfg.FeverUsername},
+		{"password", cfg.FeverPassword},
+	} {
+		if setting[1] != "" {
+			fever = append(fever, setting[0]+" = "+strconv.Quote(setting[1]))
+		}
+	}
+	if len(fever) > 0 {
+		lines = append(append(lines, "", "[fever]"), fever...)
+	}
+	httpSection := []string{}
+	if cfg.HTTPProxy != "" {
+		httpSection = append(httpSection, "proxy = "+strconv.Quote(cfg.HTTPProxy))
+	}
+	if cfg.HTTPUserAgent != "" {
+		httpSection = append(httpSection, "user_agent = "+strconv.Quote(cfg.HTTPUserAgent))
+	}
+	if cfg.HTTPTimeoutSeconds > 0 {
+		httpSection = append(httpSection, "timeout_seconds = "+strconv.Itoa(cfg.HTTPTimeoutSeconds))
+	}
+	if len(httpSection) > 0 {
+		lines = append(append(lines, "", "[http]"), httpSection...)
+	}
+	smtpSection := []string{}
+	for _, setting := range [][2]string{
+		{"host", cfg.SMTPHost},
+		{"username", cfg.SMTPUsername},
+		{"password", cfg.SMTPPassword},
+		{"from", cfg.SMTPFrom},
+		{"tls", cfg.SMTPTLS},
+	} {
+		if setting[1] != "" {
+			smtpSection = append(smtpSection, setting[0]+" = "+strconv.Quote(setting[1]))
+		}
+	}
+	if cfg.SMTPPort != 0 {
+		smtpSection = append(smtpSection, "port = "+strconv.Itoa(cfg.SMTPPort))
+	}
+	if len(smtpSection) > 0 {
+		lines = append(append(lines, "", "[smtp]"), smtpSection...)
+	}
+	vaultSection := []string{}
+	for _, setting := range [][2]string{
+		{"dir", cfg.VaultDir},
+		{"note", cfg.VaultNote},
+		{"daily_format", cfg.VaultDailyFormat},
+		{"template", cfg.VaultTemplate},
+	} {
+		if setting[1] != "" {
+			vaultSection = append(vaultSection, setting[0]+" = "+strconv.Quote(setting[1]))
+		}
+	}
+	if len(cfg.VaultAuto) > 0 {
+		vaultSection = append(vaultSection, "auto = "+renderStringArray(cfg.VaultAuto))
+	}
+	if len(vaultSection) > 0 {
+		lines = append(append(lines, "", "[vault]"), vaultSection...)
+	}
+	retention := []string{}
+	if cfg.RetentionDays != defaultRetentionDays {
+		retention = append(retention, "article_days = "+strconv.Itoa(cfg.RetentionDays))
+	}
+	if cfg.RetentionItems != 0 {
+		retention = append(retention, "article_items = "+strconv.Itoa(cfg.RetentionItems))
+	}
+	if len(retention) > 0 {
+		lines = append(append(lines, "", "[retention]"), retention...)
+	}
+	if len(cfg.Keys) > 0 {
+		lines = append(lines, "", "[keys]")
+		actions := make([]string, 0, len(cfg.Keys))
+		for action := range cfg.Keys {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+		for _, action := range actions {
+			if keys := cfg.Keys[action]; len(keys) == 1 {
+				lines = append(lines, action+" = "+strconv.Quote(keys[0]))
+			} else {
+				lines = append(lines, action+" = "+renderStringArray(keys))
+			}
+		}
+	}
+	if len(cfg.Theme) > 0 {
+		lines = append(lines, "", "[theme]")
+		names := make([]string, 0, len(cfg.Theme))
+		for name := range cfg.Theme {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			lines = append(lines, name+" = "+strconv.Quote(cfg.Theme[name]))
+		}
+	}
+	if len(cfg.Openers) > 0 {
+		lines = append(lines, "", "[openers]")
+		patterns := make([]string, 0, len(cfg.Openers))
+		for pattern := range cfg.Openers {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			lines = append(lines, strconv.Quote(pattern)+" = "+strconv.Quote(cfg.Openers[pattern]))
+		}
+	}
+	if len(cfg.Plugins) > 0 {
+		lines = append(lines, "", "[plugins]")
+		for _, name := range pluginNames(cfg) {
+			lines = append(lines, strconv.Quote(name)+" = "+strconv.Quote(cfg.Plugins[name]))
+		}
+	}
+	if len(cfg.Hooks) > 0 {
+		lines = append(lines, "", "[hooks]")
+		for _, event := range hookEvents {
+			if command, ok := cfg.Hooks[event]; ok {
+				lines = append(lines, event+" = "+strconv.Quote(command))
+			}
+		}
+	}
+	if len(cfg.FeedIntervals) > 0 {
+		lines = append(lines, "", "[refresh_intervals]")
+		urls := make([]string, 0, len(cfg.FeedIntervals))
+		for url := range cfg.FeedIntervals {
+			urls = append(urls, url)
+		}
+		sort.Strings(urls)
+		for _, url := range urls {
+			lines = append(lines, strconv.Quote(url)+" = "+strconv.Itoa(cfg.FeedIntervals[url]))
+		}
+	}
+	if len(cfg.Messages) > 0 {
+		lines = append(lines, "", "[messages]")
+		ids := make([]string, 0, len(cfg.Messages))
+		for id := range cfg.Messages {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			lines = append(lines, strconv.Quote(id)+" = "+strconv.Quote(cfg.Messages[id]))
+		}
+	}
+	providerNames := make([]string, 0, len(cfg.ProviderSettings))
+	for name := range cfg.ProviderSettings {
+		providerNames = append(providerNames, name)
+	}
+	sort.Strings(providerNames)
+	for _, name := range providerNames {
+		provider := cfg.ProviderSettings[name]
+		lines = append(lines, "", "[provider."+name+"]")
+		if provider.Preset != "" {
+			lines = append(lines, "preset = "+strconv.Quote(provider.Preset))
+		}
+		if provider.API != "" {
+			lines = append(lines, "api = "+strconv.Quote(provider.API))
+		}
+		if provider.BaseURL != "" {
+			lines = append(lines, "base_url = "+strconv.Quote(provider.BaseURL))
+		}
+		if provider.Model != "" {
+			lines = append(lines, "model = "+strconv.Quote(provider.Model))
+		}
+		if provider.APIKey != "" {
+			lines = append(lines, "api_key = "+strconv.Quote(provider.APIKey))
+		}
+		if provider.TimeoutSeconds != 0 {
+			lines = append(lines, "timeout_seconds = "+strconv.Itoa(provider.TimeoutSeconds))
+		}
+		if provider.Temperature != nil {
+			lines = append(lines, "temperature = "+strconv.FormatFloat(*provider.Temperature, 'g', -1, 64))
+		}
+	}
+	accountNames := make([]string, 0, len(cfg.Accounts))
+	for name := range cfg.Accounts {
+		accountNames = append(accountNames, name)
+	}
+	sort.Strings(accountNames)
+	for _, name := range accountNames {
+		account := cfg.Accounts[name]
+		lines = append(lines, "", "[account."+name+"]")
+		if account.Backend != "" {
+			lines = append(lines, "backend = "+strconv.Quote(account.Backend))
+		}
+		if account.URL != "" {
+			lines = append(lines, "url = "+strconv.Quote(account.URL))
+		}
+		if account.Username != "" {
+			lines = append(lines, "username = "+strconv.Quote(account.Username))
+		}
+		if account.Password != "" {
+			lines = append(lines, "password = "+strconv.Quote(account.Password))
+		}
+		if account.Token != "" {
+			lines = append(lines, "token = "+strconv.Quote(account.Token))
+		}
+	}
+	notifierNames := make([]string, 0, len(cfg.Notifiers))
+	for name := range cfg.Notifiers {
+		notifierNames = append(notifierNames, name)
+	}
+	sort.Strings(notifierNames)
+	for _, name := range notifierNames {
+		notifier := cfg.Notifiers[name]
+		lines = append(lines, "", "[notify."+name+"]")
+		if notifier.Type != "" {
+			lines = append(lines, "type = "+strconv.Quote(notifier.Type))
+		}
+		if notifier.WebhookURL != "" {
+			lines = append(lines, "webhook_url = "+strconv.Quote(notifier.WebhookURL))
+		}
+		if notifier.Homeserver != "" {
+			lines = append(lines, "homeserver = "+strconv.Quote(notifier.Homeserver))
+		}
+		if notifier.RoomID != "" {
+			lines = append(lines, "room_id = "+strconv.Quote(notifier.RoomID))
+		}
+		if notifier.AccessToken != "" {
+			lines = append(lines, "access_token = "+strconv.Quote(notifier.AccessToken))
+		}
+		if notifier.BotToken != "" {
+			lines = append(lines, "bot_token = "+strconv.Quote(notifier.BotToken))
+		}
+		if notifier.ChatID != "" {
+			lines = append(lines, "chat_id = "+strconv.Quote(notifier.ChatID))
+		}
+		if len(notifier.Feeds) > 0 {
+			lines = append(lines, "feeds = "+renderStringArray(notifier.Feeds))
+		}
+		if len(notifier.Events) > 0 {
+			lines = append(lines, "events = "+renderStringArray(notifier.Events))
+		}
+	}
+	feedURLs := make([]string, 0, len(cfg.FeedOverrides))
+	for feedURL := range cfg.FeedOverrides {
+		feedURLs = append(feedURLs, feedURL)
+	}
+	for feedURL := range cfg.FeedRetention {
+		if _, ok := cfg.FeedOverrides[feedURL]; !ok {
+			feedURLs = append(feedURLs, feedURL)
+		}
+	}
+	for feedURL := range cfg.FeedAuth {
+		_, override := cfg.FeedOverrides[feedURL]
+		_, retention := cfg.FeedRetention[feedURL]
+		if !override && !retention {
+			feedURLs = append(feedURLs, feedURL)
+		}
+	}
+	sort.Strings(feedURLs)
+	for _, feedURL := range feedURLs {
+		override := cfg.FeedOverrides[feedURL]
+		lines = append(lines, "", "[feeds."+strconv.Quote(feedURL)+"]")
+		if override.Model != "" {
+			lines = append(lines, "model = "+strconv.Quote(override.Model))
+		}
+		if override.Prompt != "" {
+			lines = append(lines, "prompt = "+strconv.Quote(override.Prompt))
+		}
+		if override.Style != "" {
+			lines = append(lines, "style = "+strconv.Quote(override.Style))
+		}
+		if override.Profile != "" {
+			lines = append(lines, "profile = "+strconv.Quote(override.Profile))
+		}
+		if policy, ok := cfg.FeedRetention[feedURL]; ok {
+			if policy.Days >= 0 {
+				lines = append(lines, "retention_days = "+strconv.Itoa(policy.Days))
+			}
+			if policy.Items >= 0 {
+				lines = append(lines, "retention_items = "+strconv.Itoa(policy.Items))
+			}
+		}
+		auth := cfg.FeedAuth[feedURL]
+		for _, setting := range [][2]string{
+			{"username", auth.Username},
+			{"password", auth.Password},
+			{"header_name", auth.HeaderName},
+			{"header_value", auth.HeaderValue},
+		} {
+			if setting[1] != "" {
+				lines = append(lines, setting[0]+" = "+strconv.Quote(setting[1]))
+			}
+		}
+	}
 	return strings.Join(lines, "\n") + "\n"
 }
 
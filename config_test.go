@@ -68,6 +68,26 @@ func TestParseConfigErrors(t *testing.T) {
 	if _, err := parseStringArray("nope"); err == nil {
 		t.Fatalf("expected array error")
 	}
+	if err := parseConfig("log_level = \"loud\"", &cfg); err == nil {
+		t.Fatalf("expected log_level error")
+	}
+}
+
+func TestParseConfigLogging(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.LogLevel != "info" || strings.Contains(renderConfig(cfg), "log_") {
+		t.Fatalf("unexpected logging defaults %q", cfg.LogLevel)
+	}
+	if err := parseConfig("log_level = \"DEBUG\"\nlog_file = \"~/greeder.log\"", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil {
+		t.Fatalf("parse rendered config error: %v", err)
+	}
+	if reparsed.LogLevel != "debug" || reparsed.LogFile != "~/greeder.log" {
+		t.Fatalf("logging did not round trip: %q %q", reparsed.LogLevel, reparsed.LogFile)
+	}
 }
 
 func TestTrimQuotes(t *testing.T) {
@@ -126,6 +146,36 @@ func TestSaveConfigError(t *testing.T) {
 	}
 }
 
+func TestPatchConfigFile(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	if err := patchConfigFile(map[string]string{"keyring": "true"}); err != nil {
+		t.Fatalf("patch new file: %v", err)
+	}
+	path := configPath()
+	if data, _ := os.ReadFile(path); string(data) != "keyring = true\n" {
+		t.Fatalf("unexpected new config %q", data)
+	}
+
+	raw := "# my feeds\ndb_path = \"/tmp/feeds.db\"\nraindrop_token = \"plain\"\n\n[summarizer]\n# keep this\nmodel = \"m\"\n"
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := patchConfigFile(map[string]string{"raindrop_token": "", "keyring": "true", "db_path": `"/data/feeds.db"`}); err != nil {
+		t.Fatalf("patch error: %v", err)
+	}
+	want := "# my feeds\ndb_path = \"/data/feeds.db\"\nkeyring = true\n\n[summarizer]\n# keep this\nmodel = \"m\"\n"
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Fatalf("unexpected patched config:\n%s", data)
+	}
+	if err := patchConfigFile(map[string]string{"keyring": "maybe"}); err == nil {
+		t.Fatalf("expected invalid value error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Fatalf("expected invalid patch to leave the file alone:\n%s", data)
+	}
+}
+
 func TestParseConfigScannerError(t *testing.T) {
 	cfg := DefaultConfig()
 	longLine := strings.Repeat("a", 70000)
@@ -181,8 +231,91 @@ func TestLoadConfigParseError(t *testing.T) {
 	if err := os.WriteFile(path, []byte("badline"), 0o644); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if _, err := LoadConfig(); err == nil {
-		t.Fatalf("expected load error")
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "config.toml: config line 1") {
+		t.Fatalf("expected load error with path and line, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte("db_path = \"/tmp/x.db\"\n"), 0o644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	t.Setenv("GREEDER_FETCHER_CONCURRENCY", "none")
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "GREEDER_FETCHER_CONCURRENCY") {
+		t.Fatalf("expected env override error, got %v", err)
+	}
+	t.Setenv("GREEDER_FETCHER_CONCURRENCY", "2")
+	if cfg, err := LoadConfig(); err != nil || cfg.FetchConcurrency != 2 || cfg.DBPath != "/tmp/x.db" {
+		t.Fatalf("expected env override applied, got %+v %v", cfg, err)
+	}
+}
+
+func TestParseConfigGroups(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.FetchTimeoutSeconds != 30 || cfg.FetchConcurrency != 5 || cfg.RetentionDays != 7 || cfg.DefaultFilter != "" {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	input := strings.Join([]string{
+		"[fetcher]",
+		"timeout_seconds = 10",
+		"concurrency = 8",
+		"refresh_interval_minutes = 15",
+		"[summarizer]",
+		"lm_model = \"llama\"",
+		"[integrations]",
+		"pinboard_token = \"me:tok\"",
+		"[tui]",
+		"default_filter = \"all\"",
+		"[retention]",
+		"article_days = 30",
+	}, "\n")
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.FetchTimeoutSeconds != 10 || cfg.FetchConcurrency != 8 || cfg.RefreshIntervalMinutes != 15 || cfg.LMModel != "llama" || cfg.PinboardToken != "me:tok" || cfg.DefaultFilter != "all" || cfg.RetentionDays != 30 {
+		t.Fatalf("unexpected grouped config %+v", cfg)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil {
+		t.Fatalf("parse rendered config error: %v", err)
+	}
+	if reparsed.FetchTimeoutSeconds != 10 || reparsed.FetchConcurrency != 8 || reparsed.DefaultFilter != "all" || reparsed.RetentionDays != 30 {
+		t.Fatalf("groups did not round trip: %s", renderConfig(cfg))
+	}
+	if strings.Contains(renderConfig(DefaultConfig()), "[fetcher]") || strings.Contains(renderConfig(DefaultConfig()), "[retention]") {
+		t.Fatalf("expected defaults not rendered")
+	}
+	for bad, want := range map[string]string{
+		"[fetcher]\ntimeout_seconds = 0":               "config line 2: invalid fetcher.timeout_seconds",
+		"[fetcher]\nconcurrency = many":                "config line 2: invalid fetcher.concurrency",
+		"\n[retention]\narticle_days = -1":             "config line 3: invalid retention.article_days",
+		"[tui]\ndefault_filter = \"recent\"":           "config line 2: invalid tui.default_filter",
+		"# comment\n[summarizer]\nsummary_workers = x": "config line 3: invalid summary_workers",
+		"db_path = \"x\"\nbadline":                     "config line 2: invalid config line",
+	} {
+		if err := parseConfig(bad, &cfg); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q for %q, got %v", want, bad, err)
+		}
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	cfg := DefaultConfig()
+	environ := []string{
+		"GREEDER_DB_PATH=/tmp/env.db",
+		"GREEDER_DEFAULT_TAGS=[\"a\", \"b\"]",
+		"GREEDER_KEYRING=true",
+		"GREEDER_RETENTION_ARTICLE_DAYS=0",
+		"GREEDER_TUI_DEFAULT_FILTER=starred",
+		"GREEDER_CREDENTIAL=lm_api_key",
+		"GREEDER_SOMETHING_NEW=1",
+		"HOME=/root",
+	}
+	if err := applyEnvOverrides(&cfg, environ); err != nil {
+		t.Fatalf("applyEnvOverrides error: %v", err)
+	}
+	if cfg.DBPath != "/tmp/env.db" || len(cfg.DefaultTags) != 2 || !cfg.Keyring || cfg.RetentionDays != 0 || cfg.DefaultFilter != "starred" {
+		t.Fatalf("unexpected env config %+v", cfg)
+	}
+	if err := applyEnvOverrides(&cfg, []string{"GREEDER_SUMMARY_WORKERS=lots"}); err == nil || !strings.HasPrefix(err.Error(), "GREEDER_SUMMARY_WORKERS: invalid summary_workers") {
+		t.Fatalf("expected env error naming the variable, got %v", err)
 	}
 }
 
@@ -211,3 +344,418 @@ func TestLoadConfigSaveError(t *testing.T) {
 		t.Fatalf("expected save error")
 	}
 }
+
+func TestParseConfigCosts(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("prompt_cost_per_million = 0.15\ncompletion_cost_per_million = 0.6", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.PromptCostPerMillion != 0.15 || cfg.CompletionCostPerMillion != 0.6 {
+		t.Fatalf("unexpected costs: %+v", cfg)
+	}
+	rendered := renderConfig(cfg)
+	if !strings.Contains(rendered, "prompt_cost_per_million = 0.15") || !strings.Contains(rendered, "completion_cost_per_million = 0.6") {
+		t.Fatalf("expected costs in rendered config: %s", rendered)
+	}
+	if err := parseConfig("prompt_cost_per_million = cheap", &cfg); err == nil {
+		t.Fatalf("expected prompt cost error")
+	}
+	if err := parseConfig("completion_cost_per_million = cheap", &cfg); err == nil {
+		t.Fatalf("expected completion cost error")
+	}
+}
+
+func TestParseConfigSummarizerSettings(t *testing.T) {
+	cfg := DefaultConfig()
+	input := strings.Join([]string{
+		"lm_preset = \"ollama\"",
+		"lm_base_url = \"http://localhost:11434/v1\"",
+		"lm_model = \"mistral\"",
+		"lm_api_key = \"key\"",
+		"summary_language = \"English\"",
+		"embedding_model = \"nomic-embed-text\"",
+		"extract_topics = true",
+		"credential_command = \"pass show greeder/{name}\"",
+		"keyring = true",
+	}, "\n")
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.LMPreset != "ollama" || cfg.LMModel != "mistral" || cfg.LMAPIKey != "key" || cfg.LMBaseURL == "" || cfg.SummaryLanguage != "English" || !cfg.ExtractTopics || cfg.EmbeddingModel != "nomic-embed-text" || cfg.CredentialCommand != "pass show greeder/{name}" || !cfg.Keyring {
+		t.Fatalf("unexpected summarizer config: %+v", cfg)
+	}
+	rendered := renderConfig(cfg)
+	for _, want := range []string{"lm_preset = \"ollama\"", "lm_base_url", "lm_model = \"mistral\"", "lm_api_key", "summary_language = \"English\"", "extract_topics = true", "embedding_model = \"nomic-embed-text\"", "credential_command = \"pass show greeder/{name}\"", "keyring = true"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("rendered config missing %q: %s", want, rendered)
+		}
+	}
+	if err := parseConfig("lm_preset = \"vllm\"", &cfg); err == nil {
+		t.Fatalf("expected invalid preset error")
+	}
+	if err := parseConfig("extract_topics = maybe", &cfg); err == nil {
+		t.Fatalf("expected invalid extract_topics error")
+	}
+	if err := parseConfig("keyring = maybe", &cfg); err == nil {
+		t.Fatalf("expected invalid keyring error")
+	}
+	if err := parseConfig("pocket_consumer_key = \"ck\"\npocket_access_token = \"at\"\nsave_target = \"pocket\"", &cfg); err != nil || cfg.PocketConsumerKey != "ck" || cfg.PocketAccessToken != "at" || cfg.SaveTarget != "pocket" {
+		t.Fatalf("unexpected pocket config: %+v %v", cfg, err)
+	}
+	if rendered := renderConfig(cfg); !strings.Contains(rendered, "pocket_access_token = \"at\"") || !strings.Contains(rendered, "save_target = \"pocket\"") {
+		t.Fatalf("rendered config missing pocket settings: %s", rendered)
+	}
+	if err := parseConfig("pinboard_token = \"user:tok\"\nsave_target = \"pinboard\"", &cfg); err != nil || cfg.PinboardToken != "user:tok" || !strings.Contains(renderConfig(cfg), "pinboard_token = \"user:tok\"") {
+		t.Fatalf("unexpected pinboard config: %+v %v", cfg, err)
+	}
+	archive := "archive_type = \"shiori\"\narchive_url = \"http://shiori.local\"\narchive_token = \"t\"\narchive_username = \"me\"\narchive_password = \"pw\"\nsave_target = \"archive\""
+	if err := parseConfig(archive, &cfg); err != nil || cfg.ArchiveType != "shiori" || cfg.ArchiveURL != "http://shiori.local" || cfg.ArchiveUsername != "me" || cfg.ArchivePassword != "pw" || cfg.ArchiveToken != "t" || cfg.SaveTarget != "archive" {
+		t.Fatalf("unexpected archive config: %+v %v", cfg, err)
+	}
+	for _, want := range strings.Split(archive, "\n") {
+		if !strings.Contains(renderConfig(cfg), want) {
+			t.Fatalf("rendered config missing %q", want)
+		}
+	}
+	if err := parseConfig("archive_type = \"wallabag\"", &cfg); err == nil {
+		t.Fatalf("expected invalid archive_type error")
+	}
+	if err := parseConfig("save_target = \"delicious\"", &cfg); err == nil {
+		t.Fatalf("expected invalid save_target error")
+	}
+	sync := "sync_backend = \"nextcloud\"\nsync_url = \"https://cloud.example.com\"\nsync_username = \"me\"\nsync_password = \"app-pw\""
+	if err := parseConfig(sync, &cfg); err != nil || cfg.SyncBackend != "nextcloud" || cfg.SyncURL != "https://cloud.example.com" || cfg.SyncUsername != "me" || cfg.SyncPassword != "app-pw" {
+		t.Fatalf("unexpected sync config: %+v %v", cfg, err)
+	}
+	for _, want := range strings.Split(sync, "\n") {
+		if !strings.Contains(renderConfig(cfg), want) {
+			t.Fatalf("rendered config missing %q", want)
+		}
+	}
+	if err := parseConfig("sync_backend = \"miniflux\"\nsync_token = \"key\"", &cfg); err != nil || cfg.SyncBackend != "miniflux" || cfg.SyncToken != "key" || !strings.Contains(renderConfig(cfg), "sync_token = \"key\"") {
+		t.Fatalf("unexpected miniflux config: %+v %v", cfg, err)
+	}
+	if err := parseConfig("sync_backend = \"greader\"", &cfg); err != nil || cfg.SyncBackend != "greader" {
+		t.Fatalf("unexpected greader config: %+v %v", cfg, err)
+	}
+	if err := parseConfig("notes_dir = \"~/vault/Reading\"", &cfg); err != nil || cfg.NotesDir != "~/vault/Reading" || !strings.Contains(renderConfig(cfg), "notes_dir = \"~/vault/Reading\"") {
+		t.Fatalf("unexpected notes config: %+v %v", cfg, err)
+	}
+	if err := parseConfig("zotero_api_key = \"zk\"\nzotero_user_id = \"42\"", &cfg); err != nil || cfg.ZoteroAPIKey != "zk" || cfg.ZoteroUserID != "42" || !strings.Contains(renderConfig(cfg), "zotero_user_id = \"42\"") || !strings.Contains(renderConfig(cfg), "zotero_api_key = \"zk\"") {
+		t.Fatalf("unexpected zotero config: %+v %v", cfg, err)
+	}
+	if err := parseConfig("wayback = [\"bookmark\", \"star\"]", &cfg); err != nil || len(cfg.Wayback) != 2 || !strings.Contains(renderConfig(cfg), "wayback = [\"bookmark\", \"star\"]") {
+		t.Fatalf("unexpected wayback config: %+v %v", cfg.Wayback, err)
+	}
+	if err := parseConfig("wayback = [\"open\"]", &cfg); err == nil {
+		t.Fatalf("expected invalid wayback event error")
+	}
+	if err := parseConfig("wayback = nope", &cfg); err == nil {
+		t.Fatalf("expected invalid wayback array error")
+	}
+	if err := parseConfig("omnivore_api_key = \"ok\"\nomnivore_url = \"https://omnivore.local\"\nsave_target = \"omnivore\"", &cfg); err != nil || cfg.OmnivoreAPIKey != "ok" || cfg.OmnivoreURL != "https://omnivore.local" || cfg.SaveTarget != "omnivore" || !strings.Contains(renderConfig(cfg), "omnivore_url = \"https://omnivore.local\"") || !strings.Contains(renderConfig(cfg), "omnivore_api_key = \"ok\"") {
+		t.Fatalf("unexpected omnivore config: %+v %v", cfg, err)
+	}
+	if err := parseConfig("rss_bridge_url = \"https://bridge.example.com\"", &cfg); err != nil || cfg.RSSBridgeURL != "https://bridge.example.com" || !strings.Contains(renderConfig(cfg), "rss_bridge_url = \"https://bridge.example.com\"") {
+		t.Fatalf("unexpected rss-bridge config: %+v %v", cfg, err)
+	}
+	mastodon := "mastodon_url = \"https://mastodon.social\"\nmastodon_token = \"mt\"\nmastodon_include_summary = true"
+	if err := parseConfig(mastodon, &cfg); err != nil || cfg.MastodonURL != "https://mastodon.social" || cfg.MastodonToken != "mt" || !cfg.MastodonIncludeSummary {
+		t.Fatalf("unexpected mastodon config: %+v %v", cfg, err)
+	}
+	for _, want := range strings.Split(mastodon, "\n") {
+		if !strings.Contains(renderConfig(cfg), want) {
+			t.Fatalf("rendered config missing %q", want)
+		}
+	}
+	if err := parseConfig("mastodon_include_summary = maybe", &cfg); err == nil {
+		t.Fatalf("expected invalid mastodon_include_summary error")
+	}
+	if err := parseConfig("cache_dir = \"~/cache\"\ncache_max_mb = 20", &cfg); err != nil || cfg.CacheDir != "~/cache" || cfg.CacheMaxMB != 20 || !strings.Contains(renderConfig(cfg), "cache_max_mb = 20") {
+		t.Fatalf("unexpected cache config: %+v %v", cfg, err)
+	}
+	if err := parseConfig("cache_max_mb = -1", &cfg); err == nil {
+		t.Fatalf("expected invalid cache_max_mb error")
+	}
+	imap := "imap_host = \"mail.example.com\"\nimap_username = \"me\"\nimap_password = \"secret\"\nimap_folder = \"Newsletters\"\nimap_plaintext = true"
+	if err := parseConfig(imap, &cfg); err != nil || cfg.IMAPHost != "mail.example.com" || cfg.IMAPUsername != "me" || cfg.IMAPPassword != "secret" || cfg.IMAPFolder != "Newsletters" || !cfg.IMAPPlaintext {
+		t.Fatalf("unexpected imap config: %+v %v", cfg, err)
+	}
+	for _, want := range strings.Split(imap, "\n") {
+		if !strings.Contains(renderConfig(cfg), want) {
+			t.Fatalf("rendered config missing %q", want)
+		}
+	}
+	if err := parseConfig("imap_plaintext = maybe", &cfg); err == nil {
+		t.Fatalf("expected invalid imap_plaintext error")
+	}
+	if err := parseConfig("sync_backend = \"feedly\"", &cfg); err == nil {
+		t.Fatalf("expected invalid sync_backend error")
+	}
+}
+
+func TestParseConfigFeedOverrides(t *testing.T) {
+	cfg := DefaultConfig()
+	input := strings.Join([]string{
+		"lm_model = \"mistral\"",
+		"[feeds.\"https://essays.example.com/rss\"]",
+		"model = \"llama3.1:70b\"",
+		"style = \"paragraph\"",
+		"[feeds.\"https://links.example.com/rss\"]",
+		"prompt = \"Describe the linked page in one line.\"",
+		"[unknown]",
+		"anything = 1",
+	}, "\n")
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	essays := cfg.FeedOverrides["https://essays.example.com/rss"]
+	if cfg.LMModel != "mistral" || essays.Model != "llama3.1:70b" || essays.Style != "paragraph" {
+		t.Fatalf("unexpected overrides: %+v", cfg.FeedOverrides)
+	}
+	if cfg.FeedOverrides["https://links.example.com/rss"].Prompt != "Describe the linked page in one line." {
+		t.Fatalf("unexpected prompt override: %+v", cfg.FeedOverrides)
+	}
+
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil {
+		t.Fatalf("parse rendered config error: %v", err)
+	}
+	if len(reparsed.FeedOverrides) != 2 || reparsed.FeedOverrides["https://essays.example.com/rss"] != essays {
+		t.Fatalf("overrides did not round trip: %+v", reparsed.FeedOverrides)
+	}
+
+	if err := parseConfig("[feeds.\"https://x\"]\nstyle = \"haiku\"", &cfg); err == nil {
+		t.Fatalf("expected invalid style error")
+	}
+	if err := parseConfig("[feeds.\"\"]\nmodel = \"m\"", &cfg); err == nil {
+		t.Fatalf("expected invalid section error")
+	}
+}
+
+func TestParseConfigRelevance(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("interests = [\"go\", \"security\"]\nrelevance_scoring = \"llm\"", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if len(cfg.Interests) != 2 || cfg.Interests[1] != "security" || cfg.RelevanceScoring != "llm" {
+		t.Fatalf("unexpected relevance config: %+v", cfg)
+	}
+	rendered := renderConfig(cfg)
+	if !strings.Contains(rendered, "interests = [\"go\", \"security\"]") || !strings.Contains(rendered, "relevance_scoring = \"llm\"") {
+		t.Fatalf("expected relevance settings in rendered config: %s", rendered)
+	}
+	if err := parseConfig("relevance_scoring = \"vibes\"", &cfg); err == nil {
+		t.Fatalf("expected invalid relevance_scoring error")
+	}
+	if err := parseConfig("interests = go", &cfg); err == nil {
+		t.Fatalf("expected invalid interests error")
+	}
+}
+
+func TestParseConfigSummaryWorkers(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.SummaryWorkers != 4 || strings.Contains(renderConfig(cfg), "summary_workers") {
+		t.Fatalf("expected default workers omitted from rendered config")
+	}
+	if err := parseConfig("summary_workers = 8", &cfg); err != nil || cfg.SummaryWorkers != 8 {
+		t.Fatalf("unexpected workers: %d (%v)", cfg.SummaryWorkers, err)
+	}
+	if !strings.Contains(renderConfig(cfg), "summary_workers = 8") {
+		t.Fatalf("expected workers in rendered config")
+	}
+	for _, bad := range []string{"summary_workers = 0", "summary_workers = many"} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseConfigSummarizerLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("lm_timeout_seconds = 120\nmax_input_chars = 4000", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.LMTimeoutSeconds != 120 || cfg.MaxInputChars != 4000 {
+		t.Fatalf("unexpected limits: %+v", cfg)
+	}
+	rendered := renderConfig(cfg)
+	if !strings.Contains(rendered, "lm_timeout_seconds = 120") || !strings.Contains(rendered, "max_input_chars = 4000") {
+		t.Fatalf("expected limits in rendered config: %s", rendered)
+	}
+	for _, bad := range []string{"lm_timeout_seconds = soon", "lm_timeout_seconds = -1", "max_input_chars = lots", "max_input_chars = -5"} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseConfigProviders(t *testing.T) {
+	cfg := DefaultConfig()
+	input := strings.Join([]string{
+		"providers = [\"local\", \"hosted\"]",
+		"[provider.local]",
+		"preset = \"ollama\"",
+		"model = \"llama3.1\"",
+		"[provider.hosted]",
+		"base_url = \"https://api.example.com/v1\"",
+		"api_key = \"key\"",
+		"timeout_seconds = 30",
+	}, "\n")
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if len(cfg.Providers) != 2 || cfg.ProviderSettings["local"].Preset != "ollama" || cfg.ProviderSettings["hosted"].TimeoutSeconds != 30 {
+		t.Fatalf("unexpected providers: %+v", cfg.ProviderSettings)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil {
+		t.Fatalf("parse rendered config error: %v", err)
+	}
+	if reparsed.ProviderSettings["hosted"] != cfg.ProviderSettings["hosted"] || reparsed.Providers[0] != "local" {
+		t.Fatalf("providers did not round trip: %+v", reparsed)
+	}
+	for _, bad := range []string{
+		"[provider.x]\npreset = \"nope\"",
+		"[provider.x]\ntimeout_seconds = soon",
+		"[provider.\"\"]\nmodel = \"m\"",
+		"providers = local",
+	} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseConfigNotifiers(t *testing.T) {
+	cfg := DefaultConfig()
+	input := strings.Join([]string{
+		"digest_time = \"07:30\"",
+		"[notify.team]",
+		"type = \"slack\"",
+		"webhook_url = \"https://hooks.slack.test/x\"",
+		"feeds = [\"security\"]",
+		"[notify.me]",
+		"type = \"matrix\"",
+		"homeserver = \"https://matrix.test\"",
+		"room_id = \"!abc:matrix.test\"",
+		"access_token = \"tok\"",
+		"events = [\"digest\"]",
+		"[notify.phone]",
+		"type = \"telegram\"",
+		"bot_token = \"123:abc\"",
+		"chat_id = \"42\"",
+	}, "\n")
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.DigestTime != "07:30" || cfg.Notifiers["team"].Feeds[0] != "security" || cfg.Notifiers["me"].RoomID != "!abc:matrix.test" || cfg.Notifiers["me"].Events[0] != "digest" {
+		t.Fatalf("unexpected notifiers: %+v", cfg.Notifiers)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil {
+		t.Fatalf("parse rendered config error: %v", err)
+	}
+	if reparsed.DigestTime != "07:30" || reparsed.Notifiers["me"].AccessToken != "tok" || reparsed.Notifiers["team"].WebhookURL != "https://hooks.slack.test/x" || reparsed.Notifiers["me"].Homeserver != "https://matrix.test" || reparsed.Notifiers["phone"].BotToken != "123:abc" || reparsed.Notifiers["phone"].ChatID != "42" {
+		t.Fatalf("notifiers did not round trip: %+v", reparsed.Notifiers)
+	}
+	for _, bad := range []string{
+		"digest_time = \"noon\"",
+		"[notify.x]\ntype = \"irc\"",
+		"[notify.x]\nevents = [\"everything\"]",
+		"[notify.x]\nevents = digest",
+		"[notify.x]\nfeeds = go",
+		"[notify.\"\"]\ntype = \"slack\"",
+	} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseConfigAccounts(t *testing.T) {
+	cfg := DefaultConfig()
+	input := strings.Join([]string{
+		"sync_backend = \"nextcloud\"",
+		"[account.work]",
+		"backend = \"miniflux\"",
+		"url = \"https://flux.example.com\"",
+		"token = \"tok\"",
+		"[account.home]",
+		"backend = \"greader\"",
+		"url = \"https://fresh.example.com/api/greader.php\"",
+		"username = \"me\"",
+		"password = \"pw\"",
+	}, "\n")
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	work := cfg.Accounts["work"]
+	if cfg.SyncBackend != "nextcloud" || work.Name != "work" || work.Backend != "miniflux" || work.URL != "https://flux.example.com" || work.Token != "tok" {
+		t.Fatalf("unexpected accounts: %+v", cfg.Accounts)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil {
+		t.Fatalf("parse rendered config error: %v", err)
+	}
+	if reparsed.Accounts["work"] != work || reparsed.Accounts["home"] != cfg.Accounts["home"] {
+		t.Fatalf("accounts did not round trip: %+v", reparsed.Accounts)
+	}
+	for _, bad := range []string{
+		"[account.x]\nbackend = \"feedly\"",
+		"[account.\"\"]\nbackend = \"miniflux\"",
+	} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseConfigTimezone(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("timezone = \"Europe/Berlin\"", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil || reparsed.Timezone != "Europe/Berlin" {
+		t.Fatalf("timezone did not round trip: %q %v", reparsed.Timezone, err)
+	}
+	if err := parseConfig("timezone = \"Nowhere/Special\"", &cfg); err == nil || !strings.Contains(err.Error(), "invalid timezone") {
+		t.Fatalf("expected invalid timezone error, got %v", err)
+	}
+	app := newTUIApp(t)
+	app.applyConfig(reparsed, nil)
+	if app.location.String() != "Europe/Berlin" {
+		t.Fatalf("expected app location, got %v", app.location)
+	}
+}
+
+func TestParseConfigSummarizerProfiles(t *testing.T) {
+	cfg := DefaultConfig()
+	input := strings.Join([]string{
+		"lm_temperature = 0.5",
+		"[provider.longform]",
+		"base_url = \"https://api.example.com/v1\"",
+		"temperature = 0.7",
+		"[feeds.\"https://essays.example.com/rss\"]",
+		"profile = \"longform\"",
+	}, "\n")
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if *cfg.LMTemperature != 0.5 || *cfg.ProviderSettings["longform"].Temperature != 0.7 || cfg.FeedOverrides["https://essays.example.com/rss"].Profile != "longform" {
+		t.Fatalf("unexpected profiles config: %+v", cfg)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil {
+		t.Fatalf("parse rendered config error: %v", err)
+	}
+	if *reparsed.LMTemperature != 0.5 || *reparsed.ProviderSettings["longform"].Temperature != 0.7 || reparsed.FeedOverrides["https://essays.example.com/rss"].Profile != "longform" {
+		t.Fatalf("profiles did not round trip: %+v", reparsed)
+	}
+	for _, bad := range []string{"lm_temperature = hot", "lm_temperature = 3", "[provider.x]\ntemperature = -1"} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type tuiPanicMsg struct {
+	value any
+	stack []byte
+}
+
+var (
+	releaseTerminal = func(program *tea.Program) error { return program.ReleaseTerminal() }
+	crashNow        = time.Now
+)
+
+func guardCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer func() {
+			if r := recover(); r != nil {
+				msg = tuiPanicMsg{value: r, stack: debug.Stack()}
+			}
+		}()
+		msg = cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			guarded := make(tea.BatchMsg, len(batch))
+			for i, inner := range batch {
+				guarded[i] = guardCmd(inner)
+			}
+			return guarded
+		}
+		return msg
+	}
+}
+
+func crashLogPath(now time.Time) string {
+	dir := os.TempDir()
+	if path := defaultLogPath(); path != "" {
+		dir = filepath.Dir(path)
+	}
+	return filepath.Join(dir, "crash-"+now.Format("20060102-150405")+".log")
+}
+
+func (a *App) crashReport(now time.Time, value any, stack []byte) string {
+	lines := []string{
+		"greeder crashed at " + now.Format(time.RFC3339),
+		fmt.Sprintf("panic: %v", value),
+		"",
+		"status: " + a.status,
+		"filter: " + string(a.filter),
+	}
+	if article := a.SelectedArticle(); article != nil {
+		lines = append(lines, fmt.Sprintf("selected article: %d %s", article.ID, article.Title))
+	}
+	if len(a.summaryPending) > 0 {
+		pending := make([]string, 0, len(a.summaryPending))
+		for id := range a.summaryPending {
+			pending = append(pending, fmt.Sprint(id))
+		}
+		sort.Strings(pending)
+		lines = append(lines, "pending summaries: "+strings.Join(pending, ", "))
+	}
+	lines = append(lines, "", string(stack))
+	return strings.Join(lines, "\n")
+}
+
+func (a *App) recoverTUI(program *tea.Program, recovered any) error {
+	_ = releaseTerminal(program)
+	value, stack := recovered, debug.Stack()
+	if crash, ok := recovered.(tuiPanicMsg); ok {
+		value, stack = crash.value, crash.stack
+	}
+	now := crashNow()
+	path := crashLogPath(now)
+	logFor("tui").Error("panic", "value", fmt.Sprint(value), "crash_log", path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("tui crashed: %v", value)
+	}
+	if err := os.WriteFile(path, []byte(a.crashReport(now, value, stack)), 0o600); err != nil {
+		return fmt.Errorf("tui crashed: %v", value)
+	}
+	return fmt.Errorf("tui crashed: %v (crash log: %s)", value, path)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGuardCmd(t *testing.T) {
+	if guardCmd(nil) != nil {
+		t.Fatalf("expected nil command to stay nil")
+	}
+	if msg := guardCmd(func() tea.Msg { return spinnerTickMsg{} })(); msg != (spinnerTickMsg{}) {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+	msg := guardCmd(func() tea.Msg { panic("boom") })()
+	crash, ok := msg.(tuiPanicMsg)
+	if !ok || crash.value != "boom" || !strings.Contains(string(crash.stack), "TestGuardCmd") {
+		t.Fatalf("expected panic message, got %#v", msg)
+	}
+	batch, ok := guardCmd(tea.Batch(func() tea.Msg { return spinnerTickMsg{} }, func() tea.Msg { panic("inner") }))().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("expected batch message")
+	}
+	if inner, ok := batch[1]().(tuiPanicMsg); !ok || inner.value != "inner" {
+		t.Fatalf("expected batched command to be guarded")
+	}
+}
+
+func TestUpdateRaisesCommandPanic(t *testing.T) {
+	model := newTUIModel(newTUIApp(t))
+	defer func() {
+		recovered := recover()
+		if crash, ok := recovered.(tuiPanicMsg); !ok || crash.value != "boom" {
+			t.Fatalf("expected command panic re-raised, got %#v", recovered)
+		}
+	}()
+	model.Update(tuiPanicMsg{value: "boom"})
+}
+
+func TestRunTUIRecoversPanic(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "a", Title: "Crashy", URL: "https://example.com/a"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.status = "Refreshing feeds..."
+	app.summaryPending[app.articles[0].ID] = true
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+
+	origRun := runTeaProgram
+	origRelease := releaseTerminal
+	origNow := crashNow
+	t.Cleanup(func() {
+		runTeaProgram = origRun
+		releaseTerminal = origRelease
+		crashNow = origNow
+	})
+	released := false
+	releaseTerminal = func(*tea.Program) error {
+		released = true
+		return nil
+	}
+	crashNow = func() time.Time { return time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC) }
+	runTeaProgram = func(*tea.Program) (tea.Model, error) {
+		panic("index out of range")
+	}
+
+	err = RunTUI(app)
+	path := filepath.Join(stateDir, "greeder", "crash-20261015-093000.log")
+	if err == nil || err.Error() != "tui crashed: index out of range (crash log: "+path+")" || !released {
+		t.Fatalf("unexpected recovery %v released=%v", err, released)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read crash log error: %v", err)
+	}
+	report := string(data)
+	for _, want := range []string{"panic: index out of range", "status: Refreshing feeds...", "selected article: ", "Crashy", "pending summaries: ", "goroutine"} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("crash log missing %q: %s", want, report)
+		}
+	}
+
+	runTeaProgram = func(*tea.Program) (tea.Model, error) {
+		panic(tuiPanicMsg{value: "from command", stack: []byte("command stack")})
+	}
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, []byte("x"), 0o644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	t.Setenv("XDG_STATE_HOME", blocker)
+	if err := RunTUI(app); err == nil || err.Error() != "tui crashed: from command" {
+		t.Fatalf("expected crash error without log, got %v", err)
+	}
+}
+
+func TestCrashReportCommandStack(t *testing.T) {
+	app := newTUIApp(t)
+	report := app.crashReport(time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC), "boom", []byte("command stack"))
+	if !strings.HasPrefix(report, "greeder crashed at 2026-10-15T09:30:00Z\npanic: boom\n") || !strings.HasSuffix(report, "command stack") || strings.Contains(report, "pending summaries") {
+		t.Fatalf("unexpected report %q", report)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+const keyringService = "greeder"
+
+var credentialRun = defaultCredentialRun
+
+type credentialLookup struct {
+	name   string
+	source string
+	found  bool
+}
+
+func defaultCredentialRun(name string, args []string, env []string) (string, error) {
+	command := execCommand(name, args...)
+	command.Env = append(os.Environ(), env...)
+	out, err := command.Output()
+	return string(out), err
+}
+
+func resolveCredentials(cfg Config) (Config, []credentialLookup) {
+	if strings.TrimSpace(cfg.CredentialCommand) == "" && !cfg.Keyring {
+		return cfg, nil
+	}
+	lookups := []credentialLookup{}
+	resolve := func(name string, current string) string {
+		if current != "" {
+			return current
+		}
+		value, lookup := lookupCredential(cfg, name)
+		lookups = append(lookups, lookup)
+		return value
+	}
+	if os.Getenv("LM_API_KEY") == "" && len(cfg.Providers) == 0 {
+		cfg.LMAPIKey = resolve("lm_api_key", cfg.LMAPIKey)
+	}
+	cfg.RaindropToken = resolve("raindrop_token", cfg.RaindropToken)
+	if cfg.SaveTarget == "pinboard" {
+		cfg.PinboardToken = resolve("pinboard_token", cfg.PinboardToken)
+	}
+	if cfg.MastodonURL != "" {
+		cfg.MastodonToken = resolve("mastodon_token", cfg.MastodonToken)
+	}
+	if cfg.IMAPHost != "" {
+		cfg.IMAPPassword = resolve("imap_password", cfg.IMAPPassword)
+	}
+	if cfg.SMTPHost != "" && cfg.SMTPUsername != "" {
+		cfg.SMTPPassword = resolve("smtp_password", cfg.SMTPPassword)
+	}
+	if cfg.SaveTarget == "omnivore" {
+		cfg.OmnivoreAPIKey = resolve("omnivore_api_key", cfg.OmnivoreAPIKey)
+	}
+	if cfg.ArchiveType == "readeck" {
+		cfg.ArchiveToken = resolve("archive_token", cfg.ArchiveToken)
+	} else if cfg.ArchiveType == "shiori" && cfg.ArchiveToken == "" {
+		cfg.ArchivePassword = resolve("archive_password", cfg.ArchivePassword)
+	}
+	if (cfg.SyncBackend == "miniflux" || cfg.SyncBackend == "greader") && cfg.SyncUsername == "" {
+		cfg.SyncToken = resolve("sync_token", cfg.SyncToken)
+	} else if cfg.SyncBackend != "" {
+		cfg.SyncPassword = resolve("sync_password", cfg.SyncPassword)
+	}
+	if cfg.ZoteroUserID != "" {
+		cfg.ZoteroAPIKey = resolve("zotero_api_key", cfg.ZoteroAPIKey)
+	}
+	if cfg.PocketConsumerKey != "" {
+		cfg.PocketAccessToken = resolve("pocket_access_token", cfg.PocketAccessToken)
+	}
+	if len(cfg.Accounts) > 0 {
+		accounts := make(map[string]AccountConfig, len(cfg.Accounts))
+		for name, account := range cfg.Accounts {
+			if (account.Backend == "miniflux" || account.Backend == "greader") && account.Username == "" {
+				account.Token = resolve("account."+name+".token", account.Token)
+			} else if account.Backend != "" {
+				account.Password = resolve("account."+name+".password", account.Password)
+			}
+			accounts[name] = account
+		}
+		cfg.Accounts = accounts
+	}
+	if len(cfg.FeedAuth) > 0 {
+		feedAuth := make(map[string]FeedAuth, len(cfg.FeedAuth))
+		for feedURL, auth := range cfg.FeedAuth {
+			if auth.HeaderName != "" {
+				auth.HeaderValue = resolve("feed."+feedURL+".header_value", auth.HeaderValue)
+			} else if auth.Username != "" {
+				auth.Password = resolve("feed."+feedURL+".password", auth.Password)
+			}
+			feedAuth[feedURL] = auth
+		}
+		cfg.FeedAuth = feedAuth
+	}
+	if len(cfg.ProviderSettings) > 0 {
+		providers := make(map[string]ProviderConfig, len(cfg.ProviderSettings))
+		for name, provider := range cfg.ProviderSettings {
+			if !summarizerPresets[provider.Preset].local {
+				provider.APIKey = resolve("provider."+name+".api_key", provider.APIKey)
+			}
+			providers[name] = provider
+		}
+		cfg.ProviderSettings = providers
+	}
+	return cfg, lookups
+}
+
+func lookupCredential(cfg Config, name string) (string, credentialLookup) {
+	lookup := credentialLookup{name: name}
+	if command := strings.TrimSpace(cfg.CredentialCommand); command != "" {
+		lookup.source = "credential_command"
+		shell, args := shellCommandForOS(runtime.GOOS, credentialCommandLine(runtime.GOOS, command, name))
+		out, err := credentialRun(shell, args, []string{"GREEDER_CREDENTIAL=" + name})
+		if value := strings.TrimSpace(out); err == nil && value != "" {
+			lookup.found = true
+			return value, lookup
+		}
+	}
+	if cfg.Keyring {
+		lookup.source = "keyring"
+		backend := secretBackendForOS(secretBackendOS)
+		if backend == nil {
+			return "", lookup
+		}
+		if value, err := backend.Get(name); err == nil && value != "" {
+			lookup.found = true
+			return value, lookup
+		}
+	}
+	return "", lookup
+}
+
+// credentialCommandLine fills {name} into credential_command. Names such as
+// feed.URL can carry &, ; or ?, so anything beyond plain name characters is
+// quoted for the shell that will run the line.
+func credentialCommandLine(goos string, command string, name string) string {
+	plain := !strings.ContainsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_.-/:@+=,", r))
+	})
+	switch {
+	case plain:
+	case goos == "windows":
+		// cmd has no escape that survives %-expansion, so point it at the
+		// environment copy instead; the quotes keep & and | literal.
+		name = `"%GREEDER_CREDENTIAL%"`
+	default:
+		name = "'" + strings.ReplaceAll(name, "'", `'\''`) + "'"
+	}
+	return strings.ReplaceAll(command, "{name}", name)
+}
+
+func shellCommandForOS(goos string, command string) (string, []string) {
+	if goos == "windows" {
+		return "cmd", []string{"/c", command}
+	}
+	return "sh", []string{"-c", command}
+}
+
+func keyringCommandForOS(goos string, name string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "security", []string{"find-generic-password", "-s", keyringService, "-a", name, "-w"}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "secret-tool", []string{"lookup", "service", keyringService, "account", name}
+	default:
+		return "", nil
+	}
+}
+
+func credentialsDoctorCheck(lookups []credentialLookup) DoctorCheck {
+	found := []string{}
+	missing := []string{}
+	for _, lookup := range lookups {
+		if lookup.found {
+			found = append(found, lookup.name+" ("+lookup.source+")")
+			continue
+		}
+		missing = append(missing, lookup.name)
+	}
+	detail := "found " + valueOrFallback(strings.Join(found, ", "), "none")
+	if len(missing) > 0 {
+		detail += "; not found " + strings.Join(missing, ", ")
+	}
+	return DoctorCheck{Name: "credentials", OK: len(found) > 0, Detail: detail}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func stubCredentialRun(t *testing.T, fn func(name string, args []string, env []string) (string, error)) {
+	orig := credentialRun
+	credentialRun = fn
+	t.Cleanup(func() { credentialRun = orig })
+}
+
+func TestResolveCredentialsCommand(t *testing.T) {
+	t.Setenv("LM_API_KEY", "")
+	calls := []string{}
+	stubCredentialRun(t, func(name string, args []string, env []string) (string, error) {
+		if name != "sh" || args[0] != "-c" {
+			t.Fatalf("unexpected command %s %v", name, args)
+		}
+		calls = append(calls, args[1])
+		if len(env) != 1 || !strings.HasPrefix(env[0], "GREEDER_CREDENTIAL=") {
+			t.Fatalf("unexpected env %v", env)
+		}
+		switch args[1] {
+		case "pass show greeder/lm_api_key":
+			return "lm-secret\n", nil
+		case "pass show greeder/provider.hosted.api_key":
+			return "hosted-secret", nil
+		}
+		return "", errors.New("exit status 1")
+	})
+	cfg := Config{
+		CredentialCommand: "pass show greeder/{name}",
+		ProviderSettings: map[string]ProviderConfig{
+			"hosted": {Name: "hosted", BaseURL: "https://api.example.com"},
+			"local":  {Name: "local", Preset: "ollama"},
+			"keyed":  {Name: "keyed", BaseURL: "https://other.example.com", APIKey: "inline"},
+		},
+	}
+	resolved, lookups := resolveCredentials(cfg)
+	if resolved.LMAPIKey != "lm-secret" || resolved.RaindropToken != "" {
+		t.Fatalf("unexpected resolved config: %+v", resolved)
+	}
+	if resolved.ProviderSettings["hosted"].APIKey != "hosted-secret" || resolved.ProviderSettings["keyed"].APIKey != "inline" || resolved.ProviderSettings["local"].APIKey != "" {
+		t.Fatalf("unexpected provider keys: %+v", resolved.ProviderSettings)
+	}
+	if cfg.ProviderSettings["hosted"].APIKey != "" {
+		t.Fatalf("expected original config untouched")
+	}
+	if len(calls) != 3 || len(lookups) != 3 {
+		t.Fatalf("unexpected lookups %v %+v", calls, lookups)
+	}
+	check := credentialsDoctorCheck(lookups)
+	if !check.OK || !strings.Contains(check.Detail, "lm_api_key (credential_command)") || !strings.Contains(check.Detail, "not found raindrop_token") {
+		t.Fatalf("unexpected doctor check: %+v", check)
+	}
+}
+
+func TestResolveCredentialNames(t *testing.T) {
+	stubCredentialRun(t, func(name string, args []string, env []string) (string, error) {
+		return strings.TrimPrefix(env[0], "GREEDER_CREDENTIAL="), nil
+	})
+	resolved, _ := resolveCredentials(Config{
+		CredentialCommand: "helper {name}",
+		SMTPHost:          "mail.example.com",
+		SMTPUsername:      "me",
+		Accounts: map[string]AccountConfig{
+			"reader": {Backend: "miniflux"},
+			"cloud":  {Backend: "nextcloud", Username: "me"},
+		},
+	})
+	if resolved.SMTPPassword != "smtp_password" || resolved.Accounts["reader"].Token != "account.reader.token" || resolved.Accounts["cloud"].Password != "account.cloud.password" {
+		t.Fatalf("unexpected credential names: %q %+v", resolved.SMTPPassword, resolved.Accounts)
+	}
+}
+
+func TestResolveCredentialsKeyringAndPrecedence(t *testing.T) {
+	stubCredentialRun(t, func(name string, args []string, env []string) (string, error) {
+		if name == "sh" {
+			return "", errors.New("exit status 1")
+		}
+		if args[len(args)-1] == "raindrop_token" || args[len(args)-2] == "raindrop_token" {
+			return "rd-secret\n", nil
+		}
+		return "", errors.New("not found")
+	})
+	t.Setenv("LM_API_KEY", "from-env")
+	resolved, lookups := resolveCredentials(Config{CredentialCommand: "false", Keyring: true})
+	if resolved.RaindropToken != "rd-secret" || resolved.LMAPIKey != "" || len(lookups) != 1 || lookups[0].source != "keyring" {
+		t.Fatalf("unexpected keyring resolution: %+v %+v", resolved, lookups)
+	}
+	if _, lookups := resolveCredentials(Config{RaindropToken: "inline", LMAPIKey: "inline"}); lookups != nil {
+		t.Fatalf("expected no lookups without credential sources")
+	}
+	if check := credentialsDoctorCheck([]credentialLookup{{name: "lm_api_key", source: "keyring"}}); check.OK || check.Detail != "found none; not found lm_api_key" {
+		t.Fatalf("unexpected failing check: %+v", check)
+	}
+}
+
+func TestCredentialCommandsForOS(t *testing.T) {
+	if name, args := keyringCommandForOS("darwin", "lm_api_key"); name != "security" || args[len(args)-1] != "-w" {
+		t.Fatalf("unexpected darwin keyring command %s %v", name, args)
+	}
+	if name, args := keyringCommandForOS("linux", "lm_api_key"); name != "secret-tool" || args[len(args)-1] != "lm_api_key" {
+		t.Fatalf("unexpected linux keyring command %s %v", name, args)
+	}
+	if name, _ := keyringCommandForOS("plan9", "x"); name != "" {
+		t.Fatalf("expected unsupported keyring")
+	}
+	if name, args := shellCommandForOS("windows", "echo hi"); name != "cmd" || args[1] != "echo hi" {
+		t.Fatalf("unexpected windows shell %s %v", name, args)
+	}
+	if out, err := defaultCredentialRun("sh", []string{"-c", "printf %s \"$GREEDER_CREDENTIAL\""}, []string{"GREEDER_CREDENTIAL=lm_api_key"}); err != nil || out != "lm_api_key" {
+		t.Fatalf("unexpected command output %q %v", out, err)
+	}
+	if line := credentialCommandLine("linux", "pass show {name}", "lm_api_key"); line != "pass show lm_api_key" {
+		t.Fatalf("expected plain names left alone, got %q", line)
+	}
+	name := "feed.https://x.test/feed?a=1&b='2'.password"
+	if line := credentialCommandLine("windows", "pass show {name}", name); line != `pass show "%GREEDER_CREDENTIAL%"` {
+		t.Fatalf("unexpected windows command line %q", line)
+	}
+	shell, args := shellCommandForOS("linux", credentialCommandLine("linux", "printf %s {name}", name))
+	if out, err := defaultCredentialRun(shell, args, nil); err != nil || out != name {
+		t.Fatalf("expected the name passed as one quoted word, got %q %v", out, err)
+	}
+}
+
+func TestNewAppResolvesCredentials(t *testing.T) {
+	t.Setenv("LM_API_KEY", "")
+	t.Setenv("LM_BASE_URL", "")
+	stubCredentialRun(t, func(name string, args []string, env []string) (string, error) {
+		return "secret-for-" + strings.TrimPrefix(env[0], "GREEDER_CREDENTIAL="), nil
+	})
+	cfg := DefaultConfig()
+	cfg.DBPath = filepath.Join(t.TempDir(), "store.db")
+	cfg.LMBaseURL = "http://example.test"
+	cfg.CredentialCommand = "helper {name}"
+	app, err := NewApp(cfg)
+	if err != nil {
+		t.Fatalf("NewApp error: %v", err)
+	}
+	if app.summarizer.apiKey != "secret-for-lm_api_key" || app.raindrop.token != "secret-for-raindrop_token" {
+		t.Fatalf("expected credentials applied to clients")
+	}
+	checks := app.Doctor()
+	if checks[1].Name != "credentials" || !checks[1].OK {
+		t.Fatalf("expected credentials doctor check: %+v", checks)
+	}
+}
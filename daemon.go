@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type refreshStats struct {
+	Fetched     int
+	Failed      int
+	Skipped     int
+	BackedOff   int
+	NotModified int
+	New         int
+	Duration    time.Duration
+	Results     []feedRefreshResult
+}
+
+const (
+	feedResultOK          = "ok"
+	feedResultNotModified = "not_modified"
+	feedResultFailed      = "failed"
+	feedResultBackedOff   = "backed_off"
+	feedResultSkipped     = "skipped"
+)
+
+type feedRefreshResult struct {
+	FeedID     int    `json:"feed_id"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	Status     string `json:"status"`
+	New        int    `json:"new"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+func parseRefreshIntervalsSection(feedURL string, value string, cfg *Config) error {
+	feedURL = trimQuotes(feedURL)
+	if strings.TrimSpace(feedURL) == "" {
+		return fmt.Errorf("empty feed url in refresh_intervals")
+	}
+	minutes, err := strconv.Atoi(trimQuotes(value))
+	if err != nil || minutes <= 0 {
+		return fmt.Errorf("invalid refresh interval for %s: %q (expected minutes)", feedURL, value)
+	}
+	if cfg.FeedIntervals == nil {
+		cfg.FeedIntervals = map[string]int{}
+	}
+	cfg.FeedIntervals[feedURL] = minutes
+	return nil
+}
+
+func runDaemon(app *App, out io.Writer, stop <-chan struct{}) error {
+	if addr := app.config.MetricsAddr; addr != "" {
+		listening, closeMetrics, err := startMetricsServer(addr, app.metrics)
+		if err != nil {
+			return fmt.Errorf("metrics: %w", err)
+		}
+		defer closeMetrics()
+		fmt.Fprintf(out, "metrics on http://%s/metrics\n", listening)
+	}
+	if addr := app.config.FeverAddr; addr != "" {
+		listening, closeFever, err := startFeverServer(addr, app.store, app.config)
+		if err != nil {
+			return fmt.Errorf("fever: %w", err)
+		}
+		defer closeFever()
+		fmt.Fprintf(out, "fever api on http://%s/?api\n", listening)
+	}
+	reload, stopReload := reloadSignals()
+	defer stopReload()
+	signals, stopSignals := shutdownSignals()
+	defer stopSignals()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopping := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-signals:
+			logFor("daemon").Info("shutting down", "signal", sig)
+			cancel()
+			close(stopping)
+		case <-ctx.Done():
+		}
+	}()
+	fetcher := app.fetcher
+	app.fetcher = fetcher.withContext(ctx)
+	app.scheduled = true
+	defer func() {
+		app.fetcher = fetcher
+		app.scheduled = false
+	}()
+	lastDigest := ""
+	for {
+		app.daemonCycle(out, &lastDigest)
+		select {
+		case <-stopping:
+			fmt.Fprintf(out, "%s shutting down\n", app.now().Format(time.RFC3339))
+			return nil
+		case <-stop:
+			return nil
+		case <-reload:
+			now := app.now().Format(time.RFC3339)
+			if err := app.ReloadConfig(); err != nil {
+				fmt.Fprintf(out, "%s config reload failed: %v\n", now, err)
+			} else {
+				fmt.Fprintf(out, "%s %s\n", now, app.status)
+			}
+		case <-app.after(app.nextRefreshDelay()):
+		}
+	}
+}
+
+func (a *App) refreshInterval() time.Duration {
+	interval := time.Duration(a.config.RefreshIntervalMinutes) * time.Minute
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	return interval
+}
+
+func (a *App) feedInterval(feed Feed) time.Duration {
+	if minutes, ok := a.config.FeedIntervals[feed.URL]; ok {
+		return time.Duration(minutes) * time.Minute
+	}
+	return a.refreshInterval()
+}
+
+func (a *App) feedDue(feed Feed, now time.Time) bool {
+	last, ok := a.lastFetched[feed.ID]
+	return !ok || now.Sub(last) >= a.feedInterval(feed)
+}
+
+func (a *App) nextRefreshDelay() time.Duration {
+	delay := a.refreshInterval()
+	for _, feed := range a.feeds {
+		if interval := a.feedInterval(feed); interval < delay {
+			delay = interval
+		}
+	}
+	if delay < time.Minute {
+		delay = time.Minute
+	}
+	return delay
+}
+
+func (a *App) daemonCycle(out io.Writer, lastDigest *string) {
+	now := a.now()
+	log := logFor("daemon")
+	err := a.RefreshFeeds()
+	a.metrics.recordCycle(now, err)
+	if err != nil {
+		log.Error("refresh failed", "err", err)
+		fmt.Fprintf(out, "%s refresh failed: %v\n", now.Format(time.RFC3339), err)
+		return
+	}
+	a.metrics.queueDepth.Store(int64(a.summaryQueueDepth()))
+	stats := a.lastStats
+	log.Info("cycle", "status", a.status, "new", len(a.lastNew), "fetched", stats.Fetched, "failed", stats.Failed, "skipped", stats.Skipped, "backed_off", stats.BackedOff, "not_modified", stats.NotModified, "duration", stats.Duration)
+	line := fmt.Sprintf("%s %s; %d new articles", now.Format(time.RFC3339), a.status, len(a.lastNew))
+	if stats.Skipped > 0 {
+		line += fmt.Sprintf("; %d feeds not due", stats.Skipped)
+	}
+	fmt.Fprintln(out, line)
+	if err := a.NotifyNewArticles(a.lastNew); err != nil {
+		fmt.Fprintf(out, "%s notify failed: %v\n", now.Format(time.RFC3339), err)
+	}
+	if handled, err := a.HandleTelegramCommands(); err != nil {
+		fmt.Fprintf(out, "%s telegram failed: %v\n", now.Format(time.RFC3339), err)
+	} else if handled > 0 {
+		fmt.Fprintf(out, "%s handled %d telegram commands\n", now.Format(time.RFC3339), handled)
+	}
+	if !a.digestDue(now, *lastDigest) {
+		return
+	}
+	*lastDigest = now.Format("2006-01-02")
+	digest, err := a.GenerateDigest()
+	if err == nil {
+		err = a.DeliverDigest(digest)
+	}
+	if err != nil {
+		log.Error("digest failed", "err", err)
+		fmt.Fprintf(out, "%s digest failed: %v\n", now.Format(time.RFC3339), err)
+	}
+}
+
+func (a *App) digestDue(now time.Time, lastDigest string) bool {
+	if a.config.DigestTime == "" || lastDigest == now.Format("2006-01-02") {
+		return false
+	}
+	at, err := time.ParseInLocation("15:04", a.config.DigestTime, now.Location())
+	if err != nil {
+		return false
+	}
+	return now.Hour()*60+now.Minute() >= at.Hour()*60+at.Minute()
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunDaemon(t *testing.T) {
+	app := newTUIApp(t)
+	if _, err := app.store.InsertFeed(Feed{Title: "Sample RSS", URL: "http://example.test/rss"}); err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	app.fetcher.client = clientForResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"})
+	requests := []notifyRequest{}
+	app.notifiers = []*Notifier{{config: NotifierConfig{Name: "hook", Type: "slack", WebhookURL: "http://hooks.test"}, client: notifyTestClient(&requests, http.StatusOK)}}
+
+	stop := make(chan struct{})
+	cycles := 0
+	clock := &testClock{now: time.Date(2026, 10, 15, 9, 0, 0, 0, time.Local)}
+	app.setClock(clock)
+	clock.after = func(d time.Duration) <-chan time.Time {
+		if d != 30*time.Minute {
+			t.Fatalf("unexpected interval %v", d)
+		}
+		cycles++
+		if cycles == 2 {
+			close(stop)
+			return nil
+		}
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+		return ch
+	}
+	var out bytes.Buffer
+	if err := runDaemon(app, &out, stop); err != nil {
+		t.Fatalf("runDaemon error: %v", err)
+	}
+	if cycles != 2 || !strings.Contains(out.String(), "refreshed 1 feeds; 1 new articles") || !strings.Contains(out.String(), "; 0 new articles") {
+		t.Fatalf("unexpected daemon output: %s", out.String())
+	}
+	if len(requests) != 1 || !strings.Contains(requests[0].body["text"], "Item One") {
+		t.Fatalf("expected one new-article notification, got %+v", requests)
+	}
+}
+
+func TestDaemonCycleDigest(t *testing.T) {
+	app := seedDigestApp(t)
+	app.summarizer = digestSummarizer(t, nil)
+	requests := []notifyRequest{}
+	app.notifiers = []*Notifier{{config: NotifierConfig{Name: "hook", Type: "slack", WebhookURL: "http://hooks.test", Events: []string{notifyEventDigest}}, client: notifyTestClient(&requests, http.StatusOK)}}
+	app.config.DigestTime = "08:30"
+	clock := &testClock{now: time.Date(2026, 10, 15, 8, 0, 0, 0, time.Local)}
+	app.setClock(clock)
+
+	var out bytes.Buffer
+	lastDigest := ""
+	app.daemonCycle(&out, &lastDigest)
+	if len(requests) != 0 || lastDigest != "" {
+		t.Fatalf("expected no digest before digest_time")
+	}
+	clock.now = clock.now.Add(time.Hour)
+	app.daemonCycle(&out, &lastDigest)
+	if len(requests) != 1 || lastDigest != "2026-10-15" || !strings.Contains(requests[0].body["text"], "Things happened.") {
+		t.Fatalf("expected digest notification, got %+v", requests)
+	}
+	app.daemonCycle(&out, &lastDigest)
+	if len(requests) != 1 {
+		t.Fatalf("expected one digest per day")
+	}
+
+	app.summarizer = nil
+	lastDigest = ""
+	app.daemonCycle(&out, &lastDigest)
+	if !strings.Contains(out.String(), "digest failed") {
+		t.Fatalf("expected digest failure in output: %s", out.String())
+	}
+	app.config.DigestTime = "late"
+	if app.digestDue(clock.now, "") {
+		t.Fatalf("expected invalid digest_time to never be due")
+	}
+}
+
+func TestParseConfigRefreshIntervals(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("[refresh_intervals]\n\"https://news.example.com/rss\" = 5\n\"https://slow.example.com/atom\" = 720\n", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.FeedIntervals["https://news.example.com/rss"] != 5 || cfg.FeedIntervals["https://slow.example.com/atom"] != 720 {
+		t.Fatalf("unexpected intervals %+v", cfg.FeedIntervals)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil || len(reparsed.FeedIntervals) != 2 {
+		t.Fatalf("intervals did not round trip: %+v %v", reparsed.FeedIntervals, err)
+	}
+	for _, bad := range []string{"[refresh_intervals]\n\"https://x.test\" = 0", "[refresh_intervals]\n\"https://x.test\" = soon"} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestDaemonPerFeedIntervals(t *testing.T) {
+	app := newTUIApp(t)
+	for _, url := range []string{"http://fast.test/rss", "http://slow.test/rss"} {
+		if _, err := app.store.InsertFeed(Feed{Title: url, URL: url}); err != nil {
+			t.Fatalf("InsertFeed error: %v", err)
+		}
+	}
+	app.feeds = app.store.Feeds()
+	app.config.FeedIntervals = map[string]int{"http://fast.test/rss": 5}
+	fetched := []string{}
+	var mu sync.Mutex
+	app.fetcher.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetched = append(fetched, r.URL.Host)
+		return newResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"}, r), nil
+	})}
+	clock := &testClock{now: time.Date(2026, 10, 15, 9, 0, 0, 0, time.Local)}
+	app.setClock(clock)
+	app.scheduled = true
+	if app.nextRefreshDelay() != 5*time.Minute {
+		t.Fatalf("expected the shortest interval as the wake-up delay, got %v", app.nextRefreshDelay())
+	}
+
+	var out bytes.Buffer
+	lastDigest := ""
+	app.daemonCycle(&out, &lastDigest)
+	if len(fetched) != 2 || app.lastStats.Fetched != 2 || app.lastStats.Skipped != 0 {
+		t.Fatalf("expected every feed on the first cycle, got %v %+v", fetched, app.lastStats)
+	}
+	fetched = nil
+	clock.now = clock.now.Add(5 * time.Minute)
+	app.daemonCycle(&out, &lastDigest)
+	if len(fetched) != 1 || fetched[0] != "fast.test" || app.lastStats.Skipped != 1 {
+		t.Fatalf("expected only the fast feed, got %v %+v", fetched, app.lastStats)
+	}
+	if !strings.Contains(out.String(), "; 1 feeds not due") {
+		t.Fatalf("expected skipped feeds in output: %s", out.String())
+	}
+	fetched = nil
+	app.scheduled = false
+	if err := app.RefreshFeeds(); err != nil || len(fetched) != 2 {
+		t.Fatalf("expected manual refresh to fetch everything, got %v %v", fetched, err)
+	}
+}
+
+func TestRunDaemonStopsOnSignal(t *testing.T) {
+	app := newTUIApp(t)
+	signals := make(chan os.Signal, 1)
+	orig := shutdownSignals
+	shutdownSignals = func() (<-chan os.Signal, func()) { return signals, func() {} }
+	t.Cleanup(func() { shutdownSignals = orig })
+	clock := &testClock{now: time.Date(2026, 10, 15, 9, 0, 0, 0, time.Local)}
+	app.setClock(clock)
+	clock.after = func(time.Duration) <-chan time.Time {
+		signals <- syscall.SIGTERM
+		return nil
+	}
+	var out bytes.Buffer
+	if err := runDaemon(app, &out, nil); err != nil {
+		t.Fatalf("runDaemon error: %v", err)
+	}
+	if !strings.Contains(out.String(), "shutting down") || app.scheduled {
+		t.Fatalf("expected graceful shutdown, got %q", out.String())
+	}
+}
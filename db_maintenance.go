@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+func runDBMaintenance(cfg Config, stdout io.Writer) error {
+	store, err := NewStore(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	report, err := store.Maintain()
+	if err != nil {
+		return err
+	}
+	for _, line := range formatDBMaintenance(report) {
+		fmt.Fprintln(stdout, line)
+	}
+	if len(report.Problems) > 0 {
+		return messageErr(msgDBIntegrityFailed, len(report.Problems))
+	}
+	return nil
+}
+
+func formatDBMaintenance(report dbMaintenanceReport) []string {
+	if len(report.Problems) > 0 {
+		lines := []string{tr(msgDBIntegrityFailed, len(report.Problems))}
+		for _, problem := range report.Problems {
+			lines = append(lines, "  "+problem)
+		}
+		return append(lines, tr(msgDBMaintenanceSkipped))
+	}
+	return []string{
+		tr(msgDBIntegrityOK),
+		tr(msgDBMaintenanceSize, megabytes(report.SizeBefore), megabytes(report.SizeAfter), megabytes(report.SizeBefore-report.SizeAfter)),
+	}
+}
+
+func megabytes(size int64) string {
+	return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+}
+
+func (a *App) warnIfDBDamaged() {
+	problems, err := a.store.CheckIntegrity()
+	if err != nil {
+		logFor("store").Error("integrity check failed", "err", err)
+		return
+	}
+	if len(problems) > 0 {
+		logFor("store").Error("database damaged", "problems", len(problems), "first", problems[0])
+		a.status = tr(msgDBDamaged, len(problems))
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"database/sql"
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	fingerprintMinWords = 30
+	fingerprintShingle  = 3
+	nearDuplicateWindow = 7 * 24 * time.Hour
+	fingerprintBits     = 64
+)
+
+func articleFingerprint(title string, contentText string, content string) int64 {
+	text := firstNonEmpty(contentText, stripHTML(content))
+	words := strings.FieldsFunc(strings.ToLower(title+" "+text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) < fingerprintMinWords {
+		return 0
+	}
+	var weights [fingerprintBits]int
+	for i := 0; i+fingerprintShingle <= len(words); i++ {
+		hash := fnv.New64a()
+		hash.Write([]byte(strings.Join(words[i:i+fingerprintShingle], " ")))
+		sum := hash.Sum64()
+		for bit := 0; bit < fingerprintBits; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << bit
+		}
+	}
+	return int64(fingerprint)
+}
+
+func fingerprintSimilarity(a int64, b int64) float64 {
+	return 1 - float64(bits.OnesCount64(uint64(a^b)))/fingerprintBits
+}
+
+func backfillFingerprints(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, title, content, content_text FROM articles WHERE fingerprint IS NULL`)
+	if err != nil {
+		return err
+	}
+	fingerprints := map[int]int64{}
+	for rows.Next() {
+		var id int
+		var title, content, contentText string
+		if err := rows.Scan(&id, &title, &content, &contentText); err != nil {
+			rows.Close()
+			return err
+		}
+		fingerprints[id] = articleFingerprint(title, contentText, content)
+	}
+	rows.Close()
+	for id, fingerprint := range fingerprints {
+		if _, err := tx.Exec(`UPDATE articles SET fingerprint = ? WHERE id = ?`, fingerprint, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mergeNearDuplicates(tx *sql.Tx, similarity float64) error {
+	if err := backfillFingerprints(tx); err != nil {
+		return err
+	}
+	rows, err := tx.Query(`SELECT id, feed_id, published_at, is_read, is_starred, fingerprint FROM articles WHERE fingerprint != 0 ORDER BY COALESCE(published_at, 0), id`)
+	if err != nil {
+		return err
+	}
+	type candidate struct {
+		state       articleMergeState
+		fingerprint int64
+	}
+	all := []candidate{}
+	for rows.Next() {
+		var c candidate
+		var isRead, isStarred int
+		if err := rows.Scan(&c.state.id, &c.state.feedID, &c.state.publishedAt, &isRead, &isStarred, &c.fingerprint); err != nil {
+			rows.Close()
+			return err
+		}
+		c.state.isRead = isRead != 0
+		c.state.isStarred = isStarred != 0
+		all = append(all, c)
+	}
+	rows.Close()
+
+	window := []*candidate{}
+	for i := range all {
+		current := &all[i]
+		published := timeFromUnix(current.state.publishedAt)
+		kept := window[:0]
+		for _, earlier := range window {
+			if published.Sub(timeFromUnix(earlier.state.publishedAt)) <= nearDuplicateWindow {
+				kept = append(kept, earlier)
+			}
+		}
+		window = kept
+		var original *candidate
+		for _, earlier := range window {
+			if earlier.state.feedID != current.state.feedID && fingerprintSimilarity(earlier.fingerprint, current.fingerprint) >= similarity {
+				original = earlier
+				break
+			}
+		}
+		if original == nil {
+			window = append(window, current)
+			continue
+		}
+		if err := mergeArticleInto(tx, &original.state, current.state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+const syndicatedText = "The city council voted on Tuesday to expand the bike lane network across the downtown core, " +
+	"adding twelve kilometres of protected lanes over the next two years. Supporters said the plan would make " +
+	"commuting safer while local businesses raised concerns about parking and deliveries during construction."
+
+func TestArticleFingerprint(t *testing.T) {
+	original := articleFingerprint("Council expands bike lanes", syndicatedText, "")
+	republished := articleFingerprint("Council expands bike lanes", "", "<p>"+strings.Replace(syndicatedText, "Tuesday", "Tuesday evening", 1)+"</p>")
+	unrelated := articleFingerprint("Quarterly earnings beat expectations", strings.Repeat("shares rallied after the company reported record revenue from cloud services ", 3), "")
+	if original == 0 || republished == 0 || unrelated == 0 {
+		t.Fatalf("expected fingerprints, got %d %d %d", original, republished, unrelated)
+	}
+	if similarity := fingerprintSimilarity(original, republished); similarity < 0.85 {
+		t.Fatalf("expected republished copy to be similar, got %.2f", similarity)
+	}
+	if similarity := fingerprintSimilarity(original, unrelated); similarity >= 0.85 {
+		t.Fatalf("expected unrelated article to differ, got %.2f", similarity)
+	}
+	if got := articleFingerprint("Short", "too few words here", ""); got != 0 {
+		t.Fatalf("expected no fingerprint for short text, got %d", got)
+	}
+}
+
+func TestMergeDuplicateArticlesNearDuplicates(t *testing.T) {
+	store, _ := newWritableStore(t)
+	feedA, _ := store.InsertFeed(Feed{Title: "Wire", URL: "https://wire.example.com/rss"})
+	feedB, _ := store.InsertFeed(Feed{Title: "Local", URL: "https://local.example.com/rss"})
+	published := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	if _, err := store.InsertArticles(feedA, []Article{
+		{GUID: "wire-1", Title: "Council expands bike lanes", URL: "https://wire.example.com/story/1", ContentText: syndicatedText, PublishedAt: published},
+		{GUID: "wire-2", Title: "Council expands bike lanes", URL: "https://wire.example.com/story/1-update", ContentText: syndicatedText, PublishedAt: published.Add(time.Hour)},
+	}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := store.InsertArticles(feedB, []Article{
+		{GUID: "local-1", Title: "Council expands bike lanes", URL: "https://local.example.com/news/bikes", ContentText: syndicatedText, PublishedAt: published.Add(2 * time.Hour), IsStarred: true},
+		{GUID: "local-2", Title: "Council expands bike lanes", URL: "https://local.example.com/archive/bikes", ContentText: syndicatedText, PublishedAt: published.Add(30 * 24 * time.Hour)},
+	}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+
+	if err := store.MergeDuplicateArticles(); err != nil {
+		t.Fatalf("MergeDuplicateArticles error: %v", err)
+	}
+	if got := len(store.SortedArticles()); got != 4 {
+		t.Fatalf("expected similarity dedup disabled by default, got %d articles", got)
+	}
+
+	store.dedupSimilarity = 0.9
+	if err := store.MergeDuplicateArticles(); err != nil {
+		t.Fatalf("MergeDuplicateArticles error: %v", err)
+	}
+	articles := store.SortedArticles()
+	if len(articles) != 3 {
+		t.Fatalf("expected the syndicated copy merged, got %+v", articles)
+	}
+	var kept Article
+	for _, article := range articles {
+		if article.GUID == "wire-1" {
+			kept = article
+		}
+		if article.GUID == "local-1" {
+			t.Fatalf("expected local copy merged into the original")
+		}
+	}
+	if !kept.IsStarred {
+		t.Fatalf("expected starred state carried over, got %+v", kept)
+	}
+	if sources := store.ArticleSources(kept.ID); len(sources) != 2 {
+		t.Fatalf("expected sources for both feeds, got %+v", sources)
+	}
+}
+
+func TestConfigDedupSimilarity(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("dedup_similarity = 0.92", &cfg); err != nil || cfg.DedupSimilarity != 0.92 {
+		t.Fatalf("unexpected dedup_similarity %v %v", cfg.DedupSimilarity, err)
+	}
+	if !strings.Contains(renderConfig(cfg), "dedup_similarity = 0.92") {
+		t.Fatalf("expected dedup_similarity rendered")
+	}
+	if strings.Contains(renderConfig(DefaultConfig()), "dedup_similarity") {
+		t.Fatalf("expected dedup_similarity omitted by default")
+	}
+	for _, value := range []string{"0.5", "1.5", "high"} {
+		if err := parseConfig("dedup_similarity = "+value, &cfg); err == nil || !strings.Contains(err.Error(), "invalid dedup_similarity") {
+			t.Fatalf("expected error for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestMergeDuplicateArticlesCarriesArticleState(t *testing.T) {
+	store, _ := newWritableStore(t)
+	store.dedupSimilarity = 0.9
+	feedA, _ := store.InsertFeed(Feed{Title: "Wire", URL: "https://wire.example.com/rss"})
+	feedB, _ := store.InsertFeed(Feed{Title: "Local", URL: "https://local.example.com/rss"})
+	published := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	if _, err := store.InsertArticles(feedA, []Article{{GUID: "wire-1", Title: "Council expands bike lanes", URL: "https://wire.example.com/story/1", ContentText: syndicatedText, PublishedAt: published}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := store.InsertArticles(feedB, []Article{{GUID: "local-1", Title: "Council expands bike lanes", URL: "https://local.example.com/news/bikes", ContentText: syndicatedText, PublishedAt: published.Add(time.Hour)}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	ids := map[string]int{}
+	for _, article := range store.SortedArticles() {
+		ids[article.GUID] = article.ID
+	}
+	kept, dup := ids["wire-1"], ids["local-1"]
+	for _, step := range []error{
+		store.SetArticleTags(kept, tagSourceUser, []string{"city"}),
+		store.SetArticleTags(dup, tagSourceUser, []string{"city", "bikes"}),
+		store.QueueArticle(dup),
+		store.QueueArticle(kept),
+		store.SetSyncState(kept, "miniflux", "10", false, false),
+		store.SetSyncState(dup, "miniflux", "11", false, false),
+		store.SetWaybackSnapshot(dup, "https://web.archive.org/local"),
+	} {
+		if step != nil {
+			t.Fatalf("setup error: %v", step)
+		}
+	}
+
+	if err := store.MergeDuplicateArticles(); err != nil {
+		t.Fatalf("MergeDuplicateArticles error: %v", err)
+	}
+	if len(store.SortedArticles()) != 1 {
+		t.Fatalf("expected the copies merged")
+	}
+	if tags := store.ArticleTagsFrom(kept, tagSourceUser); strings.Join(tags, ",") != "bikes,city" {
+		t.Fatalf("expected tags merged, got %v", tags)
+	}
+	if positions := store.QueuePositions(); len(positions) != 1 || positions[kept] != 1 {
+		t.Fatalf("expected the earlier queue position kept, got %v", positions)
+	}
+	if snapshot := store.WaybackSnapshot(kept); snapshot != "https://web.archive.org/local" {
+		t.Fatalf("expected snapshot moved, got %q", snapshot)
+	}
+	var remoteID string
+	if err := store.db.QueryRow(`SELECT remote_id FROM sync_items WHERE article_id = ?`, kept).Scan(&remoteID); err != nil || remoteID != "10" {
+		t.Fatalf("expected the kept sync item, got %q %v", remoteID, err)
+	}
+	for _, table := range []string{"article_tags", "sync_items", "wayback_snapshots", "article_sources"} {
+		var orphans int
+		if err := store.db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE article_id = ?`, dup).Scan(&orphans); err != nil || orphans != 0 {
+			t.Fatalf("expected no %s rows left for the duplicate, got %d %v", table, orphans, err)
+		}
+	}
+
+	if _, err := store.InsertArticles(feedA, []Article{{GUID: "wire-1", Title: "Council expands bike lanes", URL: "https://wire.example.com/story/1", ContentText: "Rewritten text."}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	var fingerprint sql.NullInt64
+	if err := store.db.QueryRow(`SELECT fingerprint FROM articles WHERE id = ?`, kept).Scan(&fingerprint); err != nil || fingerprint.Valid {
+		t.Fatalf("expected refreshed content to clear the fingerprint, got %v %v", fingerprint, err)
+	}
+}
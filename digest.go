@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Digest struct {
+	ID          int
+	Content     string
+	Model       string
+	GeneratedAt time.Time
+	Articles    []Article
+}
+
+type DigestOptions struct {
+	Group  string
+	Topics map[int][]string
+}
+
+const (
+	digestGroupTopic = "topic"
+	digestGroupFeed  = "feed"
+)
+
+type digestArgs struct {
+	hours int
+	email bool
+	path  string
+}
+
+var digestWriteFile = os.WriteFile
+
+func (s *Summarizer) GenerateDigest(articles []Article, summaries map[int]string, opts DigestOptions) (Summary, error) {
+	if s == nil {
+		return Summary{}, errors.New("summarizer not configured")
+	}
+	if len(articles) == 0 {
+		return Summary{}, errors.New("no articles for digest")
+	}
+	return s.complete(s.withLanguage(digestSystemPrompt(opts.Group)), buildDigestPrompt(articles, summaries, opts.Topics))
+}
+
+func digestSystemPrompt(group string) string {
+	grouping := "Group related stories by topic under short markdown headings and write 1-3 sentences per group.\n"
+	if group == digestGroupFeed {
+		grouping = "Group the stories by the feed they came from, with one markdown heading per feed, and write 1-3 sentences per story.\n"
+	}
+	return "You write a concise daily news briefing from a list of article headlines and summaries.\n" +
+		grouping +
+		"Mention the most important developments first and do not invent facts that are not in the input.\n" +
+		"Output ONLY the briefing - no introductions or sign-offs."
+}
+
+func buildDigestPrompt(articles []Article, summaries map[int]string, topics map[int][]string) string {
+	lines := []string{"Today's articles:", ""}
+	for i, article := range articles {
+		lines = append(lines, "<article>", fmt.Sprintf("%d. %s (%s)", i+1, sanitizeUntrusted(article.Title), valueOrFallback(article.FeedTitle, "Unknown feed")))
+		if len(topics[article.ID]) > 0 {
+			lines = append(lines, "Topics: "+sanitizeUntrusted(strings.Join(topics[article.ID], ", ")))
+		}
+		if summary := strings.TrimSpace(summaries[article.ID]); summary != "" {
+			lines = append(lines, sanitizeUntrusted(summary))
+		} else if text := firstNonEmpty(article.ContentText, article.Content); text != "" {
+			lines = append(lines, sanitizeUntrusted(truncateText(text, 500)))
+		}
+		lines = append(lines, "</article>", "")
+	}
+	return truncateText(strings.Join(lines, "\n"), 20000)
+}
+
+func parseDigestArgs(args []string) (digestArgs, error) {
+	opts := digestArgs{}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--email":
+			opts.email = true
+		case "--hours":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("missing value for %s", args[i])
+			}
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil || value <= 0 {
+				return opts, fmt.Errorf("invalid %s: %s", args[i], args[i+1])
+			}
+			opts.hours = value
+			i++
+		default:
+			if strings.HasPrefix(args[i], "--") || opts.path != "" {
+				return opts, fmt.Errorf("unknown digest option: %s", args[i])
+			}
+			opts.path = args[i]
+		}
+	}
+	return opts, nil
+}
+
+func digestArticles(articles []Article, since time.Time) []Article {
+	items := []Article{}
+	for _, article := range articles {
+		if article.IsRead {
+			continue
+		}
+		if article.PublishedAt.Before(since) && article.FetchedAt.Before(since) {
+			continue
+		}
+		items = append(items, article)
+	}
+	return items
+}
+
+func startOfDay(value time.Time) time.Time {
+	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, value.Location())
+}
+
+func (a *App) digestSince() time.Time {
+	if a.config.DigestHours > 0 {
+		return a.now().Add(-time.Duration(a.config.DigestHours) * time.Hour)
+	}
+	return a.today()
+}
+
+func (a *App) DigestCandidates() ([]Article, map[int]string) {
+	articles := digestArticles(a.allArticles(), a.digestSince())
+	summaries := map[int]string{}
+	for _, article := range articles {
+		if summary, ok := a.store.FindSummary(article.ID); ok {
+			summaries[article.ID] = summary.Content
+		}
+	}
+	return articles, summaries
+}
+
+func (a *App) digestOptions(articles []Article) DigestOptions {
+	opts := DigestOptions{Group: a.config.DigestGroup, Topics: map[int][]string{}}
+	for _, article := range articles {
+		if topics := a.store.ArticleTagsFrom(article.ID, tagSourceTopic); len(topics) > 0 {
+			opts.Topics[article.ID] = topics
+		}
+	}
+	return opts
+}
+
+func (a *App) GenerateDigest() (Digest, error) {
+	if a.summarizer == nil {
+		a.status = tr(msgSummarizerMissing)
+		return Digest{}, errors.New("summarizer not configured")
+	}
+	articles, summaries := a.DigestCandidates()
+	if len(articles) == 0 {
+		a.status = tr(msgDigestNoUnread)
+		return Digest{}, errors.New("no articles for digest")
+	}
+	result, err := a.summarizer.GenerateDigest(articles, summaries, a.digestOptions(articles))
+	if err != nil {
+		a.status = failureStatus(msgActionDigest, err)
+		return Digest{}, err
+	}
+	a.status = tr(msgDigestGenerated, len(articles))
+	digest := Digest{Content: result.Content, Model: result.Model, GeneratedAt: a.now().UTC(), Articles: articles}
+	if stored, err := a.store.SaveDigest(digest); err != nil {
+		logFor("digest").Warn("store digest failed", "err", err)
+	} else {
+		digest = stored
+	}
+	return digest, nil
+}
+
+func (a *App) EmailDigest(digest Digest) error {
+	if strings.TrimSpace(digest.Content) == "" {
+		return errors.New("empty digest")
+	}
+	return a.deliverEmail(digestEmail(digest, a.config))
+}
+
+func (a *App) DeliverDigest(digest Digest) error {
+	errs := []error{a.NotifyDigest(digest)}
+	if a.config.DigestPath != "" {
+		errs = append(errs, WriteDigest(digestFilePath(a.config.DigestPath, digest), digest))
+	}
+	if a.config.DigestEmail {
+		errs = append(errs, a.EmailDigest(digest))
+	}
+	return errors.Join(errs...)
+}
+
+func digestFilePath(path string, digest Digest) string {
+	return strings.ReplaceAll(expandHome(path), "{date}", digest.GeneratedAt.In(time.Local).Format("2006-01-02"))
+}
+
+func WriteDigest(path string, digest Digest) error {
+	if strings.TrimSpace(path) == "" {
+		return errors.New("missing digest path")
+	}
+	return digestWriteFile(path, []byte(renderDigestMarkdown(digest)), 0o644)
+}
+
+func digestTitle(digest Digest) string {
+	return "Daily Digest - " + digest.GeneratedAt.In(time.Local).Format("2006-01-02")
+}
+
+func renderDigestMarkdown(digest Digest) string {
+	lines := []string{"# " + digestTitle(digest), "", strings.TrimSpace(digest.Content), ""}
+	if len(digest.Articles) > 0 {
+		lines = append(lines, "## Articles", "")
+		for _, article := range digest.Articles {
+			lines = append(lines, fmt.Sprintf("- [%s](%s) - %s", article.Title, article.URL, valueOrFallback(article.FeedTitle, "Unknown")))
+		}
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func buildDigestMailto(digest Digest) string {
+	return digestEmail(digest, Config{}).mailto()
+}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func digestSummarizer(t *testing.T, captured *string) *Summarizer {
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if captured != nil {
+			body, _ := io.ReadAll(r.Body)
+			*captured = string(body)
+		}
+		return newResponse(http.StatusOK, `{"choices":[{"message":{"content":"## Tech\nThings happened."}}]}`, map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	return &Summarizer{baseURL: "http://example.test", model: "digest-model", client: client}
+}
+
+func seedDigestApp(t *testing.T) *App {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	now := time.Now().UTC()
+	articles, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "Fresh", URL: "https://example.com/1", ContentText: "fresh body", PublishedAt: now},
+		{GUID: "2", Title: "Old", URL: "https://example.com/2", PublishedAt: now.AddDate(0, 0, -3), FetchedAt: now.AddDate(0, 0, -3)},
+		{GUID: "3", Title: "Read", URL: "https://example.com/3", PublishedAt: now, IsRead: true},
+	})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "- fresh summary"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	return app
+}
+
+func TestDigestArticlesFiltersUnreadToday(t *testing.T) {
+	since := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	articles := []Article{
+		{ID: 1, PublishedAt: since.Add(time.Hour)},
+		{ID: 2, PublishedAt: since.Add(-time.Hour), FetchedAt: since.Add(time.Hour)},
+		{ID: 3, PublishedAt: since.Add(-time.Hour), FetchedAt: since.Add(-time.Hour)},
+		{ID: 4, PublishedAt: since.Add(time.Hour), IsRead: true},
+	}
+	got := digestArticles(articles, since)
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 2 {
+		t.Fatalf("unexpected digest articles: %+v", got)
+	}
+}
+
+func TestBuildDigestPrompt(t *testing.T) {
+	prompt := buildDigestPrompt([]Article{
+		{ID: 1, Title: "One", FeedTitle: "Feed"},
+		{ID: 2, Title: "Two", ContentText: "body text"},
+		{ID: 3, Title: "Three"},
+	}, map[int]string{1: "- summary"}, nil)
+	for _, want := range []string{"1. One (Feed)", "- summary", "2. Two (Unknown feed)", "body text", "3. Three"} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("prompt missing %q: %s", want, prompt)
+		}
+	}
+}
+
+func TestSummarizerGenerateDigest(t *testing.T) {
+	var nilSummarizer *Summarizer
+	if _, err := nilSummarizer.GenerateDigest([]Article{{Title: "x"}}, nil, DigestOptions{}); err == nil {
+		t.Fatalf("expected nil summarizer error")
+	}
+	var body string
+	s := digestSummarizer(t, &body)
+	if _, err := s.GenerateDigest(nil, nil, DigestOptions{}); err == nil {
+		t.Fatalf("expected empty digest error")
+	}
+	result, err := s.GenerateDigest([]Article{{ID: 1, Title: "Headline"}}, nil, DigestOptions{})
+	if err != nil {
+		t.Fatalf("GenerateDigest error: %v", err)
+	}
+	if result.Model != "digest-model" || !strings.Contains(result.Content, "Things happened") {
+		t.Fatalf("unexpected digest result: %+v", result)
+	}
+	if !strings.Contains(body, "Headline") || !strings.Contains(body, "daily news briefing") {
+		t.Fatalf("unexpected digest request: %s", body)
+	}
+}
+
+func TestAppGenerateDigest(t *testing.T) {
+	app := seedDigestApp(t)
+	if _, err := app.GenerateDigest(); err == nil || app.status != tr(msgSummarizerMissing) {
+		t.Fatalf("expected no config error")
+	}
+	var body string
+	app.summarizer = digestSummarizer(t, &body)
+	digest, err := app.GenerateDigest()
+	if err != nil {
+		t.Fatalf("GenerateDigest error: %v", err)
+	}
+	if len(digest.Articles) != 1 || digest.Articles[0].Title != "Fresh" {
+		t.Fatalf("unexpected digest articles: %+v", digest.Articles)
+	}
+	if !strings.Contains(body, "fresh summary") || strings.Contains(body, "Old") {
+		t.Fatalf("unexpected digest prompt: %s", body)
+	}
+
+	var mailto string
+	app.emailSender = func(target string) error {
+		mailto = target
+		return nil
+	}
+	if err := app.EmailDigest(digest); err != nil {
+		t.Fatalf("EmailDigest error: %v", err)
+	}
+	parsed, err := url.Parse(mailto)
+	if err != nil {
+		t.Fatalf("parse mailto: %v", err)
+	}
+	if !strings.HasPrefix(parsed.Query().Get("subject"), "Daily Digest") || !strings.Contains(parsed.Query().Get("body"), "Things happened") {
+		t.Fatalf("unexpected mailto: %s", mailto)
+	}
+	if err := app.EmailDigest(Digest{}); err == nil {
+		t.Fatalf("expected empty digest error")
+	}
+
+	app.summarizer = &Summarizer{baseURL: "http://example.test", model: "m", client: clientForResponse(http.StatusInternalServerError, "", nil)}
+	if _, err := app.GenerateDigest(); err == nil || !strings.HasPrefix(app.status, tr(msgActionDigest)) {
+		t.Fatalf("expected digest failure status, got %q", app.status)
+	}
+
+	for i := range app.articles {
+		app.articles[i].IsRead = true
+	}
+	if _, err := app.GenerateDigest(); err == nil || app.status != tr(msgDigestNoUnread) {
+		t.Fatalf("expected no articles status, got %q", app.status)
+	}
+}
+
+func TestWriteDigest(t *testing.T) {
+	digest := Digest{
+		Content:     "Briefing",
+		GeneratedAt: time.Date(2026, 10, 15, 12, 0, 0, 0, time.Local),
+		Articles:    []Article{{Title: "One", URL: "https://example.com/1", FeedTitle: "Feed"}},
+	}
+	path := filepath.Join(t.TempDir(), "digest.md")
+	if err := WriteDigest(path, digest); err != nil {
+		t.Fatalf("WriteDigest error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read digest: %v", err)
+	}
+	for _, want := range []string{"# Daily Digest - 2026-10-15", "Briefing", "- [One](https://example.com/1) - Feed"} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("digest missing %q: %s", want, data)
+		}
+	}
+	if err := WriteDigest(" ", digest); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+	orig := digestWriteFile
+	digestWriteFile = func(string, []byte, os.FileMode) error { return errors.New("write fail") }
+	t.Cleanup(func() { digestWriteFile = orig })
+	if err := WriteDigest(path, digest); err == nil {
+		t.Fatalf("expected write error")
+	}
+}
+
+func TestTUIDigestFlow(t *testing.T) {
+	app := seedDigestApp(t)
+	model := newTUIModel(app)
+	model.width = 100
+	model.height = 30
+	if cmd := model.startDigest(); cmd != nil || app.status != tr(msgSummarizerMissing) {
+		t.Fatalf("expected no config status")
+	}
+	app.summarizer = digestSummarizer(t, nil)
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	model = updated.(tuiModel)
+	if cmd == nil || !model.digestPending {
+		t.Fatalf("expected digest command")
+	}
+	if again := model.startDigest(); again != nil {
+		t.Fatalf("expected no duplicate digest command")
+	}
+	if !strings.Contains(model.renderStatusBar(100), "Generating digest") {
+		t.Fatalf("expected pending digest status")
+	}
+	updated, _ = model.Update(cmd())
+	model = updated.(tuiModel)
+	if !model.showDigest || model.digestPending || !strings.Contains(model.View(), "Things happened") {
+		t.Fatalf("expected digest overlay")
+	}
+	for _, key := range []string{"j", "k", "k"} {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		model = updated.(tuiModel)
+	}
+	if model.digestScroll != 0 {
+		t.Fatalf("expected digest scroll reset, got %d", model.digestScroll)
+	}
+	emailed := false
+	app.emailSender = func(string) error {
+		emailed = true
+		return errors.New("no mail client")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	model = updated.(tuiModel)
+	if !emailed || app.status != tr(msgDigestEmailFailed, errors.New("no mail client")) {
+		t.Fatalf("expected digest email attempt")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(tuiModel)
+	if model.showDigest {
+		t.Fatalf("expected digest overlay closed")
+	}
+
+	updated, _ = model.Update(digestResultMsg{err: errors.New("boom")})
+	model = updated.(tuiModel)
+	if model.showDigest || app.status != failureStatus(msgActionDigest, errors.New("boom")) {
+		t.Fatalf("expected digest error status")
+	}
+
+	for i := range app.articles {
+		app.articles[i].IsRead = true
+	}
+	if cmd := model.startDigest(); cmd != nil || app.status != tr(msgDigestNoUnread) {
+		t.Fatalf("expected no articles status")
+	}
+}
+
+func TestDigestCmdError(t *testing.T) {
+	s := &Summarizer{baseURL: "http://example.test", model: "m", client: clientForResponse(http.StatusBadGateway, "", nil)}
+	msg := digestCmd([]Article{{Title: "x"}}, nil, DigestOptions{}, s, systemClock{})()
+	if result, ok := msg.(digestResultMsg); !ok || result.err == nil {
+		t.Fatalf("expected digest error message")
+	}
+}
+
+func TestRunMainDigest(t *testing.T) {
+	root := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", root)
+	os.Setenv("XDG_DATA_HOME", root)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	defer os.Unsetenv("XDG_DATA_HOME")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	os.Unsetenv("LM_BASE_URL")
+	if err := runMain([]string{"--digest"}, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Fatalf("expected digest error without summarizer")
+	}
+	if !strings.Contains(stderr.String(), "digest error") {
+		t.Fatalf("expected digest error output")
+	}
+	stderr.Reset()
+	if err := runMain([]string{"digest", "--hours", "x"}, strings.NewReader(""), &stdout, &stderr); err == nil || !strings.Contains(stderr.String(), "invalid --hours: x") {
+		t.Fatalf("expected digest option error, got %v %q", err, stderr.String())
+	}
+}
+
+func TestHandleCommandDigest(t *testing.T) {
+	app := seedDigestApp(t)
+	var out bytes.Buffer
+	if err := handleCommand(app, "D", &out); err == nil || err.Error() != "summarizer not configured" {
+		t.Fatalf("expected digest without summarizer to fail, got %v", err)
+	}
+	app.summarizer = digestSummarizer(t, nil)
+	if err := handleCommand(app, "digest", &out); err != nil {
+		t.Fatalf("digest command error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Things happened") {
+		t.Fatalf("expected digest output: %s", out.String())
+	}
+	path := filepath.Join(t.TempDir(), "digest.md")
+	if err := handleCommand(app, "D "+path, &out); err != nil {
+		t.Fatalf("digest write error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected digest file: %v", err)
+	}
+}
+
+func TestDigestPromptGrouping(t *testing.T) {
+	if !strings.Contains(digestSystemPrompt(""), "by topic") || !strings.Contains(digestSystemPrompt(digestGroupFeed), "by the feed") {
+		t.Fatalf("unexpected grouping prompts")
+	}
+	prompt := buildDigestPrompt([]Article{{ID: 1, Title: "One"}, {ID: 2, Title: "Two"}}, nil, map[int][]string{1: {"go", "databases"}})
+	if !strings.Contains(prompt, "1. One (Unknown feed)\nTopics: go, databases") || strings.Count(prompt, "Topics:") != 1 {
+		t.Fatalf("unexpected prompt topics: %s", prompt)
+	}
+}
+
+func TestAppDigestHoursAndStorage(t *testing.T) {
+	app := seedDigestApp(t)
+	var body string
+	app.summarizer = digestSummarizer(t, &body)
+	app.config.DigestHours = 24 * 7
+	app.config.DigestGroup = digestGroupFeed
+	if err := app.store.SetArticleTags(app.articles[0].ID, tagSourceTopic, []string{"storage"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	digest, err := app.GenerateDigest()
+	if err != nil || len(digest.Articles) != 2 || digest.ID == 0 {
+		t.Fatalf("expected a week of unread articles in a stored digest, got %+v %v", digest, err)
+	}
+	if !strings.Contains(body, "by the feed") || !strings.Contains(body, "Topics: storage") {
+		t.Fatalf("unexpected digest request: %s", body)
+	}
+	if stored := app.store.Digests(); len(stored) != 1 || stored[0].Content != digest.Content {
+		t.Fatalf("unexpected stored digests %+v", stored)
+	}
+}
+
+func TestParseDigestArgs(t *testing.T) {
+	opts, err := parseDigestArgs([]string{"--hours", "12", "--email", "out.md"})
+	if err != nil || opts.hours != 12 || !opts.email || opts.path != "out.md" {
+		t.Fatalf("unexpected digest args %+v %v", opts, err)
+	}
+	for _, args := range [][]string{{"--hours"}, {"--hours", "0"}, {"--bogus"}, {"a.md", "b.md"}} {
+		if _, err := parseDigestArgs(args); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestAppDeliverDigest(t *testing.T) {
+	app := seedDigestApp(t)
+	dir := t.TempDir()
+	app.config.DigestPath = filepath.Join(dir, "digest-{date}.md")
+	app.config.DigestEmail = true
+	var mailto string
+	app.emailSender = func(target string) error {
+		mailto = target
+		return nil
+	}
+	digest := Digest{Content: "Briefing", GeneratedAt: time.Date(2026, 10, 15, 12, 0, 0, 0, time.Local)}
+	if err := app.DeliverDigest(digest); err != nil {
+		t.Fatalf("DeliverDigest error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "digest-2026-10-15.md")); err != nil {
+		t.Fatalf("expected dated digest file: %v", err)
+	}
+	if !strings.Contains(mailto, "Briefing") {
+		t.Fatalf("expected digest email, got %q", mailto)
+	}
+}
+
+func TestTUIDigestHistory(t *testing.T) {
+	app := seedDigestApp(t)
+	model := newTUIModel(app)
+	model.width = 100
+	model.height = 30
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	model = updated.(tuiModel)
+	if model.showDigest || app.status != "No saved digests yet" {
+		t.Fatalf("expected no saved digests status, got %q", app.status)
+	}
+	base := time.Date(2026, 10, 13, 8, 0, 0, 0, time.UTC)
+	for i, content := range []string{"First briefing", "Second briefing"} {
+		if _, err := app.store.SaveDigest(Digest{Content: content, GeneratedAt: base.Add(time.Duration(i) * 24 * time.Hour)}); err != nil {
+			t.Fatalf("SaveDigest error: %v", err)
+		}
+	}
+	updated, _ = model.Update(digestResultMsg{digest: Digest{Content: "Fresh briefing", GeneratedAt: base.Add(48 * time.Hour)}})
+	model = updated.(tuiModel)
+	if !model.showDigest || model.digest.ID == 0 || len(model.digests) != 3 || !strings.Contains(model.View(), "(1/3)") {
+		t.Fatalf("expected generated digest stored and shown first")
+	}
+	for _, key := range []string{"[", "[", "["} {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		model = updated.(tuiModel)
+	}
+	if model.digestIndex != 2 || !strings.Contains(model.View(), "First briefing") {
+		t.Fatalf("expected oldest digest, got index %d", model.digestIndex)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	model = updated.(tuiModel)
+	if !strings.Contains(model.View(), "Second briefing") {
+		t.Fatalf("expected newer digest")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(tuiModel)
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	model = updated.(tuiModel)
+	if !model.showDigest || model.digestIndex != 0 || !strings.Contains(model.View(), "Fresh briefing") {
+		t.Fatalf("expected latest saved digest")
+	}
+}
+
+func TestHandleCommandDigests(t *testing.T) {
+	app := seedDigestApp(t)
+	var out bytes.Buffer
+	if err := handleCommand(app, "digests", &out); err != nil || !strings.Contains(out.String(), "No saved digests yet") {
+		t.Fatalf("unexpected empty output %q %v", out.String(), err)
+	}
+	if _, err := app.store.SaveDigest(Digest{Content: "Stored briefing", GeneratedAt: time.Date(2026, 10, 15, 12, 0, 0, 0, time.Local), Articles: []Article{{Title: "One"}}}); err != nil {
+		t.Fatalf("SaveDigest error: %v", err)
+	}
+	out.Reset()
+	if err := handleCommand(app, "digests", &out); err != nil || !strings.Contains(out.String(), "1. Daily Digest - 2026-10-15 (1 articles)") {
+		t.Fatalf("unexpected list output %q %v", out.String(), err)
+	}
+	out.Reset()
+	if err := handleCommand(app, "digests 1", &out); err != nil || !strings.Contains(out.String(), "Stored briefing") {
+		t.Fatalf("unexpected digest output %q %v", out.String(), err)
+	}
+	if err := handleCommand(app, "digests 2", &out); err == nil {
+		t.Fatalf("expected invalid digest number error")
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type discoverArgs struct {
+	urls []string
+	all  bool
+}
+
+type siteDiscovery struct {
+	Site  string
+	Feeds []FeedLink
+	Err   error
+}
+
+func formatFeedLink(link FeedLink) string {
+	if link.Title == "" {
+		return link.URL
+	}
+	return link.Title + " (" + link.URL + ")"
+}
+
+func parseDiscoverArgs(args []string) (discoverArgs, error) {
+	opts := discoverArgs{}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--all":
+			opts.all = true
+		case "--file":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("missing value for %s", args[i])
+			}
+			urls, err := readDiscoverFile(args[i+1])
+			if err != nil {
+				return opts, err
+			}
+			opts.urls = append(opts.urls, urls...)
+			i++
+		default:
+			if strings.HasPrefix(args[i], "--") {
+				return opts, fmt.Errorf("unknown discover option: %s", args[i])
+			}
+			opts.urls = append(opts.urls, args[i])
+		}
+	}
+	if len(opts.urls) == 0 {
+		return opts, errors.New(tr(msgCLIDiscoverUsage))
+	}
+	return opts, nil
+}
+
+func readDiscoverFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	urls := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	return urls, nil
+}
+
+func (a *App) DiscoverSites(sites []string) []siteDiscovery {
+	results := make([]siteDiscovery, 0, len(sites))
+	for _, site := range sites {
+		site = strings.TrimSpace(site)
+		if !strings.Contains(site, "://") {
+			site = "https://" + site
+		}
+		feeds, err := a.fetcher.DiscoverCandidates(site)
+		results = append(results, siteDiscovery{Site: site, Feeds: feeds, Err: err})
+	}
+	return results
+}
+
+func (a *App) subscribed(feedURL string) bool {
+	for _, feed := range a.feeds {
+		if feed.URL == feedURL {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) SubscribeFeedURL(feedURL string) (string, error) {
+	parsed, err := a.fetcher.FetchFeed(feedURL)
+	if err != nil {
+		return "", err
+	}
+	if err := a.addDiscoveredFeed(parsed); err != nil {
+		return "", err
+	}
+	return valueOrFallback(parsed.Title, feedURL), nil
+}
+
+func (a *App) AcceptFeedChoice(index int) error {
+	choices := a.feedChoices
+	a.feedChoices = nil
+	if index < 0 || index >= len(choices) {
+		return errors.New("no feed choice offered")
+	}
+	_, err := a.SubscribeFeedURL(choices[index].URL)
+	return err
+}
+
+func parseDiscoverChoice(input string, count int) ([]int, error) {
+	input = strings.TrimSpace(input)
+	if strings.EqualFold(input, "all") {
+		choices := make([]int, count)
+		for i := range choices {
+			choices[i] = i
+		}
+		return choices, nil
+	}
+	choices := []int{}
+	seen := map[int]bool{}
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 1 || number > count {
+			return nil, fmt.Errorf("invalid choice: %s", field)
+		}
+		if !seen[number-1] {
+			seen[number-1] = true
+			choices = append(choices, number-1)
+		}
+	}
+	return choices, nil
+}
+
+func runDiscover(app *App, args []string, stdin io.Reader, stdout io.Writer) error {
+	opts, err := parseDiscoverArgs(args)
+	if err != nil {
+		return err
+	}
+	candidates := []FeedLink{}
+	for _, result := range app.DiscoverSites(opts.urls) {
+		fmt.Fprintln(stdout, result.Site)
+		if result.Err != nil {
+			fmt.Fprintln(stdout, "  "+tr(msgCLIDiscoverSiteError, result.Err))
+			continue
+		}
+		for _, link := range result.Feeds {
+			if app.subscribed(link.URL) {
+				fmt.Fprintln(stdout, "  "+tr(msgCLIDiscoverKnown, formatFeedLink(link)))
+				continue
+			}
+			candidates = append(candidates, link)
+			fmt.Fprintf(stdout, "  %d. %s\n", len(candidates), formatFeedLink(link))
+		}
+	}
+	if len(candidates) == 0 {
+		fmt.Fprintln(stdout, tr(msgCLIDiscoverNone))
+		return nil
+	}
+
+	choices := []int{}
+	if opts.all {
+		choices, _ = parseDiscoverChoice("all", len(candidates))
+	} else {
+		fmt.Fprint(stdout, tr(msgCLIDiscoverPrompt))
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if choices, err = parseDiscoverChoice(line, len(candidates)); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, choice := range choices {
+		title, err := app.SubscribeFeedURL(candidates[choice].URL)
+		if err != nil {
+			failed++
+			fmt.Fprintln(stdout, tr(msgCLIDiscoverSubFailed, candidates[choice].URL, err))
+			continue
+		}
+		fmt.Fprintln(stdout, tr(msgCLIDiscoverSubscribed, title))
+	}
+	if failed > 0 {
+		return errors.New(tr(msgCLIDiscoverFailed, failed, len(choices)))
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func discoverClient() *http.Client {
+	return &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/rss", "/comments", "/direct":
+			return newResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"}, r), nil
+		case "/blog":
+			return newResponse(http.StatusOK, `<html><head>
+<link type="application/atom+xml" href="https://site.test/comments" title="Comments" />
+<link rel="alternate" type="application/rss+xml" href="/rss" title="Posts &amp; notes" />
+<link rel="alternate" type="application/rss+xml" href="/rss" />
+</head></html>`, nil, r), nil
+		case "/broken":
+			return newResponse(http.StatusOK, `<link rel="alternate" type="application/rss+xml" href="/missing" />`, nil, r), nil
+		case "/plain":
+			return newResponse(http.StatusOK, "<html></html>", nil, r), nil
+		}
+		return newResponse(http.StatusNotFound, "", nil, r), nil
+	})}
+}
+
+func TestDiscoverCandidates(t *testing.T) {
+	fetcher := &FeedFetcher{client: discoverClient()}
+	feeds, err := fetcher.DiscoverCandidates("https://site.test/blog")
+	if err != nil || len(feeds) != 2 || feeds[0] != (FeedLink{URL: "https://site.test/rss", Title: "Posts & notes"}) || feeds[1] != (FeedLink{URL: "https://site.test/comments", Title: "Comments"}) {
+		t.Fatalf("unexpected candidates %v %v", feeds, err)
+	}
+	if feeds, err := fetcher.DiscoverCandidates("https://site.test/direct"); err != nil || len(feeds) != 1 || feeds[0] != (FeedLink{URL: "https://site.test/direct", Title: "Sample RSS"}) {
+		t.Fatalf("expected a direct feed to be its own candidate, got %v %v", feeds, err)
+	}
+	if _, err := fetcher.DiscoverCandidates("https://site.test/plain"); err == nil {
+		t.Fatalf("expected no feed link error")
+	}
+	if _, err := fetcher.DiscoverCandidates("https://site.test/gone"); err == nil {
+		t.Fatalf("expected http error")
+	}
+}
+
+func TestParseDiscoverArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sites.txt")
+	if err := os.WriteFile(path, []byte("# blogs\nexample.com\n\n  other.test  \n"), 0o644); err != nil {
+		t.Fatalf("write sites: %v", err)
+	}
+	opts, err := parseDiscoverArgs([]string{"first.test", "--all", "--file", path})
+	if err != nil || !opts.all || strings.Join(opts.urls, ",") != "first.test,example.com,other.test" {
+		t.Fatalf("unexpected args %+v %v", opts, err)
+	}
+	if _, err := parseDiscoverArgs(nil); err == nil || !strings.Contains(err.Error(), "usage: greeder discover") {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+	if _, err := parseDiscoverArgs([]string{"--file"}); err == nil {
+		t.Fatalf("expected missing value error")
+	}
+	if _, err := parseDiscoverArgs([]string{"--file", filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Fatalf("expected missing file error")
+	}
+	if _, err := parseDiscoverArgs([]string{"--bogus", "a"}); err == nil {
+		t.Fatalf("expected unknown option error")
+	}
+	if choices, err := parseDiscoverChoice("2, 1 2", 3); err != nil || len(choices) != 2 || choices[0] != 1 || choices[1] != 0 {
+		t.Fatalf("unexpected choices %v %v", choices, err)
+	}
+	if choices, err := parseDiscoverChoice("\n", 3); err != nil || len(choices) != 0 {
+		t.Fatalf("expected blank choice to pick nothing, got %v %v", choices, err)
+	}
+	if _, err := parseDiscoverChoice("4", 3); err == nil {
+		t.Fatalf("expected out of range choice error")
+	}
+}
+
+func TestRunDiscover(t *testing.T) {
+	app := newTUIApp(t)
+	app.fetcher = &FeedFetcher{client: discoverClient()}
+
+	var out bytes.Buffer
+	if err := runDiscover(app, []string{"https://site.test/blog", "https://site.test/plain"}, strings.NewReader("2\n"), &out); err != nil {
+		t.Fatalf("runDiscover error: %v", err)
+	}
+	want := "https://site.test/blog\n  1. Posts & notes (https://site.test/rss)\n  2. Comments (https://site.test/comments)\nhttps://site.test/plain\n  no feeds: no feed link found\n"
+	if !strings.HasPrefix(out.String(), want) || !strings.HasSuffix(out.String(), "Subscribed: Sample RSS\n") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+	if len(app.feeds) != 1 || app.feeds[0].URL != "https://site.test/comments" {
+		t.Fatalf("expected only the picked feed subscribed, got %+v", app.feeds)
+	}
+
+	out.Reset()
+	if err := runDiscover(app, []string{"--all", "https://site.test/blog"}, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("runDiscover --all error: %v", err)
+	}
+	if !strings.Contains(out.String(), "already subscribed: Comments (https://site.test/comments)") || strings.Contains(out.String(), "Subscribe to which") || len(app.feeds) != 2 {
+		t.Fatalf("expected --all to subscribe the remaining feed, got %q %d", out.String(), len(app.feeds))
+	}
+
+	out.Reset()
+	if err := runDiscover(app, []string{"--all", "https://site.test/blog"}, strings.NewReader(""), &out); err != nil || !strings.HasSuffix(out.String(), "No new feeds found\n") {
+		t.Fatalf("expected nothing new, got %q %v", out.String(), err)
+	}
+
+	out.Reset()
+	if err := runDiscover(app, []string{"https://site.test/broken"}, strings.NewReader("all\n"), &out); err == nil || err.Error() != "1 of 1 feeds could not be subscribed" {
+		t.Fatalf("expected subscribe failure, got %v", err)
+	}
+	if !strings.Contains(out.String(), "Failed: https://site.test/missing") {
+		t.Fatalf("expected failure line, got %q", out.String())
+	}
+	if err := runDiscover(app, []string{"https://site.test/broken"}, strings.NewReader("x\n"), &out); err == nil {
+		t.Fatalf("expected invalid choice error")
+	}
+}
+
+func TestRunMainDiscoverUsage(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+
+	var stdout, stderr bytes.Buffer
+	if err := runMain([]string{"discover"}, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Fatalf("expected usage error")
+	}
+	if !strings.Contains(stderr.String(), "discover error: usage: greeder discover") {
+		t.Fatalf("unexpected stderr %q", stderr.String())
+	}
+}
+
+func TestTUIAddFeedChoices(t *testing.T) {
+	app := newTUIApp(t)
+	app.fetcher = &FeedFetcher{client: discoverClient()}
+	model := newTUIModel(app)
+	model.width, model.height = 100, 30
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(tuiModel)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	model.input.SetValue("https://site.test/blog")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(app.feedChoices) != 2 || app.status != "Found 2 feeds; pick one to add" || len(app.feeds) != 0 {
+		t.Fatalf("expected a feed picker, got %v %q", app.feedChoices, app.status)
+	}
+	if view := model.View(); !strings.Contains(view, "Choose a feed (2)") || !strings.Contains(view, "▸ Posts & notes (https://site.test/rss)") {
+		t.Fatalf("expected picker overlay:\n%s", view)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(app.feedChoices) != 0 || len(app.feeds) != 1 || app.feeds[0].URL != "https://site.test/comments" || app.status != "feed added" {
+		t.Fatalf("expected the second feed added, got %+v %q", app.feeds, app.status)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	model.input.SetValue("site.test/blog")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if len(app.feedChoices) != 0 || app.status != tr(msgInputCancelled) || len(app.feeds) != 1 {
+		t.Fatalf("expected esc to dismiss the picker, got %q", app.status)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	model.input.SetValue("https://site.test/broken")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(app.feedChoices) != 0 || !strings.HasPrefix(app.status, "Add feed failed:") {
+		t.Fatalf("expected a single broken link to fail without a picker, got %q", app.status)
+	}
+
+	var out bytes.Buffer
+	if err := handleCommand(app, "add https://site.test/blog", &out); err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+	if !strings.Contains(out.String(), "(run 'pick <n>')\n1. Posts & notes (https://site.test/rss)\n2. Comments") {
+		t.Fatalf("unexpected REPL choices %q", out.String())
+	}
+	if err := handleCommand(app, "pick 1", &out); err != nil || len(app.feeds) != 2 {
+		t.Fatalf("expected pick to add the first feed: %v %d", err, len(app.feeds))
+	}
+	if err := handleCommand(app, "pick 1", &out); err == nil {
+		t.Fatalf("expected pick without choices to fail")
+	}
+	if err := handleCommand(app, "pick x", &out); err == nil {
+		t.Fatalf("expected invalid pick to fail")
+	}
+}
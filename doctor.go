@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+type modelsResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+func (s *Summarizer) ListModels() ([]string, error) {
+	if s == nil {
+		return nil, errors.New("summarizer not configured")
+	}
+	models, err := s.backend().models(s)
+	var httpErr *summarizerHTTPError
+	if errors.As(err, &httpErr) {
+		return nil, fmt.Errorf("models endpoint: http %d", httpErr.status)
+	}
+	return models, err
+}
+
+func (a *App) Doctor() []DoctorCheck {
+	checks := []DoctorCheck{}
+	if err := a.store.Save(); err != nil {
+		checks = append(checks, DoctorCheck{Name: "database", Detail: err.Error()})
+	} else {
+		checks = append(checks, DoctorCheck{Name: "database", OK: true, Detail: a.store.path})
+	}
+	if len(a.credentials) > 0 {
+		checks = append(checks, credentialsDoctorCheck(a.credentials))
+	}
+	if a.summarizer == nil {
+		checks = append(checks, DoctorCheck{Name: "summarizer", Detail: "not configured (set lm_preset, lm_base_url, or LM_BASE_URL)"})
+		return checks
+	}
+	providers := a.summarizer.providers()
+	for _, provider := range providers {
+		name := "summarizer"
+		modelName := "model"
+		if len(providers) > 1 {
+			name = "provider " + provider.name
+			modelName = name + " model"
+		}
+		checks = append(checks, doctorProvider(provider, name, modelName)...)
+	}
+	feedURLs := make([]string, 0, len(a.config.FeedOverrides))
+	for feedURL, override := range a.config.FeedOverrides {
+		if override.Profile != "" {
+			feedURLs = append(feedURLs, feedURL)
+		}
+	}
+	sort.Strings(feedURLs)
+	for _, feedURL := range feedURLs {
+		profile := a.config.FeedOverrides[feedURL].Profile
+		if _, ok := a.summarizer.profiles[profile]; ok || profile == a.summarizer.name {
+			checks = append(checks, DoctorCheck{Name: "profile " + profile, OK: true, Detail: feedURL})
+		} else {
+			checks = append(checks, DoctorCheck{Name: "profile " + profile, Detail: "not defined (used by " + feedURL + ")"})
+		}
+	}
+	return checks
+}
+
+func doctorProvider(s *Summarizer, name string, modelName string) []DoctorCheck {
+	checks := []DoctorCheck{{Name: name, OK: true, Detail: fmt.Sprintf("%s (model %s)", s.baseURL, s.model)}}
+	models, err := s.ListModels()
+	if err != nil {
+		return append(checks, DoctorCheck{Name: name + " reachable", Detail: err.Error()})
+	}
+	checks = append(checks, DoctorCheck{Name: name + " reachable", OK: true, Detail: fmt.Sprintf("%d models available", len(models))})
+	if len(models) == 0 {
+		return checks
+	}
+	for _, model := range models {
+		if ollamaModelName(model) == ollamaModelName(s.model) {
+			return append(checks, DoctorCheck{Name: modelName, OK: true, Detail: s.model})
+		}
+	}
+	return append(checks, DoctorCheck{Name: modelName, Detail: s.model + " not found on server"})
+}
+
+// ollamaModelName resolves an untagged model name to the ":latest" tag the
+// server reports, so "llama3.2" and "llama3.2:latest" compare equal.
+func ollamaModelName(name string) string {
+	if name != "" && !strings.Contains(name[strings.LastIndex(name, "/")+1:], ":") {
+		return name + ":latest"
+	}
+	return name
+}
+
+func formatDoctorCheck(check DoctorCheck) string {
+	label := "fail"
+	if check.OK {
+		label = "ok"
+	}
+	return fmt.Sprintf("[%s] %s: %s", label, check.Name, check.Detail)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSummarizerListModels(t *testing.T) {
+	var nilSummarizer *Summarizer
+	if _, err := nilSummarizer.ListModels(); err == nil {
+		t.Fatalf("expected nil summarizer error")
+	}
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/v1/models" || r.Header.Get("authorization") != "Bearer key" {
+			return newResponse(http.StatusNotFound, "", nil, r), nil
+		}
+		return newResponse(http.StatusOK, `{"data":[{"id":"llama3.2"},{"id":"qwen"}]}`, nil, r), nil
+	})}
+	s := &Summarizer{baseURL: "http://example.test/v1", apiKey: "key", model: "llama3.2", client: client}
+	models, err := s.ListModels()
+	if err != nil || len(models) != 2 || models[1] != "qwen" {
+		t.Fatalf("unexpected models %v %v", models, err)
+	}
+	s.apiKey = ""
+	if _, err := s.ListModels(); err == nil {
+		t.Fatalf("expected http error")
+	}
+	s = &Summarizer{baseURL: "http://example.test", client: clientForResponse(http.StatusOK, "", nil)}
+	if models, err := s.ListModels(); err != nil || len(models) != 0 {
+		t.Fatalf("expected empty model list for empty body: %v %v", models, err)
+	}
+	s = &Summarizer{baseURL: "http://example.test", client: clientForResponse(http.StatusOK, "{", nil)}
+	if _, err := s.ListModels(); err == nil {
+		t.Fatalf("expected decode error")
+	}
+	s = &Summarizer{baseURL: "http://[::1", client: http.DefaultClient}
+	if _, err := s.ListModels(); err == nil {
+		t.Fatalf("expected request error")
+	}
+	s = &Summarizer{baseURL: "http://example.test", client: &http.Client{Transport: &errorRoundTripper{}}}
+	if _, err := s.ListModels(); err == nil {
+		t.Fatalf("expected transport error")
+	}
+}
+
+func TestAppDoctor(t *testing.T) {
+	app := newTUIApp(t)
+	checks := app.Doctor()
+	if len(checks) != 2 || !checks[0].OK || checks[1].OK {
+		t.Fatalf("unexpected checks without summarizer: %+v", checks)
+	}
+
+	app.summarizer = &Summarizer{baseURL: "http://example.test", model: "llama3.2", client: &http.Client{Transport: &errorRoundTripper{}}}
+	checks = app.Doctor()
+	if len(checks) != 3 || checks[2].OK {
+		t.Fatalf("expected unreachable check: %+v", checks)
+	}
+
+	app.summarizer.client = clientForResponse(http.StatusOK, `{"data":[{"id":"llama3.2"}]}`, nil)
+	checks = app.Doctor()
+	if len(checks) != 4 || !checks[3].OK {
+		t.Fatalf("expected model check ok: %+v", checks)
+	}
+	app.summarizer.client = clientForResponse(http.StatusOK, `{"data":[{"id":"llama3.2:latest"},{"id":"qwen3:8b"}]}`, nil)
+	if checks = app.Doctor(); !checks[3].OK {
+		t.Fatalf("expected untagged model to match :latest: %+v", checks)
+	}
+	app.summarizer.model = "qwen3"
+	if checks = app.Doctor(); checks[3].OK {
+		t.Fatalf("expected untagged model not to match another tag: %+v", checks)
+	}
+	app.summarizer.model = "missing"
+	checks = app.Doctor()
+	if checks[3].OK || !strings.Contains(formatDoctorCheck(checks[3]), "[fail] model: missing not found") {
+		t.Fatalf("expected missing model check: %+v", checks)
+	}
+
+	app.store.db.Close()
+	if checks := app.Doctor(); checks[0].OK {
+		t.Fatalf("expected database failure")
+	}
+}
+
+func TestRunMainDoctor(t *testing.T) {
+	root := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", root)
+	os.Setenv("XDG_DATA_HOME", root)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	defer os.Unsetenv("XDG_DATA_HOME")
+	os.Unsetenv("LM_BASE_URL")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if err := runMain([]string{"--doctor"}, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Fatalf("expected doctor failure without summarizer")
+	}
+	if !strings.Contains(stderr.String(), "checks failed") {
+		t.Fatalf("expected failure summary on stderr, got %q", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "[ok] database") || !strings.Contains(stdout.String(), "[fail] summarizer") {
+		t.Fatalf("unexpected doctor output: %s", stdout.String())
+	}
+
+	oldTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusOK, `{"data":[]}`, nil, r), nil
+	})
+	t.Cleanup(func() { http.DefaultTransport = oldTransport })
+	os.Setenv("LM_BASE_URL", "http://example.test")
+	defer os.Unsetenv("LM_BASE_URL")
+	stdout.Reset()
+	if err := runMain([]string{"--doctor"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain doctor error: %v\n%s", err, stdout.String())
+	}
+}
+
+func TestAppDoctorProviderChain(t *testing.T) {
+	app := newTUIApp(t)
+	app.summarizer = &Summarizer{name: "local", baseURL: "http://local.test", model: "llama3.2", client: &http.Client{Transport: &errorRoundTripper{}}}
+	app.summarizer.fallbacks = []*Summarizer{{name: "hosted", baseURL: "http://hosted.test", model: "gpt-4o-mini", client: clientForResponse(http.StatusOK, `{"data":[{"id":"gpt-4o-mini"}]}`, nil)}}
+	checks := app.Doctor()
+	names := []string{}
+	for _, check := range checks {
+		names = append(names, check.Name)
+	}
+	want := "database,provider local,provider local reachable,provider hosted,provider hosted reachable,provider hosted model"
+	if strings.Join(names, ",") != want {
+		t.Fatalf("unexpected checks: %v", names)
+	}
+	if checks[2].OK || !checks[5].OK {
+		t.Fatalf("unexpected check results: %+v", checks)
+	}
+}
+
+func TestAppDoctorFeedProfiles(t *testing.T) {
+	app := newTUIApp(t)
+	app.summarizer = &Summarizer{name: "default", baseURL: "http://local.test", model: "small", client: clientForResponse(http.StatusOK, `{"data":[]}`, nil)}
+	app.summarizer.profiles = map[string]*Summarizer{"longform": {name: "longform"}}
+	app.config.FeedOverrides = map[string]SummaryOptions{
+		"https://essays.example.com/rss": {Profile: "longform"},
+		"https://news.example.com/rss":   {Profile: "typo"},
+		"https://links.example.com/rss":  {Style: "tldr"},
+	}
+	checks := app.Doctor()
+	last := checks[len(checks)-2:]
+	if last[0].Name != "profile longform" || !last[0].OK || last[1].Name != "profile typo" || last[1].OK || !strings.Contains(last[1].Detail, "news.example.com") {
+		t.Fatalf("unexpected profile checks: %+v", checks)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"runtime"
+	"strings"
+)
+
+type emailMessage struct {
+	To      string
+	Subject string
+	Body    string
+	URL     string
+}
+
+var mailCommandRun = defaultMailCommandRun
+
+func defaultMailCommandRun(shell string, args []string, env []string, input string) error {
+	command := execCommand(shell, args...)
+	command.Env = append(command.Environ(), env...)
+	command.Stdin = strings.NewReader(input)
+	out, err := command.CombinedOutput()
+	if err != nil {
+		if detail := strings.TrimSpace(string(out)); detail != "" {
+			return fmt.Errorf("%w: %s", err, detail)
+		}
+		return err
+	}
+	return nil
+}
+
+func (m emailMessage) mailto() string {
+	params := url.Values{}
+	params.Set("subject", m.Subject)
+	params.Set("body", m.Body)
+	return "mailto:" + url.PathEscape(m.To) + "?" + params.Encode()
+}
+
+func expandEmailTemplate(template string, values map[string]string) string {
+	pairs := make([]string, 0, len(values)*2)
+	for name, value := range values {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+func articleEmail(article *Article, summary Summary, cfg Config) emailMessage {
+	summaryText := ""
+	if summary.ArticleID == article.ID {
+		summaryText = summary.Content
+	}
+	message := emailMessage{To: cfg.EmailTo, Subject: article.Title, URL: article.URL}
+	body := []string{"Title: " + article.Title, "", "URL: " + article.URL}
+	if summaryText != "" {
+		body = append(body, "", "AI Summary:", summaryText)
+	}
+	if article.ContentText != "" {
+		body = append(body, "", "Article Content:", article.ContentText)
+	}
+	message.Body = strings.Join(body, "\n")
+	values := map[string]string{
+		"title":   article.Title,
+		"url":     article.URL,
+		"feed":    article.FeedTitle,
+		"author":  article.Author,
+		"summary": summaryText,
+		"content": article.ContentText,
+	}
+	if cfg.EmailSubject != "" {
+		message.Subject = expandEmailTemplate(cfg.EmailSubject, values)
+	}
+	if cfg.EmailBody != "" {
+		message.Body = expandEmailTemplate(cfg.EmailBody, values)
+	}
+	return message
+}
+
+func digestEmail(digest Digest, cfg Config) emailMessage {
+	message := emailMessage{To: cfg.EmailTo, Subject: digestTitle(digest), Body: renderDigestMarkdown(digest)}
+	values := map[string]string{
+		"title":   message.Subject,
+		"url":     "",
+		"feed":    "",
+		"author":  "",
+		"summary": strings.TrimSpace(digest.Content),
+		"content": message.Body,
+	}
+	if cfg.EmailSubject != "" {
+		message.Subject = expandEmailTemplate(cfg.EmailSubject, values)
+	}
+	if cfg.EmailBody != "" {
+		message.Body = expandEmailTemplate(cfg.EmailBody, values)
+	}
+	return message
+}
+
+func (a *App) deliverEmail(message emailMessage) error {
+	if strings.TrimSpace(a.config.SMTPHost) != "" {
+		return sendSMTP(a.config, message, a.now())
+	}
+	command := strings.TrimSpace(a.config.EmailCommand)
+	if command == "" {
+		return a.emailSender(message.mailto())
+	}
+	shell, args := shellCommandForOS(runtime.GOOS, command)
+	env := []string{
+		"GREEDER_MAIL_TO=" + message.To,
+		"GREEDER_MAIL_SUBJECT=" + message.Subject,
+		"GREEDER_MAIL_URL=" + message.URL,
+	}
+	if err := mailCommandRun(shell, args, env, message.Body); err != nil {
+		return fmt.Errorf("email_command: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArticleEmailTemplates(t *testing.T) {
+	article := &Article{ID: 3, Title: "Go 2", URL: "https://go.dev/blog/go2", FeedTitle: "Go Blog", Author: "Gopher", ContentText: "Body text"}
+	summary := Summary{ArticleID: 3, Content: "Short."}
+
+	plain := articleEmail(article, summary, Config{})
+	if plain.Subject != "Go 2" || plain.Body != "Title: Go 2\n\nURL: https://go.dev/blog/go2\n\nAI Summary:\nShort.\n\nArticle Content:\nBody text" || plain.To != "" {
+		t.Fatalf("unexpected default email %+v", plain)
+	}
+	if plain.mailto() != buildMailto(article, summary) || !strings.HasPrefix(plain.mailto(), "mailto:?") {
+		t.Fatalf("unexpected mailto %q", plain.mailto())
+	}
+
+	cfg := Config{EmailTo: "me@example.com", EmailSubject: "[{feed}] {title}", EmailBody: "{summary}\n\n{url} by {author} {unknown}"}
+	templated := articleEmail(article, Summary{ArticleID: 9, Content: "other"}, cfg)
+	if templated.Subject != "[Go Blog] Go 2" || templated.Body != "\n\nhttps://go.dev/blog/go2 by Gopher {unknown}" || templated.URL != article.URL {
+		t.Fatalf("unexpected templated email %+v", templated)
+	}
+	if got := templated.mailto(); !strings.HasPrefix(got, "mailto:me@example.com?") || !strings.Contains(got, "subject=%5BGo+Blog%5D+Go+2") {
+		t.Fatalf("unexpected templated mailto %q", got)
+	}
+}
+
+func TestDigestEmailTemplates(t *testing.T) {
+	digest := Digest{Content: " Things happened. ", GeneratedAt: time.Date(2026, 10, 15, 12, 0, 0, 0, time.Local)}
+	plain := digestEmail(digest, Config{})
+	if plain.Subject != "Daily Digest - 2026-10-15" || plain.Body != renderDigestMarkdown(digest) || plain.mailto() != buildDigestMailto(digest) {
+		t.Fatalf("unexpected digest email %+v", plain)
+	}
+	templated := digestEmail(digest, Config{EmailSubject: "News: {title}", EmailBody: "{summary}"})
+	if templated.Subject != "News: Daily Digest - 2026-10-15" || templated.Body != "Things happened." {
+		t.Fatalf("unexpected templated digest %+v", templated)
+	}
+}
+
+func TestDeliverEmailCommand(t *testing.T) {
+	app := newTUIApp(t)
+	mailtos := []string{}
+	app.emailSender = func(target string) error {
+		mailtos = append(mailtos, target)
+		return nil
+	}
+	message := emailMessage{To: "me@example.com", Subject: "Hi", Body: "Hello", URL: "https://example.com"}
+	if err := app.deliverEmail(message); err != nil || len(mailtos) != 1 || mailtos[0] != message.mailto() {
+		t.Fatalf("expected mailto fallback, got %v %v", err, mailtos)
+	}
+
+	orig := mailCommandRun
+	t.Cleanup(func() { mailCommandRun = orig })
+	var gotShell, gotInput string
+	var gotArgs, gotEnv []string
+	mailCommandRun = func(shell string, args []string, env []string, input string) error {
+		gotShell, gotArgs, gotEnv, gotInput = shell, args, env, input
+		return nil
+	}
+	app.config.EmailCommand = "neomutt -s \"$GREEDER_MAIL_SUBJECT\" -- \"$GREEDER_MAIL_TO\""
+	if err := app.deliverEmail(message); err != nil {
+		t.Fatalf("deliverEmail error: %v", err)
+	}
+	if gotShell == "" || gotArgs[len(gotArgs)-1] != app.config.EmailCommand || gotInput != "Hello" || len(mailtos) != 1 {
+		t.Fatalf("unexpected command %q %v %q", gotShell, gotArgs, gotInput)
+	}
+	if strings.Join(gotEnv, "|") != "GREEDER_MAIL_TO=me@example.com|GREEDER_MAIL_SUBJECT=Hi|GREEDER_MAIL_URL=https://example.com" {
+		t.Fatalf("unexpected env %v", gotEnv)
+	}
+
+	mailCommandRun = func(string, []string, []string, string) error { return errors.New("exit status 1") }
+	if err := app.deliverEmail(message); err == nil || err.Error() != "email_command: exit status 1" {
+		t.Fatalf("expected command error, got %v", err)
+	}
+}
+
+func TestEmailSelectedUsesCommand(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "a", Title: "Alpha", URL: "https://example.com/a"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.config.EmailCommand = "msmtp -t"
+	app.config.EmailTo = "friend@example.com"
+	app.config.EmailBody = "To: {title}\n\n{url}"
+	orig := mailCommandRun
+	t.Cleanup(func() { mailCommandRun = orig })
+	var input string
+	mailCommandRun = func(shell string, args []string, env []string, body string) error {
+		input = body
+		return nil
+	}
+	if err := app.EmailSelected(); err != nil || input != "To: Alpha\n\nhttps://example.com/a" {
+		t.Fatalf("unexpected email %v %q", err, input)
+	}
+	if err := app.EmailDigest(Digest{Content: "Digest", GeneratedAt: time.Now()}); err != nil || !strings.Contains(input, "To: Daily Digest") {
+		t.Fatalf("unexpected digest email %v %q", err, input)
+	}
+}
+
+func TestDefaultMailCommandRun(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "mail.txt")
+	script := "cat > '" + out + "'; printf '%s' \"$GREEDER_MAIL_SUBJECT\" >> '" + out + "'"
+	if err := defaultMailCommandRun("sh", []string{"-c", script}, []string{"GREEDER_MAIL_SUBJECT=Hi"}, "Body|"); err != nil {
+		t.Fatalf("defaultMailCommandRun error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil || string(data) != "Body|Hi" {
+		t.Fatalf("unexpected mail output %q %v", data, err)
+	}
+	if err := defaultMailCommandRun("sh", []string{"-c", "echo no relay >&2; exit 3"}, nil, ""); err == nil || err.Error() != "exit status 3: no relay" {
+		t.Fatalf("expected command failure, got %v", err)
+	}
+	if err := defaultMailCommandRun("sh", []string{"-c", "exit 4"}, nil, ""); err == nil || err.Error() != "exit status 4" {
+		t.Fatalf("expected bare failure, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+)
+
+const (
+	embeddingBatchSize = 32
+	relatedThreshold   = 0.5
+)
+
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+	} `json:"usage"`
+}
+
+type RelatedArticle struct {
+	Article    Article
+	Similarity float64
+}
+
+func (s *Summarizer) Embed(inputs []string) ([][]float64, error) {
+	if s == nil || s.embeddingModel == "" {
+		return nil, errors.New("embeddings not configured")
+	}
+	if _, ok := s.backend().(openAIBackend); !ok {
+		return nil, fmt.Errorf("embeddings are not supported by the %s api", s.api)
+	}
+	blob, err := aiJSONMarshal(embeddingRequest{Model: s.embeddingModel, Input: inputs})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(s.requestContext(), http.MethodPost, s.endpoint("/embeddings"), bytes.NewReader(blob))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("content-type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("authorization", "Bearer "+s.apiKey)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("embeddings: http %d", resp.StatusCode)
+	}
+	var parsed embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+	if len(parsed.Data) != len(inputs) {
+		return nil, fmt.Errorf("embeddings: expected %d vectors, got %d", len(inputs), len(parsed.Data))
+	}
+	s.logUsage(s.embeddingModel, parsed.Usage.PromptTokens, 0)
+	vectors := make([][]float64, len(inputs))
+	for i, item := range parsed.Data {
+		index := item.Index
+		if index < 0 || index >= len(inputs) {
+			index = i
+		}
+		vectors[index] = item.Embedding
+	}
+	return vectors, nil
+}
+
+func embeddingText(article Article) string {
+	return truncateText(article.Title+"\n\n"+firstNonEmpty(article.ContentText, article.Content), 2000)
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func encodeVector(vector []float64) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(float32(value)))
+	}
+	return buf
+}
+
+func decodeVector(blob []byte) []float64 {
+	vector := make([]float64, len(blob)/4)
+	for i := range vector {
+		vector[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(blob[i*4:])))
+	}
+	return vector
+}
+
+func (s *Store) SaveEmbedding(articleID int, model string, vector []float64) error {
+	_, err := s.db.Exec(`INSERT INTO article_embeddings (article_id, model, vector) VALUES (?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET model = excluded.model, vector = excluded.vector`, articleID, model, encodeVector(vector))
+	return err
+}
+
+func (s *Store) Embeddings(model string) map[int][]float64 {
+	embeddings := map[int][]float64{}
+	rows, err := s.db.Query(`SELECT article_id, vector FROM article_embeddings WHERE model = ?`, model)
+	if err != nil {
+		return embeddings
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			return embeddings
+		}
+		embeddings[id] = decodeVector(blob)
+	}
+	return embeddings
+}
+
+func (a *App) EmbedMissingArticles() (int, error) {
+	if a.summarizer == nil || a.summarizer.embeddingModel == "" {
+		return 0, nil
+	}
+	model := a.summarizer.embeddingModel
+	embedded := 0
+	for {
+		batch := a.store.ArticlesMissingEmbedding(model, embeddingBatchSize)
+		if len(batch) == 0 {
+			break
+		}
+		inputs := make([]string, len(batch))
+		for i, article := range batch {
+			inputs[i] = embeddingText(article)
+		}
+		vectors, err := a.summarizer.Embed(inputs)
+		if err != nil {
+			a.embeddings = nil
+			return embedded, err
+		}
+		for i, article := range batch {
+			if err := a.store.SaveEmbedding(article.ID, model, vectors[i]); err != nil {
+				a.embeddings = nil
+				return embedded, err
+			}
+			embedded++
+		}
+	}
+	a.embeddings = nil
+	return embedded, nil
+}
+
+func formatRelatedArticle(item RelatedArticle) string {
+	return fmt.Sprintf("%2.0f%% %s (%s)", item.Similarity*100, item.Article.Title, valueOrFallback(item.Article.FeedTitle, "Unknown"))
+}
+
+func (a *App) RelatedArticles(articleID int, limit int) []RelatedArticle {
+	if a.summarizer == nil || a.summarizer.embeddingModel == "" {
+		return nil
+	}
+	if a.embeddings == nil {
+		a.embeddings = a.store.Embeddings(a.summarizer.embeddingModel)
+	}
+	target, ok := a.embeddings[articleID]
+	if !ok {
+		return nil
+	}
+	// Score against the stored vectors and only read the few winners that
+	// are not loaded, instead of walking the whole collection every frame.
+	loaded := make(map[int]Article, len(a.articles))
+	for _, article := range a.articles {
+		loaded[article.ID] = article
+	}
+	related := []RelatedArticle{}
+	for id, vector := range a.embeddings {
+		if id == articleID {
+			continue
+		}
+		article, ok := loaded[id]
+		if !ok && !a.moreArticles {
+			continue
+		}
+		similarity := cosineSimilarity(target, vector)
+		if similarity < relatedThreshold {
+			continue
+		}
+		article.ID = id
+		related = append(related, RelatedArticle{Article: article, Similarity: similarity})
+	}
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Similarity != related[j].Similarity {
+			return related[i].Similarity > related[j].Similarity
+		}
+		return related[i].Article.ID < related[j].Article.ID
+	})
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	missing := []int{}
+	for _, item := range related {
+		if _, ok := loaded[item.Article.ID]; !ok {
+			missing = append(missing, item.Article.ID)
+		}
+	}
+	if len(missing) == 0 {
+		return related
+	}
+	for _, article := range a.store.ArticlesByID(missing) {
+		loaded[article.ID] = article
+	}
+	found := related[:0]
+	for _, item := range related {
+		if article, ok := loaded[item.Article.ID]; ok {
+			item.Article = article
+			found = append(found, item)
+		}
+	}
+	return found
+}
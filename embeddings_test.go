@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func embeddingSummarizer(t *testing.T, vectors map[string][]float64, calls *int) *Summarizer {
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if calls != nil {
+			*calls++
+		}
+		var req embeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode embedding request: %v", err)
+		}
+		type item struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		}
+		data := []item{}
+		for i, input := range req.Input {
+			title := strings.SplitN(input, "\n", 2)[0]
+			data = append(data, item{Index: i, Embedding: vectors[title]})
+		}
+		blob, _ := json.Marshal(map[string]any{"data": data})
+		return newResponse(http.StatusOK, string(blob), map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	return &Summarizer{baseURL: "http://example.test", model: "m", embeddingModel: "embed", client: client}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	if got := cosineSimilarity([]float64{1, 0}, []float64{1, 0}); math.Abs(got-1) > 1e-9 {
+		t.Fatalf("expected identical vectors to score 1, got %f", got)
+	}
+	if got := cosineSimilarity([]float64{1, 0}, []float64{0, 1}); got != 0 {
+		t.Fatalf("expected orthogonal vectors to score 0, got %f", got)
+	}
+	if cosineSimilarity([]float64{1}, []float64{1, 2}) != 0 || cosineSimilarity([]float64{0, 0}, []float64{1, 1}) != 0 {
+		t.Fatalf("expected mismatched or zero vectors to score 0")
+	}
+}
+
+func TestEncodeDecodeVector(t *testing.T) {
+	vector := []float64{0.5, -1.25, 3}
+	decoded := decodeVector(encodeVector(vector))
+	if len(decoded) != 3 || decoded[0] != 0.5 || decoded[1] != -1.25 || decoded[2] != 3 {
+		t.Fatalf("unexpected decoded vector: %v", decoded)
+	}
+}
+
+func TestSummarizerEmbed(t *testing.T) {
+	var nilSummarizer *Summarizer
+	if _, err := nilSummarizer.Embed([]string{"x"}); err == nil {
+		t.Fatalf("expected nil summarizer error")
+	}
+	s := embeddingSummarizer(t, map[string][]float64{"a": {1, 2}}, nil)
+	vectors, err := s.Embed([]string{"a"})
+	if err != nil || len(vectors) != 1 || vectors[0][1] != 2 {
+		t.Fatalf("unexpected vectors: %v (%v)", vectors, err)
+	}
+	mismatch := &Summarizer{baseURL: "http://example.test", embeddingModel: "e", client: clientForResponse(http.StatusOK, `{"data":[]}`, nil)}
+	if _, err := mismatch.Embed([]string{"a"}); err == nil {
+		t.Fatalf("expected vector count error")
+	}
+	failing := &Summarizer{baseURL: "http://example.test", embeddingModel: "e", client: clientForResponse(http.StatusNotFound, "", nil)}
+	if _, err := failing.Embed([]string{"a"}); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	broken := &Summarizer{baseURL: "http://example.test", embeddingModel: "e", client: clientForResponse(http.StatusOK, "nope", nil)}
+	if _, err := broken.Embed([]string{"a"}); err == nil {
+		t.Fatalf("expected decode error")
+	}
+}
+
+func TestAppRelatedArticles(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "Rust release", URL: "https://example.com/1"},
+		{GUID: "2", Title: "Rust compiler", URL: "https://example.com/2"},
+		{GUID: "3", Title: "Tomato harvest", URL: "https://example.com/3"},
+	})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	if embedded, err := app.EmbedMissingArticles(); err != nil || embedded != 0 || app.RelatedArticles(articles[0].ID, 3) != nil {
+		t.Fatalf("expected embeddings disabled without model")
+	}
+
+	calls := 0
+	app.summarizer = embeddingSummarizer(t, map[string][]float64{
+		"Rust release":   {1, 0.1, 0},
+		"Rust compiler":  {0.9, 0.2, 0},
+		"Tomato harvest": {0, 0, 1},
+	}, &calls)
+	embedded, err := app.EmbedMissingArticles()
+	if err != nil || embedded != 3 || calls != 1 {
+		t.Fatalf("unexpected embed result: %d calls=%d (%v)", embedded, calls, err)
+	}
+	if embedded, _ := app.EmbedMissingArticles(); embedded != 0 || calls != 1 {
+		t.Fatalf("expected no re-embedding of stored vectors")
+	}
+	related := app.RelatedArticles(articles[0].ID, 3)
+	if len(related) != 1 || related[0].Article.Title != "Rust compiler" {
+		t.Fatalf("unexpected related articles: %+v", related)
+	}
+	if !strings.HasPrefix(formatRelatedArticle(related[0]), "99% Rust compiler (Feed)") {
+		t.Fatalf("unexpected related format: %q", formatRelatedArticle(related[0]))
+	}
+	if got := app.RelatedArticles(999, 3); got != nil {
+		t.Fatalf("expected no related for unknown article")
+	}
+
+	model := newTUIModel(app)
+	model.width = 120
+	model.height = 40
+	for app.SelectedArticle().ID != articles[0].ID {
+		app.MoveSelection(1)
+	}
+	if !strings.Contains(model.View(), "Rust compiler (Feed)") {
+		t.Fatalf("expected related articles in details")
+	}
+	var out bytes.Buffer
+	if err := handleCommand(app, "related", &out); err != nil || !strings.Contains(out.String(), "Rust compiler") {
+		t.Fatalf("unexpected related output: %s", out.String())
+	}
+
+	app.summarizer = &Summarizer{baseURL: "http://example.test", embeddingModel: "other", client: clientForResponse(http.StatusInternalServerError, "", nil)}
+	if _, err := app.EmbedMissingArticles(); err == nil {
+		t.Fatalf("expected embed error for new model")
+	}
+	out.Reset()
+	if err := handleCommand(app, "related", &out); err != nil || !strings.Contains(out.String(), "No related articles") {
+		t.Fatalf("expected no related output: %s", out.String())
+	}
+}
+
+func TestAppRelatedArticlesBeyondLoadedPage(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	published := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	articles, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "Rust release", URL: "https://example.com/1", PublishedAt: published.Add(2 * time.Hour)},
+		{GUID: "2", Title: "Tomato harvest", URL: "https://example.com/2", PublishedAt: published.Add(time.Hour)},
+		{GUID: "3", Title: "Rust compiler", URL: "https://example.com/3", PublishedAt: published},
+	})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.config.ArticlePageSize = 1
+	app.filter = FilterAll
+	app.articles = nil
+	app.loadArticles()
+	app.summarizer = embeddingSummarizer(t, map[string][]float64{
+		"Rust release":   {1, 0.1, 0},
+		"Rust compiler":  {0.9, 0.2, 0},
+		"Tomato harvest": {0, 0, 1},
+	}, nil)
+	if embedded, err := app.EmbedMissingArticles(); err != nil || embedded != 3 || len(app.articles) != 1 {
+		t.Fatalf("expected every article embedded without paging in, got %d %v (%d loaded)", embedded, err, len(app.articles))
+	}
+	related := app.RelatedArticles(articles[0].ID, 3)
+	if len(related) != 1 || related[0].Article.Title != "Rust compiler" || len(app.articles) != 1 {
+		t.Fatalf("expected the unloaded match read from the store, got %+v", related)
+	}
+}
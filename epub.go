@@ -0,0 +1,212 @@
+package main
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var epubCreate = func(path string) (io.WriteCloser, error) { return os.Create(path) }
+
+var (
+	epubBlockRe     = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|h[1-6]|blockquote|pre|tr)>`)
+	epubParagraphRe = regexp.MustCompile(`\n\s*\n`)
+)
+
+func epubParagraphs(article Article) []string {
+	text := article.ContentText
+	if strings.TrimSpace(article.Content) != "" {
+		text = html.UnescapeString(tagRe.ReplaceAllString(epubBlockRe.ReplaceAllString(article.Content, "\n\n"), ""))
+	}
+	paragraphs := []string{}
+	for _, block := range epubParagraphRe.Split(text, -1) {
+		if block = strings.Join(strings.Fields(block), " "); block != "" {
+			paragraphs = append(paragraphs, block)
+		}
+	}
+	return paragraphs
+}
+
+func epubChapter(article Article, summary string) string {
+	lines := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<!DOCTYPE html>`,
+		`<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">`,
+		`<head><title>` + html.EscapeString(article.Title) + `</title></head>`,
+		`<body>`,
+		`<h1>` + html.EscapeString(article.Title) + `</h1>`,
+	}
+	meta := []string{}
+	for _, value := range []string{article.FeedTitle, article.Author} {
+		if value != "" {
+			meta = append(meta, html.EscapeString(value))
+		}
+	}
+	if !article.PublishedAt.IsZero() {
+		meta = append(meta, article.PublishedAt.In(time.Local).Format("2006-01-02 15:04"))
+	}
+	if len(meta) > 0 {
+		lines = append(lines, `<p><em>`+strings.Join(meta, " · ")+`</em></p>`)
+	}
+	if summary = strings.TrimSpace(summary); summary != "" {
+		lines = append(lines, `<h2>Summary</h2>`)
+		for _, line := range strings.Split(summary, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, `<p>`+html.EscapeString(line)+`</p>`)
+			}
+		}
+		lines = append(lines, `<hr/>`)
+	}
+	for _, paragraph := range epubParagraphs(article) {
+		lines = append(lines, `<p>`+html.EscapeString(paragraph)+`</p>`)
+	}
+	lines = append(lines, `<p><a href="`+html.EscapeString(article.URL)+`">`+html.EscapeString(article.URL)+`</a></p>`, `</body>`, `</html>`)
+	return strings.Join(lines, "\n")
+}
+
+func epubPackage(title string, generated time.Time, articles []Article) string {
+	manifest := []string{`<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>`, `<item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>`}
+	spine := []string{}
+	for i := range articles {
+		id := fmt.Sprintf("article%d", i+1)
+		manifest = append(manifest, fmt.Sprintf(`<item id="%s" href="%s.xhtml" media-type="application/xhtml+xml"/>`, id, id))
+		spine = append(spine, fmt.Sprintf(`<itemref idref="%s"/>`, id))
+	}
+	return strings.Join([]string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="bookid">`,
+		`<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">`,
+		`<dc:identifier id="bookid">urn:greeder:` + generated.UTC().Format("20060102T150405Z") + `</dc:identifier>`,
+		`<dc:title>` + html.EscapeString(title) + `</dc:title>`,
+		`<dc:language>en</dc:language>`,
+		`<dc:creator>Greeder</dc:creator>`,
+		`<meta property="dcterms:modified">` + generated.UTC().Format("2006-01-02T15:04:05Z") + `</meta>`,
+		`</metadata>`,
+		`<manifest>`, strings.Join(manifest, "\n"), `</manifest>`,
+		`<spine toc="ncx">`, strings.Join(spine, "\n"), `</spine>`,
+		`</package>`,
+	}, "\n")
+}
+
+func epubNav(articles []Article) string {
+	items := []string{}
+	for i, article := range articles {
+		items = append(items, fmt.Sprintf(`<li><a href="article%d.xhtml">%s</a></li>`, i+1, html.EscapeString(article.Title)))
+	}
+	return strings.Join([]string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<!DOCTYPE html>`,
+		`<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">`,
+		`<head><title>Contents</title></head>`,
+		`<body><nav epub:type="toc"><h1>Contents</h1><ol>`,
+		strings.Join(items, "\n"),
+		`</ol></nav></body>`,
+		`</html>`,
+	}, "\n")
+}
+
+func epubNCX(title string, generated time.Time, articles []Article) string {
+	points := []string{}
+	for i, article := range articles {
+		points = append(points, fmt.Sprintf(`<navPoint id="nav%d" playOrder="%d"><navLabel><text>%s</text></navLabel><content src="article%d.xhtml"/></navPoint>`, i+1, i+1, html.EscapeString(article.Title), i+1))
+	}
+	return strings.Join([]string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">`,
+		`<head><meta name="dtb:uid" content="urn:greeder:` + generated.UTC().Format("20060102T150405Z") + `"/></head>`,
+		`<docTitle><text>` + html.EscapeString(title) + `</text></docTitle>`,
+		`<navMap>`, strings.Join(points, "\n"), `</navMap>`,
+		`</ncx>`,
+	}, "\n")
+}
+
+func writeEPUB(w io.Writer, title string, generated time.Time, articles []Article, summaries map[int]string) error {
+	archive := zip.NewWriter(w)
+	mimetype, err := archive.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return err
+	}
+	files := [][2]string{
+		{"META-INF/container.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`},
+		{"OEBPS/content.opf", epubPackage(title, generated, articles)},
+		{"OEBPS/nav.xhtml", epubNav(articles)},
+		{"OEBPS/toc.ncx", epubNCX(title, generated, articles)},
+	}
+	for i, article := range articles {
+		files = append(files, [2]string{fmt.Sprintf("OEBPS/article%d.xhtml", i+1), epubChapter(article, summaries[article.ID])})
+	}
+	for _, file := range files {
+		entry, err := archive.Create(file[0])
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(entry, file[1]); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (a *App) ExportEPUB(path string, selection string) (int, error) {
+	if strings.TrimSpace(path) == "" {
+		return 0, errors.New("missing epub path")
+	}
+	if selection != "starred" && selection != "unread" {
+		return 0, fmt.Errorf("unknown epub selection: %s", selection)
+	}
+	articles := []Article{}
+	for _, article := range a.allArticles() {
+		if (selection == "starred" && article.IsStarred) || (selection == "unread" && !article.IsRead) {
+			articles = append(articles, article)
+		}
+	}
+	if len(articles) == 0 {
+		return 0, fmt.Errorf("no %s articles to export", selection)
+	}
+	generated := a.now()
+	title := fmt.Sprintf("Greeder - %s articles %s", selection, generated.Format("2006-01-02"))
+	file, err := epubCreate(path)
+	if err != nil {
+		return 0, err
+	}
+	if err := writeEPUB(file, title, generated, articles, a.articleSummaries(articles)); err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	a.status = fmt.Sprintf("Exported %d %s articles to %s", len(articles), selection, path)
+	return len(articles), nil
+}
+
+func parseEPUBArgs(args []string) (string, string, error) {
+	selection := "starred"
+	path := ""
+	for _, arg := range args {
+		switch arg {
+		case "--starred", "starred":
+			selection = "starred"
+		case "--unread", "unread":
+			selection = "unread"
+		default:
+			path = arg
+		}
+	}
+	if path == "" {
+		return "", "", errors.New("missing epub path")
+	}
+	return selection, path, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readEPUB(t *testing.T, data []byte) (*zip.Reader, map[string]string) {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip error: %v", err)
+	}
+	files := map[string]string{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", file.Name, err)
+		}
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		files[file.Name] = string(body)
+	}
+	return reader, files
+}
+
+func TestEPUBParagraphs(t *testing.T) {
+	paragraphs := epubParagraphs(Article{Content: "<p>One &amp; <b>two</b></p><p>Three<br/>Four</p><div>  </div>"})
+	if strings.Join(paragraphs, "|") != "One & two|Three|Four" {
+		t.Fatalf("unexpected paragraphs %q", paragraphs)
+	}
+	if strings.Join(epubParagraphs(Article{ContentText: "plain text\n\nsecond"}), "|") != "plain text|second" {
+		t.Fatalf("expected plain text fallback")
+	}
+}
+
+func TestWriteEPUB(t *testing.T) {
+	generated := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	articles := []Article{
+		{ID: 1, Title: "Tom & Jerry <3", URL: "https://example.com/a?x=1&y=2", FeedTitle: "Feed", Author: "Ann", PublishedAt: generated, Content: "<p>Body</p>"},
+		{ID: 2, Title: "Second", URL: "https://example.com/b", ContentText: "text"},
+	}
+	var buf bytes.Buffer
+	if err := writeEPUB(&buf, "Title", generated, articles, map[int]string{1: "- point one\n- point two"}); err != nil {
+		t.Fatalf("writeEPUB error: %v", err)
+	}
+	reader, files := readEPUB(t, buf.Bytes())
+	if reader.File[0].Name != "mimetype" || reader.File[0].Method != zip.Store || files["mimetype"] != "application/epub+zip" {
+		t.Fatalf("mimetype must be first and stored")
+	}
+	for _, name := range []string{"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/nav.xhtml", "OEBPS/toc.ncx", "OEBPS/article1.xhtml", "OEBPS/article2.xhtml"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("missing %s", name)
+		}
+	}
+	chapter := files["OEBPS/article1.xhtml"]
+	for _, want := range []string{"<h1>Tom &amp; Jerry &lt;3</h1>", "<p><em>Feed · Ann · ", "<h2>Summary</h2>\n<p>- point one</p>\n<p>- point two</p>", "<p>Body</p>", `href="https://example.com/a?x=1&amp;y=2"`} {
+		if !strings.Contains(chapter, want) {
+			t.Fatalf("chapter missing %q:\n%s", want, chapter)
+		}
+	}
+	if strings.Contains(files["OEBPS/article2.xhtml"], "Summary") || strings.Contains(files["OEBPS/article2.xhtml"], "<em>") {
+		t.Fatalf("expected bare chapter:\n%s", files["OEBPS/article2.xhtml"])
+	}
+	if !strings.Contains(files["OEBPS/content.opf"], `<itemref idref="article2"/>`) || !strings.Contains(files["OEBPS/content.opf"], "2024-03-05T12:00:00Z") {
+		t.Fatalf("unexpected package:\n%s", files["OEBPS/content.opf"])
+	}
+	if !strings.Contains(files["OEBPS/nav.xhtml"], `<a href="article1.xhtml">Tom &amp; Jerry &lt;3</a>`) || !strings.Contains(files["OEBPS/toc.ncx"], `playOrder="2"`) {
+		t.Fatalf("unexpected navigation")
+	}
+}
+
+func TestParseEPUBArgs(t *testing.T) {
+	if selection, path, err := parseEPUBArgs([]string{"--unread", "out.epub"}); err != nil || selection != "unread" || path != "out.epub" {
+		t.Fatalf("unexpected parse %q %q %v", selection, path, err)
+	}
+	if selection, path, err := parseEPUBArgs([]string{"out.epub"}); err != nil || selection != "starred" || path != "out.epub" {
+		t.Fatalf("unexpected default parse %q %q %v", selection, path, err)
+	}
+	if _, _, err := parseEPUBArgs([]string{"--starred"}); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+}
+
+func TestAppExportEPUB(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "Starred", URL: "https://example.com/1", IsStarred: true, IsRead: true},
+		{GUID: "2", Title: "Unread", URL: "https://example.com/2"},
+		{GUID: "3", Title: "Unread too", URL: "https://example.com/3"},
+	}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	path := filepath.Join(t.TempDir(), "out.epub")
+	if count, err := app.ExportEPUB(path, "unread"); err != nil || count != 2 || app.status != "Exported 2 unread articles to "+path {
+		t.Fatalf("unexpected export %d %v %q", count, err, app.status)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read epub: %v", err)
+	}
+	if _, files := readEPUB(t, data); len(files) != 7 {
+		t.Fatalf("unexpected epub files %d", len(files))
+	}
+	if err := handleCommand(app, "epub starred "+path, io.Discard); err != nil || app.status != "Exported 1 starred articles to "+path {
+		t.Fatalf("unexpected epub command %v %q", err, app.status)
+	}
+	if err := handleCommand(app, "epub", io.Discard); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+	if _, err := app.ExportEPUB("", "starred"); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+	if _, err := app.ExportEPUB(path, "all"); err == nil {
+		t.Fatalf("expected selection error")
+	}
+	app.articles = nil
+	if _, err := app.ExportEPUB(path, "starred"); err == nil || err.Error() != "no starred articles to export" {
+		t.Fatalf("expected empty export error, got %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	orig := epubCreate
+	epubCreate = func(string) (io.WriteCloser, error) { return nil, errors.New("denied") }
+	if _, err := app.ExportEPUB(path, "starred"); err == nil {
+		t.Fatalf("expected create error")
+	}
+	epubCreate = func(string) (io.WriteCloser, error) { return failingWriteCloser{}, nil }
+	t.Cleanup(func() { epubCreate = orig })
+	if _, err := app.ExportEPUB(path, "starred"); err == nil {
+		t.Fatalf("expected write error")
+	}
+}
+
+type failingWriteCloser struct{}
+
+func (failingWriteCloser) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+func (failingWriteCloser) Close() error              { return nil }
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	exportFormatMarkdown = "markdown"
+	exportFormatHTML     = "html"
+)
+
+var exportReadFile = os.ReadFile
+
+func parseExportFormat(value string) (string, error) {
+	format := strings.ToLower(trimQuotes(value))
+	switch format {
+	case "", exportFormatMarkdown, exportFormatHTML:
+		return format, nil
+	case "md":
+		return exportFormatMarkdown, nil
+	}
+	return "", fmt.Errorf("invalid export_format: %q (expected markdown or html)", value)
+}
+
+func exportFileName(article Article, format string) string {
+	name := strings.TrimSuffix(noteFileName(article), ".md")
+	if format == exportFormatHTML {
+		return name + ".html"
+	}
+	return name + ".md"
+}
+
+func exportValues(article Article, summary string, tags []string, format string) map[string]string {
+	summary = strings.TrimSpace(summary)
+	paragraphs := epubParagraphs(article)
+	date, published := "", ""
+	if !article.PublishedAt.IsZero() {
+		date = article.PublishedAt.In(time.Local).Format("2006-01-02")
+		published = article.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	values := map[string]string{
+		"title":     article.Title,
+		"url":       article.URL,
+		"feed":      article.FeedTitle,
+		"author":    article.Author,
+		"date":      date,
+		"published": published,
+		"tags":      strings.Join(tags, ", "),
+		"summary":   summary,
+		"content":   strings.Join(paragraphs, "\n\n"),
+	}
+	if format != exportFormatHTML {
+		return values
+	}
+	for name, value := range values {
+		values[name] = html.EscapeString(value)
+	}
+	values["summary"] = htmlParagraphs(strings.Split(summary, "\n"))
+	values["content"] = htmlParagraphs(paragraphs)
+	return values
+}
+
+func htmlParagraphs(paragraphs []string) string {
+	lines := []string{}
+	for _, paragraph := range paragraphs {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			lines = append(lines, "<p>"+html.EscapeString(paragraph)+"</p>")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderArticleMarkdown(article Article, summary string, tags []string) string {
+	note := renderArticleNote(article, summary, tags)
+	if paragraphs := epubParagraphs(article); len(paragraphs) > 0 {
+		note += "\n## Article\n\n" + strings.Join(paragraphs, "\n\n") + "\n"
+	}
+	return note
+}
+
+func renderArticleHTML(article Article, summary string, tags []string) string {
+	values := exportValues(article, summary, tags, exportFormatHTML)
+	lines := []string{
+		`<!DOCTYPE html>`,
+		`<html>`,
+		`<head>`,
+		`<meta charset="utf-8">`,
+		`<title>` + values["title"] + `</title>`,
+	}
+	for _, name := range []string{"url", "feed", "author", "published", "tags"} {
+		if values[name] != "" {
+			lines = append(lines, `<meta name="`+name+`" content="`+values[name]+`">`)
+		}
+	}
+	lines = append(lines, `</head>`, `<body>`, `<h1>`+values["title"]+`</h1>`)
+	meta := []string{}
+	for _, name := range []string{"feed", "author", "date"} {
+		if values[name] != "" {
+			meta = append(meta, values[name])
+		}
+	}
+	if len(meta) > 0 {
+		lines = append(lines, `<p><em>`+strings.Join(meta, " · ")+`</em></p>`)
+	}
+	if values["summary"] != "" {
+		lines = append(lines, `<h2>Summary</h2>`, values["summary"], `<hr>`)
+	}
+	if values["content"] != "" {
+		lines = append(lines, values["content"])
+	}
+	lines = append(lines, `<p><a href="`+values["url"]+`">`+values["url"]+`</a></p>`, `</body>`, `</html>`, "")
+	return strings.Join(lines, "\n")
+}
+
+func renderArticleExport(article Article, summary string, tags []string, format string, template string) string {
+	if template != "" {
+		return expandEmailTemplate(template, exportValues(article, summary, tags, format))
+	}
+	if format == exportFormatHTML {
+		return renderArticleHTML(article, summary, tags)
+	}
+	return renderArticleMarkdown(article, summary, tags)
+}
+
+func (a *App) ExportArticle(article Article, format string) (string, error) {
+	dir := strings.TrimSpace(a.config.NotesDir)
+	if dir == "" {
+		return "", errors.New("notes_dir not configured")
+	}
+	if format == "" {
+		format = firstNonEmpty(a.config.ExportFormat, exportFormatMarkdown)
+	}
+	template := ""
+	if path := strings.TrimSpace(a.config.ExportTemplate); path != "" {
+		data, err := exportReadFile(expandHome(path))
+		if err != nil {
+			return "", fmt.Errorf("export_template: %w", err)
+		}
+		template = string(data)
+	}
+	summary := ""
+	if existing, ok := a.store.FindSummary(article.ID); ok {
+		summary = existing.Content
+	}
+	dir = expandHome(dir)
+	if err := notesMkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, exportFileName(article, format))
+	body := renderArticleExport(article, summary, a.noteTags(article), format, template)
+	if err := notesWriteFile(path, []byte(body), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (a *App) ExportSelectedArticle(format string) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	path, err := a.ExportArticle(*article, format)
+	if err != nil {
+		a.status = tr(msgExportFailed, err)
+		return err
+	}
+	a.status = tr(msgExportWritten, filepath.Base(path))
+	return nil
+}
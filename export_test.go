@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseExportFormat(t *testing.T) {
+	for value, want := range map[string]string{`""`: "", `"markdown"`: "markdown", `"MD"`: "markdown", "html": "html"} {
+		if got, err := parseExportFormat(value); err != nil || got != want {
+			t.Fatalf("parseExportFormat(%s) = %q, %v", value, got, err)
+		}
+	}
+	if _, err := parseExportFormat(`"pdf"`); err == nil {
+		t.Fatalf("expected invalid format error")
+	}
+	var cfg Config
+	if err := parseConfig("export_format = \"html\"\nexport_template = \"~/t.md\"", &cfg); err != nil || cfg.ExportFormat != "html" || cfg.ExportTemplate != "~/t.md" {
+		t.Fatalf("unexpected export config: %+v %v", cfg, err)
+	}
+	if rendered := renderConfig(cfg); !strings.Contains(rendered, "export_format = \"html\"") || !strings.Contains(rendered, "export_template = \"~/t.md\"") {
+		t.Fatalf("expected export settings in config:\n%s", rendered)
+	}
+}
+
+func TestRenderArticleExport(t *testing.T) {
+	published := time.Date(2024, 3, 5, 12, 0, 0, 0, time.Local)
+	article := Article{Title: "A <b> post", URL: "https://example.com/a?x=1&y=2", FeedTitle: "Feed", Author: "Ann", PublishedAt: published, Content: "<p>First &amp; one</p><p>Second</p>"}
+	if name := exportFileName(article, exportFormatHTML); name != "2024-03-05-a-b-post.html" {
+		t.Fatalf("unexpected html name %q", name)
+	}
+	if name := exportFileName(article, exportFormatMarkdown); name != "2024-03-05-a-b-post.md" {
+		t.Fatalf("unexpected markdown name %q", name)
+	}
+
+	markdown := renderArticleExport(article, "- point", []string{"go"}, exportFormatMarkdown, "")
+	for _, want := range []string{"---\ntitle: ", "tags: [\"go\"]", "## Summary\n\n- point", "## Article\n\nFirst & one\n\nSecond\n"} {
+		if !strings.Contains(markdown, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, markdown)
+		}
+	}
+	page := renderArticleExport(article, "- point", []string{"go"}, exportFormatHTML, "")
+	for _, want := range []string{"<title>A &lt;b&gt; post</title>", `<meta name="tags" content="go">`, "<p><em>Feed · Ann · 2024-03-05</em></p>", "<h2>Summary</h2>\n<p>- point</p>", "<p>First &amp; one</p>\n<p>Second</p>", `href="https://example.com/a?x=1&amp;y=2"`} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("html missing %q:\n%s", want, page)
+		}
+	}
+	if bare := renderArticleExport(Article{Title: "T", URL: "u"}, "", nil, exportFormatHTML, ""); strings.Contains(bare, "Summary") || strings.Contains(bare, "<em>") {
+		t.Fatalf("unexpected bare html:\n%s", bare)
+	}
+
+	template := "---\ntags: [{tags}]\ndate: {date}\n---\n# {title}\n{summary}\n{content}"
+	if got := renderArticleExport(article, "sum", []string{"go", "rss"}, exportFormatMarkdown, template); got != "---\ntags: [go, rss]\ndate: 2024-03-05\n---\n# A <b> post\nsum\nFirst & one\n\nSecond" {
+		t.Fatalf("unexpected markdown template output:\n%s", got)
+	}
+	if got := renderArticleExport(article, "sum", nil, exportFormatHTML, "<h1>{title}</h1>{content}"); got != "<h1>A &lt;b&gt; post</h1><p>First &amp; one</p>\n<p>Second</p>" {
+		t.Fatalf("unexpected html template output:\n%s", got)
+	}
+}
+
+func TestAppExportArticle(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	published := time.Date(2024, 3, 5, 12, 0, 0, 0, time.Local)
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1", ContentText: "Body text", PublishedAt: published}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "- summary"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.filter = FilterAll
+
+	if err := app.ExportSelectedArticle(""); err == nil || app.status != "Export failed: notes_dir not configured" {
+		t.Fatalf("expected missing notes_dir error, got %v %q", err, app.status)
+	}
+	dir := filepath.Join(t.TempDir(), "vault")
+	app.config.NotesDir = dir
+	model := newTUIModel(app)
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	model = updated.(tuiModel)
+	if app.status != "Exported: 2024-03-05-post.md" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "2024-03-05-post.md"))
+	if err != nil || !strings.Contains(string(data), "## Summary\n\n- summary") || !strings.Contains(string(data), "## Article\n\nBody text") {
+		t.Fatalf("unexpected export %v:\n%s", err, data)
+	}
+
+	var out bytes.Buffer
+	if err := handleCommand(app, "x html", &out); err != nil || app.status != "Exported: 2024-03-05-post.html" {
+		t.Fatalf("html export command: %v %q", err, app.status)
+	}
+	if err := handleCommand(app, "export-article pdf", &out); err == nil {
+		t.Fatalf("expected invalid format error")
+	}
+
+	template := filepath.Join(t.TempDir(), "note.md")
+	if err := os.WriteFile(template, []byte("{title} -> {url}"), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	app.config.ExportTemplate = template
+	path, err := app.ExportArticle(articles[0], "")
+	if err != nil {
+		t.Fatalf("template export error: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "Post -> https://example.com/1" {
+		t.Fatalf("unexpected templated export %q", data)
+	}
+	app.config.ExportTemplate = filepath.Join(t.TempDir(), "missing.md")
+	if _, err := app.ExportArticle(articles[0], ""); err == nil || !strings.Contains(err.Error(), "export_template") {
+		t.Fatalf("expected template read error, got %v", err)
+	}
+	app.config.ExportTemplate = ""
+
+	origWrite := notesWriteFile
+	notesWriteFile = func(string, []byte, os.FileMode) error { return errors.New("disk full") }
+	t.Cleanup(func() { notesWriteFile = origWrite })
+	if err := app.ExportSelectedArticle(""); err == nil || app.status != "Export failed: disk full" {
+		t.Fatalf("expected write error, got %v %q", err, app.status)
+	}
+	origMkdir := notesMkdirAll
+	notesMkdirAll = func(string, os.FileMode) error { return errors.New("denied") }
+	t.Cleanup(func() { notesMkdirAll = origMkdir })
+	if _, err := app.ExportArticle(articles[0], ""); err == nil {
+		t.Fatalf("expected mkdir error")
+	}
+
+	app.articles = nil
+	if err := app.ExportSelectedArticle(""); err != nil {
+		t.Fatalf("expected no-op without selection: %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const activityAccept = `application/activity+json, application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+var fediverseHandleRe = regexp.MustCompile(`^@?([A-Za-z0-9_.-]+)@([A-Za-z0-9.-]+\.[A-Za-z]{2,})$`)
+
+type activityActor struct {
+	Name              string          `json:"name"`
+	PreferredUsername string          `json:"preferredUsername"`
+	Summary           string          `json:"summary"`
+	URL               json.RawMessage `json:"url"`
+	Outbox            string          `json:"outbox"`
+}
+
+type activityCollection struct {
+	First        json.RawMessage   `json:"first"`
+	OrderedItems []json.RawMessage `json:"orderedItems"`
+}
+
+type activityObject struct {
+	ID           string          `json:"id"`
+	Type         string          `json:"type"`
+	Name         string          `json:"name"`
+	Summary      string          `json:"summary"`
+	Content      string          `json:"content"`
+	URL          json.RawMessage `json:"url"`
+	Published    string          `json:"published"`
+	AttributedTo json.RawMessage `json:"attributedTo"`
+	Object       json.RawMessage `json:"object"`
+}
+
+func parseFediverseHandle(input string) (string, string, bool) {
+	match := fediverseHandleRe.FindStringSubmatch(strings.TrimSpace(input))
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], strings.ToLower(match[2]), true
+}
+
+func isActivityJSON(contentType string) bool {
+	return strings.Contains(contentType, "activity+json") || strings.Contains(contentType, "ld+json")
+}
+
+func activityLink(raw json.RawMessage) string {
+	var value string
+	if json.Unmarshal(raw, &value) == nil {
+		return value
+	}
+	var link struct {
+		Href string `json:"href"`
+	}
+	if json.Unmarshal(raw, &link) == nil && link.Href != "" {
+		return link.Href
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		for _, item := range list {
+			if value := activityLink(item); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+func (f *FeedFetcher) getActivity(target string, out any) error {
+	req, err := http.NewRequestWithContext(f.requestContext(), http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", activityAccept)
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("activitypub: http %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (f *FeedFetcher) ResolveFediverse(handle string) (DiscoveredFeed, error) {
+	user, host, ok := parseFediverseHandle(handle)
+	if !ok {
+		return DiscoveredFeed{}, errors.New("invalid fediverse handle")
+	}
+	account := user + "@" + host
+	var finger struct {
+		Links []struct {
+			Rel  string `json:"rel"`
+			Type string `json:"type"`
+			Href string `json:"href"`
+		} `json:"links"`
+	}
+	resp, err := f.get("https://" + host + "/.well-known/webfinger?resource=" + url.QueryEscape("acct:"+account))
+	if err != nil {
+		return DiscoveredFeed{}, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return DiscoveredFeed{}, fmt.Errorf("webfinger: http %d", resp.StatusCode)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&finger)
+	resp.Body.Close()
+	if err != nil {
+		return DiscoveredFeed{}, err
+	}
+	actorURL, profileURL := "", ""
+	for _, link := range finger.Links {
+		switch {
+		case link.Rel == "self" && isActivityJSON(link.Type):
+			actorURL = link.Href
+		case link.Rel == "http://webfinger.net/rel/profile-page":
+			profileURL = link.Href
+		}
+	}
+	if actorURL == "" {
+		return DiscoveredFeed{}, errors.New("webfinger: no activitypub actor for " + account)
+	}
+	var actor activityActor
+	if err := f.getActivity(actorURL, &actor); err != nil {
+		return DiscoveredFeed{}, err
+	}
+	profileURL = firstNonEmpty(activityLink(actor.URL), profileURL, actorURL)
+	title := fmt.Sprintf("%s (@%s)", firstNonEmpty(strings.TrimSpace(actor.Name), actor.PreferredUsername, user), account)
+	if actor.Outbox != "" {
+		if articles, err := f.fetchOutbox(actor.Outbox, account); err == nil {
+			return DiscoveredFeed{Title: title, URL: actor.Outbox, SiteURL: profileURL, Description: stripHTML(actor.Summary), Articles: articles}, nil
+		}
+	}
+	parsed, err := f.DiscoverFeed(profileURL)
+	if err != nil {
+		return DiscoveredFeed{}, fmt.Errorf("no readable outbox or feed for %s: %w", account, err)
+	}
+	parsed.Title = title
+	parsed.SiteURL = profileURL
+	return parsed, nil
+}
+
+func (f *FeedFetcher) fetchOutbox(outboxURL string, author string) ([]Article, error) {
+	var collection activityCollection
+	if err := f.getActivity(outboxURL, &collection); err != nil {
+		return nil, err
+	}
+	return f.outboxArticles(collection, author)
+}
+
+func (f *FeedFetcher) outboxArticles(collection activityCollection, author string) ([]Article, error) {
+	if len(collection.OrderedItems) == 0 && len(collection.First) > 0 {
+		var page activityCollection
+		if json.Unmarshal(collection.First, &page) != nil || len(page.OrderedItems) == 0 {
+			first := activityLink(collection.First)
+			if first == "" {
+				return nil, errors.New("activitypub: empty outbox")
+			}
+			if err := f.getActivity(first, &page); err != nil {
+				return nil, err
+			}
+		}
+		collection = page
+	}
+	articles := []Article{}
+	for _, raw := range collection.OrderedItems {
+		var activity activityObject
+		if json.Unmarshal(raw, &activity) != nil || activity.Type != "Create" {
+			continue
+		}
+		var object activityObject
+		if json.Unmarshal(activity.Object, &object) != nil || object.ID == "" {
+			continue
+		}
+		articles = append(articles, activityArticle(object, author))
+	}
+	return articles, nil
+}
+
+func activityArticle(object activityObject, author string) Article {
+	text := stripHTML(object.Content)
+	title := strings.TrimSpace(object.Name)
+	if title == "" {
+		title = strings.TrimSpace(firstNonEmpty(object.Summary, text))
+		if runes := []rune(title); len(runes) > 80 {
+			title = strings.TrimSpace(string(runes[:79])) + "…"
+		}
+	}
+	return Article{
+		GUID:        object.ID,
+		Title:       firstNonEmpty(title, "Untitled"),
+		URL:         firstNonEmpty(activityLink(object.URL), object.ID),
+		Author:      "@" + author,
+		Content:     object.Content,
+		ContentText: text,
+		PublishedAt: parseTime(object.Published),
+	}
+}
+
+func (f *FeedFetcher) parseOutbox(feedURL string, body []byte) (DiscoveredFeed, error) {
+	var collection activityCollection
+	if err := json.Unmarshal(body, &collection); err != nil {
+		return DiscoveredFeed{}, err
+	}
+	author := ""
+	if parsed, err := url.Parse(feedURL); err == nil {
+		parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+		if len(parts) >= 2 {
+			author = parts[len(parts)-2] + "@" + parsed.Host
+		}
+	}
+	articles, err := f.outboxArticles(collection, author)
+	if err != nil {
+		return DiscoveredFeed{}, err
+	}
+	return DiscoveredFeed{URL: feedURL, Articles: articles}, nil
+}
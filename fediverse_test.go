@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const outboxPageSample = `{"type":"OrderedCollectionPage","orderedItems":[
+{"type":"Create","object":{"id":"https://mastodon.test/users/alice/statuses/1","type":"Note","content":"<p>Hello <b>fediverse</b>, this is a fairly long post that should be trimmed down to a sensible title length for the list</p>","url":"https://mastodon.test/@alice/1","published":"2024-03-01T10:00:00Z"}},
+{"type":"Announce","object":"https://elsewhere.test/notes/9"},
+{"type":"Create","object":{"id":"https://mastodon.test/users/alice/statuses/2","type":"Note","summary":"CW: lunch","content":"<p>Soup</p>","published":"2024-03-02T10:00:00Z"}}
+]}`
+
+func fediverseTestFetcher(t *testing.T) *FeedFetcher {
+	t.Helper()
+	activity := map[string]string{"content-type": "application/activity+json"}
+	return &FeedFetcher{client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Host + r.URL.Path {
+		case "mastodon.test/.well-known/webfinger", "quiet.test/.well-known/webfinger":
+			if !strings.HasPrefix(r.URL.Query().Get("resource"), "acct:alice@") {
+				return newResponse(http.StatusNotFound, "", nil, r), nil
+			}
+			host := r.URL.Host
+			return newResponse(http.StatusOK, `{"links":[{"rel":"http://webfinger.net/rel/profile-page","href":"https://`+host+`/@alice"},{"rel":"self","type":"application/activity+json","href":"https://`+host+`/users/alice"}]}`, nil, r), nil
+		case "mastodon.test/users/alice", "quiet.test/users/alice":
+			if !strings.Contains(r.Header.Get("Accept"), "activity+json") {
+				return newResponse(http.StatusNotAcceptable, "", nil, r), nil
+			}
+			return newResponse(http.StatusOK, `{"name":"Alice","preferredUsername":"alice","summary":"<p>Hi</p>","url":"https://`+r.URL.Host+`/@alice","outbox":"https://`+r.URL.Host+`/users/alice/outbox"}`, activity, r), nil
+		case "mastodon.test/users/alice/outbox":
+			if r.URL.Query().Get("page") == "true" {
+				return newResponse(http.StatusOK, outboxPageSample, activity, r), nil
+			}
+			return newResponse(http.StatusOK, `{"type":"OrderedCollection","first":"https://mastodon.test/users/alice/outbox?page=true"}`, activity, r), nil
+		case "quiet.test/@alice":
+			return newResponse(http.StatusOK, `<html><head><link rel="alternate" type="application/rss+xml" href="/@alice.rss"></head></html>`, map[string]string{"content-type": "text/html"}, r), nil
+		case "quiet.test/@alice.rss":
+			return newResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"}, r), nil
+		}
+		return newResponse(http.StatusNotFound, "", nil, r), nil
+	})}}
+}
+
+func TestParseFediverseHandle(t *testing.T) {
+	if user, host, ok := parseFediverseHandle(" @alice@Mastodon.Test "); !ok || user != "alice" || host != "mastodon.test" {
+		t.Fatalf("unexpected handle %q %q %v", user, host, ok)
+	}
+	for _, input := range []string{"example.com", "https://example.com/@alice", "alice@localhost", "@alice"} {
+		if _, _, ok := parseFediverseHandle(input); ok {
+			t.Fatalf("expected %q to be rejected", input)
+		}
+	}
+}
+
+func TestResolveFediverse(t *testing.T) {
+	fetcher := fediverseTestFetcher(t)
+	parsed, err := fetcher.ResolveFediverse("@alice@mastodon.test")
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	if parsed.Title != "Alice (@alice@mastodon.test)" || parsed.URL != "https://mastodon.test/users/alice/outbox" || parsed.SiteURL != "https://mastodon.test/@alice" || parsed.Description != "Hi" {
+		t.Fatalf("unexpected feed %+v", parsed)
+	}
+	if len(parsed.Articles) != 2 {
+		t.Fatalf("expected two notes, got %+v", parsed.Articles)
+	}
+	first := parsed.Articles[0]
+	if first.GUID != "https://mastodon.test/users/alice/statuses/1" || first.URL != "https://mastodon.test/@alice/1" || first.Author != "@alice@mastodon.test" || first.PublishedAt.IsZero() {
+		t.Fatalf("unexpected article %+v", first)
+	}
+	if !strings.HasPrefix(first.Title, "Hello fediverse") || !strings.HasSuffix(first.Title, "…") || len([]rune(first.Title)) > 80 {
+		t.Fatalf("unexpected title %q", first.Title)
+	}
+	if second := parsed.Articles[1]; second.Title != "CW: lunch" || second.URL != second.GUID || second.ContentText != "Soup" {
+		t.Fatalf("unexpected second article %+v", second)
+	}
+
+	fallback, err := fetcher.ResolveFediverse("alice@quiet.test")
+	if err != nil || fallback.Title != "Alice (@alice@quiet.test)" || fallback.URL != "https://quiet.test/@alice.rss" || len(fallback.Articles) == 0 {
+		t.Fatalf("expected rss fallback, got %+v %v", fallback, err)
+	}
+	if _, err := fetcher.ResolveFediverse("bob@mastodon.test"); err == nil {
+		t.Fatalf("expected webfinger error")
+	}
+	if _, err := fetcher.ResolveFediverse("not a handle"); err == nil {
+		t.Fatalf("expected invalid handle error")
+	}
+	fetcher.client = &http.Client{Transport: &errorRoundTripper{}}
+	if _, err := fetcher.ResolveFediverse("alice@mastodon.test"); err == nil {
+		t.Fatalf("expected transport error")
+	}
+}
+
+func TestFetchFeedOutbox(t *testing.T) {
+	parsed, err := fediverseTestFetcher(t).FetchFeed("https://mastodon.test/users/alice/outbox")
+	if err != nil || len(parsed.Articles) != 2 || parsed.Articles[0].Author != "@alice@mastodon.test" {
+		t.Fatalf("unexpected outbox refresh %+v %v", parsed, err)
+	}
+}
+
+func TestAppAddFediverseAccount(t *testing.T) {
+	app := newTUIApp(t)
+	app.fetcher = fediverseTestFetcher(t)
+	if err := app.AddFeed("@alice@mastodon.test"); err != nil {
+		t.Fatalf("add fediverse error: %v", err)
+	}
+	if len(app.feeds) != 1 || app.feeds[0].Title != "Alice (@alice@mastodon.test)" || app.status != "feed added" {
+		t.Fatalf("unexpected feeds %+v", app.feeds)
+	}
+	if err := app.AddFeed("@bob@mastodon.test"); err == nil {
+		t.Fatalf("expected unknown account error")
+	}
+}
@@ -2,19 +2,43 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
 
 type FeedFetcher struct {
 	client *http.Client
+	cache  *DiskCache
+	auth   map[string]FeedAuth
+	ctx    context.Context
+}
+
+const feedAccept = "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.9, application/activity+json;q=0.8, */*;q=0.5"
+
+type feedValidators struct {
+	ETag         string
+	LastModified string
+}
+
+var errFeedNotModified = errors.New("feed not modified")
+
+func (v feedValidators) empty() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+type FeedLink struct {
+	URL   string
+	Title string
 }
 
 type DiscoveredFeed struct {
@@ -31,46 +55,145 @@ func NewFeedFetcher() *FeedFetcher {
 	}
 }
 
+func (f *FeedFetcher) withContext(ctx context.Context) *FeedFetcher {
+	bound := *f
+	bound.ctx = ctx
+	return &bound
+}
+
+func (f *FeedFetcher) requestContext() context.Context {
+	if f.ctx == nil {
+		return context.Background()
+	}
+	return f.ctx
+}
+
+func (f *FeedFetcher) get(target string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(f.requestContext(), http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	f.authorize(req, target)
+	return f.client.Do(req)
+}
+
 func (f *FeedFetcher) FetchFeed(feedURL string) (DiscoveredFeed, error) {
-	resp, err := f.client.Get(feedURL)
+	parsed, _, err := f.fetchFeed(feedURL, feedValidators{})
+	return parsed, err
+}
+
+func (f *FeedFetcher) FetchFeedIfModified(feed Feed) (DiscoveredFeed, feedValidators, error) {
+	return f.fetchFeed(feed.URL, feedValidators{ETag: feed.ETag, LastModified: feed.LastModified})
+}
+
+func (f *FeedFetcher) fetchFeed(feedURL string, validators feedValidators) (DiscoveredFeed, feedValidators, error) {
+	req, err := http.NewRequestWithContext(f.requestContext(), http.MethodGet, feedURL, nil)
 	if err != nil {
-		return DiscoveredFeed{}, err
+		return DiscoveredFeed{}, validators, err
+	}
+	req.Header.Set("Accept", feedAccept)
+	f.authorize(req, feedURL)
+	cached, hasCached := f.cache.httpEntry(feedURL)
+	conditional := validators
+	if conditional.empty() && hasCached {
+		conditional = feedValidators{ETag: cached.ETag, LastModified: cached.LastModified}
+	}
+	if conditional.ETag != "" {
+		req.Header.Set("If-None-Match", conditional.ETag)
+	}
+	if conditional.LastModified != "" {
+		req.Header.Set("If-Modified-Since", conditional.LastModified)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return DiscoveredFeed{}, validators, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && !validators.empty() {
+		logFor("fetcher").Debug("not modified", "feed", feedURL)
+		return DiscoveredFeed{}, validators, errFeedNotModified
+	}
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		logFor("fetcher").Debug("not modified", "feed", feedURL)
+		parsed, err := f.parseCached(feedURL, cached)
+		return parsed, validators, err
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return DiscoveredFeed{}, fmt.Errorf("fetch feed: http %d", resp.StatusCode)
+		return DiscoveredFeed{}, validators, fmt.Errorf("fetch feed: http %d", resp.StatusCode)
 	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return DiscoveredFeed{}, err
+		return DiscoveredFeed{}, validators, err
+	}
+	f.cache.storeHTTP(feedURL, resp, body)
+	fresh := feedValidators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+	if isActivityJSON(resp.Header.Get("Content-Type")) {
+		parsed, err := f.parseOutbox(feedURL, body)
+		return parsed, fresh, err
+	}
+	parsed, err := parseFeed(feedURL, body)
+	return parsed, fresh, err
+}
+
+func (f *FeedFetcher) parseCached(feedURL string, cached httpCacheEntry) (DiscoveredFeed, error) {
+	if isActivityJSON(cached.ContentType) {
+		return f.parseOutbox(feedURL, cached.Body)
 	}
-	return parseFeed(feedURL, body)
+	return parseFeed(feedURL, cached.Body)
 }
 
 func (f *FeedFetcher) DiscoverFeed(startURL string) (DiscoveredFeed, error) {
-	resp, err := f.client.Get(startURL)
+	parsed, links, err := f.discoverPage(startURL)
+	if err != nil || len(links) == 0 {
+		return parsed, err
+	}
+	return f.FetchFeed(links[0].URL)
+}
+
+func (f *FeedFetcher) DiscoverCandidates(startURL string) ([]FeedLink, error) {
+	parsed, links, err := f.discoverPage(startURL)
 	if err != nil {
-		return DiscoveredFeed{}, err
+		return nil, err
+	}
+	if len(links) == 0 {
+		return []FeedLink{{URL: parsed.URL, Title: parsed.Title}}, nil
+	}
+	return links, nil
+}
+
+func (f *FeedFetcher) discoverPage(startURL string) (DiscoveredFeed, []FeedLink, error) {
+	resp, err := f.get(startURL)
+	if err != nil {
+		return DiscoveredFeed{}, nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return DiscoveredFeed{}, fmt.Errorf("discover feed: http %d", resp.StatusCode)
+		return DiscoveredFeed{}, nil, fmt.Errorf("discover feed: http %d", resp.StatusCode)
 	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return DiscoveredFeed{}, err
+		return DiscoveredFeed{}, nil, err
 	}
-	contentType := resp.Header.Get("content-type")
-	if isLikelyFeed(contentType, body) {
-		return parseFeed(resp.Request.URL.String(), body)
+	pageURL := resp.Request.URL.String()
+	if isLikelyFeed(resp.Header.Get("content-type"), body) {
+		parsed, err := parseFeed(pageURL, body)
+		return parsed, nil, err
 	}
 
-	feedURL := findFeedLink(string(body))
-	if feedURL == "" {
-		return DiscoveredFeed{}, errors.New("no feed link found")
+	links := []FeedLink{}
+	seen := map[string]bool{}
+	for _, link := range findFeedLinks(string(body)) {
+		link.URL = resolveURL(pageURL, link.URL)
+		if seen[link.URL] {
+			continue
+		}
+		seen[link.URL] = true
+		links = append(links, link)
+	}
+	if len(links) == 0 {
+		return DiscoveredFeed{}, nil, errors.New("no feed link found")
 	}
-	resolved := resolveURL(resp.Request.URL.String(), feedURL)
-	return f.FetchFeed(resolved)
+	return DiscoveredFeed{}, links, nil
 }
 
 func isLikelyFeed(contentType string, body []byte) bool {
@@ -81,18 +204,39 @@ func isLikelyFeed(contentType string, body []byte) bool {
 	return bytes.HasPrefix(trimmed, []byte("<?xml")) || bytes.HasPrefix(trimmed, []byte("<rss")) || bytes.HasPrefix(trimmed, []byte("<feed"))
 }
 
-func findFeedLink(html string) string {
-	linkRe := regexp.MustCompile(`(?i)<link[^>]+rel=["']alternate["'][^>]+type=["']application/(rss|atom)\+xml["'][^>]+href=["']([^"']+)["']`)
-	match := linkRe.FindStringSubmatch(html)
-	if len(match) >= 3 {
-		return match[2]
+func findFeedLink(page string) string {
+	links := findFeedLinks(page)
+	if len(links) == 0 {
+		return ""
 	}
-	altRe := regexp.MustCompile(`(?i)<link[^>]+type=["']application/(rss|atom)\+xml["'][^>]+href=["']([^"']+)["']`)
-	match = altRe.FindStringSubmatch(html)
-	if len(match) >= 3 {
-		return match[2]
+	return links[0].URL
+}
+
+func findFeedLinks(page string) []FeedLink {
+	tagRe := regexp.MustCompile(`(?i)<link\b[^>]*>`)
+	attrRe := regexp.MustCompile(`(?i)([a-z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	alternates := []FeedLink{}
+	others := []FeedLink{}
+	seen := map[string]bool{}
+	for _, tag := range tagRe.FindAllString(page, -1) {
+		attrs := map[string]string{}
+		for _, match := range attrRe.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(match[1])] = match[2] + match[3]
+		}
+		kind := strings.ToLower(strings.TrimSpace(attrs["type"]))
+		href := strings.TrimSpace(html.UnescapeString(attrs["href"]))
+		if href == "" || seen[href] || (kind != "application/rss+xml" && kind != "application/atom+xml") {
+			continue
+		}
+		seen[href] = true
+		link := FeedLink{URL: href, Title: strings.TrimSpace(html.UnescapeString(attrs["title"]))}
+		if slices.Contains(strings.Fields(strings.ToLower(attrs["rel"])), "alternate") {
+			alternates = append(alternates, link)
+		} else {
+			others = append(others, link)
+		}
 	}
-	return ""
+	return append(alternates, others...)
 }
 
 func resolveURL(baseURL string, href string) string {
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+)
+
+func feedAuthByKey(feedAuth map[string]FeedAuth) map[string]FeedAuth {
+	if len(feedAuth) == 0 {
+		return nil
+	}
+	byKey := make(map[string]FeedAuth, len(feedAuth))
+	for feedURL, auth := range feedAuth {
+		byKey[feedURLKey(feedURL)] = auth
+	}
+	return byKey
+}
+
+func (f *FeedFetcher) authorize(req *http.Request, target string) {
+	auth, ok := f.auth[feedURLKey(target)]
+	if !ok {
+		return
+	}
+	if auth.Username != "" || auth.Password != "" {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	if auth.HeaderName != "" {
+		req.Header.Set(auth.HeaderName, auth.HeaderValue)
+	}
+}
+
+func warnFeedAuthPermissions(path string, cfg Config) {
+	if len(cfg.FeedAuth) == 0 {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&0o077 == 0 {
+		return
+	}
+	logFor("config").Warn("config holds feed credentials but is readable by other users", "path", path, "mode", fmt.Sprintf("%#o", info.Mode().Perm()))
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigFeedAuth(t *testing.T) {
+	input := strings.Join([]string{
+		"[feeds.\"https://gitlab.example.com/group/project.atom\"]",
+		"header_name = \"PRIVATE-TOKEN\"",
+		"header_value = \"glpat-secret\"",
+		"",
+		"[feeds.\"https://jira.example.com/activity\"]",
+		"username = \"me\"",
+		"password = \"hunter2\"",
+	}, "\n")
+	cfg := DefaultConfig()
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	gitlab := cfg.FeedAuth["https://gitlab.example.com/group/project.atom"]
+	jira := cfg.FeedAuth["https://jira.example.com/activity"]
+	if gitlab.HeaderName != "PRIVATE-TOKEN" || gitlab.HeaderValue != "glpat-secret" || jira.Username != "me" || jira.Password != "hunter2" {
+		t.Fatalf("unexpected feed auth %+v", cfg.FeedAuth)
+	}
+	rendered := renderConfig(cfg)
+	if !strings.Contains(rendered, "[feeds.\"https://jira.example.com/activity\"]\nusername = \"me\"\npassword = \"hunter2\"") {
+		t.Fatalf("unexpected rendered config %s", rendered)
+	}
+	if err := parseConfig("[feeds.\"https://x.example.com/rss\"]\nheader_name = \"Authorization: Bearer\"", &cfg); err == nil || !strings.Contains(err.Error(), "invalid header_name") {
+		t.Fatalf("expected header name error, got %v", err)
+	}
+}
+
+func TestFeedFetcherAttachesFeedAuth(t *testing.T) {
+	type seen struct {
+		user, pass, token string
+		basic             bool
+	}
+	var requests []seen
+	fetcher := &FeedFetcher{client: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		user, pass, basic := req.BasicAuth()
+		requests = append(requests, seen{user: user, pass: pass, basic: basic, token: req.Header.Get("PRIVATE-TOKEN")})
+		return newResponse(http.StatusOK, rssSample, nil, req), nil
+	})}}
+	fetcher.auth = feedAuthByKey(map[string]FeedAuth{
+		"https://Jira.example.com/activity/":            {Username: "me", Password: "hunter2"},
+		"https://gitlab.example.com/group/project.atom": {HeaderName: "PRIVATE-TOKEN", HeaderValue: "glpat-secret"},
+	})
+	for _, target := range []string{"https://jira.example.com/activity", "https://gitlab.example.com/group/project.atom", "https://public.example.com/rss"} {
+		if _, err := fetcher.FetchFeed(target); err != nil {
+			t.Fatalf("FetchFeed %s: %v", target, err)
+		}
+	}
+	if len(requests) != 3 {
+		t.Fatalf("unexpected requests %+v", requests)
+	}
+	if !requests[0].basic || requests[0].user != "me" || requests[0].pass != "hunter2" || requests[0].token != "" {
+		t.Fatalf("expected basic auth, got %+v", requests[0])
+	}
+	if requests[1].basic || requests[1].token != "glpat-secret" {
+		t.Fatalf("expected token header, got %+v", requests[1])
+	}
+	if requests[2].basic || requests[2].token != "" {
+		t.Fatalf("expected no credentials, got %+v", requests[2])
+	}
+}
+
+func TestResolveCredentialsFeedAuth(t *testing.T) {
+	stubCredentialRun(t, func(name string, args []string, env []string) (string, error) {
+		switch args[1] {
+		case "pass show greeder/feed.https://jira.example.com/activity.password":
+			return "from-pass", nil
+		case "pass show greeder/feed.https://gitlab.example.com/project.atom.header_value":
+			return "glpat-pass", nil
+		}
+		return "", errors.New("exit status 1")
+	})
+	cfg := Config{
+		CredentialCommand: "pass show greeder/{name}",
+		FeedAuth: map[string]FeedAuth{
+			"https://jira.example.com/activity":       {Username: "me"},
+			"https://gitlab.example.com/project.atom": {HeaderName: "PRIVATE-TOKEN"},
+			"https://inline.example.com/rss":          {Username: "me", Password: "inline"},
+		},
+	}
+	resolved, _ := resolveCredentials(cfg)
+	if resolved.FeedAuth["https://jira.example.com/activity"].Password != "from-pass" ||
+		resolved.FeedAuth["https://gitlab.example.com/project.atom"].HeaderValue != "glpat-pass" ||
+		resolved.FeedAuth["https://inline.example.com/rss"].Password != "inline" {
+		t.Fatalf("unexpected feed auth %+v", resolved.FeedAuth)
+	}
+	if cfg.FeedAuth["https://jira.example.com/activity"].Password != "" {
+		t.Fatalf("expected original config untouched")
+	}
+}
+
+func TestWarnFeedAuthPermissions(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "greeder.log")
+	logCfg := DefaultConfig()
+	logCfg.LogFile = logPath
+	closeLog, err := setupLogging(logCfg)
+	if err != nil {
+		t.Fatalf("setupLogging error: %v", err)
+	}
+	t.Cleanup(func() { _ = closeLog() })
+	configPath := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(""), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg := Config{FeedAuth: map[string]FeedAuth{"https://x.example.com/rss": {Username: "me"}}}
+	warnFeedAuthPermissions(configPath, cfg)
+	if err := os.Chmod(configPath, 0o644); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	warnFeedAuthPermissions(configPath, Config{})
+	warnFeedAuthPermissions(configPath, cfg)
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	if got := strings.Count(string(data), "readable by other users"); got != 1 {
+		t.Fatalf("expected one warning, got %d in %s", got, data)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	feedFailingThreshold = 3
+	feedBackoffBase      = 15 * time.Minute
+	feedBackoffMax       = 24 * time.Hour
+)
+
+func (f Feed) failing() bool {
+	return f.FailCount >= feedFailingThreshold
+}
+
+func feedBackoff(failures int) time.Duration {
+	if failures < feedFailingThreshold {
+		return 0
+	}
+	delay := feedBackoffBase
+	for i := feedFailingThreshold; i < failures && delay < feedBackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, feedBackoffMax)
+}
+
+func (a *App) feedBackedOff(feed Feed, now time.Time) bool {
+	return feed.failing() && now.Before(feed.LastFailure.Add(feedBackoff(feed.FailCount)))
+}
+
+func (a *App) recordFeedHealth(feed Feed, err error) {
+	log := logFor("fetcher")
+	if err == nil || errors.Is(err, errFeedNotModified) {
+		if err := a.store.RecordFeedSuccess(feed.ID); err != nil {
+			log.Warn("store feed health failed", "feed", feed.URL, "err", err)
+		}
+		return
+	}
+	count, storeErr := a.store.RecordFeedFailure(feed.ID, err.Error())
+	if storeErr != nil {
+		log.Warn("store feed health failed", "feed", feed.URL, "err", storeErr)
+		return
+	}
+	if count >= feedFailingThreshold {
+		log.Warn("feed failing", "feed", feed.URL, "failures", count, "retry_in", feedBackoff(count))
+	}
+}
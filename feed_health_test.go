@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeedBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		0:  0,
+		2:  0,
+		3:  15 * time.Minute,
+		4:  30 * time.Minute,
+		6:  2 * time.Hour,
+		20: 24 * time.Hour,
+	}
+	for failures, want := range cases {
+		if got := feedBackoff(failures); got != want {
+			t.Fatalf("feedBackoff(%d) = %v, want %v", failures, got, want)
+		}
+	}
+}
+
+func TestRefreshBacksOffFailingFeeds(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	clock := &testClock{now: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	app.setClock(clock)
+	app.fetcher.cache = nil
+	requests := map[string]int{}
+	app.fetcher.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests[r.URL.Path]++
+		if r.URL.Path == "/news" {
+			return newResponse(http.StatusInternalServerError, "boom", nil, r), nil
+		}
+		return newResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"}, r), nil
+	})}
+	for range feedFailingThreshold {
+		if err := app.RefreshFeeds(); err != nil {
+			t.Fatalf("RefreshFeeds error: %v", err)
+		}
+	}
+	feed := app.findFeed(news.ID)
+	if !feed.failing() || feed.FailCount != 3 || app.findFeed(tech.ID).LastSuccess.IsZero() {
+		t.Fatalf("expected news failing and tech healthy, got %+v", app.feeds)
+	}
+	var out bytes.Buffer
+	if err := handleCommand(app, "feeds", &out); err != nil || !strings.Contains(out.String(), "2 News (2 unread, fetched ") || !strings.Contains(out.String(), ") failing (3 in a row, last ok never): fetch feed: http 500") {
+		t.Fatalf("unexpected feeds output %q %v", out.String(), err)
+	}
+
+	clock.now = clock.now.Add(10 * time.Minute)
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("RefreshFeeds error: %v", err)
+	}
+	if requests["/news"] != 3 || app.lastStats.BackedOff != 1 || !strings.Contains(app.status, "1 failing feeds backed off") {
+		t.Fatalf("expected news backed off, got %d requests %+v %q", requests["/news"], app.lastStats, app.status)
+	}
+	if err := app.RefreshFeed(news.ID); err != nil || requests["/news"] != 4 {
+		t.Fatalf("expected manual refresh to ignore backoff, got %d %v", requests["/news"], err)
+	}
+
+	clock.now = clock.now.Add(31 * time.Minute)
+	app.fetcher.client = clientForResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"})
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("RefreshFeeds error: %v", err)
+	}
+	if feed := app.findFeed(news.ID); feed.failing() || feed.LastError != "" || !feed.LastSuccess.Equal(clock.now) {
+		t.Fatalf("expected news recovered, got %+v", feed)
+	}
+}
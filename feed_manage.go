@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func (a *App) RefreshFeed(id int) error {
+	feed := a.findFeed(id)
+	if feed == nil {
+		return messageErr(msgFeedNotFound, id)
+	}
+	if feed.Source != "" {
+		return messageErr(msgFeedSynced, feed.Title, feed.Source)
+	}
+	title := feed.Title
+	a.runRefreshStartHook()
+	lastID := a.store.MaxArticleID()
+	a.refreshOnly = id
+	_, failed := a.fetchFeeds()
+	a.refreshOnly = 0
+	a.applyRetention()
+	a.feeds = a.store.Feeds()
+	_ = a.store.MergeDuplicateArticles()
+	a.loadArticles()
+	a.lastNew = a.store.ArticlesAfterID(lastID)
+	a.syncSummaryForSelection()
+	if failed > 0 {
+		lastError := ""
+		if refreshed := a.findFeed(id); refreshed != nil {
+			lastError = refreshed.LastError
+		}
+		a.status = tr(msgFeedRefreshFailed, title, lastError)
+		return nil
+	}
+	a.status = tr(msgFeedRefreshed, title, len(a.lastNew))
+	a.runRefreshHooks()
+	return nil
+}
+
+func (a *App) RenameFeed(id int, title string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return messageErr(msgFeedTitleEmpty)
+	}
+	if a.findFeed(id) == nil {
+		return messageErr(msgFeedNotFound, id)
+	}
+	if err := a.store.RenameFeed(id, title); err != nil {
+		return err
+	}
+	a.feeds = a.store.Feeds()
+	a.loadArticles()
+	a.status = tr(msgFeedRenamed, title)
+	return nil
+}
+
+func (a *App) SetFeedURL(id int, url string) error {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return errors.New("empty feed url")
+	}
+	if !strings.Contains(url, "://") {
+		url = "https://" + url
+	}
+	feed := a.findFeed(id)
+	if feed == nil {
+		return messageErr(msgFeedNotFound, id)
+	}
+	updated := *feed
+	updated.URL = url
+	if err := a.store.UpdateFeed(updated); err != nil {
+		return err
+	}
+	a.feeds = a.store.Feeds()
+	a.status = tr(msgFeedURLChanged, updated.Title)
+	return nil
+}
+
+func (a *App) RemoveFeed(id int) error {
+	feed := a.findFeed(id)
+	if feed == nil {
+		return messageErr(msgFeedNotFound, id)
+	}
+	title := feed.Title
+	if err := a.store.DeleteFeed(id); err != nil {
+		return err
+	}
+	a.store.CleanupOrphanSummaries()
+	a.feeds = a.store.Feeds()
+	a.loadArticles()
+	if a.scope.FeedID == id {
+		a.scope = feedScope{}
+	}
+	if count := len(a.FilteredArticles()); a.selectedIndex >= count {
+		a.selectedIndex = max(count-1, 0)
+	}
+	a.syncSummaryForSelection()
+	a.status = tr(msgFeedRemoved, title)
+	return nil
+}
+
+func (a *App) unreadCounts() map[int]int {
+	return a.store.UnreadCountByFeed()
+}
+
+func (a *App) scopeCounts() (int, int) {
+	scoped := a.scopedFeeds()
+	unread, total := 0, 0
+	for feedID, count := range a.unreadCounts() {
+		if scoped == nil || scoped[feedID] {
+			unread += count
+		}
+	}
+	if a.moreArticles {
+		for feedID, count := range a.store.ArticleCountByFeed() {
+			if scoped == nil || scoped[feedID] {
+				total += count
+			}
+		}
+		return unread, total
+	}
+	for _, article := range a.articles {
+		if scoped == nil || scoped[article.FeedID] {
+			total++
+		}
+	}
+	return unread, total
+}
+
+func formatFeedLine(feed Feed, unread int) string {
+	fetched := "never"
+	if !feed.LastFetched.IsZero() {
+		fetched = formatLocalTime(feed.LastFetched)
+	}
+	line := fmt.Sprintf("%d %s (%d unread, fetched %s)", feed.ID, feed.Title, unread, fetched)
+	switch {
+	case feed.failing():
+		lastOK := "never"
+		if !feed.LastSuccess.IsZero() {
+			lastOK = formatLocalTime(feed.LastSuccess)
+		}
+		line += fmt.Sprintf(" failing (%d in a row, last ok %s): %s", feed.FailCount, lastOK, feed.LastError)
+	case feed.LastError != "":
+		line += " error: " + feed.LastError
+	}
+	return line
+}
+
+func formatFeeds(feeds []Feed, unread map[int]int) string {
+	if len(feeds) == 0 {
+		return "No feeds."
+	}
+	lines := make([]string, 0, len(feeds))
+	for _, feed := range feeds {
+		lines = append(lines, formatFeedLine(feed, unread[feed.ID]))
+	}
+	return strings.Join(lines, "\n")
+}
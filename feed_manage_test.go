@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRefreshFeedFetchesOnlyThatFeed(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	techFetched := app.findFeed(tech.ID).LastFetched
+	requests := []string{}
+	app.fetcher.cache = nil
+	app.fetcher.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r.URL.String())
+		return newResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"}, r), nil
+	})}
+	if err := app.RefreshFeed(news.ID); err != nil {
+		t.Fatalf("RefreshFeed error: %v", err)
+	}
+	if len(requests) != 1 || requests[0] != news.URL {
+		t.Fatalf("expected only %s fetched, got %v", news.URL, requests)
+	}
+	if !strings.HasPrefix(app.status, "Refreshed News; ") || len(app.lastNew) == 0 {
+		t.Fatalf("unexpected refresh state %q %+v", app.status, app.findFeed(news.ID))
+	}
+	if app.refreshOnly != 0 || !app.findFeed(tech.ID).LastFetched.Equal(techFetched) {
+		t.Fatalf("expected other feeds untouched")
+	}
+	if err := app.RefreshFeed(99); errorCode(err) != string(msgFeedNotFound) {
+		t.Fatalf("expected feed not found, got %v", err)
+	}
+	if err := app.store.SetFeedSource(tech.ID, "miniflux"); err != nil {
+		t.Fatalf("SetFeedSource error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	if err := app.RefreshFeed(tech.ID); errorCode(err) != string(msgFeedSynced) {
+		t.Fatalf("expected synced feed error, got %v", err)
+	}
+}
+
+func TestRefreshRecordsFeedErrors(t *testing.T) {
+	app, _, news := newFolderApp(t)
+	app.fetcher.cache = nil
+	app.fetcher.client = clientForResponse(http.StatusInternalServerError, "boom", nil)
+	if err := app.RefreshFeed(news.ID); err != nil {
+		t.Fatalf("RefreshFeed error: %v", err)
+	}
+	feed := app.findFeed(news.ID)
+	if feed.LastError == "" || !strings.HasPrefix(app.status, "Refresh of News failed: ") {
+		t.Fatalf("expected feed error recorded, got %+v %q", feed, app.status)
+	}
+	app.fetcher.client = clientForResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"})
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("RefreshFeeds error: %v", err)
+	}
+	if feed := app.findFeed(news.ID); feed.LastError != "" {
+		t.Fatalf("expected error cleared, got %q", feed.LastError)
+	}
+}
+
+func TestRenameChangeURLAndRemoveFeed(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	if err := app.RenameFeed(tech.ID, "  Engineering "); err != nil || app.status != "Renamed feed to Engineering" {
+		t.Fatalf("RenameFeed error: %v %q", err, app.status)
+	}
+	for _, article := range app.articles {
+		if article.FeedID == tech.ID && article.FeedTitle != "Engineering" {
+			t.Fatalf("expected article feed titles renamed, got %+v", article)
+		}
+	}
+	if err := app.RenameFeed(tech.ID, " "); errorCode(err) != string(msgFeedTitleEmpty) {
+		t.Fatalf("expected empty title error, got %v", err)
+	}
+	if err := app.store.SetFeedValidators(news.ID, `"v1"`, ""); err != nil {
+		t.Fatalf("SetFeedValidators error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	if err := app.SetFeedURL(news.ID, "example.com/atom"); err != nil {
+		t.Fatalf("SetFeedURL error: %v", err)
+	}
+	if feed := app.findFeed(news.ID); feed.URL != "https://example.com/atom" || feed.ETag != "" || app.status != "Updated URL for News" {
+		t.Fatalf("unexpected feed after url change %+v %q", feed, app.status)
+	}
+	if err := app.SetFeedURL(news.ID, ""); err == nil {
+		t.Fatalf("expected empty url error")
+	}
+	if err := app.SetFeedURL(news.ID, tech.URL); err == nil {
+		t.Fatalf("expected duplicate url error")
+	}
+	app.SetFeedScope(feedScope{FeedID: news.ID})
+	app.selectedIndex = 1
+	if err := app.RemoveFeed(news.ID); err != nil {
+		t.Fatalf("RemoveFeed error: %v", err)
+	}
+	if len(app.feeds) != 1 || len(app.articles) != 1 || !app.scope.empty() || app.selectedIndex != 0 || app.status != "Removed News and its articles" {
+		t.Fatalf("unexpected state after remove %+v %+v %q", app.feeds, app.scope, app.status)
+	}
+	for _, call := range []func() error{
+		func() error { return app.RenameFeed(news.ID, "x") },
+		func() error { return app.SetFeedURL(news.ID, "https://example.com/x") },
+		func() error { return app.RemoveFeed(news.ID) },
+	} {
+		if err := call(); errorCode(err) != string(msgFeedNotFound) {
+			t.Fatalf("expected feed not found, got %v", err)
+		}
+	}
+}
+
+func TestFeedCommands(t *testing.T) {
+	app, _, _ := newFolderApp(t)
+	if err := app.store.SetFeedError(2, "timeout"); err != nil {
+		t.Fatalf("SetFeedError error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	var out bytes.Buffer
+	if err := handleCommand(app, "feeds", &out); err != nil {
+		t.Fatalf("feeds error: %v", err)
+	}
+	if !strings.Contains(out.String(), "1 Tech (1 unread, fetched ") || !strings.Contains(out.String(), "2 News (2 unread, fetched ") || !strings.Contains(out.String(), "error: timeout") {
+		t.Fatalf("unexpected feeds output %q", out.String())
+	}
+	if err := handleCommand(app, "rename 1 Tech Weekly", io.Discard); err != nil || app.findFeed(1).Title != "Tech Weekly" {
+		t.Fatalf("rename error: %v", err)
+	}
+	if err := handleCommand(app, "feed-url 1 https://example.com/weekly", io.Discard); err != nil || app.findFeed(1).URL != "https://example.com/weekly" {
+		t.Fatalf("feed-url error: %v", err)
+	}
+	if err := handleCommand(app, "remove 2", io.Discard); err != nil || len(app.feeds) != 1 {
+		t.Fatalf("remove error: %v", err)
+	}
+	for _, line := range []string{"rename 1", "feed-url 1", "remove", "remove x", "rename x y", "feed-url x y", "r 0"} {
+		if err := handleCommand(app, line, io.Discard); err == nil {
+			t.Fatalf("expected error for %q", line)
+		}
+	}
+	if formatFeeds(nil, nil) != "No feeds." {
+		t.Fatalf("unexpected empty feeds output")
+	}
+	if line := formatFeedLine(Feed{ID: 3, Title: "New"}, 0); line != "3 New (0 unread, fetched never)" {
+		t.Fatalf("unexpected feed line %q", line)
+	}
+}
+
+func TestUnreadCounts(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	if counts := app.store.UnreadCountByFeed(); len(counts) != 2 || counts[tech.ID] != 1 || counts[news.ID] != 2 {
+		t.Fatalf("unexpected unread counts %+v", counts)
+	}
+	if unread, total := app.scopeCounts(); unread != 3 || total != 3 {
+		t.Fatalf("unexpected counts %d/%d", unread, total)
+	}
+	model := newTUIModel(app)
+	model.width, model.height = 120, 30
+	if view := model.View(); !strings.Contains(view, "Greeder — 3 unread / 3 total") {
+		t.Fatalf("expected counts in header:\n%s", view)
+	}
+
+	for i, article := range app.FilteredArticles() {
+		if article.FeedID == news.ID {
+			app.selectedIndex = i
+			break
+		}
+	}
+	if err := app.ToggleRead(); err != nil {
+		t.Fatalf("ToggleRead error: %v", err)
+	}
+	if counts := app.store.UnreadCountByFeed(); counts[news.ID] != 1 {
+		t.Fatalf("expected count to follow mark read, got %+v", counts)
+	}
+	app.SetFeedScope(feedScope{FeedID: news.ID})
+	if view := model.View(); !strings.Contains(view, "Greeder · News — 1 unread / 2 total") {
+		t.Fatalf("expected scoped counts in header:\n%s", view)
+	}
+	if err := app.store.SetArticleRead(app.articles[0].ID, true); err != nil {
+		t.Fatalf("SetArticleRead error: %v", err)
+	}
+	if err := app.store.SetArticleRead(app.articles[1].ID, true); err != nil {
+		t.Fatalf("SetArticleRead error: %v", err)
+	}
+	if err := app.store.SetArticleRead(app.articles[2].ID, true); err != nil {
+		t.Fatalf("SetArticleRead error: %v", err)
+	}
+	if counts := app.store.UnreadCountByFeed(); len(counts) != 0 {
+		t.Fatalf("expected no unread feeds, got %+v", counts)
+	}
+}
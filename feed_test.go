@@ -126,6 +126,9 @@ func TestHelpers(t *testing.T) {
 	if got := findFeedLink("<link type=\"application/rss+xml\" href=\"/alt\" rel=\"alternate\" />"); got != "/alt" {
 		t.Fatalf("unexpected feed link alt: %s", got)
 	}
+	if got := findFeedLinks("<link rel=\"alternate\" type=\"application/rss+xml\" href=\"/a\" title=\"Posts\"><link type=\"application/atom+xml\" href=\"/b\"><link rel=\"alternate\" type=\"application/rss+xml\" href=\"/a\">"); len(got) != 2 || got[0] != (FeedLink{URL: "/a", Title: "Posts"}) || got[1].URL != "/b" {
+		t.Fatalf("unexpected feed links: %v", got)
+	}
 	if got := resolveURL("https://example.com/base", "/feed"); !strings.HasPrefix(got, "https://example.com") {
 		t.Fatalf("unexpected resolved url: %s", got)
 	}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	feverAPIVersion = 3
+	feverPageSize   = 50
+)
+
+var feverListen = net.Listen
+
+var feverImageRe = regexp.MustCompile(`(<img\b[^>]*?\bsrc=")(https?://[^"]+)(")`)
+
+type feverServer struct {
+	store  *Store
+	apiKey string
+	images *FeedFetcher
+}
+
+type feverGroup struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type feverFeedsGroup struct {
+	GroupID int    `json:"group_id"`
+	FeedIDs string `json:"feed_ids"`
+}
+
+type feverFeed struct {
+	ID                int    `json:"id"`
+	FaviconID         int    `json:"favicon_id"`
+	Title             string `json:"title"`
+	URL               string `json:"url"`
+	SiteURL           string `json:"site_url"`
+	IsSpark           int    `json:"is_spark"`
+	LastUpdatedOnTime int64  `json:"last_updated_on_time"`
+}
+
+type feverFavicon struct {
+	ID   int    `json:"id"`
+	Data string `json:"data"`
+}
+
+type feverItem struct {
+	ID            int    `json:"id"`
+	FeedID        int    `json:"feed_id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	HTML          string `json:"html"`
+	URL           string `json:"url"`
+	IsSaved       int    `json:"is_saved"`
+	IsRead        int    `json:"is_read"`
+	CreatedOnTime int64  `json:"created_on_time"`
+}
+
+func feverAPIKey(username string, password string) string {
+	sum := md5.Sum([]byte(username + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+func newFeverServer(store *Store, cfg Config) (*feverServer, error) {
+	if cfg.FeverUsername == "" || cfg.FeverPassword == "" {
+		return nil, errors.New("fever.username and fever.password are required")
+	}
+	return &feverServer{store: store, apiKey: feverAPIKey(cfg.FeverUsername, cfg.FeverPassword), images: newImageFetcher(cfg)}, nil
+}
+
+func startFeverServer(addr string, store *Store, cfg Config) (net.Addr, func() error, error) {
+	fever, err := newFeverServer(store, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	listener, err := feverListen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	server := &http.Server{Handler: fever, ReadHeaderTimeout: 5 * time.Second}
+	go server.Serve(listener)
+	return listener.Addr(), server.Close, nil
+}
+
+func (f *feverServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path == "/image" {
+		f.serveImage(w, r)
+		return
+	}
+	if _, ok := r.Form["api"]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+	response := map[string]any{"api_version": feverAPIVersion, "auth": 0}
+	key := strings.ToLower(r.Form.Get("api_key"))
+	if subtle.ConstantTimeCompare([]byte(key), []byte(f.apiKey)) != 1 {
+		writeFeverResponse(w, response)
+		return
+	}
+	response["auth"] = 1
+	log := logFor("fever")
+	if err := f.mark(r.Form); err != nil {
+		log.Warn("mark failed", "err", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	feeds := f.store.Feeds()
+	response["last_refreshed_on_time"] = feverLastRefreshed(feeds)
+	if feverHas(r.Form, "groups") || feverHas(r.Form, "feeds") {
+		groups, feedsGroups := f.groups(feeds)
+		if feverHas(r.Form, "groups") {
+			response["groups"] = groups
+		}
+		response["feeds_groups"] = feedsGroups
+	}
+	if feverHas(r.Form, "feeds") {
+		items := make([]feverFeed, 0, len(feeds))
+		for _, feed := range feeds {
+			item := feverFeed{ID: feed.ID, Title: feed.Title, URL: feed.URL, SiteURL: feed.SiteURL, LastUpdatedOnTime: timeToUnix(feed.LastFetched)}
+			if f.images != nil && feed.SiteURL != "" {
+				item.FaviconID = feed.ID
+			}
+			items = append(items, item)
+		}
+		response["feeds"] = items
+	}
+	if feverHas(r.Form, "favicons") {
+		response["favicons"] = f.favicons(feeds)
+	}
+	if feverHas(r.Form, "links") {
+		response["links"] = []any{}
+	}
+	if feverHas(r.Form, "items") || feverHas(r.Form, "unread_item_ids") || feverHas(r.Form, "saved_item_ids") {
+		articles := f.store.Articles()
+		if feverHas(r.Form, "items") {
+			items := feverItems(articles, r.Form)
+			if f.images != nil {
+				scheme := "http"
+				if r.Header.Get("X-Forwarded-Proto") == "https" {
+					scheme = "https"
+				}
+				f.proxyImages(items, scheme+"://"+r.Host)
+			}
+			response["items"] = items
+			response["total_items"] = len(articles)
+		}
+		if feverHas(r.Form, "unread_item_ids") {
+			response["unread_item_ids"] = feverItemIDs(articles, func(article Article) bool { return !article.IsRead })
+		}
+		if feverHas(r.Form, "saved_item_ids") {
+			response["saved_item_ids"] = feverItemIDs(articles, func(article Article) bool { return article.IsStarred })
+		}
+	}
+	writeFeverResponse(w, response)
+}
+
+func (f *feverServer) mark(form url.Values) error {
+	kind := form.Get("mark")
+	if kind == "" {
+		return nil
+	}
+	as := form.Get("as")
+	id, err := strconv.Atoi(form.Get("id"))
+	if err != nil {
+		return fmt.Errorf("invalid id: %q", form.Get("id"))
+	}
+	switch kind {
+	case "item":
+		switch as {
+		case "read", "unread":
+			return f.store.SetArticleRead(id, as == "read")
+		case "saved", "unsaved":
+			return f.store.SetArticleStarred(id, as == "saved")
+		}
+	case "feed", "group":
+		if as != "read" {
+			break
+		}
+		before := time.Now()
+		if value := form.Get("before"); value != "" {
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid before: %q", value)
+			}
+			before = time.Unix(seconds, 0)
+		}
+		return f.store.MarkFeedsRead(f.markTargets(kind, id), before)
+	}
+	return fmt.Errorf("unsupported mark: %s as %s", kind, as)
+}
+
+func (f *feverServer) markTargets(kind string, id int) []int {
+	if kind == "feed" {
+		return []int{id}
+	}
+	feeds := f.store.Feeds()
+	folderIDs := f.store.FolderIDs()
+	ids := []int{}
+	for _, feed := range feeds {
+		if id == 0 || feed.Folder != "" && folderIDs[feed.Folder] == id {
+			ids = append(ids, feed.ID)
+		}
+	}
+	return ids
+}
+
+func (f *feverServer) groups(feeds []Feed) ([]feverGroup, []feverFeedsGroup) {
+	folderIDs := f.store.FolderIDs()
+	members := map[int][]string{}
+	for _, feed := range feeds {
+		if id, ok := folderIDs[feed.Folder]; ok {
+			members[id] = append(members[id], strconv.Itoa(feed.ID))
+		}
+	}
+	groups := []feverGroup{}
+	feedsGroups := []feverFeedsGroup{}
+	for _, folder := range f.store.Folders() {
+		id := folderIDs[folder]
+		groups = append(groups, feverGroup{ID: id, Title: folder})
+		feedsGroups = append(feedsGroups, feverFeedsGroup{GroupID: id, FeedIDs: strings.Join(members[id], ",")})
+	}
+	return groups, feedsGroups
+}
+
+// favicons lists a favicon per feed with a site URL, keyed by feed ID. They
+// come from the image cache, so only the first request goes to the sites.
+func (f *feverServer) favicons(feeds []Feed) []feverFavicon {
+	favicons := []feverFavicon{}
+	if f.images == nil {
+		return favicons
+	}
+	for _, feed := range feeds {
+		if feed.SiteURL == "" {
+			continue
+		}
+		icon, err := f.images.Favicon(feed.SiteURL)
+		if err != nil {
+			logFor("fever").Debug("favicon unavailable", "site", feed.SiteURL, "err", err)
+			continue
+		}
+		favicons = append(favicons, feverFavicon{ID: feed.ID, Data: icon.ContentType + ";base64," + base64.StdEncoding.EncodeToString(icon.Body)})
+	}
+	return favicons
+}
+
+// proxyImages points remote images in item HTML at /image on this server so
+// clients load them through the image cache. Each link carries a signature
+// over the image URL, keyed by the API key, so the proxy only fetches URLs
+// this server handed out.
+func (f *feverServer) proxyImages(items []feverItem, base string) {
+	for i := range items {
+		items[i].HTML = feverImageRe.ReplaceAllStringFunc(items[i].HTML, func(tag string) string {
+			parts := feverImageRe.FindStringSubmatch(tag)
+			src := html.UnescapeString(parts[2])
+			proxied := base + "/image?" + url.Values{"url": {src}, "sig": {f.imageSignature(src)}}.Encode()
+			return parts[1] + html.EscapeString(proxied) + parts[3]
+		})
+	}
+}
+
+func (f *feverServer) imageSignature(imageURL string) string {
+	mac := hmac.New(sha256.New, []byte(f.apiKey))
+	mac.Write([]byte(imageURL))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (f *feverServer) serveImage(w http.ResponseWriter, r *http.Request) {
+	imageURL := r.Form.Get("url")
+	if f.images == nil || imageURL == "" || !hmac.Equal([]byte(r.Form.Get("sig")), []byte(f.imageSignature(imageURL))) {
+		http.NotFound(w, r)
+		return
+	}
+	image, err := f.images.Image(imageURL)
+	if err != nil {
+		logFor("fever").Debug("image unavailable", "url", imageURL, "err", err)
+		http.Error(w, "image unavailable", http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("content-type", image.ContentType)
+	w.Header().Set("cache-control", "max-age=86400")
+	_, _ = w.Write(image.Body)
+}
+
+func feverItems(articles []Article, form url.Values) []feverItem {
+	selected := []Article{}
+	switch {
+	case form.Get("with_ids") != "":
+		wanted := map[int]bool{}
+		for _, part := range strings.Split(form.Get("with_ids"), ",") {
+			if id, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+				wanted[id] = true
+			}
+		}
+		for _, article := range articles {
+			if wanted[article.ID] {
+				selected = append(selected, article)
+			}
+		}
+	case form.Get("since_id") != "":
+		since, _ := strconv.Atoi(form.Get("since_id"))
+		for _, article := range articles {
+			if article.ID > since {
+				selected = append(selected, article)
+			}
+		}
+	default:
+		maxID, err := strconv.Atoi(form.Get("max_id"))
+		for _, article := range articles {
+			if err != nil || article.ID < maxID {
+				selected = append(selected, article)
+			}
+		}
+		sort.Slice(selected, func(i, j int) bool { return selected[i].ID > selected[j].ID })
+	}
+	if len(selected) > feverPageSize {
+		selected = selected[:feverPageSize]
+	}
+	items := make([]feverItem, 0, len(selected))
+	for _, article := range selected {
+		created := article.PublishedAt
+		if created.IsZero() {
+			created = article.FetchedAt
+		}
+		items = append(items, feverItem{
+			ID:            article.ID,
+			FeedID:        article.FeedID,
+			Title:         article.Title,
+			Author:        article.Author,
+			HTML:          firstNonEmpty(article.Content, article.ContentText),
+			URL:           article.URL,
+			IsSaved:       boolToInt(article.IsStarred),
+			IsRead:        boolToInt(article.IsRead),
+			CreatedOnTime: timeToUnix(created),
+		})
+	}
+	return items
+}
+
+func feverItemIDs(articles []Article, include func(Article) bool) string {
+	ids := []string{}
+	for _, article := range articles {
+		if include(article) {
+			ids = append(ids, strconv.Itoa(article.ID))
+		}
+	}
+	return strings.Join(ids, ",")
+}
+
+func feverLastRefreshed(feeds []Feed) int64 {
+	var last int64
+	for _, feed := range feeds {
+		if fetched := timeToUnix(feed.LastFetched); fetched > last {
+			last = fetched
+		}
+	}
+	return last
+}
+
+func feverHas(form url.Values, key string) bool {
+	_, ok := form[key]
+	return ok
+}
+
+func writeFeverResponse(w http.ResponseWriter, response map[string]any) {
+	w.Header().Set("content-type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFeverTestServer(t *testing.T) (*feverServer, Feed, Feed, []Article) {
+	t.Helper()
+	store := newTestStore(t)
+	tech, err := store.InsertFeed(Feed{Title: "Tech", URL: "https://tech.example/rss", SiteURL: "https://tech.example", Folder: "Work"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	news, err := store.InsertFeed(Feed{Title: "News", URL: "https://news.example/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	added, err := store.InsertArticles(tech, []Article{{GUID: "t1", Title: "Go", URL: "https://tech.example/go", Author: "Ann", Content: "<p>go</p>", PublishedAt: published}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	more, err := store.InsertArticles(news, []Article{{GUID: "n1", Title: "Rain", PublishedAt: published.Add(time.Hour)}, {GUID: "n2", Title: "Sun", PublishedAt: published.Add(48 * time.Hour)}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	server, err := newFeverServer(store, Config{FeverUsername: "me", FeverPassword: "secret"})
+	if err != nil {
+		t.Fatalf("newFeverServer error: %v", err)
+	}
+	return server, tech, news, append(added, more...)
+}
+
+func feverRequest(t *testing.T, server *feverServer, query string, form url.Values) map[string]any {
+	t.Helper()
+	if form == nil {
+		form = url.Values{}
+	}
+	form.Set("api_key", feverAPIKey("me", "secret"))
+	req := httptest.NewRequest(http.MethodPost, "/?api&"+query, strings.NewReader(form.Encode()))
+	req.Header.Set("content-type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	var response map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	return response
+}
+
+func TestFeverAuth(t *testing.T) {
+	server, _, _, _ := newFeverTestServer(t)
+	if feverAPIKey("me", "secret") != "5f67bbe865987f84db7ba3daea424dcf" {
+		t.Fatalf("unexpected api key %s", feverAPIKey("me", "secret"))
+	}
+	req := httptest.NewRequest(http.MethodPost, "/?api&feeds", strings.NewReader("api_key=wrong"))
+	req.Header.Set("content-type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if body := rec.Body.String(); !strings.Contains(body, `"auth":0`) || !strings.Contains(body, `"api_version":3`) || strings.Contains(body, "Tech") {
+		t.Fatalf("expected unauthenticated response, got %s", body)
+	}
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without api param, got %d", rec.Code)
+	}
+	if _, err := newFeverServer(nil, Config{FeverUsername: "me"}); err == nil {
+		t.Fatalf("expected missing password error")
+	}
+}
+
+func TestFeverFeedsAndGroups(t *testing.T) {
+	server, tech, news, _ := newFeverTestServer(t)
+	response := feverRequest(t, server, "groups&feeds", nil)
+	if response["auth"] != float64(1) {
+		t.Fatalf("expected auth, got %v", response)
+	}
+	groups := response["groups"].([]any)
+	if len(groups) != 1 || groups[0].(map[string]any)["title"] != "Work" {
+		t.Fatalf("unexpected groups %v", groups)
+	}
+	feedsGroups := response["feeds_groups"].([]any)
+	if len(feedsGroups) != 1 || feedsGroups[0].(map[string]any)["feed_ids"] != strconv.Itoa(tech.ID) {
+		t.Fatalf("unexpected feeds_groups %v", feedsGroups)
+	}
+	feeds := response["feeds"].([]any)
+	if len(feeds) != 2 || feeds[0].(map[string]any)["site_url"] != "https://tech.example" || feeds[1].(map[string]any)["id"] != float64(news.ID) {
+		t.Fatalf("unexpected feeds %v", feeds)
+	}
+	if response["last_refreshed_on_time"].(float64) == 0 {
+		t.Fatalf("expected last refreshed time")
+	}
+}
+
+func TestFeverItems(t *testing.T) {
+	server, tech, _, articles := newFeverTestServer(t)
+	response := feverRequest(t, server, "items&unread_item_ids&saved_item_ids", nil)
+	items := response["items"].([]any)
+	if len(items) != 3 || items[0].(map[string]any)["id"] != float64(articles[2].ID) || response["total_items"] != float64(3) {
+		t.Fatalf("expected newest first, got %v", items)
+	}
+	first := items[2].(map[string]any)
+	if first["feed_id"] != float64(tech.ID) || first["html"] != "<p>go</p>" || first["author"] != "Ann" || first["created_on_time"] != float64(articles[0].PublishedAt.Unix()) {
+		t.Fatalf("unexpected item %v", first)
+	}
+	if response["unread_item_ids"] != strconv.Itoa(articles[0].ID)+","+strconv.Itoa(articles[1].ID)+","+strconv.Itoa(articles[2].ID) || response["saved_item_ids"] != "" {
+		t.Fatalf("unexpected id lists %v %v", response["unread_item_ids"], response["saved_item_ids"])
+	}
+
+	response = feverRequest(t, server, "items&since_id="+strconv.Itoa(articles[0].ID), nil)
+	if items := response["items"].([]any); len(items) != 2 || items[0].(map[string]any)["id"] != float64(articles[1].ID) {
+		t.Fatalf("unexpected since_id items %v", items)
+	}
+	response = feverRequest(t, server, "items&max_id="+strconv.Itoa(articles[2].ID), nil)
+	if items := response["items"].([]any); len(items) != 2 || items[0].(map[string]any)["id"] != float64(articles[1].ID) {
+		t.Fatalf("unexpected max_id items %v", items)
+	}
+	response = feverRequest(t, server, "items&with_ids="+strconv.Itoa(articles[0].ID)+",x,"+strconv.Itoa(articles[2].ID), nil)
+	if items := response["items"].([]any); len(items) != 2 {
+		t.Fatalf("unexpected with_ids items %v", items)
+	}
+}
+
+func TestFeverMark(t *testing.T) {
+	server, _, news, articles := newFeverTestServer(t)
+	feverRequest(t, server, "", url.Values{"mark": {"item"}, "as": {"read"}, "id": {strconv.Itoa(articles[0].ID)}})
+	feverRequest(t, server, "", url.Values{"mark": {"item"}, "as": {"saved"}, "id": {strconv.Itoa(articles[1].ID)}})
+	response := feverRequest(t, server, "unread_item_ids&saved_item_ids", nil)
+	if response["unread_item_ids"] != strconv.Itoa(articles[1].ID)+","+strconv.Itoa(articles[2].ID) || response["saved_item_ids"] != strconv.Itoa(articles[1].ID) {
+		t.Fatalf("unexpected state after item marks %v", response)
+	}
+	feverRequest(t, server, "", url.Values{"mark": {"item"}, "as": {"unsaved"}, "id": {strconv.Itoa(articles[1].ID)}})
+	feverRequest(t, server, "", url.Values{"mark": {"item"}, "as": {"unread"}, "id": {strconv.Itoa(articles[0].ID)}})
+
+	before := articles[1].PublishedAt.Add(time.Minute).Unix()
+	feverRequest(t, server, "", url.Values{"mark": {"feed"}, "as": {"read"}, "id": {strconv.Itoa(news.ID)}, "before": {strconv.FormatInt(before, 10)}})
+	response = feverRequest(t, server, "unread_item_ids&saved_item_ids", nil)
+	if response["unread_item_ids"] != strconv.Itoa(articles[0].ID)+","+strconv.Itoa(articles[2].ID) || response["saved_item_ids"] != "" {
+		t.Fatalf("unexpected state after feed mark %v", response)
+	}
+	groupID := server.store.FolderIDs()["Work"]
+	feverRequest(t, server, "", url.Values{"mark": {"group"}, "as": {"read"}, "id": {strconv.Itoa(groupID)}})
+	if response := feverRequest(t, server, "unread_item_ids", nil); response["unread_item_ids"] != strconv.Itoa(articles[2].ID) {
+		t.Fatalf("unexpected state after group mark %v", response)
+	}
+	feverRequest(t, server, "", url.Values{"mark": {"group"}, "as": {"read"}, "id": {"0"}})
+	if response := feverRequest(t, server, "unread_item_ids", nil); response["unread_item_ids"] != "" {
+		t.Fatalf("expected everything read, got %v", response)
+	}
+
+	for _, form := range []url.Values{
+		{"mark": {"item"}, "as": {"read"}, "id": {"x"}},
+		{"mark": {"item"}, "as": {"bogus"}, "id": {"1"}},
+		{"mark": {"feed"}, "as": {"read"}, "id": {"1"}, "before": {"soon"}},
+	} {
+		form.Set("api_key", feverAPIKey("me", "secret"))
+		req := httptest.NewRequest(http.MethodPost, "/?api", strings.NewReader(form.Encode()))
+		req.Header.Set("content-type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected bad request for %v, got %d", form, rec.Code)
+		}
+	}
+}
+
+func TestRunDaemonFever(t *testing.T) {
+	app := newTUIApp(t)
+	app.config.FeverAddr = "127.0.0.1:0"
+	app.config.FeverUsername = "me"
+	app.config.FeverPassword = "secret"
+
+	origListen := feverListen
+	t.Cleanup(func() { feverListen = origListen })
+	var listener net.Listener
+	feverListen = func(network, addr string) (net.Listener, error) {
+		var err error
+		listener, err = origListen(network, addr)
+		return listener, err
+	}
+	stop := make(chan struct{})
+	var body string
+	app.setClock(&testClock{now: time.Now(), after: func(time.Duration) <-chan time.Time {
+		resp, err := http.PostForm("http://"+listener.Addr().String()+"/?api", url.Values{"api_key": {feverAPIKey("me", "secret")}})
+		if err != nil {
+			t.Fatalf("fever request error: %v", err)
+		}
+		var buf bytes.Buffer
+		buf.ReadFrom(resp.Body)
+		resp.Body.Close()
+		body = buf.String()
+		close(stop)
+		return nil
+	}})
+	var out bytes.Buffer
+	if err := runDaemon(app, &out, stop); err != nil {
+		t.Fatalf("runDaemon error: %v", err)
+	}
+	if !strings.Contains(out.String(), "fever api on http://127.0.0.1:") || !strings.Contains(body, `"auth":1`) {
+		t.Fatalf("unexpected daemon output %s / %s", out.String(), body)
+	}
+
+	feverListen = func(string, string) (net.Listener, error) { return nil, errors.New("in use") }
+	if err := runDaemon(app, &out, stop); err == nil || err.Error() != "fever: in use" {
+		t.Fatalf("expected listen error, got %v", err)
+	}
+	app.config.FeverPassword = ""
+	if err := runDaemon(app, &out, stop); err == nil || !strings.Contains(err.Error(), "fever.password") {
+		t.Fatalf("expected credentials error, got %v", err)
+	}
+}
+
+func TestParseConfigFever(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("[fever]\naddr = \"127.0.0.1:8081\"\nusername = \"me\"\npassword = \"secret\"", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil {
+		t.Fatalf("parse rendered config error: %v", err)
+	}
+	if reparsed.FeverAddr != "127.0.0.1:8081" || reparsed.FeverUsername != "me" || reparsed.FeverPassword != "secret" {
+		t.Fatalf("fever config did not round trip: %+v", reparsed)
+	}
+}
+
+func TestFeverFaviconsAndImageProxy(t *testing.T) {
+	server, tech, news, _ := newFeverTestServer(t)
+	server.images = newImageFetcher(Config{CacheDir: t.TempDir(), CacheMaxMB: 1})
+	server.images.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host != "tech.example" {
+			return newResponse(http.StatusNotFound, "", nil, r), nil
+		}
+		return newResponse(http.StatusOK, "gif", map[string]string{"content-type": "image/gif"}, r), nil
+	})}
+	article, err := server.store.InsertArticles(tech, []Article{{GUID: "t2", Title: "Pic", Content: `<p><img alt="x" src="https://tech.example/a.png?w=1&amp;h=2"></p>`}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+
+	response := feverRequest(t, server, "feeds&favicons", nil)
+	favicons := response["favicons"].([]any)
+	if len(favicons) != 1 || favicons[0].(map[string]any)["id"] != float64(tech.ID) || favicons[0].(map[string]any)["data"] != "image/gif;base64,Z2lm" {
+		t.Fatalf("unexpected favicons %v", favicons)
+	}
+	faviconIDs := map[float64]any{}
+	for _, feed := range response["feeds"].([]any) {
+		item := feed.(map[string]any)
+		faviconIDs[item["id"].(float64)] = item["favicon_id"]
+	}
+	if faviconIDs[float64(tech.ID)] != float64(tech.ID) || faviconIDs[float64(news.ID)] != float64(0) {
+		t.Fatalf("unexpected favicon ids %v", faviconIDs)
+	}
+
+	response = feverRequest(t, server, "items&with_ids="+strconv.Itoa(article[0].ID), nil)
+	html := response["items"].([]any)[0].(map[string]any)["html"].(string)
+	match := regexp.MustCompile(`src="([^"]+)"`).FindStringSubmatch(html)
+	if match == nil || !strings.HasPrefix(match[1], "http://example.com/image?") {
+		t.Fatalf("expected proxied image, got %q", html)
+	}
+	proxied := strings.ReplaceAll(match[1], "&amp;", "&")
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, proxied, nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "gif" || rec.Header().Get("content-type") != "image/gif" {
+		t.Fatalf("unexpected proxied image %d %q", rec.Code, rec.Body.String())
+	}
+	if _, ok := server.images.cache.entry(cacheNamespaceImages, "https://tech.example/a.png?w=1&h=2"); !ok {
+		t.Fatalf("expected image cached under its original url")
+	}
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/image?url=https%3A%2F%2Finternal.test%2F&sig=00", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected unsigned image url refused, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type feedScope struct {
+	Folder string
+	FeedID int
+}
+
+type sidebarEntry struct {
+	label  string
+	scope  feedScope
+	depth  int
+	unread int
+}
+
+func (s feedScope) empty() bool {
+	return s.Folder == "" && s.FeedID == 0
+}
+
+func (a *App) SetFeedFolder(feedID int, folder string) error {
+	feed := a.findFeed(feedID)
+	if feed == nil {
+		return messageErr(msgFeedNotFound, feedID)
+	}
+	folder = normalizeFolder(folder)
+	if err := a.store.SetFeedFolder(feedID, folder); err != nil {
+		return err
+	}
+	a.feeds = a.store.Feeds()
+	if folder == "" {
+		a.status = tr(msgFolderCleared, feed.Title)
+	} else {
+		a.status = tr(msgFolderSet, feed.Title, folder)
+	}
+	return nil
+}
+
+func (a *App) SetFeedScope(scope feedScope) {
+	scope.Folder = normalizeFolder(scope.Folder)
+	for _, feed := range a.feeds {
+		if folderWithin(feed.Folder, scope.Folder) {
+			scope.Folder = feed.Folder[:len(scope.Folder)]
+			break
+		}
+	}
+	a.scope = scope
+	a.selectedIndex = 0
+	if scope.empty() {
+		a.status = tr(msgScopeCleared)
+	} else {
+		a.status = tr(msgScopeSet, a.scopeLabel(), len(a.filteredArticles()))
+	}
+	a.syncSummaryForSelection()
+}
+
+func (a *App) scopeLabel() string {
+	if a.scope.FeedID != 0 {
+		if feed := a.findFeed(a.scope.FeedID); feed != nil {
+			return feed.Title
+		}
+		return fmt.Sprintf("feed %d", a.scope.FeedID)
+	}
+	if a.scope.Folder != "" {
+		return a.scope.Folder
+	}
+	return tr(msgScopeAll)
+}
+
+func (a *App) scopedFeeds() map[int]bool {
+	if a.scope.empty() {
+		return nil
+	}
+	ids := map[int]bool{}
+	for _, feed := range a.feeds {
+		if feed.ID == a.scope.FeedID || a.scope.FeedID == 0 && folderWithin(feed.Folder, a.scope.Folder) {
+			ids[feed.ID] = true
+		}
+	}
+	return ids
+}
+
+func (a *App) findFeed(id int) *Feed {
+	for i := range a.feeds {
+		if a.feeds[i].ID == id {
+			return &a.feeds[i]
+		}
+	}
+	return nil
+}
+
+func (a *App) sidebarEntries() []sidebarEntry {
+	unread := a.unreadCounts()
+	entries := []sidebarEntry{{label: tr(msgScopeAll)}}
+	byFolder := map[string][]Feed{}
+	unfiled := []sidebarEntry{}
+	for _, feed := range a.feeds {
+		entries[0].unread += unread[feed.ID]
+		if feed.Folder == "" {
+			unfiled = append(unfiled, sidebarEntry{label: feed.Title, scope: feedScope{FeedID: feed.ID}, unread: unread[feed.ID]})
+			continue
+		}
+		byFolder[feed.Folder] = append(byFolder[feed.Folder], feed)
+	}
+	for _, folder := range a.store.Folders() {
+		feeds, ok := byFolder[folder]
+		if !ok {
+			continue
+		}
+		folderEntry := len(entries)
+		entries = append(entries, sidebarEntry{label: folder, scope: feedScope{Folder: folder}})
+		for _, feed := range feeds {
+			entries[folderEntry].unread += unread[feed.ID]
+			entries = append(entries, sidebarEntry{label: feed.Title, scope: feedScope{FeedID: feed.ID}, depth: 1, unread: unread[feed.ID]})
+		}
+	}
+	return append(entries, unfiled...)
+}
+
+func parseScopeArgs(args []string) (feedScope, error) {
+	if len(args) >= 1 && args[0] == "feed" {
+		if len(args) != 2 {
+			return feedScope{}, fmt.Errorf("usage: scope feed <id>")
+		}
+		id, err := parseFeedID(args[1])
+		if err != nil {
+			return feedScope{}, err
+		}
+		return feedScope{FeedID: id}, nil
+	}
+	return feedScope{Folder: strings.Join(args, " ")}, nil
+}
+
+func formatFolders(feeds []Feed) string {
+	lines := []string{}
+	unfiled := []string{}
+	byFolder := map[string][]string{}
+	order := []string{}
+	for _, feed := range feeds {
+		line := fmt.Sprintf("%d %s", feed.ID, feed.Title)
+		if feed.Folder == "" {
+			unfiled = append(unfiled, "  "+line)
+			continue
+		}
+		if _, ok := byFolder[feed.Folder]; !ok {
+			order = append(order, feed.Folder)
+		}
+		byFolder[feed.Folder] = append(byFolder[feed.Folder], "  "+line)
+	}
+	for _, folder := range order {
+		lines = append(lines, folder+":")
+		lines = append(lines, byFolder[folder]...)
+	}
+	if len(unfiled) > 0 {
+		lines = append(lines, "(no folder):")
+		lines = append(lines, unfiled...)
+	}
+	if len(lines) == 0 {
+		return "No feeds."
+	}
+	return strings.Join(lines, "\n")
+}
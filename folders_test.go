@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newFolderApp(t *testing.T) (*App, Feed, Feed) {
+	t.Helper()
+	app := newTUIApp(t)
+	tech, err := app.store.InsertFeed(Feed{Title: "Tech", URL: "https://example.com/tech", Folder: "Work"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	news, err := app.store.InsertFeed(Feed{Title: "News", URL: "https://example.com/news"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(tech, []Article{{GUID: "t1", Title: "Tech One", URL: "https://example.com/t1"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(news, []Article{{GUID: "n1", Title: "News One", URL: "https://example.com/n1"}, {GUID: "n2", Title: "News Two", URL: "https://example.com/n2"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	app.articles = app.store.SortedArticles()
+	app.filter = FilterAll
+	return app, tech, news
+}
+
+func TestFeedScopeFiltersArticles(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	app.SetFeedScope(feedScope{Folder: "work"})
+	if articles := app.FilteredArticles(); len(articles) != 1 || articles[0].FeedID != tech.ID {
+		t.Fatalf("expected folder scope, got %+v", articles)
+	}
+	if app.status != "Showing Work (1 articles)" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	app.SetFeedScope(feedScope{FeedID: news.ID})
+	if articles := app.FilteredArticles(); len(articles) != 2 || app.scopeLabel() != "News" {
+		t.Fatalf("expected feed scope, got %+v", articles)
+	}
+	app.SetFeedScope(feedScope{})
+	if len(app.FilteredArticles()) != 3 || app.status != "Showing all feeds" {
+		t.Fatalf("expected scope cleared, got %q", app.status)
+	}
+	app.scope = feedScope{FeedID: 99}
+	if app.scopeLabel() != "feed 99" || len(app.FilteredArticles()) != 0 {
+		t.Fatalf("expected missing feed scope to be empty")
+	}
+}
+
+func TestFeedScopeIncludesSubfolders(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	if err := app.SetFeedFolder(news.ID, " work /  World News/ "); err != nil {
+		t.Fatalf("SetFeedFolder error: %v", err)
+	}
+	if feed := app.findFeed(news.ID); feed.Folder != "work/World News" {
+		t.Fatalf("unexpected folder %q", feed.Folder)
+	}
+	app.SetFeedScope(feedScope{Folder: "WORK"})
+	if app.scopeLabel() != "Work" || len(app.FilteredArticles()) != 3 {
+		t.Fatalf("expected parent scope to include subfolders, got %q %d", app.scopeLabel(), len(app.FilteredArticles()))
+	}
+	app.SetFeedScope(feedScope{Folder: "work/world news"})
+	if articles := app.FilteredArticles(); len(articles) != 2 || articles[0].FeedID == tech.ID {
+		t.Fatalf("expected subfolder scope only, got %+v", articles)
+	}
+	if folderWithin("Workshop", "Work") || !folderWithin("Work/Go", "Work") {
+		t.Fatalf("unexpected folderWithin result")
+	}
+}
+
+func TestSetFeedFolder(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	if err := app.SetFeedFolder(news.ID, "Reading"); err != nil {
+		t.Fatalf("SetFeedFolder error: %v", err)
+	}
+	if app.findFeed(news.ID).Folder != "Reading" || app.status != "Moved News to Reading" {
+		t.Fatalf("unexpected folder state %+v %q", app.feeds, app.status)
+	}
+	if err := app.SetFeedFolder(tech.ID, ""); err != nil || app.status != "Removed Tech from its folder" {
+		t.Fatalf("expected folder cleared, got %v %q", err, app.status)
+	}
+	if err := app.SetFeedFolder(42, "Work"); err == nil || errorCode(err) != string(msgFeedNotFound) {
+		t.Fatalf("expected feed not found, got %v", err)
+	}
+}
+
+func TestSidebarEntries(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	entries := app.sidebarEntries()
+	if len(entries) != 4 {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if entries[0].label != "All feeds" || !entries[0].scope.empty() {
+		t.Fatalf("expected all entry first, got %+v", entries[0])
+	}
+	if entries[1].scope.Folder != "Work" || entries[2].scope.FeedID != tech.ID || entries[2].depth != 1 || entries[3].scope.FeedID != news.ID || entries[3].depth != 0 {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if entries[0].unread != 3 || entries[1].unread != 1 || entries[2].unread != 1 || entries[3].unread != 2 {
+		t.Fatalf("unexpected unread badges %+v", entries)
+	}
+}
+
+func TestFolderCommands(t *testing.T) {
+	app, _, news := newFolderApp(t)
+	if err := handleCommand(app, "folder "+strconv.Itoa(news.ID)+" Daily Reads", io.Discard); err != nil {
+		t.Fatalf("folder error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := handleCommand(app, "folders", &out); err != nil {
+		t.Fatalf("folders error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Work:\n  1 Tech") || !strings.Contains(out.String(), "Daily Reads:\n  2 News") {
+		t.Fatalf("unexpected folders output %q", out.String())
+	}
+	if err := handleCommand(app, "scope Daily Reads", io.Discard); err != nil || len(app.FilteredArticles()) != 2 {
+		t.Fatalf("scope folder error: %v", err)
+	}
+	if err := handleCommand(app, "scope feed 1", io.Discard); err != nil || len(app.FilteredArticles()) != 1 {
+		t.Fatalf("scope feed error: %v", err)
+	}
+	if err := handleCommand(app, "scope", io.Discard); err != nil || len(app.FilteredArticles()) != 3 {
+		t.Fatalf("scope clear error: %v", err)
+	}
+	for _, line := range []string{"folder", "folder x Work", "scope feed", "scope feed x"} {
+		if err := handleCommand(app, line, io.Discard); err == nil {
+			t.Fatalf("expected error for %q", line)
+		}
+	}
+	if formatFolders(nil) != "No feeds." {
+		t.Fatalf("unexpected empty folders output")
+	}
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	greaderReadTag    = "user/-/state/com.google/read"
+	greaderStarredTag = "user/-/state/com.google/starred"
+	greaderPageSize   = 1000
+)
+
+type GReaderClient struct {
+	baseURL  string
+	username string
+	password string
+	auth     string
+	client   *http.Client
+}
+
+type greaderLink struct {
+	Href string `json:"href"`
+}
+
+type greaderItem struct {
+	ID         string        `json:"id"`
+	Title      string        `json:"title"`
+	Published  int64         `json:"published"`
+	Author     string        `json:"author"`
+	Canonical  []greaderLink `json:"canonical"`
+	Alternate  []greaderLink `json:"alternate"`
+	Categories []string      `json:"categories"`
+	Summary    struct {
+		Content string `json:"content"`
+	} `json:"summary"`
+	Content struct {
+		Content string `json:"content"`
+	} `json:"content"`
+	Origin struct {
+		StreamID string `json:"streamId"`
+	} `json:"origin"`
+}
+
+func NewGReaderClient(baseURL, token, username, password string) *GReaderClient {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" || (token == "" && username == "") {
+		return nil
+	}
+	return &GReaderClient{
+		baseURL:  baseURL,
+		username: username,
+		password: password,
+		auth:     token,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (g *GReaderClient) Name() string {
+	return "Google Reader API"
+}
+
+func (g *GReaderClient) login() error {
+	if g.auth != "" {
+		return nil
+	}
+	form := url.Values{}
+	form.Set("Email", g.username)
+	form.Set("Passwd", g.password)
+	resp, err := g.client.PostForm(g.baseURL+"/accounts/ClientLogin", form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("greader login http %d", resp.StatusCode)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "Auth="); ok {
+			g.auth = value
+			return nil
+		}
+	}
+	return errors.New("greader login: no auth token in response")
+}
+
+func (g *GReaderClient) Pull() (SyncSnapshot, error) {
+	if g == nil {
+		return SyncSnapshot{}, errors.New("greader not configured")
+	}
+	if err := g.login(); err != nil {
+		return SyncSnapshot{}, err
+	}
+	var subscriptions struct {
+		Subscriptions []struct {
+			ID      string `json:"id"`
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			HTMLURL string `json:"htmlUrl"`
+		} `json:"subscriptions"`
+	}
+	if err := g.get("/reader/api/0/subscription/list?output=json", &subscriptions); err != nil {
+		return SyncSnapshot{}, err
+	}
+	snapshot := SyncSnapshot{}
+	for _, sub := range subscriptions.Subscriptions {
+		feedURL := sub.URL
+		if feedURL == "" {
+			feedURL = strings.TrimPrefix(sub.ID, "feed/")
+		}
+		snapshot.Feeds = append(snapshot.Feeds, SyncFeed{
+			RemoteID: sub.ID,
+			Title:    sub.Title,
+			URL:      feedURL,
+			SiteURL:  sub.HTMLURL,
+		})
+	}
+	continuation := ""
+	for {
+		query := url.Values{}
+		query.Set("output", "json")
+		query.Set("n", fmt.Sprint(greaderPageSize))
+		if continuation != "" {
+			query.Set("c", continuation)
+		}
+		var page struct {
+			Items        []greaderItem `json:"items"`
+			Continuation string        `json:"continuation"`
+		}
+		if err := g.get("/reader/api/0/stream/contents/user/-/state/com.google/reading-list?"+query.Encode(), &page); err != nil {
+			return SyncSnapshot{}, err
+		}
+		for _, item := range page.Items {
+			snapshot.Items = append(snapshot.Items, item.syncItem())
+		}
+		if page.Continuation == "" || page.Continuation == continuation || len(page.Items) == 0 {
+			break
+		}
+		continuation = page.Continuation
+	}
+	return snapshot, nil
+}
+
+func (item greaderItem) syncItem() SyncItem {
+	link := ""
+	for _, links := range [][]greaderLink{item.Canonical, item.Alternate} {
+		if len(links) > 0 && link == "" {
+			link = links[0].Href
+		}
+	}
+	synced := SyncItem{
+		RemoteID:     item.ID,
+		FeedRemoteID: item.Origin.StreamID,
+		GUID:         item.ID,
+		Title:        item.Title,
+		URL:          link,
+		Author:       item.Author,
+		Content:      firstNonEmpty(item.Content.Content, item.Summary.Content),
+		PublishedAt:  time.Unix(item.Published, 0).UTC(),
+	}
+	for _, category := range item.Categories {
+		switch {
+		case strings.HasSuffix(category, "/state/com.google/read"):
+			synced.Read = true
+		case strings.HasSuffix(category, "/state/com.google/starred"):
+			synced.Starred = true
+		}
+	}
+	return synced
+}
+
+func (g *GReaderClient) Push(changes []SyncChange) error {
+	if g == nil {
+		return errors.New("greader not configured")
+	}
+	if err := g.login(); err != nil {
+		return err
+	}
+	type edit struct {
+		action string
+		tag    string
+	}
+	batches := map[edit][]string{}
+	for _, change := range changes {
+		if change.ReadChanged {
+			key := edit{greaderAction(change.Read), greaderReadTag}
+			batches[key] = append(batches[key], change.RemoteID)
+		}
+		if change.StarredChanged {
+			key := edit{greaderAction(change.Starred), greaderStarredTag}
+			batches[key] = append(batches[key], change.RemoteID)
+		}
+	}
+	if len(batches) == 0 {
+		return nil
+	}
+	token, err := g.editToken()
+	if err != nil {
+		return err
+	}
+	for _, key := range []edit{{"a", greaderReadTag}, {"r", greaderReadTag}, {"a", greaderStarredTag}, {"r", greaderStarredTag}} {
+		ids := batches[key]
+		if len(ids) == 0 {
+			continue
+		}
+		form := url.Values{}
+		form.Set("T", token)
+		form.Set(key.action, key.tag)
+		for _, id := range ids {
+			form.Add("i", id)
+		}
+		resp, err := g.request(http.MethodPost, "/reader/api/0/edit-tag", strings.NewReader(form.Encode()))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
+
+func greaderAction(set bool) string {
+	if set {
+		return "a"
+	}
+	return "r"
+}
+
+func (g *GReaderClient) editToken() (string, error) {
+	resp, err := g.request(http.MethodGet, "/reader/api/0/token", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+func (g *GReaderClient) get(path string, out any) error {
+	resp, err := g.request(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (g *GReaderClient) request(method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, g.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "GoogleLogin auth="+g.auth)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusUnauthorized && g.username != "" {
+			g.auth = ""
+		}
+		return nil, fmt.Errorf("greader http %d", resp.StatusCode)
+	}
+	return resp, nil
+}
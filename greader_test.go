@@ -0,0 +1,156 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNewGReaderClient(t *testing.T) {
+	if NewGReaderClient("", "tok", "", "") != nil || NewGReaderClient("https://rss.example.com/api/greader.php", "", "", "") != nil {
+		t.Fatalf("expected nil client without url or credentials")
+	}
+	if syncer := NewSyncer(Config{SyncBackend: "greader", SyncURL: "https://rss.example.com/api/greader.php/", SyncUsername: "me"}); syncer == nil || syncer.Name() != "Google Reader API" {
+		t.Fatalf("unexpected syncer %v", syncer)
+	}
+	if NewSyncer(Config{SyncBackend: "greader"}) != nil {
+		t.Fatalf("expected nil syncer without url")
+	}
+}
+
+func greaderTestClient(t *testing.T, edits *[]url.Values) *GReaderClient {
+	t.Helper()
+	client := NewGReaderClient("http://fresh.test/api/greader.php", "", "me", "pw")
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/greader.php")
+		if path == "/accounts/ClientLogin" {
+			r.ParseForm()
+			if r.PostForm.Get("Email") != "me" || r.PostForm.Get("Passwd") != "pw" {
+				return newResponse(http.StatusForbidden, "", nil, r), nil
+			}
+			return newResponse(http.StatusOK, "SID=me/1\nLSID=null\nAuth=me/abc\n", nil, r), nil
+		}
+		if r.Header.Get("Authorization") != "GoogleLogin auth=me/abc" {
+			return newResponse(http.StatusUnauthorized, "", nil, r), nil
+		}
+		switch path {
+		case "/reader/api/0/subscription/list":
+			return newResponse(http.StatusOK, `{"subscriptions":[{"id":"feed/1","title":"Example","url":"https://example.com/rss","htmlUrl":"https://example.com"},{"id":"feed/https://other.example.com/feed","title":"Other"}]}`, nil, r), nil
+		case "/reader/api/0/stream/contents/user/-/state/com.google/reading-list":
+			if r.URL.Query().Get("c") == "" {
+				return newResponse(http.StatusOK, `{"items":[{"id":"tag:google.com,2005:reader/item/0001","title":"A","published":1700000000,"author":"Ann","canonical":[{"href":"https://example.com/a"}],"summary":{"content":"<p>sum</p>"},"origin":{"streamId":"feed/1"},"categories":["user/-/state/com.google/reading-list","user/1/state/com.google/read"]}],"continuation":"next"}`, nil, r), nil
+			}
+			return newResponse(http.StatusOK, `{"items":[{"id":"tag:google.com,2005:reader/item/0002","title":"B","alternate":[{"href":"https://other.example.com/b"}],"content":{"content":"<p>full</p>"},"origin":{"streamId":"feed/https://other.example.com/feed"},"categories":["user/-/state/com.google/starred"]}],"continuation":"next"}`, nil, r), nil
+		case "/reader/api/0/token":
+			return newResponse(http.StatusOK, "edit-token\n", nil, r), nil
+		case "/reader/api/0/edit-tag":
+			body, _ := io.ReadAll(r.Body)
+			values, _ := url.ParseQuery(string(body))
+			*edits = append(*edits, values)
+			return newResponse(http.StatusOK, "OK", nil, r), nil
+		}
+		return newResponse(http.StatusNotFound, "", nil, r), nil
+	})}
+	return client
+}
+
+func TestGReaderPull(t *testing.T) {
+	client := greaderTestClient(t, nil)
+	snapshot, err := client.Pull()
+	if err != nil {
+		t.Fatalf("Pull error: %v", err)
+	}
+	if len(snapshot.Feeds) != 2 || snapshot.Feeds[0].SiteURL != "https://example.com" || snapshot.Feeds[1].URL != "https://other.example.com/feed" {
+		t.Fatalf("unexpected feeds %+v", snapshot.Feeds)
+	}
+	if len(snapshot.Items) != 2 {
+		t.Fatalf("unexpected items %+v", snapshot.Items)
+	}
+	first, second := snapshot.Items[0], snapshot.Items[1]
+	if first.FeedRemoteID != "feed/1" || first.URL != "https://example.com/a" || first.Content != "<p>sum</p>" || !first.Read || first.Starred || first.PublishedAt.Unix() != 1700000000 {
+		t.Fatalf("unexpected first item %+v", first)
+	}
+	if second.URL != "https://other.example.com/b" || second.Content != "<p>full</p>" || second.Read || !second.Starred {
+		t.Fatalf("unexpected second item %+v", second)
+	}
+
+	client.auth = "stale"
+	if _, err := client.Pull(); err == nil || client.auth != "" {
+		t.Fatalf("expected unauthorized error to reset auth, got %v", err)
+	}
+	client.password = "wrong"
+	if _, err := client.Pull(); err == nil || !strings.Contains(err.Error(), "login http 403") {
+		t.Fatalf("expected login error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusOK, "Error=BadAuthentication", nil)
+	if _, err := client.Pull(); err == nil || !strings.Contains(err.Error(), "no auth token") {
+		t.Fatalf("expected missing token error, got %v", err)
+	}
+	client.auth = "tok"
+	client.client = clientForResponse(http.StatusOK, "nope", nil)
+	if _, err := client.Pull(); err == nil {
+		t.Fatalf("expected decode error")
+	}
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if _, err := client.Pull(); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	var nilClient *GReaderClient
+	if _, err := nilClient.Pull(); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+}
+
+func TestGReaderPush(t *testing.T) {
+	edits := []url.Values{}
+	client := greaderTestClient(t, &edits)
+	if err := client.Push(nil); err != nil || len(edits) != 0 {
+		t.Fatalf("expected no edits for empty changes: %v", err)
+	}
+	changes := []SyncChange{
+		{RemoteID: "i1", Read: true, ReadChanged: true},
+		{RemoteID: "i2", Read: true, ReadChanged: true, Starred: false, StarredChanged: true},
+		{RemoteID: "i3", Starred: true, StarredChanged: true},
+	}
+	if err := client.Push(changes); err != nil {
+		t.Fatalf("Push error: %v", err)
+	}
+	if len(edits) != 3 {
+		t.Fatalf("unexpected edits %v", edits)
+	}
+	if edits[0].Get("a") != greaderReadTag || strings.Join(edits[0]["i"], ",") != "i1,i2" || edits[0].Get("T") != "edit-token" {
+		t.Fatalf("unexpected read edit %v", edits[0])
+	}
+	if edits[1].Get("a") != greaderStarredTag || edits[1].Get("i") != "i3" {
+		t.Fatalf("unexpected star edit %v", edits[1])
+	}
+	if edits[2].Get("r") != greaderStarredTag || edits[2].Get("i") != "i2" {
+		t.Fatalf("unexpected unstar edit %v", edits[2])
+	}
+
+	client.auth = "tok"
+	client.client = clientForResponse(http.StatusInternalServerError, "", nil)
+	if err := client.Push(changes); err == nil {
+		t.Fatalf("expected token error")
+	}
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(r.URL.Path, "/token") {
+			return newResponse(http.StatusOK, "t", nil, r), nil
+		}
+		return newResponse(http.StatusBadRequest, "", nil, r), nil
+	})}
+	if err := client.Push(changes); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("expected edit error, got %v", err)
+	}
+	client.auth = ""
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if err := client.Push(changes); err == nil {
+		t.Fatalf("expected login transport error")
+	}
+	var nilClient *GReaderClient
+	if err := nilClient.Push(nil); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	hookRefreshStart     = "on_refresh_start"
+	hookRefreshComplete  = "on_refresh_complete"
+	hookNewArticle       = "on_new_article"
+	hookSummaryGenerated = "on_summary_generated"
+)
+
+var hookEvents = []string{hookRefreshStart, hookRefreshComplete, hookNewArticle, hookSummaryGenerated}
+
+var (
+	hookRun     = defaultPluginRun
+	hookMarshal = json.Marshal
+)
+
+type hookInvocation struct {
+	event   string
+	command string
+	env     []string
+	input   []byte
+}
+
+func parseHooksSection(event string, value string, cfg *Config) error {
+	event = trimQuotes(event)
+	known := false
+	for _, name := range hookEvents {
+		known = known || name == event
+	}
+	if !known {
+		return fmt.Errorf("unknown hook: %q (expected one of %s)", event, strings.Join(hookEvents, ", "))
+	}
+	command := trimQuotes(value)
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("empty command for hook %s", event)
+	}
+	if cfg.Hooks == nil {
+		cfg.Hooks = map[string]string{}
+	}
+	cfg.Hooks[event] = command
+	return nil
+}
+
+func (a *App) articlePayload(article Article, tags []string) pluginArticle {
+	payload := pluginArticle{
+		ID:          article.ID,
+		Title:       article.Title,
+		URL:         article.URL,
+		Feed:        article.FeedTitle,
+		Author:      article.Author,
+		PublishedAt: article.PublishedAt,
+		Read:        article.IsRead,
+		Starred:     article.IsStarred,
+		Content:     firstNonEmpty(article.ContentText, article.Content),
+		Tags:        cleanTags(tags),
+	}
+	if summary, ok := a.store.FindSummary(article.ID); ok {
+		payload.Summary = summary.Content
+	}
+	return payload
+}
+
+func articleHookEnv(article Article) []string {
+	return []string{
+		"GREEDER_ARTICLE_ID=" + strconv.Itoa(article.ID),
+		"GREEDER_ARTICLE_TITLE=" + article.Title,
+		"GREEDER_ARTICLE_URL=" + article.URL,
+		"GREEDER_FEED=" + article.FeedTitle,
+	}
+}
+
+func (a *App) hook(event string, env []string, payload any) (hookInvocation, bool) {
+	command, ok := a.config.Hooks[event]
+	if !ok {
+		return hookInvocation{}, false
+	}
+	input := []byte{}
+	if payload != nil {
+		blob, err := hookMarshal(payload)
+		if err != nil {
+			logFor("hooks").Warn("encode failed", "hook", event, "err", err)
+			return hookInvocation{}, false
+		}
+		input = blob
+	}
+	return hookInvocation{event: event, command: command, env: append([]string{"GREEDER_EVENT=" + event}, env...), input: input}, true
+}
+
+func (h hookInvocation) run() error {
+	shell, args := shellCommandForOS(runtime.GOOS, h.command)
+	start := time.Now()
+	_, err := hookRun(shell, args, h.env, string(h.input))
+	log := logFor("hooks").With("hook", h.event, "duration", time.Since(start))
+	if err != nil {
+		log.Warn("hook failed", "err", err)
+		return fmt.Errorf("%s: %w", h.event, err)
+	}
+	log.Debug("hook ran")
+	return nil
+}
+
+func (a *App) runHook(event string, env []string, payload any) {
+	if invocation, ok := a.hook(event, env, payload); ok {
+		_ = invocation.run()
+	}
+}
+
+func (a *App) runRefreshStartHook() {
+	a.runHook(hookRefreshStart, nil, nil)
+}
+
+func (a *App) runRefreshHooks() {
+	if _, ok := a.config.Hooks[hookRefreshComplete]; ok {
+		articles := make([]pluginArticle, len(a.lastNew))
+		for i, article := range a.lastNew {
+			articles[i] = a.articlePayload(article, nil)
+		}
+		a.runHook(hookRefreshComplete, []string{"GREEDER_STATUS=" + a.status, "GREEDER_NEW_ARTICLES=" + strconv.Itoa(len(a.lastNew))}, articles)
+	}
+	if _, ok := a.config.Hooks[hookNewArticle]; ok {
+		for _, article := range a.lastNew {
+			a.runHook(hookNewArticle, articleHookEnv(article), a.articlePayload(article, nil))
+		}
+	}
+}
+
+func (a *App) runSummaryHook(article Article) {
+	a.runHook(hookSummaryGenerated, articleHookEnv(article), a.articlePayload(article, nil))
+}
+
+func (a *App) summaryHookCmd(article Article) tea.Cmd {
+	invocation, ok := a.hook(hookSummaryGenerated, articleHookEnv(article), a.articlePayload(article, nil))
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = invocation.run()
+		return nil
+	}
+}
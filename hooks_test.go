@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseHooksSection(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("[hooks]\non_new_article = \"notify-send new\"\non_summary_generated = \"~/bin/archive\"\n", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.Hooks[hookNewArticle] != "notify-send new" || cfg.Hooks[hookSummaryGenerated] != "~/bin/archive" {
+		t.Fatalf("unexpected hooks %+v", cfg.Hooks)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil || len(reparsed.Hooks) != 2 {
+		t.Fatalf("hooks did not round trip: %+v %v", reparsed.Hooks, err)
+	}
+	for _, bad := range []string{"[hooks]\non_typo = \"x\"", "[hooks]\non_new_article = \"\""} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+type hookCall struct {
+	command string
+	env     string
+	input   string
+}
+
+func stubHookRun(t *testing.T, err error) *[]hookCall {
+	t.Helper()
+	orig := hookRun
+	t.Cleanup(func() { hookRun = orig })
+	calls := []hookCall{}
+	hookRun = func(shell string, args []string, env []string, input string) (string, error) {
+		calls = append(calls, hookCall{command: args[len(args)-1], env: strings.Join(env, "\n"), input: input})
+		return "", err
+	}
+	return &calls
+}
+
+func TestRunRefreshHooks(t *testing.T) {
+	app, _ := newSessionApp(t)
+	app.config.Hooks = map[string]string{hookRefreshComplete: "on-refresh", hookNewArticle: "on-article"}
+	app.lastNew = app.articles[:2]
+	app.status = "Refreshed 1 feeds"
+	calls := stubHookRun(t, nil)
+
+	app.runRefreshHooks()
+	if len(*calls) != 3 {
+		t.Fatalf("expected 3 hook calls, got %+v", *calls)
+	}
+	refresh := (*calls)[0]
+	if refresh.command != "on-refresh" || !strings.Contains(refresh.env, "GREEDER_EVENT=on_refresh_complete") || !strings.Contains(refresh.env, "GREEDER_NEW_ARTICLES=2") || !strings.Contains(refresh.env, "GREEDER_STATUS=Refreshed 1 feeds") {
+		t.Fatalf("unexpected refresh hook %+v", refresh)
+	}
+	var payload []pluginArticle
+	if err := json.Unmarshal([]byte(refresh.input), &payload); err != nil || len(payload) != 2 || payload[0].ID != app.lastNew[0].ID {
+		t.Fatalf("unexpected refresh payload %q %v", refresh.input, err)
+	}
+	article := (*calls)[2]
+	if article.command != "on-article" || !strings.Contains(article.env, "GREEDER_ARTICLE_URL="+app.lastNew[1].URL) {
+		t.Fatalf("unexpected article hook %+v", article)
+	}
+	var single pluginArticle
+	if err := json.Unmarshal([]byte(article.input), &single); err != nil || single.Title != app.lastNew[1].Title {
+		t.Fatalf("unexpected article payload %q %v", article.input, err)
+	}
+}
+
+func TestHooksAreOptionalAndFailuresIgnored(t *testing.T) {
+	app, _ := newSessionApp(t)
+	calls := stubHookRun(t, errors.New("exit status 1"))
+	app.lastNew = app.articles
+	app.runRefreshHooks()
+	if cmd := app.summaryHookCmd(app.articles[0]); cmd != nil || len(*calls) != 0 {
+		t.Fatalf("expected no hooks without configuration, got %d calls", len(*calls))
+	}
+
+	app.config.Hooks = map[string]string{hookSummaryGenerated: "archive"}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: app.articles[0].ID, Content: "Short"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	cmd := app.summaryHookCmd(app.articles[0])
+	if cmd == nil {
+		t.Fatalf("expected summary hook command")
+	}
+	if msg := cmd(); msg != nil {
+		t.Fatalf("expected hook failure to be swallowed, got %v", msg)
+	}
+	var payload pluginArticle
+	if len(*calls) != 1 || json.Unmarshal([]byte((*calls)[0].input), &payload) != nil || payload.Summary != "Short" {
+		t.Fatalf("unexpected summary hook calls %+v", *calls)
+	}
+}
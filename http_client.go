@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type configuredTransport struct {
+	base      http.RoundTripper
+	proxy     http.RoundTripper
+	userAgent string
+}
+
+func parseHTTPProxy(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	proxy, err := url.Parse(raw)
+	if err != nil || proxy.Host == "" {
+		return nil, fmt.Errorf("invalid http.proxy: %q (expected a URL like http://host:port)", raw)
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+		return proxy, nil
+	}
+	return nil, fmt.Errorf("invalid http.proxy: %q (scheme must be http, https or socks5)", raw)
+}
+
+func httpTimeout(cfg Config, fallback time.Duration) time.Duration {
+	if cfg.HTTPTimeoutSeconds > 0 {
+		return time.Duration(cfg.HTTPTimeoutSeconds) * time.Second
+	}
+	return fallback
+}
+
+func newHTTPClient(cfg Config, timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	configureHTTPClient(client, cfg)
+	return client
+}
+
+func configureHTTPClient(client *http.Client, cfg Config) {
+	base := unwrapTransport(client.Transport)
+	proxy, _ := parseHTTPProxy(cfg.HTTPProxy)
+	userAgent := strings.TrimSpace(cfg.HTTPUserAgent)
+	if proxy == nil && userAgent == "" {
+		client.Transport = base
+		return
+	}
+	configured := &configuredTransport{base: base, userAgent: userAgent}
+	if proxy != nil && base == nil {
+		configured.proxy = proxyTransport(proxy)
+	}
+	client.Transport = configured
+}
+
+func unwrapTransport(rt http.RoundTripper) http.RoundTripper {
+	for {
+		switch t := rt.(type) {
+		case *limitedTransport:
+			rt = t.base
+		case *configuredTransport:
+			rt = t.base
+		default:
+			return rt
+		}
+	}
+}
+
+func proxyTransport(proxy *url.URL) http.RoundTripper {
+	transport := &http.Transport{}
+	if defaults, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaults.Clone()
+	}
+	transport.Proxy = http.ProxyURL(proxy)
+	return transport
+}
+
+func (t *configuredTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt := t.base
+	if t.proxy != nil {
+		rt = t.proxy
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return rt.RoundTrip(req)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHTTPProxy(t *testing.T) {
+	if proxy, err := parseHTTPProxy(" "); proxy != nil || err != nil {
+		t.Fatalf("expected no proxy, got %v %v", proxy, err)
+	}
+	if proxy, err := parseHTTPProxy("socks5://127.0.0.1:1080"); err != nil || proxy.Host != "127.0.0.1:1080" {
+		t.Fatalf("unexpected proxy %v %v", proxy, err)
+	}
+	for _, raw := range []string{"ftp://proxy:21", "proxy:8080", "http://"} {
+		if _, err := parseHTTPProxy(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestConfigHTTPSection(t *testing.T) {
+	cfg := DefaultConfig()
+	input := "[http]\nproxy = \"http://proxy.local:3128\"\nuser_agent = \"greeder/1.0\"\ntimeout_seconds = 12"
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.HTTPProxy != "http://proxy.local:3128" || cfg.HTTPUserAgent != "greeder/1.0" || cfg.HTTPTimeoutSeconds != 12 {
+		t.Fatalf("unexpected http config %+v", cfg)
+	}
+	rendered := renderConfig(cfg)
+	if !strings.Contains(rendered, "[http]\nproxy = \"http://proxy.local:3128\"\nuser_agent = \"greeder/1.0\"\ntimeout_seconds = 12") {
+		t.Fatalf("unexpected rendered config %s", rendered)
+	}
+	if strings.Contains(renderConfig(DefaultConfig()), "[http]") {
+		t.Fatalf("expected no http section by default")
+	}
+	for input, want := range map[string]string{
+		"[http]\nproxy = \"gopher://x\"": "config line 2: invalid http.proxy",
+		"[http]\ntimeout_seconds = -1":   "config line 2: invalid http.timeout_seconds",
+	} {
+		cfg := DefaultConfig()
+		if err := parseConfig(input, &cfg); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("parseConfig(%q) = %v, want %q", input, err, want)
+		}
+	}
+	cfg = DefaultConfig()
+	if err := applyEnvOverrides(&cfg, []string{"GREEDER_HTTP_USER_AGENT=env-agent"}); err != nil || cfg.HTTPUserAgent != "env-agent" {
+		t.Fatalf("unexpected env override %q %v", cfg.HTTPUserAgent, err)
+	}
+}
+
+func TestConfiguredTransportSetsUserAgent(t *testing.T) {
+	var agents []string
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		agents = append(agents, req.Header.Get("User-Agent"))
+		return newResponse(http.StatusOK, "ok", nil, req), nil
+	})}
+	configureHTTPClient(client, Config{HTTPUserAgent: "greeder-test"})
+	if _, err := client.Get("https://example.com/feed"); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/feed", nil)
+	req.Header.Set("User-Agent", "custom")
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("Do error: %v", err)
+	}
+	if len(agents) != 2 || agents[0] != "greeder-test" || agents[1] != "custom" {
+		t.Fatalf("unexpected user agents %v", agents)
+	}
+}
+
+func TestConfigureHTTPClientProxyAndReapply(t *testing.T) {
+	client := &http.Client{}
+	configureHTTPClient(client, Config{HTTPProxy: "http://proxy.local:3128"})
+	limitClient(client, newHTTPLimiter(Config{}))
+	configured, ok := unwrapLimited(client.Transport).(*configuredTransport)
+	if !ok || configured.base != nil {
+		t.Fatalf("expected configured transport, got %#v", client.Transport)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/feed", nil)
+	proxy, err := configured.proxy.(*http.Transport).Proxy(req)
+	if err != nil || proxy.String() != "http://proxy.local:3128" {
+		t.Fatalf("unexpected proxy %v %v", proxy, err)
+	}
+
+	configureHTTPClient(client, Config{})
+	if client.Transport != nil {
+		t.Fatalf("expected reapply without settings to restore the default transport, got %#v", client.Transport)
+	}
+}
+
+func TestAppAppliesHTTPConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DBPath = filepath.Join(t.TempDir(), "store.db")
+	cfg.HTTPUserAgent = "greeder-test"
+	cfg.HTTPTimeoutSeconds = 7
+	cfg.RaindropToken = "token"
+	cfg.LMBaseURL = "http://lm.test"
+	app, err := NewApp(cfg)
+	if err != nil {
+		t.Fatalf("NewApp error: %v", err)
+	}
+	if app.fetcher.client.Timeout != 7*time.Second || app.raindrop.client.Timeout != 7*time.Second || app.summarizer.client.Timeout != 7*time.Second {
+		t.Fatalf("unexpected timeouts %v %v %v", app.fetcher.client.Timeout, app.raindrop.client.Timeout, app.summarizer.client.Timeout)
+	}
+	for _, client := range []*http.Client{app.fetcher.client, app.raindrop.client, app.summarizer.client} {
+		if configured, ok := unwrapLimited(client.Transport).(*configuredTransport); !ok || configured.userAgent != "greeder-test" {
+			t.Fatalf("expected user agent transport, got %#v", client.Transport)
+		}
+	}
+
+	cfg.FetchTimeoutSeconds = 45
+	app.applyConfig(cfg, nil)
+	if app.fetcher.client.Timeout != 45*time.Second {
+		t.Fatalf("expected fetcher timeout to win, got %v", app.fetcher.client.Timeout)
+	}
+	limited := app.fetcher.client.Transport.(*limitedTransport)
+	if configured := limited.base.(*configuredTransport); configured.base != nil {
+		t.Fatalf("expected reload not to stack transports, got %#v", configured.base)
+	}
+}
+
+func unwrapLimited(rt http.RoundTripper) http.RoundTripper {
+	if limited, ok := rt.(*limitedTransport); ok {
+		return limited.base
+	}
+	return rt
+}
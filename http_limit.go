@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFetchPerHost   = 2
+	bandwidthChunkBytes   = 16 * 1024
+	bandwidthBurstSeconds = 1
+)
+
+type httpLimiter struct {
+	total     chan struct{}
+	perHost   int
+	mu        sync.Mutex
+	hosts     map[string]chan struct{}
+	bandwidth *bandwidthThrottle
+}
+
+type bandwidthThrottle struct {
+	bytesPerSecond int
+	mu             sync.Mutex
+	next           time.Time
+}
+
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter *httpLimiter
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	limiter *httpLimiter
+	release func()
+	once    sync.Once
+}
+
+var httpLimitSleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func newHTTPLimiter(cfg Config) *httpLimiter {
+	limiter := &httpLimiter{perHost: cfg.FetchPerHost, hosts: map[string]chan struct{}{}}
+	if cfg.FetchMaxRequests > 0 {
+		limiter.total = make(chan struct{}, cfg.FetchMaxRequests)
+	}
+	if cfg.FetchBandwidthKBps > 0 {
+		limiter.bandwidth = &bandwidthThrottle{bytesPerSecond: cfg.FetchBandwidthKBps * 1024}
+	}
+	return limiter
+}
+
+func (l *httpLimiter) hostSlots(host string) chan struct{} {
+	if l.perHost <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	slots, ok := l.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, l.perHost)
+		l.hosts[host] = slots
+	}
+	return slots
+}
+
+func (l *httpLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	held := []chan struct{}{}
+	release := func() {
+		for _, slots := range held {
+			<-slots
+		}
+	}
+	for _, slots := range []chan struct{}{l.hostSlots(strings.ToLower(host)), l.total} {
+		if slots == nil {
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+			held = append(held, slots)
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+func (t *bandwidthThrottle) wait(ctx context.Context, n int) error {
+	now := time.Now()
+	t.mu.Lock()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	t.next = t.next.Add(time.Duration(n) * time.Second / time.Duration(t.bytesPerSecond))
+	delay := t.next.Sub(now) - bandwidthBurstSeconds*time.Second
+	t.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	return httpLimitSleep(ctx, delay)
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	release, err := t.limiter.acquire(req.Context(), req.URL.Host)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, ctx: req.Context(), limiter: t.limiter, release: release}
+	return resp, nil
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.limiter.bandwidth == nil {
+		return b.ReadCloser.Read(p)
+	}
+	if len(p) > bandwidthChunkBytes {
+		p = p[:bandwidthChunkBytes]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := b.limiter.bandwidth.wait(b.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	b.once.Do(b.release)
+	return b.ReadCloser.Close()
+}
+
+func limitClient(client *http.Client, limiter *httpLimiter) {
+	if limited, ok := client.Transport.(*limitedTransport); ok {
+		client.Transport = &limitedTransport{base: limited.base, limiter: limiter}
+		return
+	}
+	client.Transport = &limitedTransport{base: client.Transport, limiter: limiter}
+}
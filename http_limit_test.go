@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseConfigFetcherLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.FetchPerHost != defaultFetchPerHost || cfg.FetchMaxRequests != 0 || cfg.FetchBandwidthKBps != 0 {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := parseConfig("[fetcher]\nmax_requests = 8\nper_host = 1\nbandwidth_kbps = 512\n", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.FetchMaxRequests != 8 || cfg.FetchPerHost != 1 || cfg.FetchBandwidthKBps != 512 {
+		t.Fatalf("unexpected limits %+v", cfg)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil || reparsed.FetchMaxRequests != 8 || reparsed.FetchPerHost != 1 || reparsed.FetchBandwidthKBps != 512 {
+		t.Fatalf("limits did not round trip: %+v %v", reparsed, err)
+	}
+	if err := parseConfig("[fetcher]\nper_host = -1\n", &cfg); err == nil {
+		t.Fatalf("expected error for negative per_host")
+	}
+}
+
+type blockingTransport struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+	peak     map[string]int
+	total    int
+	peakAll  int
+	release  chan struct{}
+	started  chan struct{}
+}
+
+func newBlockingTransport() *blockingTransport {
+	return &blockingTransport{inFlight: map[string]int{}, peak: map[string]int{}, release: make(chan struct{}), started: make(chan struct{}, 16)}
+}
+
+func (b *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b.mu.Lock()
+	b.inFlight[req.URL.Host]++
+	b.total++
+	b.peak[req.URL.Host] = max(b.peak[req.URL.Host], b.inFlight[req.URL.Host])
+	b.peakAll = max(b.peakAll, b.total)
+	b.mu.Unlock()
+	b.started <- struct{}{}
+	<-b.release
+	b.mu.Lock()
+	b.inFlight[req.URL.Host]--
+	b.total--
+	b.mu.Unlock()
+	return newResponse(http.StatusOK, "ok", nil, req), nil
+}
+
+func TestLimitedTransportCapsConcurrency(t *testing.T) {
+	base := newBlockingTransport()
+	client := &http.Client{Transport: base}
+	limitClient(client, newHTTPLimiter(Config{FetchMaxRequests: 3, FetchPerHost: 1}))
+
+	urls := []string{"https://a.example/1", "https://a.example/2", "https://b.example/1", "https://c.example/1", "https://d.example/1"}
+	var wg sync.WaitGroup
+	for _, target := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(target)
+			if err != nil {
+				t.Errorf("Get %s: %v", target, err)
+				return
+			}
+			_, _ = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}()
+	}
+	for range 3 {
+		<-base.started
+	}
+	select {
+	case <-base.started:
+		t.Fatalf("expected the fourth request to wait for a slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(base.release)
+	wg.Wait()
+	if base.peakAll > 3 || base.peak["a.example"] > 1 {
+		t.Fatalf("limits exceeded: total %d, per host %v", base.peakAll, base.peak)
+	}
+}
+
+func TestLimitedTransportHonoursContextWhileWaiting(t *testing.T) {
+	limiter := newHTTPLimiter(Config{FetchPerHost: 1})
+	release, err := limiter.acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("acquire error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limiter.acquire(ctx, "EXAMPLE.com"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation while waiting, got %v", err)
+	}
+	release()
+	if release, err := limiter.acquire(context.Background(), "example.com"); err != nil {
+		t.Fatalf("expected slot after release, got %v", err)
+	} else {
+		release()
+	}
+}
+
+func TestLimitedTransportThrottlesBandwidth(t *testing.T) {
+	orig := httpLimitSleep
+	t.Cleanup(func() { httpLimitSleep = orig })
+	var slept time.Duration
+	httpLimitSleep = func(ctx context.Context, d time.Duration) error {
+		slept = max(slept, d)
+		return nil
+	}
+	body := strings.Repeat("x", 4*1024)
+	client := clientForResponse(http.StatusOK, body, nil)
+	limitClient(client, newHTTPLimiter(Config{FetchBandwidthKBps: 1}))
+
+	resp, err := client.Get("https://example.com/big")
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(data) != body {
+		t.Fatalf("unexpected body %d bytes, %v", len(data), err)
+	}
+	if slept < 2*time.Second || slept > 3*time.Second+100*time.Millisecond {
+		t.Fatalf("expected reads to be held back about 3s for 4KiB at 1KiB/s, slept %s", slept)
+	}
+}
+
+func TestLimitClientKeepsBaseTransportOnReload(t *testing.T) {
+	client := clientForResponse(http.StatusOK, "ok", nil)
+	limitClient(client, newHTTPLimiter(DefaultConfig()))
+	limitClient(client, newHTTPLimiter(DefaultConfig()))
+	limited, ok := client.Transport.(*limitedTransport)
+	if !ok {
+		t.Fatalf("expected limited transport, got %T", client.Transport)
+	}
+	if _, nested := limited.base.(*limitedTransport); nested {
+		t.Fatalf("expected limiter to be replaced, not nested")
+	}
+}
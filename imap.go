@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type IMAPClient struct {
+	addr     string
+	username string
+	password string
+	folder   string
+	dial     func(addr string) (net.Conn, error)
+}
+
+type Newsletter struct {
+	MessageID   string
+	FromName    string
+	FromAddress string
+	Subject     string
+	Date        time.Time
+	HTML        string
+	Text        string
+}
+
+var imapLiteralRe = regexp.MustCompile(`\{(\d+)\}$`)
+
+func NewIMAPClient(cfg Config) *IMAPClient {
+	host := strings.TrimSpace(cfg.IMAPHost)
+	if host == "" || cfg.IMAPUsername == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		if cfg.IMAPPlaintext {
+			host = net.JoinHostPort(host, "143")
+		} else {
+			host = net.JoinHostPort(host, "993")
+		}
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	dial := func(addr string) (net.Conn, error) {
+		return tls.DialWithDialer(dialer, "tcp", addr, nil)
+	}
+	if cfg.IMAPPlaintext {
+		dial = func(addr string) (net.Conn, error) {
+			return dialer.Dial("tcp", addr)
+		}
+	}
+	return &IMAPClient{
+		addr:     host,
+		username: cfg.IMAPUsername,
+		password: cfg.IMAPPassword,
+		folder:   firstNonEmpty(strings.TrimSpace(cfg.IMAPFolder), "INBOX"),
+		dial:     dial,
+	}
+}
+
+type imapSession struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+func (s *imapSession) readLine() (string, error) {
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (s *imapSession) command(cmd string) ([]string, [][]byte, error) {
+	s.tag++
+	tag := "a" + strconv.Itoa(s.tag)
+	if _, err := fmt.Fprintf(s.conn, "%s %s\r\n", tag, cmd); err != nil {
+		return nil, nil, err
+	}
+	lines := []string{}
+	literals := [][]byte{}
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return nil, nil, err
+		}
+		for {
+			match := imapLiteralRe.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			size, _ := strconv.Atoi(match[1])
+			literal := make([]byte, size)
+			if _, err := io.ReadFull(s.reader, literal); err != nil {
+				return nil, nil, err
+			}
+			literals = append(literals, literal)
+			rest, err := s.readLine()
+			if err != nil {
+				return nil, nil, err
+			}
+			line = strings.TrimSuffix(line, match[0]) + rest
+		}
+		if strings.HasPrefix(line, tag+" ") {
+			status := strings.TrimPrefix(line, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return nil, nil, fmt.Errorf("imap: %s", status)
+			}
+			return lines, literals, nil
+		}
+		lines = append(lines, line)
+	}
+}
+
+func imapQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+func (c *IMAPClient) Fetch() ([]Newsletter, error) {
+	if c == nil {
+		return nil, errors.New("imap not configured")
+	}
+	conn, err := c.dial(c.addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	session := &imapSession{conn: conn, reader: bufio.NewReader(conn)}
+	greeting, err := session.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		return nil, fmt.Errorf("imap: unexpected greeting %q", greeting)
+	}
+	if _, _, err := session.command("LOGIN " + imapQuote(c.username) + " " + imapQuote(c.password)); err != nil {
+		return nil, err
+	}
+	if _, _, err := session.command("SELECT " + imapQuote(c.folder)); err != nil {
+		return nil, err
+	}
+	lines, _, err := session.command("UID SEARCH UNSEEN")
+	if err != nil {
+		return nil, err
+	}
+	uids := []string{}
+	for _, line := range lines {
+		if strings.HasPrefix(line, "* SEARCH") {
+			uids = append(uids, strings.Fields(strings.TrimPrefix(line, "* SEARCH"))...)
+		}
+	}
+	newsletters := []Newsletter{}
+	if len(uids) > 0 {
+		set := strings.Join(uids, ",")
+		_, literals, err := session.command("UID FETCH " + set + " (BODY.PEEK[])")
+		if err != nil {
+			return nil, err
+		}
+		for _, literal := range literals {
+			newsletter, err := parseNewsletter(literal)
+			if err != nil {
+				continue
+			}
+			newsletters = append(newsletters, newsletter)
+		}
+		if _, _, err := session.command("UID STORE " + set + ` +FLAGS.SILENT (\Seen)`); err != nil {
+			return nil, err
+		}
+	}
+	_, _, _ = session.command("LOGOUT")
+	return newsletters, nil
+}
+
+func parseNewsletter(raw []byte) (Newsletter, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return Newsletter{}, err
+	}
+	decoder := new(mime.WordDecoder)
+	newsletter := Newsletter{
+		MessageID: strings.Trim(strings.TrimSpace(msg.Header.Get("Message-Id")), "<>"),
+	}
+	if from, err := (&mail.AddressParser{WordDecoder: decoder}).Parse(msg.Header.Get("From")); err == nil {
+		newsletter.FromName = from.Name
+		newsletter.FromAddress = strings.ToLower(from.Address)
+	}
+	if newsletter.FromAddress == "" {
+		return Newsletter{}, errors.New("newsletter without sender")
+	}
+	newsletter.Subject = strings.TrimSpace(msg.Header.Get("Subject"))
+	if subject, err := decoder.DecodeHeader(newsletter.Subject); err == nil {
+		newsletter.Subject = subject
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		newsletter.Date = date.UTC()
+	}
+	if err := collectMailBody(&newsletter, msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body); err != nil {
+		return Newsletter{}, err
+	}
+	return newsletter, nil
+}
+
+func collectMailBody(newsletter *Newsletter, contentType, encoding string, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(firstNonEmpty(contentType, "text/plain"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := collectMailBody(newsletter, part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part); err != nil {
+				return err
+			}
+		}
+	}
+	if mediaType != "text/html" && mediaType != "text/plain" {
+		return nil
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if mediaType == "text/html" && newsletter.HTML == "" {
+		newsletter.HTML = string(data)
+	} else if mediaType == "text/plain" && newsletter.Text == "" {
+		newsletter.Text = strings.TrimSpace(string(data))
+	}
+	return nil
+}
+
+func newsletterFeedURL(address string) string {
+	return "mailto:" + address
+}
+
+func newsletterArticle(newsletter Newsletter) Article {
+	id := newsletter.MessageID
+	if id == "" {
+		sum := sha1.Sum([]byte(newsletter.FromAddress + "\n" + newsletter.Subject + "\n" + newsletter.Date.String()))
+		id = hex.EncodeToString(sum[:])
+	}
+	content := newsletter.HTML
+	text := stripHTML(content)
+	if content == "" {
+		content = "<pre>" + html.EscapeString(newsletter.Text) + "</pre>"
+		text = newsletter.Text
+	}
+	return Article{
+		GUID:        "mid:" + id,
+		Title:       firstNonEmpty(newsletter.Subject, "Untitled"),
+		URL:         "mid:" + id,
+		Author:      firstNonEmpty(newsletter.FromName, newsletter.FromAddress),
+		Content:     content,
+		ContentText: text,
+		PublishedAt: newsletter.Date,
+	}
+}
+
+func (a *App) ingestNewsletters() (int, error) {
+	log := logFor("imap").With("folder", a.imap.folder)
+	start := time.Now()
+	newsletters, err := a.imap.Fetch()
+	if err != nil {
+		log.Warn("fetch failed", "duration", time.Since(start), "err", err)
+		return 0, err
+	}
+	feeds := map[string]Feed{}
+	for _, feed := range a.store.Feeds() {
+		feeds[feed.URL] = feed
+	}
+	grouped := map[string][]Article{}
+	for _, newsletter := range newsletters {
+		feedURL := newsletterFeedURL(newsletter.FromAddress)
+		if _, ok := feeds[feedURL]; !ok {
+			feed, err := a.store.InsertFeed(Feed{
+				Title:       firstNonEmpty(newsletter.FromName, newsletter.FromAddress),
+				URL:         feedURL,
+				Description: "Newsletters from " + newsletter.FromAddress,
+			})
+			if err != nil {
+				return 0, err
+			}
+			feeds[feedURL] = feed
+		}
+		grouped[feedURL] = append(grouped[feedURL], newsletterArticle(newsletter))
+	}
+	added := 0
+	for feedURL, articles := range grouped {
+		inserted, err := a.store.InsertArticles(feeds[feedURL], articles)
+		if err != nil {
+			return added, err
+		}
+		added += len(inserted)
+	}
+	log.Info("ingested", "messages", len(newsletters), "new", added, "duration", time.Since(start))
+	return added, nil
+}
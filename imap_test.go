@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+const newsletterHTML = "From: \"Weekly Go\" <News@Weekly.example>\r\n" +
+	"Subject: =?UTF-8?Q?Issue_42_=E2=80=94_generics?=\r\n" +
+	"Date: Mon, 04 Mar 2024 09:00:00 +0000\r\n" +
+	"Message-ID: <issue42@weekly.example>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/alternative; boundary=b1\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Plain issue 42\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"PHA+SGVsbG8gPGI+cmVhZGVyczwvYj48L3A+\r\n" +
+	"--b1--\r\n"
+
+const newsletterText = "From: digest@letters.example\r\n" +
+	"Subject: Daily digest\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Caf=C3=A9 <news>\r\n"
+
+type fakeIMAPServer struct {
+	messages []string
+	failOn   string
+	commands []string
+}
+
+func (s *fakeIMAPServer) dial(string) (net.Conn, error) {
+	client, server := net.Pipe()
+	go s.serve(server)
+	return client, nil
+}
+
+func (s *fakeIMAPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	fmt.Fprint(conn, "* OK fake IMAP ready\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, cmd, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		s.commands = append(s.commands, cmd)
+		if s.failOn != "" && strings.HasPrefix(cmd, s.failOn) {
+			fmt.Fprintf(conn, "%s NO [AUTHENTICATIONFAILED] denied\r\n", tag)
+			continue
+		}
+		switch {
+		case strings.HasPrefix(cmd, "UID SEARCH"):
+			uids := []string{}
+			for i := range s.messages {
+				uids = append(uids, fmt.Sprint(i+1))
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(uids, " "))
+		case strings.HasPrefix(cmd, "UID FETCH"):
+			for i, message := range s.messages {
+				fmt.Fprintf(conn, "* %d FETCH (UID %d BODY[] {%d}\r\n%s)\r\n", i+1, i+1, len(message), message)
+			}
+		case cmd == "LOGOUT":
+			fmt.Fprintf(conn, "* BYE\r\n%s OK bye\r\n", tag)
+			return
+		}
+		fmt.Fprintf(conn, "%s OK done\r\n", tag)
+	}
+}
+
+func TestNewIMAPClient(t *testing.T) {
+	if NewIMAPClient(Config{}) != nil || NewIMAPClient(Config{IMAPHost: "mail.example.com"}) != nil {
+		t.Fatalf("expected nil client when unconfigured")
+	}
+	client := NewIMAPClient(Config{IMAPHost: "mail.example.com", IMAPUsername: "me"})
+	if client.addr != "mail.example.com:993" || client.folder != "INBOX" {
+		t.Fatalf("unexpected client %+v", client)
+	}
+	client = NewIMAPClient(Config{IMAPHost: "localhost", IMAPUsername: "me", IMAPPlaintext: true, IMAPFolder: "News"})
+	if client.addr != "localhost:143" || client.folder != "News" {
+		t.Fatalf("unexpected plaintext client %+v", client)
+	}
+	if NewIMAPClient(Config{IMAPHost: "localhost:1143", IMAPUsername: "me"}).addr != "localhost:1143" {
+		t.Fatalf("expected explicit port to be kept")
+	}
+	var missing *IMAPClient
+	if _, err := missing.Fetch(); err == nil {
+		t.Fatalf("expected not configured error")
+	}
+}
+
+func TestParseNewsletter(t *testing.T) {
+	newsletter, err := parseNewsletter([]byte(newsletterHTML))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if newsletter.FromName != "Weekly Go" || newsletter.FromAddress != "news@weekly.example" || newsletter.Subject != "Issue 42 — generics" || newsletter.MessageID != "issue42@weekly.example" || newsletter.Date.IsZero() {
+		t.Fatalf("unexpected headers %+v", newsletter)
+	}
+	if newsletter.HTML != "<p>Hello <b>readers</b></p>" || newsletter.Text != "Plain issue 42" {
+		t.Fatalf("unexpected body %+v", newsletter)
+	}
+	article := newsletterArticle(newsletter)
+	if article.GUID != "mid:issue42@weekly.example" || article.URL != article.GUID || article.Author != "Weekly Go" || article.ContentText != "Hello readers" {
+		t.Fatalf("unexpected article %+v", article)
+	}
+
+	plain, err := parseNewsletter([]byte(newsletterText))
+	if err != nil || plain.Text != "Café <news>" || plain.HTML != "" {
+		t.Fatalf("unexpected plain newsletter %+v %v", plain, err)
+	}
+	article = newsletterArticle(plain)
+	if article.Content != "<pre>Café &lt;news&gt;</pre>" || article.Author != "digest@letters.example" || !strings.HasPrefix(article.GUID, "mid:") || len(article.GUID) != 44 {
+		t.Fatalf("unexpected plain article %+v", article)
+	}
+	if _, err := parseNewsletter([]byte("Subject: no sender\r\n\r\nbody")); err == nil {
+		t.Fatalf("expected missing sender error")
+	}
+}
+
+func TestIMAPFetch(t *testing.T) {
+	server := &fakeIMAPServer{messages: []string{newsletterHTML, "not a message", newsletterText}}
+	client := &IMAPClient{addr: "fake:143", username: "me", password: `pa"ss`, folder: "Newsletters", dial: server.dial}
+	newsletters, err := client.Fetch()
+	if err != nil {
+		t.Fatalf("fetch error: %v", err)
+	}
+	if len(newsletters) != 2 || newsletters[0].Subject != "Issue 42 — generics" || newsletters[1].Subject != "Daily digest" {
+		t.Fatalf("unexpected newsletters %+v", newsletters)
+	}
+	want := []string{`LOGIN "me" "pa\"ss"`, `SELECT "Newsletters"`, "UID SEARCH UNSEEN", "UID FETCH 1,2,3 (BODY.PEEK[])", `UID STORE 1,2,3 +FLAGS.SILENT (\Seen)`, "LOGOUT"}
+	if strings.Join(server.commands, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected commands %q", server.commands)
+	}
+
+	empty := &fakeIMAPServer{}
+	client.dial = empty.dial
+	if newsletters, err := client.Fetch(); err != nil || len(newsletters) != 0 || len(empty.commands) != 4 {
+		t.Fatalf("unexpected empty fetch %+v %v %q", newsletters, err, empty.commands)
+	}
+	client.dial = (&fakeIMAPServer{failOn: "LOGIN"}).dial
+	if _, err := client.Fetch(); err == nil || !strings.Contains(err.Error(), "AUTHENTICATIONFAILED") {
+		t.Fatalf("expected login error, got %v", err)
+	}
+	client.dial = func(string) (net.Conn, error) { return nil, errors.New("refused") }
+	if _, err := client.Fetch(); err == nil {
+		t.Fatalf("expected dial error")
+	}
+}
+
+func TestAppIngestNewsletters(t *testing.T) {
+	app := newTUIApp(t)
+	server := &fakeIMAPServer{messages: []string{newsletterHTML, newsletterText}}
+	app.imap = &IMAPClient{addr: "fake:143", username: "me", folder: "INBOX", dial: server.dial}
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("refresh error: %v", err)
+	}
+	if len(app.feeds) != 2 || !strings.Contains(app.status, "2 new newsletters") {
+		t.Fatalf("unexpected feeds %+v status %q", app.feeds, app.status)
+	}
+	titles := map[string]string{}
+	for _, feed := range app.feeds {
+		titles[feed.URL] = feed.Title
+	}
+	if titles["mailto:news@weekly.example"] != "Weekly Go" || titles["mailto:digest@letters.example"] != "digest@letters.example" {
+		t.Fatalf("unexpected newsletter feeds %+v", titles)
+	}
+	if err := app.RefreshFeeds(); err != nil || app.status != "refreshed 0 feeds" {
+		t.Fatalf("expected newsletter feeds skipped by fetcher and no duplicates, got %q %v", app.status, err)
+	}
+	if len(app.articles) != 2 {
+		t.Fatalf("expected two newsletter articles, got %d", len(app.articles))
+	}
+	app.imap.dial = (&fakeIMAPServer{failOn: "SELECT"}).dial
+	if err := app.RefreshFeeds(); err != nil || !strings.Contains(app.status, "newsletters failed") {
+		t.Fatalf("expected newsletter failure in status, got %q %v", app.status, err)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type listOptions struct {
+	unread  bool
+	starred bool
+	feedID  int
+	limit   int
+}
+
+type articleListing struct {
+	ID          int       `json:"id"`
+	FeedID      int       `json:"feed_id"`
+	FeedTitle   string    `json:"feed_title"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	IsRead      bool      `json:"is_read"`
+	IsStarred   bool      `json:"is_starred"`
+	Score       int       `json:"score"`
+}
+
+func takeJSONFlag(args []string) ([]string, bool) {
+	jsonOutput := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOutput = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, jsonOutput
+}
+
+func parseListArgs(args []string) (listOptions, error) {
+	opts := listOptions{}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--unread", "unread":
+			opts.unread = true
+		case "--starred", "starred":
+			opts.starred = true
+		case "--feed", "--limit":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("missing value for %s", args[i])
+			}
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil || value <= 0 {
+				return opts, fmt.Errorf("invalid %s: %s", args[i], args[i+1])
+			}
+			if args[i] == "--feed" {
+				opts.feedID = value
+			} else {
+				opts.limit = value
+			}
+			i++
+		default:
+			return opts, fmt.Errorf("unknown list option: %s", args[i])
+		}
+	}
+	return opts, nil
+}
+
+func (a *App) ListArticles(opts listOptions) []articleListing {
+	listings := []articleListing{}
+	for _, article := range a.store.SortedArticles() {
+		if (opts.unread && article.IsRead) || (opts.starred && !article.IsStarred) || (opts.feedID != 0 && article.FeedID != opts.feedID) {
+			continue
+		}
+		listings = append(listings, articleListing{
+			ID:          article.ID,
+			FeedID:      article.FeedID,
+			FeedTitle:   article.FeedTitle,
+			Title:       article.Title,
+			URL:         article.URL,
+			Author:      article.Author,
+			PublishedAt: article.PublishedAt,
+			IsRead:      article.IsRead,
+			IsStarred:   article.IsStarred,
+			Score:       article.Score,
+		})
+		if opts.limit > 0 && len(listings) == opts.limit {
+			break
+		}
+	}
+	return listings
+}
+
+func formatArticleListing(listing articleListing) string {
+	return fmt.Sprintf("%d\t%s\t%s\t%s", listing.ID, listing.FeedTitle, listing.Title, listing.URL)
+}
+
+func writeJSONLines[T any](out io.Writer, values []T) error {
+	encoder := json.NewEncoder(out)
+	for _, value := range values {
+		if err := encoder.Encode(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseListArgs(t *testing.T) {
+	opts, err := parseListArgs([]string{"--unread", "--feed", "3", "--limit", "10"})
+	if err != nil || !opts.unread || opts.starred || opts.feedID != 3 || opts.limit != 10 {
+		t.Fatalf("unexpected options %+v %v", opts, err)
+	}
+	for _, args := range [][]string{{"--feed"}, {"--limit", "0"}, {"--bogus"}} {
+		if _, err := parseListArgs(args); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestListArticlesFilters(t *testing.T) {
+	app := newTUIApp(t)
+	feedA, _ := app.store.InsertFeed(Feed{Title: "A", URL: "https://a.example.com/rss"})
+	feedB, _ := app.store.InsertFeed(Feed{Title: "B", URL: "https://b.example.com/rss"})
+	now := time.Now()
+	if _, err := app.store.InsertArticles(feedA, []Article{
+		{GUID: "a1", Title: "Read", URL: "https://a.example.com/1", IsRead: true, PublishedAt: now},
+		{GUID: "a2", Title: "Starred", URL: "https://a.example.com/2", IsStarred: true, PublishedAt: now.Add(-time.Hour)},
+	}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feedB, []Article{{GUID: "b1", Title: "Unread", URL: "https://b.example.com/1", PublishedAt: now.Add(-2 * time.Hour)}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	all := app.ListArticles(listOptions{})
+	if len(all) != 3 {
+		t.Fatalf("expected all articles, got %+v", all)
+	}
+	if got := app.ListArticles(listOptions{unread: true}); len(got) != 2 {
+		t.Fatalf("expected two unread articles, got %+v", got)
+	}
+	if got := app.ListArticles(listOptions{feedID: feedB.ID}); len(got) != 1 || got[0].Title != "Unread" || got[0].FeedTitle != "B" {
+		t.Fatalf("expected feed B only, got %+v", got)
+	}
+	for _, listing := range app.ListArticles(listOptions{unread: true}) {
+		if listing.IsRead {
+			t.Fatalf("expected only unread articles, got %+v", listing)
+		}
+	}
+	for _, listing := range app.ListArticles(listOptions{starred: true}) {
+		if !listing.IsStarred {
+			t.Fatalf("expected only starred articles, got %+v", listing)
+		}
+	}
+	if got := app.ListArticles(listOptions{limit: 1}); len(got) != 1 || got[0].ID != all[0].ID {
+		t.Fatalf("expected first article only, got %+v", got)
+	}
+	if got := app.ListArticles(listOptions{feedID: 9999}); len(got) != 0 {
+		t.Fatalf("expected no articles for unknown feed, got %+v", got)
+	}
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	published := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	listings := []articleListing{{ID: 1, Title: "One", PublishedAt: published}, {ID: 2, Title: "Two", IsStarred: true}}
+	if err := writeJSONLines(&out, listings); err != nil {
+		t.Fatalf("writeJSONLines error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"published_at":"2026-01-02T03:04:05Z"`) || !strings.Contains(lines[1], `"is_starred":true`) {
+		t.Fatalf("unexpected json lines %q", out.String())
+	}
+	if got := formatArticleListing(articleListing{ID: 7, FeedTitle: "Feed", Title: "Title", URL: "https://x"}); got != "7\tFeed\tTitle\thttps://x" {
+		t.Fatalf("unexpected plain listing %q", got)
+	}
+}
+
+func TestRunMainListAndRefreshJSON(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+	oldTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if strings.Contains(r.URL.Host, "broken") {
+			return nil, errors.New("connection refused")
+		}
+		return newResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"}, r), nil
+	})
+	t.Cleanup(func() { http.DefaultTransport = oldTransport })
+	app, err := NewApp(DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewApp error: %v", err)
+	}
+	for _, url := range []string{"http://example.test/rss", "http://broken.test/rss"} {
+		if _, err := app.store.InsertFeed(Feed{Title: url, URL: url}); err != nil {
+			t.Fatalf("InsertFeed error: %v", err)
+		}
+	}
+	app.store.Close()
+
+	var stdout, stderr bytes.Buffer
+	if err := runMain([]string{"refresh", "--json"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain refresh error: %v %s", err, stderr.String())
+	}
+	statuses := map[string]feedRefreshResult{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var result feedRefreshResult
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Fatalf("invalid json line %q: %v", line, err)
+		}
+		statuses[result.URL] = result
+	}
+	if ok := statuses["http://example.test/rss"]; ok.Status != feedResultOK || ok.New == 0 {
+		t.Fatalf("unexpected ok result %+v", ok)
+	}
+	if failed := statuses["http://broken.test/rss"]; failed.Status != feedResultFailed || !strings.Contains(failed.Error, "connection refused") {
+		t.Fatalf("unexpected failed result %+v", failed)
+	}
+
+	stdout.Reset()
+	if err := runMain([]string{"list", "--unread", "--json", "--limit", "1"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain list error: %v %s", err, stderr.String())
+	}
+	var listing articleListing
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &listing); err != nil || listing.ID == 0 || listing.IsRead {
+		t.Fatalf("unexpected listing %q %v", stdout.String(), err)
+	}
+
+	stderr.Reset()
+	if err := runMain([]string{"list", "--bogus"}, strings.NewReader(""), &stdout, &stderr); err == nil || !strings.Contains(stderr.String(), "list error") {
+		t.Fatalf("expected list error, got %v %q", err, stderr.String())
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+type keyBinding struct {
+	action string
+	keys   []string
+}
+
+var defaultKeyBindings = []keyBinding{
+	{"quit", []string{"ctrl+c", "q"}},
+	{"cancel", []string{"esc"}},
+	{"help", []string{"/"}},
+	{"down", []string{"j", "down"}},
+	{"up", []string{"k", "up"}},
+	{"summarize", []string{"enter"}},
+	{"summarize_all", []string{"G"}},
+	{"regenerate", []string{"R"}},
+	{"digest", []string{"D"}},
+	{"digests", []string{"H"}},
+	{"chat", []string{"c"}},
+	{"refresh", []string{"r"}},
+	{"sync", []string{"Y"}},
+	{"add_feed", []string{"a"}},
+	{"import_opml", []string{"i"}},
+	{"export_opml", []string{"w"}},
+	{"import_state", []string{"I"}},
+	{"export_state", []string{"E"}},
+	{"bookmark", []string{"b"}},
+	{"collection", []string{"C"}},
+	{"share", []string{"S"}},
+	{"star", []string{"s"}},
+	{"queue", []string{"Q"}},
+	{"queue_view", []string{"L"}},
+	{"visual", []string{"v"}},
+	{"select", []string{"space"}},
+	{"queue_up", []string{"K"}},
+	{"queue_down", []string{"J"}},
+	{"mark_read", []string{"m"}},
+	{"mark_all_read", []string{"A"}},
+	{"star_matching", []string{"*"}},
+	{"open", []string{"o"}},
+	{"open_starred", []string{"O"}},
+	{"email", []string{"e"}},
+	{"copy_url", []string{"y"}},
+	{"copy_summary", []string{"ctrl+y"}},
+	{"copy_markdown", []string{"M"}},
+	{"copy_content", []string{"alt+y"}},
+	{"note", []string{"N"}},
+	{"export", []string{"x"}},
+	{"vault", []string{"V"}},
+	{"speak", []string{"p"}},
+	{"speak_article", []string{"P"}},
+	{"filter", []string{"f"}},
+	{"mute", []string{"W"}},
+	{"show_muted", []string{"h"}},
+	{"sidebar", []string{"tab"}},
+	{"saved", []string{"B"}},
+	{"feeds", []string{"F"}},
+	{"sort", []string{"z"}},
+	{"older_summary", []string{"["}},
+	{"newer_summary", []string{"]"}},
+	{"tag", []string{"t"}},
+	{"topic", []string{"T"}},
+	{"delete", []string{"d"}},
+	{"undelete", []string{"u"}},
+	{"undelete_days", []string{"U"}},
+	{"trash", []string{"X"}},
+	{"page_up", []string{"pgup", "ctrl+u"}},
+	{"page_down", []string{"pgdown", "ctrl+d"}},
+	{"top", []string{"home"}},
+	{"bottom", []string{"end"}},
+}
+
+var namedKeys = map[string]bool{
+	"up": true, "down": true, "left": true, "right": true, "enter": true, "tab": true, "esc": true,
+	"backspace": true, "delete": true, "insert": true, "home": true, "end": true, "pgup": true, "pgdown": true,
+	"space": true, "f1": true, "f2": true, "f3": true, "f4": true, "f5": true, "f6": true, "f7": true,
+	"f8": true, "f9": true, "f10": true, "f11": true, "f12": true,
+}
+
+func findKeyBinding(action string) (keyBinding, bool) {
+	for _, binding := range defaultKeyBindings {
+		if binding.action == action {
+			return binding, true
+		}
+	}
+	return keyBinding{}, false
+}
+
+func validKeyName(key string) bool {
+	for _, prefix := range []string{"ctrl+", "alt+", "shift+"} {
+		if rest, ok := strings.CutPrefix(key, prefix); ok && rest != "" {
+			key = rest
+		}
+	}
+	return utf8.RuneCountInString(key) == 1 && key != " " || namedKeys[key]
+}
+
+func parseKeysSection(action string, value string, cfg *Config) error {
+	if _, ok := findKeyBinding(action); !ok {
+		return fmt.Errorf("invalid keys action: %q", action)
+	}
+	keys := []string{trimQuotes(value)}
+	if strings.HasPrefix(strings.TrimSpace(value), "[") {
+		items, err := parseStringArray(value)
+		if err != nil {
+			return err
+		}
+		keys = items
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("invalid keys.%s: no keys given", action)
+	}
+	for _, key := range keys {
+		if !validKeyName(key) {
+			return fmt.Errorf("invalid key for %s: %q", action, key)
+		}
+	}
+	if cfg.Keys == nil {
+		cfg.Keys = map[string][]string{}
+	}
+	cfg.Keys[action] = keys
+	return nil
+}
+
+func validateKeyBindings(custom map[string][]string) error {
+	owners := map[string]string{}
+	for _, binding := range defaultKeyBindings {
+		keys, ok := custom[binding.action]
+		if !ok {
+			keys = binding.keys
+		}
+		for _, key := range keys {
+			if owner, taken := owners[key]; taken {
+				return fmt.Errorf("invalid keys: %q is bound to both %s and %s", key, owner, binding.action)
+			}
+			owners[key] = binding.action
+		}
+	}
+	return nil
+}
+
+func buildKeyRemap(custom map[string][]string) map[string]string {
+	remap := map[string]string{}
+	for _, binding := range defaultKeyBindings {
+		for _, key := range custom[binding.action] {
+			remap[key] = binding.keys[0]
+		}
+	}
+	for _, binding := range defaultKeyBindings {
+		if _, ok := custom[binding.action]; !ok {
+			continue
+		}
+		for _, key := range binding.keys {
+			if _, taken := remap[key]; !taken {
+				remap[key] = ""
+			}
+		}
+	}
+	return remap
+}
+
+func renderKeyBindings(custom map[string][]string) []string {
+	actions := make([]string, 0, len(custom))
+	for action := range custom {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	lines := []string{}
+	for _, action := range actions {
+		lines = append(lines, fmt.Sprintf("%-14s - %s", strings.Join(custom[action], "/"), strings.ReplaceAll(action, "_", " ")))
+	}
+	return lines
+}
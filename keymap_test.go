@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestValidKeyName(t *testing.T) {
+	for _, key := range []string{"x", "X", "/", "ctrl+s", "alt+enter", "f5", "pgdown", "é"} {
+		if !validKeyName(key) {
+			t.Fatalf("expected %q to be valid", key)
+		}
+	}
+	for _, key := range []string{"", " ", "xx", "ctrl+", "hyper+x", "f13"} {
+		if validKeyName(key) {
+			t.Fatalf("expected %q to be invalid", key)
+		}
+	}
+}
+
+func TestParseKeysSection(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("[keys]\nstar = \"g\"\ndown = [\"n\", \"down\"]", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if strings.Join(cfg.Keys["star"], ",") != "g" || strings.Join(cfg.Keys["down"], ",") != "n,down" {
+		t.Fatalf("unexpected keys %+v", cfg.Keys)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil || strings.Join(reparsed.Keys["down"], ",") != "n,down" {
+		t.Fatalf("keys did not round trip: %v\n%s", err, renderConfig(cfg))
+	}
+	for bad, want := range map[string]string{
+		"[keys]\nteleport = \"x\"":                "config line 2: invalid keys action",
+		"[keys]\nstar = \"xx\"":                   "invalid key for star",
+		"[keys]\nstar = []":                       "no keys given",
+		"[keys]\nstar = [x":                       "invalid array value",
+		"[keys]\nstar = \"m\"":                    `"m" is bound to both star and mark_read`,
+		"[keys]\nstar = \"x\"\nmark_read = \"x\"": `"x" is bound to both star and mark_read`,
+	} {
+		cfg := DefaultConfig()
+		if err := parseConfig(bad, &cfg); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q for %q, got %v", want, bad, err)
+		}
+	}
+	swapped := DefaultConfig()
+	if err := parseConfig("[keys]\nstar = \"m\"\nmark_read = \"s\"", &swapped); err != nil {
+		t.Fatalf("expected swapped keys to be valid: %v", err)
+	}
+}
+
+func TestBuildKeyRemap(t *testing.T) {
+	remap := buildKeyRemap(map[string][]string{"star": {"x"}, "mark_read": {"s"}, "down": {"n", "down"}})
+	if remap["x"] != "s" || remap["s"] != "m" || remap["m"] != "" || remap["n"] != "j" || remap["down"] != "j" || remap["j"] != "" {
+		t.Fatalf("unexpected remap %+v", remap)
+	}
+	if _, ok := remap["o"]; ok {
+		t.Fatalf("expected unbound actions untouched")
+	}
+	if len(buildKeyRemap(nil)) != 0 {
+		t.Fatalf("expected empty remap without custom keys")
+	}
+	if lines := renderKeyBindings(map[string][]string{"star": {"x"}, "down": {"n", "down"}}); len(lines) != 2 || !strings.HasPrefix(lines[0], "n/down") || !strings.HasSuffix(lines[1], "- star") {
+		t.Fatalf("unexpected custom key lines %q", lines)
+	}
+}
+
+func TestTUICustomKeys(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "One", URL: "https://example.com/1"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.config.Keys = map[string][]string{"star": {"x"}}
+	model := newTUIModel(app)
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	model = updated.(tuiModel)
+	if app.articles[0].IsStarred {
+		t.Fatalf("expected default star key unbound")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	model = updated.(tuiModel)
+	if !app.articles[0].IsStarred {
+		t.Fatalf("expected custom key to star")
+	}
+	model.width, model.height = 100, 60
+	if !strings.Contains(model.renderHelpOverlay(), "Custom keys") {
+		t.Fatalf("expected custom keys in help overlay")
+	}
+}
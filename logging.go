@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultLogLevel = "info"
+
+var (
+	logger   = slog.New(slog.DiscardHandler)
+	logLevel = new(slog.LevelVar)
+)
+
+func logFor(component string) *slog.Logger {
+	return logger.With("component", component)
+}
+
+func defaultLogPath() string {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, "greeder", "greeder.log")
+}
+
+func parseLogLevel(value string) (slog.Level, bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug, true, nil
+	case "", "info":
+		return slog.LevelInfo, true, nil
+	case "warn", "warning":
+		return slog.LevelWarn, true, nil
+	case "error":
+		return slog.LevelError, true, nil
+	case "off":
+		return 0, false, nil
+	}
+	return 0, false, fmt.Errorf("invalid log_level: %q", value)
+}
+
+func setupLogging(cfg Config) (func() error, error) {
+	logger = slog.New(slog.DiscardHandler)
+	level, enabled, err := parseLogLevel(cfg.LogLevel)
+	if err != nil || !enabled {
+		return func() error { return nil }, err
+	}
+	path := expandHome(cfg.LogFile)
+	if path == "" {
+		path = defaultLogPath()
+	}
+	if path == "" {
+		return func() error { return nil }, fmt.Errorf("no log path")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return func() error { return nil }, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return func() error { return nil }, err
+	}
+	logLevel.Set(level)
+	logger = slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{Level: logLevel}))
+	return func() error {
+		logger = slog.New(slog.DiscardHandler)
+		return file.Close()
+	}, nil
+}
+
+func applyLogLevel(cfg Config) {
+	level, enabled, err := parseLogLevel(cfg.LogLevel)
+	if err != nil {
+		return
+	}
+	if !enabled {
+		level = slog.LevelError + 4
+	}
+	logLevel.Set(level)
+}
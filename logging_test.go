@@ -0,0 +1,110 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	stateDir, err := os.MkdirTemp("", "greeder-state")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_STATE_HOME", stateDir)
+	code := m.Run()
+	os.RemoveAll(stateDir)
+	os.Exit(code)
+}
+
+func TestParseLogLevel(t *testing.T) {
+	cases := map[string]slog.Level{"debug": slog.LevelDebug, "": slog.LevelInfo, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "error": slog.LevelError}
+	for value, want := range cases {
+		level, enabled, err := parseLogLevel(value)
+		if err != nil || !enabled || level != want {
+			t.Fatalf("parseLogLevel(%q) = %v %v %v", value, level, enabled, err)
+		}
+	}
+	if _, enabled, err := parseLogLevel("off"); err != nil || enabled {
+		t.Fatalf("expected off to disable logging")
+	}
+	if _, _, err := parseLogLevel("loud"); err == nil {
+		t.Fatalf("expected invalid level error")
+	}
+}
+
+func TestDefaultLogPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	if got := defaultLogPath(); got != filepath.Join("/tmp/state", "greeder", "greeder.log") {
+		t.Fatalf("unexpected log path %q", got)
+	}
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", "/tmp/home")
+	if got := defaultLogPath(); got != filepath.Join("/tmp/home", ".local", "state", "greeder", "greeder.log") {
+		t.Fatalf("unexpected fallback log path %q", got)
+	}
+}
+
+func TestSetupLogging(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "greeder.log")
+	cfg := DefaultConfig()
+	cfg.LogFile = path
+	cfg.LogLevel = "warn"
+	closeLog, err := setupLogging(cfg)
+	if err != nil {
+		t.Fatalf("setupLogging error: %v", err)
+	}
+	logFor("fetcher").Info("hidden")
+	logFor("fetcher").Warn("fetch failed", "feed", "https://example.com/rss")
+	cfg.LogLevel = "debug"
+	applyLogLevel(cfg)
+	logFor("sync").Debug("verbose")
+	cfg.LogLevel = "off"
+	applyLogLevel(cfg)
+	logFor("sync").Error("silenced")
+	if err := closeLog(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+	logFor("main").Error("after close")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log error: %v", err)
+	}
+	out := string(data)
+	if strings.Contains(out, "hidden") || strings.Contains(out, "silenced") || strings.Contains(out, "after close") {
+		t.Fatalf("unexpected log lines %q", out)
+	}
+	if !strings.Contains(out, "component=fetcher") || !strings.Contains(out, "feed=https://example.com/rss") || !strings.Contains(out, "msg=verbose") {
+		t.Fatalf("missing log lines %q", out)
+	}
+}
+
+func TestSetupLoggingDisabledAndErrors(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LogLevel = "off"
+	cfg.LogFile = filepath.Join(t.TempDir(), "never.log")
+	closeLog, err := setupLogging(cfg)
+	if err != nil || closeLog() != nil {
+		t.Fatalf("expected disabled logging, got %v", err)
+	}
+	if _, err := os.Stat(cfg.LogFile); !os.IsNotExist(err) {
+		t.Fatalf("expected no log file")
+	}
+
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, []byte("x"), 0o644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	cfg.LogLevel = "info"
+	cfg.LogFile = filepath.Join(blocker, "greeder.log")
+	if _, err := setupLogging(cfg); err == nil {
+		t.Fatalf("expected mkdir error")
+	}
+	cfg.LogLevel = "loud"
+	if _, err := setupLogging(cfg); err == nil {
+		t.Fatalf("expected level error")
+	}
+}
@@ -1,15 +1,20 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+
+	"github.com/mattn/go-isatty"
 )
 
 var (
 	exitFunc     = os.Exit
 	refreshFeeds = func(app *App) error { return app.RefreshFeeds() }
 	runTUI       = RunTUI
+	isTerminalFd = func(fd uintptr) bool { return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd) }
 )
 
 func main() {
@@ -20,88 +25,323 @@ func main() {
 
 func runMain(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
 	if err := maybeOfferMigration(stdin, stdout, stderr); err != nil {
-		fmt.Fprintln(stderr, "migration error:", err)
-		return err
+		return reportError(stderr, msgCLIMigrationError, err)
 	}
 	cfg, err := LoadConfig()
 	if err != nil {
-		fmt.Fprintln(stderr, "config error:", err)
-		return err
+		return reportError(stderr, msgCLIConfigError, err)
+	}
+	closeLog, err := setupLogging(cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, tr(msgCLILogError, err))
+	}
+	defer closeLog()
+	logFor("main").Info("starting", "args", args)
+	args, tuiMode := takeTUIFlag(args)
+	args, jsonOutput := takeJSONFlag(args)
+	if len(args) >= 1 && args[0] == "cache" {
+		if err := runCacheCommand(cfg, args[1:], stdout); err != nil {
+			return reportError(stderr, msgCLICacheError, err)
+		}
+		return nil
+	}
+	if len(args) >= 1 && args[0] == "--db-maintenance" {
+		if err := runDBMaintenance(cfg, stdout); err != nil {
+			return reportError(stderr, msgCLIDBMaintenanceError, err)
+		}
+		return nil
+	}
+	if len(args) >= 1 && args[0] == "--set-secret" {
+		name := ""
+		if len(args) >= 2 {
+			name = args[1]
+		}
+		if _, err := SetSecret(cfg, name, stdin, stdout); err != nil {
+			return reportError(stderr, msgCLISecretError, err)
+		}
+		if err := saveSecretConfig(name); err != nil {
+			return reportError(stderr, msgCLISecretError, err)
+		}
+		return nil
+	}
+	if len(args) >= 1 && args[0] == "--pocket-login" {
+		cfg, err = PocketLogin(cfg, stdout)
+		if err != nil {
+			return reportError(stderr, msgCLIPocketLoginError, err)
+		}
+		if err := patchConfigFile(map[string]string{"pocket_access_token": strconv.Quote(cfg.PocketAccessToken)}); err != nil {
+			return reportError(stderr, msgCLIPocketLoginError, err)
+		}
+		fmt.Fprintln(stdout, tr(msgCLIPocketConnected))
+		return nil
 	}
 	app, err := NewApp(cfg)
 	if err != nil {
-		fmt.Fprintln(stderr, "init error:", err)
-		return err
+		return reportError(stderr, msgCLIInitError, err)
 	}
 
+	if len(args) >= 2 && args[0] == "--collection" {
+		if err := app.SetRaindropCollection(args[1]); err != nil {
+			return reportError(stderr, msgCLICollectionError, err)
+		}
+		args = args[2:]
+	}
+	if len(args) >= 1 && args[0] == "--collections" {
+		collections, err := app.raindrop.ListCollections()
+		if err != nil {
+			return reportError(stderr, msgCLICollectionsError, err)
+		}
+		fmt.Fprintln(stdout, formatRaindropCollections(collections))
+		return nil
+	}
 	if len(args) >= 2 && args[0] == "--import" {
-		if err := app.ImportOPML(args[1]); err != nil {
-			fmt.Fprintln(stderr, "import error:", err)
-			return err
+		path, dryRun := parseImportArgs(args[1:])
+		if dryRun {
+			report, err := app.PlanOPMLImport(path)
+			if err != nil {
+				return reportError(stderr, msgCLIImportError, err)
+			}
+			fmt.Fprintln(stdout, formatOPMLReport(report))
+			return nil
+		}
+		report, err := app.ImportOPMLReport(path)
+		if err != nil {
+			return reportError(stderr, msgCLIImportError, err)
 		}
-		fmt.Fprintf(stdout, "Imported feeds from %s\n", args[1])
+		fmt.Fprintln(stdout, formatOPMLReport(report))
+		fmt.Fprintln(stdout, tr(msgCLIImportedFeeds, path))
 		return nil
 	}
 	if len(args) >= 2 && args[0] == "--import-state" {
 		if err := app.ImportState(args[1]); err != nil {
-			fmt.Fprintln(stderr, "import state error:", err)
-			return err
+			return reportError(stderr, msgCLIImportStateError, err)
 		}
-		fmt.Fprintf(stdout, "Imported state from %s\n", args[1])
+		fmt.Fprintln(stdout, tr(msgCLIImportedState, args[1]))
 		return nil
 	}
 	if len(args) >= 2 && args[0] == "--export-state" {
 		if err := app.ExportState(args[1]); err != nil {
-			fmt.Fprintln(stderr, "export state error:", err)
-			return err
+			return reportError(stderr, msgCLIExportStateError, err)
+		}
+		fmt.Fprintln(stdout, tr(msgCLIExportedState, args[1]))
+		return nil
+	}
+	if len(args) >= 2 && args[0] == "--export-legacy" {
+		if err := app.ExportLegacy(args[1]); err != nil {
+			return reportError(stderr, msgCLIExportLegacyError, err)
+		}
+		fmt.Fprintln(stdout, tr(msgCLIExportedLegacy, app.status, args[1]))
+		return nil
+	}
+	if len(args) >= 1 && args[0] == "--export-notes" {
+		count, err := app.ExportStarredNotes()
+		if err != nil {
+			return reportError(stderr, msgCLINotesError, err)
+		}
+		fmt.Fprintln(stdout, tr(msgCLINotesWritten, count, expandHome(app.config.NotesDir)))
+		return nil
+	}
+	if len(args) >= 2 && args[0] == "--export-bibtex" {
+		count, err := app.ExportBibTeX(args[1])
+		if err != nil {
+			return reportError(stderr, msgCLIBibTeXError, err)
+		}
+		fmt.Fprintln(stdout, tr(msgCLIBibTeXExported, count, args[1]))
+		return nil
+	}
+	if len(args) >= 1 && (args[0] == "--export-epub" || args[0] == "export-epub") {
+		selection, path, err := parseEPUBArgs(args[1:])
+		if err == nil {
+			_, err = app.ExportEPUB(path, selection)
+		}
+		if err != nil {
+			return reportError(stderr, msgCLIEPUBError, err)
+		}
+		fmt.Fprintln(stdout, app.status)
+		return nil
+	}
+	if len(args) >= 1 && (args[0] == "--export-pdf" || args[0] == "export-pdf") {
+		selection, articleID, path, err := parsePDFArgs(args[1:])
+		if err == nil {
+			_, err = app.ExportPDF(path, selection, articleID)
+		}
+		if err != nil {
+			return reportError(stderr, msgCLIPDFError, err)
+		}
+		fmt.Fprintln(stdout, app.status)
+		return nil
+	}
+	if len(args) >= 1 && args[0] == "--zotero" {
+		count, err := app.SaveStarredToZotero()
+		if err != nil {
+			return reportError(stderr, msgCLIZoteroError, err)
+		}
+		fmt.Fprintln(stdout, tr(msgCLIZoteroSaved, count))
+		return nil
+	}
+	if len(args) >= 1 && (args[0] == "--list" || args[0] == "list") {
+		opts, err := parseListArgs(args[1:])
+		if err != nil {
+			return reportError(stderr, msgCLIListError, err)
+		}
+		listings := app.ListArticles(opts)
+		if jsonOutput {
+			if err := writeJSONLines(stdout, listings); err != nil {
+				return reportError(stderr, msgCLIListError, err)
+			}
+			return nil
 		}
-		fmt.Fprintf(stdout, "Exported state to %s\n", args[1])
+		for _, listing := range listings {
+			fmt.Fprintln(stdout, formatArticleListing(listing))
+		}
+		return nil
+	}
+	if len(args) >= 1 && args[0] == "--doctor" {
+		failed := 0
+		checks := app.Doctor()
+		for _, check := range checks {
+			if !check.OK {
+				failed++
+			}
+			if !jsonOutput {
+				fmt.Fprintln(stdout, formatDoctorCheck(check))
+			}
+		}
+		if jsonOutput {
+			_ = writeJSONLines(stdout, checks)
+		}
+		if failed > 0 {
+			return reportError(stderr, msgCLIDoctorFailed, failed)
+		}
+		return nil
+	}
+	if len(args) >= 1 && args[0] == "--stats" {
+		weeks := 4
+		if len(args) >= 2 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed <= 0 {
+				return reportError(stderr, msgCLIStatsError, errors.New(tr(msgCLIInvalidWeeks)))
+			}
+			weeks = parsed
+		}
+		for _, line := range formatUsageStats(app.SummaryUsage(weeks), app.config) {
+			fmt.Fprintln(stdout, line)
+		}
+		return nil
+	}
+	if len(args) >= 1 && (args[0] == "--digest" || args[0] == "--email-digest" || args[0] == "digest") {
+		opts, err := parseDigestArgs(args[1:])
+		if err != nil {
+			return reportError(stderr, msgCLIDigestError, err)
+		}
+		if opts.hours > 0 {
+			app.config.DigestHours = opts.hours
+		}
+		digest, err := app.GenerateDigest()
+		if err != nil {
+			return reportError(stderr, msgCLIDigestError, err)
+		}
+		if args[0] == "--email-digest" || opts.email {
+			if err := app.EmailDigest(digest); err != nil {
+				return reportError(stderr, msgCLIDigestError, err)
+			}
+			fmt.Fprintln(stdout, tr(msgCLIDigestSent))
+		}
+		if opts.path != "" {
+			path := digestFilePath(opts.path, digest)
+			if err := WriteDigest(path, digest); err != nil {
+				return reportError(stderr, msgCLIDigestError, err)
+			}
+			fmt.Fprintln(stdout, tr(msgCLIDigestWritten, path))
+		}
+		if args[0] == "--email-digest" || opts.email || opts.path != "" {
+			return nil
+		}
+		fmt.Fprint(stdout, renderDigestMarkdown(digest))
 		return nil
 	}
-	if len(args) >= 1 && args[0] == "--refresh" {
+	if len(args) >= 1 && args[0] == "--daemon" {
+		err := runDaemon(app, stdout, nil)
+		if shutdownErr := app.Shutdown(shutdownTimeout); err == nil {
+			err = shutdownErr
+		}
+		if err != nil {
+			return reportError(stderr, msgCLIDaemonError, err)
+		}
+		return nil
+	}
+	if len(args) >= 1 && (args[0] == "--refresh" || args[0] == "refresh") {
 		if err := refreshFeeds(app); err != nil {
-			fmt.Fprintln(stderr, "refresh error:", err)
-			return err
+			return reportError(stderr, msgCLIRefreshError, err)
+		}
+		if jsonOutput {
+			if err := writeJSONLines(stdout, app.lastStats.Results); err != nil {
+				return reportError(stderr, msgCLIRefreshError, err)
+			}
+			return nil
+		}
+		fmt.Fprintln(stdout, tr(msgCLIRefreshed, len(app.feeds)))
+		return nil
+	}
+
+	if len(args) >= 1 && (args[0] == "--discover" || args[0] == "discover") {
+		if err := runDiscover(app, args[1:], stdin, stdout); err != nil {
+			return reportError(stderr, msgCLIDiscoverError, err)
+		}
+		return nil
+	}
+
+	if len(args) >= 1 && args[0] == "--sync" {
+		if err := app.SyncAccounts(); err != nil {
+			return reportError(stderr, msgCLISyncError, err)
 		}
-		fmt.Fprintf(stdout, "Refreshed %d feeds\n", len(app.feeds))
+		fmt.Fprintln(stdout, app.status)
 		return nil
 	}
 
-	if !isTerminalReader(stdin) || !isTerminalWriter(stdout) {
+	useTUI := isTerminalReader(stdin) && isTerminalWriter(stdout)
+	if tuiMode != "" {
+		useTUI = tuiMode == "--tui"
+	}
+	if !useTUI {
 		if err := Run(app, stdin, stdout); err != nil {
-			fmt.Fprintln(stderr, "run error:", err)
-			return err
+			return reportError(stderr, msgCLIRunError, err)
 		}
 		return nil
 	}
 
 	if err := runTUI(app); err != nil {
-		fmt.Fprintln(stderr, "run error:", err)
-		return err
+		return reportError(stderr, msgCLIRunError, err)
 	}
 	return nil
 }
 
-func isTerminalReader(stream io.Reader) bool {
-	file, ok := stream.(*os.File)
-	if !ok {
-		return false
+func takeTUIFlag(args []string) ([]string, string) {
+	mode := ""
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "--tui" || arg == "--no-tui" {
+			mode = arg
+			continue
+		}
+		rest = append(rest, arg)
 	}
-	info, err := file.Stat()
-	if err != nil {
+	return rest, mode
+}
+
+func isTerminalFile(file *os.File) bool {
+	if file == nil || file.Fd() == ^uintptr(0) {
 		return false
 	}
-	return info.Mode()&os.ModeCharDevice != 0
+	return isTerminalFd(file.Fd())
+}
+
+func isTerminalReader(stream io.Reader) bool {
+	file, ok := stream.(*os.File)
+	return ok && isTerminalFile(file)
 }
 
 func isTerminalWriter(stream io.Writer) bool {
 	file, ok := stream.(*os.File)
-	if !ok {
-		return false
-	}
-	info, err := file.Stat()
-	if err != nil {
-		return false
-	}
-	return info.Mode()&os.ModeCharDevice != 0
+	return ok && isTerminalFile(file)
 }
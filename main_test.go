@@ -55,6 +55,15 @@ func TestRunMainImportRefreshAndRun(t *testing.T) {
 		t.Fatalf("expected export state output")
 	}
 
+	legacyPath := filepath.Join(root, "legacy.json")
+	stdout.Reset()
+	if err := runMain([]string{"--export-legacy", legacyPath}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain export legacy error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "in legacy format to "+legacyPath) {
+		t.Fatalf("expected export legacy output, got %q", stdout.String())
+	}
+
 	stdout.Reset()
 	if err := runMain([]string{"--import-state", statePath}, strings.NewReader(""), &stdout, &stderr); err != nil {
 		t.Fatalf("runMain import state error: %v", err)
@@ -115,6 +124,11 @@ func TestRunMainStateErrors(t *testing.T) {
 		t.Fatalf("expected export state error output")
 	}
 
+	stderr.Reset()
+	if err := runMain([]string{"--export-legacy", stateDir}, strings.NewReader(""), &stdout, &stderr); err == nil || !strings.Contains(stderr.String(), "export legacy error") {
+		t.Fatalf("expected export legacy error")
+	}
+
 	stdout.Reset()
 	stderr.Reset()
 	missing := filepath.Join(root, "missing.json")
@@ -235,6 +249,25 @@ func TestRunMainRefreshError(t *testing.T) {
 	}
 }
 
+func TestRunMainSyncNotConfigured(t *testing.T) {
+	root := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", root)
+	os.Setenv("XDG_DATA_HOME", root)
+	t.Cleanup(func() {
+		os.Unsetenv("XDG_CONFIG_HOME")
+		os.Unsetenv("XDG_DATA_HOME")
+	})
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if err := runMain([]string{"--sync"}, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Fatalf("expected sync error")
+	}
+	if !strings.Contains(stderr.String(), "sync error: no sync backend configured") {
+		t.Fatalf("unexpected sync error output %q", stderr.String())
+	}
+}
+
 func TestRunMainUsesTUI(t *testing.T) {
 	root := t.TempDir()
 	os.Setenv("XDG_CONFIG_HOME", root)
@@ -252,6 +285,7 @@ func TestRunMainUsesTUI(t *testing.T) {
 	}
 	t.Cleanup(func() { runTUI = orig })
 
+	stubTerminal(t, true)
 	tty, err := os.Open("/dev/null")
 	if err != nil {
 		t.Fatalf("open /dev/null: %v", err)
@@ -266,6 +300,13 @@ func TestRunMainUsesTUI(t *testing.T) {
 	}
 }
 
+func stubTerminal(t *testing.T, terminal bool) {
+	t.Helper()
+	orig := isTerminalFd
+	isTerminalFd = func(uintptr) bool { return terminal }
+	t.Cleanup(func() { isTerminalFd = orig })
+}
+
 func TestIsTerminalHelpers(t *testing.T) {
 	if isTerminalReader(strings.NewReader("x")) {
 		t.Fatalf("expected non-terminal reader")
@@ -279,6 +320,10 @@ func TestIsTerminalHelpers(t *testing.T) {
 		t.Fatalf("open /dev/null: %v", err)
 	}
 	defer tty.Close()
+	if isTerminalReader(tty) || isTerminalWriter(tty) {
+		t.Fatalf("expected /dev/null not to be a terminal")
+	}
+	stubTerminal(t, true)
 	if !isTerminalReader(tty) {
 		t.Fatalf("expected terminal reader")
 	}
@@ -295,6 +340,52 @@ func TestIsTerminalHelpers(t *testing.T) {
 	}
 }
 
+func TestTakeTUIFlag(t *testing.T) {
+	args, mode := takeTUIFlag([]string{"--collection", "Go", "--no-tui"})
+	if mode != "--no-tui" || len(args) != 2 || args[1] != "Go" {
+		t.Fatalf("unexpected %v %q", args, mode)
+	}
+	if _, mode := takeTUIFlag([]string{"--no-tui", "--tui"}); mode != "--tui" {
+		t.Fatalf("expected last flag to win, got %q", mode)
+	}
+	if args, mode := takeTUIFlag(nil); mode != "" || len(args) != 0 {
+		t.Fatalf("unexpected %v %q", args, mode)
+	}
+}
+
+func TestRunMainTUIOverride(t *testing.T) {
+	root := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", root)
+	os.Setenv("XDG_DATA_HOME", root)
+	t.Cleanup(func() {
+		os.Unsetenv("XDG_CONFIG_HOME")
+		os.Unsetenv("XDG_DATA_HOME")
+	})
+
+	orig := runTUI
+	called := false
+	runTUI = func(*App) error {
+		called = true
+		return nil
+	}
+	t.Cleanup(func() { runTUI = orig })
+
+	var stdout, stderr bytes.Buffer
+	if err := runMain([]string{"--tui"}, strings.NewReader(""), &stdout, &stderr); err != nil || !called {
+		t.Fatalf("expected --tui to force the TUI, called=%v err=%v", called, err)
+	}
+	called = false
+	stubTerminal(t, true)
+	tty, err := os.Open("/dev/null")
+	if err != nil {
+		t.Fatalf("open /dev/null: %v", err)
+	}
+	defer tty.Close()
+	if err := runMain([]string{"--no-tui"}, tty, &stdout, &stderr); err != nil || called {
+		t.Fatalf("expected --no-tui to use pipe mode, called=%v err=%v", called, err)
+	}
+}
+
 func TestRunMainNonTTYFallback(t *testing.T) {
 	root := t.TempDir()
 	os.Setenv("XDG_CONFIG_HOME", root)
@@ -324,6 +415,7 @@ func TestRunMainTUIError(t *testing.T) {
 	runTUI = func(*App) error { return errors.New("tui fail") }
 	t.Cleanup(func() { runTUI = orig })
 
+	stubTerminal(t, true)
 	tty, err := os.Open("/dev/null")
 	if err != nil {
 		t.Fatalf("open /dev/null: %v", err)
@@ -363,3 +455,154 @@ func TestRunMainMigrationError(t *testing.T) {
 		t.Fatalf("expected migration error")
 	}
 }
+
+func TestRunMainStats(t *testing.T) {
+	root := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", root)
+	os.Setenv("XDG_DATA_HOME", root)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	defer os.Unsetenv("XDG_DATA_HOME")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if err := runMain([]string{"--stats"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain stats error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No summarizer requests") {
+		t.Fatalf("unexpected stats output: %s", stdout.String())
+	}
+	stdout.Reset()
+	if err := runMain([]string{"--stats", "2"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain stats weeks error: %v", err)
+	}
+	if err := runMain([]string{"--stats", "zero"}, strings.NewReader(""), &stdout, &stderr); errorCode(err) != "cli.stats_error" {
+		t.Fatalf("expected coded invalid weeks error, got %v", err)
+	}
+}
+
+func TestRunMainCollections(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+	t.Setenv("RAINDROP_BASE_URL", "http://example.test")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if err := runMain([]string{"--collections"}, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Fatalf("expected collections error without raindrop token")
+	}
+	if err := runMain([]string{"--collection", "Reading"}, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Fatalf("expected collection error without raindrop token")
+	}
+
+	path := filepath.Join(root, "greeder", "config.toml")
+	if err := os.WriteFile(path, []byte("db_path = \""+filepath.Join(root, "feeds.db")+"\"\nraindrop_token = \"token\"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	oldTransport := http.DefaultTransport
+	http.DefaultTransport = raindropCollectionsClient().Transport
+	t.Cleanup(func() { http.DefaultTransport = oldTransport })
+	stdout.Reset()
+	if err := runMain([]string{"--collections"}, strings.NewReader(""), &stdout, &stderr); err != nil || !strings.Contains(stdout.String(), "10\tReading (3)") {
+		t.Fatalf("unexpected collections output %q %v", stdout.String(), err)
+	}
+	stdout.Reset()
+	if err := runMain([]string{"--collection", "Go", "--stats"}, strings.NewReader(""), &stdout, &stderr); err != nil || !strings.Contains(stdout.String(), "No summarizer requests") {
+		t.Fatalf("expected collection flag to precede other commands: %q %v", stdout.String(), err)
+	}
+}
+
+func TestRunMainExportNotes(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if err := runMain([]string{"--export-notes"}, strings.NewReader(""), &stdout, &stderr); err == nil || !strings.Contains(stderr.String(), "notes_dir not configured") {
+		t.Fatalf("expected notes_dir error, got %v %q", err, stderr.String())
+	}
+	path := filepath.Join(root, "greeder", "config.toml")
+	notes := filepath.Join(root, "notes")
+	if err := os.WriteFile(path, []byte("db_path = \""+filepath.Join(root, "feeds.db")+"\"\nnotes_dir = \""+notes+"\"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := runMain([]string{"--export-notes"}, strings.NewReader(""), &stdout, &stderr); err != nil || stdout.String() != "Wrote 0 notes to "+notes+"\n" {
+		t.Fatalf("unexpected export output %q %v", stdout.String(), err)
+	}
+}
+
+func TestRunMainBibTeXAndZotero(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	path := filepath.Join(root, "starred.bib")
+	if err := runMain([]string{"--export-bibtex", path}, strings.NewReader(""), &stdout, &stderr); err != nil || stdout.String() != "Exported 0 starred articles to "+path+"\n" {
+		t.Fatalf("unexpected bibtex output %q %v", stdout.String(), err)
+	}
+	if err := runMain([]string{"--export-bibtex", filepath.Join(root, "missing", "x.bib")}, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Fatalf("expected bibtex write error")
+	}
+	if err := runMain([]string{"--zotero"}, strings.NewReader(""), &stdout, &stderr); err == nil || !strings.Contains(stderr.String(), "zotero not configured") {
+		t.Fatalf("expected zotero error, got %v %q", err, stderr.String())
+	}
+	cfgPath := filepath.Join(root, "greeder", "config.toml")
+	if err := os.WriteFile(cfgPath, []byte("db_path = \""+filepath.Join(root, "feeds.db")+"\"\nzotero_api_key = \"k\"\nzotero_user_id = \"1\"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	stdout.Reset()
+	if err := runMain([]string{"--zotero"}, strings.NewReader(""), &stdout, &stderr); err != nil || stdout.String() != "Saved 0 starred articles to Zotero\n" {
+		t.Fatalf("unexpected zotero output %q %v", stdout.String(), err)
+	}
+}
+
+func TestRunMainExportEPUB(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if err := runMain([]string{"export-epub", "--starred"}, strings.NewReader(""), &stdout, &stderr); err == nil || !strings.Contains(stderr.String(), "missing epub path") {
+		t.Fatalf("expected missing path error, got %v %q", err, stderr.String())
+	}
+	if err := runMain([]string{"--export-epub", "--unread", filepath.Join(root, "out.epub")}, strings.NewReader(""), &stdout, &stderr); err == nil || !strings.Contains(stderr.String(), "no unread articles") {
+		t.Fatalf("expected empty export error, got %v %q", err, stderr.String())
+	}
+	if err := runMain([]string{"export-pdf", filepath.Join(root, "out.pdf")}, strings.NewReader(""), &stdout, &stderr); errorCode(err) != "cli.pdf_error" || !strings.Contains(stderr.String(), "missing article id") {
+		t.Fatalf("expected missing selection error, got %v %q", err, stderr.String())
+	}
+	if err := runMain([]string{"export-pdf", "--starred", filepath.Join(root, "out.pdf")}, strings.NewReader(""), &stdout, &stderr); err == nil || !strings.Contains(stderr.String(), "no starred articles") {
+		t.Fatalf("expected empty export error, got %v %q", err, stderr.String())
+	}
+}
+
+func TestRunMainCache(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+	cacheRoot := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheRoot)
+	if err := os.MkdirAll(filepath.Join(cacheRoot, "greeder", "http"), 0o755); err != nil {
+		t.Fatalf("mkdir error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheRoot, "greeder", "http", "entry"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	if err := runMain([]string{"cache"}, strings.NewReader(""), &stdout, &stderr); err != nil || !strings.Contains(stdout.String(), "1 files") {
+		t.Fatalf("unexpected cache output %q %v", stdout.String(), err)
+	}
+	if err := runMain([]string{"cache", "clear"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("cache clear error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheRoot, "greeder", "http")); !os.IsNotExist(err) {
+		t.Fatalf("expected cache entries removed, got %v", err)
+	}
+	if err := runMain([]string{"cache", "bogus"}, strings.NewReader(""), &stdout, &stderr); err == nil || !strings.Contains(stderr.String(), "cache error") {
+		t.Fatalf("expected cache error, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	mastodonStatusLimit = 500
+	mastodonURLLength   = 23
+)
+
+type MastodonClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewMastodonClient(baseURL, token string) *MastodonClient {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	token = strings.TrimSpace(token)
+	if baseURL == "" || token == "" {
+		return nil
+	}
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+	return &MastodonClient{
+		baseURL: baseURL,
+		token:   token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func mastodonHashtag(tag string) string {
+	var b strings.Builder
+	for _, field := range strings.FieldsFunc(tag, func(r rune) bool { return r == ' ' || r == '-' || r == '#' }) {
+		b.WriteString(field)
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "#" + b.String()
+}
+
+func composeMastodonStatus(title, link, summary string, tags []string) string {
+	hashtags := []string{}
+	for _, tag := range tags {
+		if hashtag := mastodonHashtag(strings.TrimSpace(tag)); hashtag != "" {
+			hashtags = append(hashtags, hashtag)
+		}
+	}
+	footer := link
+	if len(hashtags) > 0 {
+		footer += "\n\n" + strings.Join(hashtags, " ")
+	}
+	footerLength := len([]rune(footer)) - len([]rune(link)) + mastodonURLLength
+	title = strings.TrimSpace(title)
+	budget := mastodonStatusLimit - footerLength - 2
+	if titleRunes := []rune(title); len(titleRunes) > budget {
+		title = string(titleRunes[:max(budget-1, 0)]) + "…"
+	}
+	parts := []string{title}
+	budget -= len([]rune(title)) + 2
+	summary = strings.Join(strings.Fields(summary), " ")
+	if summary != "" && budget > 20 {
+		if summaryRunes := []rune(summary); len(summaryRunes) > budget {
+			summary = strings.TrimSpace(string(summaryRunes[:budget-1])) + "…"
+		}
+		parts = append(parts, summary)
+	}
+	parts = append(parts, footer)
+	return strings.Join(parts, "\n\n")
+}
+
+func (m *MastodonClient) Post(status string) (string, error) {
+	if m == nil {
+		return "", errors.New("mastodon not configured")
+	}
+	blob, err := servicesJSONMarshal(map[string]string{"status": status})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, m.baseURL+"/api/v1/statuses", bytes.NewReader(blob))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var parsed struct {
+		URL   string `json:"url"`
+		Error string `json:"error"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&parsed)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if parsed.Error != "" {
+			return "", errors.New("mastodon: " + parsed.Error)
+		}
+		return "", fmt.Errorf("mastodon http %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return "", decodeErr
+	}
+	return parsed.URL, nil
+}
+
+func (a *App) ShareToMastodon(tags []string) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	if a.mastodon == nil {
+		return errors.New("mastodon not configured")
+	}
+	summary := ""
+	if a.config.MastodonIncludeSummary && a.current.ArticleID == article.ID {
+		summary = a.current.Content
+	}
+	if _, err := a.mastodon.Post(composeMastodonStatus(article.Title, article.URL, summary, tags)); err != nil {
+		return err
+	}
+	a.status = "Posted to Mastodon"
+	return nil
+}
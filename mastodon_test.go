@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestComposeMastodonStatus(t *testing.T) {
+	status := composeMastodonStatus(" Go 1.24 ", "https://example.com/a", "- faster\n- smaller", []string{"go", "machine learning", " ", "#rust"})
+	if status != "Go 1.24\n\n- faster - smaller\n\nhttps://example.com/a\n\n#go #machinelearning #rust" {
+		t.Fatalf("unexpected status %q", status)
+	}
+	if status := composeMastodonStatus("Title", "https://example.com/a", "", nil); status != "Title\n\nhttps://example.com/a" {
+		t.Fatalf("unexpected bare status %q", status)
+	}
+	long := composeMastodonStatus("Title", "https://example.com/"+strings.Repeat("x", 200), strings.Repeat("word ", 200), []string{"go"})
+	counted := len([]rune(long)) - len([]rune("https://example.com/"+strings.Repeat("x", 200))) + mastodonURLLength
+	if counted > mastodonStatusLimit || !strings.Contains(long, "…") || !strings.HasSuffix(long, "#go") {
+		t.Fatalf("expected trimmed summary within limit, got %d: %q", counted, long)
+	}
+	longTitle := composeMastodonStatus(strings.Repeat("t", 600), "https://example.com/a", "summary", nil)
+	if len([]rune(longTitle))-len("https://example.com/a")+mastodonURLLength > mastodonStatusLimit || strings.Contains(longTitle, "summary") {
+		t.Fatalf("expected truncated title without summary, got %d", len([]rune(longTitle)))
+	}
+}
+
+func TestMastodonClient(t *testing.T) {
+	if NewMastodonClient("", "t") != nil || NewMastodonClient("mastodon.social", " ") != nil {
+		t.Fatalf("expected nil client without url or token")
+	}
+	if client := NewMastodonClient("mastodon.social/", "t"); client.baseURL != "https://mastodon.social" {
+		t.Fatalf("unexpected base url %q", client.baseURL)
+	}
+	client := NewMastodonClient("http://mastodon.test", "tok")
+	posted := ""
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/api/v1/statuses" || r.Header.Get("Authorization") != "Bearer tok" {
+			return newResponse(http.StatusUnauthorized, `{"error":"The access token is invalid"}`, nil, r), nil
+		}
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]string
+		_ = json.Unmarshal(body, &payload)
+		posted = payload["status"]
+		return newResponse(http.StatusOK, `{"id":"1","url":"http://mastodon.test/@me/1"}`, nil, r), nil
+	})}
+	if link, err := client.Post("hello"); err != nil || link != "http://mastodon.test/@me/1" || posted != "hello" {
+		t.Fatalf("unexpected post %q %v %q", link, err, posted)
+	}
+	client.token = "bad"
+	if _, err := client.Post("hello"); err == nil || err.Error() != "mastodon: The access token is invalid" {
+		t.Fatalf("expected api error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusBadGateway, "", nil)
+	if _, err := client.Post("hello"); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusOK, "nope", nil)
+	if _, err := client.Post("hello"); err == nil {
+		t.Fatalf("expected decode error")
+	}
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if _, err := client.Post("hello"); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	var nilClient *MastodonClient
+	if _, err := nilClient.Post("hello"); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+}
+
+func TestAppShareToMastodon(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	if err := app.SaveBookmarkTo("mastodon", nil); err == nil || err.Error() != "mastodon not configured" {
+		t.Fatalf("expected not configured error, got %v", err)
+	}
+	posted := ""
+	app.mastodon = &MastodonClient{baseURL: "http://mastodon.test", token: "t", client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]string
+		_ = json.Unmarshal(body, &payload)
+		posted = payload["status"]
+		return newResponse(http.StatusOK, `{"url":"u"}`, nil, r), nil
+	})}}
+	app.current = Summary{ArticleID: articles[0].ID, Content: "- point"}
+	if targets := app.ShareTargets(); len(targets) != 1 || targets[0] != "mastodon" {
+		t.Fatalf("unexpected targets %v", targets)
+	}
+	if err := handleCommand(app, "share mastodon go", io.Discard); err != nil || app.status != "Posted to Mastodon" || posted != "Post\n\nhttps://example.com/1\n\n#go" {
+		t.Fatalf("unexpected share %v %q %q", err, app.status, posted)
+	}
+	if app.store.SavedCount() != 0 {
+		t.Fatalf("expected mastodon posts not recorded as bookmarks")
+	}
+	app.config.MastodonIncludeSummary = true
+	if err := app.ShareToMastodon(nil); err != nil || posted != "Post\n\n- point\n\nhttps://example.com/1" {
+		t.Fatalf("expected summary in status, got %q", posted)
+	}
+	app.mastodon.client = clientForResponse(http.StatusInternalServerError, "", nil)
+	if err := app.ShareToMastodon(nil); err == nil {
+		t.Fatalf("expected post error")
+	}
+	app.articles = nil
+	if err := app.ShareToMastodon(nil); err != nil {
+		t.Fatalf("expected no-op without selection, got %v", err)
+	}
+}
@@ -0,0 +1,596 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+type messageID string
+
+const (
+	msgFeedsLoaded             messageID = "feeds.loaded"
+	msgDBDamaged               messageID = "db.damaged"
+	msgSecretMissingName       messageID = "secret.missing_name"
+	msgSecretsUnsupported      messageID = "secret.unsupported"
+	msgSecretPrompt            messageID = "secret.prompt"
+	msgSecretEmpty             messageID = "secret.empty"
+	msgSecretStored            messageID = "secret.stored"
+	msgSecretPlaintextRemoved  messageID = "secret.plaintext_removed"
+	msgDBIntegrityOK           messageID = "db.integrity_ok"
+	msgDBIntegrityFailed       messageID = "db.integrity_failed"
+	msgDBMaintenanceSkipped    messageID = "db.maintenance_skipped"
+	msgDBMaintenanceSize       messageID = "db.maintenance_size"
+	msgFeedAdded               messageID = "feed.added"
+	msgFeedNotFound            messageID = "feed.not_found"
+	msgFeedSynced              messageID = "feed.synced"
+	msgFeedTitleEmpty          messageID = "feed.title_empty"
+	msgFeedRenamed             messageID = "feed.renamed"
+	msgFeedURLChanged          messageID = "feed.url_changed"
+	msgFeedRemoved             messageID = "feed.removed"
+	msgFeedConfirmRemove       messageID = "feed.confirm_remove"
+	msgTagSet                  messageID = "tag.set"
+	msgTagCleared              messageID = "tag.cleared"
+	msgQueueAdded              messageID = "queue.added"
+	msgQueueRemoved            messageID = "queue.removed"
+	msgQueueMoved              messageID = "queue.moved"
+	msgQueueNotQueued          messageID = "queue.not_queued"
+	msgQueueView               messageID = "queue.view"
+	msgQueueViewClosed         messageID = "queue.view_closed"
+	msgQueueEmpty              messageID = "queue.empty"
+	msgTagNone                 messageID = "tag.none"
+	msgBulkMarkedRead          messageID = "bulk.marked_read"
+	msgSelectionCount          messageID = "selection.count"
+	msgMutedSet                messageID = "muted.set"
+	msgMutedCleared            messageID = "muted.cleared"
+	msgMutedShown              messageID = "muted.shown"
+	msgMutedHidden             messageID = "muted.hidden"
+	msgActionMute              messageID = "action.mute"
+	msgSelectionDeleted        messageID = "selection.deleted"
+	msgSelectionSaved          messageID = "selection.saved"
+	msgSelectionSaveFailed     messageID = "selection.save_failed"
+	msgSelectionTagged         messageID = "selection.tagged"
+	msgBulkStarred             messageID = "bulk.starred"
+	msgBulkEmptyQuery          messageID = "bulk.empty_query"
+	msgBulkDeletedRead         messageID = "bulk.deleted_read"
+	msgFeedRefreshed           messageID = "feed.refreshed"
+	msgFeedRefreshFailed       messageID = "feed.refresh_failed"
+	msgFolderSet               messageID = "folder.set"
+	msgFolderCleared           messageID = "folder.cleared"
+	msgScopeAll                messageID = "scope.all"
+	msgScopeSet                messageID = "scope.set"
+	msgScopeCleared            messageID = "scope.cleared"
+	msgFeedAddFailed           messageID = "feed.add_failed"
+	msgFeedChoices             messageID = "feed.choices"
+	msgConfigReloadFailed      messageID = "config.reload_failed"
+	msgRefreshNoFeeds          messageID = "refresh.no_feeds"
+	msgRefreshRunning          messageID = "refresh.running"
+	msgRefreshDone             messageID = "refresh.done"
+	msgRefreshDoneFailed       messageID = "refresh.done_failed"
+	msgRefreshBackedOff        messageID = "refresh.backed_off"
+	msgRefreshNewsletters      messageID = "refresh.newsletters"
+	msgRefreshNewslettersError messageID = "refresh.newsletters_failed"
+	msgRefreshEmbeddingsError  messageID = "refresh.embeddings_failed"
+	msgSyncFailed              messageID = "sync.failed"
+	msgSyncNotConfigured       messageID = "sync.not_configured"
+	msgSyncRunning             messageID = "sync.running"
+	msgTopicsFailed            messageID = "topics.failed"
+	msgTopicsSaveFailed        messageID = "topics.save_failed"
+	msgRelevanceFailed         messageID = "relevance.failed"
+	msgScoreSaveFailed         messageID = "relevance.save_failed"
+	msgArticleDeleted          messageID = "article.deleted"
+	msgArticleRestored         messageID = "article.restored"
+	msgArticlesRestored        messageID = "article.restored_many"
+	msgUndeleteNothing         messageID = "undelete.nothing"
+	msgUndeleteNoneRecent      messageID = "undelete.none_recent"
+	msgUndeleteRestored        messageID = "undelete.restored"
+	msgUndeleteFailed          messageID = "undelete.failed"
+	msgTrashEmpty              messageID = "trash.empty"
+	msgTrashNotFound           messageID = "trash.not_found"
+	msgTrashRestored           messageID = "trash.restored"
+	msgTrashPurged             messageID = "trash.purged"
+	msgTrashConfirmPurge       messageID = "trash.confirm_purge"
+	msgTrashConfirmPurgeAll    messageID = "trash.confirm_purge_all"
+	msgStarredNoneToOpen       messageID = "starred.none_to_open"
+	msgStarredOpened           messageID = "starred.opened"
+	msgSavedRaindrop           messageID = "save.raindrop"
+	msgSavedPocket             messageID = "save.pocket"
+	msgPluginSent              messageID = "plugin.sent"
+	msgSavedPinboard           messageID = "save.pinboard"
+	msgSavedOmnivore           messageID = "save.omnivore"
+	msgSavedArchive            messageID = "save.archive"
+	msgSaveNoTargets           messageID = "save.no_targets"
+	msgSaveUnknownTarget       messageID = "save.unknown_target"
+	msgBookmarkFailed          messageID = "bookmark.failed"
+	msgSavedNone               messageID = "saved.none"
+	msgSavedNotFound           messageID = "saved.not_found"
+	msgSavedResynced           messageID = "saved.resynced"
+	msgSavedResyncUnsupported  messageID = "saved.resync_unsupported"
+	msgCollectionDefault       messageID = "collection.default"
+	msgCollectionSelected      messageID = "collection.selected"
+	msgCollectionFailed        messageID = "collection.failed"
+	msgCollectionsList         messageID = "collections.list"
+	msgCollectionsUnavailable  messageID = "collections.unavailable"
+	msgCollectionsLoading      messageID = "collections.loading"
+	msgURLCopied               messageID = "clipboard.url_copied"
+	msgSummaryCopied           messageID = "clipboard.summary_copied"
+	msgMarkdownCopied          messageID = "clipboard.markdown_copied"
+	msgContentCopied           messageID = "clipboard.content_copied"
+	msgCopyNoSummary           messageID = "clipboard.no_summary"
+	msgCopyNoContent           messageID = "clipboard.no_content"
+	msgCopyFailed              messageID = "clipboard.failed"
+	msgSummarizerMissing       messageID = "summary.not_configured"
+	msgSummarySaveFailed       messageID = "summary.save_failed"
+	msgSummaryNoneMissing      messageID = "summary.none_missing"
+	msgSummaryGenerating       messageID = "summary.generating"
+	msgSummaryNoVersions       messageID = "summary.no_versions"
+	msgSummaryRegenerating     messageID = "summary.regenerating"
+	msgBatchFailed             messageID = "batch.failed"
+	msgBatchComplete           messageID = "batch.complete"
+	msgBatchRunning            messageID = "batch.running"
+	msgBatchRunningFailed      messageID = "batch.running_failed"
+	msgBatchDone               messageID = "batch.done"
+	msgBatchDoneFailed         messageID = "batch.done_failed"
+	msgDigestGenerating        messageID = "digest.generating"
+	msgDigestGenerated         messageID = "digest.generated"
+	msgDigestNoUnread          messageID = "digest.no_unread"
+	msgDigestEmailFailed       messageID = "digest.email_failed"
+	msgDigestNoneStored        messageID = "digest.none_stored"
+	msgEmailSending            messageID = "email.sending"
+	msgEmailSent               messageID = "email.sent"
+	msgEmailFailed             messageID = "email.failed"
+	msgExportWritten           messageID = "export.written"
+	msgExportFailed            messageID = "export.failed"
+	msgVaultAdded              messageID = "vault.added"
+	msgVaultPresent            messageID = "vault.present"
+	msgVaultFailed             messageID = "vault.failed"
+	msgDigestSaveFailed        messageID = "digest.save_failed"
+	msgStateExported           messageID = "state.exported"
+	msgStateImported           messageID = "state.imported"
+	msgStateExportFailed       messageID = "state.export_failed"
+	msgStateImportFailed       messageID = "state.import_failed"
+	msgOPMLImportFailed        messageID = "opml.import_failed"
+	msgOPMLExportFailed        messageID = "opml.export_failed"
+	msgOPMLImported            messageID = "opml.imported"
+	msgOPMLReportSummary       messageID = "opml.report_summary"
+	msgOPMLInvalidURL          messageID = "opml.invalid_url"
+	msgOPMLInvalidScheme       messageID = "opml.invalid_scheme"
+	msgOPMLInvalidHost         messageID = "opml.invalid_host"
+	msgRSSBridgeNotAdded       messageID = "rssbridge.not_added"
+	msgInputCancelled          messageID = "input.cancelled"
+	msgInputInvalidDays        messageID = "input.invalid_days"
+	msgStatusReady             messageID = "status.ready"
+	msgSpeakingSummary         messageID = "speech.summary"
+	msgSpeakingArticle         messageID = "speech.article"
+	msgSpeechStopped           messageID = "speech.stopped"
+	msgSpeechFailed            messageID = "speech.failed"
+	msgCancelling              messageID = "request.cancelling"
+	msgShutdownWaiting         messageID = "shutdown.waiting"
+	msgActionFailed            messageID = "action.failed"
+	msgActionCancelled         messageID = "action.cancelled"
+	msgActionSummary           messageID = "action.summary"
+	msgActionRelevance         messageID = "action.relevance"
+	msgActionTopics            messageID = "action.topics"
+	msgActionRefresh           messageID = "action.refresh"
+	msgActionSync              messageID = "action.sync"
+	msgActionQuestion          messageID = "action.question"
+	msgActionDigest            messageID = "action.digest"
+	msgActionRemoveFeed        messageID = "action.remove_feed"
+	msgActionRenameFeed        messageID = "action.rename_feed"
+	msgActionChangeFeedURL     messageID = "action.change_feed_url"
+	msgActionMarkAllRead       messageID = "action.mark_all_read"
+	msgActionMarkRead          messageID = "action.mark_read"
+	msgActionDelete            messageID = "action.delete"
+	msgActionQueue             messageID = "action.queue"
+	msgActionStarMatching      messageID = "action.star_matching"
+	msgActionTag               messageID = "action.tag"
+	msgActionResyncTags        messageID = "action.resync_tags"
+	msgActionRestore           messageID = "action.restore"
+	msgActionPurge             messageID = "action.purge"
+	msgActionDeleteRead        messageID = "action.delete_read"
+	msgCLIMigrationError       messageID = "cli.migration_error"
+	msgCLIConfigError          messageID = "cli.config_error"
+	msgCLILogError             messageID = "cli.log_error"
+	msgCLICacheError           messageID = "cli.cache_error"
+	msgCLIPocketLoginError     messageID = "cli.pocket_login_error"
+	msgCLIInitError            messageID = "cli.init_error"
+	msgCLICollectionError      messageID = "cli.collection_error"
+	msgCLICollectionsError     messageID = "cli.collections_error"
+	msgCLIImportError          messageID = "cli.import_error"
+	msgCLIImportStateError     messageID = "cli.import_state_error"
+	msgCLIExportStateError     messageID = "cli.export_state_error"
+	msgCLIExportLegacyError    messageID = "cli.export_legacy_error"
+	msgCLINotesError           messageID = "cli.notes_error"
+	msgCLIBibTeXError          messageID = "cli.bibtex_error"
+	msgCLIEPUBError            messageID = "cli.epub_error"
+	msgCLIPDFError             messageID = "cli.pdf_error"
+	msgCLIZoteroError          messageID = "cli.zotero_error"
+	msgCLIDoctorFailed         messageID = "cli.doctor_failed"
+	msgCLIStatsError           messageID = "cli.stats_error"
+	msgCLIDBMaintenanceError   messageID = "cli.db_maintenance_error"
+	msgCLISecretError          messageID = "cli.secret_error"
+	msgCLIInvalidWeeks         messageID = "cli.invalid_weeks"
+	msgCLIDigestError          messageID = "cli.digest_error"
+	msgCLIDaemonError          messageID = "cli.daemon_error"
+	msgCLIRefreshError         messageID = "cli.refresh_error"
+	msgCLIDiscoverError        messageID = "cli.discover_error"
+	msgCLIListError            messageID = "cli.list_error"
+	msgCLISyncError            messageID = "cli.sync_error"
+	msgCLIRunError             messageID = "cli.run_error"
+	msgCLIPocketConnected      messageID = "cli.pocket_connected"
+	msgCLIPocketAuthorize      messageID = "cli.pocket_authorize"
+	msgCLIPocketWaiting        messageID = "cli.pocket_waiting"
+	msgCLIImportedFeeds        messageID = "cli.imported_feeds"
+	msgCLIImportedState        messageID = "cli.imported_state"
+	msgCLIExportedState        messageID = "cli.exported_state"
+	msgCLIExportedLegacy       messageID = "cli.exported_legacy"
+	msgCLINotesWritten         messageID = "cli.notes_written"
+	msgCLIBibTeXExported       messageID = "cli.bibtex_exported"
+	msgCLIZoteroSaved          messageID = "cli.zotero_saved"
+	msgCLIDigestSent           messageID = "cli.digest_sent"
+	msgCLIDigestWritten        messageID = "cli.digest_written"
+	msgCLIRefreshed            messageID = "cli.refreshed"
+	msgCLIDiscoverUsage        messageID = "cli.discover_usage"
+	msgCLICacheDisabled        messageID = "cli.cache_disabled"
+	msgCLICacheCleared         messageID = "cli.cache_cleared"
+	msgCLIDiscoverSiteError    messageID = "cli.discover_site_error"
+	msgCLIDiscoverKnown        messageID = "cli.discover_subscribed_already"
+	msgCLIDiscoverNone         messageID = "cli.discover_none"
+	msgCLIDiscoverPrompt       messageID = "cli.discover_prompt"
+	msgCLIDiscoverSubscribed   messageID = "cli.discover_subscribed"
+	msgCLIDiscoverSubFailed    messageID = "cli.discover_subscribe_failed"
+	msgCLIDiscoverFailed       messageID = "cli.discover_failed"
+)
+
+const defaultUILanguage = "en"
+
+var messageCatalogs = map[string]map[messageID]string{
+	"en": {
+		msgFeedsLoaded:             "%d feeds loaded",
+		msgDBDamaged:               "Database integrity check found %d problems; run greeder --db-maintenance",
+		msgSecretMissingName:       "usage: greeder --set-secret NAME",
+		msgSecretsUnsupported:      "no system keychain supported on %s",
+		msgSecretPrompt:            "Secret for %s: ",
+		msgSecretEmpty:             "empty secret",
+		msgSecretStored:            "Stored %s in %s; keyring lookups enabled",
+		msgSecretPlaintextRemoved:  "Removed plaintext %s from config",
+		msgDBIntegrityOK:           "Integrity check: ok",
+		msgDBIntegrityFailed:       "Integrity check found %d problems",
+		msgDBMaintenanceSkipped:    "Skipped VACUUM and ANALYZE; restore from a backup or --export-state before repairing",
+		msgDBMaintenanceSize:       "Size: %s -> %s (%s reclaimed)",
+		msgFeedAdded:               "feed added",
+		msgFeedNotFound:            "feed %d not found",
+		msgFeedSynced:              "%s is managed by %s sync",
+		msgFeedTitleEmpty:          "feed title is empty",
+		msgFeedRenamed:             "Renamed feed to %s",
+		msgFeedURLChanged:          "Updated URL for %s",
+		msgFeedRemoved:             "Removed %s and its articles",
+		msgFeedConfirmRemove:       "Press d again to remove %s",
+		msgTagSet:                  "Tagged: %s",
+		msgTagCleared:              "Tags cleared",
+		msgQueueAdded:              "Queued at position %d",
+		msgQueueRemoved:            "Removed from queue",
+		msgQueueMoved:              "Moved to queue position %d",
+		msgQueueNotQueued:          "Article is not queued (Q adds it)",
+		msgQueueView:               "Reading queue: %d articles",
+		msgQueueViewClosed:         "Left reading queue",
+		msgQueueEmpty:              "Reading queue is empty (Q queues the selected article)",
+		msgTagNone:                 "No tags yet.",
+		msgBulkMarkedRead:          "Marked %d articles read",
+		msgSelectionCount:          "%d selected · space toggles, v/esc ends",
+		msgMutedSet:                "Muted: %s (%d articles hidden)",
+		msgMutedCleared:            "Muted words cleared",
+		msgMutedShown:              "Showing %d muted articles",
+		msgMutedHidden:             "Hiding %d muted articles",
+		msgActionMute:              "Mute words",
+		msgSelectionDeleted:        "Deleted %d articles",
+		msgSelectionSaved:          "Saved %d articles",
+		msgSelectionSaveFailed:     "Saved %d of %d articles: %v",
+		msgSelectionTagged:         "Tagged %d articles",
+		msgBulkStarred:             "Starred %d articles matching %q",
+		msgBulkEmptyQuery:          "nothing to match",
+		msgBulkDeletedRead:         "Deleted %d read articles from %s",
+		msgFeedRefreshed:           "Refreshed %s; %d new articles",
+		msgFeedRefreshFailed:       "Refresh of %s failed: %s",
+		msgFolderSet:               "Moved %s to %s",
+		msgFolderCleared:           "Removed %s from its folder",
+		msgScopeAll:                "All feeds",
+		msgScopeSet:                "Showing %s (%d articles)",
+		msgScopeCleared:            "Showing all feeds",
+		msgFeedAddFailed:           "Add feed failed: %v",
+		msgFeedChoices:             "Found %d feeds; pick one to add",
+		msgConfigReloadFailed:      "config reload failed: %v",
+		msgRefreshNoFeeds:          "no feeds to refresh",
+		msgRefreshRunning:          "Refreshing feeds...",
+		msgRefreshDone:             "refreshed %d feeds",
+		msgRefreshDoneFailed:       "refreshed %d feeds (%d failed)",
+		msgRefreshBackedOff:        "%d failing feeds backed off",
+		msgRefreshNewsletters:      "%d new newsletters",
+		msgRefreshNewslettersError: "newsletters failed: %v",
+		msgRefreshEmbeddingsError:  "embeddings failed: %v",
+		msgSyncFailed:              "sync failed: %v",
+		msgSyncNotConfigured:       "no sync backend configured",
+		msgSyncRunning:             "Syncing...",
+		msgTopicsFailed:            "Topic extraction failed: %v",
+		msgTopicsSaveFailed:        "Topic save failed: %v",
+		msgRelevanceFailed:         "Relevance scoring failed: %v",
+		msgScoreSaveFailed:         "Score save failed: %v",
+		msgArticleDeleted:          "article deleted",
+		msgArticleRestored:         "article restored",
+		msgArticlesRestored:        "%d articles restored",
+		msgUndeleteNothing:         "nothing to undelete",
+		msgUndeleteNoneRecent:      "no deleted articles to restore",
+		msgUndeleteRestored:        "restored %d deleted articles from last %d days",
+		msgUndeleteFailed:          "undelete failed: %v",
+		msgTrashEmpty:              "Trash is empty.",
+		msgTrashNotFound:           "no deleted article %d",
+		msgTrashRestored:           "Restored %s",
+		msgTrashPurged:             "Purged %d deleted articles",
+		msgTrashConfirmPurge:       "Press d again to purge %s for good",
+		msgTrashConfirmPurgeAll:    "Press D again to purge all %d deleted articles for good",
+		msgStarredNoneToOpen:       "no starred articles to open",
+		msgStarredOpened:           "opened %d starred articles",
+		msgSavedRaindrop:           "Saved to Raindrop collection %s",
+		msgSavedPocket:             "Saved to Pocket",
+		msgPluginSent:              "Sent to %s",
+		msgSavedPinboard:           "Saved to Pinboard",
+		msgSavedOmnivore:           "Saved to Omnivore",
+		msgSavedArchive:            "Archived to %s",
+		msgSaveNoTargets:           "No save targets configured",
+		msgSaveUnknownTarget:       "Unknown save target: %s",
+		msgBookmarkFailed:          "Bookmark failed: %v",
+		msgSavedNone:               "No saved articles.",
+		msgSavedNotFound:           "article %d is not saved",
+		msgSavedResynced:           "Synced %d tags to %s",
+		msgSavedResyncUnsupported:  "%s does not support tag updates",
+		msgCollectionDefault:       "Raindrop collection: default",
+		msgCollectionSelected:      "Raindrop collection: %s",
+		msgCollectionFailed:        "Collection failed: %v",
+		msgCollectionsList:         "Collections: %s",
+		msgCollectionsUnavailable:  "Collections unavailable: %v",
+		msgCollectionsLoading:      "Loading Raindrop collections...",
+		msgURLCopied:               "URL copied to clipboard",
+		msgSummaryCopied:           "Summary copied to clipboard",
+		msgMarkdownCopied:          "Markdown link copied to clipboard",
+		msgContentCopied:           "Article text copied to clipboard",
+		msgCopyNoSummary:           "No summary to copy",
+		msgCopyNoContent:           "No article text to copy",
+		msgCopyFailed:              "Copy failed: %v",
+		msgSummarizerMissing:       "Summarizer not configured",
+		msgSummarySaveFailed:       "Summary save failed: %v",
+		msgSummaryNoneMissing:      "No missing summaries",
+		msgSummaryGenerating:       "Generating %d summaries...",
+		msgSummaryNoVersions:       "No earlier summary versions",
+		msgSummaryRegenerating:     "Regenerating summary with %s",
+		msgBatchFailed:             "Batch summary failed: %v",
+		msgBatchComplete:           "Batch summaries complete",
+		msgBatchRunning:            "Summarizing %d/%d (%d running)",
+		msgBatchRunningFailed:      "Summarizing %d/%d (%d running, %d failed)",
+		msgBatchDone:               "Batch summaries complete: %d/%d",
+		msgBatchDoneFailed:         "Batch summaries complete: %d/%d (%d failed)",
+		msgDigestGenerating:        "Generating digest from %d articles...",
+		msgDigestGenerated:         "Digest generated from %d articles",
+		msgDigestNoUnread:          "No unread articles for today's digest",
+		msgDigestEmailFailed:       "Digest email failed: %v",
+		msgDigestNoneStored:        "No saved digests yet",
+		msgEmailSending:            "Sending email to %s...",
+		msgEmailSent:               "Email sent to %s",
+		msgEmailFailed:             "Email failed: %v",
+		msgExportWritten:           "Exported: %s",
+		msgExportFailed:            "Export failed: %v",
+		msgVaultAdded:              "Added to %s",
+		msgVaultPresent:            "Already in %s",
+		msgVaultFailed:             "Vault note failed: %v",
+		msgDigestSaveFailed:        "Digest save failed: %v",
+		msgStateExported:           "State exported",
+		msgStateImported:           "State imported",
+		msgStateExportFailed:       "State export failed: %v",
+		msgStateImportFailed:       "State import failed: %v",
+		msgOPMLImportFailed:        "Import failed: %v",
+		msgOPMLExportFailed:        "Export failed: %v",
+		msgOPMLImported:            "Imported %d feeds (%d duplicate, %d invalid)",
+		msgOPMLReportSummary:       "%d new, %d duplicate, %d invalid",
+		msgOPMLInvalidURL:          "not a valid URL",
+		msgOPMLInvalidScheme:       "not an http or https URL",
+		msgOPMLInvalidHost:         "missing host",
+		msgRSSBridgeNotAdded:       "RSS-Bridge feed not added",
+		msgInputCancelled:          "Input cancelled",
+		msgInputInvalidDays:        "Invalid days value",
+		msgStatusReady:             "Ready",
+		msgSpeakingSummary:         "Reading summary: %s (p to stop)",
+		msgSpeakingArticle:         "Reading article: %s (p to stop)",
+		msgSpeechStopped:           "Stopped reading",
+		msgSpeechFailed:            "Text-to-speech failed: %v",
+		msgCancelling:              "Cancelling...",
+		msgShutdownWaiting:         "Finishing %d summaries before quitting (q again to quit now)",
+		msgActionFailed:            "%s failed: %v",
+		msgActionCancelled:         "%s cancelled",
+		msgActionSummary:           "Summary",
+		msgActionRelevance:         "Relevance scoring",
+		msgActionTopics:            "Topic extraction",
+		msgActionRefresh:           "Refresh",
+		msgActionSync:              "Sync",
+		msgActionQuestion:          "Question",
+		msgActionDigest:            "Digest",
+		msgActionRemoveFeed:        "Remove feed",
+		msgActionRenameFeed:        "Rename",
+		msgActionChangeFeedURL:     "URL change",
+		msgActionMarkAllRead:       "Mark all read",
+		msgActionMarkRead:          "Mark read",
+		msgActionDelete:            "Delete",
+		msgActionQueue:             "Queue",
+		msgActionStarMatching:      "Star matching",
+		msgActionTag:               "Tagging",
+		msgActionResyncTags:        "Tag sync",
+		msgActionRestore:           "Restore",
+		msgActionPurge:             "Purge",
+		msgActionDeleteRead:        "Deleting read articles",
+		msgCLIMigrationError:       "migration error: %v",
+		msgCLIConfigError:          "config error: %v",
+		msgCLILogError:             "log error: %v",
+		msgCLICacheError:           "cache error: %v",
+		msgCLIPocketLoginError:     "pocket login error: %v",
+		msgCLIInitError:            "init error: %v",
+		msgCLICollectionError:      "collection error: %v",
+		msgCLICollectionsError:     "collections error: %v",
+		msgCLIImportError:          "import error: %v",
+		msgCLIImportStateError:     "import state error: %v",
+		msgCLIExportStateError:     "export state error: %v",
+		msgCLIExportLegacyError:    "export legacy error: %v",
+		msgCLINotesError:           "notes error: %v",
+		msgCLIBibTeXError:          "bibtex error: %v",
+		msgCLIEPUBError:            "epub error: %v",
+		msgCLIPDFError:             "pdf error: %v",
+		msgCLIZoteroError:          "zotero error: %v",
+		msgCLIDoctorFailed:         "%d checks failed",
+		msgCLIStatsError:           "stats error: %v",
+		msgCLIDBMaintenanceError:   "db maintenance error: %v",
+		msgCLISecretError:          "secret error: %v",
+		msgCLIInvalidWeeks:         "invalid weeks value",
+		msgCLIDigestError:          "digest error: %v",
+		msgCLIDaemonError:          "daemon error: %v",
+		msgCLIRefreshError:         "refresh error: %v",
+		msgCLIDiscoverError:        "discover error: %v",
+		msgCLIListError:            "list error: %v",
+		msgCLISyncError:            "sync error: %v",
+		msgCLIRunError:             "run error: %v",
+		msgCLIPocketConnected:      "Pocket connected; access token saved to config",
+		msgCLIPocketAuthorize:      "Open this URL to authorize greeder with Pocket:",
+		msgCLIPocketWaiting:        "Waiting for approval...",
+		msgCLIImportedFeeds:        "Imported feeds from %s",
+		msgCLIImportedState:        "Imported state from %s",
+		msgCLIExportedState:        "Exported state to %s",
+		msgCLIExportedLegacy:       "%s to %s",
+		msgCLINotesWritten:         "Wrote %d notes to %s",
+		msgCLIBibTeXExported:       "Exported %d starred articles to %s",
+		msgCLIZoteroSaved:          "Saved %d starred articles to Zotero",
+		msgCLIDigestSent:           "Digest sent to mail client",
+		msgCLIDigestWritten:        "Wrote digest to %s",
+		msgCLIRefreshed:            "Refreshed %d feeds",
+		msgCLIDiscoverUsage:        "usage: greeder discover [--all] [--file PATH] URL...",
+		msgCLICacheDisabled:        "cache disabled",
+		msgCLICacheCleared:         "cache cleared: %s",
+		msgCLIDiscoverSiteError:    "no feeds: %v",
+		msgCLIDiscoverKnown:        "already subscribed: %s",
+		msgCLIDiscoverNone:         "No new feeds found",
+		msgCLIDiscoverPrompt:       "Subscribe to which feeds? (numbers, all, or blank for none): ",
+		msgCLIDiscoverSubscribed:   "Subscribed: %s",
+		msgCLIDiscoverSubFailed:    "Failed: %s: %v",
+		msgCLIDiscoverFailed:       "%d of %d feeds could not be subscribed",
+	},
+}
+
+var activeMessages atomic.Pointer[map[messageID]string]
+
+type messageError struct {
+	id   messageID
+	args []any
+}
+
+func tr(id messageID, args ...any) string {
+	catalog := messageCatalogs[defaultUILanguage]
+	if active := activeMessages.Load(); active != nil {
+		catalog = *active
+	}
+	format, ok := catalog[id]
+	if !ok {
+		format, ok = messageCatalogs[defaultUILanguage][id]
+	}
+	if !ok {
+		return string(id)
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func (e *messageError) Error() string {
+	return tr(e.id, e.args...)
+}
+
+func (e *messageError) Unwrap() error {
+	for _, arg := range e.args {
+		if err, ok := arg.(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+func messageErr(id messageID, args ...any) error {
+	return &messageError{id: id, args: args}
+}
+
+func errorCode(err error) string {
+	var coded *messageError
+	if errors.As(err, &coded) {
+		return string(coded.id)
+	}
+	return ""
+}
+
+func reportError(w io.Writer, id messageID, args ...any) error {
+	coded := messageErr(id, args...)
+	fmt.Fprintln(w, coded.Error())
+	return coded
+}
+
+func uiLanguages() []string {
+	names := make([]string, 0, len(messageCatalogs))
+	for name := range messageCatalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseUILanguage(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(trimQuotes(value)))
+	if value == "" {
+		return defaultUILanguage, nil
+	}
+	if _, ok := messageCatalogs[value]; !ok {
+		return "", fmt.Errorf("invalid ui_language: %q (expected one of %s)", value, strings.Join(uiLanguages(), ", "))
+	}
+	return value, nil
+}
+
+func messageVerbs(format string) int {
+	return strings.Count(format, "%") - 2*strings.Count(format, "%%")
+}
+
+func parseMessagesSection(key string, value string, cfg *Config) error {
+	id := messageID(trimQuotes(key))
+	base, ok := messageCatalogs[defaultUILanguage][id]
+	if !ok {
+		return fmt.Errorf("unknown message: %q", id)
+	}
+	text := trimQuotes(value)
+	if messageVerbs(text) != messageVerbs(base) {
+		return fmt.Errorf("message %s must keep the placeholders of %q", id, base)
+	}
+	if cfg.Messages == nil {
+		cfg.Messages = map[string]string{}
+	}
+	cfg.Messages[string(id)] = text
+	return nil
+}
+
+func applyMessages(cfg Config) {
+	language, err := parseUILanguage(cfg.UILanguage)
+	if err != nil {
+		language = defaultUILanguage
+	}
+	catalog := map[messageID]string{}
+	for id, text := range messageCatalogs[language] {
+		catalog[id] = text
+	}
+	for id, text := range cfg.Messages {
+		catalog[messageID(id)] = text
+	}
+	activeMessages.Store(&catalog)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseConfigMessages(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("ui_language = \"EN\"\n[messages]\n\"refresh.done\" = \"%d Feeds aktualisiert\"\n\"status.ready\" = \"Bereit\"\n", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.UILanguage != "en" || cfg.Messages["refresh.done"] != "%d Feeds aktualisiert" || cfg.Messages["status.ready"] != "Bereit" {
+		t.Fatalf("unexpected messages %q %+v", cfg.UILanguage, cfg.Messages)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil || len(reparsed.Messages) != 2 {
+		t.Fatalf("messages did not round trip: %+v %v", reparsed.Messages, err)
+	}
+	for _, bad := range []string{
+		"ui_language = \"xx\"",
+		"[messages]\n\"no.such\" = \"x\"",
+		"[messages]\n\"refresh.done\" = \"feeds refreshed\"",
+	} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestMessageOverridesAndFallback(t *testing.T) {
+	t.Cleanup(func() { applyMessages(DefaultConfig()) })
+	if got := tr(msgRefreshDoneFailed, 3, 1); got != "refreshed 3 feeds (1 failed)" {
+		t.Fatalf("unexpected default message %q", got)
+	}
+	cfg := DefaultConfig()
+	cfg.Messages = map[string]string{string(msgStatusReady): "Bereit"}
+	applyMessages(cfg)
+	if tr(msgStatusReady) != "Bereit" || tr(msgSavedPocket) != "Saved to Pocket" {
+		t.Fatalf("expected override with English fallback, got %q %q", tr(msgStatusReady), tr(msgSavedPocket))
+	}
+	if tr(messageID("no.such")) != "no.such" {
+		t.Fatalf("expected unknown IDs to render as their code")
+	}
+	for id, text := range messageCatalogs[defaultUILanguage] {
+		if strings.TrimSpace(text) == "" || !strings.Contains(string(id), ".") {
+			t.Fatalf("bad catalog entry %q = %q", id, text)
+		}
+	}
+}
+
+func TestMessageErrorCodes(t *testing.T) {
+	cause := errors.New("disk full")
+	var stderr bytes.Buffer
+	err := reportError(&stderr, msgCLIRefreshError, cause)
+	if stderr.String() != "refresh error: disk full\n" || err.Error() != "refresh error: disk full" {
+		t.Fatalf("unexpected report %q %v", stderr.String(), err)
+	}
+	if errorCode(err) != "cli.refresh_error" || !errors.Is(err, cause) {
+		t.Fatalf("expected coded error wrapping cause, got %q", errorCode(err))
+	}
+	if errorCode(cause) != "" {
+		t.Fatalf("expected plain errors to have no code")
+	}
+	if err := messageErr(msgCLIDoctorFailed, 2); err.Error() != "2 checks failed" || errorCode(err) != "cli.doctor_failed" {
+		t.Fatalf("unexpected doctor error %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type appMetrics struct {
+	feedsRefreshed     atomic.Int64
+	fetchErrors        atomic.Int64
+	summariesGenerated atomic.Int64
+	queueDepth         atomic.Int64
+	cycles             atomic.Int64
+	cycleFailures      atomic.Int64
+	lastRefresh        atomic.Int64
+	mu                 sync.Mutex
+	lastError          string
+}
+
+var metricsListen = net.Listen
+
+func (m *appMetrics) recordCycle(now time.Time, err error) {
+	m.cycles.Add(1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.cycleFailures.Add(1)
+		m.lastError = err.Error()
+		return
+	}
+	m.lastRefresh.Store(now.Unix())
+	m.lastError = ""
+}
+
+func (m *appMetrics) health() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lastError != "" {
+		return errors.New(m.lastError)
+	}
+	return nil
+}
+
+func (a *App) summaryQueueDepth() int {
+	return a.store.UnsummarizedUnreadCount()
+}
+
+func (m *appMetrics) render() string {
+	metrics := []struct {
+		name  string
+		kind  string
+		help  string
+		value int64
+	}{
+		{"greeder_feeds_refreshed_total", "counter", "Feeds fetched successfully.", m.feedsRefreshed.Load()},
+		{"greeder_fetch_errors_total", "counter", "Feed fetches that failed.", m.fetchErrors.Load()},
+		{"greeder_summaries_generated_total", "counter", "Summaries generated.", m.summariesGenerated.Load()},
+		{"greeder_summary_queue_depth", "gauge", "Unread articles without a summary.", m.queueDepth.Load()},
+		{"greeder_refresh_cycles_total", "counter", "Daemon refresh cycles run.", m.cycles.Load()},
+		{"greeder_refresh_failures_total", "counter", "Daemon refresh cycles that failed.", m.cycleFailures.Load()},
+		{"greeder_last_refresh_timestamp_seconds", "gauge", "Unix time of the last successful refresh.", m.lastRefresh.Load()},
+	}
+	var b strings.Builder
+	for _, metric := range metrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", metric.name, metric.help, metric.name, metric.kind, metric.name, metric.value)
+	}
+	return b.String()
+}
+
+func (m *appMetrics) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "text/plain; charset=utf-8")
+		if err := m.health(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "refresh failed: %s\n", err)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "text/plain; version=0.0.4; charset=utf-8")
+		fmt.Fprint(w, m.render())
+	})
+	return mux
+}
+
+func startMetricsServer(addr string, metrics *appMetrics) (net.Addr, func() error, error) {
+	listener, err := metricsListen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	server := &http.Server{Handler: metrics.handler(), ReadHeaderTimeout: 5 * time.Second}
+	go server.Serve(listener)
+	return listener.Addr(), server.Close, nil
+}
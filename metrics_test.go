@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppMetricsHandler(t *testing.T) {
+	metrics := &appMetrics{}
+	metrics.feedsRefreshed.Add(3)
+	metrics.fetchErrors.Add(1)
+	metrics.summariesGenerated.Add(2)
+	metrics.queueDepth.Store(5)
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	metrics.recordCycle(now, nil)
+	handler := metrics.handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok\n" {
+		t.Fatalf("unexpected health %d %q", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE greeder_feeds_refreshed_total counter\ngreeder_feeds_refreshed_total 3\n",
+		"greeder_fetch_errors_total 1\n",
+		"greeder_summaries_generated_total 2\n",
+		"# TYPE greeder_summary_queue_depth gauge\ngreeder_summary_queue_depth 5\n",
+		"greeder_refresh_cycles_total 1\n",
+		"greeder_refresh_failures_total 0\n",
+		"greeder_last_refresh_timestamp_seconds " + strconv.FormatInt(now.Unix(), 10) + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics missing %q in %s", want, body)
+		}
+	}
+
+	metrics.recordCycle(now.Add(time.Hour), errors.New("offline"))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Body.String() != "refresh failed: offline\n" {
+		t.Fatalf("unexpected failing health %d %q", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(metrics.render(), "greeder_refresh_failures_total 1\n") || !strings.Contains(metrics.render(), "greeder_last_refresh_timestamp_seconds "+strconv.FormatInt(now.Unix(), 10)+"\n") {
+		t.Fatalf("unexpected metrics after failure %s", metrics.render())
+	}
+	metrics.recordCycle(now.Add(2*time.Hour), nil)
+	if metrics.health() != nil {
+		t.Fatalf("expected health to recover")
+	}
+}
+
+func TestSummaryQueueDepth(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "a", Title: "A", URL: "https://example.com/a"},
+		{GUID: "b", Title: "B", URL: "https://example.com/b"},
+		{GUID: "c", Title: "C", URL: "https://example.com/c", IsRead: true},
+	})
+	if err != nil || len(articles) != 3 {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "done"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	if depth := app.summaryQueueDepth(); depth != 1 {
+		t.Fatalf("expected queue depth 1, got %d", depth)
+	}
+}
+
+func TestRunDaemonMetrics(t *testing.T) {
+	app := newTUIApp(t)
+	if _, err := app.store.InsertFeed(Feed{Title: "Sample RSS", URL: "http://example.test/rss"}); err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	app.fetcher.client = clientForResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"})
+	app.config.MetricsAddr = "127.0.0.1:0"
+
+	origListen := metricsListen
+	t.Cleanup(func() { metricsListen = origListen })
+	var listener net.Listener
+	metricsListen = func(network, addr string) (net.Listener, error) {
+		var err error
+		listener, err = origListen(network, addr)
+		return listener, err
+	}
+	stop := make(chan struct{})
+	var scraped string
+	app.setClock(&testClock{now: time.Now(), after: func(time.Duration) <-chan time.Time {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/metrics")
+		if err != nil {
+			t.Fatalf("scrape error: %v", err)
+		}
+		blob, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		scraped = string(blob)
+		close(stop)
+		return nil
+	}})
+	var out bytes.Buffer
+	if err := runDaemon(app, &out, stop); err != nil {
+		t.Fatalf("runDaemon error: %v", err)
+	}
+	if !strings.Contains(out.String(), "metrics on http://127.0.0.1:") {
+		t.Fatalf("unexpected daemon output %s", out.String())
+	}
+	if !strings.Contains(scraped, "greeder_feeds_refreshed_total 1\n") || !strings.Contains(scraped, "greeder_refresh_cycles_total 1\n") || !strings.Contains(scraped, "greeder_summary_queue_depth "+strconv.Itoa(app.summaryQueueDepth())+"\n") || app.summaryQueueDepth() == 0 {
+		t.Fatalf("unexpected metrics %s", scraped)
+	}
+	if _, err := http.Get("http://" + listener.Addr().String() + "/healthz"); err == nil {
+		t.Fatalf("expected metrics server to stop with the daemon")
+	}
+
+	metricsListen = func(string, string) (net.Listener, error) { return nil, errors.New("in use") }
+	if err := runDaemon(app, &out, stop); err == nil || err.Error() != "metrics: in use" {
+		t.Fatalf("expected listen error, got %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type legacyStoreData struct {
@@ -19,6 +20,56 @@ type legacyStoreData struct {
 	Deleted   []Deleted `json:"deleted"`
 }
 
+type legacyFeed struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	SiteURL     string    `json:"site_url"`
+	Description string    `json:"description"`
+	LastFetched time.Time `json:"last_fetched"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type legacyArticle struct {
+	ID          int       `json:"id"`
+	FeedID      int       `json:"feed_id"`
+	GUID        string    `json:"guid"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author"`
+	Content     string    `json:"content"`
+	ContentText string    `json:"content_text"`
+	PublishedAt time.Time `json:"published_at"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	IsRead      bool      `json:"is_read"`
+	IsStarred   bool      `json:"is_starred"`
+	FeedTitle   string    `json:"feed_title"`
+}
+
+type legacySummary struct {
+	ID          int       `json:"id"`
+	ArticleID   int       `json:"article_id"`
+	Content     string    `json:"content"`
+	Model       string    `json:"model"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+type legacyDeleted struct {
+	FeedID    int           `json:"feed_id"`
+	GUID      string        `json:"guid"`
+	DeletedAt time.Time     `json:"deleted_at"`
+	Article   legacyArticle `json:"article"`
+}
+
+type legacyExportData struct {
+	Feeds     []legacyFeed    `json:"feeds"`
+	Articles  []legacyArticle `json:"articles"`
+	Summaries []legacySummary `json:"summaries"`
+	Saved     []Saved         `json:"saved"`
+	Deleted   []legacyDeleted `json:"deleted"`
+}
+
 var terminalCheck = func(stdin io.Reader, stdout io.Writer) bool {
 	return isTerminalReader(stdin) && isTerminalWriter(stdout)
 }
@@ -27,6 +78,9 @@ var userConfigDir = os.UserConfigDir
 var userHomeDir = os.UserHomeDir
 var legacyJSONMarshal = json.Marshal
 var legacyReadFile = os.ReadFile
+var legacyMarshalIndent = json.MarshalIndent
+var legacyWriteFile = os.WriteFile
+var migrationNow = time.Now
 
 func legacyConfigPath() string {
 	configDir, err := userConfigDir()
@@ -62,6 +116,7 @@ func maybeOfferMigration(stdin io.Reader, stdout io.Writer, stderr io.Writer) er
 		fmt.Fprintf(stderr, "Legacy config found at %s. Run greeder interactively to migrate.\n", legacyConfig)
 		return nil
 	}
+	fmt.Fprint(stdout, legacyMigrationReport(legacyConfig))
 	fmt.Fprint(stdout, "Migrate config and database from speedy-reader to greeder? [y/N]: ")
 	reader := bufio.NewReader(stdin)
 	response, _ := reader.ReadString('\n')
@@ -69,21 +124,109 @@ func maybeOfferMigration(stdin io.Reader, stdout io.Writer, stderr io.Writer) er
 	if response != "y" && response != "yes" {
 		return nil
 	}
-	if err := migrateLegacyConfigAndDB(legacyConfig, newConfig); err != nil {
+	if err := migrateLegacyConfigAndDB(legacyConfig, newConfig, stdout); err != nil {
 		return err
 	}
 	fmt.Fprintln(stdout, "Migration complete.")
 	return nil
 }
 
-func migrateLegacyConfigAndDB(legacyConfigPath string, newConfigPath string) error {
+func loadLegacyConfig(legacyConfigPath string) (Config, error) {
 	data, err := os.ReadFile(legacyConfigPath)
 	if err != nil {
-		return err
+		return Config{}, err
 	}
 	legacyCfg := DefaultConfig()
 	legacyCfg.DBPath = legacyDefaultDBPath()
 	if err := parseConfig(string(data), &legacyCfg); err != nil {
+		return Config{}, err
+	}
+	return legacyCfg, nil
+}
+
+func readLegacyStore(path string) (legacyStoreData, error) {
+	var legacy legacyStoreData
+	if !fileExists(path) {
+		return legacy, errors.New("legacy database not found")
+	}
+	data, err := legacyReadFile(path)
+	if err != nil {
+		return legacy, err
+	}
+	if len(data) == 0 {
+		return legacy, nil
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return legacy, err
+	}
+	return legacy, nil
+}
+
+func (d legacyStoreData) records() int {
+	return len(d.Feeds) + len(d.Articles) + len(d.Summaries) + len(d.Saved) + len(d.Deleted)
+}
+
+func (d legacyStoreData) counts() string {
+	return fmt.Sprintf("%d feeds, %d articles, %d summaries, %d saved, %d deleted",
+		len(d.Feeds), len(d.Articles), len(d.Summaries), len(d.Saved), len(d.Deleted))
+}
+
+func legacyMigrationReport(legacyConfigPath string) string {
+	legacyCfg, err := loadLegacyConfig(legacyConfigPath)
+	if err != nil {
+		return fmt.Sprintf("Could not read legacy config: %v\n", err)
+	}
+	lines := []string{}
+	legacy, err := readLegacyStore(legacyCfg.DBPath)
+	if err != nil {
+		lines = append(lines, fmt.Sprintf("Could not read legacy database %s: %v", legacyCfg.DBPath, err))
+	} else {
+		lines = append(lines, fmt.Sprintf("Legacy database %s: %s.", legacyCfg.DBPath, legacy.counts()))
+	}
+	if target := defaultDBPath(); fileExists(target) {
+		lines = append(lines, fmt.Sprintf("Existing database %s will be backed up first.", target))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func backupExistingDB(path string) (string, error) {
+	if !fileExists(path) {
+		return "", nil
+	}
+	backup := path + ".bak-" + migrationNow().Format("20060102-150405")
+	if err := os.Rename(path, backup); err != nil {
+		return "", err
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if fileExists(path + suffix) {
+			if err := os.Rename(path+suffix, backup+suffix); err != nil {
+				return "", err
+			}
+		}
+	}
+	return backup, nil
+}
+
+type migrationProgress struct {
+	out   io.Writer
+	total int
+	done  int
+}
+
+func (p *migrationProgress) step() {
+	p.done++
+	if p.done%100 != 0 && p.done != p.total {
+		return
+	}
+	fmt.Fprintf(p.out, "\rMigrating records: %d/%d", p.done, p.total)
+	if p.done == p.total {
+		fmt.Fprintln(p.out)
+	}
+}
+
+func migrateLegacyConfigAndDB(legacyConfigPath string, newConfigPath string, progress io.Writer) error {
+	legacyCfg, err := loadLegacyConfig(legacyConfigPath)
+	if err != nil {
 		return err
 	}
 
@@ -97,33 +240,60 @@ func migrateLegacyConfigAndDB(legacyConfigPath string, newConfigPath string) err
 		return err
 	}
 
-	if err := migrateLegacyDB(legacyCfg.DBPath, newCfg.DBPath); err != nil {
+	if err := migrateLegacyDB(legacyCfg.DBPath, newCfg.DBPath, progress); err != nil {
 		return err
 	}
 	return nil
 }
 
-func migrateLegacyDB(oldPath string, newPath string) error {
-	if !fileExists(oldPath) {
-		return errors.New("legacy database not found")
-	}
-	data, err := legacyReadFile(oldPath)
+func migrateLegacyDB(oldPath string, newPath string, out io.Writer) error {
+	legacy, err := readLegacyStore(oldPath)
 	if err != nil {
 		return err
 	}
-	if len(data) == 0 {
-		_, err := NewStore(newPath)
-		return err
+	backup, err := backupExistingDB(newPath)
+	if err != nil {
+		return fmt.Errorf("backup existing database: %w", err)
 	}
-	var legacy legacyStoreData
-	if err := json.Unmarshal(data, &legacy); err != nil {
-		return err
+	if backup != "" {
+		fmt.Fprintf(out, "Backed up existing database to %s\n", backup)
 	}
+	if err := importLegacyStore(legacy, newPath, out); err != nil {
+		return restoreBackupDB(newPath, backup, err)
+	}
+	return nil
+}
+
+// restoreBackupDB drops a half-written target and puts the backed up
+// database back, so a failed migration leaves the user where they started.
+func restoreBackupDB(path string, backup string, cause error) error {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if fileExists(path + suffix) {
+			if err := os.Remove(path + suffix); err != nil {
+				return fmt.Errorf("%w (remove partial database: %v)", cause, err)
+			}
+		}
+	}
+	if backup == "" {
+		return cause
+	}
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if fileExists(backup + suffix) {
+			if err := os.Rename(backup+suffix, path+suffix); err != nil {
+				return fmt.Errorf("%w (restore %s: %v)", cause, backup, err)
+			}
+		}
+	}
+	return cause
+}
+
+func importLegacyStore(legacy legacyStoreData, newPath string, out io.Writer) error {
 	store, err := NewStore(newPath)
 	if err != nil {
 		return err
 	}
 	defer store.db.Close()
+	progress := &migrationProgress{out: out, total: legacy.records()}
 
 	tx, err := beginTx(store.db)
 	if err != nil {
@@ -136,6 +306,7 @@ func migrateLegacyDB(oldPath string, newPath string) error {
 			feed.ID, feed.Title, feed.URL, feed.SiteURL, feed.Description, timeToUnix(feed.LastFetched), timeToUnix(feed.CreatedAt), timeToUnix(feed.UpdatedAt)); err != nil {
 			return err
 		}
+		progress.step()
 	}
 	for _, article := range legacy.Articles {
 		base := baseURL(article.URL)
@@ -150,12 +321,14 @@ func migrateLegacyDB(oldPath string, newPath string) error {
 			article.ID, article.FeedID, timeToUnix(article.PublishedAt)); err != nil {
 			return err
 		}
+		progress.step()
 	}
 	for _, summary := range legacy.Summaries {
 		if _, err := tx.Exec(`INSERT INTO summaries (id, article_id, content, model, generated_at) VALUES (?, ?, ?, ?, ?)`,
 			summary.ID, summary.ArticleID, summary.Content, summary.Model, timeToUnix(summary.GeneratedAt)); err != nil {
 			return err
 		}
+		progress.step()
 	}
 	for _, saved := range legacy.Saved {
 		blob, err := legacyJSONMarshal(saved.Tags)
@@ -166,6 +339,7 @@ func migrateLegacyDB(oldPath string, newPath string) error {
 			saved.ArticleID, saved.RaindropID, string(blob), timeToUnix(saved.SavedAt)); err != nil {
 			return err
 		}
+		progress.step()
 	}
 	for _, deleted := range legacy.Deleted {
 		article := deleted.Article
@@ -177,11 +351,95 @@ func migrateLegacyDB(oldPath string, newPath string) error {
 			deleted.FeedID, deleted.GUID, article.Title, article.URL, base, article.Author, article.Content, article.ContentText, timeToUnix(article.PublishedAt), timeToUnix(article.FetchedAt), boolToInt(article.IsRead), boolToInt(article.IsStarred), article.FeedTitle, timeToUnix(deleted.DeletedAt)); err != nil {
 			return err
 		}
+		progress.step()
 	}
 
 	return tx.Commit()
 }
 
+func toLegacyArticle(article Article) legacyArticle {
+	return legacyArticle{
+		ID:          article.ID,
+		FeedID:      article.FeedID,
+		GUID:        article.GUID,
+		Title:       article.Title,
+		URL:         article.URL,
+		Author:      article.Author,
+		Content:     article.Content,
+		ContentText: article.ContentText,
+		PublishedAt: article.PublishedAt,
+		FetchedAt:   article.FetchedAt,
+		IsRead:      article.IsRead,
+		IsStarred:   article.IsStarred,
+		FeedTitle:   article.FeedTitle,
+	}
+}
+
+func exportLegacyDB(store *Store, path string) (legacyExportData, error) {
+	if path == "" {
+		return legacyExportData{}, errors.New("missing export path")
+	}
+	data := legacyExportData{
+		Feeds:     []legacyFeed{},
+		Articles:  []legacyArticle{},
+		Summaries: []legacySummary{},
+		Saved:     store.Saved(),
+		Deleted:   []legacyDeleted{},
+	}
+	for i := range data.Saved {
+		data.Saved[i].Provider = ""
+	}
+	for _, feed := range store.Feeds() {
+		data.Feeds = append(data.Feeds, legacyFeed{
+			ID:          feed.ID,
+			Title:       feed.Title,
+			URL:         feed.URL,
+			SiteURL:     feed.SiteURL,
+			Description: feed.Description,
+			LastFetched: feed.LastFetched,
+			CreatedAt:   feed.CreatedAt,
+			UpdatedAt:   feed.UpdatedAt,
+		})
+	}
+	for _, article := range store.Articles() {
+		data.Articles = append(data.Articles, toLegacyArticle(article))
+	}
+	for _, summary := range store.Summaries() {
+		data.Summaries = append(data.Summaries, legacySummary{
+			ID:          summary.ID,
+			ArticleID:   summary.ArticleID,
+			Content:     summary.Content,
+			Model:       summary.Model,
+			GeneratedAt: summary.GeneratedAt,
+		})
+	}
+	for _, deleted := range store.Deleted() {
+		data.Deleted = append(data.Deleted, legacyDeleted{
+			FeedID:    deleted.FeedID,
+			GUID:      deleted.GUID,
+			DeletedAt: deleted.DeletedAt,
+			Article:   toLegacyArticle(deleted.Article),
+		})
+	}
+	payload, err := legacyMarshalIndent(data, "", "  ")
+	if err != nil {
+		return legacyExportData{}, err
+	}
+	if err := legacyWriteFile(path, payload, 0o600); err != nil {
+		return legacyExportData{}, err
+	}
+	return data, nil
+}
+
+func (a *App) ExportLegacy(path string) error {
+	data, err := exportLegacyDB(a.store, path)
+	if err != nil {
+		return err
+	}
+	a.status = fmt.Sprintf("Exported %d feeds and %d articles in legacy format", len(data.Feeds), len(data.Articles))
+	return nil
+}
+
 func fileExists(path string) bool {
 	info, err := os.Stat(path)
 	return err == nil && !info.IsDir()
@@ -96,7 +96,7 @@ func TestMigrateLegacyConfigBadParse(t *testing.T) {
 	if err := os.WriteFile(legacyConfig, []byte("badline"), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyConfigAndDB(legacyConfig, newConfig); err == nil {
+	if err := migrateLegacyConfigAndDB(legacyConfig, newConfig, io.Discard); err == nil {
 		t.Fatalf("expected parse error")
 	}
 }
@@ -108,7 +108,7 @@ func TestMigrateLegacyDBEmpty(t *testing.T) {
 	if err := os.WriteFile(oldPath, []byte(""), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyDB(oldPath, newPath); err != nil {
+	if err := migrateLegacyDB(oldPath, newPath, io.Discard); err != nil {
 		t.Fatalf("migrate error: %v", err)
 	}
 	if _, err := os.Stat(newPath); err != nil {
@@ -138,7 +138,7 @@ func TestMigrateLegacyConfigErrorBranches(t *testing.T) {
 	if err := os.MkdirAll(legacyDir, 0o755); err != nil {
 		t.Fatalf("mkdir error: %v", err)
 	}
-	if err := migrateLegacyConfigAndDB(legacyDir, filepath.Join(root, "new.toml")); err == nil {
+	if err := migrateLegacyConfigAndDB(legacyDir, filepath.Join(root, "new.toml"), io.Discard); err == nil {
 		t.Fatalf("expected read error")
 	}
 
@@ -154,7 +154,7 @@ func TestMigrateLegacyConfigErrorBranches(t *testing.T) {
 	if err := os.WriteFile(blocker, []byte("x"), 0o600); err != nil {
 		t.Fatalf("write blocker: %v", err)
 	}
-	if err := migrateLegacyConfigAndDB(legacyConfig, filepath.Join(blocker, "config.toml")); err == nil {
+	if err := migrateLegacyConfigAndDB(legacyConfig, filepath.Join(blocker, "config.toml"), io.Discard); err == nil {
 		t.Fatalf("expected mkdir error")
 	}
 
@@ -162,7 +162,7 @@ func TestMigrateLegacyConfigErrorBranches(t *testing.T) {
 	if err := os.MkdirAll(readonlyDir, 0o500); err != nil {
 		t.Fatalf("mkdir error: %v", err)
 	}
-	if err := migrateLegacyConfigAndDB(legacyConfig, readonlyDir); err == nil {
+	if err := migrateLegacyConfigAndDB(legacyConfig, readonlyDir, io.Discard); err == nil {
 		t.Fatalf("expected write error")
 	}
 
@@ -172,7 +172,7 @@ func TestMigrateLegacyConfigErrorBranches(t *testing.T) {
 	if err := os.WriteFile(legacyConfig, []byte("db_path = \"/nope\"\n"), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyConfigAndDB(legacyConfig, filepath.Join(root, "new.toml")); err == nil {
+	if err := migrateLegacyConfigAndDB(legacyConfig, filepath.Join(root, "new.toml"), io.Discard); err == nil {
 		t.Fatalf("expected migrate db error")
 	}
 }
@@ -183,7 +183,7 @@ func TestMigrateLegacyDBErrorBranches(t *testing.T) {
 	if err := os.MkdirAll(badDir, 0o755); err != nil {
 		t.Fatalf("mkdir error: %v", err)
 	}
-	if err := migrateLegacyDB(badDir, filepath.Join(root, "new.db")); err == nil {
+	if err := migrateLegacyDB(badDir, filepath.Join(root, "new.db"), io.Discard); err == nil {
 		t.Fatalf("expected read error")
 	}
 
@@ -194,7 +194,7 @@ func TestMigrateLegacyDBErrorBranches(t *testing.T) {
 	if err := os.Chmod(unreadable, 0o000); err != nil {
 		t.Fatalf("chmod error: %v", err)
 	}
-	if err := migrateLegacyDB(unreadable, filepath.Join(root, "new.db")); err == nil {
+	if err := migrateLegacyDB(unreadable, filepath.Join(root, "new.db"), io.Discard); err == nil {
 		t.Fatalf("expected read error")
 	}
 	if err := os.Chmod(unreadable, 0o600); err != nil {
@@ -205,7 +205,7 @@ func TestMigrateLegacyDBErrorBranches(t *testing.T) {
 	if err := os.WriteFile(badJSON, []byte("{bad"), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyDB(badJSON, filepath.Join(root, "new.db")); err == nil {
+	if err := migrateLegacyDB(badJSON, filepath.Join(root, "new.db"), io.Discard); err == nil {
 		t.Fatalf("expected json error")
 	}
 
@@ -216,21 +216,21 @@ func TestMigrateLegacyDBErrorBranches(t *testing.T) {
 	if err := os.MkdirAll(filepath.Join(root, "dbdir"), 0o755); err != nil {
 		t.Fatalf("mkdir error: %v", err)
 	}
-	if err := migrateLegacyDB(validJSON, filepath.Join(root, "dbdir")); err == nil {
+	if err := migrateLegacyDB(validJSON, filepath.Join(root, "dbdir"), io.Discard); err == nil {
 		t.Fatalf("expected new store error")
 	}
 
 	origBegin := beginTx
 	beginTx = func(*sql.DB) (*sql.Tx, error) { return nil, errors.New("begin fail") }
 	t.Cleanup(func() { beginTx = origBegin })
-	if err := migrateLegacyDB(validJSON, filepath.Join(root, "new.db")); err == nil {
+	if err := migrateLegacyDB(validJSON, filepath.Join(root, "new.db"), io.Discard); err == nil {
 		t.Fatalf("expected begin error")
 	}
 
 	origRead := legacyReadFile
 	legacyReadFile = func(string) ([]byte, error) { return nil, errors.New("read fail") }
 	t.Cleanup(func() { legacyReadFile = origRead })
-	if err := migrateLegacyDB(validJSON, filepath.Join(root, "new.db")); err == nil {
+	if err := migrateLegacyDB(validJSON, filepath.Join(root, "new.db"), io.Discard); err == nil {
 		t.Fatalf("expected read error")
 	}
 }
@@ -243,7 +243,7 @@ func TestMigrateLegacyDBLoopErrors(t *testing.T) {
 	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyDB(path, filepath.Join(root, "feed.db")); err == nil {
+	if err := migrateLegacyDB(path, filepath.Join(root, "feed.db"), io.Discard); err == nil {
 		t.Fatalf("expected feed insert error")
 	}
 
@@ -251,7 +251,7 @@ func TestMigrateLegacyDBLoopErrors(t *testing.T) {
 	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyDB(path, filepath.Join(root, "article.db")); err == nil {
+	if err := migrateLegacyDB(path, filepath.Join(root, "article.db"), io.Discard); err == nil {
 		t.Fatalf("expected article insert error")
 	}
 
@@ -259,7 +259,7 @@ func TestMigrateLegacyDBLoopErrors(t *testing.T) {
 	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyDB(path, filepath.Join(root, "summary.db")); err == nil {
+	if err := migrateLegacyDB(path, filepath.Join(root, "summary.db"), io.Discard); err == nil {
 		t.Fatalf("expected summary insert error")
 	}
 
@@ -267,7 +267,7 @@ func TestMigrateLegacyDBLoopErrors(t *testing.T) {
 	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyDB(path, filepath.Join(root, "saved.db")); err == nil {
+	if err := migrateLegacyDB(path, filepath.Join(root, "saved.db"), io.Discard); err == nil {
 		t.Fatalf("expected saved insert error")
 	}
 
@@ -278,7 +278,7 @@ func TestMigrateLegacyDBLoopErrors(t *testing.T) {
 	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyDB(path, filepath.Join(root, "marshal.db")); err == nil {
+	if err := migrateLegacyDB(path, filepath.Join(root, "marshal.db"), io.Discard); err == nil {
 		t.Fatalf("expected marshal error")
 	}
 
@@ -347,7 +347,7 @@ func TestMigrateLegacyDBLoopErrors(t *testing.T) {
 	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyDB(path, filepath.Join(root, "deleted.db")); err == nil {
+	if err := migrateLegacyDB(path, filepath.Join(root, "deleted.db"), io.Discard); err == nil {
 		t.Fatalf("expected deleted insert error")
 	}
 }
@@ -370,7 +370,7 @@ func TestMigrateLegacyDBArticleSourcesError(t *testing.T) {
 		return nil
 	}
 	t.Cleanup(func() { schemaInit = origSchema })
-	if err := migrateLegacyDB(path, filepath.Join(root, "sources.db")); err == nil {
+	if err := migrateLegacyDB(path, filepath.Join(root, "sources.db"), io.Discard); err == nil {
 		t.Fatalf("expected article_sources insert error")
 	}
 }
@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -107,7 +109,7 @@ func TestMigrateLegacyConfigAndDB(t *testing.T) {
 	}
 
 	newConfig := configPath()
-	if err := migrateLegacyConfigAndDB(legacyConfig, newConfig); err != nil {
+	if err := migrateLegacyConfigAndDB(legacyConfig, newConfig, io.Discard); err != nil {
 		t.Fatalf("migrate error: %v", err)
 	}
 	if !fileExists(newConfig) {
@@ -141,7 +143,7 @@ func TestMigrateLegacyDBSourcesAndBaseURL(t *testing.T) {
 	if err := os.WriteFile(legacy, []byte(data), 0o600); err != nil {
 		t.Fatalf("write error: %v", err)
 	}
-	if err := migrateLegacyDB(legacy, newPath); err != nil {
+	if err := migrateLegacyDB(legacy, newPath, io.Discard); err != nil {
 		t.Fatalf("migrateLegacyDB error: %v", err)
 	}
 	store, err := NewStore(newPath)
@@ -154,8 +156,101 @@ func TestMigrateLegacyDBSourcesAndBaseURL(t *testing.T) {
 	}
 }
 
+func TestExportLegacyRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss", SiteURL: "https://example.com", Source: "Miniflux"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	published := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	articles, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "a", Title: "Alpha", URL: "https://example.com/a?utm_source=x", Content: "<p>A</p>", ContentText: "A", PublishedAt: published, IsStarred: true},
+		{GUID: "b", Title: "Beta", URL: "https://example.com/b", PublishedAt: published},
+	})
+	if err != nil || len(articles) != 2 {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "Sum", Model: "m", GeneratedAt: published, PromptTokens: 9}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	if err := app.store.SaveBookmark(articles[0].ID, "raindrop", 77, []string{"go"}); err != nil {
+		t.Fatalf("SaveBookmark error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	for i, article := range app.articles {
+		if article.GUID == "b" {
+			app.selectedIndex = i
+		}
+	}
+	app.filter = FilterAll
+	if err := app.DeleteSelected(); err != nil {
+		t.Fatalf("DeleteSelected error: %v", err)
+	}
+
+	path := filepath.Join(root, "legacy.json")
+	if err := app.ExportLegacy(path); err != nil {
+		t.Fatalf("ExportLegacy error: %v", err)
+	}
+	if app.status != "Exported 1 feeds and 1 articles in legacy format" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	for _, modern := range []string{"base_url", "score", "prompt_tokens", "content_hash", "source", "provider"} {
+		if strings.Contains(string(raw), `"`+modern+`"`) {
+			t.Fatalf("legacy export contains %q: %s", modern, raw)
+		}
+	}
+	var decoded map[string][]map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("legacy export is not JSON: %v", err)
+	}
+	if len(decoded["feeds"]) != 1 || len(decoded["articles"]) != 1 || len(decoded["summaries"]) != 1 || len(decoded["saved"]) != 1 || len(decoded["deleted"]) != 1 {
+		t.Fatalf("unexpected legacy export %s", raw)
+	}
+
+	newPath := filepath.Join(root, "new.db")
+	if err := migrateLegacyDB(path, newPath, io.Discard); err != nil {
+		t.Fatalf("migrateLegacyDB error: %v", err)
+	}
+	store, err := NewStore(newPath)
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	defer store.db.Close()
+	migrated := store.Articles()
+	if len(store.Feeds()) != 1 || len(migrated) != 1 || migrated[0].Title != "Alpha" || !migrated[0].IsStarred || !migrated[0].PublishedAt.Equal(published) {
+		t.Fatalf("unexpected migrated articles %+v", migrated)
+	}
+	if summary, ok := store.FindSummary(migrated[0].ID); !ok || summary.Content != "Sum" {
+		t.Fatalf("expected migrated summary")
+	}
+	if len(store.Saved()) != 1 || len(store.Deleted()) != 1 || store.Deleted()[0].Article.Title != "Beta" {
+		t.Fatalf("expected saved and deleted entries to survive the round trip")
+	}
+}
+
+func TestExportLegacyErrors(t *testing.T) {
+	app := newTUIApp(t)
+	if err := app.ExportLegacy(""); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+	if err := app.ExportLegacy(t.TempDir()); err == nil {
+		t.Fatalf("expected write error")
+	}
+	orig := legacyMarshalIndent
+	legacyMarshalIndent = func(any, string, string) ([]byte, error) { return nil, errors.New("boom") }
+	t.Cleanup(func() { legacyMarshalIndent = orig })
+	if err := app.ExportLegacy(filepath.Join(t.TempDir(), "x.json")); err == nil {
+		t.Fatalf("expected marshal error")
+	}
+}
+
 func TestMigrateLegacyDBMissing(t *testing.T) {
-	if err := migrateLegacyDB("/nope", "/tmp/new.db"); err == nil {
+	if err := migrateLegacyDB("/nope", "/tmp/new.db", io.Discard); err == nil {
 		t.Fatalf("expected migrate error")
 	}
 }
@@ -173,3 +268,129 @@ func TestFileExists(t *testing.T) {
 		t.Fatalf("expected dir not a file")
 	}
 }
+
+func TestMigrateLegacyDBBacksUpExistingTarget(t *testing.T) {
+	root := t.TempDir()
+	legacy := filepath.Join(root, "legacy.json")
+	newPath := filepath.Join(root, "new.db")
+	data := `{"feeds":[{"id":1,"title":"Feed","url":"https://example.com/rss"}],"articles":[{"id":1,"feed_id":1,"guid":"g","title":"t","url":"https://example.com/a"}],"summaries":[],"saved":[],"deleted":[]}`
+	if err := os.WriteFile(legacy, []byte(data), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if err := os.WriteFile(newPath, []byte("existing"), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if err := os.WriteFile(newPath+"-wal", []byte("wal"), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	migrationNow = func() time.Time { return time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC) }
+	t.Cleanup(func() { migrationNow = time.Now })
+
+	var out bytes.Buffer
+	if err := migrateLegacyDB(legacy, newPath, &out); err != nil {
+		t.Fatalf("migrateLegacyDB error: %v", err)
+	}
+	backup := newPath + ".bak-20240304-050607"
+	if blob, err := os.ReadFile(backup); err != nil || string(blob) != "existing" {
+		t.Fatalf("expected backup, got %q %v", blob, err)
+	}
+	if !fileExists(backup + "-wal") {
+		t.Fatalf("expected wal backup")
+	}
+	if !strings.Contains(out.String(), "Backed up existing database to "+backup) {
+		t.Fatalf("expected backup message, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "Migrating records: 2/2\n") {
+		t.Fatalf("expected progress, got %q", out.String())
+	}
+	store, err := NewStore(newPath)
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	defer store.db.Close()
+	if len(store.Feeds()) != 1 || len(store.Articles()) != 1 {
+		t.Fatalf("expected migrated data")
+	}
+}
+
+func TestMigrateLegacyDBRestoresTargetOnFailure(t *testing.T) {
+	root := t.TempDir()
+	legacy := filepath.Join(root, "legacy.json")
+	newPath := filepath.Join(root, "new.db")
+	data := `{"feeds":[{"id":1,"title":"A","url":"u"},{"id":1,"title":"B","url":"v"}],"articles":[],"summaries":[],"saved":[],"deleted":[]}`
+	if err := os.WriteFile(legacy, []byte(data), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	existing, err := NewStore(newPath)
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	if _, err := existing.InsertFeed(Feed{Title: "Mine", URL: "https://example.com/rss"}); err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	existing.db.Close()
+	migrationNow = func() time.Time { return time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC) }
+	t.Cleanup(func() { migrationNow = time.Now })
+
+	if err := migrateLegacyDB(legacy, newPath, io.Discard); err == nil {
+		t.Fatalf("expected feed insert error")
+	}
+	if fileExists(newPath + ".bak-20240304-050607") {
+		t.Fatalf("expected the backup moved back into place")
+	}
+	store, err := NewStore(newPath)
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	defer store.db.Close()
+	if feeds := store.Feeds(); len(feeds) != 1 || feeds[0].Title != "Mine" {
+		t.Fatalf("expected the original database kept, got %+v", feeds)
+	}
+}
+
+func TestMigrationProgressThrottles(t *testing.T) {
+	var out bytes.Buffer
+	progress := &migrationProgress{out: &out, total: 250}
+	for i := 0; i < 250; i++ {
+		progress.step()
+	}
+	want := "\rMigrating records: 100/250\rMigrating records: 200/250\rMigrating records: 250/250\n"
+	if out.String() != want {
+		t.Fatalf("unexpected progress %q", out.String())
+	}
+}
+
+func TestLegacyMigrationReport(t *testing.T) {
+	root := t.TempDir()
+	os.Setenv("XDG_DATA_HOME", root)
+	t.Cleanup(func() { os.Unsetenv("XDG_DATA_HOME") })
+	legacyDB := filepath.Join(root, "legacy.json")
+	legacyConfig := filepath.Join(root, "legacy.toml")
+	if err := os.WriteFile(legacyConfig, []byte("db_path = \""+legacyDB+"\"\n"), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	report := legacyMigrationReport(legacyConfig)
+	if !strings.Contains(report, "Could not read legacy database") {
+		t.Fatalf("expected read warning, got %q", report)
+	}
+
+	data := `{"feeds":[{"id":1}],"articles":[{"id":1},{"id":2}],"summaries":[{"id":1}],"saved":[],"deleted":[]}`
+	if err := os.WriteFile(legacyDB, []byte(data), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if err := os.WriteFile(defaultDBPath(), []byte("x"), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	report = legacyMigrationReport(legacyConfig)
+	if !strings.Contains(report, "1 feeds, 2 articles, 1 summaries, 0 saved, 0 deleted") {
+		t.Fatalf("expected counts, got %q", report)
+	}
+	if !strings.Contains(report, "will be backed up first") {
+		t.Fatalf("expected backup notice, got %q", report)
+	}
+
+	if report := legacyMigrationReport(filepath.Join(root, "missing.toml")); !strings.Contains(report, "Could not read legacy config") {
+		t.Fatalf("expected config warning, got %q", report)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const minifluxPageSize = 250
+
+type MinifluxClient struct {
+	baseURL  string
+	token    string
+	username string
+	password string
+	client   *http.Client
+}
+
+type minifluxFeed struct {
+	ID      int    `json:"id"`
+	Title   string `json:"title"`
+	FeedURL string `json:"feed_url"`
+	SiteURL string `json:"site_url"`
+}
+
+type minifluxEntry struct {
+	ID          int    `json:"id"`
+	FeedID      int    `json:"feed_id"`
+	Hash        string `json:"hash"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Author      string `json:"author"`
+	Content     string `json:"content"`
+	PublishedAt string `json:"published_at"`
+	Status      string `json:"status"`
+	Starred     bool   `json:"starred"`
+}
+
+func NewMinifluxClient(baseURL, token, username, password string) *MinifluxClient {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" || (token == "" && username == "") {
+		return nil
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/v1")
+	return &MinifluxClient{
+		baseURL:  baseURL,
+		token:    token,
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (m *MinifluxClient) Name() string {
+	return "Miniflux"
+}
+
+func (m *MinifluxClient) Pull() (SyncSnapshot, error) {
+	if m == nil {
+		return SyncSnapshot{}, errors.New("miniflux not configured")
+	}
+	var feeds []minifluxFeed
+	if err := m.do(http.MethodGet, "/v1/feeds", nil, &feeds); err != nil {
+		return SyncSnapshot{}, err
+	}
+	snapshot := SyncSnapshot{}
+	for _, feed := range feeds {
+		snapshot.Feeds = append(snapshot.Feeds, SyncFeed{
+			RemoteID: strconv.Itoa(feed.ID),
+			Title:    feed.Title,
+			URL:      feed.FeedURL,
+			SiteURL:  feed.SiteURL,
+		})
+	}
+	for offset := 0; ; offset += minifluxPageSize {
+		var page struct {
+			Total   int             `json:"total"`
+			Entries []minifluxEntry `json:"entries"`
+		}
+		path := fmt.Sprintf("/v1/entries?status=unread&status=read&order=published_at&direction=desc&limit=%d&offset=%d", minifluxPageSize, offset)
+		if err := m.do(http.MethodGet, path, nil, &page); err != nil {
+			return SyncSnapshot{}, err
+		}
+		for _, entry := range page.Entries {
+			published, _ := time.Parse(time.RFC3339, entry.PublishedAt)
+			snapshot.Items = append(snapshot.Items, SyncItem{
+				RemoteID:     strconv.Itoa(entry.ID),
+				FeedRemoteID: strconv.Itoa(entry.FeedID),
+				GUID:         entry.Hash,
+				Title:        entry.Title,
+				URL:          entry.URL,
+				Author:       entry.Author,
+				Content:      entry.Content,
+				PublishedAt:  published.UTC(),
+				Read:         entry.Status == "read",
+				Starred:      entry.Starred,
+			})
+		}
+		if len(page.Entries) < minifluxPageSize || offset+len(page.Entries) >= page.Total {
+			break
+		}
+	}
+	return snapshot, nil
+}
+
+func (m *MinifluxClient) Push(changes []SyncChange) error {
+	if m == nil {
+		return errors.New("miniflux not configured")
+	}
+	statuses := map[string][]int{}
+	toggles := []int{}
+	for _, change := range changes {
+		id, err := strconv.Atoi(change.RemoteID)
+		if err != nil {
+			continue
+		}
+		if change.ReadChanged {
+			status := "unread"
+			if change.Read {
+				status = "read"
+			}
+			statuses[status] = append(statuses[status], id)
+		}
+		if change.StarredChanged {
+			toggles = append(toggles, id)
+		}
+	}
+	for _, status := range []string{"read", "unread"} {
+		ids := statuses[status]
+		if len(ids) == 0 {
+			continue
+		}
+		payload, err := servicesJSONMarshal(map[string]any{"entry_ids": ids, "status": status})
+		if err != nil {
+			return err
+		}
+		if err := m.do(http.MethodPut, "/v1/entries", payload, nil); err != nil {
+			return err
+		}
+	}
+	for _, id := range toggles {
+		if err := m.do(http.MethodPut, "/v1/entries/"+strconv.Itoa(id)+"/bookmark", nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MinifluxClient) do(method, path string, payload []byte, out any) error {
+	req, err := http.NewRequest(method, m.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	if m.token != "" {
+		req.Header.Set("X-Auth-Token", m.token)
+	} else {
+		req.SetBasicAuth(m.username, m.password)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("miniflux http %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewMinifluxClient(t *testing.T) {
+	if NewMinifluxClient("", "key", "", "") != nil || NewMinifluxClient("https://rss.example.com", "", "", "") != nil {
+		t.Fatalf("expected nil client without url or credentials")
+	}
+	if client := NewMinifluxClient("https://rss.example.com/v1/", "key", "", ""); client.baseURL != "https://rss.example.com" {
+		t.Fatalf("unexpected base url %q", client.baseURL)
+	}
+	if syncer := NewSyncer(Config{SyncBackend: "miniflux", SyncURL: "https://rss.example.com", SyncToken: "key"}); syncer == nil || syncer.Name() != "Miniflux" {
+		t.Fatalf("unexpected syncer %v", syncer)
+	}
+	if NewSyncer(Config{SyncBackend: "miniflux"}) != nil {
+		t.Fatalf("expected nil syncer without url")
+	}
+}
+
+func TestMinifluxPull(t *testing.T) {
+	client := NewMinifluxClient("http://miniflux.test", "key", "", "")
+	offsets := []string{}
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Header.Get("X-Auth-Token") != "key" {
+			return newResponse(http.StatusUnauthorized, "", nil, r), nil
+		}
+		switch r.URL.Path {
+		case "/v1/feeds":
+			return newResponse(http.StatusOK, `[{"id":3,"title":"Example","feed_url":"https://example.com/rss","site_url":"https://example.com"}]`, nil, r), nil
+		case "/v1/entries":
+			offset := r.URL.Query().Get("offset")
+			offsets = append(offsets, offset)
+			if offset == "0" {
+				entries := make([]string, minifluxPageSize)
+				for i := range entries {
+					entries[i] = fmt.Sprintf(`{"id":%d,"feed_id":3,"hash":"h%d","title":"T","url":"https://example.com/%d","status":"unread"}`, i+1, i+1, i+1)
+				}
+				return newResponse(http.StatusOK, `{"total":251,"entries":[`+strings.Join(entries, ",")+`]}`, nil, r), nil
+			}
+			return newResponse(http.StatusOK, `{"total":251,"entries":[{"id":999,"feed_id":3,"hash":"last","title":"Last","url":"https://example.com/last","author":"Ann","content":"<p>x</p>","published_at":"2024-01-02T03:04:05+01:00","status":"read","starred":true}]}`, nil, r), nil
+		}
+		return newResponse(http.StatusNotFound, "", nil, r), nil
+	})}
+	snapshot, err := client.Pull()
+	if err != nil {
+		t.Fatalf("Pull error: %v", err)
+	}
+	if len(offsets) != 2 || offsets[1] != "250" {
+		t.Fatalf("unexpected paging %v", offsets)
+	}
+	if len(snapshot.Feeds) != 1 || snapshot.Feeds[0].URL != "https://example.com/rss" || snapshot.Feeds[0].RemoteID != "3" {
+		t.Fatalf("unexpected feeds %+v", snapshot.Feeds)
+	}
+	if len(snapshot.Items) != 251 || snapshot.Items[0].Read {
+		t.Fatalf("unexpected items %d", len(snapshot.Items))
+	}
+	last := snapshot.Items[250]
+	if last.RemoteID != "999" || last.GUID != "last" || !last.Read || !last.Starred || last.PublishedAt.Hour() != 2 {
+		t.Fatalf("unexpected item %+v", last)
+	}
+
+	basic := NewMinifluxClient("http://miniflux.test", "", "me", "pw")
+	basic.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me" || pass != "pw" {
+			return newResponse(http.StatusUnauthorized, "", nil, r), nil
+		}
+		if r.URL.Path == "/v1/feeds" {
+			return newResponse(http.StatusOK, `[]`, nil, r), nil
+		}
+		return newResponse(http.StatusOK, `{"total":0,"entries":[]}`, nil, r), nil
+	})}
+	if snapshot, err := basic.Pull(); err != nil || len(snapshot.Feeds) != 0 {
+		t.Fatalf("unexpected basic auth pull %+v %v", snapshot, err)
+	}
+	basic.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/v1/feeds" {
+			return newResponse(http.StatusOK, `[]`, nil, r), nil
+		}
+		return newResponse(http.StatusBadGateway, "", nil, r), nil
+	})}
+	if _, err := basic.Pull(); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("expected entries error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusOK, "nope", nil)
+	if _, err := client.Pull(); err == nil {
+		t.Fatalf("expected decode error")
+	}
+	var nilClient *MinifluxClient
+	if _, err := nilClient.Pull(); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+}
+
+func TestMinifluxPush(t *testing.T) {
+	client := NewMinifluxClient("http://miniflux.test", "key", "", "")
+	statuses := map[string][]int{}
+	toggled := []string{}
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method != http.MethodPut {
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+		if r.URL.Path == "/v1/entries" {
+			body, _ := io.ReadAll(r.Body)
+			var payload struct {
+				EntryIDs []int  `json:"entry_ids"`
+				Status   string `json:"status"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatalf("bad payload %s", body)
+			}
+			statuses[payload.Status] = payload.EntryIDs
+		} else {
+			toggled = append(toggled, r.URL.Path)
+		}
+		return newResponse(http.StatusNoContent, "", nil, r), nil
+	})}
+	changes := []SyncChange{
+		{RemoteID: "1", Read: true, ReadChanged: true},
+		{RemoteID: "2", ReadChanged: true, Starred: true, StarredChanged: true},
+		{RemoteID: "x", Read: true, ReadChanged: true},
+	}
+	if err := client.Push(changes); err != nil {
+		t.Fatalf("Push error: %v", err)
+	}
+	if len(statuses["read"]) != 1 || statuses["read"][0] != 1 || len(statuses["unread"]) != 1 || statuses["unread"][0] != 2 {
+		t.Fatalf("unexpected statuses %v", statuses)
+	}
+	if len(toggled) != 1 || toggled[0] != "/v1/entries/2/bookmark" {
+		t.Fatalf("unexpected bookmark toggles %v", toggled)
+	}
+	client.client = clientForResponse(http.StatusForbidden, "", nil)
+	if err := client.Push(changes); err == nil {
+		t.Fatalf("expected status error")
+	}
+	if err := client.Push([]SyncChange{{RemoteID: "2", StarredChanged: true}}); err == nil {
+		t.Fatalf("expected bookmark error")
+	}
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if err := client.Push(changes); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	var nilClient *MinifluxClient
+	if err := nilClient.Push(nil); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func normalizeMutedWords(words []string) []string {
+	seen := map[string]bool{}
+	normalized := []string{}
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		key := strings.ToLower(word)
+		if word == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, word)
+	}
+	return normalized
+}
+
+func titleMuted(title string, words []string) bool {
+	title = strings.ToLower(title)
+	for _, word := range words {
+		if strings.Contains(title, strings.ToLower(word)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) hidesMuted() bool {
+	return !a.showMuted && len(a.config.MutedWords) > 0
+}
+
+func (a *App) mutedCount() int {
+	count := 0
+	for _, article := range a.allArticles() {
+		if titleMuted(article.Title, a.config.MutedWords) {
+			count++
+		}
+	}
+	return count
+}
+
+func (a *App) ToggleShowMuted() {
+	a.showMuted = !a.showMuted
+	if a.showMuted {
+		a.status = tr(msgMutedShown, a.mutedCount())
+	} else {
+		a.status = tr(msgMutedHidden, a.mutedCount())
+	}
+	a.selectedIndex = 0
+	a.syncSummaryForSelection()
+}
+
+func (a *App) SetMutedWords(raw string) error {
+	words := []string{}
+	if raw = strings.TrimSpace(raw); raw != "-" {
+		words = normalizeMutedWords(strings.Split(raw, ","))
+	}
+	if err := saveMutedWords(words); err != nil {
+		return err
+	}
+	a.config.MutedWords = words
+	if count := len(a.FilteredArticles()); a.selectedIndex >= count {
+		a.selectedIndex = max(count-1, 0)
+	}
+	a.syncSummaryForSelection()
+	if len(words) == 0 {
+		a.status = tr(msgMutedCleared)
+		return nil
+	}
+	a.status = tr(msgMutedSet, strings.Join(words, ", "), a.mutedCount())
+	return nil
+}
+
+func saveMutedWords(words []string) error {
+	path := configPath()
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := parseConfig(string(data), &cfg); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.MutedWords = words
+	return saveConfig(cfg)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMutedWordsConfig(t *testing.T) {
+	if words := normalizeMutedWords([]string{" Crypto ", "", "crypto", "ads"}); strings.Join(words, ",") != "Crypto,ads" {
+		t.Fatalf("unexpected normalized words %v", words)
+	}
+	if !titleMuted("New CRYPTO coin", []string{"crypto"}) || titleMuted("Go 1.24", []string{"crypto"}) {
+		t.Fatalf("unexpected title matching")
+	}
+	var cfg Config
+	if err := parseConfig(`muted_words = ["sponsored", "Sponsored", "ads"]`, &cfg); err != nil || strings.Join(cfg.MutedWords, ",") != "sponsored,ads" {
+		t.Fatalf("unexpected muted config: %v %v", cfg.MutedWords, err)
+	}
+	if !strings.Contains(renderConfig(cfg), `muted_words = ["sponsored", "ads"]`) {
+		t.Fatalf("expected muted words in rendered config:\n%s", renderConfig(cfg))
+	}
+}
+
+func TestTUIMutedWords(t *testing.T) {
+	writeReloadConfig(t, "refresh_interval_minutes = 15\n")
+	app := newTUIApp(t)
+	app.filter = FilterAll
+	insertQueueArticles(t, app.store, 3)
+	app.loadArticles()
+	model := newTUIModel(app)
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(tuiModel)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+	model.input.SetValue("b, C")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.status != "Muted: b, C (2 articles hidden)" || len(app.FilteredArticles()) != 1 || app.FilteredArticles()[0].Title != "A" {
+		t.Fatalf("expected muted articles hidden, got %q %+v", app.status, app.FilteredArticles())
+	}
+	app.filter = FilterUnread
+	if len(app.FilteredArticles()) != 1 {
+		t.Fatalf("expected muted articles hidden from every filter")
+	}
+	data, err := os.ReadFile(filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "greeder", "config.toml"))
+	if err != nil || !strings.Contains(string(data), `muted_words = ["b", "C"]`) || !strings.Contains(string(data), "refresh_interval_minutes = 15") {
+		t.Fatalf("expected muted words saved with the rest of the config: %v\n%s", err, data)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if app.status != "Showing 2 muted articles" || len(app.FilteredArticles()) != 3 {
+		t.Fatalf("expected muted articles revealed, got %q %d", app.status, len(app.FilteredArticles()))
+	}
+	if !strings.Contains(model.renderList(80), "Muted shown") {
+		t.Fatalf("expected list header to say muted articles are shown")
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if app.status != "Hiding 2 muted articles" || len(app.FilteredArticles()) != 1 {
+		t.Fatalf("expected muted articles hidden again, got %q", app.status)
+	}
+	if stored := app.store.SortedArticles(); len(stored) != 3 {
+		t.Fatalf("expected muted articles kept in the store, got %d", len(stored))
+	}
+
+	var out bytes.Buffer
+	if err := handleCommand(app, "mute", &out); err != nil || out.String() != "b, C\n" {
+		t.Fatalf("unexpected mute listing %q %v", out.String(), err)
+	}
+	if err := handleCommand(app, "mute -", &out); err != nil || app.status != "Muted words cleared" || len(app.FilteredArticles()) != 3 {
+		t.Fatalf("expected muted words cleared, got %q %v", app.status, err)
+	}
+	out.Reset()
+	if err := handleCommand(app, "mute", &out); err != nil || out.String() != "No muted words.\n" {
+		t.Fatalf("unexpected empty listing %q", out.String())
+	}
+
+	writeReloadConfig(t, "not valid")
+	if err := app.SetMutedWords("x"); err == nil || len(app.config.MutedWords) != 0 {
+		t.Fatalf("expected broken config to stop saving, got %v %v", err, app.config.MutedWords)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type NextcloudClient struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+}
+
+type nextcloudFeed struct {
+	ID    int    `json:"id"`
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Link  string `json:"link"`
+}
+
+type nextcloudItem struct {
+	ID      int    `json:"id"`
+	GUID    string `json:"guid"`
+	URL     string `json:"url"`
+	Title   string `json:"title"`
+	Author  string `json:"author"`
+	PubDate int64  `json:"pubDate"`
+	Body    string `json:"body"`
+	FeedID  int    `json:"feedId"`
+	Unread  bool   `json:"unread"`
+	Starred bool   `json:"starred"`
+}
+
+func NewNextcloudClient(baseURL, username, password string) *NextcloudClient {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" || username == "" {
+		return nil
+	}
+	if !strings.Contains(baseURL, "/apps/news/api/") {
+		baseURL += "/index.php/apps/news/api/v1-3"
+	}
+	return &NextcloudClient{
+		baseURL:  baseURL,
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (n *NextcloudClient) Name() string {
+	return "Nextcloud News"
+}
+
+func (n *NextcloudClient) Pull() (SyncSnapshot, error) {
+	if n == nil {
+		return SyncSnapshot{}, errors.New("nextcloud not configured")
+	}
+	var feeds struct {
+		Feeds []nextcloudFeed `json:"feeds"`
+	}
+	if err := n.do(http.MethodGet, "/feeds", nil, &feeds); err != nil {
+		return SyncSnapshot{}, err
+	}
+	var items struct {
+		Items []nextcloudItem `json:"items"`
+	}
+	if err := n.do(http.MethodGet, "/items?batchSize=-1&offset=0&type=3&id=0&getRead=true", nil, &items); err != nil {
+		return SyncSnapshot{}, err
+	}
+	snapshot := SyncSnapshot{}
+	for _, feed := range feeds.Feeds {
+		snapshot.Feeds = append(snapshot.Feeds, SyncFeed{
+			RemoteID: strconv.Itoa(feed.ID),
+			Title:    feed.Title,
+			URL:      feed.URL,
+			SiteURL:  feed.Link,
+		})
+	}
+	for _, item := range items.Items {
+		snapshot.Items = append(snapshot.Items, SyncItem{
+			RemoteID:     strconv.Itoa(item.ID),
+			FeedRemoteID: strconv.Itoa(item.FeedID),
+			GUID:         item.GUID,
+			Title:        item.Title,
+			URL:          item.URL,
+			Author:       item.Author,
+			Content:      item.Body,
+			PublishedAt:  time.Unix(item.PubDate, 0).UTC(),
+			Read:         !item.Unread,
+			Starred:      item.Starred,
+		})
+	}
+	return snapshot, nil
+}
+
+func (n *NextcloudClient) Push(changes []SyncChange) error {
+	if n == nil {
+		return errors.New("nextcloud not configured")
+	}
+	batches := map[string][]int{}
+	for _, change := range changes {
+		id, err := strconv.Atoi(change.RemoteID)
+		if err != nil {
+			continue
+		}
+		if change.ReadChanged {
+			if change.Read {
+				batches["read"] = append(batches["read"], id)
+			} else {
+				batches["unread"] = append(batches["unread"], id)
+			}
+		}
+		if change.StarredChanged {
+			if change.Starred {
+				batches["star"] = append(batches["star"], id)
+			} else {
+				batches["unstar"] = append(batches["unstar"], id)
+			}
+		}
+	}
+	for _, action := range []string{"read", "unread", "star", "unstar"} {
+		ids := batches[action]
+		if len(ids) == 0 {
+			continue
+		}
+		payload, err := servicesJSONMarshal(map[string][]int{"itemIds": ids})
+		if err != nil {
+			return err
+		}
+		if err := n.do(http.MethodPut, "/items/"+action+"/multiple", payload, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *NextcloudClient) do(method, path string, payload []byte, out any) error {
+	req, err := http.NewRequest(method, n.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(n.username, n.password)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("nextcloud http %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
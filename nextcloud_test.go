@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewNextcloudClient(t *testing.T) {
+	if NewNextcloudClient(" ", "me", "pw") != nil || NewNextcloudClient("https://cloud.example.com", "", "pw") != nil {
+		t.Fatalf("expected nil client without url or username")
+	}
+	if client := NewNextcloudClient("https://cloud.example.com/", "me", "pw"); client.baseURL != "https://cloud.example.com/index.php/apps/news/api/v1-3" {
+		t.Fatalf("unexpected base url %q", client.baseURL)
+	}
+	if client := NewNextcloudClient("https://cloud.example.com/apps/news/api/v1-3", "me", "pw"); client.baseURL != "https://cloud.example.com/apps/news/api/v1-3" {
+		t.Fatalf("unexpected explicit base url %q", client.baseURL)
+	}
+	if NewSyncer(Config{SyncBackend: "nextcloud"}) != nil {
+		t.Fatalf("expected nil syncer without url")
+	}
+	if syncer := NewSyncer(Config{SyncBackend: "nextcloud", SyncURL: "https://cloud.example.com", SyncUsername: "me"}); syncer == nil || syncer.Name() != "Nextcloud News" {
+		t.Fatalf("unexpected syncer %v", syncer)
+	}
+}
+
+func TestNextcloudPull(t *testing.T) {
+	client := NewNextcloudClient("http://cloud.test", "me", "pw")
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me" || pass != "pw" {
+			return newResponse(http.StatusUnauthorized, "", nil, r), nil
+		}
+		switch r.URL.Path {
+		case "/index.php/apps/news/api/v1-3/feeds":
+			return newResponse(http.StatusOK, `{"feeds":[{"id":7,"url":"https://example.com/rss","title":"Example","link":"https://example.com"}]}`, nil, r), nil
+		case "/index.php/apps/news/api/v1-3/items":
+			if r.URL.Query().Get("getRead") != "true" || r.URL.Query().Get("type") != "3" {
+				t.Fatalf("unexpected query %s", r.URL.RawQuery)
+			}
+			return newResponse(http.StatusOK, `{"items":[{"id":42,"guid":"g1","url":"https://example.com/a","title":"A","author":"Ann","pubDate":1700000000,"body":"<p>Hi</p>","feedId":7,"unread":false,"starred":true}]}`, nil, r), nil
+		}
+		return newResponse(http.StatusNotFound, "", nil, r), nil
+	})}
+	snapshot, err := client.Pull()
+	if err != nil {
+		t.Fatalf("Pull error: %v", err)
+	}
+	if len(snapshot.Feeds) != 1 || snapshot.Feeds[0].RemoteID != "7" || snapshot.Feeds[0].SiteURL != "https://example.com" {
+		t.Fatalf("unexpected feeds %+v", snapshot.Feeds)
+	}
+	item := snapshot.Items[0]
+	if item.RemoteID != "42" || item.FeedRemoteID != "7" || !item.Read || !item.Starred || item.PublishedAt.Unix() != 1700000000 || item.Content != "<p>Hi</p>" {
+		t.Fatalf("unexpected item %+v", item)
+	}
+
+	client.password = "wrong"
+	if _, err := client.Pull(); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusOK, "nope", nil)
+	if _, err := client.Pull(); err == nil {
+		t.Fatalf("expected decode error")
+	}
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(r.URL.Path, "/feeds") {
+			return newResponse(http.StatusOK, `{"feeds":[]}`, nil, r), nil
+		}
+		return newResponse(http.StatusInternalServerError, "", nil, r), nil
+	})}
+	if _, err := client.Pull(); err == nil {
+		t.Fatalf("expected items error")
+	}
+	var nilClient *NextcloudClient
+	if _, err := nilClient.Pull(); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+}
+
+func TestNextcloudPush(t *testing.T) {
+	client := NewNextcloudClient("http://cloud.test", "me", "pw")
+	calls := map[string][]int{}
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method != http.MethodPut {
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var payload struct {
+			ItemIDs []int `json:"itemIds"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("bad payload %s", body)
+		}
+		calls[strings.TrimPrefix(r.URL.Path, "/index.php/apps/news/api/v1-3")] = payload.ItemIDs
+		return newResponse(http.StatusOK, "", nil, r), nil
+	})}
+	changes := []SyncChange{
+		{RemoteID: "1", Read: true, ReadChanged: true},
+		{RemoteID: "2", Read: false, ReadChanged: true, Starred: true, StarredChanged: true},
+		{RemoteID: "3", Starred: false, StarredChanged: true},
+		{RemoteID: "bad", Read: true, ReadChanged: true},
+	}
+	if err := client.Push(changes); err != nil {
+		t.Fatalf("Push error: %v", err)
+	}
+	if len(calls) != 4 || calls["/items/read/multiple"][0] != 1 || calls["/items/unread/multiple"][0] != 2 || calls["/items/star/multiple"][0] != 2 || calls["/items/unstar/multiple"][0] != 3 {
+		t.Fatalf("unexpected calls %v", calls)
+	}
+	client.client = clientForResponse(http.StatusForbidden, "", nil)
+	if err := client.Push(changes); err == nil {
+		t.Fatalf("expected http error")
+	}
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if err := client.Push(changes); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	var nilClient *NextcloudClient
+	if err := nilClient.Push(nil); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+}
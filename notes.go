@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	notesWriteFile = os.WriteFile
+	notesMkdirAll  = os.MkdirAll
+)
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+func noteSlug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimRight(b.String(), "-")
+	if runes := []rune(slug); len(runes) > 60 {
+		slug = strings.TrimRight(string(runes[:60]), "-")
+	}
+	if slug == "" {
+		slug = "article"
+	}
+	return slug
+}
+
+func noteFileName(article Article) string {
+	date := article.PublishedAt
+	if date.IsZero() {
+		date = article.FetchedAt
+	}
+	return fmt.Sprintf("%s-%s.md", date.In(time.Local).Format("2006-01-02"), noteSlug(article.Title))
+}
+
+func renderArticleNote(article Article, summary string, tags []string) string {
+	lines := []string{
+		"---",
+		"title: " + strconv.Quote(article.Title),
+		"url: " + strconv.Quote(article.URL),
+	}
+	if !article.PublishedAt.IsZero() {
+		lines = append(lines, "date: "+article.PublishedAt.UTC().Format(time.RFC3339))
+	}
+	if article.FeedTitle != "" {
+		lines = append(lines, "feed: "+strconv.Quote(article.FeedTitle))
+	}
+	if article.Author != "" {
+		lines = append(lines, "author: "+strconv.Quote(article.Author))
+	}
+	quoted := make([]string, len(tags))
+	for i, tag := range tags {
+		quoted[i] = strconv.Quote(tag)
+	}
+	lines = append(lines, "tags: ["+strings.Join(quoted, ", ")+"]")
+	summary = strings.TrimSpace(summary)
+	if summary != "" {
+		lines = append(lines, "summary: |")
+		for _, line := range strings.Split(summary, "\n") {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, "---", "", "# "+article.Title, "")
+	if summary != "" {
+		lines = append(lines, "## Summary", "", summary, "")
+	}
+	lines = append(lines, fmt.Sprintf("[Read the article](%s)", article.URL), "")
+	return strings.Join(lines, "\n")
+}
+
+func (a *App) noteTags(article Article) []string {
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, tag := range append(append([]string{}, a.config.DefaultTags...), a.store.ArticleTags(article.ID)...) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (a *App) ExportNotes(articles []Article) (int, error) {
+	dir := strings.TrimSpace(a.config.NotesDir)
+	if dir == "" {
+		return 0, errors.New("notes_dir not configured")
+	}
+	dir = expandHome(dir)
+	if err := notesMkdirAll(dir, 0o755); err != nil {
+		return 0, err
+	}
+	for i, article := range articles {
+		summary := ""
+		if existing, ok := a.store.FindSummary(article.ID); ok {
+			summary = existing.Content
+		}
+		note := renderArticleNote(article, summary, a.noteTags(article))
+		if err := notesWriteFile(filepath.Join(dir, noteFileName(article)), []byte(note), 0o644); err != nil {
+			return i, err
+		}
+	}
+	return len(articles), nil
+}
+
+func (a *App) ExportSelectedNote() error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	if _, err := a.ExportNotes([]Article{*article}); err != nil {
+		a.status = "Note export failed: " + err.Error()
+		return err
+	}
+	a.status = "Note written: " + noteFileName(*article)
+	return nil
+}
+
+func (a *App) ExportStarredNotes() (int, error) {
+	count, err := a.ExportNotes(a.starredArticles())
+	if err != nil {
+		a.status = "Note export failed: " + err.Error()
+		return count, err
+	}
+	a.status = fmt.Sprintf("Wrote %d notes", count)
+	return count, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNoteHelpers(t *testing.T) {
+	if noteSlug("Go 1.24: What's New?!") != "go-1-24-what-s-new" {
+		t.Fatalf("unexpected slug %q", noteSlug("Go 1.24: What's New?!"))
+	}
+	if noteSlug("  !!! ") != "article" {
+		t.Fatalf("expected fallback slug")
+	}
+	if slug := noteSlug(strings.Repeat("word ", 30)); len(slug) > 60 || strings.HasSuffix(slug, "-") {
+		t.Fatalf("expected trimmed slug, got %q", slug)
+	}
+	published := time.Date(2024, 3, 5, 12, 0, 0, 0, time.Local)
+	if name := noteFileName(Article{Title: "Hello World", PublishedAt: published}); name != "2024-03-05-hello-world.md" {
+		t.Fatalf("unexpected file name %q", name)
+	}
+	if name := noteFileName(Article{Title: "Hello", FetchedAt: published}); name != "2024-03-05-hello.md" {
+		t.Fatalf("unexpected fetched file name %q", name)
+	}
+
+	home, _ := os.UserHomeDir()
+	if expandHome("~/vault") != filepath.Join(home, "vault") || expandHome("/tmp/vault") != "/tmp/vault" || expandHome("~other") != "~other" {
+		t.Fatalf("unexpected home expansion")
+	}
+
+	note := renderArticleNote(Article{Title: `Say "hi"`, URL: "https://example.com/a", FeedTitle: "Feed", Author: "Ann", PublishedAt: published}, "- one\n- two\n", []string{"rss", "go"})
+	for _, want := range []string{"---\ntitle: \"Say \\\"hi\\\"\"\nurl: \"https://example.com/a\"\ndate: ", "feed: \"Feed\"", "author: \"Ann\"", "tags: [\"rss\", \"go\"]", "summary: |\n  - one\n  - two\n---", "# Say \"hi\"", "## Summary\n\n- one\n- two", "[Read the article](https://example.com/a)"} {
+		if !strings.Contains(note, want) {
+			t.Fatalf("note missing %q:\n%s", want, note)
+		}
+	}
+	bare := renderArticleNote(Article{Title: "T", URL: "u"}, "", nil)
+	if strings.Contains(bare, "summary:") || strings.Contains(bare, "date:") || !strings.Contains(bare, "tags: []") {
+		t.Fatalf("unexpected bare note:\n%s", bare)
+	}
+}
+
+func TestAppExportNotes(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	published := time.Date(2024, 3, 5, 12, 0, 0, 0, time.Local)
+	articles, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "Starred Post", URL: "https://example.com/1", PublishedAt: published, IsStarred: true},
+		{GUID: "2", Title: "Other Post", URL: "https://example.com/2", PublishedAt: published.Add(-time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "- summary"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	if err := app.store.SetArticleTags(articles[0].ID, "topic", []string{"go", "rss"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.filter = FilterAll
+
+	if err := app.ExportSelectedNote(); err == nil || !strings.Contains(app.status, "notes_dir not configured") {
+		t.Fatalf("expected missing notes_dir error, got %v %q", err, app.status)
+	}
+	dir := filepath.Join(t.TempDir(), "vault")
+	app.config.NotesDir = dir
+	count, err := app.ExportStarredNotes()
+	if err != nil || count != 1 || app.status != "Wrote 1 notes" {
+		t.Fatalf("unexpected starred export %d %v %q", count, err, app.status)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "2024-03-05-starred-post.md"))
+	if err != nil {
+		t.Fatalf("read note: %v", err)
+	}
+	if !strings.Contains(string(data), "tags: [\"rss\", \"go\"]") || !strings.Contains(string(data), "summary: |\n  - summary") {
+		t.Fatalf("unexpected note:\n%s", data)
+	}
+
+	app.selectedIndex = 1
+	model := newTUIModel(app)
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	model = updated.(tuiModel)
+	if app.status != "Note written: 2024-03-05-other-post.md" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	var out bytes.Buffer
+	if err := handleCommand(app, "notes", &out); err != nil {
+		t.Fatalf("notes command error: %v", err)
+	}
+	if err := handleCommand(app, "note", &out); err != nil {
+		t.Fatalf("note command error: %v", err)
+	}
+
+	origWrite := notesWriteFile
+	notesWriteFile = func(string, []byte, os.FileMode) error { return errors.New("disk full") }
+	t.Cleanup(func() { notesWriteFile = origWrite })
+	if _, err := app.ExportStarredNotes(); err == nil || app.status != "Note export failed: disk full" {
+		t.Fatalf("expected write error, got %v %q", err, app.status)
+	}
+	origMkdir := notesMkdirAll
+	notesMkdirAll = func(string, os.FileMode) error { return errors.New("denied") }
+	t.Cleanup(func() { notesMkdirAll = origMkdir })
+	if _, err := app.ExportNotes(nil); err == nil {
+		t.Fatalf("expected mkdir error")
+	}
+
+	app.articles = nil
+	if err := app.ExportSelectedNote(); err != nil {
+		t.Fatalf("expected no-op without selection: %v", err)
+	}
+}
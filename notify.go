@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	notifyEventArticles = "articles"
+	notifyEventDigest   = "digest"
+	discordMessageLimit = 2000
+)
+
+type Notifier struct {
+	config         NotifierConfig
+	client         *http.Client
+	telegramOffset int64
+}
+
+var notifyTxnID = func() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+func NewNotifiers(cfg Config) []*Notifier {
+	names := make([]string, 0, len(cfg.Notifiers))
+	for name := range cfg.Notifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	notifiers := []*Notifier{}
+	for _, name := range names {
+		notifier := cfg.Notifiers[name]
+		notifier.Name = name
+		notifiers = append(notifiers, &Notifier{config: notifier, client: &http.Client{Timeout: 30 * time.Second}})
+	}
+	return notifiers
+}
+
+func (n *Notifier) wants(event string) bool {
+	if len(n.config.Events) == 0 {
+		return true
+	}
+	for _, item := range n.config.Events {
+		if item == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Notifier) matches(article Article, feed Feed) bool {
+	if len(n.config.Feeds) == 0 {
+		return true
+	}
+	for _, rule := range n.config.Feeds {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if rule == feed.URL || strings.Contains(strings.ToLower(firstNonEmpty(feed.Title, article.FeedTitle)), strings.ToLower(rule)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Notifier) Send(text string) error {
+	switch n.config.Type {
+	case "slack":
+		return n.postJSON(http.MethodPost, n.config.WebhookURL, map[string]string{"text": text}, "")
+	case "discord":
+		if len(text) > discordMessageLimit {
+			text = truncateText(text, discordMessageLimit-3) + "..."
+		}
+		return n.postJSON(http.MethodPost, n.config.WebhookURL, map[string]string{"content": text}, "")
+	case "telegram":
+		if len(text) > telegramMessageLimit {
+			text = truncateText(text, telegramMessageLimit-3) + "..."
+		}
+		return n.postJSON(http.MethodPost, n.telegramEndpoint("sendMessage"), map[string]string{"chat_id": n.config.ChatID, "text": text}, "")
+	case "matrix":
+		endpoint := strings.TrimRight(n.config.Homeserver, "/") + "/_matrix/client/v3/rooms/" + url.PathEscape(n.config.RoomID) + "/send/m.room.message/" + notifyTxnID()
+		return n.postJSON(http.MethodPut, endpoint, map[string]string{"msgtype": "m.text", "body": text}, n.config.AccessToken)
+	}
+	return fmt.Errorf("unknown notifier type: %q", n.config.Type)
+}
+
+func (n *Notifier) postJSON(method string, endpoint string, payload any, token string) error {
+	blob, err := servicesJSONMarshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(blob))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	if token != "" {
+		req.Header.Set("authorization", "Bearer "+token)
+	}
+	log := logFor("notify").With("notifier", n.config.Name, "type", n.config.Type)
+	start := time.Now()
+	resp, err := n.client.Do(req)
+	if err != nil {
+		log.Warn("send failed", "duration", time.Since(start), "err", err)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Warn("send failed", "duration", time.Since(start), "status", resp.StatusCode)
+		return fmt.Errorf("notify %s: http %d", n.config.Name, resp.StatusCode)
+	}
+	log.Info("sent", "duration", time.Since(start))
+	return nil
+}
+
+func (a *App) NotifyNewArticles(articles []Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	feeds := map[int]Feed{}
+	for _, feed := range a.feeds {
+		feeds[feed.ID] = feed
+	}
+	var errs []error
+	for _, notifier := range a.notifiers {
+		if !notifier.wants(notifyEventArticles) {
+			continue
+		}
+		matched := []Article{}
+		for _, article := range articles {
+			if notifier.matches(article, feeds[article.FeedID]) {
+				matched = append(matched, article)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		message := formatNewArticlesMessage(matched)
+		if notifier.config.Type == "telegram" {
+			message = formatTelegramArticlesMessage(matched)
+		}
+		if err := notifier.Send(message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *App) NotifyDigest(digest Digest) error {
+	var errs []error
+	for _, notifier := range a.notifiers {
+		if !notifier.wants(notifyEventDigest) {
+			continue
+		}
+		if err := notifier.Send(renderDigestMarkdown(digest)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func formatNewArticlesMessage(articles []Article) string {
+	lines := []string{fmt.Sprintf("%d new articles:", len(articles))}
+	for _, article := range articles {
+		line := "- " + article.Title
+		if article.FeedTitle != "" {
+			line += " (" + article.FeedTitle + ")"
+		}
+		if article.URL != "" {
+			line += " " + article.URL
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type notifyRequest struct {
+	method string
+	path   string
+	auth   string
+	body   map[string]string
+}
+
+func notifyTestClient(requests *[]notifyRequest, status int) *http.Client {
+	return &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		body := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		*requests = append(*requests, notifyRequest{method: r.Method, path: r.URL.Path, auth: r.Header.Get("authorization"), body: body})
+		return newResponse(status, "{}", nil, r), nil
+	})}
+}
+
+func TestNotifierSend(t *testing.T) {
+	requests := []notifyRequest{}
+	client := notifyTestClient(&requests, http.StatusOK)
+	origTxn := notifyTxnID
+	notifyTxnID = func() string { return "txn1" }
+	defer func() { notifyTxnID = origTxn }()
+
+	slack := &Notifier{config: NotifierConfig{Name: "s", Type: "slack", WebhookURL: "http://hooks.test/slack"}, client: client}
+	discord := &Notifier{config: NotifierConfig{Name: "d", Type: "discord", WebhookURL: "http://hooks.test/discord"}, client: client}
+	matrix := &Notifier{config: NotifierConfig{Name: "m", Type: "matrix", Homeserver: "http://matrix.test/", RoomID: "!room:matrix.test", AccessToken: "tok"}, client: client}
+	for _, notifier := range []*Notifier{slack, discord, matrix} {
+		if err := notifier.Send("hello"); err != nil {
+			t.Fatalf("Send %s error: %v", notifier.config.Type, err)
+		}
+	}
+	if requests[0].body["text"] != "hello" || requests[0].path != "/slack" {
+		t.Fatalf("unexpected slack request %+v", requests[0])
+	}
+	if requests[1].body["content"] != "hello" {
+		t.Fatalf("unexpected discord request %+v", requests[1])
+	}
+	if requests[2].method != http.MethodPut || requests[2].path != "/_matrix/client/v3/rooms/!room:matrix.test/send/m.room.message/txn1" || requests[2].auth != "Bearer tok" || requests[2].body["msgtype"] != "m.text" {
+		t.Fatalf("unexpected matrix request %+v", requests[2])
+	}
+	if err := discord.Send(strings.Repeat("x", 2500)); err != nil || len(requests[3].body["content"]) != discordMessageLimit {
+		t.Fatalf("expected discord message truncated, got %d %v", len(requests[3].body["content"]), err)
+	}
+
+	unknown := &Notifier{config: NotifierConfig{Type: "irc"}, client: client}
+	if err := unknown.Send("x"); err == nil {
+		t.Fatalf("expected unknown type error")
+	}
+	failing := &Notifier{config: NotifierConfig{Name: "f", Type: "slack", WebhookURL: "http://hooks.test"}, client: notifyTestClient(&requests, http.StatusInternalServerError)}
+	if err := failing.Send("x"); err == nil || err.Error() != "notify f: http 500" {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	failing.client = &http.Client{Transport: &errorRoundTripper{}}
+	if err := failing.Send("x"); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	failing.config.WebhookURL = "http://[::1"
+	if err := failing.Send("x"); err == nil {
+		t.Fatalf("expected request error")
+	}
+	orig := servicesJSONMarshal
+	servicesJSONMarshal = func(any) ([]byte, error) { return nil, errors.New("marshal") }
+	defer func() { servicesJSONMarshal = orig }()
+	if err := slack.Send("x"); err == nil {
+		t.Fatalf("expected marshal error")
+	}
+}
+
+func TestNotifierRouting(t *testing.T) {
+	notifier := &Notifier{config: NotifierConfig{Feeds: []string{"https://go.dev/blog/feed.atom", "security", " "}, Events: []string{notifyEventDigest}}}
+	if !notifier.matches(Article{}, Feed{URL: "https://go.dev/blog/feed.atom"}) || !notifier.matches(Article{FeedTitle: "Krebs on Security"}, Feed{}) {
+		t.Fatalf("expected routing rules to match")
+	}
+	if notifier.matches(Article{FeedTitle: "Cooking"}, Feed{URL: "https://food.example/rss"}) {
+		t.Fatalf("expected unrelated feed to be skipped")
+	}
+	if notifier.wants(notifyEventArticles) || !notifier.wants(notifyEventDigest) {
+		t.Fatalf("unexpected event filter")
+	}
+	if all := (&Notifier{}); !all.matches(Article{}, Feed{}) || !all.wants(notifyEventArticles) {
+		t.Fatalf("expected empty rules to match everything")
+	}
+}
+
+func TestAppNotify(t *testing.T) {
+	app := newTUIApp(t)
+	app.feeds = []Feed{{ID: 1, Title: "Go Blog", URL: "https://go.dev/feed"}, {ID: 2, Title: "Cooking", URL: "https://food.example/rss"}}
+	requests := []notifyRequest{}
+	client := notifyTestClient(&requests, http.StatusOK)
+	app.config.Notifiers = map[string]NotifierConfig{
+		"go":      {Type: "slack", WebhookURL: "http://hooks.test/go", Feeds: []string{"go blog"}},
+		"digests": {Type: "discord", WebhookURL: "http://hooks.test/digest", Events: []string{notifyEventDigest}},
+	}
+	app.notifiers = NewNotifiers(app.config)
+	if len(app.notifiers) != 2 || app.notifiers[0].config.Name != "digests" {
+		t.Fatalf("expected notifiers sorted by name: %+v", app.notifiers)
+	}
+	for _, notifier := range app.notifiers {
+		notifier.client = client
+	}
+	if err := app.NotifyNewArticles(nil); err != nil || len(requests) != 0 {
+		t.Fatalf("expected no notifications for no articles")
+	}
+	articles := []Article{
+		{FeedID: 1, Title: "Go 1.30", FeedTitle: "Go Blog", URL: "https://go.dev/1"},
+		{FeedID: 2, Title: "Soup", FeedTitle: "Cooking", URL: "https://food.example/soup"},
+	}
+	if err := app.NotifyNewArticles(articles); err != nil {
+		t.Fatalf("NotifyNewArticles error: %v", err)
+	}
+	if len(requests) != 1 || requests[0].body["text"] != "1 new articles:\n- Go 1.30 (Go Blog) https://go.dev/1" {
+		t.Fatalf("unexpected article notifications %+v", requests)
+	}
+	if err := app.NotifyNewArticles(articles[1:]); err != nil || len(requests) != 1 {
+		t.Fatalf("expected unmatched articles to be skipped")
+	}
+	if err := app.NotifyDigest(Digest{Content: "## Tech\nThings."}); err != nil {
+		t.Fatalf("NotifyDigest error: %v", err)
+	}
+	if len(requests) != 3 || requests[1].path != "/digest" || requests[2].path != "/go" || !strings.Contains(requests[1].body["content"], "Things.") {
+		t.Fatalf("unexpected digest notifications %+v", requests)
+	}
+	for _, notifier := range app.notifiers {
+		notifier.client = notifyTestClient(&requests, http.StatusBadGateway)
+	}
+	if err := app.NotifyNewArticles(articles); err == nil {
+		t.Fatalf("expected article notify error")
+	}
+	if err := app.NotifyDigest(Digest{}); err == nil {
+		t.Fatalf("expected digest notify error")
+	}
+	if got := formatNewArticlesMessage([]Article{{Title: "Bare"}}); got != "1 new articles:\n- Bare" {
+		t.Fatalf("unexpected bare message %q", got)
+	}
+}
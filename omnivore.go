@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const omnivoreSaveURLMutation = `mutation SaveUrl($input: SaveUrlInput!) {
+  saveUrl(input: $input) {
+    ... on SaveSuccess { url clientRequestId }
+    ... on SaveError { errorCodes message }
+  }
+}`
+
+type OmnivoreClient struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+var omnivoreRequestID = func() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func NewOmnivoreClient(apiKey, baseURL string) *OmnivoreClient {
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return nil
+	}
+	base := strings.TrimSpace(baseURL)
+	if base == "" {
+		base = "https://api-prod.omnivore.app"
+	}
+	return &OmnivoreClient{
+		baseURL: strings.TrimRight(base, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (o *OmnivoreClient) Save(item RaindropItem) (int, error) {
+	if o == nil {
+		return 0, errors.New("omnivore not configured")
+	}
+	labels := []map[string]string{}
+	for _, tag := range item.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			labels = append(labels, map[string]string{"name": tag})
+		}
+	}
+	blob, err := servicesJSONMarshal(map[string]any{
+		"query": omnivoreSaveURLMutation,
+		"variables": map[string]any{
+			"input": map[string]any{
+				"url":             item.Link,
+				"source":          "api",
+				"clientRequestId": omnivoreRequestID(),
+				"labels":          labels,
+			},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest(http.MethodPost, o.baseURL+"/api/graphql", bytes.NewReader(blob))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", o.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("omnivore http %d", resp.StatusCode)
+	}
+	var parsed struct {
+		Data struct {
+			SaveURL struct {
+				URL        string   `json:"url"`
+				ErrorCodes []string `json:"errorCodes"`
+				Message    string   `json:"message"`
+			} `json:"saveUrl"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return 0, err
+	}
+	if len(parsed.Errors) > 0 {
+		return 0, errors.New("omnivore: " + parsed.Errors[0].Message)
+	}
+	if result := parsed.Data.SaveURL; len(result.ErrorCodes) > 0 {
+		return 0, errors.New("omnivore: " + firstNonEmpty(result.Message, strings.Join(result.ErrorCodes, ", ")))
+	}
+	return 0, nil
+}
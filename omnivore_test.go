@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestOmnivoreClient(t *testing.T) {
+	if NewOmnivoreClient(" ", "") != nil {
+		t.Fatalf("expected nil client without api key")
+	}
+	if client := NewOmnivoreClient("key", ""); client.baseURL != "https://api-prod.omnivore.app" {
+		t.Fatalf("unexpected default base url %q", client.baseURL)
+	}
+	orig := omnivoreRequestID
+	omnivoreRequestID = func() string { return "req-1" }
+	t.Cleanup(func() { omnivoreRequestID = orig })
+	client := NewOmnivoreClient("key", "http://omnivore.test/")
+	var payload struct {
+		Query     string `json:"query"`
+		Variables struct {
+			Input struct {
+				URL             string              `json:"url"`
+				Source          string              `json:"source"`
+				ClientRequestID string              `json:"clientRequestId"`
+				Labels          []map[string]string `json:"labels"`
+			} `json:"input"`
+		} `json:"variables"`
+	}
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/api/graphql" || r.Header.Get("Authorization") != "key" {
+			return newResponse(http.StatusUnauthorized, "", nil, r), nil
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("bad payload %s", body)
+		}
+		return newResponse(http.StatusOK, `{"data":{"saveUrl":{"url":"https://omnivore.app/me/x","clientRequestId":"req-1"}}}`, nil, r), nil
+	})}
+	if _, err := client.Save(RaindropItem{Link: "https://example.com", Tags: []string{"go", " ", "ai"}}); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	input := payload.Variables.Input
+	if !strings.Contains(payload.Query, "saveUrl") || input.URL != "https://example.com" || input.Source != "api" || input.ClientRequestID != "req-1" || len(input.Labels) != 2 || input.Labels[1]["name"] != "ai" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	client.client = clientForResponse(http.StatusOK, `{"data":{"saveUrl":{"errorCodes":["UNAUTHORIZED"],"message":""}}}`, nil)
+	if _, err := client.Save(RaindropItem{}); err == nil || err.Error() != "omnivore: UNAUTHORIZED" {
+		t.Fatalf("expected save error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusOK, `{"errors":[{"message":"bad query"}]}`, nil)
+	if _, err := client.Save(RaindropItem{}); err == nil || err.Error() != "omnivore: bad query" {
+		t.Fatalf("expected graphql error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusBadGateway, "", nil)
+	if _, err := client.Save(RaindropItem{}); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusOK, "nope", nil)
+	if _, err := client.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected decode error")
+	}
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if _, err := client.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	var nilClient *OmnivoreClient
+	if _, err := nilClient.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+	if id := orig(); len(id) != 36 || id[14] != '4' {
+		t.Fatalf("expected uuid v4, got %q", id)
+	}
+}
+
+func TestAppSaveToOmnivore(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	if err := app.SaveBookmarkTo("omnivore", nil); err == nil || err.Error() != "omnivore not configured" {
+		t.Fatalf("expected not configured error, got %v", err)
+	}
+	app.omnivore = &OmnivoreClient{baseURL: "http://omnivore.test", apiKey: "k", client: clientForResponse(http.StatusOK, `{"data":{"saveUrl":{"url":"u"}}}`, nil)}
+	if targets := app.ShareTargets(); len(targets) != 1 || targets[0] != "omnivore" || app.targetName("omnivore") != "Omnivore" {
+		t.Fatalf("unexpected share targets %v", targets)
+	}
+	if err := handleCommand(app, "share omnivore go,rss", io.Discard); err != nil || app.status != "Saved to Omnivore" || app.store.SavedCount() != 1 {
+		t.Fatalf("expected omnivore share, got %v %q", err, app.status)
+	}
+	app.omnivore.client = clientForResponse(http.StatusInternalServerError, "", nil)
+	if err := app.SaveToOmnivore(nil); err == nil {
+		t.Fatalf("expected omnivore error")
+	}
+	app.articles = nil
+	if err := app.SaveToOmnivore(nil); err != nil {
+		t.Fatalf("expected no-op without selection, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var openerStart = func(name string, args []string) error {
+	return execCommand(name, args...).Start()
+}
+
+func parseOpenersSection(pattern string, value string, cfg *Config) error {
+	pattern = strings.ToLower(trimQuotes(pattern))
+	if pattern == "" || pattern == ":" {
+		return fmt.Errorf("invalid opener pattern: %q", pattern)
+	}
+	command := trimQuotes(value)
+	if _, err := splitCommandLine(command); err != nil {
+		return fmt.Errorf("invalid opener for %s: %w", pattern, err)
+	}
+	if cfg.Openers == nil {
+		cfg.Openers = map[string]string{}
+	}
+	cfg.Openers[pattern] = command
+	return nil
+}
+
+func splitCommandLine(command string) ([]string, error) {
+	args := []string{}
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+	return args, nil
+}
+
+func openerFor(cfg Config, target string) string {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return cfg.Browser
+	}
+	if command, ok := cfg.Openers[strings.ToLower(parsed.Scheme)+":"]; ok && parsed.Scheme != "" {
+		return command
+	}
+	host := strings.ToLower(parsed.Hostname())
+	best := ""
+	for pattern := range cfg.Openers {
+		if strings.HasSuffix(pattern, ":") || len(pattern) <= len(best) {
+			continue
+		}
+		if host == pattern || strings.HasSuffix(host, "."+pattern) {
+			best = pattern
+		}
+	}
+	if best != "" {
+		return cfg.Openers[best]
+	}
+	return cfg.Browser
+}
+
+func runOpener(command string, target string) error {
+	args, err := splitCommandLine(command)
+	if err != nil {
+		return err
+	}
+	substituted := false
+	for i, arg := range args[1:] {
+		if strings.Contains(arg, "{url}") {
+			args[i+1] = strings.ReplaceAll(arg, "{url}", target)
+			substituted = true
+		}
+	}
+	if !substituted {
+		args = append(args, target)
+	}
+	return openerStart(args[0], args[1:])
+}
+
+func (a *App) openLink(target string) error {
+	if target == "" {
+		return errors.New("empty url")
+	}
+	if command := strings.TrimSpace(openerFor(a.config, target)); command != "" {
+		return runOpener(command, target)
+	}
+	return a.openURL(target)
+}
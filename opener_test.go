@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	args, err := splitCommandLine(`mpv --ytdl-format="best[height<=720]" '{url}'  extra`)
+	if err != nil || !reflect.DeepEqual(args, []string{"mpv", "--ytdl-format=best[height<=720]", "{url}", "extra"}) {
+		t.Fatalf("unexpected args %q %v", args, err)
+	}
+	if args, err := splitCommandLine(`"/Applications/Firefox Nightly.app/firefox" ""`); err != nil || !reflect.DeepEqual(args, []string{"/Applications/Firefox Nightly.app/firefox", ""}) {
+		t.Fatalf("unexpected quoted args %q %v", args, err)
+	}
+	if _, err := splitCommandLine(`mpv "oops`); err == nil {
+		t.Fatalf("expected unterminated quote error")
+	}
+	if _, err := splitCommandLine("  "); err == nil {
+		t.Fatalf("expected empty command error")
+	}
+}
+
+func TestOpenerFor(t *testing.T) {
+	cfg := Config{
+		Browser: "firefox",
+		Openers: map[string]string{
+			"youtube.com":       "mpv",
+			"music.youtube.com": "spotify-ish",
+			"gemini:":           "lagrange",
+			"youtu.be":          "mpv --no-video",
+		},
+	}
+	cases := map[string]string{
+		"https://www.youtube.com/watch?v=1":   "mpv",
+		"https://YouTube.com/watch?v=1":       "mpv",
+		"https://music.youtube.com/watch?v=1": "spotify-ish",
+		"https://youtu.be/abc":                "mpv --no-video",
+		"gemini://example.org/":               "lagrange",
+		"https://notyoutube.com/":             "firefox",
+		"https://example.com/":                "firefox",
+		"::not a url":                         "firefox",
+	}
+	for target, want := range cases {
+		if got := openerFor(cfg, target); got != want {
+			t.Fatalf("openerFor(%q) = %q, want %q", target, got, want)
+		}
+	}
+	if got := openerFor(Config{}, "https://example.com"); got != "" {
+		t.Fatalf("expected system opener, got %q", got)
+	}
+}
+
+func TestOpenLink(t *testing.T) {
+	app := newTUIApp(t)
+	opened := []string{}
+	app.openURL = func(target string) error {
+		opened = append(opened, target)
+		return nil
+	}
+	orig := openerStart
+	t.Cleanup(func() { openerStart = orig })
+	started := [][]string{}
+	openerStart = func(name string, args []string) error {
+		started = append(started, append([]string{name}, args...))
+		return nil
+	}
+
+	if err := app.openLink("https://example.com/a"); err != nil || len(opened) != 1 || len(started) != 0 {
+		t.Fatalf("expected system opener, got %v %v %v", err, opened, started)
+	}
+	app.config.Browser = "firefox --new-tab"
+	app.config.Openers = map[string]string{"youtube.com": "mpv --title={url} -- {url}"}
+	if err := app.openLink("https://example.com/b"); err != nil {
+		t.Fatalf("openLink error: %v", err)
+	}
+	if err := app.openLink("https://www.youtube.com/watch?v=1;rm -rf"); err != nil {
+		t.Fatalf("openLink error: %v", err)
+	}
+	want := [][]string{
+		{"firefox", "--new-tab", "https://example.com/b"},
+		{"mpv", "--title=https://www.youtube.com/watch?v=1;rm -rf", "--", "https://www.youtube.com/watch?v=1;rm -rf"},
+	}
+	if !reflect.DeepEqual(started, want) || len(opened) != 1 {
+		t.Fatalf("unexpected commands %q", started)
+	}
+	if err := app.openLink(""); err == nil {
+		t.Fatalf("expected empty url error")
+	}
+	openerStart = func(string, []string) error { return errors.New("not found") }
+	if err := app.openLink("https://example.com"); err == nil {
+		t.Fatalf("expected start error")
+	}
+	app.config.Browser = `firefox "`
+	if err := app.openLink("https://example.com"); err == nil {
+		t.Fatalf("expected split error")
+	}
+}
+
+func TestParseConfigOpeners(t *testing.T) {
+	cfg := DefaultConfig()
+	input := strings.Join([]string{
+		`browser = "firefox --new-tab {url}"`,
+		"[openers]",
+		`"YouTube.com" = "mpv {url}"`,
+		`gemini: = "lagrange"`,
+	}, "\n")
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.Browser != "firefox --new-tab {url}" || cfg.Openers["youtube.com"] != "mpv {url}" || cfg.Openers["gemini:"] != "lagrange" {
+		t.Fatalf("unexpected openers %q %v", cfg.Browser, cfg.Openers)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil {
+		t.Fatalf("parse rendered config error: %v", err)
+	}
+	if reparsed.Browser != cfg.Browser || !reflect.DeepEqual(reparsed.Openers, cfg.Openers) {
+		t.Fatalf("openers did not round trip: %v", reparsed.Openers)
+	}
+	for _, bad := range []string{
+		`browser = "firefox '"`,
+		"[openers]\n\"\" = \"mpv\"",
+		"[openers]\n\":\" = \"mpv\"",
+		"[openers]\n\"youtube.com\" = \"\"",
+	} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
@@ -17,9 +17,9 @@ type opmlBody struct {
 type opmlOutline struct {
 	Text        string        `xml:"text,attr"`
 	Title       string        `xml:"title,attr"`
-	Type        string        `xml:"type,attr"`
-	XMLURL      string        `xml:"xmlUrl,attr"`
-	HTMLURL     string        `xml:"htmlUrl,attr"`
+	Type        string        `xml:"type,attr,omitempty"`
+	XMLURL      string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL     string        `xml:"htmlUrl,attr,omitempty"`
 	Children    []opmlOutline `xml:"outline"`
 }
 
@@ -37,14 +37,14 @@ func ParseOPML(path string) ([]Feed, error) {
 		return nil, err
 	}
 	feeds := []Feed{}
-	collectOpml(&feeds, doc.Body.Outlines)
+	collectOpml(&feeds, doc.Body.Outlines, "")
 	if len(feeds) == 0 {
 		return nil, errors.New("no feeds found in OPML")
 	}
 	return feeds, nil
 }
 
-func collectOpml(feeds *[]Feed, outlines []opmlOutline) {
+func collectOpml(feeds *[]Feed, outlines []opmlOutline, folder string) {
 	for _, outline := range outlines {
 		if outline.XMLURL != "" {
 			feed := Feed{
@@ -52,25 +52,46 @@ func collectOpml(feeds *[]Feed, outlines []opmlOutline) {
 				URL:         outline.XMLURL,
 				SiteURL:     outline.HTMLURL,
 				Description: "",
+				Folder:      folder,
 			}
 			*feeds = append(*feeds, feed)
 		}
 		if len(outline.Children) > 0 {
-			collectOpml(feeds, outline.Children)
+			childFolder := folder
+			if outline.XMLURL == "" {
+				childFolder = normalizeFolder(folder + folderSeparator + firstNonEmpty(outline.Title, outline.Text))
+			}
+			collectOpml(feeds, outline.Children, childFolder)
 		}
 	}
 }
 
+func insertOutline(outlines []opmlOutline, path []string, outline opmlOutline) []opmlOutline {
+	if len(path) == 0 {
+		return append(outlines, outline)
+	}
+	for i := range outlines {
+		if outlines[i].XMLURL == "" && outlines[i].Title == path[0] {
+			outlines[i].Children = insertOutline(outlines[i].Children, path[1:], outline)
+			return outlines
+		}
+	}
+	group := opmlOutline{Title: path[0], Text: path[0]}
+	group.Children = insertOutline(nil, path[1:], outline)
+	return append(outlines, group)
+}
+
 func ExportOPML(path string, feeds []Feed) error {
 	outlines := make([]opmlOutline, 0, len(feeds))
 	for _, feed := range feeds {
-		outlines = append(outlines, opmlOutline{
+		outline := opmlOutline{
 			Title:   feed.Title,
 			Text:    feed.Title,
 			Type:    "rss",
 			XMLURL:  feed.URL,
 			HTMLURL: feed.SiteURL,
-		})
+		}
+		outlines = insertOutline(outlines, folderPath(feed.Folder), outline)
 	}
 	doc := opmlDocument{Body: opmlBody{Outlines: outlines}}
 	data, err := opmlMarshal(doc)
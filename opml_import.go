@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type opmlImportReport struct {
+	New       []Feed
+	Duplicate []Feed
+	Invalid   []opmlInvalidFeed
+}
+
+type opmlInvalidFeed struct {
+	Feed   Feed
+	Reason string
+}
+
+func (a *App) PlanOPMLImport(path string) (opmlImportReport, error) {
+	feeds, err := ParseOPML(path)
+	if err != nil {
+		return opmlImportReport{}, err
+	}
+	seen := map[string]bool{}
+	for _, feed := range a.store.Feeds() {
+		seen[feedURLKey(feed.URL)] = true
+	}
+	report := opmlImportReport{}
+	for _, feed := range feeds {
+		feed.URL = strings.TrimSpace(feed.URL)
+		if reason := invalidFeedURL(feed.URL); reason != "" {
+			report.Invalid = append(report.Invalid, opmlInvalidFeed{Feed: feed, Reason: reason})
+			continue
+		}
+		key := feedURLKey(feed.URL)
+		if seen[key] {
+			report.Duplicate = append(report.Duplicate, feed)
+			continue
+		}
+		seen[key] = true
+		report.New = append(report.New, feed)
+	}
+	return report, nil
+}
+
+func (a *App) ImportOPMLReport(path string) (opmlImportReport, error) {
+	report, err := a.PlanOPMLImport(path)
+	if err != nil {
+		return report, err
+	}
+	added := []Feed{}
+	for _, feed := range report.New {
+		if _, err := a.store.InsertFeed(feed); err != nil {
+			report.Invalid = append(report.Invalid, opmlInvalidFeed{Feed: feed, Reason: err.Error()})
+			continue
+		}
+		added = append(added, feed)
+	}
+	report.New = added
+	a.feeds = a.store.Feeds()
+	if err := a.RefreshFeeds(); err != nil {
+		return report, err
+	}
+	a.status = tr(msgOPMLImported, len(report.New), len(report.Duplicate), len(report.Invalid))
+	return report, nil
+}
+
+func (a *App) ImportOPML(path string) error {
+	_, err := a.ImportOPMLReport(path)
+	return err
+}
+
+func parseImportArgs(args []string) (string, bool) {
+	path, dryRun := "", false
+	for _, arg := range args {
+		if arg == "--dry-run" {
+			dryRun = true
+		} else if path == "" {
+			path = arg
+		}
+	}
+	return path, dryRun
+}
+
+func invalidFeedURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return tr(msgOPMLInvalidURL)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return tr(msgOPMLInvalidScheme)
+	}
+	if parsed.Host == "" {
+		return tr(msgOPMLInvalidHost)
+	}
+	return ""
+}
+
+func feedURLKey(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return raw
+	}
+	key := strings.ToLower(parsed.Host) + strings.TrimRight(parsed.Path, "/")
+	if parsed.RawQuery != "" {
+		key += "?" + parsed.RawQuery
+	}
+	return key
+}
+
+func (r opmlImportReport) summary() string {
+	return tr(msgOPMLReportSummary, len(r.New), len(r.Duplicate), len(r.Invalid))
+}
+
+func (r opmlImportReport) lines() []string {
+	lines := []string{}
+	section := func(title string, feeds []Feed) {
+		if len(feeds) == 0 {
+			return
+		}
+		lines = append(lines, fmt.Sprintf("%s (%d):", title, len(feeds)))
+		for _, feed := range feeds {
+			lines = append(lines, "  "+formatOPMLFeed(feed))
+		}
+	}
+	section("New", r.New)
+	section("Duplicate", r.Duplicate)
+	if len(r.Invalid) > 0 {
+		lines = append(lines, fmt.Sprintf("Invalid (%d):", len(r.Invalid)))
+		for _, invalid := range r.Invalid {
+			lines = append(lines, "  "+formatOPMLFeed(invalid.Feed)+": "+invalid.Reason)
+		}
+	}
+	return lines
+}
+
+func formatOPMLFeed(feed Feed) string {
+	line := feed.Title + " <" + feed.URL + ">"
+	if feed.Folder != "" {
+		line += " [" + feed.Folder + "]"
+	}
+	return line
+}
+
+func formatOPMLReport(report opmlImportReport) string {
+	return strings.Join(append(report.lines(), report.summary()), "\n")
+}
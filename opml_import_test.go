@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const opmlImportSample = `<?xml version="1.0"?>
+<opml version="2.0"><body>
+  <outline text="Tech" title="Tech" type="rss" xmlUrl="https://EXAMPLE.com/tech/"/>
+  <outline text="Work">
+    <outline text="Fresh" title="Fresh" type="rss" xmlUrl="http://fresh.example.com/rss"/>
+    <outline text="Again" title="Again" type="rss" xmlUrl="http://fresh.example.com/rss"/>
+  </outline>
+  <outline text="Local" title="Local" type="rss" xmlUrl="file:///etc/passwd"/>
+  <outline text="Hostless" title="Hostless" type="rss" xmlUrl="https:///rss"/>
+</body></opml>`
+
+func writeOPMLImportSample(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "feeds.opml")
+	if err := os.WriteFile(path, []byte(opmlImportSample), 0o644); err != nil {
+		t.Fatalf("write opml error: %v", err)
+	}
+	return path
+}
+
+func TestPlanOPMLImport(t *testing.T) {
+	app, _, _ := newFolderApp(t)
+	report, err := app.PlanOPMLImport(writeOPMLImportSample(t))
+	if err != nil {
+		t.Fatalf("PlanOPMLImport error: %v", err)
+	}
+	if len(report.New) != 1 || report.New[0].Title != "Fresh" || report.New[0].Folder != "Work" {
+		t.Fatalf("unexpected new feeds %+v", report.New)
+	}
+	if len(report.Duplicate) != 2 || report.Duplicate[0].Title != "Tech" || report.Duplicate[1].Title != "Again" {
+		t.Fatalf("unexpected duplicates %+v", report.Duplicate)
+	}
+	if len(report.Invalid) != 2 || report.Invalid[0].Reason != "not an http or https URL" || report.Invalid[1].Reason != "missing host" {
+		t.Fatalf("unexpected invalid feeds %+v", report.Invalid)
+	}
+	if len(app.store.Feeds()) != 2 {
+		t.Fatalf("expected dry run to leave feeds alone")
+	}
+	text := formatOPMLReport(report)
+	for _, want := range []string{"New (1):\n  Fresh <http://fresh.example.com/rss> [Work]", "Duplicate (2):", "Local <file:///etc/passwd>: not an http or https URL", "1 new, 2 duplicate, 2 invalid"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in report:\n%s", want, text)
+		}
+	}
+	if _, err := app.PlanOPMLImport(filepath.Join(t.TempDir(), "missing.opml")); err == nil {
+		t.Fatalf("expected missing file error")
+	}
+	if reason := invalidFeedURL("http://[::1"); reason != "not a valid URL" {
+		t.Fatalf("unexpected reason %q", reason)
+	}
+}
+
+func TestImportOPMLReport(t *testing.T) {
+	app, _, _ := newFolderApp(t)
+	app.fetcher = &FeedFetcher{client: clientForResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"})}
+	path := writeOPMLImportSample(t)
+	var out bytes.Buffer
+	if err := handleCommand(app, "import --dry-run "+path, &out); err != nil || !strings.Contains(out.String(), "1 new, 2 duplicate, 2 invalid") {
+		t.Fatalf("unexpected dry run %q %v", out.String(), err)
+	}
+	if len(app.store.Feeds()) != 2 {
+		t.Fatalf("expected dry run to leave feeds alone")
+	}
+	if err := handleCommand(app, "import "+path, io.Discard); err != nil {
+		t.Fatalf("import error: %v", err)
+	}
+	if app.status != "Imported 1 feeds (2 duplicate, 2 invalid)" || len(app.feeds) != 3 {
+		t.Fatalf("unexpected import %q %d", app.status, len(app.feeds))
+	}
+	report, err := app.ImportOPMLReport(path)
+	if err != nil || len(report.New) != 0 || len(report.Duplicate) != 3 {
+		t.Fatalf("expected everything duplicate on second import, got %+v %v", report, err)
+	}
+	if err := handleCommand(app, "import --dry-run", io.Discard); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+}
+
+func TestRunMainImportDryRun(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+	path := writeOPMLImportSample(t)
+	var stdout, stderr bytes.Buffer
+	if err := runMain([]string{"--import", "--dry-run", path}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "2 new, 1 duplicate, 2 invalid") || strings.Contains(stdout.String(), "Imported feeds") {
+		t.Fatalf("unexpected dry run output %q", stdout.String())
+	}
+	if err := runMain([]string{"--import", path, "--dry-run"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain error: %v", err)
+	}
+	if err := runMain([]string{"--import", "--dry-run", filepath.Join(root, "missing.opml")}, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Fatalf("expected missing file error")
+	}
+}
+
+func TestTUIImportReport(t *testing.T) {
+	app, _, _ := newFolderApp(t)
+	app.fetcher = &FeedFetcher{client: clientForResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"})}
+	model := newTUIModel(app)
+	model.width, model.height = 120, 30
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(tuiModel)
+	}
+	path := writeOPMLImportSample(t)
+	for _, cancel := range []bool{true, false} {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+		model.input.SetValue(path)
+		press(tea.KeyMsg{Type: tea.KeyEnter})
+		if model.importPlan == nil || !strings.Contains(model.View(), "Fresh <http://fresh.example.com/rss>") {
+			t.Fatalf("expected import report:\n%s", model.View())
+		}
+		if cancel {
+			press(tea.KeyMsg{Type: tea.KeyEsc})
+			if model.importPlan != nil || len(model.app.store.Feeds()) != 2 {
+				t.Fatalf("expected import cancelled")
+			}
+			continue
+		}
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+		press(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	if model.importPlan != nil || len(model.app.feeds) != 3 {
+		t.Fatalf("expected feeds imported, got %d", len(model.app.feeds))
+	}
+}
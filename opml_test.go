@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -108,3 +109,85 @@ func TestOPMLWriteError(t *testing.T) {
 		t.Fatalf("expected write error")
 	}
 }
+
+func TestOPMLFolders(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "folders.opml")
+	feeds := []Feed{
+		{Title: "Tech", URL: "https://example.com/tech", Folder: "Work"},
+		{Title: "News", URL: "https://example.com/news"},
+		{Title: "Ops", URL: "https://example.com/ops", Folder: "Work"},
+	}
+	if err := ExportOPML(path, feeds); err != nil {
+		t.Fatalf("ExportOPML error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if !strings.Contains(string(data), `<outline text="Work" title="Work">`) || strings.Count(string(data), `xmlUrl=`) != 3 {
+		t.Fatalf("unexpected opml:\n%s", data)
+	}
+	parsed, err := ParseOPML(path)
+	if err != nil {
+		t.Fatalf("ParseOPML error: %v", err)
+	}
+	if len(parsed) != 3 || parsed[0].Folder != "Work" || parsed[1].Folder != "Work" || parsed[2].Folder != "" {
+		t.Fatalf("unexpected parsed feeds: %+v", parsed)
+	}
+}
+
+func TestOPMLNestedFolderRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "nested.opml")
+	content := `<?xml version="1.0"?>
+<opml version="2.0">
+  <body>
+    <outline text="Tech">
+      <outline text="Go" title="Go" type="rss" xmlUrl="https://example.com/go" />
+      <outline title="Languages">
+        <outline text="Rust" title="Rust" type="rss" xmlUrl="https://example.com/rust" />
+      </outline>
+    </outline>
+    <outline text="News" title="News" type="rss" xmlUrl="https://example.com/news" />
+  </body>
+</opml>`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	feeds, err := ParseOPML(path)
+	if err != nil || len(feeds) != 3 {
+		t.Fatalf("ParseOPML error: %v %+v", err, feeds)
+	}
+	if feeds[0].Folder != "Tech" || feeds[1].Folder != "Tech/Languages" || feeds[2].Folder != "" {
+		t.Fatalf("unexpected folders: %+v", feeds)
+	}
+
+	out := filepath.Join(root, "out.opml")
+	if err := ExportOPML(out, append(feeds, Feed{Title: "Zig", URL: "https://example.com/zig", Folder: "Tech/Languages"})); err != nil {
+		t.Fatalf("ExportOPML error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if strings.Count(string(data), `<outline text="Languages" title="Languages">`) != 1 || strings.Count(string(data), `<outline text="Tech" title="Tech">`) != 1 {
+		t.Fatalf("expected nested groups once each:\n%s", data)
+	}
+	again, err := ParseOPML(out)
+	if err != nil || len(again) != 4 {
+		t.Fatalf("ParseOPML error: %v %+v", err, again)
+	}
+	folders := map[string]string{}
+	for _, feed := range again {
+		folders[feed.URL] = feed.Folder
+	}
+	for _, feed := range feeds {
+		if folders[feed.URL] != feed.Folder {
+			t.Fatalf("round trip moved %+v to %q", feed, folders[feed.URL])
+		}
+	}
+	if folders["https://example.com/zig"] != "Tech/Languages" {
+		t.Fatalf("unexpected folders %v", folders)
+	}
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var pdfCreate = func(path string) (io.WriteCloser, error) { return os.Create(path) }
+
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 56.0
+)
+
+var pdfHelveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var pdfWinAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '•': 0x95, '–': 0x96, '—': 0x97,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '™': 0x99,
+}
+
+type pdfFont string
+
+const (
+	pdfRegular pdfFont = "F1"
+	pdfBold    pdfFont = "F2"
+	pdfItalic  pdfFont = "F3"
+)
+
+type pdfBlock struct {
+	font pdfFont
+	size float64
+	text string
+	gap  float64
+}
+
+type pdfLine struct {
+	font pdfFont
+	size float64
+	text string
+	y    float64
+}
+
+func pdfEncode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 32 && r <= 126, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		case pdfWinAnsi[r] != 0:
+			out = append(out, pdfWinAnsi[r])
+		case r == '\t':
+			out = append(out, ' ')
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func pdfTextWidth(text []byte, font pdfFont, size float64) float64 {
+	units := 0
+	for _, c := range text {
+		if c >= 32 && c <= 126 {
+			units += pdfHelveticaWidths[c-32]
+		} else {
+			units += 556
+		}
+	}
+	width := float64(units) * size / 1000
+	if font == pdfBold {
+		width *= 1.08
+	}
+	return width
+}
+
+func pdfWrap(text string, font pdfFont, size float64, width float64) []string {
+	lines := []string{}
+	current := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if current != "" && pdfTextWidth(pdfEncode(candidate), font, size) > width {
+			lines = append(lines, current)
+			candidate = word
+		}
+		for pdfTextWidth(pdfEncode(candidate), font, size) > width && len([]rune(candidate)) > 1 {
+			runes := []rune(candidate)
+			cut := len(runes) - 1
+			for cut > 1 && pdfTextWidth(pdfEncode(string(runes[:cut])), font, size) > width {
+				cut--
+			}
+			lines = append(lines, string(runes[:cut]))
+			candidate = string(runes[cut:])
+		}
+		current = candidate
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+func pdfArticleBlocks(article Article, summary string) []pdfBlock {
+	blocks := []pdfBlock{{font: pdfBold, size: 18, text: article.Title, gap: 6}}
+	meta := []string{}
+	for _, value := range []string{article.FeedTitle, article.Author} {
+		if value != "" {
+			meta = append(meta, value)
+		}
+	}
+	if !article.PublishedAt.IsZero() {
+		meta = append(meta, article.PublishedAt.In(time.Local).Format("2006-01-02 15:04"))
+	}
+	if len(meta) > 0 {
+		blocks = append(blocks, pdfBlock{font: pdfItalic, size: 10, text: strings.Join(meta, " · "), gap: 4})
+	}
+	if article.URL != "" {
+		blocks = append(blocks, pdfBlock{font: pdfRegular, size: 9, text: article.URL, gap: 12})
+	}
+	if summary = strings.TrimSpace(summary); summary != "" {
+		blocks = append(blocks, pdfBlock{font: pdfBold, size: 13, text: "Summary", gap: 4})
+		for _, line := range strings.Split(summary, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				blocks = append(blocks, pdfBlock{font: pdfRegular, size: 11, text: line, gap: 3})
+			}
+		}
+		blocks[len(blocks)-1].gap = 14
+	}
+	for _, paragraph := range epubParagraphs(article) {
+		blocks = append(blocks, pdfBlock{font: pdfRegular, size: 11, text: paragraph, gap: 8})
+	}
+	return blocks
+}
+
+func pdfLayout(blocks []pdfBlock) [][]pdfLine {
+	pages := [][]pdfLine{}
+	page := []pdfLine{}
+	y := pdfPageHeight - pdfMargin
+	for _, block := range blocks {
+		leading := block.size * 1.35
+		for _, text := range pdfWrap(block.text, block.font, block.size, pdfPageWidth-2*pdfMargin) {
+			if y-leading < pdfMargin {
+				pages = append(pages, page)
+				page = []pdfLine{}
+				y = pdfPageHeight - pdfMargin
+			}
+			y -= leading
+			page = append(page, pdfLine{font: block.font, size: block.size, text: text, y: y})
+		}
+		y -= block.gap
+	}
+	return append(pages, page)
+}
+
+func pdfString(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range pdfEncode(text) {
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func pdfContent(lines []pdfLine, number int, total int) string {
+	var b strings.Builder
+	for _, line := range lines {
+		fmt.Fprintf(&b, "BT /%s %s Tf %s %s Td %s Tj ET\n", line.font, pdfNumber(line.size), pdfNumber(pdfMargin), pdfNumber(line.y), pdfString(line.text))
+	}
+	footer := fmt.Sprintf("%d / %d", number, total)
+	fmt.Fprintf(&b, "BT /%s 8 Tf %s %s Td %s Tj ET\n", pdfRegular, pdfNumber(pdfPageWidth-pdfMargin-pdfTextWidth([]byte(footer), pdfRegular, 8)), pdfNumber(pdfMargin/2), pdfString(footer))
+	return b.String()
+}
+
+func pdfNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func writePDF(w io.Writer, title string, generated time.Time, articles []Article, summaries map[int]string) error {
+	pages := [][]pdfLine{}
+	for _, article := range articles {
+		pages = append(pages, pdfLayout(pdfArticleBlocks(article, summaries[article.ID]))...)
+	}
+	fonts := []string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique"}
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+	}
+	fontRefs := []string{}
+	for i, font := range fonts {
+		objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /"+font+" /Encoding /WinAnsiEncoding >>")
+		fontRefs = append(fontRefs, fmt.Sprintf("/F%d %d 0 R", i+1, len(objects)))
+	}
+	objects = append(objects, fmt.Sprintf("<< /Title %s /Producer (Greeder) /CreationDate (D:%s) >>", pdfString(title), generated.UTC().Format("20060102150405Z")))
+	info := len(objects)
+	kids := []string{}
+	for i, lines := range pages {
+		content := pdfContent(lines, i+1, len(pages))
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s >> >> /Contents %d 0 R >>", pdfNumber(pdfPageWidth), pdfNumber(pdfPageHeight), strings.Join(fontRefs, " "), len(objects)))
+		kids = append(kids, fmt.Sprintf("%d 0 R", len(objects)))
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, info, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (a *App) ExportPDF(path string, selection string, articleID int) (int, error) {
+	if strings.TrimSpace(path) == "" {
+		return 0, errors.New("missing pdf path")
+	}
+	articles := []Article{}
+	switch selection {
+	case "article":
+		article := a.findArticle(articleID)
+		if article == nil {
+			return 0, fmt.Errorf("article %d not found", articleID)
+		}
+		articles = append(articles, *article)
+	case "starred":
+		for _, article := range a.allArticles() {
+			if article.IsStarred {
+				articles = append(articles, article)
+			}
+		}
+		if len(articles) == 0 {
+			return 0, errors.New("no starred articles to export")
+		}
+	default:
+		return 0, fmt.Errorf("unknown pdf selection: %s", selection)
+	}
+	generated := a.now()
+	title := articles[0].Title
+	if selection == "starred" {
+		title = fmt.Sprintf("Greeder - starred articles %s", generated.Format("2006-01-02"))
+	}
+	file, err := pdfCreate(path)
+	if err != nil {
+		return 0, err
+	}
+	if err := writePDF(file, title, generated, articles, a.articleSummaries(articles)); err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	a.status = fmt.Sprintf("Exported %d articles to %s", len(articles), path)
+	return len(articles), nil
+}
+
+func parsePDFArgs(args []string) (string, int, string, error) {
+	selection := ""
+	articleID := 0
+	path := ""
+	for _, arg := range args {
+		if arg == "--starred" || arg == "starred" {
+			selection = "starred"
+			continue
+		}
+		if id, err := strconv.Atoi(arg); err == nil && selection == "" {
+			selection, articleID = "article", id
+			continue
+		}
+		path = arg
+	}
+	if selection == "" {
+		return "", 0, "", errors.New("missing article id or --starred")
+	}
+	if path == "" {
+		path = "greeder-starred.pdf"
+		if selection == "article" {
+			path = fmt.Sprintf("greeder-article-%d.pdf", articleID)
+		}
+	}
+	return selection, articleID, path, nil
+}
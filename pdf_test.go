@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPDFEncodeAndWrap(t *testing.T) {
+	if got := string(pdfEncode("café – “ok” 日本\t!")); got != "caf\xe9 \x96 \x93ok\x94 ?? !" {
+		t.Fatalf("unexpected encoding %q", got)
+	}
+	lines := pdfWrap(strings.Repeat("word ", 60), pdfRegular, 11, 200)
+	if len(lines) < 5 {
+		t.Fatalf("expected wrapped lines, got %q", lines)
+	}
+	for _, line := range lines {
+		if pdfTextWidth(pdfEncode(line), pdfRegular, 11) > 200 {
+			t.Fatalf("line too wide: %q", line)
+		}
+	}
+	long := pdfWrap("https://example.com/"+strings.Repeat("a", 200), pdfRegular, 9, 100)
+	if len(long) < 3 || strings.Join(long, "") != "https://example.com/"+strings.Repeat("a", 200) {
+		t.Fatalf("expected long words to be broken without loss, got %q", long)
+	}
+	if pdfString(`a (b) \c`) != `(a \(b\) \\c)` {
+		t.Fatalf("unexpected escaping %s", pdfString(`a (b) \c`))
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	generated := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	articles := []Article{
+		{ID: 1, Title: "Tom (and) Jerry", URL: "https://example.com/a", FeedTitle: "Feed", Author: "Ann", PublishedAt: generated, Content: "<p>Body</p>"},
+		{ID: 2, Title: "Long", ContentText: strings.Repeat("A long paragraph of text. ", 40) + "\n\n" + strings.Repeat("More text here. ", 400)},
+	}
+	var buf bytes.Buffer
+	if err := writePDF(&buf, "Export", generated, articles, map[int]string{1: "- point one\n- point two"}); err != nil {
+		t.Fatalf("writePDF error: %v", err)
+	}
+	data := buf.String()
+	if !strings.HasPrefix(data, "%PDF-1.4\n") || !strings.HasSuffix(data, "%%EOF\n") {
+		t.Fatalf("missing PDF header or trailer")
+	}
+	for _, want := range []string{"(Tom \\(and\\) Jerry) Tj", "(Summary) Tj", "(- point one) Tj", "(Body) Tj", "(https://example.com/a) Tj", "/Title (Export)", "/BaseFont /Helvetica-Bold"} {
+		if !strings.Contains(data, want) {
+			t.Fatalf("pdf missing %q", want)
+		}
+	}
+	count := regexp.MustCompile(`/Count (\d+)`).FindStringSubmatch(data)
+	if count == nil || count[1] == "1" || count[1] == "2" {
+		t.Fatalf("expected the long article to span several pages, got %v", count)
+	}
+	if !strings.Contains(data, "(1 / "+count[1]+") Tj") {
+		t.Fatalf("missing page footer")
+	}
+	start, err := strconv.Atoi(regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(data)[1])
+	if err != nil || !strings.HasPrefix(data[start:], "xref\n") {
+		t.Fatalf("startxref does not point at the xref table")
+	}
+	for _, match := range regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(data, -1) {
+		offset, _ := strconv.Atoi(match[1])
+		if !regexp.MustCompile(`^\d+ 0 obj\n`).MatchString(data[offset:]) {
+			t.Fatalf("xref offset %d does not point at an object", offset)
+		}
+	}
+}
+
+func TestParsePDFArgs(t *testing.T) {
+	if selection, id, path, err := parsePDFArgs([]string{"42", "out.pdf"}); err != nil || selection != "article" || id != 42 || path != "out.pdf" {
+		t.Fatalf("unexpected parse %q %d %q %v", selection, id, path, err)
+	}
+	if selection, _, path, err := parsePDFArgs([]string{"--starred"}); err != nil || selection != "starred" || path != "greeder-starred.pdf" {
+		t.Fatalf("unexpected starred parse %q %q %v", selection, path, err)
+	}
+	if _, _, path, _ := parsePDFArgs([]string{"7"}); path != "greeder-article-7.pdf" {
+		t.Fatalf("unexpected default path %q", path)
+	}
+	if _, _, _, err := parsePDFArgs([]string{"out.pdf"}); err == nil {
+		t.Fatalf("expected missing selection error")
+	}
+}
+
+func TestAppExportPDF(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	inserted, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "Starred", URL: "https://example.com/1", IsStarred: true},
+		{GUID: "2", Title: "Plain", URL: "https://example.com/2"},
+	})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: inserted[1].ID, Content: "Short summary"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	path := filepath.Join(t.TempDir(), "out.pdf")
+	if count, err := app.ExportPDF(path, "article", inserted[1].ID); err != nil || count != 1 || app.status != "Exported 1 articles to "+path {
+		t.Fatalf("unexpected export %d %v %q", count, err, app.status)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "(Short summary) Tj") || !strings.Contains(string(data), "/Title (Plain)") {
+		t.Fatalf("unexpected pdf %v", err)
+	}
+	if err := handleCommand(app, "pdf starred "+path, io.Discard); err != nil || app.status != "Exported 1 articles to "+path {
+		t.Fatalf("unexpected pdf command %v %q", err, app.status)
+	}
+	if _, err := app.ExportPDF(path, "article", 9999); err == nil {
+		t.Fatalf("expected missing article error")
+	}
+	if _, err := app.ExportPDF("", "starred", 0); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+	app.articles = nil
+	if _, err := app.ExportPDF(path, "starred", 0); err == nil || err.Error() != "no starred articles to export" {
+		t.Fatalf("expected empty export error, got %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	orig := pdfCreate
+	t.Cleanup(func() { pdfCreate = orig })
+	pdfCreate = func(string) (io.WriteCloser, error) { return nil, errors.New("denied") }
+	if _, err := app.ExportPDF(path, "starred", 0); err == nil {
+		t.Fatalf("expected create error")
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type PinboardClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewPinboardClient(token string) *PinboardClient {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil
+	}
+	base := strings.TrimSpace(os.Getenv("PINBOARD_BASE_URL"))
+	if base == "" {
+		base = "https://api.pinboard.in"
+	}
+	return &PinboardClient{
+		baseURL: strings.TrimRight(base, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *PinboardClient) Save(item RaindropItem) (int, error) {
+	if p == nil {
+		return 0, errors.New("pinboard not configured")
+	}
+	tags := make([]string, 0, len(item.Tags))
+	for _, tag := range item.Tags {
+		if tag = strings.Join(strings.Fields(tag), "-"); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	query := url.Values{}
+	query.Set("url", item.Link)
+	query.Set("description", item.Title)
+	query.Set("extended", item.Note)
+	query.Set("tags", strings.Join(tags, " "))
+	query.Set("auth_token", p.token)
+	query.Set("format", "json")
+	resp, err := p.client.Get(p.baseURL + "/v1/posts/add?" + query.Encode())
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("pinboard http %d", resp.StatusCode)
+	}
+	var parsed struct {
+		ResultCode string `json:"result_code"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return 0, err
+	}
+	if parsed.ResultCode != "done" {
+		return 0, errors.New("pinboard: " + parsed.ResultCode)
+	}
+	return 0, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPinboardClient(t *testing.T) {
+	if NewPinboardClient(" ") != nil {
+		t.Fatalf("expected nil client without token")
+	}
+	t.Setenv("PINBOARD_BASE_URL", "http://pinboard.test/")
+	client := NewPinboardClient("user:TOKEN")
+	var query map[string]string
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/v1/posts/add" {
+			return newResponse(http.StatusNotFound, "", nil, r), nil
+		}
+		query = map[string]string{}
+		for key := range r.URL.Query() {
+			query[key] = r.URL.Query().Get(key)
+		}
+		return newResponse(http.StatusOK, `{"result_code":"done"}`, nil, r), nil
+	})}
+	if _, err := client.Save(RaindropItem{Link: "https://example.com", Title: "Example", Note: "- summary", Tags: []string{"go", "machine learning", " "}}); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	if query["url"] != "https://example.com" || query["description"] != "Example" || query["extended"] != "- summary" || query["tags"] != "go machine-learning" || query["auth_token"] != "user:TOKEN" || query["format"] != "json" {
+		t.Fatalf("unexpected query %v", query)
+	}
+
+	var nilClient *PinboardClient
+	if _, err := nilClient.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+	client.client = clientForResponse(http.StatusOK, `{"result_code":"missing url"}`, nil)
+	if _, err := client.Save(RaindropItem{}); err == nil || err.Error() != "pinboard: missing url" {
+		t.Fatalf("expected result code error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusUnauthorized, "", nil)
+	if _, err := client.Save(RaindropItem{}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusOK, "nope", nil)
+	if _, err := client.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected decode error")
+	}
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if _, err := client.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected transport error")
+	}
+}
+
+func TestAppSaveBookmarkPinboard(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "g1", Title: "T", URL: "https://example.com/a"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.config.SaveTarget = "pinboard"
+	if app.saveTargetName() != "Pinboard" {
+		t.Fatalf("expected pinboard target name")
+	}
+	if err := app.SaveBookmark(nil); err == nil || err.Error() != "pinboard not configured" {
+		t.Fatalf("expected pinboard not configured, got %v", err)
+	}
+	extended := ""
+	app.pinboard = &PinboardClient{baseURL: "http://pinboard.test", token: "t", client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		extended = r.URL.Query().Get("extended")
+		return newResponse(http.StatusOK, `{"result_code":"done"}`, nil, r), nil
+	})}}
+	app.current = Summary{ArticleID: articles[0].ID, Content: "- point"}
+	if err := app.SaveBookmark([]string{"go"}); err != nil || extended != "- point" || app.status != "Saved to Pinboard" || app.store.SavedCount() != 1 {
+		t.Fatalf("expected pinboard save with summary, got %v %q %q", err, extended, app.status)
+	}
+	app.pinboard.client = clientForResponse(http.StatusInternalServerError, "", nil)
+	if err := app.SaveBookmark(nil); err == nil {
+		t.Fatalf("expected pinboard save error")
+	}
+	app.articles = nil
+	if err := app.SaveToPinboard(nil); err != nil {
+		t.Fatalf("expected no-op without selection, got %v", err)
+	}
+}
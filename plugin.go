@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+const pluginTargetPrefix = "plugin:"
+
+type pluginArticle struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Feed        string    `json:"feed"`
+	Author      string    `json:"author"`
+	PublishedAt time.Time `json:"published_at"`
+	Read        bool      `json:"read"`
+	Starred     bool      `json:"starred"`
+	Summary     string    `json:"summary"`
+	Content     string    `json:"content"`
+	Tags        []string  `json:"tags"`
+}
+
+var (
+	pluginRun     = defaultPluginRun
+	pluginMarshal = json.Marshal
+)
+
+func defaultPluginRun(shell string, args []string, env []string, input string) (string, error) {
+	command := execCommand(shell, args...)
+	command.Env = append(command.Environ(), env...)
+	command.Stdin = strings.NewReader(input)
+	out, err := command.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if err != nil && output != "" {
+		return output, fmt.Errorf("%w: %s", err, output)
+	}
+	return output, err
+}
+
+func parsePluginsSection(name string, value string, cfg *Config) error {
+	name = trimQuotes(name)
+	if name == "" || strings.ContainsAny(name, " \t,") {
+		return fmt.Errorf("invalid plugin name: %q", name)
+	}
+	command := trimQuotes(value)
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("empty command for plugin %s", name)
+	}
+	if cfg.Plugins == nil {
+		cfg.Plugins = map[string]string{}
+	}
+	cfg.Plugins[name] = command
+	return nil
+}
+
+func pluginNames(cfg Config) []string {
+	names := make([]string, 0, len(cfg.Plugins))
+	for name := range cfg.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func pluginFromTarget(target string) (string, bool) {
+	if !strings.HasPrefix(target, pluginTargetPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(target, pluginTargetPrefix), true
+}
+
+func (a *App) RunPlugin(name string, tags []string) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	command, ok := a.config.Plugins[name]
+	if !ok {
+		return fmt.Errorf("unknown plugin: %q", name)
+	}
+	blob, err := pluginMarshal(a.articlePayload(*article, tags))
+	if err != nil {
+		return err
+	}
+	shell, args := shellCommandForOS(runtime.GOOS, command)
+	env := []string{"GREEDER_PLUGIN=" + name, "GREEDER_ARTICLE_URL=" + article.URL}
+	start := time.Now()
+	output, err := pluginRun(shell, args, env, string(blob))
+	log := logFor("plugin").With("plugin", name, "duration", time.Since(start))
+	if err != nil {
+		log.Warn("plugin failed", "err", err)
+		return fmt.Errorf("plugin %s: %w", name, err)
+	}
+	log.Info("plugin ran", "article", article.ID)
+	a.status = name + ": " + lastLine(output)
+	if output == "" {
+		a.status = tr(msgPluginSent, name)
+	}
+	return nil
+}
+
+func cleanTags(tags []string) []string {
+	cleaned := []string{}
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return cleaned
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
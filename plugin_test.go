@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePluginsSection(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("save_target = \"plugin:readwise\"\n[plugins]\nreadwise = \"~/bin/readwise --quiet\"\n\"to-kindle\" = \"send-kindle\"\n", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.Plugins["readwise"] != "~/bin/readwise --quiet" || cfg.Plugins["to-kindle"] != "send-kindle" || cfg.SaveTarget != "plugin:readwise" {
+		t.Fatalf("unexpected plugins %+v %q", cfg.Plugins, cfg.SaveTarget)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil || len(reparsed.Plugins) != 2 {
+		t.Fatalf("plugins did not round trip: %+v %v", reparsed.Plugins, err)
+	}
+	for _, bad := range []string{"[plugins]\n\"two words\" = \"x\"", "[plugins]\nempty = \"\""} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestPluginShareTargets(t *testing.T) {
+	app := newTUIApp(t)
+	app.config.Plugins = map[string]string{"zeta": "z", "alpha": "a"}
+	targets := app.ShareTargets()
+	if len(targets) != 2 || targets[0] != "plugin:alpha" || targets[1] != "plugin:zeta" {
+		t.Fatalf("unexpected targets %v", targets)
+	}
+	if target, ok := app.resolveShareTarget("zeta"); !ok || target != "plugin:zeta" {
+		t.Fatalf("expected plugin resolved by name, got %q", target)
+	}
+	if app.targetName("plugin:alpha") != "alpha" {
+		t.Fatalf("unexpected target name")
+	}
+}
+
+func TestRunPlugin(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	published := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "One", URL: "https://example.com/1", ContentText: "Body", PublishedAt: published}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "Short"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.config.Plugins = map[string]string{"readwise": "send-readwise"}
+
+	orig := pluginRun
+	t.Cleanup(func() { pluginRun = orig })
+	var gotArgs, gotEnv []string
+	var got pluginArticle
+	output := "queued\nsaved as #42\n"
+	var runErr error
+	pluginRun = func(shell string, args []string, env []string, input string) (string, error) {
+		gotArgs, gotEnv = args, env
+		if err := json.Unmarshal([]byte(input), &got); err != nil {
+			t.Fatalf("plugin input is not JSON: %v", err)
+		}
+		return strings.TrimSpace(output), runErr
+	}
+
+	if err := app.SaveBookmarkTo("plugin:readwise", []string{" go ", "", "rss"}); err != nil {
+		t.Fatalf("SaveBookmarkTo error: %v", err)
+	}
+	if gotArgs[len(gotArgs)-1] != "send-readwise" || !strings.Contains(strings.Join(gotEnv, " "), "GREEDER_PLUGIN=readwise") {
+		t.Fatalf("unexpected invocation %v %v", gotArgs, gotEnv)
+	}
+	if got.Title != "One" || got.Summary != "Short" || got.Content != "Body" || got.Feed != "Feed" || !got.PublishedAt.Equal(published) || strings.Join(got.Tags, ",") != "go,rss" {
+		t.Fatalf("unexpected payload %+v", got)
+	}
+	if app.status != "readwise: saved as #42" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+
+	output = ""
+	if err := app.RunPlugin("readwise", nil); err != nil || app.status != "Sent to readwise" {
+		t.Fatalf("unexpected quiet plugin result %q %v", app.status, err)
+	}
+	runErr = errors.New("exit status 1: no token")
+	if err := app.RunPlugin("readwise", nil); err == nil || err.Error() != "plugin readwise: exit status 1: no token" {
+		t.Fatalf("expected plugin error, got %v", err)
+	}
+	if err := app.RunPlugin("missing", nil); err == nil {
+		t.Fatalf("expected unknown plugin error")
+	}
+	pluginMarshal = func(any) ([]byte, error) { return nil, errors.New("marshal") }
+	t.Cleanup(func() { pluginMarshal = json.Marshal })
+	if err := app.RunPlugin("readwise", nil); err == nil {
+		t.Fatalf("expected marshal error")
+	}
+
+	app.articles = nil
+	if err := app.RunPlugin("readwise", nil); err != nil {
+		t.Fatalf("expected no-op without selection, got %v", err)
+	}
+}
+
+func TestDefaultPluginRun(t *testing.T) {
+	output, err := defaultPluginRun("sh", []string{"-c", "cat; printf ' %s' \"$GREEDER_PLUGIN\""}, []string{"GREEDER_PLUGIN=x"}, "{}")
+	if err != nil || output != "{} x" {
+		t.Fatalf("unexpected plugin output %q %v", output, err)
+	}
+	if _, err := defaultPluginRun("sh", []string{"-c", "echo denied; exit 2"}, nil, ""); err == nil || err.Error() != "exit status 2: denied" {
+		t.Fatalf("expected plugin failure, got %v", err)
+	}
+	if _, err := defaultPluginRun("sh", []string{"-c", "exit 3"}, nil, ""); err == nil || err.Error() != "exit status 3" {
+		t.Fatalf("expected bare failure, got %v", err)
+	}
+}
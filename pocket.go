@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PocketClient struct {
+	baseURL     string
+	consumerKey string
+	accessToken string
+	client      *http.Client
+}
+
+const pocketRedirectURI = "https://getpocket.com/connected_applications"
+
+var (
+	pocketPollInterval = 2 * time.Second
+	pocketPollTimeout  = 2 * time.Minute
+	pocketSleep        = time.Sleep
+)
+
+func NewPocketClient(consumerKey, accessToken string) *PocketClient {
+	consumerKey = strings.TrimSpace(consumerKey)
+	if consumerKey == "" {
+		return nil
+	}
+	base := strings.TrimSpace(os.Getenv("POCKET_BASE_URL"))
+	if base == "" {
+		base = "https://getpocket.com"
+	}
+	return &PocketClient{
+		baseURL:     strings.TrimRight(base, "/"),
+		consumerKey: consumerKey,
+		accessToken: strings.TrimSpace(accessToken),
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *PocketClient) post(path string, payload any, out any) error {
+	blob, err := servicesJSONMarshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, p.baseURL+path, bytes.NewReader(blob))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json; charset=UTF-8")
+	req.Header.Set("x-accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if reason := resp.Header.Get("x-error"); reason != "" {
+			return fmt.Errorf("pocket http %d: %s", resp.StatusCode, reason)
+		}
+		return fmt.Errorf("pocket http %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (p *PocketClient) RequestToken() (string, error) {
+	if p == nil {
+		return "", errors.New("pocket not configured")
+	}
+	var parsed struct {
+		Code string `json:"code"`
+	}
+	if err := p.post("/v3/oauth/request", map[string]string{"consumer_key": p.consumerKey, "redirect_uri": pocketRedirectURI}, &parsed); err != nil {
+		return "", err
+	}
+	if parsed.Code == "" {
+		return "", errors.New("pocket returned no request token")
+	}
+	return parsed.Code, nil
+}
+
+func (p *PocketClient) AuthorizeURL(code string) string {
+	return p.baseURL + "/auth/authorize?request_token=" + url.QueryEscape(code) + "&redirect_uri=" + url.QueryEscape(pocketRedirectURI)
+}
+
+func (p *PocketClient) Authorize(code string) (string, error) {
+	if p == nil {
+		return "", errors.New("pocket not configured")
+	}
+	var parsed struct {
+		AccessToken string `json:"access_token"`
+		Username    string `json:"username"`
+	}
+	if err := p.post("/v3/oauth/authorize", map[string]string{"consumer_key": p.consumerKey, "code": code}, &parsed); err != nil {
+		return "", err
+	}
+	if parsed.AccessToken == "" {
+		return "", errors.New("pocket returned no access token")
+	}
+	return parsed.AccessToken, nil
+}
+
+func (p *PocketClient) Save(item RaindropItem) (int, error) {
+	if p == nil || p.accessToken == "" {
+		return 0, errors.New("pocket not configured")
+	}
+	var parsed struct {
+		Item struct {
+			ItemID json.RawMessage `json:"item_id"`
+		} `json:"item"`
+	}
+	payload := map[string]string{
+		"url":          item.Link,
+		"title":        item.Title,
+		"tags":         strings.Join(item.Tags, ","),
+		"consumer_key": p.consumerKey,
+		"access_token": p.accessToken,
+	}
+	if err := p.post("/v3/add", payload, &parsed); err != nil {
+		return 0, err
+	}
+	id, _ := strconv.Atoi(strings.Trim(string(parsed.Item.ItemID), `"`))
+	return id, nil
+}
+
+func PocketLogin(cfg Config, out io.Writer) (Config, error) {
+	pocket := NewPocketClient(cfg.PocketConsumerKey, "")
+	if pocket == nil {
+		return cfg, errors.New("set pocket_consumer_key first")
+	}
+	code, err := pocket.RequestToken()
+	if err != nil {
+		return cfg, err
+	}
+	fmt.Fprintln(out, tr(msgCLIPocketAuthorize))
+	fmt.Fprintln(out, pocket.AuthorizeURL(code))
+	fmt.Fprintln(out, tr(msgCLIPocketWaiting))
+	deadline := time.Now().Add(pocketPollTimeout)
+	for {
+		token, err := pocket.Authorize(code)
+		if err == nil {
+			cfg.PocketAccessToken = token
+			return cfg, nil
+		}
+		if time.Now().After(deadline) {
+			return cfg, fmt.Errorf("pocket authorization not approved: %w", err)
+		}
+		pocketSleep(pocketPollInterval)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func pocketTestClient(t *testing.T, approved *bool, requests *[]map[string]string) *http.Client {
+	return &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Header.Get("x-accept") != "application/json" {
+			t.Fatalf("missing x-accept header")
+		}
+		payload := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		if requests != nil {
+			*requests = append(*requests, payload)
+		}
+		switch r.URL.Path {
+		case "/v3/oauth/request":
+			return newResponse(http.StatusOK, `{"code":"req-code"}`, nil, r), nil
+		case "/v3/oauth/authorize":
+			if !*approved {
+				*approved = true
+				return newResponse(http.StatusForbidden, "", map[string]string{"x-error": "User rejected code."}, r), nil
+			}
+			return newResponse(http.StatusOK, `{"access_token":"access","username":"reader"}`, nil, r), nil
+		case "/v3/add":
+			return newResponse(http.StatusOK, `{"item":{"item_id":"321"},"status":1}`, nil, r), nil
+		}
+		return newResponse(http.StatusNotFound, "", nil, r), nil
+	})}
+}
+
+func TestPocketClient(t *testing.T) {
+	if NewPocketClient(" ", "token") != nil {
+		t.Fatalf("expected nil client without consumer key")
+	}
+	t.Setenv("POCKET_BASE_URL", "http://pocket.test/")
+	approved := true
+	requests := []map[string]string{}
+	client := NewPocketClient("consumer", "access")
+	client.client = pocketTestClient(t, &approved, &requests)
+	code, err := client.RequestToken()
+	if err != nil || code != "req-code" {
+		t.Fatalf("RequestToken error: %q %v", code, err)
+	}
+	if got := client.AuthorizeURL(code); !strings.HasPrefix(got, "http://pocket.test/auth/authorize?request_token=req-code&redirect_uri=") {
+		t.Fatalf("unexpected authorize url %q", got)
+	}
+	if token, err := client.Authorize(code); err != nil || token != "access" {
+		t.Fatalf("Authorize error: %q %v", token, err)
+	}
+	id, err := client.Save(RaindropItem{Link: "https://example.com", Title: "Example", Tags: []string{"a", "b"}})
+	if err != nil || id != 321 {
+		t.Fatalf("Save error: %d %v", id, err)
+	}
+	last := requests[len(requests)-1]
+	if last["url"] != "https://example.com" || last["tags"] != "a,b" || last["access_token"] != "access" || last["consumer_key"] != "consumer" {
+		t.Fatalf("unexpected add payload %v", last)
+	}
+
+	var nilClient *PocketClient
+	if _, err := nilClient.RequestToken(); err == nil {
+		t.Fatalf("expected nil request token error")
+	}
+	if _, err := nilClient.Authorize("x"); err == nil {
+		t.Fatalf("expected nil authorize error")
+	}
+	if _, err := nilClient.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected nil save error")
+	}
+	client.client = clientForResponse(http.StatusForbidden, "", map[string]string{"x-error": "Invalid consumer key."})
+	if _, err := client.RequestToken(); err == nil || !strings.Contains(err.Error(), "Invalid consumer key.") {
+		t.Fatalf("expected x-error in message, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusInternalServerError, "", nil)
+	if _, err := client.Save(RaindropItem{}); err == nil || err.Error() != "pocket http 500" {
+		t.Fatalf("expected status error, got %v", err)
+	}
+	client.client = clientForResponse(http.StatusOK, `{}`, nil)
+	if _, err := client.RequestToken(); err == nil {
+		t.Fatalf("expected missing code error")
+	}
+	if _, err := client.Authorize("x"); err == nil {
+		t.Fatalf("expected missing token error")
+	}
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if _, err := client.Save(RaindropItem{}); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	client.baseURL = "http://[::1"
+	if _, err := client.RequestToken(); err == nil {
+		t.Fatalf("expected request error")
+	}
+	orig := servicesJSONMarshal
+	servicesJSONMarshal = func(any) ([]byte, error) { return nil, errors.New("marshal") }
+	defer func() { servicesJSONMarshal = orig }()
+	if _, err := client.RequestToken(); err == nil {
+		t.Fatalf("expected marshal error")
+	}
+}
+
+func TestPocketLogin(t *testing.T) {
+	if _, err := PocketLogin(Config{}, &bytes.Buffer{}); err == nil {
+		t.Fatalf("expected missing consumer key error")
+	}
+	approved := false
+	origTransport := http.DefaultTransport
+	http.DefaultTransport = pocketTestClient(t, &approved, nil).Transport
+	origSleep := pocketSleep
+	sleeps := 0
+	pocketSleep = func(time.Duration) { sleeps++ }
+	t.Cleanup(func() {
+		http.DefaultTransport = origTransport
+		pocketSleep = origSleep
+	})
+	var out bytes.Buffer
+	cfg, err := PocketLogin(Config{PocketConsumerKey: "consumer"}, &out)
+	if err != nil || cfg.PocketAccessToken != "access" || sleeps != 1 {
+		t.Fatalf("PocketLogin error: %+v %v (sleeps %d)", cfg, err, sleeps)
+	}
+	if !strings.Contains(out.String(), "request_token=req-code") {
+		t.Fatalf("expected authorize url in output: %s", out.String())
+	}
+
+	origTimeout := pocketPollTimeout
+	pocketPollTimeout = -time.Second
+	defer func() { pocketPollTimeout = origTimeout }()
+	http.DefaultTransport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/v3/oauth/request" {
+			return newResponse(http.StatusOK, `{"code":"c"}`, nil, r), nil
+		}
+		return newResponse(http.StatusForbidden, "", nil, r), nil
+	})
+	if _, err := PocketLogin(Config{PocketConsumerKey: "consumer"}, &out); err == nil || !strings.Contains(err.Error(), "not approved") {
+		t.Fatalf("expected approval timeout, got %v", err)
+	}
+	http.DefaultTransport = &errorRoundTripper{}
+	if _, err := PocketLogin(Config{PocketConsumerKey: "consumer"}, &out); err == nil {
+		t.Fatalf("expected request token error")
+	}
+}
+
+func TestAppSaveBookmarkPocket(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "g1", Title: "T", URL: "https://example.com/a"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.config.SaveTarget = "pocket"
+	if app.saveTargetName() != "Pocket" {
+		t.Fatalf("expected pocket target name")
+	}
+	if err := app.SaveBookmark([]string{"x"}); err == nil || !strings.Contains(err.Error(), "--pocket-login") {
+		t.Fatalf("expected pocket not configured error, got %v", err)
+	}
+	approved := true
+	app.pocket = &PocketClient{baseURL: "http://pocket.test", consumerKey: "consumer", accessToken: "access", client: pocketTestClient(t, &approved, nil)}
+	if err := app.SaveBookmark([]string{"x"}); err != nil || app.status != "Saved to Pocket" || app.store.SavedCount() != 1 {
+		t.Fatalf("expected pocket save, got %v %q", err, app.status)
+	}
+	if saved := app.store.Saved(); saved[0].Provider != "pocket" {
+		t.Fatalf("expected pocket provider recorded, got %+v", saved)
+	}
+	app.pocket.client = clientForResponse(http.StatusBadGateway, "", nil)
+	if err := app.SaveBookmark(nil); err == nil {
+		t.Fatalf("expected pocket save error")
+	}
+	app.config.SaveTarget = ""
+	if app.saveTargetName() != "Raindrop" {
+		t.Fatalf("expected raindrop target name")
+	}
+	if err := app.SaveBookmark(nil); err == nil || err.Error() != "raindrop not configured" {
+		t.Fatalf("expected raindrop path, got %v", err)
+	}
+}
+
+func TestRunMainPocketLogin(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+	var stdout, stderr bytes.Buffer
+	if err := runMain([]string{"--pocket-login"}, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Fatalf("expected login error without consumer key")
+	}
+	path := filepath.Join(root, "greeder", "config.toml")
+	if err := os.WriteFile(path, []byte("# pocket setup\ndb_path = \""+filepath.Join(root, "feeds.db")+"\"\npocket_consumer_key = \"consumer\"\nsave_target = \"pocket\"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	approved := true
+	origTransport := http.DefaultTransport
+	http.DefaultTransport = pocketTestClient(t, &approved, nil).Transport
+	t.Cleanup(func() { http.DefaultTransport = origTransport })
+	t.Setenv("GREEDER_LM_API_KEY", "env-secret")
+	if err := runMain([]string{"--pocket-login"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain pocket login error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), `pocket_access_token = "access"`) || !strings.Contains(string(data), `save_target = "pocket"`) {
+		t.Fatalf("expected token saved to config: %s", data)
+	}
+	if !strings.HasPrefix(string(data), "# pocket setup\n") || strings.Contains(string(data), "env-secret") {
+		t.Fatalf("expected only the token patched into config: %s", data)
+	}
+	if !strings.Contains(stdout.String(), tr(msgCLIPocketAuthorize)) {
+		t.Fatalf("expected authorize prompt, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import "sort"
+
+func (a *App) queuedArticles() []Article {
+	if a.moreArticles {
+		return a.store.QueuedArticles()
+	}
+	queued := []Article{}
+	for _, article := range a.articles {
+		if _, ok := a.queue[article.ID]; ok {
+			queued = append(queued, article)
+		}
+	}
+	sort.SliceStable(queued, func(i, j int) bool {
+		return a.queue[queued[i].ID] < a.queue[queued[j].ID]
+	})
+	return queued
+}
+
+func (a *App) ToggleQueue() error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	if _, queued := a.queue[article.ID]; queued {
+		if err := a.store.DequeueArticle(article.ID); err != nil {
+			return err
+		}
+		a.queue = a.store.QueuePositions()
+		a.status = tr(msgQueueRemoved)
+		if a.queueView && a.selectedIndex >= len(a.queue) && a.selectedIndex > 0 {
+			a.selectedIndex--
+		}
+		a.syncSummaryForSelection()
+		return nil
+	}
+	if err := a.store.QueueArticle(article.ID); err != nil {
+		return err
+	}
+	a.queue = a.store.QueuePositions()
+	a.status = tr(msgQueueAdded, a.queue[article.ID])
+	return nil
+}
+
+func (a *App) ToggleQueueView() {
+	a.queueView = !a.queueView
+	a.selectedIndex = 0
+	if !a.queueView {
+		a.status = tr(msgQueueViewClosed)
+		a.syncSummaryForSelection()
+		return
+	}
+	a.queue = a.store.QueuePositions()
+	if len(a.queue) == 0 {
+		a.status = tr(msgQueueEmpty)
+	} else {
+		a.status = tr(msgQueueView, len(a.queue))
+	}
+	a.syncSummaryForSelection()
+}
+
+func (a *App) MoveQueued(delta int) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	if _, queued := a.queue[article.ID]; !queued {
+		a.status = tr(msgQueueNotQueued)
+		return nil
+	}
+	moved, err := a.store.MoveQueued(article.ID, delta)
+	if err != nil || !moved {
+		return err
+	}
+	a.queue = a.store.QueuePositions()
+	if a.queueView {
+		a.selectedIndex += delta
+	}
+	a.status = tr(msgQueueMoved, a.queue[article.ID])
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTUIReadingQueue(t *testing.T) {
+	app := newTUIApp(t)
+	app.filter = FilterAll
+	insertQueueArticles(t, app.store, 3)
+	app.loadArticles()
+	model := newTUIModel(app)
+	press := func(key string) {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		model = updated.(tuiModel)
+	}
+
+	press("L")
+	if !app.queueView || len(app.FilteredArticles()) != 0 || app.status != tr(msgQueueEmpty) {
+		t.Fatalf("expected empty queue view, got %v %q", app.queueView, app.status)
+	}
+	press("L")
+	first := app.FilteredArticles()[0]
+	second := app.FilteredArticles()[1]
+	press("Q")
+	press("j")
+	press("Q")
+	if len(app.queue) != 2 || app.status != tr(msgQueueAdded, 2) {
+		t.Fatalf("expected two queued articles, got %v %q", app.queue, app.status)
+	}
+	press("K")
+	if app.queue[second.ID] != 1 || app.queue[first.ID] != 2 {
+		t.Fatalf("expected second article moved up, got %v", app.queue)
+	}
+
+	press("L")
+	queued := app.FilteredArticles()
+	if len(queued) != 2 || queued[0].ID != second.ID || queued[1].ID != first.ID {
+		t.Fatalf("expected manual queue order, got %+v", queued)
+	}
+	press("J")
+	if app.selectedIndex != 1 || app.FilteredArticles()[0].ID != first.ID {
+		t.Fatalf("expected selection to follow the moved article, got %d %+v", app.selectedIndex, app.FilteredArticles())
+	}
+	press("Q")
+	if len(app.FilteredArticles()) != 1 || app.selectedIndex != 0 || app.queue[first.ID] != 1 {
+		t.Fatalf("expected article removed from queue, got %d %v", app.selectedIndex, app.queue)
+	}
+	press("L")
+	if app.queueView || len(app.FilteredArticles()) != 3 {
+		t.Fatalf("expected queue view closed")
+	}
+	press("j")
+	press("J")
+	if app.status != tr(msgQueueNotQueued) {
+		t.Fatalf("expected not queued status, got %q", app.status)
+	}
+}
+
+func TestQueueViewLoadsQueuedPages(t *testing.T) {
+	app := newTUIApp(t)
+	ids := insertQueueArticles(t, app.store, 5)
+	if err := app.store.QueueArticle(ids[4]); err != nil {
+		t.Fatalf("QueueArticle error: %v", err)
+	}
+	app.config.ArticlePageSize = 2
+	app.loadArticles()
+	app.ToggleQueueView()
+	if queued := app.FilteredArticles(); len(queued) != 1 || queued[0].ID != ids[4] {
+		t.Fatalf("expected queued article loaded from a later page, got %+v", queued)
+	}
+}
+
+func TestReplQueueCommands(t *testing.T) {
+	app := newTUIApp(t)
+	app.filter = FilterAll
+	insertQueueArticles(t, app.store, 2)
+	app.loadArticles()
+	for _, line := range []string{"queue", "j", "queue", "queue-up", "queue-view"} {
+		if err := handleCommand(app, line, io.Discard); err != nil {
+			t.Fatalf("handleCommand(%q) error: %v", line, err)
+		}
+	}
+	queued := app.FilteredArticles()
+	if !app.queueView || len(queued) != 2 || app.queue[queued[0].ID] != 1 || queued[0].Title != "B" {
+		t.Fatalf("unexpected queue %+v %v", queued, app.queue)
+	}
+}
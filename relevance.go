@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var scorePattern = regexp.MustCompile(`\d+`)
+
+func heuristicScore(article Article, topics []string, interests []string) int {
+	title := strings.ToLower(article.Title)
+	content := strings.ToLower(firstNonEmpty(article.ContentText, article.Content))
+	score := 0
+	for _, interest := range interests {
+		interest = strings.ToLower(strings.TrimSpace(interest))
+		if interest == "" {
+			continue
+		}
+		switch {
+		case strings.Contains(title, interest):
+			score += 40
+		case containsString(topics, interest):
+			score += 30
+		case strings.Contains(content, interest):
+			score += 15
+		}
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Summarizer) ScoreRelevance(title, content string, interests []string) (int, error) {
+	if s == nil {
+		return 0, errors.New("summarizer not configured")
+	}
+	prompt := "Interests: " + strings.Join(interests, ", ") + "\n\n" + wrapArticle(title, truncateText(content, 4000))
+	result, err := s.complete(relevanceSystemPrompt(), prompt)
+	if err != nil {
+		return 0, err
+	}
+	return parseScore(result.Content)
+}
+
+func relevanceSystemPrompt() string {
+	return "Rate how relevant this article is to the reader's interests on a scale from 0 (irrelevant) to 100 (must read).\n" +
+		"Output ONLY the number."
+}
+
+func parseScore(raw string) (int, error) {
+	match := scorePattern.FindString(raw)
+	if match == "" {
+		return 0, fmt.Errorf("invalid relevance score: %q", strings.TrimSpace(raw))
+	}
+	score, err := strconv.Atoi(match)
+	if err != nil {
+		return 0, err
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score, nil
+}
+
+func (a *App) ScoreArticles() error {
+	return a.scoreArticles(a.articles)
+}
+
+func (a *App) scoreArticles(articles []Article) error {
+	if len(a.config.Interests) == 0 || a.config.RelevanceScoring == "llm" {
+		return nil
+	}
+	scores := map[int]int{}
+	for i, article := range articles {
+		score := heuristicScore(article, a.store.ArticleTags(article.ID), a.config.Interests)
+		if score != article.Score {
+			scores[article.ID] = score
+			articles[i].Score = score
+		}
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+	return a.store.SetArticleScores(scores)
+}
+
+func (a *App) ScoreWithSummarizer(article Article) error {
+	if len(a.config.Interests) == 0 || a.config.RelevanceScoring != "llm" {
+		return nil
+	}
+	score, err := a.summarizer.ScoreRelevance(article.Title, firstNonEmpty(article.ContentText, article.Content), a.config.Interests)
+	if err != nil {
+		return err
+	}
+	return a.storeScore(article.ID, score)
+}
+
+func (a *App) storeScore(articleID int, score int) error {
+	if err := a.store.SetArticleScores(map[int]int{articleID: score}); err != nil {
+		return err
+	}
+	if article := a.findArticle(articleID); article != nil {
+		article.Score = score
+	}
+	return nil
+}
+
+func (a *App) ToggleSort() {
+	a.sortMode = nextSortMode(a.sortMode)
+	a.status = "Sort: " + sortModeLabels[a.sortMode]
+	if a.sortMode == SortRanked && len(a.config.Interests) == 0 {
+		a.status += " (set interests in config to score articles)"
+	}
+	a.loadArticles()
+	a.selectedIndex = 0
+	a.syncSummaryForSelection()
+}
+
+func rankArticles(articles []Article) []Article {
+	ranked := make([]Article, len(articles))
+	copy(ranked, articles)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
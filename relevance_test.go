@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHeuristicScore(t *testing.T) {
+	article := Article{Title: "Rust in the Linux kernel", ContentText: "Memory safety and security work continues."}
+	interests := []string{"Rust", "security", "gardening", " ", "databases"}
+	if got := heuristicScore(article, []string{"databases"}, interests); got != 85 {
+		t.Fatalf("unexpected score: %d", got)
+	}
+	if got := heuristicScore(article, nil, []string{"rust", "linux", "kernel"}); got != 100 {
+		t.Fatalf("expected capped score, got %d", got)
+	}
+	if got := heuristicScore(article, nil, nil); got != 0 {
+		t.Fatalf("expected zero score, got %d", got)
+	}
+}
+
+func TestParseScore(t *testing.T) {
+	if score, err := parseScore("Score: 72/100"); err != nil || score != 72 {
+		t.Fatalf("unexpected score: %d (%v)", score, err)
+	}
+	if score, err := parseScore("250"); err != nil || score != 100 {
+		t.Fatalf("expected clamped score, got %d (%v)", score, err)
+	}
+	if _, err := parseScore("very relevant"); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
+
+func TestSummarizerScoreRelevance(t *testing.T) {
+	var nilSummarizer *Summarizer
+	if _, err := nilSummarizer.ScoreRelevance("t", "c", nil); err == nil {
+		t.Fatalf("expected nil summarizer error")
+	}
+	if score, err := topicsSummarizer("64").ScoreRelevance("Title", "Body", []string{"go"}); err != nil || score != 64 {
+		t.Fatalf("unexpected score: %d (%v)", score, err)
+	}
+	failing := &Summarizer{baseURL: "http://example.test", model: "m", client: clientForResponse(http.StatusInternalServerError, "", nil)}
+	if _, err := failing.ScoreRelevance("Title", "Body", nil); err == nil {
+		t.Fatalf("expected http error")
+	}
+}
+
+func TestAppScoreArticlesAndRankedSort(t *testing.T) {
+	app, articles := seedTopicsApp(t)
+	if err := app.ScoreArticles(); err != nil || app.articles[0].Score != 0 {
+		t.Fatalf("expected no scoring without interests")
+	}
+	app.config.Interests = []string{"plants"}
+	if err := app.ScoreArticles(); err != nil {
+		t.Fatalf("ScoreArticles error: %v", err)
+	}
+	if err := app.ScoreArticles(); err != nil {
+		t.Fatalf("ScoreArticles rerun error: %v", err)
+	}
+	stored := map[int]int{}
+	for _, article := range app.store.SortedArticles() {
+		stored[article.ID] = article.Score
+	}
+	if stored[articles[1].ID] != 15 || stored[articles[0].ID] != 0 {
+		t.Fatalf("unexpected stored scores: %v", stored)
+	}
+
+	app.sortMode = SortOldest
+	app.ToggleSort()
+	if app.sortMode != SortRanked || app.FilteredArticles()[0].ID != articles[1].ID {
+		t.Fatalf("expected ranked sort")
+	}
+	app.ToggleSort()
+	if app.sortMode != SortNewest || app.status != "Sort: newest first" {
+		t.Fatalf("expected newest sort")
+	}
+	app.config.Interests = nil
+	app.sortMode = SortOldest
+	app.ToggleSort()
+	if !strings.Contains(app.status, "set interests") {
+		t.Fatalf("expected interests hint, got %q", app.status)
+	}
+}
+
+func TestAppScoreWithSummarizer(t *testing.T) {
+	app, articles := seedTopicsApp(t)
+	app.config.Interests = []string{"encryption"}
+	app.config.RelevanceScoring = "llm"
+	if err := app.ScoreArticles(); err != nil || app.articles[0].Score != 0 {
+		t.Fatalf("expected heuristic scoring skipped in llm mode")
+	}
+	app.summarizer = topicsSummarizer("91")
+	if err := app.ScoreWithSummarizer(articles[0]); err != nil {
+		t.Fatalf("ScoreWithSummarizer error: %v", err)
+	}
+	if article := app.findArticle(articles[0].ID); article == nil || article.Score != 91 {
+		t.Fatalf("expected score stored on article, got %+v", article)
+	}
+	app.config.RelevanceScoring = ""
+	if err := app.ScoreWithSummarizer(articles[0]); err != nil {
+		t.Fatalf("expected heuristic mode to skip llm scoring: %v", err)
+	}
+}
+
+func TestTUIRankedSortAndScores(t *testing.T) {
+	app, articles := seedTopicsApp(t)
+	app.config.Interests = []string{"encryption"}
+	app.config.RelevanceScoring = "llm"
+	app.summarizer = topicsSummarizer("77")
+	model := newTUIModel(app)
+	model.width = 120
+	model.height = 40
+
+	updated, cmd := model.Update(summaryResultMsg{articleID: articles[0].ID, summaryText: "- crypto"})
+	model = updated.(tuiModel)
+	if cmd == nil {
+		t.Fatalf("expected follow-up commands")
+	}
+	updated, _ = model.Update(scoreCmd(articles[0], app.config.Interests, app.summarizer)())
+	model = updated.(tuiModel)
+	if app.findArticle(articles[0].ID).Score != 77 {
+		t.Fatalf("expected score stored")
+	}
+	updated, _ = model.Update(scoreResultMsg{articleID: articles[0].ID, err: errors.New("boom")})
+	model = updated.(tuiModel)
+	if app.status != "Relevance scoring failed: boom" {
+		t.Fatalf("unexpected status: %q", app.status)
+	}
+
+	app.sortMode = SortOldest
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	model = updated.(tuiModel)
+	view := model.View()
+	if app.sortMode != SortRanked || !strings.Contains(view, " 77 Crypto") || !strings.Contains(view, "Relevance: 77/100") {
+		t.Fatalf("expected ranked list with scores")
+	}
+
+	var out bytes.Buffer
+	if err := handleCommand(app, "sort", &out); err != nil || app.sortMode != SortNewest {
+		t.Fatalf("expected sort command to toggle")
+	}
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+type configReloadMsg struct{}
+
+var reloadSignals = func() (<-chan os.Signal, func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	return ch, func() { signal.Stop(ch) }
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func writeReloadConfig(t *testing.T, content string) {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	path := filepath.Join(root, "greeder", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir error: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+}
+
+func TestAppReloadConfig(t *testing.T) {
+	t.Setenv("LM_BASE_URL", "")
+	app := newTUIApp(t)
+	dbPath := app.config.DBPath
+	app.notifiers = []*Notifier{{config: NotifierConfig{Name: "phone", Type: "telegram"}, telegramOffset: 12}}
+	writeReloadConfig(t, strings.Join([]string{
+		"db_path = \"/elsewhere/feeds.db\"",
+		"refresh_interval_minutes = 5",
+		"lm_base_url = \"http://lm.test/v1\"",
+		"lm_model = \"reloaded-model\"",
+		"[fetcher]",
+		"timeout_seconds = 9",
+		"[theme]",
+		"selected = \"#ff0000\"",
+		"[notify.phone]",
+		"type = \"telegram\"",
+		"bot_token = \"t\"",
+		"chat_id = \"1\"",
+	}, "\n"))
+	if err := app.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig error: %v", err)
+	}
+	if app.status != "config reloaded (db_path change needs a restart)" || app.config.DBPath != dbPath {
+		t.Fatalf("expected db_path kept, got %q %q", app.status, app.config.DBPath)
+	}
+	if app.config.RefreshIntervalMinutes != 5 || app.refreshInterval() != 5*time.Minute || app.summarizer == nil || app.summarizer.model != "reloaded-model" {
+		t.Fatalf("expected settings applied, got %+v", app.config)
+	}
+	if app.fetcher.client.Timeout != 9*time.Second || themeColor(app.config, "selected") != "#ff0000" {
+		t.Fatalf("expected fetcher and theme updated")
+	}
+	if len(app.notifiers) != 1 || app.notifiers[0].telegramOffset != 12 || app.notifiers[0].config.BotToken != "t" {
+		t.Fatalf("expected telegram offset carried over, got %+v", app.notifiers)
+	}
+
+	writeReloadConfig(t, "refresh_interval_minutes = soon")
+	if err := app.ReloadConfig(); err == nil || !strings.HasPrefix(app.status, "config reload failed") || app.config.RefreshIntervalMinutes != 5 {
+		t.Fatalf("expected failed reload to keep config, got %v %q", err, app.status)
+	}
+	var out bytes.Buffer
+	if err := handleCommand(app, "reload", &out); err == nil || !strings.Contains(out.String(), "config reload failed") {
+		t.Fatalf("expected reload command to report failure, got %q %v", out.String(), err)
+	}
+}
+
+func TestDaemonReloadOnSignal(t *testing.T) {
+	app := newTUIApp(t)
+	writeReloadConfig(t, "refresh_interval_minutes = 2\n")
+	reload := make(chan os.Signal, 1)
+	stopped := false
+	origSignals := reloadSignals
+	t.Cleanup(func() { reloadSignals = origSignals })
+	reloadSignals = func() (<-chan os.Signal, func()) { return reload, func() { stopped = true } }
+	stop := make(chan struct{})
+	intervals := []time.Duration{}
+	app.setClock(&testClock{now: time.Now(), after: func(d time.Duration) <-chan time.Time {
+		intervals = append(intervals, d)
+		switch len(intervals) {
+		case 1:
+			reload <- os.Interrupt
+		case 2:
+			close(stop)
+		}
+		return nil
+	}})
+	var out bytes.Buffer
+	if err := runDaemon(app, &out, stop); err != nil {
+		t.Fatalf("runDaemon error: %v", err)
+	}
+	if !stopped || !strings.Contains(out.String(), "config reloaded") || intervals[0] != 30*time.Minute || intervals[1] != 2*time.Minute {
+		t.Fatalf("unexpected reload handling %q %v", out.String(), intervals)
+	}
+}
+
+func TestTUIConfigReload(t *testing.T) {
+	app := newTUIApp(t)
+	writeReloadConfig(t, "db_path = "+strconv.Quote(app.config.DBPath)+"\n[keys]\nstar = \"g\"\n")
+	model := newTUIModel(app)
+	updated, _ := model.Update(configReloadMsg{})
+	model = updated.(tuiModel)
+	if model.keyRemap["g"] != "s" || app.status != "config reloaded" {
+		t.Fatalf("expected key remap rebuilt, got %+v %q", model.keyRemap, app.status)
+	}
+
+	origSignals, origNew, origRun := reloadSignals, teaNewProgram, runTeaProgram
+	t.Cleanup(func() {
+		reloadSignals = origSignals
+		teaNewProgram = origNew
+		runTeaProgram = origRun
+	})
+	stopped := false
+	reloadSignals = func() (<-chan os.Signal, func()) { return make(chan os.Signal), func() { stopped = true } }
+	teaNewProgram = func(m tea.Model, opts ...tea.ProgramOption) *tea.Program { return tea.NewProgram(m) }
+	runTeaProgram = func(program *tea.Program) (tea.Model, error) { return nil, errors.New("done") }
+	if err := RunTUI(app); err == nil || !stopped {
+		t.Fatalf("expected signal handler stopped after the TUI exits, got %v", err)
+	}
+}
+
+func TestTUIConfigReloadWaitsForRefresh(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "A", URL: "u"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	app.loadArticles()
+	writeReloadConfig(t, "db_path = "+strconv.Quote(app.config.DBPath)+"\n[keys]\nstar = \"g\"\n")
+	model := newTUIModel(app)
+	model.app.refreshPending = true
+
+	msg := refreshCmd(app, context.Background())()
+	if err := app.ToggleStar(); err != nil {
+		t.Fatalf("ToggleStar error: %v", err)
+	}
+	updated, _ := model.Update(configReloadMsg{})
+	model = updated.(tuiModel)
+	if model.keyRemap["g"] == "s" || !model.reloadQueued {
+		t.Fatalf("expected reload held during refresh, got %+v", model.keyRemap)
+	}
+	updated, _ = model.Update(msg)
+	model = updated.(tuiModel)
+	if model.reloadQueued || model.keyRemap["g"] != "s" || app.status != "config reloaded" {
+		t.Fatalf("expected reload after refresh, got %+v %q", model.keyRemap, app.status)
+	}
+	if len(app.articles) != 1 || !app.articles[0].IsStarred {
+		t.Fatalf("expected star made during refresh kept, got %+v", app.articles)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+func (p RetentionPolicy) merge(override RetentionPolicy) RetentionPolicy {
+	if override.Days >= 0 {
+		p.Days = override.Days
+	}
+	if override.Items >= 0 {
+		p.Items = override.Items
+	}
+	return p
+}
+
+func (a *App) applyRetention() {
+	defaults := RetentionPolicy{Days: a.config.RetentionDays, Items: a.config.RetentionItems}
+	removed, err := a.store.ApplyRetention(defaults, a.config.FeedRetention)
+	log := logFor("retention")
+	if err != nil {
+		log.Warn("retention failed", "err", err)
+		return
+	}
+	if removed > 0 {
+		log.Info("removed old articles", "count", removed)
+	}
+}
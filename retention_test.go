@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetentionPolicyMerge(t *testing.T) {
+	defaults := RetentionPolicy{Days: 7, Items: 100}
+	if got := defaults.merge(RetentionPolicy{Days: -1, Items: 5}); got != (RetentionPolicy{Days: 7, Items: 5}) {
+		t.Fatalf("unexpected merge %+v", got)
+	}
+	if got := defaults.merge(RetentionPolicy{Days: 0, Items: -1}); got != (RetentionPolicy{Days: 0, Items: 100}) {
+		t.Fatalf("unexpected merge %+v", got)
+	}
+}
+
+func TestRefreshAppliesRetention(t *testing.T) {
+	app := newTUIApp(t)
+	clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	app.setClock(clock)
+	feed, err := app.store.InsertFeed(Feed{Title: "Sample RSS", URL: "http://example.test/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "stale", Title: "Stale"}, {GUID: "starred", Title: "Starred", IsStarred: true}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	if err := app.store.SetArticlesStarred([]int{app.articles[1].ID}, true); err != nil {
+		t.Fatalf("SetArticlesStarred error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	app.fetcher.client = clientForResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"})
+	clock.now = clock.now.Add(30 * 24 * time.Hour)
+	app.config.RetentionDays = 0
+	app.config.FeedRetention = map[string]RetentionPolicy{feed.URL: {Days: 7, Items: -1}}
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("RefreshFeeds error: %v", err)
+	}
+	for _, article := range app.articles {
+		if article.GUID == "stale" {
+			t.Fatalf("expected stale article removed after refresh")
+		}
+	}
+	starred := false
+	for _, article := range app.articles {
+		starred = starred || article.GUID == "starred"
+	}
+	if !starred || len(app.articles) < 2 {
+		t.Fatalf("expected starred and fresh articles kept, got %+v", app.articles)
+	}
+}
+
+func TestParseConfigRetention(t *testing.T) {
+	cfg := DefaultConfig()
+	input := "[retention]\narticle_days = 14\narticle_items = 200\n\n[feeds.\"https://daily.example/rss\"]\nretention_items = 20\n\n[feeds.\"https://essays.example/rss\"]\nstyle = \"paragraph\"\nretention_days = 0\n"
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.RetentionDays != 14 || cfg.RetentionItems != 200 {
+		t.Fatalf("unexpected defaults %d %d", cfg.RetentionDays, cfg.RetentionItems)
+	}
+	if got := cfg.FeedRetention["https://daily.example/rss"]; got != (RetentionPolicy{Days: -1, Items: 20}) {
+		t.Fatalf("unexpected daily policy %+v", got)
+	}
+	if _, ok := cfg.FeedOverrides["https://daily.example/rss"]; ok {
+		t.Fatalf("retention-only section should not add a summary override")
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil {
+		t.Fatalf("parse rendered config error: %v", err)
+	}
+	if reparsed.RetentionItems != 200 || reparsed.FeedRetention["https://essays.example/rss"] != (RetentionPolicy{Days: 0, Items: -1}) || reparsed.FeedRetention["https://daily.example/rss"].Items != 20 || reparsed.FeedOverrides["https://essays.example/rss"].Style != "paragraph" {
+		t.Fatalf("retention did not round trip: %+v", reparsed.FeedRetention)
+	}
+	for _, bad := range []string{"[retention]\narticle_items = -1", "[feeds.\"https://x.example\"]\nretention_days = soon"} {
+		if err := parseConfig(bad, &cfg); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func (f *FeedFetcher) DetectBridge(bridgeURL, pageURL string) (string, error) {
+	base := strings.TrimRight(strings.TrimSpace(bridgeURL), "/")
+	if base == "" {
+		return "", errors.New("rss-bridge not configured")
+	}
+	query := url.Values{}
+	query.Set("action", "detect")
+	query.Set("format", "Atom")
+	query.Set("url", pageURL)
+	client := *f.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	req, err := http.NewRequestWithContext(f.requestContext(), http.MethodGet, base+"/?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		if location := resp.Header.Get("Location"); location != "" {
+			return resolveURL(base+"/", location), nil
+		}
+	}
+	if resp.StatusCode >= 500 {
+		return "", fmt.Errorf("rss-bridge: http %d", resp.StatusCode)
+	}
+	return "", errors.New("rss-bridge: no bridge for this site")
+}
+
+func (a *App) offerBridgeFeed(pageURL string, discoverErr error) error {
+	if strings.TrimSpace(a.config.RSSBridgeURL) == "" {
+		return discoverErr
+	}
+	bridgeFeed, err := a.fetcher.DetectBridge(a.config.RSSBridgeURL, pageURL)
+	if err != nil {
+		return discoverErr
+	}
+	a.bridgeOffer = bridgeFeed
+	a.status = "No feed found; RSS-Bridge can provide one: " + bridgeFeed
+	return nil
+}
+
+func (a *App) AcceptBridgeOffer() error {
+	offer := a.bridgeOffer
+	a.bridgeOffer = ""
+	if offer == "" {
+		return errors.New("no rss-bridge feed offered")
+	}
+	parsed, err := a.fetcher.FetchFeed(offer)
+	if err != nil {
+		return err
+	}
+	parsed.URL = offer
+	return a.addDiscoveredFeed(parsed)
+}
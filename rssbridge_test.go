@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func bridgeTestFetcher(t *testing.T) *FeedFetcher {
+	t.Helper()
+	return &FeedFetcher{client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		switch {
+		case r.URL.Host == "social.example.com":
+			return newResponse(http.StatusOK, "<html><body>no feeds here</body></html>", map[string]string{"content-type": "text/html"}, r), nil
+		case r.URL.Host == "bridge.test" && r.URL.Query().Get("action") == "detect":
+			page, _ := url.Parse(r.URL.Query().Get("url"))
+			if page == nil || page.Host != "social.example.com" || r.URL.Query().Get("format") != "Atom" {
+				return newResponse(http.StatusBadRequest, "", nil, r), nil
+			}
+			return newResponse(http.StatusMovedPermanently, "", map[string]string{"Location": "?action=display&bridge=Social&u=" + strings.TrimPrefix(page.Path, "/") + "&format=Atom"}, r), nil
+		case r.URL.Host == "bridge.test" && r.URL.Query().Get("action") == "display":
+			return newResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/atom+xml"}, r), nil
+		}
+		return newResponse(http.StatusNotFound, "", nil, r), nil
+	})}}
+}
+
+func TestDetectBridge(t *testing.T) {
+	fetcher := bridgeTestFetcher(t)
+	if feedURL, err := fetcher.DetectBridge("http://bridge.test/", "https://social.example.com/someone"); err != nil || feedURL != "http://bridge.test/?action=display&bridge=Social&u=someone&format=Atom" {
+		t.Fatalf("unexpected bridge feed %q %v", feedURL, err)
+	}
+	if _, err := fetcher.DetectBridge("http://bridge.test", "https://unsupported.example.com"); err == nil || !strings.Contains(err.Error(), "no bridge") {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+	if _, err := fetcher.DetectBridge(" ", "https://social.example.com/someone"); err == nil {
+		t.Fatalf("expected not configured error")
+	}
+	fetcher.client = clientForResponse(http.StatusInternalServerError, "", nil)
+	if _, err := fetcher.DetectBridge("http://bridge.test", "https://x"); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Fatalf("expected server error, got %v", err)
+	}
+	fetcher.client = &http.Client{Transport: &errorRoundTripper{}}
+	if _, err := fetcher.DetectBridge("http://bridge.test", "https://x"); err == nil {
+		t.Fatalf("expected transport error")
+	}
+}
+
+func TestAppBridgeOffer(t *testing.T) {
+	app := newTUIApp(t)
+	app.fetcher = bridgeTestFetcher(t)
+	if err := app.AddFeed("social.example.com/someone"); err == nil || err.Error() != "no feed link found" {
+		t.Fatalf("expected discovery error without bridge, got %v", err)
+	}
+	app.config.RSSBridgeURL = "http://bridge.test"
+	if err := app.AddFeed("https://unsupported.example.com"); err == nil || app.bridgeOffer != "" {
+		t.Fatalf("expected original error when no bridge matches, got %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := handleCommand(app, "add https://social.example.com/someone", &out); err != nil {
+		t.Fatalf("add command error: %v", err)
+	}
+	if !strings.Contains(out.String(), "RSS-Bridge can provide one") || !strings.Contains(out.String(), "run 'bridge'") {
+		t.Fatalf("expected bridge offer, got %q", out.String())
+	}
+	if err := handleCommand(app, "bridge", &out); err != nil || len(app.feeds) != 1 || app.feeds[0].URL != "http://bridge.test/?action=display&bridge=Social&u=someone&format=Atom" || app.status != "feed added" {
+		t.Fatalf("expected bridge feed added, got %v %+v", err, app.feeds)
+	}
+	if err := app.AcceptBridgeOffer(); err == nil {
+		t.Fatalf("expected no offer error")
+	}
+
+	model := newTUIModel(app)
+	model = model.startInput(inputAddFeed, "")
+	model.input.SetValue("https://social.example.com/someone")
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	if model.inputMode != inputBridgeFeed || model.inputPrompt() != "Add RSS-Bridge Feed" {
+		t.Fatalf("expected bridge confirmation prompt")
+	}
+	model.input.SetValue("n")
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	if app.bridgeOffer != "" || app.status != "RSS-Bridge feed not added" || len(app.feeds) != 1 {
+		t.Fatalf("expected declined offer, got %q", app.status)
+	}
+
+	model = model.startInput(inputAddFeed, "")
+	model.input.SetValue("https://social.example.com/other")
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	model.input.SetValue("y")
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	if len(app.feeds) != 2 {
+		t.Fatalf("expected accepted offer to add feed")
+	}
+
+	app.bridgeOffer = "http://bridge.test/?action=missing"
+	model = model.startInput(inputBridgeFeed, "")
+	model.input.SetValue("yes")
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	if !strings.HasPrefix(app.status, "Add feed failed") {
+		t.Fatalf("expected fetch failure, got %q", app.status)
+	}
+	app.bridgeOffer = "x"
+	model = model.startInput(inputBridgeFeed, "")
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.bridgeOffer != "" || app.status != "Input cancelled" {
+		t.Fatalf("expected cancel to clear offer")
+	}
+}
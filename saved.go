@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type savedEntry struct {
+	Saved
+	Title     string
+	URL       string
+	FeedTitle string
+}
+
+func (a *App) SavedEntries() []savedEntry {
+	entries := []savedEntry{}
+	for _, saved := range a.store.Saved() {
+		article := a.findArticle(saved.ArticleID)
+		if article == nil {
+			continue
+		}
+		entries = append(entries, savedEntry{Saved: saved, Title: article.Title, URL: article.URL, FeedTitle: article.FeedTitle})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].SavedAt.After(entries[j].SavedAt)
+	})
+	return entries
+}
+
+func (a *App) findSaved(articleID int) (savedEntry, error) {
+	for _, entry := range a.SavedEntries() {
+		if entry.ArticleID == articleID {
+			return entry, nil
+		}
+	}
+	return savedEntry{}, messageErr(msgSavedNotFound, articleID)
+}
+
+func (a *App) OpenSaved(articleID int) error {
+	entry, err := a.findSaved(articleID)
+	if err != nil {
+		return err
+	}
+	return a.openLink(entry.URL)
+}
+
+func (a *App) ResyncSavedTags(articleID int) error {
+	entry, err := a.findSaved(articleID)
+	if err != nil {
+		return err
+	}
+	tags := mergeSavedTags(entry.Tags, a.store.ArticleTagsFrom(articleID, tagSourceUser))
+	provider := firstNonEmpty(entry.Provider, "raindrop")
+	item := RaindropItem{Link: entry.URL, Title: entry.Title, Tags: tags}
+	if summary, ok := a.store.FindSummary(articleID); ok {
+		item.Note = summary.Content
+	}
+	switch provider {
+	case "raindrop":
+		err = a.raindrop.UpdateTags(entry.RaindropID, tags)
+	case "pocket":
+		_, err = a.pocket.Save(item)
+	case "pinboard":
+		_, err = a.pinboard.Save(item)
+	default:
+		return messageErr(msgSavedResyncUnsupported, savedProviderName(provider))
+	}
+	if err != nil {
+		return err
+	}
+	if err := a.store.SetSavedTags(articleID, tags); err != nil {
+		return err
+	}
+	a.status = tr(msgSavedResynced, len(tags), savedProviderName(provider))
+	return nil
+}
+
+func mergeSavedTags(saved, local []string) []string {
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, tag := range append(append([]string{}, saved...), local...) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func savedProviderName(provider string) string {
+	if provider == "" {
+		return saveTargetNames["raindrop"]
+	}
+	if name, ok := saveTargetNames[provider]; ok {
+		return name
+	}
+	return provider
+}
+
+func formatSavedEntry(entry savedEntry) string {
+	line := fmt.Sprintf("%d %s (%s, saved %s)", entry.ArticleID, valueOrFallback(entry.Title, entry.URL), savedProviderName(entry.Provider), formatLocalTime(entry.SavedAt))
+	if len(entry.Tags) > 0 {
+		line += " tags: " + strings.Join(entry.Tags, ", ")
+	}
+	return line
+}
+
+func formatSavedEntries(entries []savedEntry) string {
+	if len(entries) == 0 {
+		return tr(msgSavedNone)
+	}
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, formatSavedEntry(entry))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newSavedApp(t *testing.T) (*App, []Article) {
+	t.Helper()
+	app, _, _ := newFolderApp(t)
+	clock := &testClock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	app.setClock(clock)
+	articles := app.store.SortedArticles()
+	if err := app.store.SaveBookmark(articles[0].ID, "raindrop", 7, []string{"go"}); err != nil {
+		t.Fatalf("SaveBookmark error: %v", err)
+	}
+	clock.now = clock.now.Add(time.Hour)
+	if err := app.store.SaveBookmark(articles[1].ID, "omnivore", 0, nil); err != nil {
+		t.Fatalf("SaveBookmark error: %v", err)
+	}
+	return app, articles
+}
+
+func TestSavedEntries(t *testing.T) {
+	app, articles := newSavedApp(t)
+	entries := app.SavedEntries()
+	if len(entries) != 2 || entries[0].ArticleID != articles[1].ID || entries[1].URL != articles[0].URL {
+		t.Fatalf("expected newest first, got %+v", entries)
+	}
+	line := formatSavedEntry(entries[1])
+	if !strings.Contains(line, articles[0].Title+" (Raindrop, saved ") || !strings.HasSuffix(line, "tags: go") {
+		t.Fatalf("unexpected line %q", line)
+	}
+	var out strings.Builder
+	if err := handleCommand(app, "saved", &out); err != nil || strings.Count(out.String(), "\n") != 2 {
+		t.Fatalf("unexpected saved listing %q %v", out.String(), err)
+	}
+	if got := formatSavedEntries(nil); got != "No saved articles." {
+		t.Fatalf("unexpected empty listing %q", got)
+	}
+
+	opened := ""
+	app.openURL = func(url string) error {
+		opened = url
+		return nil
+	}
+	if err := handleCommand(app, "saved open "+strconv.Itoa(articles[0].ID), io.Discard); err != nil || opened != articles[0].URL {
+		t.Fatalf("expected saved article opened, got %q %v", opened, err)
+	}
+	if err := app.OpenSaved(articles[2].ID); err == nil || err.Error() != "article "+strconv.Itoa(articles[2].ID)+" is not saved" {
+		t.Fatalf("expected not saved error, got %v", err)
+	}
+	for _, command := range []string{"saved open", "saved drop 1", "saved sync x"} {
+		if err := handleCommand(app, command, io.Discard); err == nil {
+			t.Fatalf("expected usage error for %q", command)
+		}
+	}
+}
+
+func TestResyncSavedTags(t *testing.T) {
+	app, articles := newSavedApp(t)
+	var method, path string
+	var body map[string][]string
+	app.raindrop = &RaindropClient{baseURL: "http://example.test", token: "token", client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		method, path = r.Method, r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		return newResponse(http.StatusOK, `{"result":true}`, nil, r), nil
+	})}}
+	app.selectedIndex = 0
+	if err := app.TagSelected("later, Go"); err != nil {
+		t.Fatalf("TagSelected error: %v", err)
+	}
+	if err := handleCommand(app, "saved sync "+strconv.Itoa(articles[0].ID), io.Discard); err != nil {
+		t.Fatalf("saved sync error: %v", err)
+	}
+	if method != http.MethodPut || path != "/rest/v1/raindrop/7" || strings.Join(body["tags"], ",") != "go,later" {
+		t.Fatalf("unexpected request %s %s %v", method, path, body)
+	}
+	if app.status != "Synced 2 tags to Raindrop" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	if saved := app.store.Saved(); len(saved[0].Tags) != 2 {
+		t.Fatalf("expected stored tags updated, got %+v", saved)
+	}
+	if err := app.ResyncSavedTags(articles[1].ID); err == nil || err.Error() != "Omnivore does not support tag updates" {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+	app.raindrop = nil
+	if err := app.ResyncSavedTags(articles[0].ID); err == nil {
+		t.Fatalf("expected not configured error")
+	}
+}
+
+func TestRaindropUpdateTags(t *testing.T) {
+	var nilClient *RaindropClient
+	if err := nilClient.UpdateTags(1, nil); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+	client := &RaindropClient{baseURL: "http://example.test", token: "token", client: clientForResponse(http.StatusNotFound, `{}`, nil)}
+	if err := client.UpdateTags(1, []string{"go"}); err == nil {
+		t.Fatalf("expected http error")
+	}
+	client.client = &http.Client{Transport: &raindropErrorRoundTripper{}}
+	if err := client.UpdateTags(1, []string{"go"}); err == nil {
+		t.Fatalf("expected transport error")
+	}
+}
+
+func TestTUISavedView(t *testing.T) {
+	app, articles := newSavedApp(t)
+	opened := ""
+	app.openURL = func(url string) error {
+		opened = url
+		return nil
+	}
+	model := newTUIModel(app)
+	model.width, model.height = 140, 30
+	press := func(key string) {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		model = updated.(tuiModel)
+	}
+	press("B")
+	if !model.showSaved || !strings.Contains(model.View(), "Saved (2)") || !strings.Contains(model.View(), "o open, s sync tags") {
+		t.Fatalf("expected saved view:\n%s", model.View())
+	}
+	press("j")
+	press("o")
+	if opened != articles[0].URL {
+		t.Fatalf("expected second entry opened, got %q", opened)
+	}
+	press("k")
+	press("s")
+	if model.app.status != "Tag sync failed: Omnivore does not support tag updates" {
+		t.Fatalf("unexpected status %q", model.app.status)
+	}
+	press("B")
+	if model.showSaved {
+		t.Fatalf("expected saved view closed")
+	}
+}
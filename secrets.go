@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+type secretBackend interface {
+	Name() string
+	Get(name string) (string, error)
+	Set(name string, value string) error
+}
+
+type commandSecretBackend struct {
+	goos string
+}
+
+type nativeSecretBackend struct{}
+
+var (
+	secretStoreRun  = defaultSecretStoreRun
+	secretBackendOS = runtime.GOOS
+	readSecretInput = defaultReadSecretInput
+)
+
+var secretAliases = map[string]string{
+	"lm":       "lm_api_key",
+	"raindrop": "raindrop_token",
+	"pinboard": "pinboard_token",
+	"mastodon": "mastodon_token",
+	"imap":     "imap_password",
+	"omnivore": "omnivore_api_key",
+	"zotero":   "zotero_api_key",
+	"pocket":   "pocket_access_token",
+}
+
+func secretBackendForOS(goos string) secretBackend {
+	switch goos {
+	case "windows":
+		return nativeSecretBackend{}
+	case "darwin", "linux", "freebsd", "openbsd", "netbsd":
+		return commandSecretBackend{goos: goos}
+	default:
+		return nil
+	}
+}
+
+func (b commandSecretBackend) Name() string {
+	if b.goos == "darwin" {
+		return "macOS Keychain"
+	}
+	return "Secret Service"
+}
+
+func (b commandSecretBackend) Get(name string) (string, error) {
+	cmdName, args := keyringCommandForOS(b.goos, name)
+	out, err := credentialRun(cmdName, args, nil)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (b commandSecretBackend) Set(name string, value string) error {
+	if b.goos == "darwin" {
+		// Feed the command to security's interactive mode on stdin, hex-encoded
+		// with -X, so the secret never shows up in the process list.
+		command := fmt.Sprintf("add-generic-password -U -s %q -a %q -X %s\n", keyringService, name, hex.EncodeToString([]byte(value)))
+		return secretStoreRun("security", []string{"-i"}, command)
+	}
+	return secretStoreRun("secret-tool", []string{"store", "--label", keyringService + " " + name, "service", keyringService, "account", name}, value)
+}
+
+func (nativeSecretBackend) Name() string {
+	return "Windows Credential Manager"
+}
+
+func (nativeSecretBackend) Get(name string) (string, error) {
+	return nativeSecretGet(keyringService + ":" + name)
+}
+
+func (nativeSecretBackend) Set(name string, value string) error {
+	return nativeSecretSet(keyringService+":"+name, value)
+}
+
+func defaultSecretStoreRun(name string, args []string, input string) error {
+	command := execCommand(name, args...)
+	command.Stdin = strings.NewReader(input)
+	out, err := command.CombinedOutput()
+	if err != nil && len(strings.TrimSpace(string(out))) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return err
+}
+
+func defaultReadSecretInput(stdin io.Reader) (string, error) {
+	if file, ok := stdin.(*os.File); ok && isTerminalFile(file) {
+		secret, err := term.ReadPassword(file.Fd())
+		return string(secret), err
+	}
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return line, nil
+}
+
+func secretName(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if name, ok := secretAliases[strings.ToLower(raw)]; ok {
+		return name
+	}
+	return raw
+}
+
+func configSecretField(cfg *Config, name string) *string {
+	switch name {
+	case "lm_api_key":
+		return &cfg.LMAPIKey
+	case "raindrop_token":
+		return &cfg.RaindropToken
+	case "pinboard_token":
+		return &cfg.PinboardToken
+	case "mastodon_token":
+		return &cfg.MastodonToken
+	case "imap_password":
+		return &cfg.IMAPPassword
+	case "omnivore_api_key":
+		return &cfg.OmnivoreAPIKey
+	case "archive_token":
+		return &cfg.ArchiveToken
+	case "archive_password":
+		return &cfg.ArchivePassword
+	case "sync_token":
+		return &cfg.SyncToken
+	case "sync_password":
+		return &cfg.SyncPassword
+	case "zotero_api_key":
+		return &cfg.ZoteroAPIKey
+	case "pocket_access_token":
+		return &cfg.PocketAccessToken
+	}
+	return nil
+}
+
+// saveSecretConfig turns keyring lookups on in the config file and drops any
+// plaintext copy of the secret, leaving every other line untouched.
+func saveSecretConfig(raw string) error {
+	name := secretName(raw)
+	values := map[string]string{"keyring": "true"}
+	if configSecretField(&Config{}, name) != nil {
+		values[name] = ""
+	}
+	return patchConfigFile(values)
+}
+
+func SetSecret(cfg Config, raw string, stdin io.Reader, stdout io.Writer) (Config, error) {
+	name := secretName(raw)
+	if name == "" {
+		return cfg, messageErr(msgSecretMissingName)
+	}
+	backend := secretBackendForOS(secretBackendOS)
+	if backend == nil {
+		return cfg, messageErr(msgSecretsUnsupported, secretBackendOS)
+	}
+	fmt.Fprint(stdout, tr(msgSecretPrompt, name))
+	value, err := readSecretInput(stdin)
+	fmt.Fprintln(stdout)
+	if err != nil {
+		return cfg, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return cfg, messageErr(msgSecretEmpty)
+	}
+	if err := backend.Set(name, value); err != nil {
+		return cfg, fmt.Errorf("%s: %w", backend.Name(), err)
+	}
+	fmt.Fprintln(stdout, tr(msgSecretStored, name, backend.Name()))
+	cfg.Keyring = true
+	if field := configSecretField(&cfg, name); field != nil && *field != "" {
+		*field = ""
+		fmt.Fprintln(stdout, tr(msgSecretPlaintextRemoved, name))
+	}
+	return cfg, nil
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package main
+
+import "errors"
+
+func nativeSecretGet(string) (string, error) {
+	return "", errors.New("no native credential store")
+}
+
+func nativeSecretSet(string, string) error {
+	return errors.New("no native credential store")
+}
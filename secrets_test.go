@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+type secretStoreCall struct {
+	name  string
+	args  []string
+	input string
+}
+
+func stubSecretStore(t *testing.T, goos string, err error) *[]secretStoreCall {
+	t.Helper()
+	calls := []secretStoreCall{}
+	origRun, origOS := secretStoreRun, secretBackendOS
+	secretStoreRun = func(name string, args []string, input string) error {
+		calls = append(calls, secretStoreCall{name: name, args: args, input: input})
+		return err
+	}
+	secretBackendOS = goos
+	t.Cleanup(func() {
+		secretStoreRun = origRun
+		secretBackendOS = origOS
+	})
+	return &calls
+}
+
+func TestSetSecretStoresInSecretService(t *testing.T) {
+	calls := stubSecretStore(t, "linux", nil)
+	var out strings.Builder
+	cfg, err := SetSecret(Config{RaindropToken: "plain"}, "Raindrop", strings.NewReader("s3cret\n"), &out)
+	if err != nil {
+		t.Fatalf("SetSecret error: %v", err)
+	}
+	if len(*calls) != 1 || (*calls)[0].name != "secret-tool" || (*calls)[0].input != "s3cret" || (*calls)[0].args[len((*calls)[0].args)-1] != "raindrop_token" {
+		t.Fatalf("unexpected store calls %+v", *calls)
+	}
+	if !cfg.Keyring || cfg.RaindropToken != "" {
+		t.Fatalf("expected keyring enabled and plaintext cleared, got %+v", cfg)
+	}
+	if !strings.Contains(out.String(), "Stored raindrop_token in Secret Service") || !strings.Contains(out.String(), "Removed plaintext raindrop_token") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestSetSecretStoresInKeychain(t *testing.T) {
+	calls := stubSecretStore(t, "darwin", nil)
+	cfg, err := SetSecret(Config{}, "provider.hosted.api_key", strings.NewReader("key"), &strings.Builder{})
+	if err != nil || !cfg.Keyring {
+		t.Fatalf("SetSecret error: %v", err)
+	}
+	call := (*calls)[0]
+	if call.name != "security" || strings.Join(call.args, " ") != "-i" || call.input != `add-generic-password -U -s "greeder" -a "provider.hosted.api_key" -X 6b6579`+"\n" {
+		t.Fatalf("unexpected keychain call %+v", *calls)
+	}
+}
+
+func TestSetSecretErrors(t *testing.T) {
+	stubSecretStore(t, "plan9", nil)
+	if _, err := SetSecret(Config{}, "lm", strings.NewReader("key"), &strings.Builder{}); err == nil || !strings.Contains(err.Error(), "no system keychain supported on plan9") {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+	stubSecretStore(t, "linux", errors.New("exit status 1"))
+	if _, err := SetSecret(Config{}, "", strings.NewReader("key"), &strings.Builder{}); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+	if _, err := SetSecret(Config{}, "lm", strings.NewReader("\n"), &strings.Builder{}); err == nil || err.Error() != "empty secret" {
+		t.Fatalf("expected empty secret error, got %v", err)
+	}
+	cfg, err := SetSecret(Config{LMAPIKey: "plain"}, "lm", strings.NewReader("key"), &strings.Builder{})
+	if err == nil || !strings.Contains(err.Error(), "Secret Service") || cfg.LMAPIKey != "plain" || cfg.Keyring {
+		t.Fatalf("expected store failure to leave config alone, got %+v %v", cfg, err)
+	}
+}
+
+func TestSecretBackends(t *testing.T) {
+	if backend := secretBackendForOS("windows"); backend == nil || backend.Name() != "Windows Credential Manager" {
+		t.Fatalf("unexpected windows backend %v", backend)
+	}
+	if backend := secretBackendForOS("darwin"); backend == nil || backend.Name() != "macOS Keychain" {
+		t.Fatalf("unexpected darwin backend %v", backend)
+	}
+	if backend := secretBackendForOS("plan9"); backend != nil {
+		t.Fatalf("expected no backend")
+	}
+	stubCredentialRun(t, func(name string, args []string, env []string) (string, error) {
+		if name != "secret-tool" || args[len(args)-1] != "raindrop_token" {
+			t.Fatalf("unexpected lookup %s %v", name, args)
+		}
+		return "from-keyring\n", nil
+	})
+	if value, err := secretBackendForOS("linux").Get("raindrop_token"); err != nil || value != "from-keyring" {
+		t.Fatalf("unexpected secret %q %v", value, err)
+	}
+	if secretName(" LM ") != "lm_api_key" || secretName("account.work") != "account.work" {
+		t.Fatalf("unexpected secret names")
+	}
+	if value, err := defaultReadSecretInput(strings.NewReader("token")); err != nil || value != "token" {
+		t.Fatalf("unexpected piped secret %q %v", value, err)
+	}
+}
+
+func TestRunMainSetSecret(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+	stubSecretStore(t, "linux", nil)
+	cfg := DefaultConfig()
+	cfg.RaindropToken = "plain"
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig error: %v", err)
+	}
+	data, err := os.ReadFile(configPath())
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if err := os.WriteFile(configPath(), append([]byte("# my settings\n"), data...), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("GREEDER_LM_API_KEY", "env-secret")
+	var stdout, stderr strings.Builder
+	if err := runMain([]string{"--set-secret", "raindrop"}, strings.NewReader("s3cret\n"), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain error: %v (%s)", err, stderr.String())
+	}
+	data, err = os.ReadFile(configPath())
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# my settings\n") || strings.Contains(string(data), "env-secret") {
+		t.Fatalf("expected only the secret fields patched: %s", data)
+	}
+	if strings.Contains(string(data), "raindrop_token") || !strings.Contains(string(data), "keyring = true") {
+		t.Fatalf("unexpected config %s", data)
+	}
+}
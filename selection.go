@@ -0,0 +1,156 @@
+package main
+
+import "strings"
+
+func (a *App) ToggleSelection() {
+	article := a.SelectedArticle()
+	if article == nil {
+		return
+	}
+	if a.selection == nil {
+		a.selection = map[int]bool{}
+	}
+	if a.selection[article.ID] {
+		delete(a.selection, article.ID)
+	} else {
+		a.selection[article.ID] = true
+	}
+}
+
+func (a *App) ClearSelection() {
+	a.selection = nil
+}
+
+func (a *App) selectionArticles() []Article {
+	selected := []Article{}
+	for _, article := range a.FilteredArticles() {
+		if a.selection[article.ID] {
+			selected = append(selected, article)
+		}
+	}
+	return selected
+}
+
+func (a *App) SelectionCount() int {
+	return len(a.selectionArticles())
+}
+
+func (a *App) selectArticleID(id int) bool {
+	for i, shown := range a.FilteredArticles() {
+		if shown.ID == id {
+			a.selectedIndex = i
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) eachSelected(fn func() error) (int, int, error) {
+	articles := a.selectionArticles()
+	current := a.SelectedArticle()
+	done, failed := 0, 0
+	var lastErr error
+	for _, article := range articles {
+		if !a.selectArticleID(article.ID) {
+			continue
+		}
+		a.syncSummaryForSelection()
+		if err := fn(); err != nil {
+			failed++
+			lastErr = err
+			continue
+		}
+		done++
+	}
+	if current != nil {
+		a.selectArticleID(current.ID)
+	}
+	a.syncSummaryForSelection()
+	return done, failed, lastErr
+}
+
+func (a *App) MarkSelectionRead() error {
+	ids := []int{}
+	for _, article := range a.selectionArticles() {
+		ids = append(ids, article.ID)
+	}
+	if err := a.store.SetArticlesRead(ids, true); err != nil {
+		return err
+	}
+	for i := range a.articles {
+		if a.selection[a.articles[i].ID] {
+			a.articles[i].IsRead = true
+		}
+	}
+	if count := len(a.FilteredArticles()); a.selectedIndex >= count {
+		a.selectedIndex = max(count-1, 0)
+	}
+	a.syncSummaryForSelection()
+	a.ClearSelection()
+	a.status = tr(msgBulkMarkedRead, len(ids))
+	return nil
+}
+
+func (a *App) DeleteSelection() error {
+	batch := []int{}
+	var err error
+	for _, article := range a.selectionArticles() {
+		var trashID int
+		if _, trashID, err = a.store.TrashArticle(article.ID); err != nil {
+			break
+		}
+		delete(a.summaryPending, article.ID)
+		batch = append(batch, trashID)
+	}
+	if len(batch) > 0 {
+		a.lastDeleted = batch
+	}
+	_ = a.store.CompactQueue()
+	a.queue = a.store.QueuePositions()
+	a.loadArticles()
+	if count := len(a.FilteredArticles()); a.selectedIndex >= count {
+		a.selectedIndex = max(count-1, 0)
+	}
+	a.syncSummaryForSelection()
+	a.ClearSelection()
+	if err != nil {
+		a.status = failureStatus(msgActionDelete, err)
+		return err
+	}
+	a.status = tr(msgSelectionDeleted, len(batch))
+	return nil
+}
+
+func (a *App) BookmarkSelection(target string, tags []string) error {
+	done, failed, err := a.eachSelected(func() error {
+		return a.SaveBookmarkTo(target, tags)
+	})
+	a.ClearSelection()
+	if err != nil {
+		a.status = tr(msgSelectionSaveFailed, done, done+failed, err)
+		return err
+	}
+	a.status = tr(msgSelectionSaved, done)
+	return nil
+}
+
+func (a *App) TagSelection(raw string) error {
+	raw = strings.TrimSpace(raw)
+	tags := []string{}
+	if raw != "-" {
+		tags = strings.Split(raw, ",")
+	}
+	tagged := 0
+	for _, article := range a.selectionArticles() {
+		if err := a.store.SetArticleTags(article.ID, tagSourceUser, tags); err != nil {
+			return err
+		}
+		tagged++
+	}
+	if a.tagFilter != "" {
+		a.tagged = a.store.ArticleIDsWithTag(a.tagFilter)
+	}
+	a.ClearSelection()
+	a.status = tr(msgSelectionTagged, tagged)
+	return nil
+}
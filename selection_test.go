@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTUIMultiSelect(t *testing.T) {
+	app := newTUIApp(t)
+	app.filter = FilterAll
+	insertQueueArticles(t, app.store, 4)
+	app.loadArticles()
+	model := newTUIModel(app)
+	model.width, model.height = 100, 30
+	press := func(key string) {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if key == " " {
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(key)}
+		}
+		updated, _ := model.Update(msg)
+		model = updated.(tuiModel)
+	}
+	articles := app.FilteredArticles()
+
+	press("v")
+	press("j")
+	press("j")
+	press(" ")
+	if !model.selecting || app.SelectionCount() != 2 || !app.selection[articles[0].ID] || !app.selection[articles[2].ID] {
+		t.Fatalf("expected two selected articles, got %v", app.selection)
+	}
+	if tip := model.tooltipText(); !strings.HasPrefix(tip, "2 selected") {
+		t.Fatalf("unexpected tooltip %q", tip)
+	}
+	if view := model.renderList(60); !strings.Contains(view, "▸✓") || !strings.Contains(view, " ✓") {
+		t.Fatalf("expected selection marks in list:\n%s", view)
+	}
+	press("m")
+	if model.selecting || len(app.selection) != 0 || app.status != "Marked 2 articles read" {
+		t.Fatalf("expected selection cleared after mark read, got %v %q", app.selection, app.status)
+	}
+	for _, article := range app.store.SortedArticles() {
+		want := article.ID == articles[0].ID || article.ID == articles[2].ID
+		if article.IsRead != want {
+			t.Fatalf("unexpected read state for %s: %v", article.Title, article.IsRead)
+		}
+	}
+
+	press(" ")
+	press("k")
+	press(" ")
+	press("t")
+	model.input.SetValue("go, rss")
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	if model.selecting || app.status != "Tagged 2 articles" {
+		t.Fatalf("expected tagged selection, got %q", app.status)
+	}
+	if tags := app.store.ArticleTagsFrom(articles[1].ID, tagSourceUser); strings.Join(tags, ",") != "go,rss" {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+
+	press("v")
+	press("b")
+	model.input.SetValue("later")
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	if model.selecting || app.status != "Saved 0 of 1 articles: raindrop not configured" {
+		t.Fatalf("expected bookmark failure for the selection, got %q", app.status)
+	}
+
+	press("v")
+	press("enter")
+	if model.selecting || app.status != tr(msgSummarizerMissing) {
+		t.Fatalf("expected summarize to need a summarizer, got %q", app.status)
+	}
+
+	press("v")
+	press("v")
+	if model.selecting || len(app.selection) != 0 {
+		t.Fatalf("expected v to end selection")
+	}
+	press(" ")
+	press("j")
+	press(" ")
+	press("d")
+	if model.selecting || app.status != "Deleted 2 articles" || len(app.FilteredArticles()) != 2 {
+		t.Fatalf("expected two articles deleted, got %q %d", app.status, len(app.FilteredArticles()))
+	}
+	other := app.FilteredArticles()[0]
+	if _, err := app.store.DeleteArticle(other.ID); err != nil {
+		t.Fatalf("DeleteArticle error: %v", err)
+	}
+	press("u")
+	trash := app.store.Deleted()
+	if app.status != "2 articles restored" || len(app.FilteredArticles()) != 3 || len(trash) != 1 || trash[0].GUID != other.GUID {
+		t.Fatalf("expected undo to restore exactly the selection, got %q %d %+v", app.status, len(app.FilteredArticles()), trash)
+	}
+	app.lastDeleted = []int{trash[0].ID}
+	if err := app.RestoreDeleted(trash[0].ID); err != nil || app.lastDeleted != nil || len(app.FilteredArticles()) != 4 {
+		t.Fatalf("expected the trash restore to forget the undo batch, got %v %v", err, app.lastDeleted)
+	}
+
+	articles = app.FilteredArticles()
+	if _, err := app.store.db.Exec(fmt.Sprintf(`CREATE TRIGGER articles_delete_block BEFORE DELETE ON articles WHEN OLD.id = %d BEGIN SELECT RAISE(FAIL, 'no'); END;`, articles[1].ID)); err != nil {
+		t.Fatalf("trigger error: %v", err)
+	}
+	app.selectedIndex = 0
+	press(" ")
+	press("j")
+	press(" ")
+	press("d")
+	if model.selecting || len(app.selection) != 0 || !strings.HasPrefix(app.status, "Delete failed:") || len(app.FilteredArticles()) != 3 {
+		t.Fatalf("expected a failed delete to reload the list, got %q %d", app.status, len(app.FilteredArticles()))
+	}
+	press("u")
+	if app.status != tr(msgArticleRestored) || len(app.FilteredArticles()) != 4 {
+		t.Fatalf("expected undo to restore the partial delete, got %q %d", app.status, len(app.FilteredArticles()))
+	}
+	press(" ")
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(tuiModel)
+	if model.selecting || len(app.selection) != 0 {
+		t.Fatalf("expected esc to clear the selection")
+	}
+}
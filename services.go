@@ -2,12 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,13 +18,25 @@ type RaindropClient struct {
 	baseURL string
 	token   string
 	client  *http.Client
+	ctx     context.Context
 }
 
 type RaindropItem struct {
-	Link  string   `json:"link"`
-	Title string   `json:"title"`
-	Tags  []string `json:"tags"`
-	Note  string   `json:"note"`
+	Link       string                 `json:"link"`
+	Title      string                 `json:"title"`
+	Tags       []string               `json:"tags"`
+	Note       string                 `json:"note"`
+	Collection *raindropCollectionRef `json:"collection,omitempty"`
+}
+
+type raindropCollectionRef struct {
+	ID int `json:"$id"`
+}
+
+type RaindropCollection struct {
+	ID    int
+	Title string
+	Count int
 }
 
 type raindropResponse struct {
@@ -31,6 +45,14 @@ type raindropResponse struct {
 	} `json:"item"`
 }
 
+type raindropCollectionsResponse struct {
+	Items []struct {
+		ID    int    `json:"_id"`
+		Title string `json:"title"`
+		Count int    `json:"count"`
+	} `json:"items"`
+}
+
 var servicesJSONMarshal = json.Marshal
 var execCommand = exec.Command
 var clipboardRun = defaultClipboardRun
@@ -58,6 +80,22 @@ func defaultClipboardRun(cmd string, args []string, input string) error {
 	return command.Run()
 }
 
+func (r *RaindropClient) withContext(ctx context.Context) *RaindropClient {
+	if r == nil {
+		return nil
+	}
+	bound := *r
+	bound.ctx = ctx
+	return &bound
+}
+
+func (r *RaindropClient) requestContext() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
 func (r *RaindropClient) Save(item RaindropItem) (int, error) {
 	if r == nil {
 		return 0, errors.New("raindrop not configured")
@@ -67,7 +105,7 @@ func (r *RaindropClient) Save(item RaindropItem) (int, error) {
 		return 0, err
 	}
 	endpoint := r.baseURL + "/rest/v1/raindrop"
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(blob))
+	req, err := http.NewRequestWithContext(r.requestContext(), http.MethodPost, endpoint, bytes.NewReader(blob))
 	if err != nil {
 		return 0, err
 	}
@@ -88,6 +126,63 @@ func (r *RaindropClient) Save(item RaindropItem) (int, error) {
 	return parsed.Item.ID, nil
 }
 
+func (r *RaindropClient) UpdateTags(id int, tags []string) error {
+	if r == nil {
+		return errors.New("raindrop not configured")
+	}
+	blob, err := servicesJSONMarshal(map[string][]string{"tags": tags})
+	if err != nil {
+		return err
+	}
+	endpoint := r.baseURL + "/rest/v1/raindrop/" + strconv.Itoa(id)
+	req, err := http.NewRequestWithContext(r.requestContext(), http.MethodPut, endpoint, bytes.NewReader(blob))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("authorization", "Bearer "+r.token)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("raindrop http error")
+	}
+	return nil
+}
+
+func (r *RaindropClient) ListCollections() ([]RaindropCollection, error) {
+	if r == nil {
+		return nil, errors.New("raindrop not configured")
+	}
+	collections := []RaindropCollection{}
+	for _, path := range []string{"/rest/v1/collections", "/rest/v1/collections/childrens"} {
+		req, err := http.NewRequestWithContext(r.requestContext(), http.MethodGet, r.baseURL+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("authorization", "Bearer "+r.token)
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var parsed raindropCollectionsResponse
+		err = json.NewDecoder(resp.Body).Decode(&parsed)
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, errors.New("raindrop http error")
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range parsed.Items {
+			collections = append(collections, RaindropCollection{ID: item.ID, Title: item.Title, Count: item.Count})
+		}
+	}
+	return collections, nil
+}
+
 func defaultOpenURL(target string) error {
 	return defaultOpenURLForOS(runtime.GOOS, target)
 }
@@ -112,18 +207,28 @@ func copyToClipboard(text string) error {
 	if strings.TrimSpace(text) == "" {
 		return errors.New("empty text")
 	}
-	commands := clipboardCommands(runtime.GOOS)
-	if len(commands) == 0 {
-		return errors.New("clipboard not supported")
+	remote := remoteSession()
+	if remote {
+		if err := copyOSC52(text); err == nil {
+			return nil
+		}
 	}
-	var lastErr error
-	for _, cmd := range commands {
+	if err := nativeClipboard(text); err == nil {
+		return nil
+	}
+	lastErr := errors.New("clipboard not supported")
+	for _, cmd := range clipboardCommands(runtime.GOOS) {
 		if err := clipboardRun(cmd.name, cmd.args, text); err == nil {
 			return nil
 		} else {
 			lastErr = err
 		}
 	}
+	if !remote {
+		if err := copyOSC52(text); err == nil {
+			return nil
+		}
+	}
 	return lastErr
 }
 
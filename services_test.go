@@ -198,3 +198,43 @@ func TestClipboardHelperProcess(t *testing.T) {
 	}
 	os.Exit(0)
 }
+
+func raindropCollectionsClient() *http.Client {
+	return &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/rest/v1/collections":
+			return newResponse(http.StatusOK, `{"items":[{"_id":10,"title":"Reading","count":3}]}`, nil, r), nil
+		case "/rest/v1/collections/childrens":
+			return newResponse(http.StatusOK, `{"items":[{"_id":11,"title":"Go","count":1}]}`, nil, r), nil
+		}
+		return newResponse(http.StatusNotFound, "", nil, r), nil
+	})}
+}
+
+func TestRaindropListCollections(t *testing.T) {
+	client := &RaindropClient{baseURL: "http://example.test", token: "token", client: raindropCollectionsClient()}
+	collections, err := client.ListCollections()
+	if err != nil || len(collections) != 2 || collections[0].Title != "Reading" || collections[1].ID != 11 || collections[0].Count != 3 {
+		t.Fatalf("unexpected collections %+v %v", collections, err)
+	}
+	var nilClient *RaindropClient
+	if _, err := nilClient.ListCollections(); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+	client.client = clientForResponse(http.StatusUnauthorized, `{}`, nil)
+	if _, err := client.ListCollections(); err == nil {
+		t.Fatalf("expected http error")
+	}
+	client.client = clientForResponse(http.StatusOK, `nope`, nil)
+	if _, err := client.ListCollections(); err == nil {
+		t.Fatalf("expected decode error")
+	}
+	client.client = &http.Client{Transport: &raindropErrorRoundTripper{}}
+	if _, err := client.ListCollections(); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	client = &RaindropClient{baseURL: "http://[::1", token: "token", client: http.DefaultClient}
+	if _, err := client.ListCollections(); err == nil {
+		t.Fatalf("expected request error")
+	}
+}
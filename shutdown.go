@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+type shutdownMsg struct{}
+
+type shutdownTimeoutMsg struct{}
+
+var (
+	shutdownTimeout = 5 * time.Second
+	shutdownSignals = func() (<-chan os.Signal, func()) {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGTERM, os.Interrupt)
+		return ch, func() { signal.Stop(ch) }
+	}
+)
+
+func (a *App) restoreSession() {
+	session, ok := a.store.LoadSession()
+	if !ok {
+		return
+	}
+	switch session.Filter {
+	case FilterAll, FilterUnread, FilterStarred:
+		a.filter = session.Filter
+	}
+	if validSortMode(session.SortMode) && session.SortMode != a.sortMode {
+		a.sortMode = session.SortMode
+		a.loadArticles()
+	}
+	a.selectedIndex = 0
+	for i, article := range a.FilteredArticles() {
+		if article.ID == session.ArticleID {
+			a.selectedIndex = i
+			break
+		}
+	}
+	a.syncSummaryForSelection()
+}
+
+func (a *App) saveSession() error {
+	session := Session{Filter: a.filter, SortMode: a.sortMode}
+	if article := a.SelectedArticle(); article != nil {
+		session.ArticleID = article.ID
+	}
+	return a.store.SaveSession(session)
+}
+
+func (a *App) Shutdown(timeout time.Duration) error {
+	a.speaker.Stop()
+	done := make(chan error, 1)
+	go func() {
+		err := a.saveSession()
+		if closeErr := a.store.Close(); err == nil {
+			err = closeErr
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			logFor("main").Error("shutdown failed", "err", err)
+		}
+		return err
+	case <-time.After(timeout):
+		logFor("main").Error("shutdown timed out", "timeout", timeout)
+		return errors.New("shutdown timed out")
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newSessionApp(t *testing.T) (*App, []Article) {
+	t.Helper()
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "One", URL: "https://example.com/1", PublishedAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{GUID: "2", Title: "Two", URL: "https://example.com/2", PublishedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{GUID: "3", Title: "Three", URL: "https://example.com/3", PublishedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	return app, articles
+}
+
+func TestShutdownSavesAndRestoresSession(t *testing.T) {
+	app, _ := newSessionApp(t)
+	app.filter = FilterAll
+	app.MoveSelection(2)
+	selected := app.SelectedArticle()
+	if err := app.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown error: %v", err)
+	}
+	if err := app.store.db.Ping(); err == nil {
+		t.Fatalf("expected store closed")
+	}
+
+	reopened, err := NewApp(app.config)
+	if err != nil {
+		t.Fatalf("NewApp error: %v", err)
+	}
+	defer reopened.store.Close()
+	reopened.restoreSession()
+	if reopened.filter != FilterAll {
+		t.Fatalf("expected filter restored, got %q", reopened.filter)
+	}
+	if article := reopened.SelectedArticle(); article == nil || article.ID != selected.ID {
+		t.Fatalf("expected selection restored to %d, got %+v", selected.ID, article)
+	}
+}
+
+func TestRestoreSessionIgnoresUnknownValues(t *testing.T) {
+	app, _ := newSessionApp(t)
+	if err := app.store.SaveSession(Session{ArticleID: 999, Filter: "bogus", SortMode: "bogus"}); err != nil {
+		t.Fatalf("SaveSession error: %v", err)
+	}
+	app.restoreSession()
+	if app.filter != FilterUnread || app.sortMode != SortNewest || app.selectedIndex != 0 {
+		t.Fatalf("unexpected restored state %q %q %d", app.filter, app.sortMode, app.selectedIndex)
+	}
+}
+
+func TestShutdownTimesOut(t *testing.T) {
+	app, _ := newSessionApp(t)
+	tx, err := app.store.db.Begin()
+	if err != nil {
+		t.Fatalf("Begin error: %v", err)
+	}
+	app.store.db.SetMaxOpenConns(1)
+	err = app.Shutdown(50 * time.Millisecond)
+	if err == nil || err.Error() != "shutdown timed out" {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	_ = tx.Rollback()
+}
+
+func TestTUIQuitWaitsForPendingSummaries(t *testing.T) {
+	app, articles := newSessionApp(t)
+	model := newTUIModel(app)
+	model.app.summaryPending[articles[0].ID] = true
+	model.app.summaryPending[articles[1].ID] = true
+	model.summaryQueue = []Article{articles[2]}
+	model.batchActive = true
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	model = updated.(tuiModel)
+	if !model.shuttingDown || cmd == nil || model.batchActive || len(model.summaryQueue) != 0 {
+		t.Fatalf("expected shutdown to wait for summaries")
+	}
+	if model.ctx.Err() != nil {
+		t.Fatalf("pending summaries should not be cancelled")
+	}
+
+	updated, cmd = model.Update(summaryResultMsg{articleID: articles[0].ID, summaryText: "done", model: "m"})
+	model = updated.(tuiModel)
+	if cmd != nil {
+		t.Fatalf("expected to keep waiting, status %q", model.app.status)
+	}
+	if _, ok := app.store.FindSummary(articles[0].ID); !ok {
+		t.Fatalf("expected summary flushed to store")
+	}
+
+	updated, cmd = model.Update(summaryResultMsg{articleID: articles[1].ID, err: errors.New("boom")})
+	model = updated.(tuiModel)
+	if cmd == nil {
+		t.Fatalf("expected quit after last summary")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatalf("expected quit msg")
+	}
+	if model.ctx.Err() == nil {
+		t.Fatalf("expected context cancelled on quit")
+	}
+}
+
+func TestTUIShutdownSignalAndTimeout(t *testing.T) {
+	app, articles := newSessionApp(t)
+	model := newTUIModel(app)
+	model.app.summaryPending[articles[0].ID] = true
+
+	updated, _ := model.Update(shutdownMsg{})
+	model = updated.(tuiModel)
+	if !model.shuttingDown {
+		t.Fatalf("expected SIGTERM to start shutdown")
+	}
+	updated, cmd := model.Update(shutdownTimeoutMsg{})
+	model = updated.(tuiModel)
+	if _, ok := cmd().(tea.QuitMsg); !ok || model.ctx.Err() == nil {
+		t.Fatalf("expected timeout to cancel and quit")
+	}
+
+	model = newTUIModel(app)
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	updated, cmd = updated.(tuiModel).Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatalf("expected second quit to exit immediately")
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	smtpTLSStartTLS     = "starttls"
+	smtpTLSImplicit     = "tls"
+	smtpTLSNone         = "none"
+	defaultSMTPPort     = 587
+	smtpTimeout         = 30 * time.Second
+	smtpImplicitTLSPort = 465
+)
+
+var smtpDial = func(addr string, implicitTLS bool, config *tls.Config) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	if implicitTLS {
+		return tls.DialWithDialer(dialer, "tcp", addr, config)
+	}
+	return dialer.Dial("tcp", addr)
+}
+
+func parseSMTPTLS(value string) (string, error) {
+	mode := strings.ToLower(trimQuotes(value))
+	switch mode {
+	case "", smtpTLSStartTLS, smtpTLSImplicit, smtpTLSNone:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid smtp.tls: %q (expected starttls, tls, or none)", value)
+}
+
+func smtpPort(cfg Config) int {
+	if cfg.SMTPPort > 0 {
+		return cfg.SMTPPort
+	}
+	if cfg.SMTPTLS == smtpTLSImplicit {
+		return smtpImplicitTLSPort
+	}
+	return defaultSMTPPort
+}
+
+func buildSMTPMessage(from string, message emailMessage, now time.Time) []byte {
+	var body strings.Builder
+	writer := quotedprintable.NewWriter(&body)
+	_, _ = writer.Write([]byte(strings.ReplaceAll(message.Body, "\n", "\r\n")))
+	_ = writer.Close()
+	headers := []string{
+		"From: " + from,
+		"To: " + message.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + now.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+	}
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body.String())
+}
+
+func sendSMTP(cfg Config, message emailMessage, now time.Time) error {
+	if strings.TrimSpace(message.To) == "" {
+		return errors.New("smtp: email_to is not set")
+	}
+	recipients, err := mail.ParseAddressList(message.To)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid email_to: %w", err)
+	}
+	from := firstNonEmpty(cfg.SMTPFrom, cfg.SMTPUsername)
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid from address %q", from)
+	}
+	host := cfg.SMTPHost
+	tlsConfig := &tls.Config{ServerName: host}
+	conn, err := smtpDial(net.JoinHostPort(host, strconv.Itoa(smtpPort(cfg))), cfg.SMTPTLS == smtpTLSImplicit, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(smtpTimeout))
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer client.Close()
+	if cfg.SMTPTLS == smtpTLSStartTLS || cfg.SMTPTLS == "" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp: server does not support STARTTLS (set smtp.tls = \"none\" to send unencrypted)")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+	}
+	if cfg.SMTPUsername != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, host)); err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+	}
+	if err := client.Mail(sender.Address); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient.Address); err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+	}
+	data, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if _, err := data.Write(buildSMTPMessage(sender.String(), message, now)); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if err := data.Close(); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type fakeSMTPSession struct {
+	commands []string
+	data     string
+}
+
+func stubSMTPServer(t *testing.T, extensions []string) (*fakeSMTPSession, *string) {
+	t.Helper()
+	session := &fakeSMTPSession{}
+	dialed := ""
+	original := smtpDial
+	smtpDial = func(addr string, implicitTLS bool, config *tls.Config) (net.Conn, error) {
+		dialed = addr
+		client, server := net.Pipe()
+		go serveFakeSMTP(server, extensions, session)
+		return client, nil
+	}
+	t.Cleanup(func() { smtpDial = original })
+	return session, &dialed
+}
+
+func serveFakeSMTP(conn net.Conn, extensions []string, session *fakeSMTPSession) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	write := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	write("220 fake ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		session.commands = append(session.commands, line)
+		switch verb := strings.ToUpper(strings.Fields(line + " x")[0]); verb {
+		case "EHLO":
+			for _, extension := range extensions {
+				write("250-" + extension)
+			}
+			write("250 fake")
+		case "AUTH":
+			write("235 ok")
+		case "DATA":
+			write("354 go ahead")
+			var body strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil || dataLine == ".\r\n" {
+					break
+				}
+				body.WriteString(dataLine)
+			}
+			session.data = body.String()
+			write("250 queued")
+		case "QUIT":
+			write("221 bye")
+			return
+		default:
+			write("250 ok")
+		}
+	}
+}
+
+func TestSendSMTP(t *testing.T) {
+	session, dialed := stubSMTPServer(t, []string{"AUTH PLAIN"})
+	cfg := Config{SMTPHost: "localhost", SMTPTLS: smtpTLSNone, SMTPUsername: "me@example.com", SMTPPassword: "hunter2"}
+	message := emailMessage{To: "Reader <reader@example.com>, other@example.com", Subject: "Grüße", Body: "line one\nline two"}
+	if err := sendSMTP(cfg, message, time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("sendSMTP error: %v", err)
+	}
+	if *dialed != "localhost:587" {
+		t.Fatalf("unexpected address %q", *dialed)
+	}
+	commands := strings.Join(session.commands, "\n")
+	auth := base64.StdEncoding.EncodeToString([]byte("\x00me@example.com\x00hunter2"))
+	for _, want := range []string{"AUTH PLAIN " + auth, "MAIL FROM:<me@example.com>", "RCPT TO:<reader@example.com>", "RCPT TO:<other@example.com>"} {
+		if !strings.Contains(commands, want) {
+			t.Fatalf("missing %q in %s", want, commands)
+		}
+	}
+	for _, want := range []string{"From: <me@example.com>", "To: Reader <reader@example.com>, other@example.com", "Subject: =?utf-8?q?Gr=C3=BC=C3=9Fe?=", "Date: Thu, 15 Oct 2026 08:00:00 +0000", "line one\r\nline two"} {
+		if !strings.Contains(session.data, want) {
+			t.Fatalf("missing %q in %q", want, session.data)
+		}
+	}
+}
+
+func TestSendSMTPRequiresStartTLSAndAddresses(t *testing.T) {
+	stubSMTPServer(t, nil)
+	cfg := Config{SMTPHost: "mail.example.com", SMTPFrom: "me@example.com"}
+	if err := sendSMTP(cfg, emailMessage{To: "you@example.com"}, time.Now()); err == nil || !strings.Contains(err.Error(), "does not support STARTTLS") {
+		t.Fatalf("expected STARTTLS error, got %v", err)
+	}
+	if err := sendSMTP(cfg, emailMessage{}, time.Now()); err == nil || !strings.Contains(err.Error(), "email_to is not set") {
+		t.Fatalf("expected missing recipient error, got %v", err)
+	}
+	if err := sendSMTP(Config{SMTPHost: "mail.example.com"}, emailMessage{To: "you@example.com"}, time.Now()); err == nil || !strings.Contains(err.Error(), "invalid from address") {
+		t.Fatalf("expected missing sender error, got %v", err)
+	}
+	if port := smtpPort(Config{SMTPTLS: smtpTLSImplicit}); port != 465 {
+		t.Fatalf("expected implicit TLS port, got %d", port)
+	}
+}
+
+func TestConfigSMTPSection(t *testing.T) {
+	cfg := DefaultConfig()
+	input := "[smtp]\nhost = \"smtp.example.com\"\nport = 2525\nusername = \"me\"\nfrom = \"Greeder <me@example.com>\"\ntls = \"TLS\""
+	if err := parseConfig(input, &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.SMTPHost != "smtp.example.com" || cfg.SMTPPort != 2525 || cfg.SMTPUsername != "me" || cfg.SMTPTLS != smtpTLSImplicit {
+		t.Fatalf("unexpected smtp config %+v", cfg)
+	}
+	if rendered := renderConfig(cfg); !strings.Contains(rendered, "[smtp]\nhost = \"smtp.example.com\"\nusername = \"me\"\nfrom = \"Greeder <me@example.com>\"\ntls = \"tls\"\nport = 2525") {
+		t.Fatalf("unexpected rendered config %s", rendered)
+	}
+	for _, input := range []string{"[smtp]\nport = 70000", "[smtp]\ntls = \"ssl3\""} {
+		if err := parseConfig(input, &cfg); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+	if err := applyEnvOverrides(&cfg, []string{"GREEDER_SMTP_PASSWORD=secret"}); err != nil || cfg.SMTPPassword != "secret" {
+		t.Fatalf("unexpected env override %q %v", cfg.SMTPPassword, err)
+	}
+}
+
+func TestTUIEmailOverSMTP(t *testing.T) {
+	session, _ := stubSMTPServer(t, []string{"AUTH PLAIN"})
+	app := newTUIApp(t)
+	app.filter = FilterAll
+	feed, _ := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1", ContentText: "body"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.loadArticles()
+	app.config.SMTPHost = "localhost"
+	app.config.SMTPTLS = smtpTLSNone
+	app.config.SMTPFrom = "me@example.com"
+	app.config.EmailTo = "you@example.com"
+	app.emailSender = func(string) error {
+		t.Fatalf("expected smtp instead of mailto")
+		return nil
+	}
+	model := newTUIModel(app)
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	model = updated.(tuiModel)
+	if cmd == nil || app.status != "Sending email to you@example.com..." {
+		t.Fatalf("expected async send, got %q", app.status)
+	}
+	updated, _ = model.Update(cmd())
+	if app.status != "Email sent to you@example.com" || !strings.Contains(session.data, "Subject: Post") {
+		t.Fatalf("unexpected result %q %q", app.status, session.data)
+	}
+	updated.(tuiModel).Update(emailResultMsg{to: "you@example.com", err: net.ErrClosed})
+	if !strings.HasPrefix(app.status, "Email failed:") {
+		t.Fatalf("expected failure status, got %q", app.status)
+	}
+}
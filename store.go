@@ -8,14 +8,19 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite"
 )
 
 type Store struct {
-	path string
-	db   *sql.DB
+	path            string
+	db              *sql.DB
+	clock           Clock
+	stmtMu          sync.Mutex
+	stmts           map[string]*sql.Stmt
+	dedupSimilarity float64
 }
 
 var (
@@ -43,7 +48,7 @@ func NewStore(path string) (*Store, error) {
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return nil, err
 	}
-	db, err := openSQLite("sqlite", path)
+	db, err := openSQLite("sqlite", sqliteDSN(path))
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +56,12 @@ func NewStore(path string) (*Store, error) {
 		_ = db.Close()
 		return nil, err
 	}
-	return &Store{path: path, db: db}, nil
+	store := &Store{path: path, db: db, clock: systemClock{}}
+	if err := store.prepareStatements(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
 }
 
 func initSchema(db *sql.DB) error {
@@ -126,17 +136,115 @@ func initSchema(db *sql.DB) error {
 			FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE,
 			FOREIGN KEY(feed_id) REFERENCES feeds(id) ON DELETE CASCADE
 		);`,
+		`CREATE TABLE IF NOT EXISTS article_embeddings (
+			article_id INTEGER PRIMARY KEY,
+			model TEXT,
+			vector BLOB,
+			FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+		);`,
+		`CREATE TABLE IF NOT EXISTS summary_versions (
+			id INTEGER PRIMARY KEY,
+			article_id INTEGER,
+			content TEXT,
+			model TEXT,
+			generated_at INTEGER,
+			prompt_tokens INTEGER,
+			completion_tokens INTEGER,
+			content_hash TEXT,
+			FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+		);`,
+		`CREATE TABLE IF NOT EXISTS sync_items (
+			article_id INTEGER PRIMARY KEY,
+			backend TEXT,
+			remote_id TEXT,
+			is_read INTEGER,
+			is_starred INTEGER,
+			FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+		);`,
+		`CREATE TABLE IF NOT EXISTS wayback_snapshots (
+			article_id INTEGER PRIMARY KEY,
+			snapshot_url TEXT,
+			archived_at INTEGER,
+			FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+		);`,
+		`CREATE TABLE IF NOT EXISTS article_tags (
+			article_id INTEGER,
+			tag TEXT,
+			source TEXT,
+			UNIQUE(article_id, tag),
+			FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+		);`,
+		`CREATE TABLE IF NOT EXISTS folders (
+			id INTEGER PRIMARY KEY,
+			name TEXT UNIQUE
+		);`,
+		`CREATE TABLE IF NOT EXISTS feed_folders (
+			feed_id INTEGER PRIMARY KEY,
+			folder_id INTEGER,
+			FOREIGN KEY(feed_id) REFERENCES feeds(id) ON DELETE CASCADE,
+			FOREIGN KEY(folder_id) REFERENCES folders(id) ON DELETE CASCADE
+		);`,
+		`CREATE TABLE IF NOT EXISTS session (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS digests (
+			id INTEGER PRIMARY KEY,
+			content TEXT,
+			model TEXT,
+			generated_at INTEGER,
+			articles TEXT
+		);`,
+		`CREATE TABLE IF NOT EXISTS usage_log (
+			id INTEGER PRIMARY KEY,
+			recorded_at INTEGER,
+			provider TEXT,
+			model TEXT,
+			prompt_tokens INTEGER,
+			completion_tokens INTEGER
+		);`,
+		`CREATE INDEX IF NOT EXISTS usage_log_recorded_at ON usage_log(recorded_at);`,
+	}
+	var hasUsageLog int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'usage_log'`).Scan(&hasUsageLog); err != nil {
+		return err
 	}
 	for _, stmt := range stmts {
 		if _, err := db.Exec(stmt); err != nil {
 			return err
 		}
 	}
-	if err := ensureColumnFn(db, "articles", "base_url", "TEXT"); err != nil {
-		return err
+	columns := []struct {
+		table      string
+		column     string
+		columnType string
+	}{
+		{"articles", "base_url", "TEXT"},
+		{"deleted", "base_url", "TEXT"},
+		{"summaries", "prompt_tokens", "INTEGER"},
+		{"summaries", "completion_tokens", "INTEGER"},
+		{"summaries", "content_hash", "TEXT"},
+		{"articles", "score", "INTEGER"},
+		{"feeds", "source", "TEXT"},
+		{"feeds", "etag", "TEXT"},
+		{"feeds", "last_modified", "TEXT"},
+		{"feeds", "last_error", "TEXT"},
+		{"feeds", "fail_count", "INTEGER"},
+		{"feeds", "last_success", "INTEGER"},
+		{"feeds", "last_failure", "INTEGER"},
+		{"saved", "provider", "TEXT"},
+		{"articles", "fingerprint", "INTEGER"},
+		{"articles", "queue_position", "INTEGER"},
+	}
+	for _, col := range columns {
+		if err := ensureColumnFn(db, col.table, col.column, col.columnType); err != nil {
+			return err
+		}
 	}
-	if err := ensureColumnFn(db, "deleted", "base_url", "TEXT"); err != nil {
-		return err
+	if hasUsageLog == 0 {
+		if _, err := db.Exec(`INSERT INTO usage_log (recorded_at, provider, model, prompt_tokens, completion_tokens) SELECT generated_at, '', model, COALESCE(prompt_tokens, 0), COALESCE(completion_tokens, 0) FROM summaries WHERE COALESCE(prompt_tokens, 0) + COALESCE(completion_tokens, 0) > 0`); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -171,7 +279,8 @@ func (s *Store) Save() error {
 }
 
 func (s *Store) Feeds() []Feed {
-	rows, err := s.db.Query(`SELECT id, title, url, site_url, description, last_fetched, created_at, updated_at FROM feeds ORDER BY id`)
+	rows, err := s.db.Query(`SELECT f.id, f.title, f.url, f.site_url, f.description, f.last_fetched, f.created_at, f.updated_at, COALESCE(f.source, ''), COALESCE(f.etag, ''), COALESCE(f.last_modified, ''), COALESCE(f.last_error, ''), COALESCE(f.fail_count, 0), f.last_success, f.last_failure, COALESCE(d.name, '')
+		FROM feeds f LEFT JOIN feed_folders ff ON ff.feed_id = f.id LEFT JOIN folders d ON d.id = ff.folder_id ORDER BY f.id`)
 	if err != nil {
 		return nil
 	}
@@ -180,10 +289,12 @@ func (s *Store) Feeds() []Feed {
 	feeds := []Feed{}
 	for rows.Next() {
 		var feed Feed
-		var lastFetched, createdAt, updatedAt sql.NullInt64
-		if err := rows.Scan(&feed.ID, &feed.Title, &feed.URL, &feed.SiteURL, &feed.Description, &lastFetched, &createdAt, &updatedAt); err != nil {
+		var lastFetched, createdAt, updatedAt, lastSuccess, lastFailure sql.NullInt64
+		if err := rows.Scan(&feed.ID, &feed.Title, &feed.URL, &feed.SiteURL, &feed.Description, &lastFetched, &createdAt, &updatedAt, &feed.Source, &feed.ETag, &feed.LastModified, &feed.LastError, &feed.FailCount, &lastSuccess, &lastFailure, &feed.Folder); err != nil {
 			return feeds
 		}
+		feed.LastSuccess = timeFromUnix(lastSuccess)
+		feed.LastFailure = timeFromUnix(lastFailure)
 		feed.LastFetched = timeFromUnix(lastFetched)
 		feed.CreatedAt = timeFromUnix(createdAt)
 		feed.UpdatedAt = timeFromUnix(updatedAt)
@@ -193,7 +304,7 @@ func (s *Store) Feeds() []Feed {
 }
 
 func (s *Store) Articles() []Article {
-	rows, err := s.db.Query(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title FROM articles ORDER BY id`)
+	rows, err := s.db.Query(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title, COALESCE(score, 0) FROM articles ORDER BY id`)
 	if err != nil {
 		return nil
 	}
@@ -211,7 +322,7 @@ func (s *Store) Articles() []Article {
 }
 
 func (s *Store) Summaries() []Summary {
-	rows, err := s.db.Query(`SELECT id, article_id, content, model, generated_at FROM summaries ORDER BY id`)
+	rows, err := s.db.Query(`SELECT id, article_id, content, model, generated_at, COALESCE(prompt_tokens, 0), COALESCE(completion_tokens, 0), COALESCE(content_hash, '') FROM summaries ORDER BY id`)
 	if err != nil {
 		return nil
 	}
@@ -219,19 +330,17 @@ func (s *Store) Summaries() []Summary {
 
 	items := []Summary{}
 	for rows.Next() {
-		var summary Summary
-		var generatedAt sql.NullInt64
-		if err := rows.Scan(&summary.ID, &summary.ArticleID, &summary.Content, &summary.Model, &generatedAt); err != nil {
+		summary, err := scanSummary(rows)
+		if err != nil {
 			return items
 		}
-		summary.GeneratedAt = timeFromUnix(generatedAt)
 		items = append(items, summary)
 	}
 	return items
 }
 
 func (s *Store) Saved() []Saved {
-	rows, err := s.db.Query(`SELECT article_id, raindrop_id, tags, saved_at FROM saved ORDER BY article_id`)
+	rows, err := s.db.Query(`SELECT article_id, COALESCE(provider, ''), raindrop_id, tags, saved_at FROM saved ORDER BY article_id`)
 	if err != nil {
 		return nil
 	}
@@ -242,7 +351,7 @@ func (s *Store) Saved() []Saved {
 		var saved Saved
 		var tagsRaw string
 		var savedAt sql.NullInt64
-		if err := rows.Scan(&saved.ArticleID, &saved.RaindropID, &tagsRaw, &savedAt); err != nil {
+		if err := rows.Scan(&saved.ArticleID, &saved.Provider, &saved.RaindropID, &tagsRaw, &savedAt); err != nil {
 			return items
 		}
 		if tagsRaw != "" {
@@ -254,8 +363,17 @@ func (s *Store) Saved() []Saved {
 	return items
 }
 
+func (s *Store) SetSavedTags(articleID int, tags []string) error {
+	blob, err := tagsMarshal(tags)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE saved SET tags = ? WHERE article_id = ?`, string(blob), articleID)
+	return err
+}
+
 func (s *Store) Deleted() []Deleted {
-	rows, err := s.db.Query(`SELECT feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title, deleted_at FROM deleted ORDER BY id`)
+	rows, err := s.db.Query(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title, deleted_at FROM deleted ORDER BY id`)
 	if err != nil {
 		return nil
 	}
@@ -267,7 +385,7 @@ func (s *Store) Deleted() []Deleted {
 		var publishedAt, fetchedAt, deletedAt sql.NullInt64
 		var isRead, isStarred int
 		article := Article{}
-		if err := rows.Scan(&deleted.FeedID, &deleted.GUID, &article.Title, &article.URL, &article.BaseURL, &article.Author, &article.Content, &article.ContentText, &publishedAt, &fetchedAt, &isRead, &isStarred, &article.FeedTitle, &deletedAt); err != nil {
+		if err := rows.Scan(&deleted.ID, &deleted.FeedID, &deleted.GUID, &article.Title, &article.URL, &article.BaseURL, &article.Author, &article.Content, &article.ContentText, &publishedAt, &fetchedAt, &isRead, &isStarred, &article.FeedTitle, &deletedAt); err != nil {
 			return items
 		}
 		article.FeedID = deleted.FeedID
@@ -291,7 +409,7 @@ func (s *Store) InsertFeed(feed Feed) (Feed, error) {
 		return Feed{}, err
 	}
 
-	now := time.Now().UTC()
+	now := s.now().UTC()
 	if feed.CreatedAt.IsZero() {
 		feed.CreatedAt = now
 	}
@@ -299,8 +417,8 @@ func (s *Store) InsertFeed(feed Feed) (Feed, error) {
 		feed.UpdatedAt = feed.CreatedAt
 	}
 
-	result, err := s.db.Exec(`INSERT INTO feeds (title, url, site_url, description, last_fetched, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		feed.Title, feed.URL, feed.SiteURL, feed.Description, timeToUnix(feed.LastFetched), timeToUnix(feed.CreatedAt), timeToUnix(feed.UpdatedAt))
+	result, err := s.db.Exec(`INSERT INTO feeds (title, url, site_url, description, last_fetched, created_at, updated_at, source) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		feed.Title, feed.URL, feed.SiteURL, feed.Description, timeToUnix(feed.LastFetched), timeToUnix(feed.CreatedAt), timeToUnix(feed.UpdatedAt), feed.Source)
 	if err != nil {
 		return Feed{}, err
 	}
@@ -309,13 +427,34 @@ func (s *Store) InsertFeed(feed Feed) (Feed, error) {
 		return Feed{}, err
 	}
 	feed.ID = int(id)
+	if feed.Folder != "" {
+		if err := s.SetFeedFolder(feed.ID, feed.Folder); err != nil {
+			return Feed{}, err
+		}
+	}
 	return feed, nil
 }
 
+func (s *Store) SetFeedSource(feedID int, source string) error {
+	_, err := s.db.Exec(`UPDATE feeds SET source = ? WHERE id = ?`, source, feedID)
+	return err
+}
+
+func (s *Store) SetFeedValidators(id int, etag string, lastModified string) error {
+	_, err := s.db.Exec(`UPDATE feeds SET etag = ?, last_modified = ? WHERE id = ?`, etag, lastModified, id)
+	return err
+}
+
+func (s *Store) SetFeedError(id int, message string) error {
+	_, err := s.db.Exec(`UPDATE feeds SET last_error = ? WHERE id = ?`, message, id)
+	return err
+}
+
 func (s *Store) UpdateFeed(feed Feed) error {
-	feed.UpdatedAt = time.Now().UTC()
-	result, err := s.db.Exec(`UPDATE feeds SET title = ?, url = ?, site_url = ?, description = ?, last_fetched = ?, created_at = ?, updated_at = ? WHERE id = ?`,
-		feed.Title, feed.URL, feed.SiteURL, feed.Description, timeToUnix(feed.LastFetched), timeToUnix(feed.CreatedAt), timeToUnix(feed.UpdatedAt), feed.ID)
+	feed.UpdatedAt = s.now().UTC()
+	result, err := s.db.Exec(`UPDATE feeds SET title = ?, url = ?, site_url = ?, description = ?, last_fetched = ?, created_at = ?, updated_at = ?,
+		etag = CASE WHEN url = ? THEN etag END, last_modified = CASE WHEN url = ? THEN last_modified END WHERE id = ?`,
+		feed.Title, feed.URL, feed.SiteURL, feed.Description, timeToUnix(feed.LastFetched), timeToUnix(feed.CreatedAt), timeToUnix(feed.UpdatedAt), feed.URL, feed.URL, feed.ID)
 	if err != nil {
 		return err
 	}
@@ -329,6 +468,29 @@ func (s *Store) UpdateFeed(feed Feed) error {
 	return nil
 }
 
+func (s *Store) RenameFeed(id int, title string) error {
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec(`UPDATE feeds SET title = ?, updated_at = ? WHERE id = ?`, title, timeToUnix(s.now().UTC()), id)
+	if err != nil {
+		return err
+	}
+	rows, err := rowsAffected(result)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("feed not found")
+	}
+	if _, err := tx.Exec(`UPDATE articles SET feed_title = ? WHERE feed_id = ?`, title, id); err != nil {
+		return err
+	}
+	return commitTx(tx)
+}
+
 func (s *Store) DeleteFeed(id int) error {
 	tx, err := beginTx(s.db)
 	if err != nil {
@@ -342,6 +504,12 @@ func (s *Store) DeleteFeed(id int) error {
 	if _, err := tx.Exec(`DELETE FROM articles WHERE feed_id = ?`, id); err != nil {
 		return err
 	}
+	if _, err := tx.Exec(`DELETE FROM feed_folders WHERE feed_id = ?`, id); err != nil {
+		return err
+	}
+	if err := pruneFolders(tx); err != nil {
+		return err
+	}
 	return commitTx(tx)
 }
 
@@ -353,17 +521,19 @@ func (s *Store) InsertArticles(feed Feed, incoming []Article) ([]Article, error)
 	defer tx.Rollback()
 
 	seen := map[string]bool{}
-	rows, err := tx.Query(`SELECT guid FROM articles WHERE feed_id = ?`, feed.ID)
+	existing := map[string]Article{}
+	rows, err := tx.Query(`SELECT id, guid, title, content, content_text FROM articles WHERE feed_id = ?`, feed.ID)
 	if err != nil {
 		return nil, err
 	}
 	for rows.Next() {
-		var guid string
-		if err := rows.Scan(&guid); err != nil {
+		var article Article
+		if err := rows.Scan(&article.ID, &article.GUID, &article.Title, &article.Content, &article.ContentText); err != nil {
 			rows.Close()
 			return nil, err
 		}
-		seen[guid] = true
+		seen[article.GUID] = true
+		existing[article.GUID] = article
 	}
 	rows.Close()
 	rows, err = tx.Query(`SELECT guid FROM deleted WHERE feed_id = ?`, feed.ID)
@@ -379,6 +549,14 @@ func (s *Store) InsertArticles(feed Feed, incoming []Article) ([]Article, error)
 		seen[guid] = true
 	}
 	rows.Close()
+	refresh, err := s.txStmt(tx, refreshArticleSQL)
+	if err != nil {
+		return nil, err
+	}
+	insert, err := s.txStmt(tx, insertArticleSQL)
+	if err != nil {
+		return nil, err
+	}
 
 	added := []Article{}
 	for _, article := range incoming {
@@ -390,13 +568,20 @@ func (s *Store) InsertArticles(feed Feed, incoming []Article) ([]Article, error)
 			article.BaseURL = article.URL
 		}
 		if seen[article.GUID] {
+			current, ok := existing[article.GUID]
+			if ok && firstNonEmpty(article.ContentText, article.Content) != "" && (current.Title != article.Title || current.Content != article.Content || current.ContentText != article.ContentText) {
+				if _, err := refresh.Exec(article.Title, article.Content, article.ContentText, current.ID); err != nil {
+					return nil, err
+				}
+				delete(existing, article.GUID)
+			}
 			continue
 		}
 		seen[article.GUID] = true
 		article.FeedID = feed.ID
 		article.FeedTitle = feed.Title
 		if article.FetchedAt.IsZero() {
-			article.FetchedAt = time.Now().UTC()
+			article.FetchedAt = s.now().UTC()
 		}
 		existingID, err := findArticleIDByBaseURLFn(tx, article.BaseURL)
 		if err != nil {
@@ -408,8 +593,7 @@ func (s *Store) InsertArticles(feed Feed, incoming []Article) ([]Article, error)
 			}
 			continue
 		}
-		result, err := tx.Exec(`INSERT INTO articles (feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			article.FeedID, article.GUID, article.Title, article.URL, article.BaseURL, article.Author, article.Content, article.ContentText, timeToUnix(article.PublishedAt), timeToUnix(article.FetchedAt), boolToInt(article.IsRead), boolToInt(article.IsStarred), article.FeedTitle)
+		result, err := insert.Exec(article.FeedID, article.GUID, article.Title, article.URL, article.BaseURL, article.Author, article.Content, article.ContentText, timeToUnix(article.PublishedAt), timeToUnix(article.FetchedAt), boolToInt(article.IsRead), boolToInt(article.IsStarred), article.FeedTitle)
 		if err != nil {
 			return nil, err
 		}
@@ -424,8 +608,8 @@ func (s *Store) InsertArticles(feed Feed, incoming []Article) ([]Article, error)
 		added = append(added, article)
 	}
 
-	feed.LastFetched = time.Now().UTC()
-	feed.UpdatedAt = time.Now().UTC()
+	feed.LastFetched = s.now().UTC()
+	feed.UpdatedAt = s.now().UTC()
 	if _, err := tx.Exec(`UPDATE feeds SET last_fetched = ?, updated_at = ? WHERE id = ?`, timeToUnix(feed.LastFetched), timeToUnix(feed.UpdatedAt), feed.ID); err != nil {
 		return nil, err
 	}
@@ -469,40 +653,54 @@ func ensureArticleSource(tx *sql.Tx, articleID int, feedID int, publishedAt time
 }
 
 func (s *Store) FindSummary(articleID int) (Summary, bool) {
-	var summary Summary
-	var generatedAt sql.NullInt64
-	if err := s.db.QueryRow(`SELECT id, article_id, content, model, generated_at FROM summaries WHERE article_id = ?`, articleID).Scan(&summary.ID, &summary.ArticleID, &summary.Content, &summary.Model, &generatedAt); err != nil {
+	find, err := s.stmt(findSummarySQL)
+	if err != nil {
+		return Summary{}, false
+	}
+	summary, err := scanSummary(find.QueryRow(articleID))
+	if err != nil {
 		return Summary{}, false
 	}
-	summary.GeneratedAt = timeFromUnix(generatedAt)
 	return summary, true
 }
 
 func (s *Store) UpsertSummary(summary Summary) (Summary, error) {
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return Summary{}, err
+	}
+	defer tx.Rollback()
+
 	var existingID int
-	if err := s.db.QueryRow(`SELECT id FROM summaries WHERE article_id = ?`, summary.ArticleID).Scan(&existingID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+	if err := tx.QueryRow(`SELECT id FROM summaries WHERE article_id = ?`, summary.ArticleID).Scan(&existingID); err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return Summary{}, err
 	}
 	if summary.GeneratedAt.IsZero() {
-		summary.GeneratedAt = time.Now().UTC()
+		summary.GeneratedAt = s.now().UTC()
 	}
 	if existingID != 0 {
+		if err := archiveSummary(tx, summary.ArticleID); err != nil {
+			return Summary{}, err
+		}
 		summary.ID = existingID
-		_, err := s.db.Exec(`UPDATE summaries SET content = ?, model = ?, generated_at = ? WHERE article_id = ?`, summary.Content, summary.Model, timeToUnix(summary.GeneratedAt), summary.ArticleID)
+		_, err := tx.Exec(`UPDATE summaries SET content = ?, model = ?, generated_at = ?, prompt_tokens = ?, completion_tokens = ?, content_hash = ? WHERE article_id = ?`, summary.Content, summary.Model, timeToUnix(summary.GeneratedAt), summary.PromptTokens, summary.CompletionTokens, summary.ContentHash, summary.ArticleID)
 		if err != nil {
 			return Summary{}, err
 		}
-		return summary, nil
-	}
-	result, err := s.db.Exec(`INSERT INTO summaries (article_id, content, model, generated_at) VALUES (?, ?, ?, ?)`, summary.ArticleID, summary.Content, summary.Model, timeToUnix(summary.GeneratedAt))
-	if err != nil {
-		return Summary{}, err
+	} else {
+		result, err := tx.Exec(`INSERT INTO summaries (article_id, content, model, generated_at, prompt_tokens, completion_tokens, content_hash) VALUES (?, ?, ?, ?, ?, ?, ?)`, summary.ArticleID, summary.Content, summary.Model, timeToUnix(summary.GeneratedAt), summary.PromptTokens, summary.CompletionTokens, summary.ContentHash)
+		if err != nil {
+			return Summary{}, err
+		}
+		id, err := lastInsertID(result)
+		if err != nil {
+			return Summary{}, err
+		}
+		summary.ID = int(id)
 	}
-	id, err := lastInsertID(result)
-	if err != nil {
+	if err := commitTx(tx); err != nil {
 		return Summary{}, err
 	}
-	summary.ID = int(id)
 	return summary, nil
 }
 
@@ -510,8 +708,11 @@ func (s *Store) UpdateArticle(article Article) error {
 	if article.BaseURL == "" {
 		article.BaseURL = baseURL(article.URL)
 	}
-	result, err := s.db.Exec(`UPDATE articles SET feed_id = ?, guid = ?, title = ?, url = ?, base_url = ?, author = ?, content = ?, content_text = ?, published_at = ?, fetched_at = ?, is_read = ?, is_starred = ?, feed_title = ? WHERE id = ?`,
-		article.FeedID, article.GUID, article.Title, article.URL, article.BaseURL, article.Author, article.Content, article.ContentText, timeToUnix(article.PublishedAt), timeToUnix(article.FetchedAt), boolToInt(article.IsRead), boolToInt(article.IsStarred), article.FeedTitle, article.ID)
+	update, err := s.stmt(updateArticleSQL)
+	if err != nil {
+		return err
+	}
+	result, err := update.Exec(article.FeedID, article.GUID, article.Title, article.URL, article.BaseURL, article.Author, article.Content, article.ContentText, timeToUnix(article.PublishedAt), timeToUnix(article.FetchedAt), boolToInt(article.IsRead), boolToInt(article.IsStarred), article.FeedTitle, article.ID)
 	if err != nil {
 		return err
 	}
@@ -525,38 +726,74 @@ func (s *Store) UpdateArticle(article Article) error {
 	return nil
 }
 
+func (s *Store) SetArticleScores(scores map[int]int) error {
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for id, score := range scores {
+		if _, err := tx.Exec(`UPDATE articles SET score = ? WHERE id = ?`, score, id); err != nil {
+			return err
+		}
+	}
+	return commitTx(tx)
+}
+
 func (s *Store) DeleteArticle(id int) (Article, error) {
-	row := s.db.QueryRow(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title FROM articles WHERE id = ?`, id)
+	article, _, err := s.TrashArticle(id)
+	return article, err
+}
+
+// TrashArticle deletes an article like DeleteArticle and also returns its row
+// in the trash, so the caller can restore exactly that entry later.
+func (s *Store) TrashArticle(id int) (Article, int, error) {
+	row := s.db.QueryRow(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title, COALESCE(score, 0) FROM articles WHERE id = ?`, id)
 	article, err := scanArticle(row)
 	if err != nil {
-		return Article{}, errors.New("article not found")
+		return Article{}, 0, errors.New("article not found")
 	}
 	tx, err := beginTx(s.db)
 	if err != nil {
-		return Article{}, err
+		return Article{}, 0, err
 	}
 	defer tx.Rollback()
 	if _, err := tx.Exec(`DELETE FROM articles WHERE id = ?`, id); err != nil {
-		return Article{}, err
+		return Article{}, 0, err
 	}
 	if _, err := tx.Exec(`DELETE FROM summaries WHERE article_id = ?`, id); err != nil {
-		return Article{}, err
+		return Article{}, 0, err
+	}
+	if _, err := tx.Exec(`DELETE FROM summary_versions WHERE article_id = ?`, id); err != nil {
+		return Article{}, 0, err
 	}
 	if _, err := tx.Exec(`DELETE FROM saved WHERE article_id = ?`, id); err != nil {
-		return Article{}, err
+		return Article{}, 0, err
 	}
-	if _, err := tx.Exec(`INSERT INTO deleted (feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		article.FeedID, article.GUID, article.Title, article.URL, article.BaseURL, article.Author, article.Content, article.ContentText, timeToUnix(article.PublishedAt), timeToUnix(article.FetchedAt), boolToInt(article.IsRead), 0, article.FeedTitle, timeToUnix(time.Now().UTC())); err != nil {
-		return Article{}, err
+	result, err := tx.Exec(`INSERT INTO deleted (feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		article.FeedID, article.GUID, article.Title, article.URL, article.BaseURL, article.Author, article.Content, article.ContentText, timeToUnix(article.PublishedAt), timeToUnix(article.FetchedAt), boolToInt(article.IsRead), 0, article.FeedTitle, timeToUnix(s.now().UTC()))
+	if err != nil {
+		return Article{}, 0, err
+	}
+	trashID, err := lastInsertID(result)
+	if err != nil {
+		return Article{}, 0, err
 	}
 	if err := commitTx(tx); err != nil {
-		return Article{}, err
+		return Article{}, 0, err
 	}
-	return article, nil
+	return article, int(trashID), nil
 }
 
 func (s *Store) UndeleteLast() (Article, error) {
-	row := s.db.QueryRow(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title FROM deleted ORDER BY id DESC LIMIT 1`)
+	return s.restoreDeletedRow(s.db.QueryRow(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title FROM deleted ORDER BY id DESC LIMIT 1`))
+}
+
+func (s *Store) RestoreDeleted(id int) (Article, error) {
+	return s.restoreDeletedRow(s.db.QueryRow(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title FROM deleted WHERE id = ?`, id))
+}
+
+func (s *Store) restoreDeletedRow(row *sql.Row) (Article, error) {
 	var deletedID int
 	article, err := scanDeleted(row, &deletedID)
 	if err != nil {
@@ -664,7 +901,7 @@ func (s *Store) UndeleteByPublishedDays(days int) (int, error) {
 }
 
 func (s *Store) DeleteOldArticles(days int) int {
-	cutoff := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	cutoff := s.now().Add(-time.Duration(days) * 24 * time.Hour)
 	var count int
 	if err := s.db.QueryRow(`SELECT COUNT(*) FROM articles WHERE fetched_at < ?`, timeToUnix(cutoff)).Scan(&count); err != nil {
 		return 0
@@ -678,19 +915,24 @@ func (s *Store) DeleteOldArticles(days int) int {
 
 func (s *Store) CleanupOrphanSummaries() {
 	_, _ = s.db.Exec(`DELETE FROM summaries WHERE article_id NOT IN (SELECT id FROM articles)`)
+	_, _ = s.db.Exec(`DELETE FROM summary_versions WHERE article_id NOT IN (SELECT id FROM articles)`)
 	_, _ = s.db.Exec(`DELETE FROM saved WHERE article_id NOT IN (SELECT id FROM articles)`)
+	_, _ = s.db.Exec(`DELETE FROM article_tags WHERE article_id NOT IN (SELECT id FROM articles)`)
+	_, _ = s.db.Exec(`DELETE FROM article_embeddings WHERE article_id NOT IN (SELECT id FROM articles)`)
+	_, _ = s.db.Exec(`DELETE FROM sync_items WHERE article_id NOT IN (SELECT id FROM articles)`)
+	_, _ = s.db.Exec(`DELETE FROM wayback_snapshots WHERE article_id NOT IN (SELECT id FROM articles)`)
 }
 
 func (s *Store) Compact(days int) int {
 	return s.DeleteOldArticles(days)
 }
 
-func (s *Store) SaveToRaindrop(articleID int, raindropID int, tags []string) error {
+func (s *Store) SaveBookmark(articleID int, provider string, remoteID int, tags []string) error {
 	blob, err := tagsMarshal(tags)
 	if err != nil {
 		return err
 	}
-	result, err := s.db.Exec(`UPDATE saved SET raindrop_id = ?, tags = ?, saved_at = ? WHERE article_id = ?`, raindropID, string(blob), timeToUnix(time.Now().UTC()), articleID)
+	result, err := s.db.Exec(`UPDATE saved SET provider = ?, raindrop_id = ?, tags = ?, saved_at = ? WHERE article_id = ?`, provider, remoteID, string(blob), timeToUnix(s.now().UTC()), articleID)
 	if err != nil {
 		return err
 	}
@@ -699,7 +941,7 @@ func (s *Store) SaveToRaindrop(articleID int, raindropID int, tags []string) err
 		return err
 	}
 	if rows == 0 {
-		_, err := s.db.Exec(`INSERT INTO saved (article_id, raindrop_id, tags, saved_at) VALUES (?, ?, ?, ?)`, articleID, raindropID, string(blob), timeToUnix(time.Now().UTC()))
+		_, err := s.db.Exec(`INSERT INTO saved (article_id, provider, raindrop_id, tags, saved_at) VALUES (?, ?, ?, ?, ?)`, articleID, provider, remoteID, string(blob), timeToUnix(s.now().UTC()))
 		if err != nil {
 			return err
 		}
@@ -707,6 +949,23 @@ func (s *Store) SaveToRaindrop(articleID int, raindropID int, tags []string) err
 	return nil
 }
 
+func (s *Store) UnreadCountByFeed() map[int]int {
+	counts := map[int]int{}
+	rows, err := s.db.Query(`SELECT feed_id, COUNT(*) FROM articles WHERE is_read = 0 GROUP BY feed_id`)
+	if err != nil {
+		return counts
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var feedID, count int
+		if err := rows.Scan(&feedID, &count); err != nil {
+			return counts
+		}
+		counts[feedID] = count
+	}
+	return counts
+}
+
 func (s *Store) SavedCount() int {
 	var count int
 	if err := s.db.QueryRow(`SELECT COUNT(*) FROM saved`).Scan(&count); err != nil {
@@ -715,49 +974,83 @@ func (s *Store) SavedCount() int {
 	return count
 }
 
-func (s *Store) ArticleSources(articleID int) []ArticleSource {
-	rows, err := s.db.Query(`SELECT COALESCE(feeds.title, ''), article_sources.published_at FROM article_sources LEFT JOIN feeds ON feeds.id = article_sources.feed_id WHERE article_sources.article_id = ? ORDER BY feeds.title`, articleID)
+func (s *Store) RecordUsage(record UsageRecord) error {
+	if record.RecordedAt.IsZero() {
+		record.RecordedAt = s.now()
+	}
+	_, err := s.db.Exec(`INSERT INTO usage_log (recorded_at, provider, model, prompt_tokens, completion_tokens) VALUES (?, ?, ?, ?, ?)`,
+		timeToUnix(record.RecordedAt), record.Provider, record.Model, record.PromptTokens, record.CompletionTokens)
+	return err
+}
+
+func (s *Store) SummaryUsageByWeek(since time.Time) []SummaryUsage {
+	rows, err := s.db.Query(`SELECT recorded_at, COALESCE(prompt_tokens, 0), COALESCE(completion_tokens, 0) FROM usage_log WHERE recorded_at >= ? ORDER BY recorded_at`, timeToUnix(since))
 	if err != nil {
 		return nil
 	}
 	defer rows.Close()
 
-	items := []ArticleSource{}
+	items := []SummaryUsage{}
 	for rows.Next() {
-		var source ArticleSource
-		var publishedAt sql.NullInt64
-		if err := rows.Scan(&source.FeedTitle, &publishedAt); err != nil {
+		var recordedAt sql.NullInt64
+		var promptTokens, completionTokens int
+		if err := rows.Scan(&recordedAt, &promptTokens, &completionTokens); err != nil {
 			return items
 		}
-		source.PublishedAt = timeFromUnix(publishedAt)
-		items = append(items, source)
+		week := weekStart(timeFromUnix(recordedAt))
+		if len(items) == 0 || !items[len(items)-1].WeekStart.Equal(week) {
+			items = append(items, SummaryUsage{WeekStart: week})
+		}
+		usage := &items[len(items)-1]
+		usage.Requests++
+		usage.PromptTokens += promptTokens
+		usage.CompletionTokens += completionTokens
 	}
 	return items
 }
 
-func (s *Store) SortedArticles() []Article {
-	rows, err := s.db.Query(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title FROM articles ORDER BY published_at DESC`)
+func weekStart(value time.Time) time.Time {
+	value = value.UTC()
+	offset := (int(value.Weekday()) + 6) % 7
+	day := time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -offset)
+}
+
+func (s *Store) ArticleSources(articleID int) []ArticleSource {
+	rows, err := s.db.Query(`SELECT COALESCE(feeds.title, ''), COALESCE(feeds.source, ''), article_sources.published_at FROM article_sources LEFT JOIN feeds ON feeds.id = article_sources.feed_id WHERE article_sources.article_id = ? ORDER BY feeds.title`, articleID)
 	if err != nil {
 		return nil
 	}
 	defer rows.Close()
 
-	articles := []Article{}
+	items := []ArticleSource{}
 	for rows.Next() {
-		article, err := scanArticle(rows)
-		if err != nil {
-			return articles
+		var source ArticleSource
+		var publishedAt sql.NullInt64
+		if err := rows.Scan(&source.FeedTitle, &source.Source, &publishedAt); err != nil {
+			return items
 		}
-		articles = append(articles, article)
+		source.PublishedAt = timeFromUnix(publishedAt)
+		items = append(items, source)
 	}
-	return articles
+	return items
+}
+
+// SortedArticles lists every article in the given sort mode, newest first
+// when no mode is passed.
+func (s *Store) SortedArticles(mode ...SortMode) []Article {
+	order := SortNewest
+	if len(mode) > 0 {
+		order = mode[0]
+	}
+	return s.SortedArticlesPage(order, nil, 0)
 }
 
 func scanArticle(scanner interface{ Scan(dest ...any) error }) (Article, error) {
 	var article Article
 	var publishedAt, fetchedAt sql.NullInt64
 	var isRead, isStarred int
-	if err := scanner.Scan(&article.ID, &article.FeedID, &article.GUID, &article.Title, &article.URL, &article.BaseURL, &article.Author, &article.Content, &article.ContentText, &publishedAt, &fetchedAt, &isRead, &isStarred, &article.FeedTitle); err != nil {
+	if err := scanner.Scan(&article.ID, &article.FeedID, &article.GUID, &article.Title, &article.URL, &article.BaseURL, &article.Author, &article.Content, &article.ContentText, &publishedAt, &fetchedAt, &isRead, &isStarred, &article.FeedTitle, &article.Score); err != nil {
 		return Article{}, err
 	}
 	article.PublishedAt = timeFromUnix(publishedAt)
@@ -767,6 +1060,16 @@ func scanArticle(scanner interface{ Scan(dest ...any) error }) (Article, error)
 	return article, nil
 }
 
+func scanSummary(scanner interface{ Scan(dest ...any) error }) (Summary, error) {
+	var summary Summary
+	var generatedAt sql.NullInt64
+	if err := scanner.Scan(&summary.ID, &summary.ArticleID, &summary.Content, &summary.Model, &generatedAt, &summary.PromptTokens, &summary.CompletionTokens, &summary.ContentHash); err != nil {
+		return Summary{}, err
+	}
+	summary.GeneratedAt = timeFromUnix(generatedAt)
+	return summary, nil
+}
+
 func scanDeleted(scanner interface{ Scan(dest ...any) error }, deletedID *int) (Article, error) {
 	var article Article
 	var publishedAt, fetchedAt sql.NullInt64
@@ -819,12 +1122,7 @@ func (s *Store) MergeDuplicateArticles() error {
 	}
 	defer rows.Close()
 
-	type mergeState struct {
-		id        int
-		isRead    bool
-		isStarred bool
-	}
-	baseToState := map[string]mergeState{}
+	baseToState := map[string]articleMergeState{}
 	for rows.Next() {
 		var id, feedID int
 		var urlValue, baseValue string
@@ -833,8 +1131,7 @@ func (s *Store) MergeDuplicateArticles() error {
 		if err := rows.Scan(&id, &feedID, &urlValue, &baseValue, &publishedAt, &isRead, &isStarred); err != nil {
 			return err
 		}
-		currentRead := isRead != 0
-		currentStarred := isStarred != 0
+		current := articleMergeState{id: id, feedID: feedID, publishedAt: publishedAt, isRead: isRead != 0, isStarred: isStarred != 0}
 		normalized := baseURL(urlValue)
 		if normalized == "" {
 			normalized = strings.TrimSpace(baseValue)
@@ -849,59 +1146,90 @@ func (s *Store) MergeDuplicateArticles() error {
 		}
 		baseValue = normalized
 		if existing, ok := baseToState[baseValue]; ok {
-			if err := ensureArticleSourceFn(tx, existing.id, feedID, timeFromUnix(publishedAt)); err != nil {
-				return err
-			}
-			mergedRead := existing.isRead && currentRead
-			mergedStarred := existing.isStarred || currentStarred
-			if mergedRead != existing.isRead || mergedStarred != existing.isStarred {
-				if _, err := tx.Exec(`UPDATE articles SET is_read = ?, is_starred = ? WHERE id = ?`,
-					boolToInt(mergedRead), boolToInt(mergedStarred), existing.id); err != nil {
-					return err
-				}
-				existing.isRead = mergedRead
-				existing.isStarred = mergedStarred
-				baseToState[baseValue] = existing
-			}
-			hasSummary, err := existsByIDFn(tx, "summaries", existing.id)
-			if err != nil {
-				return err
-			}
-			if hasSummary {
-				if _, err := tx.Exec(`DELETE FROM summaries WHERE article_id = ?`, id); err != nil {
-					return err
-				}
-			} else {
-				if _, err := tx.Exec(`UPDATE summaries SET article_id = ? WHERE article_id = ?`, existing.id, id); err != nil {
-					return err
-				}
-			}
-			hasSaved, err := existsByIDFn(tx, "saved", existing.id)
-			if err != nil {
-				return err
-			}
-			if hasSaved {
-				if _, err := tx.Exec(`DELETE FROM saved WHERE article_id = ?`, id); err != nil {
-					return err
-				}
-			} else {
-				if _, err := tx.Exec(`UPDATE saved SET article_id = ? WHERE article_id = ?`, existing.id, id); err != nil {
-					return err
-				}
-			}
-			if _, err := tx.Exec(`DELETE FROM articles WHERE id = ?`, id); err != nil {
+			if err := mergeArticleInto(tx, &existing, current); err != nil {
 				return err
 			}
+			baseToState[baseValue] = existing
 			continue
 		}
-		baseToState[baseValue] = mergeState{id: id, isRead: currentRead, isStarred: currentStarred}
+		baseToState[baseValue] = current
 		if err := ensureArticleSourceFn(tx, id, feedID, timeFromUnix(publishedAt)); err != nil {
 			return err
 		}
 	}
+	rows.Close()
+	if s.dedupSimilarity > 0 {
+		if err := mergeNearDuplicates(tx, s.dedupSimilarity); err != nil {
+			return err
+		}
+	}
 	return commitTx(tx)
 }
 
+type articleMergeState struct {
+	id          int
+	feedID      int
+	publishedAt sql.NullInt64
+	isRead      bool
+	isStarred   bool
+}
+
+func mergeArticleInto(tx *sql.Tx, existing *articleMergeState, dup articleMergeState) error {
+	if err := ensureArticleSourceFn(tx, existing.id, dup.feedID, timeFromUnix(dup.publishedAt)); err != nil {
+		return err
+	}
+	mergedRead := existing.isRead && dup.isRead
+	mergedStarred := existing.isStarred || dup.isStarred
+	if mergedRead != existing.isRead || mergedStarred != existing.isStarred {
+		if _, err := tx.Exec(`UPDATE articles SET is_read = ?, is_starred = ? WHERE id = ?`,
+			boolToInt(mergedRead), boolToInt(mergedStarred), existing.id); err != nil {
+			return err
+		}
+		existing.isRead = mergedRead
+		existing.isStarred = mergedStarred
+	}
+	for _, table := range []string{"summaries", "saved", "sync_items", "wayback_snapshots", "article_embeddings"} {
+		if err := moveArticleRow(tx, table, existing.id, dup.id); err != nil {
+			return err
+		}
+	}
+	for _, table := range []string{"article_tags", "article_sources"} {
+		if _, err := tx.Exec("UPDATE OR IGNORE "+table+" SET article_id = ? WHERE article_id = ?", existing.id, dup.id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE article_id = ?", dup.id); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`UPDATE summary_versions SET article_id = ? WHERE article_id = ?`, existing.id, dup.id); err != nil {
+		return err
+	}
+	// Keep whichever copy sits earlier in the reading queue.
+	if _, err := tx.Exec(`UPDATE articles SET queue_position = dup.queue_position
+		FROM (SELECT queue_position FROM articles WHERE id = ?) AS dup
+		WHERE articles.id = ? AND dup.queue_position IS NOT NULL
+		AND (articles.queue_position IS NULL OR articles.queue_position > dup.queue_position)`, dup.id, existing.id); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM articles WHERE id = ?`, dup.id)
+	return err
+}
+
+// moveArticleRow hands a one-row-per-article table over to keptID, dropping
+// the duplicate's row when the kept article already has its own.
+func moveArticleRow(tx *sql.Tx, table string, keptID int, dupID int) error {
+	exists, err := existsByIDFn(tx, table, keptID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		_, err = tx.Exec("DELETE FROM "+table+" WHERE article_id = ?", dupID)
+		return err
+	}
+	_, err = tx.Exec("UPDATE "+table+" SET article_id = ? WHERE article_id = ?", keptID, dupID)
+	return err
+}
+
 func existsByID(tx *sql.Tx, table string, articleID int) (bool, error) {
 	var existing int
 	if err := tx.QueryRow("SELECT 1 FROM "+table+" WHERE article_id = ? LIMIT 1", articleID).Scan(&existing); err != nil {
@@ -0,0 +1,59 @@
+package main
+
+func (s *Store) SetArticlesRead(ids []int, read bool) error {
+	return s.updateArticles(`UPDATE articles SET is_read = ? WHERE id = ?`, ids, boolToInt(read))
+}
+
+func (s *Store) SetArticlesStarred(ids []int, starred bool) error {
+	return s.updateArticles(`UPDATE articles SET is_starred = ? WHERE id = ?`, ids, boolToInt(starred))
+}
+
+func (s *Store) updateArticles(query string, ids []int, value int) error {
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, id := range ids {
+		if _, err := stmt.Exec(value, id); err != nil {
+			return err
+		}
+	}
+	return commitTx(tx)
+}
+
+func (s *Store) DeleteReadArticles(feedID int) (int, error) {
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	const matching = `SELECT id FROM articles WHERE feed_id = ? AND is_read = 1 AND is_starred = 0`
+	if _, err := tx.Exec(`INSERT INTO deleted (feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title, deleted_at)
+		SELECT feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, 0, feed_title, ? FROM articles WHERE id IN (`+matching+`) ORDER BY id`,
+		timeToUnix(s.now().UTC()), feedID); err != nil {
+		return 0, err
+	}
+	for _, table := range []string{"summaries", "summary_versions", "saved"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE article_id IN (`+matching+`)`, feedID); err != nil {
+			return 0, err
+		}
+	}
+	result, err := tx.Exec(`DELETE FROM articles WHERE id IN (`+matching+`)`, feedID)
+	if err != nil {
+		return 0, err
+	}
+	removed, err := rowsAffected(result)
+	if err != nil {
+		return 0, err
+	}
+	if err := commitTx(tx); err != nil {
+		return 0, err
+	}
+	return int(removed), nil
+}
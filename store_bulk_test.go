@@ -0,0 +1,92 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestStoreBulkReadAndStar(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	added, err := store.InsertArticles(feed, []Article{{GUID: "a", Title: "A"}, {GUID: "b", Title: "B"}, {GUID: "c", Title: "C"}})
+	if err != nil || len(added) != 3 {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if err := store.SetArticlesRead([]int{added[0].ID, added[2].ID}, true); err != nil {
+		t.Fatalf("SetArticlesRead error: %v", err)
+	}
+	if err := store.SetArticlesStarred([]int{added[1].ID}, true); err != nil {
+		t.Fatalf("SetArticlesStarred error: %v", err)
+	}
+	articles := store.Articles()
+	if !articles[0].IsRead || articles[1].IsRead || !articles[2].IsRead || !articles[1].IsStarred || articles[0].IsStarred {
+		t.Fatalf("unexpected state %+v", articles)
+	}
+	if err := store.SetArticlesRead(nil, true); err != nil {
+		t.Fatalf("expected empty batch to succeed, got %v", err)
+	}
+
+	origBegin := beginTx
+	beginTx = func(*sql.DB) (*sql.Tx, error) { return nil, errors.New("begin fail") }
+	t.Cleanup(func() { beginTx = origBegin })
+	if err := store.SetArticlesRead([]int{added[1].ID}, true); err == nil {
+		t.Fatalf("expected begin error")
+	}
+	if _, err := store.DeleteReadArticles(feed.ID); err == nil {
+		t.Fatalf("expected begin error")
+	}
+}
+
+func TestStoreDeleteReadArticles(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	other, err := store.InsertFeed(Feed{Title: "Other", URL: "https://other.example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	added, err := store.InsertArticles(feed, []Article{{GUID: "read", Title: "Read", IsRead: true}, {GUID: "starred", Title: "Starred", IsRead: true, IsStarred: true}, {GUID: "unread", Title: "Unread"}})
+	if err != nil || len(added) != 3 {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := store.InsertArticles(other, []Article{{GUID: "elsewhere", Title: "Elsewhere", IsRead: true}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if err := store.SetArticlesRead([]int{added[0].ID, added[1].ID}, true); err != nil {
+		t.Fatalf("SetArticlesRead error: %v", err)
+	}
+	if err := store.SetArticlesStarred([]int{added[1].ID}, true); err != nil {
+		t.Fatalf("SetArticlesStarred error: %v", err)
+	}
+	if _, err := store.UpsertSummary(Summary{ArticleID: added[0].ID, Content: "sum"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	if err := store.SaveBookmark(added[0].ID, "raindrop", 1, nil); err != nil {
+		t.Fatalf("SaveBookmark error: %v", err)
+	}
+	removed, err := store.DeleteReadArticles(feed.ID)
+	if err != nil || removed != 1 {
+		t.Fatalf("expected one removal, got %d %v", removed, err)
+	}
+	if articles := store.Articles(); len(articles) != 3 {
+		t.Fatalf("expected starred, unread and other feed kept, got %+v", articles)
+	}
+	if deleted := store.Deleted(); len(deleted) != 1 || deleted[0].GUID != "read" {
+		t.Fatalf("expected deleted record, got %+v", deleted)
+	}
+	if store.SavedCount() != 0 || len(store.Summaries()) != 0 {
+		t.Fatalf("expected dependent rows removed")
+	}
+	if _, err := store.UndeleteLast(); err != nil {
+		t.Fatalf("UndeleteLast error: %v", err)
+	}
+	if removed, err := store.DeleteReadArticles(other.ID + 1); err != nil || removed != 0 {
+		t.Fatalf("expected nothing removed, got %d %v", removed, err)
+	}
+}
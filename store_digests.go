@@ -0,0 +1,61 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+type digestArticle struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Feed  string `json:"feed,omitempty"`
+}
+
+func (s *Store) SaveDigest(digest Digest) (Digest, error) {
+	refs := make([]digestArticle, 0, len(digest.Articles))
+	for _, article := range digest.Articles {
+		refs = append(refs, digestArticle{Title: article.Title, URL: article.URL, Feed: article.FeedTitle})
+	}
+	blob, err := json.Marshal(refs)
+	if err != nil {
+		return Digest{}, err
+	}
+	if digest.GeneratedAt.IsZero() {
+		digest.GeneratedAt = s.now().UTC()
+	}
+	result, err := s.db.Exec(`INSERT INTO digests (content, model, generated_at, articles) VALUES (?, ?, ?, ?)`, digest.Content, digest.Model, timeToUnix(digest.GeneratedAt), string(blob))
+	if err != nil {
+		return Digest{}, err
+	}
+	id, err := lastInsertID(result)
+	if err != nil {
+		return Digest{}, err
+	}
+	digest.ID = int(id)
+	return digest, nil
+}
+
+func (s *Store) Digests() []Digest {
+	rows, err := s.db.Query(`SELECT id, COALESCE(content, ''), COALESCE(model, ''), generated_at, COALESCE(articles, '') FROM digests ORDER BY generated_at DESC, id DESC`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	digests := []Digest{}
+	for rows.Next() {
+		var digest Digest
+		var generatedAt sql.NullInt64
+		var articles string
+		if err := rows.Scan(&digest.ID, &digest.Content, &digest.Model, &generatedAt, &articles); err != nil {
+			return digests
+		}
+		digest.GeneratedAt = timeFromUnix(generatedAt)
+		var refs []digestArticle
+		_ = json.Unmarshal([]byte(articles), &refs)
+		for _, ref := range refs {
+			digest.Articles = append(digest.Articles, Article{Title: ref.Title, URL: ref.URL, FeedTitle: ref.Feed})
+		}
+		digests = append(digests, digest)
+	}
+	return digests
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreSaveAndListDigests(t *testing.T) {
+	store := newTestStore(t)
+	if digests := store.Digests(); len(digests) != 0 {
+		t.Fatalf("expected no digests, got %+v", digests)
+	}
+	older := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)
+	first, err := store.SaveDigest(Digest{Content: "Yesterday", Model: "m", GeneratedAt: older, Articles: []Article{{Title: "One", URL: "https://example.com/1", FeedTitle: "Feed"}}})
+	if err != nil || first.ID == 0 {
+		t.Fatalf("SaveDigest error: %+v %v", first, err)
+	}
+	if _, err := store.SaveDigest(Digest{Content: "Today", Model: "m", GeneratedAt: older.Add(24 * time.Hour)}); err != nil {
+		t.Fatalf("SaveDigest error: %v", err)
+	}
+	digests := store.Digests()
+	if len(digests) != 2 || digests[0].Content != "Today" || digests[1].ID != first.ID {
+		t.Fatalf("unexpected digests %+v", digests)
+	}
+	if !digests[1].GeneratedAt.Equal(older) || len(digests[1].Articles) != 1 || digests[1].Articles[0].FeedTitle != "Feed" || digests[1].Articles[0].URL != "https://example.com/1" {
+		t.Fatalf("unexpected stored digest %+v", digests[1])
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"os"
@@ -55,7 +56,7 @@ func TestStoreErrorPathsWithClosedDB(t *testing.T) {
 	if count := store.DeleteOldArticles(7); count != 0 {
 		t.Fatalf("expected delete old count 0")
 	}
-	if err := store.SaveToRaindrop(1, 2, []string{"t"}); err == nil {
+	if err := store.SaveBookmark(1, "raindrop", 2, []string{"t"}); err == nil {
 		t.Fatalf("expected save to raindrop error")
 	}
 	if count := store.SavedCount(); count != 0 {
@@ -90,7 +91,7 @@ func TestNewStoreDirMismatch(t *testing.T) {
 	}
 }
 
-func TestStoreSaveToRaindropInsert(t *testing.T) {
+func TestStoreSaveBookmarkInsert(t *testing.T) {
 	root := t.TempDir()
 	path := filepath.Join(root, "store.db")
 	store, err := NewStore(path)
@@ -105,8 +106,8 @@ func TestStoreSaveToRaindropInsert(t *testing.T) {
 	if err != nil {
 		t.Fatalf("InsertArticles error: %v", err)
 	}
-	if err := store.SaveToRaindrop(articles[0].ID, 8, []string{"a"}); err != nil {
-		t.Fatalf("SaveToRaindrop error: %v", err)
+	if err := store.SaveBookmark(articles[0].ID, "raindrop", 8, []string{"a"}); err != nil {
+		t.Fatalf("SaveBookmark error: %v", err)
 	}
 	if store.SavedCount() != 1 {
 		t.Fatalf("expected saved count 1")
@@ -259,8 +260,8 @@ func TestDeleteArticleCleanupErrors(t *testing.T) {
 	if err != nil {
 		t.Fatalf("InsertArticles error: %v", err)
 	}
-	if err := store.SaveToRaindrop(articles[0].ID, 1, []string{"tag"}); err != nil {
-		t.Fatalf("SaveToRaindrop error: %v", err)
+	if err := store.SaveBookmark(articles[0].ID, "raindrop", 1, []string{"tag"}); err != nil {
+		t.Fatalf("SaveBookmark error: %v", err)
 	}
 	if _, err := store.db.Exec(`DROP TABLE saved`); err != nil {
 		t.Fatalf("drop saved error: %v", err)
@@ -559,7 +560,8 @@ func TestArticleSourcesScanError(t *testing.T) {
 
 func TestFindArticleIDByBaseURL(t *testing.T) {
 	store, _ := newWritableStore(t)
-	tx, err := store.db.Begin()
+	// A read-only transaction starts deferred, so the DROP below is not blocked.
+	tx, err := store.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
 	if err != nil {
 		t.Fatalf("begin error: %v", err)
 	}
@@ -1205,7 +1207,7 @@ func TestDeleteOldAndSaveErrors(t *testing.T) {
 	store, _ = newWritableStore(t)
 	orig := tagsMarshal
 	tagsMarshal = func(any) ([]byte, error) { return nil, errors.New("marshal fail") }
-	if err := store.SaveToRaindrop(1, 2, []string{"t"}); err == nil {
+	if err := store.SaveBookmark(1, "raindrop", 2, []string{"t"}); err == nil {
 		t.Fatalf("expected marshal error")
 	}
 	tagsMarshal = orig
@@ -1213,7 +1215,7 @@ func TestDeleteOldAndSaveErrors(t *testing.T) {
 	store, _ = newWritableStore(t)
 	origRows := rowsAffected
 	rowsAffected = func(sql.Result) (int64, error) { return 0, errors.New("rows fail") }
-	if err := store.SaveToRaindrop(1, 2, []string{"t"}); err == nil {
+	if err := store.SaveBookmark(1, "raindrop", 2, []string{"t"}); err == nil {
 		t.Fatalf("expected rows affected error")
 	}
 	rowsAffected = origRows
@@ -1222,7 +1224,39 @@ func TestDeleteOldAndSaveErrors(t *testing.T) {
 	if _, err := store.db.Exec(`CREATE TRIGGER saved_block BEFORE INSERT ON saved BEGIN SELECT RAISE(FAIL, 'no'); END;`); err != nil {
 		t.Fatalf("trigger error: %v", err)
 	}
-	if err := store.SaveToRaindrop(1, 2, []string{"t"}); err == nil {
+	if err := store.SaveBookmark(1, "raindrop", 2, []string{"t"}); err == nil {
 		t.Fatalf("expected insert error")
 	}
 }
+
+func TestStoreFeedValidators(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "store.db"))
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	inserted, err := store.InsertFeed(Feed{Title: "Feed", URL: "http://example.test/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if err := store.SetFeedValidators(inserted.ID, `"v1"`, "Mon, 04 Mar 2024 09:00:00 GMT"); err != nil {
+		t.Fatalf("SetFeedValidators error: %v", err)
+	}
+	feed := store.Feeds()[0]
+	if feed.ETag != `"v1"` || feed.LastModified != "Mon, 04 Mar 2024 09:00:00 GMT" {
+		t.Fatalf("unexpected validators %+v", feed)
+	}
+	feed.Title = "Renamed"
+	if err := store.UpdateFeed(feed); err != nil {
+		t.Fatalf("UpdateFeed error: %v", err)
+	}
+	if feed := store.Feeds()[0]; feed.ETag != `"v1"` {
+		t.Fatalf("expected validators kept on rename, got %+v", feed)
+	}
+	feed.URL = "http://example.test/atom"
+	if err := store.UpdateFeed(feed); err != nil {
+		t.Fatalf("UpdateFeed error: %v", err)
+	}
+	if feed := store.Feeds()[0]; feed.ETag != "" || feed.LastModified != "" {
+		t.Fatalf("expected validators reset on url change, got %+v", feed)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"database/sql"
+	"strings"
+)
+
+func (s *Store) SetFeedFolder(feedID int, folder string) error {
+	folder = normalizeFolder(folder)
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM feed_folders WHERE feed_id = ?`, feedID); err != nil {
+		return err
+	}
+	if folder != "" {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO folders (name) VALUES (?)`, folder); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO feed_folders (feed_id, folder_id) SELECT ?, id FROM folders WHERE name = ?`, feedID, folder); err != nil {
+			return err
+		}
+	}
+	if err := pruneFolders(tx); err != nil {
+		return err
+	}
+	return commitTx(tx)
+}
+
+func (s *Store) Folders() []string {
+	rows, err := s.db.Query(`SELECT name FROM folders ORDER BY name COLLATE NOCASE`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	folders := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return folders
+		}
+		folders = append(folders, name)
+	}
+	return folders
+}
+
+func (s *Store) FolderIDs() map[string]int {
+	ids := map[string]int{}
+	rows, err := s.db.Query(`SELECT id, name FROM folders`)
+	if err != nil {
+		return ids
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return ids
+		}
+		ids[name] = id
+	}
+	return ids
+}
+
+func pruneFolders(tx *sql.Tx) error {
+	_, err := tx.Exec(`DELETE FROM folders WHERE id NOT IN (SELECT folder_id FROM feed_folders)`)
+	return err
+}
+
+const folderSeparator = "/"
+
+func normalizeFolder(folder string) string {
+	return strings.Join(folderPath(folder), folderSeparator)
+}
+
+func folderPath(folder string) []string {
+	path := []string{}
+	for _, part := range strings.Split(folder, folderSeparator) {
+		if part = strings.Join(strings.Fields(part), " "); part != "" {
+			path = append(path, part)
+		}
+	}
+	return path
+}
+
+func folderWithin(folder, parent string) bool {
+	if parent == "" || len(folder) < len(parent) || !strings.EqualFold(folder[:len(parent)], parent) {
+		return false
+	}
+	return len(folder) == len(parent) || strings.HasPrefix(folder[len(parent):], folderSeparator)
+}
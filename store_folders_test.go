@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestStoreFeedFolders(t *testing.T) {
+	store := newTestStore(t)
+	tech, err := store.InsertFeed(Feed{Title: "Tech", URL: "https://example.com/tech", Folder: " Tech  News "})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	news, err := store.InsertFeed(Feed{Title: "News", URL: "https://example.com/news"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if err := store.SetFeedFolder(news.ID, "blogs"); err != nil {
+		t.Fatalf("SetFeedFolder error: %v", err)
+	}
+	feeds := store.Feeds()
+	if len(feeds) != 2 || feeds[0].Folder != "Tech News" || feeds[1].Folder != "blogs" {
+		t.Fatalf("unexpected feeds: %+v", feeds)
+	}
+	if folders := store.Folders(); len(folders) != 2 || folders[0] != "blogs" || folders[1] != "Tech News" {
+		t.Fatalf("unexpected folders: %v", folders)
+	}
+	if err := store.SetFeedFolder(news.ID, ""); err != nil {
+		t.Fatalf("SetFeedFolder error: %v", err)
+	}
+	if folders := store.Folders(); len(folders) != 1 || store.Feeds()[1].Folder != "" {
+		t.Fatalf("expected empty folder pruned, got %v", folders)
+	}
+	if err := store.DeleteFeed(tech.ID); err != nil {
+		t.Fatalf("DeleteFeed error: %v", err)
+	}
+	if folders := store.Folders(); len(folders) != 0 {
+		t.Fatalf("expected folders pruned on delete, got %v", folders)
+	}
+}
+
+func TestStoreFeedFolderClosedDB(t *testing.T) {
+	store := newTestStore(t)
+	_ = store.db.Close()
+	if err := store.SetFeedFolder(1, "Tech"); err == nil {
+		t.Fatalf("expected error on closed db")
+	}
+	if folders := store.Folders(); folders != nil {
+		t.Fatalf("expected nil folders, got %v", folders)
+	}
+}
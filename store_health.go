@@ -0,0 +1,15 @@
+package main
+
+func (s *Store) RecordFeedSuccess(id int) error {
+	_, err := s.db.Exec(`UPDATE feeds SET fail_count = 0, last_error = '', last_success = ? WHERE id = ?`, timeToUnix(s.now().UTC()), id)
+	return err
+}
+
+func (s *Store) RecordFeedFailure(id int, message string) (int, error) {
+	if _, err := s.db.Exec(`UPDATE feeds SET fail_count = COALESCE(fail_count, 0) + 1, last_error = ?, last_failure = ? WHERE id = ?`, message, timeToUnix(s.now().UTC()), id); err != nil {
+		return 0, err
+	}
+	var count int
+	err := s.db.QueryRow(`SELECT COALESCE(fail_count, 0) FROM feeds WHERE id = ?`, id).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreRecordFeedHealth(t *testing.T) {
+	store := newTestStore(t)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	store.clock = &testClock{now: now}
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	for want := 1; want <= 2; want++ {
+		count, err := store.RecordFeedFailure(feed.ID, "timeout")
+		if err != nil || count != want {
+			t.Fatalf("expected %d failures, got %d %v", want, count, err)
+		}
+	}
+	got := store.Feeds()[0]
+	if got.FailCount != 2 || got.LastError != "timeout" || !got.LastFailure.Equal(now) || !got.LastSuccess.IsZero() {
+		t.Fatalf("unexpected health %+v", got)
+	}
+	if err := store.RecordFeedSuccess(feed.ID); err != nil {
+		t.Fatalf("RecordFeedSuccess error: %v", err)
+	}
+	got = store.Feeds()[0]
+	if got.FailCount != 0 || got.LastError != "" || !got.LastSuccess.Equal(now) || !got.LastFailure.Equal(now) {
+		t.Fatalf("expected failures reset, got %+v", got)
+	}
+	if _, err := store.RecordFeedFailure(feed.ID+1, "missing"); err == nil {
+		t.Fatalf("expected missing feed error")
+	}
+}
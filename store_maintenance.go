@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+type dbMaintenanceReport struct {
+	SizeBefore int64
+	SizeAfter  int64
+	Problems   []string
+}
+
+func (s *Store) CheckIntegrity() ([]string, error) {
+	rows, err := s.db.Query(`PRAGMA integrity_check`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	problems := []string{}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(line) != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil && len(problems) == 0 {
+		return nil, err
+	}
+	return problems, nil
+}
+
+func (s *Store) Maintain() (dbMaintenanceReport, error) {
+	report := dbMaintenanceReport{SizeBefore: s.fileSize()}
+	problems, err := s.CheckIntegrity()
+	if err != nil {
+		return report, err
+	}
+	report.Problems = problems
+	if len(problems) > 0 {
+		report.SizeAfter = report.SizeBefore
+		return report, nil
+	}
+	for _, stmt := range []string{`VACUUM`, `ANALYZE`, `PRAGMA wal_checkpoint(TRUNCATE)`} {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return report, err
+		}
+	}
+	report.SizeAfter = s.fileSize()
+	return report, nil
+}
+
+func (s *Store) fileSize() int64 {
+	var size int64
+	for _, suffix := range []string{"", "-wal"} {
+		if info, err := os.Stat(s.path + suffix); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
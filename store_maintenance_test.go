@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func corruptStoreIndex(t *testing.T, store *Store) {
+	t.Helper()
+	store.db.SetMaxOpenConns(1)
+	var root int
+	if err := store.db.QueryRow(`SELECT rootpage FROM sqlite_master WHERE name = 'feeds'`).Scan(&root); err != nil {
+		t.Fatalf("rootpage error: %v", err)
+	}
+	for _, stmt := range []string{`PRAGMA writable_schema = ON`, fmt.Sprintf(`UPDATE sqlite_master SET rootpage = %d WHERE name = 'sqlite_autoindex_articles_1'`, root), `PRAGMA writable_schema = OFF`} {
+		if _, err := store.db.Exec(stmt); err != nil {
+			t.Fatalf("corrupt error: %v", err)
+		}
+	}
+}
+
+func TestStoreMaintainReclaimsSpace(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles := []Article{}
+	for i := 0; i < 200; i++ {
+		articles = append(articles, Article{GUID: fmt.Sprintf("g%d", i), URL: fmt.Sprintf("https://example.com/%d", i), Content: strings.Repeat("x", 4096)})
+	}
+	if _, err := store.InsertArticles(feed, articles); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := store.db.Exec(`DELETE FROM articles`); err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+	if problems, err := store.CheckIntegrity(); err != nil || len(problems) != 0 {
+		t.Fatalf("expected healthy database, got %v %v", problems, err)
+	}
+	report, err := store.Maintain()
+	if err != nil {
+		t.Fatalf("Maintain error: %v", err)
+	}
+	if report.SizeAfter >= report.SizeBefore || len(report.Problems) != 0 {
+		t.Fatalf("expected space reclaimed, got %+v", report)
+	}
+	lines := formatDBMaintenance(report)
+	if lines[0] != "Integrity check: ok" || !strings.HasPrefix(lines[1], "Size: ") || !strings.HasSuffix(lines[1], "reclaimed)") {
+		t.Fatalf("unexpected report %v", lines)
+	}
+}
+
+func TestStoreMaintainStopsOnCorruption(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := store.InsertArticles(feed, []Article{{GUID: "a", URL: "https://example.com/a"}, {GUID: "b", URL: "https://example.com/b"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	corruptStoreIndex(t, store)
+	store.Close()
+	damaged, err := NewStore(store.path)
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	defer damaged.Close()
+	report, err := damaged.Maintain()
+	if err != nil || len(report.Problems) == 0 || report.SizeAfter != report.SizeBefore {
+		t.Fatalf("expected corruption report, got %+v %v", report, err)
+	}
+	lines := formatDBMaintenance(report)
+	if !strings.HasPrefix(lines[0], "Integrity check found") || !strings.HasPrefix(lines[len(lines)-1], "Skipped VACUUM") {
+		t.Fatalf("unexpected report %v", lines)
+	}
+
+	app := &App{store: damaged, status: "ready"}
+	app.warnIfDBDamaged()
+	if !strings.Contains(app.status, "run greeder --db-maintenance") {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+}
+
+func TestRunMainDBMaintenance(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("XDG_DATA_HOME", root)
+	var stdout, stderr strings.Builder
+	if err := runMain([]string{"--db-maintenance"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runMain error: %v (%s)", err, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Integrity check: ok") || !strings.Contains(stdout.String(), "Size: ") {
+		t.Fatalf("unexpected output %q", stdout.String())
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import "strings"
+
+func (s *Store) SortedArticlesPage(mode SortMode, after *Article, limit int) []Article {
+	query := `SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title, COALESCE(score, 0) FROM articles`
+	where, order, args := articleOrderSQL(mode, after)
+	if where != "" {
+		query += ` WHERE ` + where
+	}
+	query += ` ORDER BY ` + order
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	articles := []Article{}
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return articles
+		}
+		articles = append(articles, article)
+	}
+	return articles
+}
+
+func (s *Store) ArticlesAfterID(id int) []Article {
+	rows, err := s.db.Query(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title, COALESCE(score, 0) FROM articles WHERE id > ? ORDER BY COALESCE(published_at, 0) DESC, id`, id)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	articles := []Article{}
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return articles
+		}
+		articles = append(articles, article)
+	}
+	return articles
+}
+
+// ArticlesByID reads the listed articles, skipping any that no longer exist.
+func (s *Store) ArticlesByID(ids []int) []Article {
+	if len(ids) == 0 {
+		return []Article{}
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return s.queryArticles(`WHERE id IN (?`+strings.Repeat(", ?", len(ids)-1)+`) ORDER BY id`, args...)
+}
+
+func (s *Store) QueuedArticles() []Article {
+	return s.queryArticles(`WHERE queue_position IS NOT NULL ORDER BY queue_position`)
+}
+
+func (s *Store) ArticlesMissingEmbedding(model string, limit int) []Article {
+	return s.queryArticles(`WHERE id NOT IN (SELECT article_id FROM article_embeddings WHERE model = ?) ORDER BY id LIMIT ?`, model, limit)
+}
+
+func (s *Store) UnsummarizedUnreadCount() int {
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM articles WHERE COALESCE(is_read, 0) = 0 AND id NOT IN (SELECT article_id FROM summaries)`).Scan(&count); err != nil {
+		return 0
+	}
+	return count
+}
+
+func (s *Store) queryArticles(clause string, args ...any) []Article {
+	rows, err := s.db.Query(`SELECT id, feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title, COALESCE(score, 0) FROM articles `+clause, args...)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	articles := []Article{}
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return articles
+		}
+		articles = append(articles, article)
+	}
+	return articles
+}
+
+func (s *Store) MaxArticleID() int {
+	var id int
+	if err := s.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM articles`).Scan(&id); err != nil {
+		return 0
+	}
+	return id
+}
+
+func (s *Store) ArticleCountByFeed() map[int]int {
+	counts := map[int]int{}
+	rows, err := s.db.Query(`SELECT feed_id, COUNT(*) FROM articles GROUP BY feed_id`)
+	if err != nil {
+		return counts
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var feedID, count int
+		if err := rows.Scan(&feedID, &count); err != nil {
+			return counts
+		}
+		counts[feedID] = count
+	}
+	return counts
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func insertPagedArticles(t *testing.T, store *Store, count int) Feed {
+	t.Helper()
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	articles := make([]Article, 0, count)
+	for i := 0; i < count; i++ {
+		articles = append(articles, Article{
+			GUID:        fmt.Sprintf("g%d", i),
+			Title:       fmt.Sprintf("Article %d", i),
+			URL:         fmt.Sprintf("https://example.com/%d", i),
+			PublishedAt: base.Add(time.Duration(i/2) * time.Hour),
+		})
+	}
+	if _, err := store.InsertArticles(feed, articles); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	return feed
+}
+
+func TestStoreSortedArticlesPage(t *testing.T) {
+	store := newTestStore(t)
+	feed := insertPagedArticles(t, store, 7)
+	all := store.SortedArticles()
+	if len(all) != 7 {
+		t.Fatalf("expected 7 articles, got %d", len(all))
+	}
+	paged := []Article{}
+	var after *Article
+	for {
+		page := store.SortedArticlesPage(SortNewest, after, 3)
+		paged = append(paged, page...)
+		if len(page) < 3 {
+			break
+		}
+		after = &page[len(page)-1]
+	}
+	if len(paged) != len(all) {
+		t.Fatalf("expected %d paged articles, got %d", len(all), len(paged))
+	}
+	for i := range all {
+		if paged[i].ID != all[i].ID {
+			t.Fatalf("page order differs at %d: %d vs %d", i, paged[i].ID, all[i].ID)
+		}
+	}
+	maxID := 0
+	for _, article := range all {
+		maxID = max(maxID, article.ID)
+	}
+	if store.MaxArticleID() != maxID {
+		t.Fatalf("expected max id %d, got %d", maxID, store.MaxArticleID())
+	}
+	if counts := store.ArticleCountByFeed(); counts[feed.ID] != 7 {
+		t.Fatalf("unexpected counts %v", counts)
+	}
+	lastID := store.MaxArticleID()
+	if newer := store.ArticlesAfterID(lastID); len(newer) != 0 {
+		t.Fatalf("expected no newer articles, got %+v", newer)
+	}
+	if _, err := store.InsertArticles(feed, []Article{{GUID: "late", Title: "Late", URL: "https://example.com/late"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if newer := store.ArticlesAfterID(lastID); len(newer) != 1 || newer[0].GUID != "late" {
+		t.Fatalf("unexpected newer articles %+v", newer)
+	}
+}
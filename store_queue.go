@@ -0,0 +1,95 @@
+package main
+
+import "errors"
+
+func (s *Store) QueueArticle(articleID int) error {
+	result, err := s.db.Exec(`UPDATE articles SET queue_position = (SELECT COALESCE(MAX(queue_position), 0) + 1 FROM articles) WHERE id = ? AND queue_position IS NULL`, articleID)
+	if err != nil {
+		return err
+	}
+	rows, err := rowsAffected(result)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		if _, queued := s.QueuePositions()[articleID]; !queued {
+			return errors.New("article not found")
+		}
+	}
+	return nil
+}
+
+func (s *Store) DequeueArticle(articleID int) error {
+	if _, err := s.db.Exec(`UPDATE articles SET queue_position = NULL WHERE id = ?`, articleID); err != nil {
+		return err
+	}
+	return s.CompactQueue()
+}
+
+func (s *Store) QueuePositions() map[int]int {
+	positions := map[int]int{}
+	rows, err := s.db.Query(`SELECT id, queue_position FROM articles WHERE queue_position IS NOT NULL`)
+	if err != nil {
+		return positions
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, position int
+		if err := rows.Scan(&id, &position); err != nil {
+			return positions
+		}
+		positions[id] = position
+	}
+	return positions
+}
+
+func (s *Store) QueuedIDs() []int {
+	ids := []int{}
+	rows, err := s.db.Query(`SELECT id FROM articles WHERE queue_position IS NOT NULL ORDER BY queue_position, id`)
+	if err != nil {
+		return ids
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return ids
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (s *Store) MoveQueued(articleID int, delta int) (bool, error) {
+	ids := s.QueuedIDs()
+	from := -1
+	for i, id := range ids {
+		if id == articleID {
+			from = i
+		}
+	}
+	to := from + delta
+	if from < 0 || to < 0 || to >= len(ids) {
+		return false, nil
+	}
+	ids[from], ids[to] = ids[to], ids[from]
+	return true, s.writeQueue(ids)
+}
+
+func (s *Store) CompactQueue() error {
+	return s.writeQueue(s.QueuedIDs())
+}
+
+func (s *Store) writeQueue(ids []int) error {
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for i, id := range ids {
+		if _, err := tx.Exec(`UPDATE articles SET queue_position = ? WHERE id = ?`, i+1, id); err != nil {
+			return err
+		}
+	}
+	return commitTx(tx)
+}
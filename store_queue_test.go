@@ -0,0 +1,80 @@
+package main
+
+import "testing"
+
+func insertQueueArticles(t *testing.T, store *Store, n int) []int {
+	t.Helper()
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles := []Article{}
+	for i := 0; i < n; i++ {
+		articles = append(articles, Article{GUID: string(rune('a' + i)), Title: string(rune('A' + i)), URL: "https://example.com/" + string(rune('a'+i))})
+	}
+	added, err := store.InsertArticles(feed, articles)
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	ids := []int{}
+	for _, article := range added {
+		ids = append(ids, article.ID)
+	}
+	return ids
+}
+
+func TestStoreQueueOrderAndCompaction(t *testing.T) {
+	store := newTestStore(t)
+	ids := insertQueueArticles(t, store, 4)
+	for _, id := range []int{ids[2], ids[0], ids[3]} {
+		if err := store.QueueArticle(id); err != nil {
+			t.Fatalf("QueueArticle error: %v", err)
+		}
+	}
+	if err := store.QueueArticle(ids[0]); err != nil {
+		t.Fatalf("expected requeue to be a no-op, got %v", err)
+	}
+	if err := store.QueueArticle(9999); err == nil {
+		t.Fatalf("expected error for missing article")
+	}
+	assertQueue(t, store, ids[2], ids[0], ids[3])
+
+	if moved, err := store.MoveQueued(ids[3], -1); err != nil || !moved {
+		t.Fatalf("MoveQueued error: %v %v", moved, err)
+	}
+	assertQueue(t, store, ids[2], ids[3], ids[0])
+	if moved, err := store.MoveQueued(ids[2], -1); err != nil || moved {
+		t.Fatalf("expected no move past the top, got %v %v", moved, err)
+	}
+
+	if err := store.DequeueArticle(ids[3]); err != nil {
+		t.Fatalf("DequeueArticle error: %v", err)
+	}
+	assertQueue(t, store, ids[2], ids[0])
+	if positions := store.QueuePositions(); positions[ids[2]] != 1 || positions[ids[0]] != 2 {
+		t.Fatalf("expected compacted positions, got %v", positions)
+	}
+
+	if _, err := store.DeleteArticle(ids[2]); err != nil {
+		t.Fatalf("DeleteArticle error: %v", err)
+	}
+	if err := store.CompactQueue(); err != nil {
+		t.Fatalf("CompactQueue error: %v", err)
+	}
+	if positions := store.QueuePositions(); len(positions) != 1 || positions[ids[0]] != 1 {
+		t.Fatalf("expected compaction after delete, got %v", positions)
+	}
+}
+
+func assertQueue(t *testing.T, store *Store, want ...int) {
+	t.Helper()
+	got := store.QueuedIDs()
+	if len(got) != len(want) {
+		t.Fatalf("expected queue %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected queue %v, got %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import "time"
+
+func (s *Store) ApplyRetention(defaults RetentionPolicy, overrides map[string]RetentionPolicy) (int, error) {
+	urls := map[int]string{}
+	for _, feed := range s.Feeds() {
+		urls[feed.ID] = feed.URL
+	}
+	rows, err := s.db.Query(`SELECT DISTINCT feed_id FROM articles`)
+	if err != nil {
+		return 0, err
+	}
+	feedIDs := []int{}
+	for rows.Next() {
+		var feedID int
+		if err := rows.Scan(&feedID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		feedIDs = append(feedIDs, feedID)
+	}
+	rows.Close()
+
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	const unprotected = `feed_id = ? AND is_starred = 0 AND id NOT IN (SELECT article_id FROM saved)`
+	removed := 0
+	for _, feedID := range feedIDs {
+		policy := defaults
+		if override, ok := overrides[urls[feedID]]; ok {
+			policy = defaults.merge(override)
+		}
+		if policy.Days > 0 {
+			cutoff := s.now().Add(-time.Duration(policy.Days) * 24 * time.Hour)
+			result, err := tx.Exec(`DELETE FROM articles WHERE `+unprotected+` AND fetched_at < ?`, feedID, timeToUnix(cutoff))
+			if err != nil {
+				return 0, err
+			}
+			count, err := rowsAffected(result)
+			if err != nil {
+				return 0, err
+			}
+			removed += int(count)
+		}
+		if policy.Items > 0 {
+			result, err := tx.Exec(`DELETE FROM articles WHERE `+unprotected+` AND id NOT IN (
+				SELECT id FROM articles WHERE feed_id = ? ORDER BY COALESCE(NULLIF(published_at, 0), fetched_at) DESC, id DESC LIMIT ?)`,
+				feedID, feedID, policy.Items)
+			if err != nil {
+				return 0, err
+			}
+			count, err := rowsAffected(result)
+			if err != nil {
+				return 0, err
+			}
+			removed += int(count)
+		}
+	}
+	if err := commitTx(tx); err != nil {
+		return 0, err
+	}
+	if removed > 0 {
+		s.CleanupOrphanSummaries()
+	}
+	return removed, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreApplyRetention(t *testing.T) {
+	store := newTestStore(t)
+	clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	store.clock = clock
+	daily, err := store.InsertFeed(Feed{Title: "Daily", URL: "https://daily.example/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	archive, err := store.InsertFeed(Feed{Title: "Archive", URL: "https://archive.example/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	old, err := store.InsertArticles(daily, []Article{{GUID: "old"}, {GUID: "old-starred"}, {GUID: "old-saved"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if err := store.SetArticlesStarred([]int{old[1].ID}, true); err != nil {
+		t.Fatalf("SetArticlesStarred error: %v", err)
+	}
+	if err := store.SaveBookmark(old[2].ID, "raindrop", 1, nil); err != nil {
+		t.Fatalf("SaveBookmark error: %v", err)
+	}
+	if _, err := store.InsertArticles(archive, []Article{{GUID: "kept-old"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	clock.now = clock.now.Add(10 * 24 * time.Hour)
+	published := clock.now.Add(-time.Hour)
+	if _, err := store.InsertArticles(daily, []Article{
+		{GUID: "n1", PublishedAt: published.Add(-3 * time.Minute)},
+		{GUID: "n2", PublishedAt: published.Add(-2 * time.Minute)},
+		{GUID: "n3", PublishedAt: published.Add(-time.Minute)},
+	}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+
+	overrides := map[string]RetentionPolicy{
+		archive.URL: {Days: 0, Items: -1},
+		daily.URL:   {Days: -1, Items: 2},
+	}
+	removed, err := store.ApplyRetention(RetentionPolicy{Days: 7}, overrides)
+	if err != nil {
+		t.Fatalf("ApplyRetention error: %v", err)
+	}
+	if removed != 2 {
+		t.Fatalf("expected old and oldest new article removed, got %d", removed)
+	}
+	kept := map[string]bool{}
+	for _, article := range store.Articles() {
+		kept[article.GUID] = true
+	}
+	for _, guid := range []string{"old-starred", "old-saved", "kept-old", "n2", "n3"} {
+		if !kept[guid] {
+			t.Fatalf("expected %s kept, got %v", guid, kept)
+		}
+	}
+	if kept["old"] || kept["n1"] || store.SavedCount() != 1 {
+		t.Fatalf("unexpected retention result %v", kept)
+	}
+
+	if removed, err := store.ApplyRetention(RetentionPolicy{}, nil); err != nil || removed != 0 {
+		t.Fatalf("expected no-op without policy, got %d %v", removed, err)
+	}
+	if removed, err := store.ApplyRetention(RetentionPolicy{Days: 1}, nil); err != nil || removed != 1 {
+		t.Fatalf("expected archive article removed by defaults, got %d %v", removed, err)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import "strconv"
+
+func (s *Store) SaveSession(session Session) error {
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	values := map[string]string{
+		"article_id": strconv.Itoa(session.ArticleID),
+		"filter":     string(session.Filter),
+		"sort":       string(session.SortMode),
+	}
+	for key, value := range values {
+		if _, err := tx.Exec(`INSERT INTO session (key, value) VALUES (?, ?)
+			ON CONFLICT(key) DO UPDATE SET value = excluded.value`, key, value); err != nil {
+			return err
+		}
+	}
+	return commitTx(tx)
+}
+
+func (s *Store) LoadSession() (Session, bool) {
+	rows, err := s.db.Query(`SELECT key, value FROM session`)
+	if err != nil {
+		return Session{}, false
+	}
+	defer rows.Close()
+	session := Session{}
+	found := false
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return Session{}, false
+		}
+		found = true
+		switch key {
+		case "article_id":
+			session.ArticleID, _ = strconv.Atoi(value)
+		case "filter":
+			session.Filter = FilterMode(value)
+		case "sort":
+			session.SortMode = SortMode(value)
+		}
+	}
+	return session, found
+}
+
+func (s *Store) Close() error {
+	s.closeStmts()
+	return s.db.Close()
+}
@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestStoreSessionRoundTrip(t *testing.T) {
+	store := newTestStore(t)
+	if _, ok := store.LoadSession(); ok {
+		t.Fatalf("expected no session")
+	}
+	if err := store.SaveSession(Session{ArticleID: 7, Filter: FilterStarred, SortMode: SortRanked}); err != nil {
+		t.Fatalf("SaveSession error: %v", err)
+	}
+	if err := store.SaveSession(Session{ArticleID: 9, Filter: FilterAll, SortMode: SortRanked}); err != nil {
+		t.Fatalf("SaveSession update error: %v", err)
+	}
+	session, ok := store.LoadSession()
+	if !ok || session != (Session{ArticleID: 9, Filter: FilterAll, SortMode: SortRanked}) {
+		t.Fatalf("unexpected session %+v %v", session, ok)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if _, ok := store.LoadSession(); ok {
+		t.Fatalf("expected closed store to report no session")
+	}
+	if err := store.SaveSession(Session{}); err == nil {
+		t.Fatalf("expected error on closed store")
+	}
+}
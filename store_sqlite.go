@@ -0,0 +1,65 @@
+package main
+
+import (
+	"database/sql"
+	"strconv"
+)
+
+const sqliteBusyTimeoutMS = 5000
+
+const (
+	insertArticleSQL  = `INSERT INTO articles (feed_id, guid, title, url, base_url, author, content, content_text, published_at, fetched_at, is_read, is_starred, feed_title) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	refreshArticleSQL = `UPDATE articles SET title = ?, content = ?, content_text = ?, fingerprint = NULL WHERE id = ?`
+	updateArticleSQL  = `UPDATE articles SET feed_id = ?, guid = ?, title = ?, url = ?, base_url = ?, author = ?, content = ?, content_text = ?, published_at = ?, fetched_at = ?, is_read = ?, is_starred = ?, feed_title = ?, fingerprint = NULL WHERE id = ?`
+	findSummarySQL    = `SELECT id, article_id, content, model, generated_at, COALESCE(prompt_tokens, 0), COALESCE(completion_tokens, 0), COALESCE(content_hash, '') FROM summaries WHERE article_id = ?`
+)
+
+func sqliteDSN(path string) string {
+	return path + "?_pragma=busy_timeout(" + strconv.Itoa(sqliteBusyTimeoutMS) + ")&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_txlock=immediate"
+}
+
+func (s *Store) stmt(query string) (*sql.Stmt, error) {
+	s.stmtMu.Lock()
+	defer s.stmtMu.Unlock()
+	if stmt, ok := s.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	if s.stmts == nil {
+		s.stmts = map[string]*sql.Stmt{}
+	}
+	s.stmts[query] = stmt
+	return stmt, nil
+}
+
+func (s *Store) prepareStatements() error {
+	for _, query := range []string{insertArticleSQL, refreshArticleSQL, updateArticleSQL, findSummarySQL} {
+		if _, err := s.stmt(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) txStmt(tx *sql.Tx, query string) (*sql.Stmt, error) {
+	s.stmtMu.Lock()
+	stmt, ok := s.stmts[query]
+	s.stmtMu.Unlock()
+	if !ok {
+		// Preparing on the pool here would need a second connection while tx holds one.
+		return tx.Prepare(query)
+	}
+	return tx.Stmt(stmt), nil
+}
+
+func (s *Store) closeStmts() {
+	s.stmtMu.Lock()
+	defer s.stmtMu.Unlock()
+	for query, stmt := range s.stmts {
+		_ = stmt.Close()
+		delete(s.stmts, query)
+	}
+}
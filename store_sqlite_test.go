@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestNewStoreConfiguresSQLite(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+	var mode string
+	if err := store.db.QueryRow(`PRAGMA journal_mode`).Scan(&mode); err != nil || mode != "wal" {
+		t.Fatalf("expected wal journal, got %q %v", mode, err)
+	}
+	ctx := context.Background()
+	first, err := store.db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn error: %v", err)
+	}
+	defer first.Close()
+	second, err := store.db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn error: %v", err)
+	}
+	defer second.Close()
+	for _, conn := range []*sql.Conn{first, second} {
+		var timeout int
+		if err := conn.QueryRowContext(ctx, `PRAGMA busy_timeout`).Scan(&timeout); err != nil || timeout != sqliteBusyTimeoutMS {
+			t.Fatalf("expected busy timeout on every connection, got %d %v", timeout, err)
+		}
+	}
+}
+
+func TestStoreTransactionsTakeWriteLock(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+	tx, err := beginTx(store.db)
+	if err != nil {
+		t.Fatalf("beginTx error: %v", err)
+	}
+	defer tx.Rollback()
+	other, err := sql.Open("sqlite", store.path)
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+	defer other.Close()
+	if _, err := other.Exec(`BEGIN IMMEDIATE`); err == nil {
+		t.Fatalf("expected the open transaction to hold the write lock")
+	}
+}
+
+func TestStoreReusesPreparedStatements(t *testing.T) {
+	store := newTestStore(t)
+	if len(store.stmts) != 4 {
+		t.Fatalf("expected hot statements prepared up front, got %d", len(store.stmts))
+	}
+	first, err := store.stmt(updateArticleSQL)
+	if err != nil {
+		t.Fatalf("stmt error: %v", err)
+	}
+	second, _ := store.stmt(updateArticleSQL)
+	if first != second {
+		t.Fatalf("expected cached statement")
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if len(store.stmts) != 0 {
+		t.Fatalf("expected statements closed, got %d", len(store.stmts))
+	}
+	if _, ok := store.FindSummary(1); ok {
+		t.Fatalf("expected closed store to miss")
+	}
+}
+
+func TestStoreConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.db")
+	store, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	defer store.Close()
+	other, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	defer other.Close()
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for worker, s := range []*Store{store, other} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				guid := fmt.Sprintf("w%d-%d", worker, i)
+				added, err := s.InsertArticles(feed, []Article{{GUID: guid, Title: guid, URL: "https://example.com/" + guid}})
+				if err == nil && len(added) == 1 {
+					added[0].IsRead = true
+					err = s.UpdateArticle(added[0])
+				}
+				if err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("concurrent write error: %v", err)
+	}
+	if articles := store.SortedArticles(); len(articles) != 40 {
+		t.Fatalf("expected 40 articles, got %d", len(articles))
+	}
+}
+
+func TestBeginTxTakesWriteLock(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+	tx, err := beginTx(store.db)
+	if err != nil {
+		t.Fatalf("beginTx error: %v", err)
+	}
+	defer tx.Rollback()
+	ctx := context.Background()
+	other, err := store.db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn error: %v", err)
+	}
+	defer other.Close()
+	if _, err := other.ExecContext(ctx, `PRAGMA busy_timeout = 10`); err != nil {
+		t.Fatalf("busy_timeout error: %v", err)
+	}
+	if _, err := other.ExecContext(ctx, `INSERT INTO feeds (title, url) VALUES ('Feed', 'https://example.com/lock')`); err == nil {
+		t.Fatalf("expected the open transaction to hold the write lock")
+	}
+}
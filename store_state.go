@@ -18,13 +18,14 @@ var (
 )
 
 type ExportState struct {
-	Version    int       `json:"version"`
-	ExportedAt time.Time `json:"exported_at"`
-	Feeds      []Feed    `json:"feeds"`
-	Articles   []Article `json:"articles"`
-	Summaries  []Summary `json:"summaries"`
-	Saved      []Saved   `json:"saved"`
-	Deleted    []Deleted `json:"deleted"`
+	Version    int          `json:"version"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Feeds      []Feed       `json:"feeds"`
+	Articles   []Article    `json:"articles"`
+	Summaries  []Summary    `json:"summaries"`
+	Saved      []Saved      `json:"saved"`
+	Deleted    []Deleted    `json:"deleted"`
+	Tags       []ArticleTag `json:"tags,omitempty"`
 }
 
 func (s *Store) ExportState(path string) error {
@@ -33,12 +34,13 @@ func (s *Store) ExportState(path string) error {
 	}
 	state := ExportState{
 		Version:    exportStateVersion,
-		ExportedAt: time.Now().UTC(),
+		ExportedAt: s.now().UTC(),
 		Feeds:      s.Feeds(),
 		Articles:   s.Articles(),
 		Summaries:  s.Summaries(),
 		Saved:      s.Saved(),
 		Deleted:    s.Deleted(),
+		Tags:       s.AllArticleTags(),
 	}
 	payload, err := stateMarshalIndent(state, "", "  ")
 	if err != nil {
@@ -76,6 +78,9 @@ func (s *Store) ImportState(path string) error {
 	if _, err := tx.Exec(`DELETE FROM deleted`); err != nil {
 		return err
 	}
+	if _, err := tx.Exec(`DELETE FROM article_tags`); err != nil {
+		return err
+	}
 	if _, err := tx.Exec(`DELETE FROM articles`); err != nil {
 		return err
 	}
@@ -106,8 +111,8 @@ func (s *Store) ImportState(path string) error {
 		}
 	}
 	for _, summary := range state.Summaries {
-		if _, err := tx.Exec(`INSERT INTO summaries (id, article_id, content, model, generated_at) VALUES (?, ?, ?, ?, ?)`,
-			summary.ID, summary.ArticleID, summary.Content, summary.Model, timeToUnix(summary.GeneratedAt)); err != nil {
+		if _, err := tx.Exec(`INSERT INTO summaries (id, article_id, content, model, generated_at, prompt_tokens, completion_tokens, content_hash) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			summary.ID, summary.ArticleID, summary.Content, summary.Model, timeToUnix(summary.GeneratedAt), summary.PromptTokens, summary.CompletionTokens, summary.ContentHash); err != nil {
 			return err
 		}
 	}
@@ -116,8 +121,8 @@ func (s *Store) ImportState(path string) error {
 		if err != nil {
 			return err
 		}
-		if _, err := tx.Exec(`INSERT INTO saved (article_id, raindrop_id, tags, saved_at) VALUES (?, ?, ?, ?)`,
-			saved.ArticleID, saved.RaindropID, string(blob), timeToUnix(saved.SavedAt)); err != nil {
+		if _, err := tx.Exec(`INSERT INTO saved (article_id, provider, raindrop_id, tags, saved_at) VALUES (?, ?, ?, ?, ?)`,
+			saved.ArticleID, saved.Provider, saved.RaindropID, string(blob), timeToUnix(saved.SavedAt)); err != nil {
 			return err
 		}
 	}
@@ -135,6 +140,11 @@ func (s *Store) ImportState(path string) error {
 			return err
 		}
 	}
+	for _, tag := range state.Tags {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO article_tags (article_id, tag, source) VALUES (?, ?, ?)`, tag.ArticleID, tag.Tag, firstNonEmpty(tag.Source, tagSourceUser)); err != nil {
+			return err
+		}
+	}
 	if err := commitTx(tx); err != nil {
 		return err
 	}
@@ -27,8 +27,11 @@ func TestStoreExportImportState(t *testing.T) {
 	if _, err := store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "Summary", Model: "m"}); err != nil {
 		t.Fatalf("UpsertSummary error: %v", err)
 	}
-	if err := store.SaveToRaindrop(articles[0].ID, 42, []string{"tag"}); err != nil {
-		t.Fatalf("SaveToRaindrop error: %v", err)
+	if err := store.SaveBookmark(articles[0].ID, "raindrop", 42, []string{"tag"}); err != nil {
+		t.Fatalf("SaveBookmark error: %v", err)
+	}
+	if err := store.SetArticleTags(articles[0].ID, tagSourceUser, []string{"later"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
 	}
 	if _, err := store.DeleteArticle(articles[1].ID); err != nil {
 		t.Fatalf("DeleteArticle error: %v", err)
@@ -52,12 +55,15 @@ func TestStoreExportImportState(t *testing.T) {
 	if len(other.Summaries()) != 1 {
 		t.Fatalf("expected summaries imported")
 	}
-	if len(other.Saved()) != 1 {
+	if saved := other.Saved(); len(saved) != 1 || saved[0].Provider != "raindrop" {
 		t.Fatalf("expected saved imported")
 	}
 	if len(other.Deleted()) != 1 {
 		t.Fatalf("expected deleted imported")
 	}
+	if tags := other.ArticleTagsFrom(articles[0].ID, tagSourceUser); len(tags) != 1 || tags[0] != "later" {
+		t.Fatalf("expected tags imported, got %v", tags)
+	}
 }
 
 func TestStoreImportStateErrors(t *testing.T) {
@@ -147,6 +153,7 @@ func TestStoreImportStateDeleteErrors(t *testing.T) {
 		{"summaries", "summaries"},
 		{"saved", "saved"},
 		{"deleted", "deleted"},
+		{"tags", "article_tags"},
 		{"feeds", "feeds"},
 	}
 	for _, testCase := range cases {
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+func (s *Store) SyncedArticleID(feedID int, guid string, url string) int {
+	var id int
+	err := s.db.QueryRow(`SELECT id FROM articles WHERE feed_id = ? AND guid = ?`, feedID, guid).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) && url != "" {
+		err = s.db.QueryRow(`SELECT id FROM articles WHERE base_url = ? ORDER BY id LIMIT 1`, baseURL(url)).Scan(&id)
+	}
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func (s *Store) SetArticleState(articleID int, read bool, starred bool) error {
+	_, err := s.db.Exec(`UPDATE articles SET is_read = ?, is_starred = ? WHERE id = ?`, boolToInt(read), boolToInt(starred), articleID)
+	return err
+}
+
+func (s *Store) SetArticleRead(articleID int, read bool) error {
+	_, err := s.db.Exec(`UPDATE articles SET is_read = ? WHERE id = ?`, boolToInt(read), articleID)
+	return err
+}
+
+func (s *Store) SetArticleStarred(articleID int, starred bool) error {
+	_, err := s.db.Exec(`UPDATE articles SET is_starred = ? WHERE id = ?`, boolToInt(starred), articleID)
+	return err
+}
+
+func (s *Store) MarkFeedsRead(feedIDs []int, before time.Time) error {
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, feedID := range feedIDs {
+		if _, err := tx.Exec(`UPDATE articles SET is_read = 1 WHERE feed_id = ? AND COALESCE(NULLIF(published_at, 0), fetched_at) <= ?`, feedID, before.Unix()); err != nil {
+			return err
+		}
+	}
+	return commitTx(tx)
+}
+
+func (s *Store) SetSyncState(articleID int, backend string, remoteID string, read bool, starred bool) error {
+	_, err := s.db.Exec(`INSERT INTO sync_items (article_id, backend, remote_id, is_read, is_starred) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET backend = excluded.backend, remote_id = excluded.remote_id, is_read = excluded.is_read, is_starred = excluded.is_starred`,
+		articleID, backend, remoteID, boolToInt(read), boolToInt(starred))
+	return err
+}
+
+func (s *Store) PendingSyncChanges(backend string) ([]SyncChange, error) {
+	rows, err := s.db.Query(`SELECT sync_items.article_id, sync_items.remote_id, articles.is_read, articles.is_starred, sync_items.is_read, sync_items.is_starred
+		FROM sync_items JOIN articles ON articles.id = sync_items.article_id
+		WHERE sync_items.backend = ? AND (articles.is_read != sync_items.is_read OR articles.is_starred != sync_items.is_starred)
+		ORDER BY sync_items.article_id`, backend)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	changes := []SyncChange{}
+	for rows.Next() {
+		var change SyncChange
+		var read, starred, syncedRead, syncedStarred int
+		if err := rows.Scan(&change.ArticleID, &change.RemoteID, &read, &starred, &syncedRead, &syncedStarred); err != nil {
+			return nil, err
+		}
+		change.Read = read == 1
+		change.Starred = starred == 1
+		change.ReadChanged = read != syncedRead
+		change.StarredChanged = starred != syncedStarred
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
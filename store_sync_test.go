@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreSyncState(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	added, err := store.InsertArticles(feed, []Article{{GUID: "g1", Title: "A", URL: "https://example.com/a?utm_source=x"}, {GUID: "g2", Title: "B", URL: "https://example.com/b"}})
+	if err != nil || len(added) != 2 {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	first, second := added[0].ID, added[1].ID
+	if store.SyncedArticleID(feed.ID, "g1", "") != first {
+		t.Fatalf("expected lookup by guid")
+	}
+	if store.SyncedArticleID(feed.ID+1, "other", "https://example.com/b") != second {
+		t.Fatalf("expected lookup by base url")
+	}
+	if store.SyncedArticleID(feed.ID, "missing", "") != 0 {
+		t.Fatalf("expected no match")
+	}
+
+	if err := store.SetSyncState(first, "Nextcloud News", "41", false, false); err != nil {
+		t.Fatalf("SetSyncState error: %v", err)
+	}
+	if err := store.SetSyncState(second, "Nextcloud News", "42", true, false); err != nil {
+		t.Fatalf("SetSyncState error: %v", err)
+	}
+	if changes, err := store.PendingSyncChanges("Nextcloud News"); err != nil || len(changes) != 1 || changes[0].RemoteID != "42" || !changes[0].ReadChanged || changes[0].Read {
+		t.Fatalf("unexpected initial changes %+v %v", changes, err)
+	}
+	if err := store.SetArticleState(first, true, true); err != nil {
+		t.Fatalf("SetArticleState error: %v", err)
+	}
+	if err := store.SetSyncState(second, "Nextcloud News", "42", false, false); err != nil {
+		t.Fatalf("SetSyncState error: %v", err)
+	}
+	changes, err := store.PendingSyncChanges("Nextcloud News")
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("unexpected changes %+v %v", changes, err)
+	}
+	if change := changes[0]; change.ArticleID != first || !change.Read || !change.Starred || !change.ReadChanged || !change.StarredChanged {
+		t.Fatalf("unexpected change %+v", change)
+	}
+	if changes, _ := store.PendingSyncChanges("Miniflux"); len(changes) != 0 {
+		t.Fatalf("expected changes scoped to backend")
+	}
+
+	if _, err := store.DeleteArticle(first); err != nil {
+		t.Fatalf("DeleteArticle error: %v", err)
+	}
+	store.CleanupOrphanSummaries()
+	var count int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM sync_items`).Scan(&count); err != nil || count != 1 {
+		t.Fatalf("expected orphan sync state removed, got %d %v", count, err)
+	}
+}
+
+func TestStoreMarkFeedsRead(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	added, err := store.InsertArticles(feed, []Article{{GUID: "old", Title: "Old", PublishedAt: old}, {GUID: "new", Title: "New", PublishedAt: old.Add(48 * time.Hour)}})
+	if err != nil || len(added) != 2 {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if err := store.MarkFeedsRead([]int{feed.ID}, old.Add(time.Hour)); err != nil {
+		t.Fatalf("MarkFeedsRead error: %v", err)
+	}
+	if err := store.SetArticleStarred(added[1].ID, true); err != nil {
+		t.Fatalf("SetArticleStarred error: %v", err)
+	}
+	articles := store.Articles()
+	if !articles[0].IsRead || articles[1].IsRead || !articles[1].IsStarred {
+		t.Fatalf("unexpected state %+v", articles)
+	}
+	if err := store.SetArticleRead(added[0].ID, false); err != nil {
+		t.Fatalf("SetArticleRead error: %v", err)
+	}
+	if store.Articles()[0].IsRead {
+		t.Fatalf("expected article unread")
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	tagSourceTopic = "topic"
+	tagSourceUser  = "user"
+)
+
+func (s *Store) SetArticleTags(articleID int, source string, tags []string) error {
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM article_tags WHERE article_id = ? AND source = ?`, articleID, source); err != nil {
+		return err
+	}
+	insert := `INSERT OR IGNORE INTO article_tags (article_id, tag, source) VALUES (?, ?, ?)`
+	if source == tagSourceUser {
+		insert = `INSERT INTO article_tags (article_id, tag, source) VALUES (?, ?, ?)
+			ON CONFLICT(article_id, tag) DO UPDATE SET source = excluded.source`
+	}
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" {
+			continue
+		}
+		if _, err := tx.Exec(insert, articleID, tag, source); err != nil {
+			return err
+		}
+	}
+	return commitTx(tx)
+}
+
+func (s *Store) ArticleTags(articleID int) []string {
+	rows, err := s.db.Query(`SELECT tag FROM article_tags WHERE article_id = ? ORDER BY tag`, articleID)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return tags
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (s *Store) ArticleTagsFrom(articleID int, source string) []string {
+	rows, err := s.db.Query(`SELECT tag FROM article_tags WHERE article_id = ? AND source = ? ORDER BY tag`, articleID, source)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return tags
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (s *Store) AllArticleTags() []ArticleTag {
+	rows, err := s.db.Query(`SELECT article_id, tag, source FROM article_tags ORDER BY article_id, source, tag`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	tags := []ArticleTag{}
+	for rows.Next() {
+		var tag ArticleTag
+		if err := rows.Scan(&tag.ArticleID, &tag.Tag, &tag.Source); err != nil {
+			return tags
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (s *Store) ArticleIDsWithTag(tag string) map[int]bool {
+	ids := map[int]bool{}
+	rows, err := s.db.Query(`SELECT article_id FROM article_tags WHERE tag = ?`, normalizeTag(tag))
+	if err != nil {
+		return ids
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return ids
+		}
+		ids[id] = true
+	}
+	return ids
+}
+
+func (s *Store) TagCounts() map[string]int {
+	counts := map[string]int{}
+	rows, err := s.db.Query(`SELECT tag, COUNT(*) FROM article_tags GROUP BY tag`)
+	if err != nil {
+		return counts
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tag string
+		var count int
+		if err := rows.Scan(&tag, &count); err != nil {
+			return counts
+		}
+		counts[tag] = count
+	}
+	return counts
+}
+
+func normalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	tag = strings.Trim(tag, "#-*.\"'")
+	return strings.Join(strings.Fields(tag), " ")
+}
+
+func sortedTags(counts map[string]int) []string {
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	return tags
+}
@@ -0,0 +1,95 @@
+package main
+
+import "testing"
+
+func TestStoreArticleTags(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := store.InsertArticles(feed, []Article{{GUID: "1", Title: "One", URL: "https://example.com/1"}, {GUID: "2", Title: "Two", URL: "https://example.com/2"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if err := store.SetArticleTags(articles[0].ID, tagSourceTopic, []string{" Go ", "#Databases", "go", ""}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	if err := store.SetArticleTags(articles[1].ID, tagSourceTopic, []string{"go"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	tags := store.ArticleTags(articles[0].ID)
+	if len(tags) != 2 || tags[0] != "databases" || tags[1] != "go" {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+	if ids := store.ArticleIDsWithTag("Go"); len(ids) != 2 || !ids[articles[1].ID] {
+		t.Fatalf("unexpected tagged ids: %v", ids)
+	}
+	counts := store.TagCounts()
+	if counts["go"] != 2 || counts["databases"] != 1 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+	if got := sortedTags(counts); len(got) != 2 || got[0] != "go" {
+		t.Fatalf("unexpected tag order: %v", got)
+	}
+
+	if err := store.SetArticleTags(articles[0].ID, tagSourceTopic, []string{"sqlite"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	if tags := store.ArticleTags(articles[0].ID); len(tags) != 1 || tags[0] != "sqlite" {
+		t.Fatalf("expected topics replaced, got %v", tags)
+	}
+
+	if _, err := store.DeleteArticle(articles[0].ID); err != nil {
+		t.Fatalf("DeleteArticle error: %v", err)
+	}
+	store.CleanupOrphanSummaries()
+	if counts := store.TagCounts(); counts["sqlite"] != 0 {
+		t.Fatalf("expected orphan tags removed, got %v", counts)
+	}
+}
+
+func TestStoreArticleTagSources(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := store.InsertArticles(feed, []Article{{GUID: "1", Title: "One"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if err := store.SetArticleTags(articles[0].ID, tagSourceTopic, []string{"go"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	if err := store.SetArticleTags(articles[0].ID, tagSourceUser, []string{"Later", "go"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	if tags := store.ArticleTagsFrom(articles[0].ID, tagSourceUser); len(tags) != 2 || tags[0] != "go" || tags[1] != "later" {
+		t.Fatalf("unexpected user tags: %v", tags)
+	}
+	if tags := store.ArticleTagsFrom(articles[0].ID, tagSourceTopic); len(tags) != 0 {
+		t.Fatalf("expected user tag to claim the topic, got %v", tags)
+	}
+	if err := store.SetArticleTags(articles[0].ID, tagSourceTopic, []string{"go", "rss"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	all := store.AllArticleTags()
+	if len(all) != 3 || all[0] != (ArticleTag{ArticleID: articles[0].ID, Tag: "rss", Source: tagSourceTopic}) || all[1].Source != tagSourceUser {
+		t.Fatalf("unexpected tag rows: %+v", all)
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	cases := map[string]string{
+		"  Machine   Learning ": "machine learning",
+		"#rust":                 "rust",
+		"- privacy.":            "privacy",
+		"":                      "",
+	}
+	for input, want := range cases {
+		if got := normalizeTag(input); got != want {
+			t.Fatalf("normalizeTag(%q) = %q", input, got)
+		}
+	}
+}
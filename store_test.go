@@ -60,15 +60,21 @@ func TestStoreCRUD(t *testing.T) {
 		t.Fatalf("expected undelete error")
 	}
 
-	if err := store.SaveToRaindrop(article.ID, 10, []string{"tag"}); err != nil {
-		t.Fatalf("SaveToRaindrop error: %v", err)
+	if err := store.SaveBookmark(article.ID, "raindrop", 10, []string{"tag"}); err != nil {
+		t.Fatalf("SaveBookmark error: %v", err)
 	}
-	if err := store.SaveToRaindrop(article.ID, 11, []string{"tag2"}); err != nil {
-		t.Fatalf("SaveToRaindrop update error: %v", err)
+	if saved := store.Saved(); len(saved) != 1 || saved[0].Provider != "raindrop" || saved[0].RaindropID != 10 {
+		t.Fatalf("unexpected saved %+v", saved)
+	}
+	if err := store.SaveBookmark(article.ID, "pocket", 11, []string{"tag2"}); err != nil {
+		t.Fatalf("SaveBookmark update error: %v", err)
 	}
 	if store.SavedCount() != 1 {
 		t.Fatalf("expected saved count 1")
 	}
+	if saved := store.Saved(); saved[0].Provider != "pocket" || saved[0].RaindropID != 11 {
+		t.Fatalf("expected provider updated, got %+v", saved)
+	}
 
 	oldArticle := Article{GUID: "old", Title: "Old", URL: "https://example.com/old", FetchedAt: time.Now().Add(-10 * 24 * time.Hour)}
 	if _, err := store.InsertArticles(feed, []Article{oldArticle}); err != nil {
@@ -247,3 +253,124 @@ func TestStoreFileDirMismatch(t *testing.T) {
 		t.Fatalf("expected directory error")
 	}
 }
+
+func TestStoreSummaryUsage(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "store.db"))
+	if err != nil {
+		t.Fatalf("NewStore error: %v", err)
+	}
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "http://example.test/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	added, err := store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "One", URL: "http://example.test/1"},
+		{GUID: "2", Title: "Two", URL: "http://example.test/2"},
+		{GUID: "3", Title: "Three", URL: "http://example.test/3"},
+	})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	monday := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	summaries := []Summary{
+		{ArticleID: added[0].ID, Content: "a", GeneratedAt: monday.AddDate(0, 0, -3), PromptTokens: 100, CompletionTokens: 10},
+		{ArticleID: added[1].ID, Content: "b", GeneratedAt: monday, PromptTokens: 200, CompletionTokens: 20},
+		{ArticleID: added[2].ID, Content: "c", GeneratedAt: monday.AddDate(0, 0, 2), PromptTokens: 300, CompletionTokens: 30},
+	}
+	for _, summary := range summaries {
+		if _, err := store.UpsertSummary(summary); err != nil {
+			t.Fatalf("UpsertSummary error: %v", err)
+		}
+	}
+	if found, ok := store.FindSummary(added[1].ID); !ok || found.PromptTokens != 200 || found.CompletionTokens != 20 {
+		t.Fatalf("expected stored token counts, got %+v", found)
+	}
+	if usage := store.SummaryUsageByWeek(monday.AddDate(0, 0, -7)); len(usage) != 0 {
+		t.Fatalf("expected usage to come from the usage log, got %+v", usage)
+	}
+	for _, summary := range summaries {
+		record := UsageRecord{RecordedAt: summary.GeneratedAt, Model: "m", PromptTokens: summary.PromptTokens, CompletionTokens: summary.CompletionTokens}
+		if err := store.RecordUsage(record); err != nil {
+			t.Fatalf("RecordUsage error: %v", err)
+		}
+	}
+	if _, err := store.UpsertSummary(Summary{ArticleID: added[1].ID, Content: "b2", GeneratedAt: monday, PromptTokens: 1, CompletionTokens: 1}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	if _, err := store.DeleteArticle(added[2].ID); err != nil {
+		t.Fatalf("DeleteArticle error: %v", err)
+	}
+	usage := store.SummaryUsageByWeek(monday.AddDate(0, 0, -7))
+	if len(usage) != 2 {
+		t.Fatalf("expected two weeks, got %+v", usage)
+	}
+	if usage[0].Requests != 1 || usage[0].PromptTokens != 100 {
+		t.Fatalf("unexpected first week: %+v", usage[0])
+	}
+	if !usage[1].WeekStart.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)) || usage[1].Requests != 2 || usage[1].CompletionTokens != 50 {
+		t.Fatalf("expected regenerated and deleted summaries to keep their usage, got %+v", usage[1])
+	}
+	if got := store.SummaryUsageByWeek(monday.AddDate(0, 0, 7)); len(got) != 0 {
+		t.Fatalf("expected no usage after cutoff")
+	}
+}
+
+func TestStoreUsageLogBackfill(t *testing.T) {
+	store, _ := newWritableStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "http://example.test/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	added, err := store.InsertArticles(feed, []Article{{GUID: "1", Title: "One", URL: "http://example.test/1"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	monday := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	if _, err := store.UpsertSummary(Summary{ArticleID: added[0].ID, Content: "a", Model: "m", GeneratedAt: monday, PromptTokens: 100, CompletionTokens: 10}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	if _, err := store.db.Exec(`DROP TABLE usage_log`); err != nil {
+		t.Fatalf("drop usage_log: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := initSchema(store.db); err != nil {
+			t.Fatalf("initSchema error: %v", err)
+		}
+	}
+	usage := store.SummaryUsageByWeek(monday.AddDate(0, 0, -7))
+	if len(usage) != 1 || usage[0].Requests != 1 || usage[0].PromptTokens != 100 || usage[0].CompletionTokens != 10 {
+		t.Fatalf("expected existing summaries to seed the usage log once, got %+v", usage)
+	}
+}
+
+func TestWeekStartSunday(t *testing.T) {
+	sunday := time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+	if got := weekStart(sunday); !got.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected week start: %v", got)
+	}
+}
+
+func TestStoreInsertArticlesUpdatesChangedContent(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Test", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := store.InsertArticles(feed, []Article{{GUID: "g1", Title: "A", URL: "u1", ContentText: "first"}})
+	if err != nil || len(articles) != 1 {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	inserted, err := store.InsertArticles(feed, []Article{{GUID: "g1", Title: "A (updated)", URL: "u1", ContentText: "second"}})
+	if err != nil || len(inserted) != 0 {
+		t.Fatalf("expected no new articles, got %d (%v)", len(inserted), err)
+	}
+	if updated := store.Articles(); len(updated) != 1 || updated[0].Title != "A (updated)" || updated[0].ContentText != "second" {
+		t.Fatalf("expected updated article, got %+v", updated)
+	}
+	if _, err := store.InsertArticles(feed, []Article{{GUID: "g1", Title: "A (updated)", URL: "u1"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if unchanged := store.Articles(); unchanged[0].ContentText != "second" {
+		t.Fatalf("expected empty content to be ignored, got %q", unchanged[0].ContentText)
+	}
+}
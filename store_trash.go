@@ -0,0 +1,33 @@
+package main
+
+func (s *Store) PurgeDeleted(ids []int) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	tx, err := beginTx(s.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(`DELETE FROM deleted WHERE id = ?`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	purged := 0
+	for _, id := range ids {
+		result, err := stmt.Exec(id)
+		if err != nil {
+			return 0, err
+		}
+		count, err := rowsAffected(result)
+		if err != nil {
+			return 0, err
+		}
+		purged += int(count)
+	}
+	if err := commitTx(tx); err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestStoreRestoreAndPurgeDeleted(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	added, err := store.InsertArticles(feed, []Article{{GUID: "a", Title: "A", URL: "https://example.com/a"}, {GUID: "b", Title: "B", URL: "https://example.com/b"}, {GUID: "c", Title: "C", URL: "https://example.com/c"}})
+	if err != nil || len(added) != 3 {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	for _, article := range added {
+		if _, err := store.DeleteArticle(article.ID); err != nil {
+			t.Fatalf("DeleteArticle error: %v", err)
+		}
+	}
+	deleted := store.Deleted()
+	if len(deleted) != 3 || deleted[0].ID == 0 || deleted[0].GUID != "a" {
+		t.Fatalf("unexpected deleted rows %+v", deleted)
+	}
+	restored, err := store.RestoreDeleted(deleted[0].ID)
+	if err != nil || restored.GUID != "a" || restored.ID == 0 {
+		t.Fatalf("RestoreDeleted error: %v %+v", err, restored)
+	}
+	if _, err := store.RestoreDeleted(deleted[0].ID); err == nil {
+		t.Fatalf("expected missing deleted error")
+	}
+	purged, err := store.PurgeDeleted([]int{deleted[1].ID, deleted[0].ID})
+	if err != nil || purged != 1 {
+		t.Fatalf("expected one purge, got %d %v", purged, err)
+	}
+	if left := store.Deleted(); len(left) != 1 || left[0].GUID != "c" {
+		t.Fatalf("unexpected deleted rows %+v", left)
+	}
+	if articles := store.Articles(); len(articles) != 1 || articles[0].GUID != "a" {
+		t.Fatalf("expected restored article, got %+v", articles)
+	}
+	if purged, err := store.PurgeDeleted(nil); err != nil || purged != 0 {
+		t.Fatalf("expected empty purge, got %d %v", purged, err)
+	}
+
+	origBegin := beginTx
+	beginTx = func(*sql.DB) (*sql.Tx, error) { return nil, errors.New("begin fail") }
+	t.Cleanup(func() { beginTx = origBegin })
+	if _, err := store.PurgeDeleted([]int{store.Deleted()[0].ID}); err == nil {
+		t.Fatalf("expected begin error")
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import "database/sql"
+
+func archiveSummary(tx *sql.Tx, articleID int) error {
+	_, err := tx.Exec(`INSERT INTO summary_versions (article_id, content, model, generated_at, prompt_tokens, completion_tokens, content_hash)
+		SELECT article_id, content, model, generated_at, prompt_tokens, completion_tokens, content_hash FROM summaries WHERE article_id = ?`, articleID)
+	return err
+}
+
+func (s *Store) SummaryVersions(articleID int) []Summary {
+	versions := []Summary{}
+	if current, ok := s.FindSummary(articleID); ok {
+		versions = append(versions, current)
+	}
+	rows, err := s.db.Query(`SELECT id, article_id, content, model, generated_at, COALESCE(prompt_tokens, 0), COALESCE(completion_tokens, 0), COALESCE(content_hash, '') FROM summary_versions WHERE article_id = ? ORDER BY generated_at DESC, id DESC`, articleID)
+	if err != nil {
+		return versions
+	}
+	defer rows.Close()
+	for rows.Next() {
+		summary, err := scanSummary(rows)
+		if err != nil {
+			return versions
+		}
+		versions = append(versions, summary)
+	}
+	return versions
+}
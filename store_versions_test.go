@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreSummaryVersions(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := store.InsertArticles(feed, []Article{{GUID: "1", Title: "One", URL: "https://example.com/1"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if versions := store.SummaryVersions(articles[0].ID); len(versions) != 0 {
+		t.Fatalf("expected no versions, got %v", versions)
+	}
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, content := range []string{"- first", "- second", "- third"} {
+		if _, err := store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: content, Model: "m", GeneratedAt: base.Add(time.Duration(i) * time.Hour), PromptTokens: i}); err != nil {
+			t.Fatalf("UpsertSummary error: %v", err)
+		}
+	}
+	versions := store.SummaryVersions(articles[0].ID)
+	if len(versions) != 3 || versions[0].Content != "- third" || versions[1].Content != "- second" || versions[2].Content != "- first" {
+		t.Fatalf("unexpected versions: %+v", versions)
+	}
+	if versions[2].PromptTokens != 0 || versions[1].PromptTokens != 1 || !versions[2].GeneratedAt.Equal(base) {
+		t.Fatalf("expected archived metadata kept, got %+v", versions)
+	}
+
+	if _, err := store.DeleteArticle(articles[0].ID); err != nil {
+		t.Fatalf("DeleteArticle error: %v", err)
+	}
+	if versions := store.SummaryVersions(articles[0].ID); len(versions) != 0 {
+		t.Fatalf("expected versions removed with article, got %v", versions)
+	}
+}
+
+func TestStoreUpsertSummaryArchivesAtomically(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := store.InsertArticles(feed, []Article{{GUID: "1", Title: "One", URL: "https://example.com/1"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "- first"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	if _, err := store.db.Exec(`CREATE TRIGGER fail_summary_update BEFORE UPDATE ON summaries BEGIN SELECT RAISE(ABORT, 'update failed'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	if _, err := store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "- second"}); err == nil {
+		t.Fatalf("expected update failure")
+	}
+	if versions := store.SummaryVersions(articles[0].ID); len(versions) != 1 || versions[0].Content != "- first" {
+		t.Fatalf("expected the archive to roll back with the failed update, got %+v", versions)
+	}
+}
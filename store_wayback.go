@@ -0,0 +1,16 @@
+package main
+
+func (s *Store) SetWaybackSnapshot(articleID int, snapshotURL string) error {
+	_, err := s.db.Exec(`INSERT INTO wayback_snapshots (article_id, snapshot_url, archived_at) VALUES (?, ?, ?)
+		ON CONFLICT(article_id) DO UPDATE SET snapshot_url = excluded.snapshot_url, archived_at = excluded.archived_at`,
+		articleID, snapshotURL, timeToUnix(s.now().UTC()))
+	return err
+}
+
+func (s *Store) WaybackSnapshot(articleID int) string {
+	var snapshotURL string
+	if err := s.db.QueryRow(`SELECT snapshot_url FROM wayback_snapshots WHERE article_id = ?`, articleID).Scan(&snapshotURL); err != nil {
+		return ""
+	}
+	return snapshotURL
+}
@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestStoreWaybackSnapshot(t *testing.T) {
+	store := newTestStore(t)
+	feed, err := store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := store.InsertArticles(feed, []Article{{GUID: "1", Title: "A", URL: "https://example.com/a"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	id := articles[0].ID
+	if store.WaybackSnapshot(id) != "" {
+		t.Fatalf("expected no snapshot")
+	}
+	if err := store.SetWaybackSnapshot(id, "https://web.archive.org/web/1/a"); err != nil {
+		t.Fatalf("SetWaybackSnapshot error: %v", err)
+	}
+	if err := store.SetWaybackSnapshot(id, "https://web.archive.org/web/2/a"); err != nil {
+		t.Fatalf("SetWaybackSnapshot update error: %v", err)
+	}
+	if store.WaybackSnapshot(id) != "https://web.archive.org/web/2/a" {
+		t.Fatalf("expected updated snapshot")
+	}
+	if _, err := store.DeleteArticle(id); err != nil {
+		t.Fatalf("DeleteArticle error: %v", err)
+	}
+	store.CleanupOrphanSummaries()
+	if store.WaybackSnapshot(id) != "" {
+		t.Fatalf("expected orphan snapshot removed")
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	summaryStrategyTruncate = "truncate"
+	summaryStrategyChunk    = "chunk"
+	charsPerToken           = 4
+	maxSummaryChunks        = 8
+	headingMaxChars         = 80
+)
+
+func parseSummaryStrategy(value string) (string, error) {
+	strategy := strings.ToLower(trimQuotes(value))
+	switch strategy {
+	case "", summaryStrategyTruncate, summaryStrategyChunk:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("invalid summary_strategy: %q (expected truncate or chunk)", value)
+}
+
+func (s *Summarizer) inputBudget() int {
+	budget := s.maxInput
+	if budget <= 0 {
+		budget = defaultMaxInputChars
+	}
+	if s.maxInputTokens > 0 && (s.maxInput <= 0 || s.maxInputTokens*charsPerToken < budget) {
+		budget = s.maxInputTokens * charsPerToken
+	}
+	return budget
+}
+
+func splitParagraphs(content string) []string {
+	paragraphs := []string{}
+	for _, block := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		if block = strings.TrimSpace(block); block != "" {
+			paragraphs = append(paragraphs, block)
+		}
+	}
+	if len(paragraphs) == 1 && strings.Contains(paragraphs[0], "\n") {
+		paragraphs = paragraphs[:0]
+		for _, line := range strings.Split(content, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				paragraphs = append(paragraphs, line)
+			}
+		}
+	}
+	return paragraphs
+}
+
+func isHeading(paragraph string) bool {
+	if strings.HasPrefix(paragraph, "#") {
+		return true
+	}
+	if len(paragraph) > headingMaxChars || strings.Contains(paragraph, "\n") {
+		return false
+	}
+	return !strings.ContainsAny(paragraph[len(paragraph)-1:], ".!?:;,\"')")
+}
+
+func smartTruncate(content string, budget int) string {
+	paragraphs := splitParagraphs(content)
+	if len(paragraphs) < 2 || len(paragraphs[0]) > budget {
+		return truncateText(content, budget) + fmt.Sprintf("\n\n[... article truncated: first %d of %d characters shown]", budget, len(content))
+	}
+	kept := []string{}
+	used := 0
+	next := 0
+	for ; next < len(paragraphs) && used+len(paragraphs[next]) <= budget*4/5; next++ {
+		kept = append(kept, paragraphs[next])
+		used += len(paragraphs[next]) + 2
+	}
+	if next == 0 {
+		kept = append(kept, paragraphs[0])
+		used += len(paragraphs[0]) + 2
+		next = 1
+	}
+	headings := 0
+	for _, paragraph := range paragraphs[next:] {
+		if isHeading(paragraph) && used+len(paragraph) <= budget {
+			kept = append(kept, paragraph)
+			used += len(paragraph) + 2
+			headings++
+		}
+	}
+	omitted := len(paragraphs) - len(kept)
+	return strings.Join(kept, "\n\n") + fmt.Sprintf("\n\n[... article truncated: %d of %d paragraphs omitted; the opening and %d later headings are shown]", omitted, len(paragraphs), headings)
+}
+
+func splitChunks(content string, budget int) []string {
+	chunks := []string{}
+	current := ""
+	for _, paragraph := range splitParagraphs(content) {
+		for len(paragraph) > budget {
+			if current != "" {
+				chunks = append(chunks, current)
+				current = ""
+			}
+			head := truncateText(paragraph, budget)
+			chunks = append(chunks, head)
+			paragraph = paragraph[len(head):]
+		}
+		if current != "" && len(current)+2+len(paragraph) > budget {
+			chunks = append(chunks, current)
+			current = ""
+		}
+		if current != "" {
+			current += "\n\n"
+		}
+		current += paragraph
+	}
+	if current != "" {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+func (s *Summarizer) summarizeChunks(title string, content string, system string) (string, Summary, error) {
+	budget := s.inputBudget()
+	if len(content) > budget*maxSummaryChunks {
+		content = smartTruncate(content, budget*maxSummaryChunks)
+	}
+	chunks := splitChunks(content, budget)
+	usage := Summary{}
+	notes := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		prompt := fmt.Sprintf("This is part %d of %d of a long article. Summarize this part in a few bullet points:\n\n", i+1, len(chunks)) + wrapArticle(title, chunk)
+		part, err := s.complete(system, prompt)
+		if err != nil {
+			return "", usage, err
+		}
+		usage.PromptTokens += part.PromptTokens
+		usage.CompletionTokens += part.CompletionTokens
+		notes = append(notes, fmt.Sprintf("Part %d of %d:\n%s", i+1, len(chunks), part.Content))
+	}
+	return strings.Join(notes, "\n\n"), usage, nil
+}
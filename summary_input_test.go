@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSummarizerInputBudget(t *testing.T) {
+	for _, tc := range []struct {
+		chars, tokens, want int
+	}{
+		{0, 0, defaultMaxInputChars},
+		{500, 0, 500},
+		{0, 1000, 4000},
+		{0, 5000, 20000},
+		{500, 1000, 500},
+		{8000, 1000, 4000},
+	} {
+		s := &Summarizer{maxInput: tc.chars, maxInputTokens: tc.tokens}
+		if got := s.inputBudget(); got != tc.want {
+			t.Fatalf("inputBudget(%d, %d) = %d, want %d", tc.chars, tc.tokens, got, tc.want)
+		}
+	}
+}
+
+func TestSmartTruncateKeepsOpeningAndHeadings(t *testing.T) {
+	body := strings.Repeat("Filler sentence that carries detail. ", 5)
+	content := strings.Join([]string{
+		"Opening paragraph with the key claim.",
+		"Second paragraph adds context.",
+		body,
+		"## Results",
+		body,
+		"Conclusion",
+		body,
+	}, "\n\n")
+	got := smartTruncate(content, 120)
+	for _, want := range []string{"Opening paragraph", "Second paragraph", "## Results", "Conclusion", "3 of 7 paragraphs omitted", "2 later headings"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in %q", want, got)
+		}
+	}
+	if strings.Contains(got, "Filler") {
+		t.Fatalf("expected body paragraphs to be dropped: %q", got)
+	}
+	if got := smartTruncate(strings.Repeat("x", 300)+"\n\nmore", 100); !strings.Contains(got, "first 100 of 306 characters shown") {
+		t.Fatalf("expected hard cut for an oversized opening paragraph, got %q", got)
+	}
+}
+
+func TestSplitChunks(t *testing.T) {
+	content := strings.Join([]string{strings.Repeat("a", 40), strings.Repeat("b", 40), strings.Repeat("c", 120)}, "\n\n")
+	chunks := splitChunks(content, 100)
+	if len(chunks) != 3 || chunks[0] != strings.Repeat("a", 40)+"\n\n"+strings.Repeat("b", 40) || len(chunks[1]) != 100 || len(chunks[2]) != 20 {
+		t.Fatalf("unexpected chunks %q", chunks)
+	}
+	if got := strings.Join(chunks[1:], ""); got != strings.Repeat("c", 120) {
+		t.Fatalf("expected oversized paragraph split without loss, got %q", got)
+	}
+}
+
+func TestSummarizeWithChunkStrategy(t *testing.T) {
+	var prompts []string
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		var payload chatRequest
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		prompts = append(prompts, payload.Messages[len(payload.Messages)-1].Content)
+		body := fmt.Sprintf(`{"choices":[{"message":{"content":"- note %d"}}],"usage":{"prompt_tokens":10,"completion_tokens":2}}`, len(prompts))
+		return newResponse(http.StatusOK, body, map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	s := &Summarizer{baseURL: "http://example.test", model: "local", client: client, maxInput: 100, strategy: summaryStrategyChunk}
+	content := strings.Join([]string{strings.Repeat("a", 90), strings.Repeat("b", 90), strings.Repeat("c", 90)}, "\n\n")
+	summary, err := s.SummarizeWith("Long", content, SummaryOptions{})
+	if err != nil {
+		t.Fatalf("SummarizeWith error: %v", err)
+	}
+	if len(prompts) != 4 || summary.Content != "- note 4" || summary.PromptTokens != 40 || summary.CompletionTokens != 8 {
+		t.Fatalf("unexpected chunked summary %+v after %d requests", summary, len(prompts))
+	}
+	if !strings.Contains(prompts[1], "part 2 of 3") || !strings.Contains(prompts[1], strings.Repeat("b", 90)) {
+		t.Fatalf("unexpected part prompt %q", prompts[1])
+	}
+	if !strings.Contains(prompts[3], "Part 3 of 3:\n- note 3") {
+		t.Fatalf("unexpected combine prompt %q", prompts[3])
+	}
+
+	prompts = nil
+	s.strategy = ""
+	if _, err := s.SummarizeWith("Long", content, SummaryOptions{}); err != nil || len(prompts) != 1 {
+		t.Fatalf("expected single truncated request, got %d %v", len(prompts), err)
+	}
+}
+
+func TestConfigSummaryInputKeys(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("max_input_tokens = 2048\nsummary_strategy = \"Chunk\"", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.MaxInputTokens != 2048 || cfg.SummaryStrategy != summaryStrategyChunk {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	rendered := renderConfig(cfg)
+	if !strings.Contains(rendered, "max_input_tokens = 2048\nsummary_strategy = \"chunk\"") {
+		t.Fatalf("unexpected rendered config %s", rendered)
+	}
+	for _, input := range []string{"max_input_tokens = -1", "summary_strategy = \"mapreduce\""} {
+		if err := parseConfig(input, &cfg); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSummaryRetries = 2
+	summaryRetryBase      = time.Second
+	summaryRetryMax       = 30 * time.Second
+)
+
+var summarySleep = func(ctx context.Context, d time.Duration) error {
+	return httpLimitSleep(ctx, d)
+}
+
+type summarizerHTTPError struct {
+	status     int
+	retryAfter time.Duration
+}
+
+func (e *summarizerHTTPError) Error() string {
+	return fmt.Sprintf("summarizer http error: %d %s", e.status, http.StatusText(e.status))
+}
+
+func newSummarizerHTTPError(resp *http.Response) error {
+	err := &summarizerHTTPError{status: resp.StatusCode}
+	if seconds, parseErr := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); parseErr == nil && seconds > 0 {
+		err.retryAfter = time.Duration(seconds) * time.Second
+	}
+	return err
+}
+
+func retryableSummaryError(err error) (*summarizerHTTPError, bool) {
+	var httpErr *summarizerHTTPError
+	if !errors.As(err, &httpErr) {
+		return nil, false
+	}
+	return httpErr, httpErr.status == http.StatusTooManyRequests || httpErr.status >= 500
+}
+
+func summaryRetryDelay(attempt int, httpErr *summarizerHTTPError) time.Duration {
+	delay := summaryRetryBase << attempt
+	if httpErr.retryAfter > delay {
+		delay = httpErr.retryAfter
+	}
+	return min(delay, summaryRetryMax)
+}
+
+type summaryRateLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+func newSummaryRateLimiter(perMinute int) *summaryRateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &summaryRateLimiter{interval: time.Minute / time.Duration(perMinute)}
+}
+
+func (l *summaryRateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	now := time.Now()
+	l.mu.Lock()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	return summarySleep(ctx, delay)
+}
+
+func (s *Summarizer) chatWithRetry(messages []chatMessage) (Summary, error) {
+	for attempt := 0; ; attempt++ {
+		if err := s.limiter.wait(s.requestContext()); err != nil {
+			return Summary{}, err
+		}
+		summary, err := s.chatOnce(messages)
+		httpErr, retryable := retryableSummaryError(err)
+		if !retryable || attempt >= s.retries {
+			return summary, err
+		}
+		delay := summaryRetryDelay(attempt, httpErr)
+		logFor("summarizer").Warn("completion retry", "provider", s.name, "model", s.model, "status", httpErr.status, "attempt", attempt+1, "retry_in", delay)
+		if err := summarySleep(s.requestContext(), delay); err != nil {
+			return Summary{}, err
+		}
+	}
+}
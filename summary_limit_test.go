@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func stubSummarySleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	slept := []time.Duration{}
+	original := summarySleep
+	summarySleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	t.Cleanup(func() { summarySleep = original })
+	return &slept
+}
+
+func TestSummarizerRetriesOnRetryableStatus(t *testing.T) {
+	slept := stubSummarySleep(t)
+	statuses := []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusOK}
+	calls := 0
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		status := statuses[calls]
+		calls++
+		if status != http.StatusOK {
+			return newResponse(status, "busy", map[string]string{"Retry-After": "5"}, r), nil
+		}
+		return newResponse(status, `{"choices":[{"message":{"content":"- ok"}}]}`, map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	s := &Summarizer{baseURL: "http://example.test", model: "m", client: client, retries: 2}
+	summary, err := s.Summarize("Title", "Body")
+	if err != nil || summary.Content != "- ok" || calls != 3 {
+		t.Fatalf("unexpected result %q %v after %d calls", summary.Content, err, calls)
+	}
+	if len(*slept) != 2 || (*slept)[0] != 5*time.Second || (*slept)[1] != 5*time.Second {
+		t.Fatalf("unexpected backoff %v", *slept)
+	}
+
+	calls, statuses = 0, []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+	*slept = (*slept)[:0]
+	client.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return newResponse(http.StatusServiceUnavailable, "down", nil, r), nil
+	})
+	if _, err := s.Summarize("Title", "Body"); err == nil || !strings.Contains(err.Error(), "503") || calls != 3 {
+		t.Fatalf("expected failure after retries, got %v after %d calls", err, calls)
+	}
+	if len(*slept) != 2 || (*slept)[0] != time.Second || (*slept)[1] != 2*time.Second {
+		t.Fatalf("expected exponential backoff, got %v", *slept)
+	}
+
+	calls = 0
+	client.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return newResponse(http.StatusBadRequest, "bad", nil, r), nil
+	})
+	if _, err := s.Summarize("Title", "Body"); err == nil || calls != 1 {
+		t.Fatalf("expected no retry for client errors, got %v after %d calls", err, calls)
+	}
+}
+
+func TestSummaryRetryDelayCaps(t *testing.T) {
+	if got := summaryRetryDelay(10, &summarizerHTTPError{status: 500}); got != summaryRetryMax {
+		t.Fatalf("expected capped delay, got %v", got)
+	}
+}
+
+func TestSummaryRateLimiterSpacesRequests(t *testing.T) {
+	slept := stubSummarySleep(t)
+	if newSummaryRateLimiter(0) != nil {
+		t.Fatalf("expected no limiter without a rate")
+	}
+	limiter := newSummaryRateLimiter(30)
+	for range 3 {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatalf("wait error: %v", err)
+		}
+	}
+	if len(*slept) != 2 || (*slept)[0] <= time.Second || (*slept)[0] > 2*time.Second || (*slept)[1] <= 3*time.Second {
+		t.Fatalf("unexpected waits %v", *slept)
+	}
+}
+
+func TestConfigSummarizerLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.SummaryRetries != defaultSummaryRetries || strings.Contains(renderConfig(cfg), "[summarizer]") {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := parseConfig("[summarizer]\nrequests_per_minute = 20\nretries = 0", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.SummaryRequestsPerMinute != 20 || cfg.SummaryRetries != 0 {
+		t.Fatalf("unexpected summarizer limits %+v", cfg)
+	}
+	if rendered := renderConfig(cfg); !strings.Contains(rendered, "[summarizer]\nrequests_per_minute = 20\nretries = 0") {
+		t.Fatalf("unexpected rendered config %s", rendered)
+	}
+	if err := parseConfig("[summarizer]\nretries = -1", &cfg); err == nil || !strings.Contains(err.Error(), "invalid summarizer.retries") {
+		t.Fatalf("expected retries error, got %v", err)
+	}
+	s := newProviderSummarizer(ProviderConfig{BaseURL: "http://lm.test"}, cfg)
+	if s.retries != 0 || s.limiter == nil || s.limiter.interval != 3*time.Second {
+		t.Fatalf("unexpected summarizer limits %+v", s)
+	}
+}
+
+func TestAppGenerateMissingSummariesContinuesAfterFailure(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DBPath = filepath.Join(t.TempDir(), "store.db")
+	app, err := NewApp(cfg)
+	if err != nil {
+		t.Fatalf("NewApp error: %v", err)
+	}
+	feed, _ := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if _, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "One", URL: "u1", ContentText: "fail"},
+		{GUID: "2", Title: "Two", URL: "u2", ContentText: "ok"},
+		{GUID: "3", Title: "Three", URL: "u3", ContentText: "ok"},
+	}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.summarizer = &Summarizer{baseURL: "http://example.test", model: "m", client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "fail") {
+			return newResponse(http.StatusBadRequest, "bad", nil, r), nil
+		}
+		return newResponse(http.StatusOK, `{"choices":[{"message":{"content":"- ok"}}]}`, map[string]string{"content-type": "application/json"}, r), nil
+	})}}
+	err = app.GenerateMissingSummaries()
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 summaries failed") || !strings.Contains(err.Error(), "One") {
+		t.Fatalf("expected failure tally error, got %v", err)
+	}
+	if app.status != "Batch summaries complete: 2/3 (1 failed)" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	if len(app.store.Summaries()) != 2 {
+		t.Fatalf("expected remaining articles summarized, got %d", len(app.store.Summaries()))
+	}
+}
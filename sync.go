@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Syncer interface {
+	Name() string
+	Pull() (SyncSnapshot, error)
+	Push(changes []SyncChange) error
+}
+
+type SyncSnapshot struct {
+	Feeds []SyncFeed
+	Items []SyncItem
+}
+
+type SyncFeed struct {
+	RemoteID string
+	Title    string
+	URL      string
+	SiteURL  string
+}
+
+type SyncItem struct {
+	RemoteID     string
+	FeedRemoteID string
+	GUID         string
+	Title        string
+	URL          string
+	Author       string
+	Content      string
+	PublishedAt  time.Time
+	Read         bool
+	Starred      bool
+}
+
+type SyncChange struct {
+	ArticleID      int
+	RemoteID       string
+	Read           bool
+	Starred        bool
+	ReadChanged    bool
+	StarredChanged bool
+}
+
+type syncAccount struct {
+	name   string
+	syncer Syncer
+}
+
+func NewSyncer(cfg Config) Syncer {
+	return newSyncer(cfg.SyncBackend, cfg.SyncURL, cfg.SyncToken, cfg.SyncUsername, cfg.SyncPassword)
+}
+
+func newSyncer(backend string, url string, token string, username string, password string) Syncer {
+	switch backend {
+	case "nextcloud":
+		if client := NewNextcloudClient(url, username, password); client != nil {
+			return client
+		}
+	case "miniflux":
+		if client := NewMinifluxClient(url, token, username, password); client != nil {
+			return client
+		}
+	case "greader":
+		if client := NewGReaderClient(url, token, username, password); client != nil {
+			return client
+		}
+	}
+	return nil
+}
+
+func NewSyncAccounts(cfg Config) []syncAccount {
+	accounts := []syncAccount{}
+	if syncer := NewSyncer(cfg); syncer != nil {
+		accounts = append(accounts, syncAccount{name: syncer.Name(), syncer: syncer})
+	}
+	names := make([]string, 0, len(cfg.Accounts))
+	for name := range cfg.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		account := cfg.Accounts[name]
+		if syncer := newSyncer(account.Backend, account.URL, account.Token, account.Username, account.Password); syncer != nil {
+			accounts = append(accounts, syncAccount{name: name, syncer: syncer})
+		}
+	}
+	return accounts
+}
+
+func (a *App) SyncAccounts() error {
+	if len(a.accounts) == 0 {
+		return messageErr(msgSyncNotConfigured)
+	}
+	lastID := a.store.MaxArticleID()
+	statuses := []string{}
+	for _, account := range a.accounts {
+		synced, err := a.syncRemote(account)
+		if err != nil {
+			a.status = tr(msgSyncFailed, err)
+			return err
+		}
+		statuses = append(statuses, synced)
+	}
+	a.applyRetention()
+	a.feeds = a.store.Feeds()
+	_ = a.store.MergeDuplicateArticles()
+	a.loadArticles()
+	a.lastNew = a.store.ArticlesAfterID(lastID)
+	a.status = strings.Join(statuses, "; ")
+	a.syncSummaryForSelection()
+	return nil
+}
+
+func (a *App) syncRemote(account syncAccount) (string, error) {
+	if account.syncer == nil {
+		return "", errors.New("sync not configured")
+	}
+	backend := account.name
+	log := logFor("sync").With("account", backend)
+	start := time.Now()
+	changes, err := a.store.PendingSyncChanges(backend)
+	if err != nil {
+		return "", err
+	}
+	if len(changes) > 0 {
+		if err := account.syncer.Push(changes); err != nil {
+			log.Warn("push failed", "changes", len(changes), "duration", time.Since(start), "err", err)
+			return "", fmt.Errorf("push to %s: %w", backend, err)
+		}
+	}
+	snapshot, err := account.syncer.Pull()
+	if err != nil {
+		log.Warn("pull failed", "duration", time.Since(start), "err", err)
+		return "", fmt.Errorf("pull from %s: %w", backend, err)
+	}
+	feeds := map[string]Feed{}
+	for _, feed := range a.store.Feeds() {
+		feeds[feed.URL] = feed
+	}
+	byRemote := map[string]Feed{}
+	for _, remote := range snapshot.Feeds {
+		feed, ok := feeds[remote.URL]
+		if !ok {
+			feed, err = a.store.InsertFeed(Feed{Title: remote.Title, URL: remote.URL, SiteURL: remote.SiteURL, Source: backend})
+			if err != nil {
+				return "", err
+			}
+			feeds[feed.URL] = feed
+		} else if feed.Source == "" {
+			if err := a.store.SetFeedSource(feed.ID, backend); err != nil {
+				return "", err
+			}
+			feed.Source = backend
+			feeds[feed.URL] = feed
+		}
+		byRemote[remote.RemoteID] = feed
+	}
+	grouped := map[string][]SyncItem{}
+	for _, item := range snapshot.Items {
+		grouped[item.FeedRemoteID] = append(grouped[item.FeedRemoteID], item)
+	}
+	for remoteFeedID, items := range grouped {
+		feed, ok := byRemote[remoteFeedID]
+		if !ok {
+			continue
+		}
+		articles := make([]Article, 0, len(items))
+		for _, item := range items {
+			articles = append(articles, Article{
+				GUID:        firstNonEmpty(item.GUID, item.URL),
+				Title:       item.Title,
+				URL:         item.URL,
+				Author:      item.Author,
+				Content:     item.Content,
+				ContentText: stripHTML(item.Content),
+				PublishedAt: item.PublishedAt,
+				IsRead:      item.Read,
+				IsStarred:   item.Starred,
+			})
+		}
+		if _, err := a.store.InsertArticles(feed, articles); err != nil {
+			return "", err
+		}
+		for i, item := range items {
+			id := a.store.SyncedArticleID(feed.ID, articles[i].GUID, item.URL)
+			if id == 0 {
+				continue
+			}
+			if err := a.store.SetArticleState(id, item.Read, item.Starred); err != nil {
+				return "", err
+			}
+			if err := a.store.SetSyncState(id, backend, item.RemoteID, item.Read, item.Starred); err != nil {
+				return "", err
+			}
+		}
+	}
+	a.metrics.feedsRefreshed.Add(int64(len(snapshot.Feeds)))
+	log.Info("synced", "feeds", len(snapshot.Feeds), "items", len(snapshot.Items), "pushed", len(changes), "duration", time.Since(start))
+	return fmt.Sprintf("synced %d feeds with %s (%d items, %d changes pushed)", len(snapshot.Feeds), backend, len(snapshot.Items), len(changes)), nil
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type fakeSyncer struct {
+	snapshot SyncSnapshot
+	pushed   [][]SyncChange
+	pullErr  error
+	pushErr  error
+}
+
+func (f *fakeSyncer) Name() string {
+	return "fake"
+}
+
+func (f *fakeSyncer) Pull() (SyncSnapshot, error) {
+	return f.snapshot, f.pullErr
+}
+
+func (f *fakeSyncer) Push(changes []SyncChange) error {
+	f.pushed = append(f.pushed, changes)
+	return f.pushErr
+}
+
+func TestAppSyncRefresh(t *testing.T) {
+	app := newTUIApp(t)
+	existing, err := app.store.InsertFeed(Feed{Title: "Local", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	published := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	syncer := &fakeSyncer{snapshot: SyncSnapshot{
+		Feeds: []SyncFeed{
+			{RemoteID: "1", Title: "Example", URL: "https://example.com/rss"},
+			{RemoteID: "2", Title: "Other", URL: "https://other.example.com/feed", SiteURL: "https://other.example.com"},
+		},
+		Items: []SyncItem{
+			{RemoteID: "10", FeedRemoteID: "1", GUID: "a", Title: "A", URL: "https://example.com/a", Content: "<p>Alpha</p>", PublishedAt: published, Read: true},
+			{RemoteID: "11", FeedRemoteID: "2", Title: "B", URL: "https://other.example.com/b", Starred: true},
+			{RemoteID: "12", FeedRemoteID: "99", GUID: "orphan", Title: "C", URL: "https://example.com/c"},
+		},
+	}}
+	app.accounts = []syncAccount{{name: "fake", syncer: syncer}}
+	app.feeds = app.store.Feeds()
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("RefreshFeeds error: %v", err)
+	}
+	if len(syncer.pushed) != 0 {
+		t.Fatalf("expected nothing pushed on first sync")
+	}
+	if len(app.feeds) != 2 || !strings.HasPrefix(app.status, "synced 2 feeds with fake (3 items, 0 changes pushed)") {
+		t.Fatalf("unexpected state %d feeds, status %q", len(app.feeds), app.status)
+	}
+	if len(app.articles) != 2 || len(app.lastNew) != 2 {
+		t.Fatalf("unexpected articles %+v", app.articles)
+	}
+	var alpha, beta Article
+	for _, article := range app.articles {
+		if article.GUID == "a" {
+			alpha = article
+		} else {
+			beta = article
+		}
+	}
+	if alpha.FeedID != existing.ID || !alpha.IsRead || alpha.ContentText != "Alpha" || !alpha.PublishedAt.Equal(published) {
+		t.Fatalf("unexpected synced article %+v", alpha)
+	}
+	if beta.GUID != "https://other.example.com/b" || !beta.IsStarred || beta.IsRead {
+		t.Fatalf("unexpected synced article %+v", beta)
+	}
+
+	if err := app.store.SetArticleState(beta.ID, true, false); err != nil {
+		t.Fatalf("SetArticleState error: %v", err)
+	}
+	syncer.snapshot.Items[0].Read = false
+	syncer.snapshot.Items[1].Read = true
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("RefreshFeeds error: %v", err)
+	}
+	if len(syncer.pushed) != 1 || len(syncer.pushed[0]) != 1 || syncer.pushed[0][0].RemoteID != "11" || !syncer.pushed[0][0].StarredChanged {
+		t.Fatalf("unexpected pushed changes %+v", syncer.pushed)
+	}
+	if article := app.findArticle(alpha.ID); article == nil || article.IsRead {
+		t.Fatalf("expected server read state applied")
+	}
+	if len(app.lastNew) != 0 {
+		t.Fatalf("expected no new articles on second sync")
+	}
+
+	app.store.SetArticleState(alpha.ID, true, false)
+	syncer.pushErr = errors.New("offline")
+	if err := app.RefreshFeeds(); err == nil || app.status != "sync failed: push to fake: offline" {
+		t.Fatalf("expected push error, got %v %q", err, app.status)
+	}
+	syncer.pushErr = nil
+	syncer.pullErr = errors.New("offline")
+	if err := app.RefreshFeeds(); err == nil || !strings.Contains(app.status, "pull from fake") {
+		t.Fatalf("expected pull error, got %v %q", err, app.status)
+	}
+	if _, err := app.syncRemote(syncAccount{name: "none"}); err == nil {
+		t.Fatalf("expected not configured error")
+	}
+}
+
+func TestAppSyncMultipleAccounts(t *testing.T) {
+	app := newTUIApp(t)
+	app.fetcher.client = clientForResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"})
+	local, err := app.store.InsertFeed(Feed{Title: "Local", URL: "https://local.example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	work := &fakeSyncer{snapshot: SyncSnapshot{
+		Feeds: []SyncFeed{{RemoteID: "1", Title: "Work", URL: "https://work.example.com/rss"}},
+		Items: []SyncItem{{RemoteID: "10", FeedRemoteID: "1", GUID: "w", Title: "W", URL: "https://work.example.com/w"}},
+	}}
+	home := &fakeSyncer{snapshot: SyncSnapshot{
+		Feeds: []SyncFeed{{RemoteID: "7", Title: "Home", URL: "https://home.example.com/rss"}},
+		Items: []SyncItem{{RemoteID: "70", FeedRemoteID: "7", GUID: "h", Title: "H", URL: "https://home.example.com/h", Starred: true}},
+	}}
+	app.accounts = []syncAccount{{name: "work", syncer: work}, {name: "home", syncer: home}}
+	app.feeds = app.store.Feeds()
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("RefreshFeeds error: %v", err)
+	}
+	want := "synced 1 feeds with work (1 items, 0 changes pushed); synced 1 feeds with home (1 items, 0 changes pushed); refreshed 1 feeds"
+	if app.status != want {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	sources := map[string]string{}
+	for _, feed := range app.feeds {
+		sources[feed.URL] = feed.Source
+	}
+	if len(sources) != 3 || sources[local.URL] != "" || sources["https://work.example.com/rss"] != "work" || sources["https://home.example.com/rss"] != "home" {
+		t.Fatalf("unexpected feed sources %+v", sources)
+	}
+	var homeArticle Article
+	for _, article := range app.articles {
+		if article.GUID == "h" {
+			homeArticle = article
+		}
+	}
+	if homeArticle.ID == 0 || !homeArticle.IsStarred || len(app.articles) < 3 {
+		t.Fatalf("expected unified article list, got %+v", app.articles)
+	}
+	if got := formatSourceAccounts(app.store.ArticleSources(homeArticle.ID)); got != "home" {
+		t.Fatalf("unexpected source attribution %q", got)
+	}
+
+	app.store.SetArticleState(homeArticle.ID, true, true)
+	if err := app.RefreshFeeds(); err != nil {
+		t.Fatalf("RefreshFeeds error: %v", err)
+	}
+	if len(home.pushed) != 1 || home.pushed[0][0].RemoteID != "70" || len(work.pushed) != 0 {
+		t.Fatalf("expected change pushed to owning account only, got work %+v home %+v", work.pushed, home.pushed)
+	}
+}
+
+func TestNewSyncAccounts(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SyncBackend = "miniflux"
+	cfg.SyncURL = "https://flux.example.com"
+	cfg.SyncToken = "tok"
+	cfg.Accounts = map[string]AccountConfig{
+		"work":   {Name: "work", Backend: "nextcloud", URL: "https://cloud.example.com", Username: "u", Password: "p"},
+		"broken": {Name: "broken", Backend: "greader"},
+	}
+	accounts := NewSyncAccounts(cfg)
+	if len(accounts) != 2 || accounts[0].name != accounts[0].syncer.Name() || accounts[1].name != "work" {
+		t.Fatalf("unexpected accounts %+v", accounts)
+	}
+	if len(NewSyncAccounts(DefaultConfig())) != 0 {
+		t.Fatalf("expected no accounts by default")
+	}
+}
+
+func TestFormatSourceAccounts(t *testing.T) {
+	if got := formatSourceAccounts([]ArticleSource{{FeedTitle: "A"}}); got != "" {
+		t.Fatalf("expected no attribution for local feeds, got %q", got)
+	}
+	if got := formatSourceAccounts([]ArticleSource{{Source: "work"}, {}, {Source: "work"}}); got != "work, local" {
+		t.Fatalf("unexpected attribution %q", got)
+	}
+}
+
+func TestRefreshCmdLeavesAppUntilApplied(t *testing.T) {
+	app := newTUIApp(t)
+	syncer := &fakeSyncer{snapshot: SyncSnapshot{
+		Feeds: []SyncFeed{{RemoteID: "1", Title: "Example", URL: "https://example.com/rss"}},
+		Items: []SyncItem{{RemoteID: "10", FeedRemoteID: "1", GUID: "a", Title: "A", URL: "https://example.com/a"}},
+	}}
+	app.accounts = []syncAccount{{name: "fake", syncer: syncer}}
+	app.status = "before"
+	model := newTUIModel(app)
+	model.app.refreshPending = true
+
+	msg := refreshCmd(app, context.Background())()
+	if app.status != "before" || len(app.feeds) != 0 || len(app.articles) != 0 {
+		t.Fatalf("refresh mutated app before apply: %q %d %d", app.status, len(app.feeds), len(app.articles))
+	}
+	updated, _ := model.Update(msg)
+	model = updated.(tuiModel)
+	if model.app.refreshPending || len(model.app.feeds) != 1 || len(model.app.articles) != 1 || len(model.app.lastNew) != 1 {
+		t.Fatalf("unexpected applied state %+v", model.app.articles)
+	}
+	if !strings.HasPrefix(model.app.status, "synced 1 feeds with fake") {
+		t.Fatalf("unexpected status %q", model.app.status)
+	}
+}
+
+func TestAppSyncAccounts(t *testing.T) {
+	app := newTUIApp(t)
+	if err := app.SyncAccounts(); err == nil || err.Error() != "no sync backend configured" {
+		t.Fatalf("expected not configured error, got %v", err)
+	}
+	local, err := app.store.InsertFeed(Feed{Title: "Local", URL: "https://local.example/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	app.fetcher.client = &http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		t.Fatalf("sync must not fetch local feeds")
+		return nil, nil
+	})}
+	syncer := &fakeSyncer{snapshot: SyncSnapshot{
+		Feeds: []SyncFeed{{RemoteID: "1", Title: "Example", URL: "https://example.com/rss"}},
+		Items: []SyncItem{{RemoteID: "10", FeedRemoteID: "1", GUID: "a", Title: "A", URL: "https://example.com/a"}},
+	}}
+	app.accounts = []syncAccount{{name: "fake", syncer: syncer}}
+	app.feeds = app.store.Feeds()
+	if err := app.SyncAccounts(); err != nil {
+		t.Fatalf("SyncAccounts error: %v", err)
+	}
+	if len(app.feeds) != 2 || len(app.articles) != 1 || len(app.lastNew) != 1 || app.status != "synced 1 feeds with fake (1 items, 0 changes pushed)" {
+		t.Fatalf("unexpected state %d feeds %d articles, status %q", len(app.feeds), len(app.articles), app.status)
+	}
+	if feed := app.findFeed(local.ID); feed == nil || !feed.LastFetched.IsZero() {
+		t.Fatalf("expected local feed untouched, got %+v", feed)
+	}
+
+	syncer.pullErr = errors.New("offline")
+	if err := app.SyncAccounts(); err == nil || app.status != "sync failed: pull from fake: offline" {
+		t.Fatalf("expected pull failure, got %v %q", err, app.status)
+	}
+}
+
+func TestTUISyncKey(t *testing.T) {
+	app := newTUIApp(t)
+	syncer := &fakeSyncer{snapshot: SyncSnapshot{
+		Feeds: []SyncFeed{{RemoteID: "1", Title: "Example", URL: "https://example.com/rss"}},
+	}}
+	app.accounts = []syncAccount{{name: "fake", syncer: syncer}}
+	model := newTUIModel(app)
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Y")})
+	model = updated.(tuiModel)
+	if !model.app.refreshPending || model.app.refreshStatus != "Syncing..." || cmd == nil {
+		t.Fatalf("expected sync to start")
+	}
+	updated, _ = model.Update(cmd())
+	model = updated.(tuiModel)
+	if model.app.refreshPending || len(model.app.feeds) != 1 || !strings.HasPrefix(model.app.status, "synced 1 feeds with fake") {
+		t.Fatalf("unexpected state after sync %q", model.app.status)
+	}
+
+	syncer.pullErr = errors.New("offline")
+	updated, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Y")})
+	model = updated.(tuiModel)
+	updated, _ = model.Update(cmd())
+	model = updated.(tuiModel)
+	if !strings.HasPrefix(model.app.status, "Sync failed") {
+		t.Fatalf("expected sync failure status, got %q", model.app.status)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const tagChoiceLimit = 9
+
+func (a *App) TagSelected(raw string) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	raw = strings.TrimSpace(raw)
+	tags := []string{}
+	if raw != "-" {
+		tags = strings.Split(raw, ",")
+	}
+	if err := a.store.SetArticleTags(article.ID, tagSourceUser, tags); err != nil {
+		return err
+	}
+	if a.tagFilter != "" {
+		a.tagged = a.store.ArticleIDsWithTag(a.tagFilter)
+	}
+	if saved := a.store.ArticleTagsFrom(article.ID, tagSourceUser); len(saved) > 0 {
+		a.status = tr(msgTagSet, strings.Join(saved, ", "))
+	} else {
+		a.status = tr(msgTagCleared)
+	}
+	return nil
+}
+
+func (a *App) tagChoices() []string {
+	tags := sortedTags(a.store.TagCounts())
+	if len(tags) > tagChoiceLimit {
+		tags = tags[:tagChoiceLimit]
+	}
+	return tags
+}
+
+func (a *App) resolveTagChoice(value string) string {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.Atoi(value); err == nil {
+		if choices := a.tagChoices(); n >= 1 && n <= len(choices) {
+			return choices[n-1]
+		}
+	}
+	return value
+}
+
+func formatTagChoices(tags []string) string {
+	if len(tags) == 0 {
+		return tr(msgTagNone)
+	}
+	parts := make([]string, 0, len(tags))
+	for i, tag := range tags {
+		parts = append(parts, fmt.Sprintf("%d %s", i+1, tag))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (a *App) suggestedBookmarkTags() []string {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, tag := range append(a.store.ArticleTagsFrom(article.ID, tagSourceUser), a.store.ArticleTagsFrom(article.ID, tagSourceTopic)...) {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
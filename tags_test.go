@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTagSelected(t *testing.T) {
+	app, _, _ := newFolderApp(t)
+	article := app.SelectedArticle()
+	if err := app.TagSelected("Read Later, #go,,"); err != nil {
+		t.Fatalf("TagSelected error: %v", err)
+	}
+	if app.status != "Tagged: go, read later" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	app.SetTagFilter("go")
+	if !app.tagged[article.ID] || len(app.FilteredArticles()) != 1 {
+		t.Fatalf("expected tag filter to match, got %v", app.tagged)
+	}
+	if err := handleCommand(app, "tag -", io.Discard); err != nil {
+		t.Fatalf("tag error: %v", err)
+	}
+	if app.status != "Tags cleared" || len(app.tagged) != 0 {
+		t.Fatalf("expected tags cleared, got %q %v", app.status, app.tagged)
+	}
+	if err := handleCommand(app, "tag", io.Discard); err == nil {
+		t.Fatalf("expected usage error")
+	}
+}
+
+func TestResolveTagChoice(t *testing.T) {
+	app, _, _ := newFolderApp(t)
+	if got := formatTagChoices(app.tagChoices()); got != "No tags yet." {
+		t.Fatalf("unexpected empty choices %q", got)
+	}
+	articles := app.store.Articles()
+	for _, article := range articles {
+		if err := app.store.SetArticleTags(article.ID, tagSourceUser, []string{"news"}); err != nil {
+			t.Fatalf("SetArticleTags error: %v", err)
+		}
+	}
+	if err := app.store.SetArticleTags(articles[0].ID, tagSourceTopic, []string{"go"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	if got := formatTagChoices(app.tagChoices()); got != "1 news, 2 go" {
+		t.Fatalf("unexpected choices %q", got)
+	}
+	for input, want := range map[string]string{"2": "go", "1": "news", "7": "7", "rust": "rust"} {
+		if got := app.resolveTagChoice(input); got != want {
+			t.Fatalf("resolveTagChoice(%q) = %q, want %q", input, got, want)
+		}
+	}
+	if err := handleCommand(app, "T 2", io.Discard); err != nil || app.tagFilter != "go" {
+		t.Fatalf("expected numbered filter, got %v %q", err, app.tagFilter)
+	}
+}
+
+func TestTUITagKeys(t *testing.T) {
+	app, _, _ := newFolderApp(t)
+	if err := app.TagSelected("later"); err != nil {
+		t.Fatalf("TagSelected error: %v", err)
+	}
+	model := newTUIModel(app)
+	model.width, model.height = 120, 30
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(tuiModel)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if model.inputMode != inputTagArticle || model.input.Value() != "later" {
+		t.Fatalf("expected prefilled tag input, got %v %q", model.inputMode, model.input.Value())
+	}
+	model.input.SetValue("later, work")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.app.status != "Tagged: later, work" {
+		t.Fatalf("unexpected status %q", model.app.status)
+	}
+	if !strings.Contains(model.View(), "Tags: later, work") {
+		t.Fatalf("expected tags in details:\n%s", model.View())
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if model.inputMode != inputTopicFilter || model.app.status != "1 later, 2 work" {
+		t.Fatalf("expected tag list, got %q", model.app.status)
+	}
+	model.input.SetValue("2")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.app.tagFilter != "work" || len(model.app.FilteredArticles()) != 1 {
+		t.Fatalf("expected work filter, got %q", model.app.tagFilter)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const telegramMessageLimit = 4096
+
+const telegramHelp = "Commands: /read <id>, /star <id>, /bookmark <id> [tag,tag]"
+
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+func telegramBaseURL() string {
+	base := strings.TrimSpace(os.Getenv("TELEGRAM_BASE_URL"))
+	if base == "" {
+		base = "https://api.telegram.org"
+	}
+	return strings.TrimRight(base, "/")
+}
+
+func (n *Notifier) telegramEndpoint(method string) string {
+	return telegramBaseURL() + "/bot" + n.config.BotToken + "/" + method
+}
+
+func (n *Notifier) telegramUpdates() ([]string, error) {
+	query := url.Values{}
+	query.Set("timeout", "0")
+	query.Set("allowed_updates", `["message"]`)
+	if n.telegramOffset > 0 {
+		query.Set("offset", strconv.FormatInt(n.telegramOffset, 10))
+	}
+	resp, err := n.client.Get(n.telegramEndpoint("getUpdates") + "?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("telegram %s: http %d", n.config.Name, resp.StatusCode)
+	}
+	var payload struct {
+		OK     bool             `json:"ok"`
+		Result []telegramUpdate `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	if !payload.OK {
+		return nil, fmt.Errorf("telegram %s: request rejected", n.config.Name)
+	}
+	commands := []string{}
+	for _, update := range payload.Result {
+		if update.UpdateID >= n.telegramOffset {
+			n.telegramOffset = update.UpdateID + 1
+		}
+		if update.Message == nil || strconv.FormatInt(update.Message.Chat.ID, 10) != strings.TrimSpace(n.config.ChatID) {
+			continue
+		}
+		if text := strings.TrimSpace(update.Message.Text); strings.HasPrefix(text, "/") {
+			commands = append(commands, text)
+		}
+	}
+	return commands, nil
+}
+
+func formatTelegramArticlesMessage(articles []Article) string {
+	lines := []string{fmt.Sprintf("%d new articles:", len(articles))}
+	for _, article := range articles {
+		line := fmt.Sprintf("- [%d] %s", article.ID, article.Title)
+		if article.FeedTitle != "" {
+			line += " (" + article.FeedTitle + ")"
+		}
+		if article.URL != "" {
+			line += " " + article.URL
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(append(lines, "", telegramHelp), "\n")
+}
+
+func (a *App) HandleTelegramCommands() (int, error) {
+	handled := 0
+	var errs []error
+	for _, notifier := range a.notifiers {
+		if notifier.config.Type != "telegram" {
+			continue
+		}
+		commands, err := notifier.telegramUpdates()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, command := range commands {
+			handled++
+			if err := notifier.Send(a.runTelegramCommand(command)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return handled, errors.Join(errs...)
+}
+
+func (a *App) runTelegramCommand(text string) string {
+	fields := strings.Fields(text)
+	command, _, _ := strings.Cut(strings.TrimPrefix(fields[0], "/"), "@")
+	switch command {
+	case "read", "star", "bookmark":
+	case "start", "help":
+		return telegramHelp
+	default:
+		return "Unknown command /" + command + ". " + telegramHelp
+	}
+	if len(fields) < 2 {
+		return "Usage: /" + command + " <id>"
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(fields[1], "#"))
+	if err != nil {
+		return "Invalid article id: " + fields[1]
+	}
+	err = a.withArticleSelected(id, func(article Article) error {
+		switch command {
+		case "read":
+			if !article.IsRead {
+				return a.ToggleRead()
+			}
+		case "star":
+			if !article.IsStarred {
+				return a.ToggleStar()
+			}
+		case "bookmark":
+			tags := []string{}
+			if len(fields) > 2 {
+				tags = strings.Split(strings.Join(fields[2:], ""), ",")
+			}
+			return a.SaveBookmark(tags)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Sprintf("/%s %d failed: %v", command, id, err)
+	}
+	switch command {
+	case "read":
+		return fmt.Sprintf("Marked %d read", id)
+	case "star":
+		return fmt.Sprintf("Starred %d", id)
+	}
+	return fmt.Sprintf("%d: %s", id, a.status)
+}
+
+func (a *App) withArticleSelected(id int, fn func(Article) error) error {
+	filter, tagFilter, scope, queueView, index := a.filter, a.tagFilter, a.scope, a.queueView, a.selectedIndex
+	defer func() {
+		a.filter, a.tagFilter, a.scope, a.queueView, a.selectedIndex = filter, tagFilter, scope, queueView, index
+	}()
+	a.filter, a.tagFilter, a.scope, a.queueView = FilterAll, "", feedScope{}, false
+	for i, article := range a.FilteredArticles() {
+		if article.ID == id {
+			a.selectedIndex = i
+			return fn(article)
+		}
+	}
+	return errors.New("article not found")
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type fakeTelegram struct {
+	updates []string
+	offsets []string
+	sent    []map[string]string
+}
+
+func (f *fakeTelegram) client(t *testing.T) *http.Client {
+	t.Helper()
+	return &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/bot123:abc/getUpdates":
+			f.offsets = append(f.offsets, r.URL.Query().Get("offset"))
+			body := `{"ok":true,"result":[` + strings.Join(f.updates, ",") + `]}`
+			f.updates = nil
+			return newResponse(http.StatusOK, body, nil, r), nil
+		case "/bot123:abc/sendMessage":
+			payload := map[string]string{}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			f.sent = append(f.sent, payload)
+			return newResponse(http.StatusOK, `{"ok":true}`, nil, r), nil
+		}
+		return newResponse(http.StatusNotFound, "", nil, r), nil
+	})}
+}
+
+func telegramMessage(updateID int, chatID int, text string) string {
+	return fmt.Sprintf(`{"update_id":%d,"message":{"chat":{"id":%d},"text":%q}}`, updateID, chatID, text)
+}
+
+func TestTelegramSend(t *testing.T) {
+	t.Setenv("TELEGRAM_BASE_URL", "http://telegram.test/")
+	fake := &fakeTelegram{}
+	notifier := &Notifier{config: NotifierConfig{Name: "phone", Type: "telegram", BotToken: "123:abc", ChatID: "42"}, client: fake.client(t)}
+	if err := notifier.Send(strings.Repeat("x", 5000)); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if len(fake.sent) != 1 || fake.sent[0]["chat_id"] != "42" || len(fake.sent[0]["text"]) != telegramMessageLimit {
+		t.Fatalf("unexpected telegram message %+v", fake.sent)
+	}
+	message := formatTelegramArticlesMessage([]Article{{ID: 7, Title: "Post", FeedTitle: "Feed", URL: "https://example.com/p"}})
+	if !strings.Contains(message, "- [7] Post (Feed) https://example.com/p") || !strings.HasSuffix(message, telegramHelp) {
+		t.Fatalf("unexpected articles message %q", message)
+	}
+	notifier.client = clientForResponse(http.StatusUnauthorized, "", nil)
+	if _, err := notifier.telegramUpdates(); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	notifier.client = clientForResponse(http.StatusOK, `{"ok":false}`, nil)
+	if _, err := notifier.telegramUpdates(); err == nil {
+		t.Fatalf("expected rejected request error")
+	}
+}
+
+func TestAppHandleTelegramCommands(t *testing.T) {
+	t.Setenv("TELEGRAM_BASE_URL", "http://telegram.test")
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "One", URL: "https://example.com/1"}, {GUID: "2", Title: "Two", URL: "https://example.com/2"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	if handled, err := app.HandleTelegramCommands(); handled != 0 || err != nil {
+		t.Fatalf("expected no-op without telegram notifiers")
+	}
+
+	fake := &fakeTelegram{}
+	app.notifiers = []*Notifier{{config: NotifierConfig{Name: "phone", Type: "telegram", BotToken: "123:abc", ChatID: "42"}, client: fake.client(t)}}
+	posted := 0
+	app.mastodon = &MastodonClient{baseURL: "http://mastodon.test", token: "t", client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		posted++
+		return newResponse(http.StatusOK, `{"url":"u"}`, nil, r), nil
+	})}}
+	app.config.SaveTarget = "mastodon"
+	one, two := articles[0].ID, articles[1].ID
+	fake.updates = []string{
+		telegramMessage(10, 42, fmt.Sprintf("/read %d", one)),
+		telegramMessage(11, 42, fmt.Sprintf("/star@greeder_bot %d", one)),
+		telegramMessage(12, 99, fmt.Sprintf("/read %d", two)),
+		telegramMessage(13, 42, "hello"),
+		telegramMessage(14, 42, fmt.Sprintf("/bookmark #%d go", two)),
+		telegramMessage(15, 42, "/read"),
+		telegramMessage(16, 42, "/read 9999"),
+		telegramMessage(17, 42, "/dance"),
+		`{"update_id":18}`,
+	}
+	handled, err := app.HandleTelegramCommands()
+	if err != nil || handled != 6 {
+		t.Fatalf("unexpected handled %d %v", handled, err)
+	}
+	replies := []string{}
+	for _, sent := range fake.sent {
+		replies = append(replies, sent["text"])
+	}
+	want := []string{
+		fmt.Sprintf("Marked %d read", one),
+		fmt.Sprintf("Starred %d", one),
+		fmt.Sprintf("%d: Posted to Mastodon", two),
+		"Usage: /read <id>",
+		"/read 9999 failed: article not found",
+		"Unknown command /dance. " + telegramHelp,
+	}
+	if strings.Join(replies, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected replies %q", replies)
+	}
+	updated := app.findArticle(one)
+	if !updated.IsRead || !updated.IsStarred || app.findArticle(two).IsRead || posted != 1 {
+		t.Fatalf("unexpected article state %+v posted %d", updated, posted)
+	}
+	if app.filter != FilterUnread {
+		t.Fatalf("expected view filter restored, got %q", app.filter)
+	}
+
+	var out bytes.Buffer
+	lastDigest := ""
+	fake.updates = []string{telegramMessage(19, 42, "/help")}
+	app.daemonCycle(&out, &lastDigest)
+	if fake.offsets[len(fake.offsets)-1] != "19" || !strings.Contains(out.String(), "handled 1 telegram commands") || fake.sent[len(fake.sent)-1]["text"] != telegramHelp {
+		t.Fatalf("unexpected daemon telegram handling %q %q", out.String(), fake.offsets)
+	}
+	app.notifiers[0].client = clientForResponse(http.StatusBadGateway, "", nil)
+	app.daemonCycle(&out, &lastDigest)
+	if !strings.Contains(out.String(), "telegram failed") {
+		t.Fatalf("expected telegram failure in output: %s", out.String())
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var defaultTheme = map[string]string{
+	"header":        "86",
+	"selected":      "205",
+	"title":         "33",
+	"summary":       "214",
+	"meta":          "245",
+	"status":        "241",
+	"border":        "63",
+	"dialog_border": "62",
+}
+
+var hexColorRe = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+func parseThemeSection(name string, value string, cfg *Config) error {
+	if _, ok := defaultTheme[name]; !ok {
+		return fmt.Errorf("invalid theme color name: %q", name)
+	}
+	color := trimQuotes(value)
+	if !validThemeColor(color) {
+		return fmt.Errorf("invalid theme.%s: %q (expected an ANSI color 0-255 or #rrggbb)", name, color)
+	}
+	if cfg.Theme == nil {
+		cfg.Theme = map[string]string{}
+	}
+	cfg.Theme[name] = color
+	return nil
+}
+
+func validThemeColor(color string) bool {
+	if hexColorRe.MatchString(color) {
+		return true
+	}
+	parsed, err := strconv.Atoi(color)
+	return err == nil && parsed >= 0 && parsed <= 255
+}
+
+func themeColor(cfg Config, name string) lipgloss.Color {
+	if color, ok := cfg.Theme[name]; ok {
+		return lipgloss.Color(color)
+	}
+	return lipgloss.Color(defaultTheme[name])
+}
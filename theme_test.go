@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestParseThemeSection(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("[theme]\nheader = \"#ff8800\"\nselected = 99\nborder = \"#abc\"", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.Theme["header"] != "#ff8800" || cfg.Theme["selected"] != "99" || cfg.Theme["border"] != "#abc" {
+		t.Fatalf("unexpected theme %+v", cfg.Theme)
+	}
+	if !strings.Contains(renderConfig(cfg), "[theme]\nborder = \"#abc\"\nheader = \"#ff8800\"\nselected = \"99\"") {
+		t.Fatalf("unexpected rendered theme:\n%s", renderConfig(cfg))
+	}
+	if themeColor(cfg, "header") != lipgloss.Color("#ff8800") || themeColor(cfg, "meta") != lipgloss.Color("245") {
+		t.Fatalf("unexpected theme lookups")
+	}
+	for bad, want := range map[string]string{
+		"[theme]\nsparkle = \"1\"":     "invalid theme color name",
+		"[theme]\nheader = \"256\"":    "invalid theme.header",
+		"[theme]\nheader = \"#12345\"": "invalid theme.header",
+		"[theme]\nheader = \"orange\"": "invalid theme.header",
+	} {
+		if err := parseConfig(bad, &cfg); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q for %q, got %v", want, bad, err)
+		}
+	}
+}
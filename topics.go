@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func (s *Summarizer) ExtractTopics(title, content string) ([]string, error) {
+	if s == nil {
+		return nil, errors.New("summarizer not configured")
+	}
+	prompt := wrapArticle(title, truncateText(content, 6000))
+	result, err := s.complete(topicsSystemPrompt(), prompt)
+	if err != nil {
+		return nil, err
+	}
+	topics := parseTopics(result.Content)
+	if len(topics) == 0 {
+		return nil, errors.New("no topics returned")
+	}
+	return topics, nil
+}
+
+func topicsSystemPrompt() string {
+	return "List the 3-5 main topics of this article as short lowercase keywords of one to three words.\n" +
+		"Output ONLY a JSON object of the form {\"topics\": [\"first topic\", \"second topic\"]} - no explanations or other text."
+}
+
+func jsonTopics(raw string) []string {
+	start := strings.IndexAny(raw, "{[")
+	end := strings.LastIndexAny(raw, "}]")
+	if start < 0 || end < start {
+		return nil
+	}
+	blob := []byte(raw[start : end+1])
+	var object struct {
+		Topics []string `json:"topics"`
+		Tags   []string `json:"tags"`
+	}
+	if err := json.Unmarshal(blob, &object); err == nil {
+		return append(append([]string{}, object.Topics...), object.Tags...)
+	}
+	var list []string
+	if err := json.Unmarshal(blob, &list); err == nil {
+		return list
+	}
+	return nil
+}
+
+func parseTopics(raw string) []string {
+	fields := jsonTopics(raw)
+	if fields == nil {
+		fields = strings.FieldsFunc(raw, func(r rune) bool {
+			return r == ',' || r == '\n' || r == ';'
+		})
+	}
+	seen := map[string]bool{}
+	topics := []string{}
+	for _, field := range fields {
+		topic := normalizeTag(field)
+		if topic == "" || seen[topic] || len(topic) > 40 {
+			continue
+		}
+		seen[topic] = true
+		topics = append(topics, topic)
+		if len(topics) == 5 {
+			break
+		}
+	}
+	return topics
+}
+
+func (a *App) ExtractTopics(article Article) error {
+	if a.summarizer == nil {
+		return errors.New("summarizer not configured")
+	}
+	text := firstNonEmpty(article.ContentText, article.Content)
+	if summary, ok := a.store.FindSummary(article.ID); ok && summary.Content != "" {
+		text = summary.Content
+	}
+	topics, err := a.summarizer.ExtractTopics(article.Title, text)
+	if err != nil {
+		return err
+	}
+	return a.storeTopics(article.ID, topics)
+}
+
+func (a *App) storeTopics(articleID int, topics []string) error {
+	if err := a.store.SetArticleTags(articleID, tagSourceTopic, topics); err != nil {
+		return err
+	}
+	if a.tagFilter != "" {
+		a.tagged = a.store.ArticleIDsWithTag(a.tagFilter)
+	}
+	return nil
+}
+
+func (a *App) SetTagFilter(tag string) {
+	a.tagFilter = normalizeTag(tag)
+	a.tagged = nil
+	if a.tagFilter != "" {
+		a.tagged = a.store.ArticleIDsWithTag(a.tagFilter)
+		a.status = fmt.Sprintf("Topic filter: %s (%d articles)", a.tagFilter, len(a.tagged))
+	} else {
+		a.status = "Topic filter cleared"
+	}
+	a.selectedIndex = 0
+	a.syncSummaryForSelection()
+}
+
+func formatTopicCounts(counts map[string]int) string {
+	tags := sortedTags(counts)
+	if len(tags) == 0 {
+		return "No topics yet."
+	}
+	parts := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		parts = append(parts, fmt.Sprintf("%s (%d)", tag, counts[tag]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func topicsSummarizer(content string) *Summarizer {
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusOK, `{"choices":[{"message":{"content":`+strconv.Quote(content)+`}}]}`, map[string]string{"content-type": "application/json"}, r), nil
+	})}
+	return &Summarizer{baseURL: "http://example.test", model: "m", client: client}
+}
+
+func TestParseTopics(t *testing.T) {
+	topics := parseTopics("Go, databases\n- SQLite; go, a, b, c, d")
+	if len(topics) != 5 || topics[0] != "go" || topics[1] != "databases" || topics[2] != "sqlite" {
+		t.Fatalf("unexpected topics: %v", topics)
+	}
+	if topics := parseTopics(strings.Repeat("x", 41)); len(topics) != 0 {
+		t.Fatalf("expected long topic dropped, got %v", topics)
+	}
+	if topics := parseTopics("```json\n{\"topics\": [\"Machine Learning\", \"gpus, cheap\"]}\n```"); len(topics) != 2 || topics[0] != "machine learning" || topics[1] != "gpus, cheap" {
+		t.Fatalf("unexpected json topics: %v", topics)
+	}
+	if topics := parseTopics(`["rust", "wasm"]`); len(topics) != 2 || topics[1] != "wasm" {
+		t.Fatalf("unexpected json list topics: %v", topics)
+	}
+	if topics := parseTopics(`{"summary": "no topics here"}`); len(topics) != 0 {
+		t.Fatalf("expected no topics from unrelated json, got %v", topics)
+	}
+}
+
+func TestTUIBookmarkPrefillsTags(t *testing.T) {
+	app, articles := seedTopicsApp(t)
+	app.selectedIndex = 0
+	for i, article := range app.articles {
+		if article.ID == articles[0].ID {
+			app.selectedIndex = i
+		}
+	}
+	if err := app.store.SetArticleTags(articles[0].ID, tagSourceTopic, []string{"encryption", "privacy"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	if err := app.store.SetArticleTags(articles[0].ID, tagSourceUser, []string{"work", "privacy"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	model := newTUIModel(app)
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	model = updated.(tuiModel)
+	if model.inputMode != inputBookmarkTags || model.input.Value() != "privacy, work, encryption" {
+		t.Fatalf("expected suggested tags, got %q", model.input.Value())
+	}
+	app.selectedIndex = 1 - app.selectedIndex
+	updated, _ = newTUIModel(app).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	if value := updated.(tuiModel).input.Value(); value != "" {
+		t.Fatalf("expected empty input without tags, got %q", value)
+	}
+}
+
+func TestSummarizerExtractTopics(t *testing.T) {
+	var nilSummarizer *Summarizer
+	if _, err := nilSummarizer.ExtractTopics("t", "c"); err == nil {
+		t.Fatalf("expected nil summarizer error")
+	}
+	topics, err := topicsSummarizer("privacy, encryption").ExtractTopics("Title", "Body")
+	if err != nil || len(topics) != 2 || topics[1] != "encryption" {
+		t.Fatalf("unexpected topics: %v (%v)", topics, err)
+	}
+	topics, err = topicsSummarizer(`{"topics": ["privacy", "encryption", "law"]}`).ExtractTopics("Title", "Body")
+	if err != nil || len(topics) != 3 || topics[2] != "law" {
+		t.Fatalf("unexpected json topics: %v (%v)", topics, err)
+	}
+	if _, err := topicsSummarizer(" ").ExtractTopics("Title", "Body"); err == nil {
+		t.Fatalf("expected empty topics error")
+	}
+	failing := &Summarizer{baseURL: "http://example.test", model: "m", client: clientForResponse(http.StatusInternalServerError, "", nil)}
+	if _, err := failing.ExtractTopics("Title", "Body"); err == nil {
+		t.Fatalf("expected http error")
+	}
+}
+
+func seedTopicsApp(t *testing.T) (*App, []Article) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "Crypto", URL: "https://example.com/1", ContentText: "about encryption"},
+		{GUID: "2", Title: "Gardening", URL: "https://example.com/2", ContentText: "about plants"},
+	})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	app.articles = app.store.SortedArticles()
+	return app, articles
+}
+
+func TestAppExtractTopicsAndFilter(t *testing.T) {
+	app, articles := seedTopicsApp(t)
+	if err := app.ExtractTopics(articles[0]); err == nil {
+		t.Fatalf("expected no summarizer error")
+	}
+	app.summarizer = topicsSummarizer("privacy, encryption")
+	app.config.ExtractTopics = true
+	for app.SelectedArticle().ID != articles[0].ID {
+		app.MoveSelection(1)
+	}
+	if err := app.GenerateSummary(); err != nil {
+		t.Fatalf("GenerateSummary error: %v", err)
+	}
+	if tags := app.store.ArticleTags(articles[0].ID); len(tags) != 2 {
+		t.Fatalf("expected topics stored after summary, got %v", tags)
+	}
+
+	app.SetTagFilter("Privacy")
+	filtered := app.FilteredArticles()
+	if len(filtered) != 1 || filtered[0].ID != articles[0].ID || !strings.Contains(app.status, "privacy (1 articles)") {
+		t.Fatalf("unexpected filtered articles: %+v (%s)", filtered, app.status)
+	}
+	app.filter = FilterAll
+	if len(app.FilteredArticles()) != 1 {
+		t.Fatalf("expected topic filter to apply with all filter")
+	}
+	if err := app.storeTopics(articles[1].ID, []string{"privacy"}); err != nil {
+		t.Fatalf("storeTopics error: %v", err)
+	}
+	if len(app.FilteredArticles()) != 2 {
+		t.Fatalf("expected filter refreshed after new topics")
+	}
+	app.SetTagFilter("")
+	if app.status != "Topic filter cleared" || len(app.FilteredArticles()) != 2 {
+		t.Fatalf("expected filter cleared")
+	}
+
+	app.summarizer = &Summarizer{baseURL: "http://example.test", model: "m", client: clientForResponse(http.StatusInternalServerError, "", nil)}
+	if err := app.GenerateMissingSummaries(); err == nil {
+		t.Fatalf("expected batch error")
+	}
+}
+
+func TestFormatTopicCounts(t *testing.T) {
+	if got := formatTopicCounts(map[string]int{}); got != "No topics yet." {
+		t.Fatalf("unexpected empty output: %q", got)
+	}
+	if got := formatTopicCounts(map[string]int{"go": 1, "ai": 3}); got != "ai (3), go (1)" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestTUITopics(t *testing.T) {
+	app, articles := seedTopicsApp(t)
+	app.config.ExtractTopics = true
+	app.summarizer = topicsSummarizer("gardening, plants")
+	model := newTUIModel(app)
+	model.width = 120
+	model.height = 40
+
+	updated, cmd := model.Update(summaryResultMsg{articleID: articles[1].ID, summaryText: "- plants"})
+	model = updated.(tuiModel)
+	if cmd == nil {
+		t.Fatalf("expected topics command")
+	}
+	updated, _ = model.Update(topicsCmd(articles[1], "- plants", app.summarizer)())
+	model = updated.(tuiModel)
+	if tags := app.store.ArticleTags(articles[1].ID); len(tags) != 2 || tags[0] != "gardening" {
+		t.Fatalf("expected topics stored, got %v", tags)
+	}
+	updated, _ = model.Update(topicsResultMsg{articleID: articles[1].ID, err: errors.New("boom")})
+	model = updated.(tuiModel)
+	if app.status != "Topic extraction failed: boom" {
+		t.Fatalf("unexpected status: %q", app.status)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	model = updated.(tuiModel)
+	if model.inputMode != inputTopicFilter || model.inputPrompt() != "Filter by Topic" {
+		t.Fatalf("expected topic input")
+	}
+	model.input.SetValue("plants")
+	model = model.commitInput()
+	if app.tagFilter != "plants" || len(app.FilteredArticles()) != 1 {
+		t.Fatalf("expected topic filter applied")
+	}
+	if !strings.Contains(model.View(), "Topics: gardening, plants") {
+		t.Fatalf("expected topics in details")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	model = updated.(tuiModel)
+	if app.tagFilter != "" {
+		t.Fatalf("expected topic filter cleared")
+	}
+}
+
+func TestHandleCommandTopics(t *testing.T) {
+	app, articles := seedTopicsApp(t)
+	if err := app.store.SetArticleTags(articles[0].ID, tagSourceTopic, []string{"privacy"}); err != nil {
+		t.Fatalf("SetArticleTags error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := handleCommand(app, "topics", &out); err != nil || !strings.Contains(out.String(), "privacy (1)") {
+		t.Fatalf("unexpected topics output: %s", out.String())
+	}
+	if err := handleCommand(app, "T privacy", &out); err != nil || app.tagFilter != "privacy" {
+		t.Fatalf("expected topic filter")
+	}
+	if !strings.Contains(render(app), "Topics: privacy") {
+		t.Fatalf("expected topics in render")
+	}
+	if err := handleCommand(app, "topic", &out); err != nil || app.tagFilter != "" {
+		t.Fatalf("expected topic filter cleared")
+	}
+}
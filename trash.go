@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (a *App) TrashEntries() []Deleted {
+	deleted := a.store.Deleted()
+	for i, j := 0, len(deleted)-1; i < j; i, j = i+1, j-1 {
+		deleted[i], deleted[j] = deleted[j], deleted[i]
+	}
+	return deleted
+}
+
+func (a *App) RestoreDeleted(id int) error {
+	article, err := a.store.RestoreDeleted(id)
+	if err != nil {
+		return messageErr(msgTrashNotFound, id)
+	}
+	a.lastDeleted = nil
+	delete(a.summaryPending, article.ID)
+	a.loadArticles()
+	a.status = tr(msgTrashRestored, article.Title)
+	a.syncSummaryForSelection()
+	return nil
+}
+
+func (a *App) PurgeDeleted(ids []int) error {
+	purged, err := a.store.PurgeDeleted(ids)
+	if err != nil {
+		return err
+	}
+	a.lastDeleted = nil
+	a.status = tr(msgTrashPurged, purged)
+	return nil
+}
+
+func formatTrashEntry(entry Deleted) string {
+	return fmt.Sprintf("%d %s (%s, deleted %s)", entry.ID, valueOrFallback(entry.Article.Title, entry.Article.URL), valueOrFallback(entry.Article.FeedTitle, "Unknown"), formatLocalTime(entry.DeletedAt))
+}
+
+func formatTrash(entries []Deleted) string {
+	if len(entries) == 0 {
+		return tr(msgTrashEmpty)
+	}
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, formatTrashEntry(entry))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTrashApp(t *testing.T) *App {
+	t.Helper()
+	app, _, _ := newFolderApp(t)
+	for range 3 {
+		if err := app.DeleteSelected(); err != nil {
+			t.Fatalf("DeleteSelected error: %v", err)
+		}
+	}
+	return app
+}
+
+func TestTrashRestoreAndPurge(t *testing.T) {
+	app := newTrashApp(t)
+	entries := app.TrashEntries()
+	if len(entries) != 3 || len(app.articles) != 0 || entries[0].ID < entries[2].ID {
+		t.Fatalf("expected newest deletion first, got %+v", entries)
+	}
+	var out strings.Builder
+	if err := handleCommand(app, "trash", &out); err != nil || !strings.Contains(out.String(), strconv.Itoa(entries[1].ID)+" "+entries[1].Article.Title+" (") {
+		t.Fatalf("unexpected trash listing %q %v", out.String(), err)
+	}
+	if err := handleCommand(app, "restore "+strconv.Itoa(entries[1].ID), io.Discard); err != nil {
+		t.Fatalf("restore error: %v", err)
+	}
+	if app.status != "Restored "+entries[1].Article.Title || len(app.articles) != 1 {
+		t.Fatalf("unexpected restore %q %+v", app.status, app.articles)
+	}
+	if err := app.RestoreDeleted(entries[1].ID); err == nil || err.Error() != "no deleted article "+strconv.Itoa(entries[1].ID) {
+		t.Fatalf("expected missing error, got %v", err)
+	}
+	if err := handleCommand(app, "purge "+strconv.Itoa(entries[0].ID), io.Discard); err != nil || app.status != "Purged 1 deleted articles" {
+		t.Fatalf("unexpected purge %q %v", app.status, err)
+	}
+	if err := handleCommand(app, "purge all", io.Discard); err != nil || app.status != "Purged 1 deleted articles" || len(app.TrashEntries()) != 0 {
+		t.Fatalf("unexpected purge all %q %v", app.status, err)
+	}
+	if got := formatTrash(nil); got != "Trash is empty." {
+		t.Fatalf("unexpected empty trash %q", got)
+	}
+	for _, command := range []string{"restore", "restore x", "purge", "purge 1 x"} {
+		if err := handleCommand(app, command, io.Discard); err == nil {
+			t.Fatalf("expected usage error for %q", command)
+		}
+	}
+}
+
+func TestTUITrashView(t *testing.T) {
+	app := newTrashApp(t)
+	model := newTUIModel(app)
+	model.width, model.height = 140, 30
+	press := func(key string) {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		model = updated.(tuiModel)
+	}
+	press("X")
+	if !model.showTrash || !strings.Contains(model.View(), "Trash (3)") || !strings.Contains(model.View(), "u restore, d purge") {
+		t.Fatalf("expected trash view:\n%s", model.View())
+	}
+	press("j")
+	press("u")
+	if len(model.app.articles) != 1 || !strings.HasPrefix(model.app.status, "Restored ") {
+		t.Fatalf("unexpected restore %q", model.app.status)
+	}
+	press("d")
+	if !strings.HasPrefix(model.app.status, "Press d again") || len(model.app.TrashEntries()) != 2 {
+		t.Fatalf("expected purge confirmation, got %q", model.app.status)
+	}
+	press("d")
+	if model.app.status != "Purged 1 deleted articles" {
+		t.Fatalf("unexpected status %q", model.app.status)
+	}
+	press("D")
+	press("j")
+	press("D")
+	if len(model.app.TrashEntries()) != 1 {
+		t.Fatalf("expected confirmation reset by other keys")
+	}
+	press("D")
+	press("D")
+	if len(model.app.TrashEntries()) != 0 || !strings.Contains(model.View(), "Trash is empty.") {
+		t.Fatalf("expected trash emptied:\n%s", model.View())
+	}
+	press("X")
+	if model.showTrash {
+		t.Fatalf("expected trash closed")
+	}
+}
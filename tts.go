@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultTTSPlayer = "mpv --no-terminal --really-quiet -"
+
+var (
+	ttsLookPath = exec.LookPath
+	ttsRun      = func(ctx context.Context, name string, args []string, input io.Reader) error {
+		command := exec.CommandContext(ctx, name, args...)
+		command.Stdin = input
+		return command.Run()
+	}
+)
+
+type Speaker struct {
+	engine  string
+	command string
+	voice   string
+	apiURL  string
+	apiKey  string
+	model   string
+	player  string
+	client  *http.Client
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	playing int
+}
+
+func NewSpeaker(cfg Config) *Speaker {
+	return &Speaker{
+		engine:  strings.TrimSpace(cfg.TTSEngine),
+		command: strings.TrimSpace(cfg.TTSCommand),
+		voice:   strings.TrimSpace(cfg.TTSVoice),
+		apiURL:  strings.TrimRight(strings.TrimSpace(cfg.TTSAPIURL), "/"),
+		apiKey:  strings.TrimSpace(cfg.TTSAPIKey),
+		model:   firstNonEmpty(strings.TrimSpace(cfg.TTSModel), "tts-1"),
+		player:  firstNonEmpty(strings.TrimSpace(cfg.TTSPlayer), defaultTTSPlayer),
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func parseTTSEngine(value string) (string, error) {
+	engine := strings.ToLower(trimQuotes(value))
+	switch engine {
+	case "", "say", "espeak", "command", "api":
+		return engine, nil
+	}
+	return "", fmt.Errorf("invalid tts.engine: %q (expected say, espeak, command, or api)", value)
+}
+
+func ttsEngineForOS(goos string) string {
+	if goos == "darwin" {
+		return "say"
+	}
+	return "espeak"
+}
+
+func (s *Speaker) resolvedEngine() string {
+	if s.engine != "" {
+		return s.engine
+	}
+	if s.command != "" {
+		return "command"
+	}
+	return ttsEngineForOS(runtime.GOOS)
+}
+
+func (s *Speaker) localCommand(engine string) (string, []string, error) {
+	switch engine {
+	case "say":
+		args := []string{"-f", "-"}
+		if s.voice != "" {
+			args = append(args, "-v", s.voice)
+		}
+		return "say", args, nil
+	case "espeak":
+		name := "espeak-ng"
+		if _, err := ttsLookPath(name); err != nil {
+			name = "espeak"
+		}
+		if _, err := ttsLookPath(name); err != nil {
+			return "", nil, errors.New("espeak-ng or espeak not found; set tts.engine or tts.command")
+		}
+		args := []string{"--stdin"}
+		if s.voice != "" {
+			args = append(args, "-v", s.voice)
+		}
+		return name, args, nil
+	case "command":
+		if s.command == "" {
+			return "", nil, errors.New("tts.command not configured")
+		}
+		shell, args := shellCommandForOS(runtime.GOOS, s.command)
+		return shell, args, nil
+	}
+	return "", nil, fmt.Errorf("unknown tts engine: %s", engine)
+}
+
+func (s *Speaker) synthesize(ctx context.Context, text string) ([]byte, error) {
+	if s.apiURL == "" {
+		return nil, errors.New("tts.api_url not configured")
+	}
+	body, err := json.Marshal(map[string]string{"model": s.model, "input": text, "voice": firstNonEmpty(s.voice, "alloy"), "response_format": "mp3"})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiURL+"/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	audio, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("tts api error: %s", strings.TrimSpace(string(audio)))
+	}
+	return audio, nil
+}
+
+func (s *Speaker) Speak(text string) error {
+	if s == nil {
+		return errors.New("text-to-speech not configured")
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errors.New("nothing to read")
+	}
+	engine := s.resolvedEngine()
+	var name string
+	var args []string
+	if engine == "api" {
+		shell, shellArgs := shellCommandForOS(runtime.GOOS, s.player)
+		name, args = shell, shellArgs
+		if s.apiURL == "" {
+			return errors.New("tts.api_url not configured")
+		}
+	} else {
+		var err error
+		if name, args, err = s.localCommand(engine); err != nil {
+			return err
+		}
+	}
+	s.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.cancel = cancel
+	s.playing++
+	generation := s.playing
+	s.mu.Unlock()
+	go func() {
+		defer s.finish(generation)
+		input := io.Reader(strings.NewReader(text))
+		if engine == "api" {
+			audio, err := s.synthesize(ctx, text)
+			if err != nil {
+				if ctx.Err() == nil {
+					logFor("tts").Warn("synthesis failed", "err", err)
+				}
+				return
+			}
+			input = bytes.NewReader(audio)
+		}
+		if err := ttsRun(ctx, name, args, input); err != nil && ctx.Err() == nil {
+			logFor("tts").Warn("playback failed", "engine", engine, "err", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Speaker) finish(generation int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.playing == generation && s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+func (s *Speaker) Speaking() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cancel != nil
+}
+
+func (s *Speaker) Stop() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel == nil {
+		return false
+	}
+	s.cancel()
+	s.cancel = nil
+	return true
+}
+
+func (a *App) speechText(article Article, full bool) (string, bool) {
+	if !full {
+		if summary, ok := a.store.FindSummary(article.ID); ok && strings.TrimSpace(summary.Content) != "" {
+			return article.Title + ".\n" + summary.Content, false
+		}
+	}
+	return strings.Join(append([]string{article.Title + "."}, epubParagraphs(article)...), "\n"), true
+}
+
+func (a *App) SpeakSelected(full bool) error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	text, readArticle := a.speechText(*article, full)
+	if err := a.speaker.Speak(text); err != nil {
+		a.status = tr(msgSpeechFailed, err)
+		return err
+	}
+	if readArticle {
+		a.status = tr(msgSpeakingArticle, article.Title)
+	} else {
+		a.status = tr(msgSpeakingSummary, article.Title)
+	}
+	return nil
+}
+
+func (a *App) StopSpeaking() bool {
+	if !a.speaker.Stop() {
+		return false
+	}
+	a.status = tr(msgSpeechStopped)
+	return true
+}
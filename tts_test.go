@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type ttsCall struct {
+	name  string
+	args  []string
+	input string
+}
+
+func stubTTS(t *testing.T, block bool) (chan ttsCall, *sync.WaitGroup) {
+	t.Helper()
+	origRun, origLook := ttsRun, ttsLookPath
+	t.Cleanup(func() { ttsRun, ttsLookPath = origRun, origLook })
+	ttsLookPath = func(name string) (string, error) {
+		if name == "espeak-ng" {
+			return "", errors.New("not found")
+		}
+		return "/usr/bin/" + name, nil
+	}
+	calls := make(chan ttsCall, 4)
+	var running sync.WaitGroup
+	ttsRun = func(ctx context.Context, name string, args []string, input io.Reader) error {
+		running.Add(1)
+		defer running.Done()
+		data, _ := io.ReadAll(input)
+		calls <- ttsCall{name: name, args: args, input: string(data)}
+		if block {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}
+	return calls, &running
+}
+
+func waitTTSCall(t *testing.T, calls chan ttsCall) ttsCall {
+	t.Helper()
+	select {
+	case call := <-calls:
+		return call
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected speech to start")
+	}
+	return ttsCall{}
+}
+
+func TestParseConfigTTS(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := parseConfig("[tts]\nengine = \"api\"\napi_url = \"https://api.example.com/v1\"\nvoice = \"nova\"\nplayer = \"afplay -\"\n", &cfg); err != nil {
+		t.Fatalf("parseConfig error: %v", err)
+	}
+	if cfg.TTSEngine != "api" || cfg.TTSAPIURL != "https://api.example.com/v1" || cfg.TTSVoice != "nova" || cfg.TTSPlayer != "afplay -" {
+		t.Fatalf("unexpected tts config %+v", cfg)
+	}
+	reparsed := DefaultConfig()
+	if err := parseConfig(renderConfig(cfg), &reparsed); err != nil || reparsed.TTSEngine != "api" || reparsed.TTSPlayer != "afplay -" {
+		t.Fatalf("tts config did not round trip: %+v %v", reparsed, err)
+	}
+	if err := parseConfig("[tts]\nengine = \"festival\"\n", &cfg); err == nil {
+		t.Fatalf("expected invalid engine error")
+	}
+	if err := applyEnvOverrides(&cfg, []string{"GREEDER_TTS_API_KEY=secret"}); err != nil || cfg.TTSAPIKey != "secret" {
+		t.Fatalf("expected env override, got %q %v", cfg.TTSAPIKey, err)
+	}
+}
+
+func TestSpeakerLocalEngines(t *testing.T) {
+	calls, _ := stubTTS(t, false)
+	speaker := NewSpeaker(Config{TTSEngine: "espeak", TTSVoice: "en-gb"})
+	if err := speaker.Speak("Hello there"); err != nil {
+		t.Fatalf("Speak error: %v", err)
+	}
+	call := waitTTSCall(t, calls)
+	if call.name != "espeak" || strings.Join(call.args, " ") != "--stdin -v en-gb" || call.input != "Hello there" {
+		t.Fatalf("unexpected espeak call %+v", call)
+	}
+	speaker = NewSpeaker(Config{TTSCommand: "piper --output-raw | aplay"})
+	if err := speaker.Speak("Hi"); err != nil {
+		t.Fatalf("Speak error: %v", err)
+	}
+	if call := waitTTSCall(t, calls); call.args[len(call.args)-1] != "piper --output-raw | aplay" || call.input != "Hi" {
+		t.Fatalf("unexpected command call %+v", call)
+	}
+	if name, args, err := NewSpeaker(Config{TTSEngine: "say", TTSVoice: "Samantha"}).localCommand("say"); err != nil || name != "say" || strings.Join(args, " ") != "-f - -v Samantha" {
+		t.Fatalf("unexpected say command %s %v %v", name, args, err)
+	}
+	if ttsEngineForOS("darwin") != "say" || ttsEngineForOS("linux") != "espeak" {
+		t.Fatalf("unexpected default engines")
+	}
+	if err := speaker.Speak("  "); err == nil {
+		t.Fatalf("expected empty text error")
+	}
+	var missing *Speaker
+	if err := missing.Speak("x"); err == nil || missing.Stop() || missing.Speaking() {
+		t.Fatalf("expected nil speaker to be inert")
+	}
+}
+
+func TestSpeakerStopCancelsPlayback(t *testing.T) {
+	calls, running := stubTTS(t, true)
+	speaker := NewSpeaker(Config{TTSEngine: "espeak"})
+	if err := speaker.Speak("long text"); err != nil {
+		t.Fatalf("Speak error: %v", err)
+	}
+	waitTTSCall(t, calls)
+	if !speaker.Speaking() {
+		t.Fatalf("expected speaker to be busy")
+	}
+	if !speaker.Stop() || speaker.Speaking() || speaker.Stop() {
+		t.Fatalf("expected one successful stop")
+	}
+	running.Wait()
+}
+
+func TestSpeakerAPIEngine(t *testing.T) {
+	calls, _ := stubTTS(t, false)
+	speaker := NewSpeaker(Config{TTSEngine: "api", TTSAPIURL: "https://api.example.com/v1/", TTSAPIKey: "key", TTSVoice: "nova"})
+	var got map[string]string
+	speaker.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.String() != "https://api.example.com/v1/audio/speech" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s %v", r.URL, r.Header)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		return newResponse(http.StatusOK, "MP3DATA", nil, r), nil
+	})}
+	if err := speaker.Speak("Read me"); err != nil {
+		t.Fatalf("Speak error: %v", err)
+	}
+	call := waitTTSCall(t, calls)
+	if call.input != "MP3DATA" || call.args[len(call.args)-1] != defaultTTSPlayer {
+		t.Fatalf("unexpected player call %+v", call)
+	}
+	if got["input"] != "Read me" || got["voice"] != "nova" || got["model"] != "tts-1" {
+		t.Fatalf("unexpected api payload %v", got)
+	}
+	if err := NewSpeaker(Config{TTSEngine: "api"}).Speak("x"); err == nil {
+		t.Fatalf("expected missing api_url error")
+	}
+}
+
+func TestAppSpeakSelected(t *testing.T) {
+	calls, _ := stubTTS(t, true)
+	app, _ := newSessionApp(t)
+	app.speaker = NewSpeaker(Config{TTSEngine: "espeak"})
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: app.SelectedArticle().ID, Content: "Short summary"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+
+	if err := app.SpeakSelected(false); err != nil {
+		t.Fatalf("SpeakSelected error: %v", err)
+	}
+	if call := waitTTSCall(t, calls); !strings.Contains(call.input, "Short summary") || !strings.HasPrefix(app.status, "Reading summary: ") {
+		t.Fatalf("unexpected summary speech %+v %q", call, app.status)
+	}
+	if !app.StopSpeaking() || app.status != "Stopped reading" || app.StopSpeaking() {
+		t.Fatalf("unexpected stop status %q", app.status)
+	}
+	if err := handleCommand(app, "speak article", io.Discard); err != nil {
+		t.Fatalf("speak command error: %v", err)
+	}
+	if call := waitTTSCall(t, calls); strings.Contains(call.input, "Short summary") || !strings.HasPrefix(app.status, "Reading article: ") {
+		t.Fatalf("unexpected article speech %+v %q", call, app.status)
+	}
+
+	var model tea.Model = newTUIModel(app)
+	press := func(key tea.KeyMsg) {
+		model, _ = model.Update(key)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.speaker.Speaking() || app.status != "Stopped reading" {
+		t.Fatalf("expected esc to stop reading, got %q", app.status)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	waitTTSCall(t, calls)
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if app.speaker.Speaking() {
+		t.Fatalf("expected p to toggle reading off")
+	}
+	app.speaker = NewSpeaker(Config{TTSEngine: "command"})
+	if err := app.SpeakSelected(false); err == nil || !strings.HasPrefix(app.status, "Text-to-speech failed: ") {
+		t.Fatalf("expected failure status, got %v %q", err, app.status)
+	}
+}
@@ -34,7 +34,7 @@ func handleCommand(app *App, line string, out io.Writer) error {
 	}
 	switch parts[0] {
 	case "q", "quit":
-		app.store.Compact(7)
+		app.applyRetention()
 		return nil
 	case "j", "down":
 		app.MoveSelection(1)
@@ -42,18 +42,92 @@ func handleCommand(app *App, line string, out io.Writer) error {
 		app.MoveSelection(-1)
 	case "enter":
 		return app.GenerateSummary()
+	case "R", "regenerate":
+		return app.RegenerateSummary(strings.Join(parts[1:], " "))
 	case "r", "refresh":
+		if len(parts) > 1 {
+			feedID, err := parseFeedID(parts[1])
+			if err != nil {
+				return err
+			}
+			return app.RefreshFeed(feedID)
+		}
 		return app.RefreshFeeds()
+	case "sync":
+		return app.SyncAccounts()
+	case "feeds":
+		fmt.Fprintln(out, formatFeeds(app.feeds, app.unreadCounts()))
+	case "rename":
+		if len(parts) < 3 {
+			return fmt.Errorf("usage: rename <feed-id> <title>")
+		}
+		feedID, err := parseFeedID(parts[1])
+		if err != nil {
+			return err
+		}
+		return app.RenameFeed(feedID, strings.Join(parts[2:], " "))
+	case "feed-url":
+		if len(parts) < 3 {
+			return fmt.Errorf("usage: feed-url <feed-id> <url>")
+		}
+		feedID, err := parseFeedID(parts[1])
+		if err != nil {
+			return err
+		}
+		return app.SetFeedURL(feedID, parts[2])
+	case "remove":
+		if len(parts) < 2 {
+			return fmt.Errorf("missing feed id")
+		}
+		feedID, err := parseFeedID(parts[1])
+		if err != nil {
+			return err
+		}
+		return app.RemoveFeed(feedID)
 	case "a", "add":
 		if len(parts) < 2 {
 			return fmt.Errorf("missing feed url")
 		}
-		return app.AddFeed(parts[1])
-	case "i", "import":
+		if err := app.AddFeed(parts[1]); err != nil {
+			return err
+		}
+		if app.bridgeOffer != "" {
+			fmt.Fprintln(out, app.status+" (run 'bridge' to add it)")
+		}
+		if len(app.feedChoices) > 0 {
+			fmt.Fprintln(out, app.status+" (run 'pick <n>')")
+			for i, link := range app.feedChoices {
+				fmt.Fprintf(out, "%d. %s\n", i+1, formatFeedLink(link))
+			}
+		}
+		return nil
+	case "bridge":
+		return app.AcceptBridgeOffer()
+	case "pick":
 		if len(parts) < 2 {
+			return fmt.Errorf("missing feed number")
+		}
+		number, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid feed number: %s", parts[1])
+		}
+		return app.AcceptFeedChoice(number - 1)
+	case "i", "import":
+		path, dryRun := parseImportArgs(parts[1:])
+		if path == "" {
 			return fmt.Errorf("missing opml path")
 		}
-		return app.ImportOPML(parts[1])
+		var report opmlImportReport
+		var err error
+		if dryRun {
+			report, err = app.PlanOPMLImport(path)
+		} else {
+			report, err = app.ImportOPMLReport(path)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, formatOPMLReport(report))
 	case "w", "export":
 		if len(parts) < 2 {
 			return fmt.Errorf("missing opml path")
@@ -71,8 +145,78 @@ func handleCommand(app *App, line string, out io.Writer) error {
 		return app.ExportState(parts[1])
 	case "s", "star":
 		return app.ToggleStar()
+	case "Q", "queue":
+		return app.ToggleQueue()
+	case "L", "queue-view":
+		app.ToggleQueueView()
+	case "K", "queue-up":
+		return app.MoveQueued(-1)
+	case "J", "queue-down":
+		return app.MoveQueued(1)
 	case "m", "mark":
 		return app.ToggleRead()
+	case "A", "read-all":
+		return app.MarkAllRead()
+	case "star-matching":
+		if len(parts) < 2 {
+			return fmt.Errorf("usage: star-matching <text>")
+		}
+		return app.StarMatching(strings.Join(parts[1:], " "))
+	case "delete-read":
+		if len(parts) != 2 {
+			return fmt.Errorf("usage: delete-read <feed-id>")
+		}
+		feedID, err := parseFeedID(parts[1])
+		if err != nil {
+			return err
+		}
+		return app.DeleteReadInFeed(feedID)
+	case "trash":
+		fmt.Fprintln(out, formatTrash(app.TrashEntries()))
+	case "restore":
+		if len(parts) != 2 {
+			return fmt.Errorf("usage: restore <deleted-id>")
+		}
+		id, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid deleted id: %s", parts[1])
+		}
+		return app.RestoreDeleted(id)
+	case "purge":
+		if len(parts) < 2 {
+			return fmt.Errorf("usage: purge <deleted-id...|all>")
+		}
+		ids := []int{}
+		if len(parts) == 2 && parts[1] == "all" {
+			for _, entry := range app.TrashEntries() {
+				ids = append(ids, entry.ID)
+			}
+			return app.PurgeDeleted(ids)
+		}
+		for _, part := range parts[1:] {
+			id, err := strconv.Atoi(part)
+			if err != nil {
+				return fmt.Errorf("invalid deleted id: %s", part)
+			}
+			ids = append(ids, id)
+		}
+		return app.PurgeDeleted(ids)
+	case "saved":
+		if len(parts) == 1 {
+			fmt.Fprintln(out, formatSavedEntries(app.SavedEntries()))
+			return nil
+		}
+		if len(parts) != 3 || (parts[1] != "open" && parts[1] != "sync") {
+			return fmt.Errorf("usage: saved [open|sync <article-id>]")
+		}
+		articleID, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return fmt.Errorf("invalid article id: %s", parts[2])
+		}
+		if parts[1] == "open" {
+			return app.OpenSaved(articleID)
+		}
+		return app.ResyncSavedTags(articleID)
 	case "o", "open":
 		return app.OpenSelected()
 	case "O", "open-starred":
@@ -80,15 +224,106 @@ func handleCommand(app *App, line string, out io.Writer) error {
 	case "e", "email":
 		return app.EmailSelected()
 	case "y", "copy":
-		return app.CopySelectedURL()
+		if len(parts) < 2 {
+			return app.CopySelectedURL()
+		}
+		switch parts[1] {
+		case "url":
+			return app.CopySelectedURL()
+		case "summary":
+			return app.CopySelectedSummary()
+		case "markdown", "md":
+			return app.CopySelectedMarkdown()
+		case "content", "text":
+			return app.CopySelectedContent()
+		}
+		return fmt.Errorf("usage: copy [url|summary|markdown|content]")
+	case "speak", "say":
+		return app.SpeakSelected(len(parts) > 1 && parts[1] == "article")
+	case "stop":
+		app.StopSpeaking()
+		return nil
+	case "N", "note":
+		return app.ExportSelectedNote()
+	case "notes":
+		_, err := app.ExportStarredNotes()
+		return err
+	case "x", "export-article":
+		format := ""
+		if len(parts) > 1 {
+			parsed, err := parseExportFormat(parts[1])
+			if err != nil {
+				return err
+			}
+			format = parsed
+		}
+		return app.ExportSelectedArticle(format)
+	case "V", "vault":
+		return app.AppendSelectedToVault()
+	case "bibtex":
+		if len(parts) < 2 {
+			return fmt.Errorf("missing bibtex path")
+		}
+		_, err := app.ExportBibTeX(parts[1])
+		return err
+	case "epub":
+		selection, path, err := parseEPUBArgs(parts[1:])
+		if err != nil {
+			return err
+		}
+		_, err = app.ExportEPUB(path, selection)
+		return err
+	case "pdf":
+		selection, articleID, path, err := parsePDFArgs(parts[1:])
+		if err != nil {
+			return err
+		}
+		_, err = app.ExportPDF(path, selection, articleID)
+		return err
+	case "zotero":
+		_, err := app.SaveStarredToZotero()
+		return err
 	case "b", "bookmark":
 		tags := []string{}
 		if len(parts) > 1 {
 			tags = strings.Split(parts[1], ",")
 		}
-		return app.SaveToRaindrop(tags)
+		return app.SaveBookmark(tags)
+	case "S", "share":
+		if len(parts) < 2 {
+			return fmt.Errorf("missing save target (%s)", strings.Join(app.ShareTargets(), ", "))
+		}
+		target, ok := app.resolveShareTarget(parts[1])
+		if !ok {
+			return fmt.Errorf("unknown save target: %s", parts[1])
+		}
+		tags := []string{}
+		if len(parts) > 2 {
+			tags = strings.Split(parts[2], ",")
+		}
+		return app.SaveBookmarkTo(target, tags)
+	case "C", "collection":
+		return app.SetRaindropCollection(strings.Join(parts[1:], " "))
+	case "collections":
+		collections, err := app.raindrop.ListCollections()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, formatRaindropCollections(collections))
 	case "f", "filter":
 		app.ToggleFilter()
+	case "mute":
+		if len(parts) < 2 {
+			if len(app.config.MutedWords) == 0 {
+				fmt.Fprintln(out, "No muted words.")
+			} else {
+				fmt.Fprintln(out, strings.Join(app.config.MutedWords, ", "))
+			}
+			return nil
+		}
+		return app.SetMutedWords(strings.Join(parts[1:], " "))
+	case "h", "muted":
+		app.ToggleShowMuted()
 	case "d", "delete":
 		return app.DeleteSelected()
 	case "u", "undelete":
@@ -104,12 +339,103 @@ func handleCommand(app *App, line string, out io.Writer) error {
 		return app.UndeleteByPublishedDays(days)
 	case "G", "bulk":
 		return app.GenerateMissingSummaries()
+	case "D", "digest":
+		digest, err := app.GenerateDigest()
+		if err != nil {
+			return err
+		}
+		if len(parts) > 1 {
+			return WriteDigest(parts[1], digest)
+		}
+		fmt.Fprintln(out, renderDigestMarkdown(digest))
+	case "H", "digests":
+		digests := app.store.Digests()
+		if len(digests) == 0 {
+			fmt.Fprintln(out, tr(msgDigestNoneStored))
+			return nil
+		}
+		if len(parts) > 1 {
+			index, err := strconv.Atoi(parts[1])
+			if err != nil || index < 1 || index > len(digests) {
+				return fmt.Errorf("invalid digest number: %s", parts[1])
+			}
+			fmt.Fprintln(out, renderDigestMarkdown(digests[index-1]))
+			return nil
+		}
+		for i, digest := range digests {
+			fmt.Fprintf(out, "%d. %s (%d articles)\n", i+1, digestTitle(digest), len(digest.Articles))
+		}
+	case "c", "ask":
+		if len(parts) < 2 {
+			return fmt.Errorf("missing question")
+		}
+		answer, err := app.AskSelected(nil, strings.Join(parts[1:], " "))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, answer)
+	case "related":
+		article := app.SelectedArticle()
+		if article == nil {
+			return nil
+		}
+		related := app.RelatedArticles(article.ID, 5)
+		if len(related) == 0 {
+			fmt.Fprintln(out, "No related articles.")
+		}
+		for _, item := range related {
+			fmt.Fprintln(out, formatRelatedArticle(item))
+		}
+	case "[", "older":
+		app.CycleSummaryVersion(1)
+	case "]", "newer":
+		app.CycleSummaryVersion(-1)
+	case "z", "sort":
+		app.ToggleSort()
+	case "t", "tag":
+		if len(parts) < 2 {
+			return fmt.Errorf("usage: tag <tag,tag|->")
+		}
+		return app.TagSelected(strings.Join(parts[1:], " "))
+	case "T", "topic":
+		app.SetTagFilter(app.resolveTagChoice(strings.Join(parts[1:], " ")))
+	case "topics", "tags":
+		fmt.Fprintln(out, formatTopicCounts(app.store.TagCounts()))
+	case "folder":
+		if len(parts) < 2 {
+			return fmt.Errorf("missing feed id")
+		}
+		feedID, err := parseFeedID(parts[1])
+		if err != nil {
+			return err
+		}
+		return app.SetFeedFolder(feedID, strings.Join(parts[2:], " "))
+	case "folders":
+		fmt.Fprintln(out, formatFolders(app.feeds))
+	case "scope":
+		scope, err := parseScopeArgs(parts[1:])
+		if err != nil {
+			return err
+		}
+		app.SetFeedScope(scope)
+	case "reload":
+		err := app.ReloadConfig()
+		fmt.Fprintln(out, app.status)
+		return err
 	case "?", "help":
 		fmt.Fprintln(out, helpText())
 	}
 	return nil
 }
 
+func parseFeedID(value string) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid feed id: %s", value)
+	}
+	return id, nil
+}
+
 func render(app *App) string {
 	articles := app.FilteredArticles()
 	leftWidth := 32
@@ -174,6 +500,8 @@ func renderRightPane(article *Article, app *App) []string {
 		lines = append(lines, "  Failed to generate summary")
 	case SummaryGenerated:
 		lines = append(lines, "  "+truncate(app.current.Content, 60))
+	case SummaryStale:
+		lines = append(lines, "  (outdated) "+truncate(app.current.Content, 49))
 	default:
 		lines = append(lines, "  Press Enter to summarize")
 	}
@@ -183,6 +511,15 @@ func renderRightPane(article *Article, app *App) []string {
 	lines = append(lines, "  Feeds: "+formatFeedTitles(sources, article.FeedTitle))
 	lines = append(lines, "  Author: "+valueOrFallback(article.Author, "Unknown"))
 	lines = append(lines, "  URL: "+valueOrFallback(article.URL, "Unknown"))
+	if accounts := formatSourceAccounts(sources); accounts != "" {
+		lines = append(lines, "  Source: "+accounts)
+	}
+	if tags := app.store.ArticleTagsFrom(article.ID, tagSourceUser); len(tags) > 0 {
+		lines = append(lines, "  Tags: "+strings.Join(tags, ", "))
+	}
+	if topics := app.store.ArticleTagsFrom(article.ID, tagSourceTopic); len(topics) > 0 {
+		lines = append(lines, "  Topics: "+strings.Join(topics, ", "))
+	}
 	if app.status != "" {
 		lines = append(lines, "Status: "+app.status)
 	}
@@ -216,8 +553,17 @@ func helpText() string {
 		"  j/k: move",
 		"  enter: summarize",
 		"  G: summarize all missing",
-		"  r: refresh",
+		"  D [path]: daily digest",
+		"  digests [n]: list saved digests or print one",
+		"  c <question>: ask about article",
+		"  r [feed-id]: refresh all feeds or one",
+		"  feeds: list feeds with unread counts, last fetch, and errors",
+		"  rename <feed-id> <title>: rename a feed",
+		"  feed-url <feed-id> <url>: change a feed's url",
+		"  remove <feed-id>: remove a feed and its articles",
 		"  a <url>: add feed",
+		"  bridge: add the offered rss-bridge feed",
+		"  pick <n>: add one of the feeds a site offers",
 		"  i <path>: import opml",
 		"  w <path>: export opml",
 		"  I <path>: import state",
@@ -227,12 +573,41 @@ func helpText() string {
 		"  o: open",
 		"  O: open starred",
 		"  e: email",
-		"  y: copy url",
+		"  y [url|summary|markdown|content]: copy url, summary, markdown link with summary, or article text",
+		"  N: write markdown note to notes_dir",
+		"  notes: write notes for starred articles",
+		"  x [markdown|html]: export the article with its content to notes_dir",
+		"  V: add the article to the vault note",
+		"  speak [article]: read the summary (or article) aloud",
+		"  stop: stop reading aloud",
+		"  bibtex <path>: export starred as bibtex",
+		"  zotero: save starred to zotero",
+		"  epub [starred|unread] <path>: export an epub",
+		"  pdf <id|starred> [path]: export a pdf",
 		"  b <tag,tag>: bookmark",
+		"  S <target> [tag,tag]: share to raindrop/pocket/pinboard/omnivore/archive/mastodon",
+		"  C [name]: raindrop collection (no name resets)",
+		"  collections: list raindrop collections",
 		"  f: filter",
+		"  mute [word,word|-]: list or set muted title words (- clears)",
+		"  h: show/hide muted articles",
+		"  z: toggle newest/ranked sort",
+		"  [ / ]: older/newer summary version",
+		"  related: list related articles",
+		"  t <tag,tag>: tag the article (- clears)",
+		"  T [tag|number]: filter by tag or topic (no tag clears)",
+		"  topics: list tags and topics",
+		"  folder <feed-id> [name]: move a feed to a folder (no name removes it)",
+		"  folders: list feeds by folder",
+		"  scope [folder | feed <id>]: show one folder or feed (no args shows all)",
 		"  d: delete",
 		"  u: undelete",
 		"  U <days>: bulk undelete by days",
+		"  trash: list deleted articles",
+		"  restore <deleted-id>: restore one deleted article",
+		"  purge <deleted-id...|all>: drop deleted articles for good",
+		"  saved [open|sync <article-id>]: list saved articles, open one, or push its tags again",
+		"  reload: re-read the config file",
 		"  q: quit",
 	}, "\n")
 }
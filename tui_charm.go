@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -22,21 +24,69 @@ const (
 	inputExportState
 	inputBookmarkTags
 	inputUndeleteDays
+	inputTopicFilter
+	inputRaindropCollection
+	inputShareTarget
+	inputBridgeFeed
+	inputRenameFeed
+	inputFeedURL
+	inputStarMatching
+	inputTagArticle
+	inputRegenerate
+	inputMuteWords
 )
 
 type spinnerTickMsg struct{}
 
 type summaryResultMsg struct {
-	articleID   int
-	summaryText string
-	model       string
-	err         error
+	articleID        int
+	summaryText      string
+	model            string
+	promptTokens     int
+	completionTokens int
+	contentHash      string
+	err              error
 }
 
 type refreshResultMsg struct {
+	worker *App
+	action messageID
+	err    error
+}
+
+type chatResultMsg struct {
+	articleID int
+	answer    string
+	err       error
+}
+
+type topicsResultMsg struct {
+	articleID int
+	topics    []string
+	err       error
+}
+
+type scoreResultMsg struct {
+	articleID int
+	score     int
+	err       error
+}
+
+type digestResultMsg struct {
+	digest Digest
+	err    error
+}
+
+type emailResultMsg struct {
+	to  string
 	err error
 }
 
+type collectionsResultMsg struct {
+	collections []RaindropCollection
+	err         error
+}
+
 type tuiModel struct {
 	app           *App
 	width         int
@@ -44,12 +94,48 @@ type tuiModel struct {
 	input         textinput.Model
 	inputMode     inputMode
 	showHelp      bool
+	selecting     bool
 	statusHint    string
 	summaryQueue  []Article
 	batchActive   bool
+	batchRunning  map[int]bool
+	batchTotal    int
+	batchDone     int
+	batchFailed   int
 	spinnerIndex  int
 	spinnerFrames []string
 	detailScroll  int
+	digest        Digest
+	digests       []Digest
+	digestIndex   int
+	showDigest    bool
+	digestPending bool
+	collections   []RaindropCollection
+	digestScroll  int
+	showChat      bool
+	chatArticleID int
+	chatHistory   []chatMessage
+	chatPending   bool
+	shareTarget   string
+	showSidebar   bool
+	sidebarIndex  int
+	showFeeds     bool
+	feedIndex     int
+	feedConfirm   int
+	showSaved     bool
+	savedIndex    int
+	showTrash     bool
+	trashIndex    int
+	trashConfirm  int
+	importPlan    *opmlImportReport
+	importPath    string
+	importScroll  int
+	choiceIndex   int
+	keyRemap      map[string]string
+	reloadQueued  bool
+	ctx           context.Context
+	cancel        context.CancelFunc
+	shuttingDown  bool
 }
 
 var (
@@ -64,10 +150,40 @@ func defaultRunTeaProgram(program *tea.Program) (tea.Model, error) {
 	return programRun(program)
 }
 
-func RunTUI(app *App) error {
+func RunTUI(app *App) (err error) {
+	app.restoreSession()
+	app.warnIfDBDamaged()
 	model := newTUIModel(app)
-	program := teaNewProgram(model, tea.WithAltScreen())
-	_, err := runTeaProgram(program)
+	program := teaNewProgram(model, tea.WithAltScreen(), tea.WithoutCatchPanics())
+	reload, stopReload := reloadSignals()
+	terminate, stopTerminate := shutdownSignals()
+	done := make(chan struct{})
+	defer func() {
+		stopReload()
+		stopTerminate()
+		close(done)
+	}()
+	go func() {
+		for {
+			select {
+			case <-reload:
+				program.Send(configReloadMsg{})
+			case <-terminate:
+				program.Send(shutdownMsg{})
+			case <-done:
+				return
+			}
+		}
+	}()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = app.recoverTUI(program, recovered)
+		}
+	}()
+	_, err = runTeaProgram(program)
+	if shutdownErr := app.Shutdown(shutdownTimeout); err == nil {
+		err = shutdownErr
+	}
 	return err
 }
 
@@ -77,20 +193,33 @@ func newTUIModel(app *App) tuiModel {
 	input.CharLimit = 256
 	input.Width = 50
 	input.Prompt = "> "
+	ctx, cancel := context.WithCancel(context.Background())
 	return tuiModel{
+		ctx:           ctx,
+		cancel:        cancel,
 		app:           app,
 		input:         input,
 		spinnerFrames: []string{"|", "/", "-", "\\"},
+		batchRunning:  map[int]bool{},
+		keyRemap:      buildKeyRemap(app.config.Keys),
 	}
 }
 
 func (m tuiModel) Init() tea.Cmd {
-	return tea.Tick(120*time.Millisecond, func(time.Time) tea.Msg {
+	return guardCmd(tea.Tick(120*time.Millisecond, func(time.Time) tea.Msg {
 		return spinnerTickMsg{}
-	})
+	}))
 }
 
 func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if crash, ok := msg.(tuiPanicMsg); ok {
+		panic(crash)
+	}
+	model, cmd := m.update(msg)
+	return model, guardCmd(cmd)
+}
+
+func (m tuiModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -104,32 +233,150 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 	case summaryResultMsg:
 		delete(m.app.summaryPending, msg.articleID)
+		m.finishBatchSummary(msg.articleID, msg.err)
+		cmds := []tea.Cmd{}
 		if msg.err != nil {
 			if selected := m.app.SelectedArticle(); selected != nil && selected.ID == msg.articleID {
 				m.app.summaryStatus = SummaryFailed
+				if errors.Is(msg.err, context.Canceled) {
+					m.app.summaryStatus = SummaryNotGenerated
+				}
 			}
-			m.app.status = "Summary failed: " + msg.err.Error()
+			m.app.status = failureStatus(msgActionSummary, msg.err)
 		} else {
 			summary := Summary{
-				ArticleID:   msg.articleID,
-				Content:     msg.summaryText,
-				Model:       msg.model,
-				GeneratedAt: time.Now().UTC(),
+				ArticleID:        msg.articleID,
+				Content:          msg.summaryText,
+				Model:            msg.model,
+				GeneratedAt:      m.app.now().UTC(),
+				PromptTokens:     msg.promptTokens,
+				CompletionTokens: msg.completionTokens,
+				ContentHash:      msg.contentHash,
 			}
 			stored, err := m.app.store.UpsertSummary(summary)
 			if err != nil {
-				m.app.status = "Summary save failed: " + err.Error()
+				m.app.status = tr(msgSummarySaveFailed, err)
 			} else if selected := m.app.SelectedArticle(); selected != nil && selected.ID == msg.articleID {
 				m.app.current = stored
+				m.app.summaryVersion = 0
 				m.app.summaryStatus = SummaryGenerated
 			}
+			if article := m.app.findArticle(msg.articleID); article != nil && err == nil {
+				cmds = append(cmds, m.app.summaryHookCmd(*article))
+			}
+			if article := m.app.findArticle(msg.articleID); article != nil {
+				if m.app.config.ExtractTopics {
+					cmds = append(cmds, topicsCmd(*article, stored.Content, m.summarizer()))
+				}
+				if m.app.config.RelevanceScoring == "llm" && len(m.app.config.Interests) > 0 {
+					cmds = append(cmds, scoreCmd(*article, m.app.config.Interests, m.summarizer()))
+				}
+			}
+		}
+		if m.shuttingDown {
+			if len(m.app.summaryPending) == 0 {
+				m.cancel()
+				return m, tea.Quit
+			}
+			m.app.status = tr(msgShutdownWaiting, len(m.app.summaryPending))
+			return m, nil
+		}
+		cmds = append(cmds, m.startBatchSummaries())
+		m.reportBatchProgress()
+		return m, tea.Batch(cmds...)
+	case scoreResultMsg:
+		if msg.err != nil {
+			m.app.status = failureStatus(msgActionRelevance, msg.err)
+		} else if err := m.app.storeScore(msg.articleID, msg.score); err != nil {
+			m.app.status = tr(msgScoreSaveFailed, err)
+		}
+	case topicsResultMsg:
+		if msg.err != nil {
+			m.app.status = failureStatus(msgActionTopics, msg.err)
+		} else if err := m.app.storeTopics(msg.articleID, msg.topics); err != nil {
+			m.app.status = tr(msgTopicsSaveFailed, err)
 		}
-		return m, m.startNextBatchSummary()
 	case refreshResultMsg:
 		m.app.refreshPending = false
+		if msg.worker != nil {
+			m.app.applyRefresh(msg.worker)
+		}
+		if msg.err != nil {
+			action := msgActionRefresh
+			if msg.action != "" {
+				action = msg.action
+			}
+			m.app.status = failureStatus(action, msg.err)
+		}
+		if m.reloadQueued {
+			m.reloadQueued = false
+			m.reloadConfig()
+		}
+		return m, nil
+	case shutdownMsg:
+		return m, m.beginShutdown()
+	case shutdownTimeoutMsg:
+		m.cancel()
+		return m, tea.Quit
+	case configReloadMsg:
+		if m.app.refreshPending {
+			m.reloadQueued = true
+			return m, nil
+		}
+		m.reloadConfig()
+		return m, nil
+	case chatResultMsg:
+		if msg.articleID != m.chatArticleID {
+			return m, nil
+		}
+		m.chatPending = false
 		if msg.err != nil {
-			m.app.status = "Refresh failed: " + msg.err.Error()
+			if last := len(m.chatHistory) - 1; last >= 0 && m.chatHistory[last].Role == "user" {
+				m.input.SetValue(m.chatHistory[last].Content)
+				m.chatHistory = m.chatHistory[:last]
+			}
+			m.app.status = failureStatus(msgActionQuestion, msg.err)
+			return m, nil
+		}
+		m.chatHistory = append(m.chatHistory, chatMessage{Role: "assistant", Content: msg.answer})
+		return m, nil
+	case emailResultMsg:
+		if msg.err != nil {
+			m.app.status = tr(msgEmailFailed, msg.err)
+		} else {
+			m.app.status = tr(msgEmailSent, msg.to)
+		}
+		return m, nil
+	case collectionsResultMsg:
+		if msg.err != nil {
+			m.app.status = tr(msgCollectionsUnavailable, msg.err)
+			return m, nil
+		}
+		m.collections = msg.collections
+		titles := make([]string, 0, len(msg.collections))
+		for _, collection := range msg.collections {
+			titles = append(titles, collection.Title)
+		}
+		m.app.status = tr(msgCollectionsList, strings.Join(titles, ", "))
+		m = m.startInput(inputRaindropCollection, "Collection name, id, or default")
+		return m, nil
+	case digestResultMsg:
+		m.digestPending = false
+		if msg.err != nil {
+			m.app.status = failureStatus(msgActionDigest, msg.err)
+			return m, nil
 		}
+		m.digest = msg.digest
+		m.showDigest = true
+		m.digestScroll = 0
+		m.app.status = tr(msgDigestGenerated, len(msg.digest.Articles))
+		if stored, err := m.app.store.SaveDigest(msg.digest); err != nil {
+			m.app.status = tr(msgDigestSaveFailed, err)
+		} else {
+			m.digest = stored
+		}
+		m.digests = m.app.store.Digests()
+		m.digestIndex = 0
 		return m, nil
 	case tea.KeyMsg:
 		key := msg.String()
@@ -139,6 +386,44 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+		if m.showDigest {
+			switch key {
+			case "esc", "q", "D":
+				m.showDigest = false
+			case "j", "down":
+				m.digestScroll++
+			case "k", "up":
+				if m.digestScroll > 0 {
+					m.digestScroll--
+				}
+			case "e":
+				if m.app.config.SMTPHost != "" {
+					return m, m.startSMTPEmail(digestEmail(m.digest, m.app.config))
+				}
+				if err := m.app.EmailDigest(m.digest); err != nil {
+					m.app.status = tr(msgDigestEmailFailed, err)
+				}
+			case "[":
+				m.showStoredDigest(m.digestIndex + 1)
+			case "]":
+				m.showStoredDigest(m.digestIndex - 1)
+			}
+			return m, nil
+		}
+		if m.showChat {
+			var cmd tea.Cmd
+			switch key {
+			case "esc":
+				m.showChat = false
+				m.input.Blur()
+				m.input.SetValue("")
+				return m, nil
+			case "enter":
+				return m, m.askQuestion()
+			}
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
 		if m.inputMode != inputNone {
 			var cmd tea.Cmd
 			switch key {
@@ -148,16 +433,50 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.input.SetValue("")
 				return m, nil
 			case "enter":
+				if m.inputMode == inputRegenerate {
+					return m.commitRegenerate()
+				}
 				m = m.commitInput()
 				return m, nil
 			}
 			m.input, cmd = m.input.Update(msg)
 			return m, cmd
 		}
+		if m.showFeeds {
+			return m.updateFeeds(key)
+		}
+		if m.showSaved {
+			return m.updateSaved(key), nil
+		}
+		if m.showTrash {
+			return m.updateTrash(key), nil
+		}
+		if m.importPlan != nil {
+			return m.updateImportPlan(key), nil
+		}
+		if len(m.app.feedChoices) > 0 {
+			return m.updateFeedChoices(key), nil
+		}
 
+		if key == " " {
+			key = "space"
+		}
+		if mapped, ok := m.keyRemap[key]; ok {
+			key = mapped
+		}
+		if m.showSidebar && m.updateSidebar(key) {
+			return m, nil
+		}
+		if m.selecting {
+			if cmd, ok := m.updateSelection(key); ok {
+				return m, cmd
+			}
+		}
 		switch key {
 		case "ctrl+c", "q":
-			return m, tea.Quit
+			return m, m.beginShutdown()
+		case "esc":
+			m.cancelWork()
 		case "/":
 			m.showHelp = true
 		case "j", "down":
@@ -170,12 +489,27 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if article := m.app.SelectedArticle(); article != nil {
 				return m, m.startSummary(*article)
 			}
+		case "R":
+			if article := m.app.SelectedArticle(); article != nil {
+				if m.app.summarizer == nil {
+					m.app.status = tr(msgSummarizerMissing)
+					break
+				}
+				m = m.startInput(inputRegenerate, "Regenerate summary with model")
+				m.input.SetValue(firstNonEmpty(m.app.summaryOptions(*article).Model, m.app.summarizer.model))
+			}
 		case "r":
 			if !m.app.refreshPending {
 				m.app.refreshPending = true
-				m.app.refreshStatus = "Refreshing feeds..."
+				m.app.refreshStatus = tr(msgRefreshRunning)
 				m.detailScroll = 0
-				return m, refreshCmd(m.app)
+				return m, refreshCmd(m.app, m.ctx)
+			}
+		case "Y":
+			if !m.app.refreshPending {
+				m.app.refreshPending = true
+				m.app.refreshStatus = tr(msgSyncRunning)
+				return m, syncCmd(m.app, m.ctx)
 			}
 		case "a":
 			m = m.startInput(inputAddFeed, "Add feed URL")
@@ -188,24 +522,130 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "E":
 			m = m.startInput(inputExportState, "Export state path")
 		case "b":
-			m = m.startInput(inputBookmarkTags, "Raindrop tags (comma separated)")
+			placeholder := m.app.saveTargetName() + " tags (comma separated)"
+			if m.app.collection.ID != 0 {
+				placeholder += " -> " + m.app.collection.Title
+			}
+			m = m.startBookmarkInput(placeholder)
+		case "S":
+			targets := m.app.ShareTargets()
+			if len(targets) == 0 {
+				m.app.status = tr(msgSaveNoTargets)
+			} else {
+				options := make([]string, len(targets))
+				for i, target := range targets {
+					options[i] = fmt.Sprintf("%d %s", i+1, m.app.targetName(target))
+				}
+				m = m.startInput(inputShareTarget, strings.Join(options, ", "))
+			}
+		case "C":
+			return m, m.startCollections()
 		case "U":
 			m = m.startInput(inputUndeleteDays, "Undelete by days")
+		case "T":
+			if m.app.tagFilter != "" {
+				m.app.SetTagFilter("")
+				m.detailScroll = 0
+			} else {
+				m.app.status = formatTagChoices(m.app.tagChoices())
+				m = m.startInput(inputTopicFilter, "Tag name or number")
+			}
+		case "t":
+			if article := m.app.SelectedArticle(); article != nil {
+				m = m.startInput(inputTagArticle, "Tags (comma separated, - clears)")
+				m.input.SetValue(strings.Join(m.app.store.ArticleTagsFrom(article.ID, tagSourceUser), ", "))
+			}
 		case "s":
 			_ = m.app.ToggleStar()
+		case "Q":
+			if err := m.app.ToggleQueue(); err != nil {
+				m.app.status = failureStatus(msgActionQueue, err)
+			}
+		case "L":
+			m.app.ToggleQueueView()
+			m.detailScroll = 0
+		case "v", "space":
+			m.selecting = true
+			m.app.ToggleSelection()
+		case "K", "J":
+			delta := -1
+			if key == "J" {
+				delta = 1
+			}
+			if err := m.app.MoveQueued(delta); err != nil {
+				m.app.status = failureStatus(msgActionQueue, err)
+			}
 		case "m":
 			_ = m.app.ToggleRead()
+		case "A":
+			if err := m.app.MarkAllRead(); err != nil {
+				m.app.status = failureStatus(msgActionMarkAllRead, err)
+			}
+			m.detailScroll = 0
+		case "*":
+			m = m.startInput(inputStarMatching, "Star articles matching")
 		case "o":
 			_ = m.app.OpenSelected()
 		case "O":
 			_ = m.app.OpenStarred()
 		case "e":
+			if article := m.app.SelectedArticle(); article != nil && m.app.config.SMTPHost != "" {
+				return m, m.startSMTPEmail(articleEmail(article, m.app.current, m.app.config))
+			}
 			_ = m.app.EmailSelected()
 		case "y":
 			_ = m.app.CopySelectedURL()
+		case "ctrl+y":
+			_ = m.app.CopySelectedSummary()
+		case "M":
+			_ = m.app.CopySelectedMarkdown()
+		case "alt+y":
+			_ = m.app.CopySelectedContent()
+		case "N":
+			_ = m.app.ExportSelectedNote()
+		case "x":
+			_ = m.app.ExportSelectedArticle("")
+		case "V":
+			_ = m.app.AppendSelectedToVault()
+		case "p":
+			if !m.app.StopSpeaking() {
+				_ = m.app.SpeakSelected(false)
+			}
+		case "P":
+			m.app.StopSpeaking()
+			_ = m.app.SpeakSelected(true)
 		case "f":
 			m.app.ToggleFilter()
 			m.detailScroll = 0
+		case "W":
+			m = m.startInput(inputMuteWords, "Muted words (comma separated, - clears)")
+			m.input.SetValue(strings.Join(m.app.config.MutedWords, ", "))
+			m.input.CursorEnd()
+		case "h":
+			m.app.ToggleShowMuted()
+			m.detailScroll = 0
+		case "tab":
+			m.openSidebar()
+		case "B":
+			m.showSaved = true
+			m.savedIndex = 0
+		case "X":
+			m.showTrash = true
+			m.trashIndex = 0
+			m.trashConfirm = 0
+		case "F":
+			m.showFeeds = true
+			m.feedConfirm = 0
+			m.feedIndex = clamp(m.feedIndex, 0, max(len(m.app.feeds)-1, 0))
+		case "z":
+			m.app.ToggleSort()
+			m.detailScroll = 0
+		case "[":
+			m.app.CycleSummaryVersion(1)
+			m.detailScroll = 0
+		case "]":
+			m.app.CycleSummaryVersion(-1)
+			m.detailScroll = 0
 		case "d":
 			_ = m.app.DeleteSelected()
 			m.detailScroll = 0
@@ -214,7 +654,19 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.detailScroll = 0
 		case "G":
 			m.queueMissingSummaries()
-			return m, m.startNextBatchSummary()
+			return m, m.startBatchSummaries()
+		case "D":
+			return m, m.startDigest()
+		case "H":
+			m.digests = m.app.store.Digests()
+			if len(m.digests) == 0 {
+				m.app.status = tr(msgDigestNoneStored)
+				break
+			}
+			m.showDigest = true
+			m.showStoredDigest(0)
+		case "c":
+			m = m.openChat()
 		case "pgup", "ctrl+u":
 			m.adjustDetailScroll(-3)
 		case "pgdown", "ctrl+d":
@@ -228,50 +680,144 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m *tuiModel) updateSelection(key string) (tea.Cmd, bool) {
+	switch key {
+	case "space":
+		m.app.ToggleSelection()
+	case "v", "esc":
+		m.endSelection()
+	case "enter":
+		m.queueSummaries(m.app.selectionArticles())
+		m.endSelection()
+		return m.startBatchSummaries(), true
+	case "m":
+		if err := m.app.MarkSelectionRead(); err != nil {
+			m.app.status = failureStatus(msgActionMarkRead, err)
+		}
+		m.endSelection()
+	case "d":
+		if err := m.app.DeleteSelection(); err != nil {
+			m.app.status = failureStatus(msgActionDelete, err)
+		}
+		m.endSelection()
+		m.detailScroll = 0
+	case "b":
+		*m = m.startInput(inputBookmarkTags, fmt.Sprintf("%s tags for %d articles (comma separated)", m.app.saveTargetName(), m.app.SelectionCount()))
+	case "t":
+		*m = m.startInput(inputTagArticle, fmt.Sprintf("Tags for %d articles (comma separated, - clears)", m.app.SelectionCount()))
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+func (m *tuiModel) endSelection() {
+	m.selecting = false
+	m.app.ClearSelection()
+}
+
 func (m *tuiModel) queueMissingSummaries() {
+	m.queueSummaries(m.app.articles)
+}
+
+func (m *tuiModel) queueSummaries(articles []Article) {
 	if m.app.summarizer == nil {
 		m.app.summaryStatus = SummaryNoConfig
-		m.app.status = "Summarizer not configured"
+		m.app.status = tr(msgSummarizerMissing)
 		return
 	}
-	existing := map[int]bool{}
+	existing := map[int]Summary{}
 	for _, summary := range m.app.store.Summaries() {
-		existing[summary.ArticleID] = true
+		existing[summary.ArticleID] = summary
 	}
 	m.summaryQueue = m.summaryQueue[:0]
-	for _, article := range m.app.articles {
-		if existing[article.ID] || m.app.summaryPending[article.ID] {
+	for _, article := range articles {
+		if summary, ok := existing[article.ID]; ok && m.app.summaryIsCurrent(summary, article) {
+			continue
+		}
+		if m.app.summaryPending[article.ID] {
 			continue
 		}
 		m.summaryQueue = append(m.summaryQueue, article)
 	}
 	if len(m.summaryQueue) == 0 {
-		m.app.status = "No missing summaries"
+		m.app.status = tr(msgSummaryNoneMissing)
 		m.batchActive = false
 		return
 	}
 	m.batchActive = true
-	m.app.status = fmt.Sprintf("Generating %d summaries...", len(m.summaryQueue))
+	m.batchTotal = len(m.summaryQueue) + len(m.batchRunning)
+	m.batchDone = 0
+	m.batchFailed = 0
+	m.app.status = tr(msgSummaryGenerating, len(m.summaryQueue))
 }
 
-func (m *tuiModel) startNextBatchSummary() tea.Cmd {
-	if !m.batchActive || len(m.summaryQueue) == 0 {
+func (m *tuiModel) startBatchSummaries() tea.Cmd {
+	if !m.batchActive {
+		return nil
+	}
+	workers := m.app.config.SummaryWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	cmds := []tea.Cmd{}
+	for len(m.batchRunning) < workers && len(m.summaryQueue) > 0 {
+		article := m.summaryQueue[0]
+		m.summaryQueue = m.summaryQueue[1:]
+		cmd := m.startSummary(article)
+		if cmd == nil {
+			m.batchDone++
+			continue
+		}
+		m.batchRunning[article.ID] = true
+		cmds = append(cmds, cmd)
+	}
+	if len(m.summaryQueue) == 0 && len(m.batchRunning) == 0 {
 		m.batchActive = false
+		m.reportBatchProgress()
 		return nil
 	}
-	article := m.summaryQueue[0]
-	m.summaryQueue = m.summaryQueue[1:]
-	return m.startSummary(article)
+	return tea.Batch(cmds...)
+}
+
+func (m *tuiModel) finishBatchSummary(articleID int, err error) {
+	if !m.batchRunning[articleID] {
+		return
+	}
+	delete(m.batchRunning, articleID)
+	m.batchDone++
+	if err != nil {
+		m.batchFailed++
+	}
+}
+
+func (m *tuiModel) reportBatchProgress() {
+	if m.batchTotal == 0 {
+		return
+	}
+	if m.batchActive {
+		m.app.status = tr(msgBatchRunning, m.batchDone, m.batchTotal, len(m.batchRunning))
+		if m.batchFailed > 0 {
+			m.app.status = tr(msgBatchRunningFailed, m.batchDone, m.batchTotal, len(m.batchRunning), m.batchFailed)
+		}
+		return
+	}
+	m.app.status = tr(msgBatchDone, m.batchDone-m.batchFailed, m.batchTotal)
+	if m.batchFailed > 0 {
+		m.app.status = tr(msgBatchDoneFailed, m.batchDone-m.batchFailed, m.batchTotal, m.batchFailed)
+	}
+	m.batchTotal = 0
 }
 
 func (m *tuiModel) startSummary(article Article) tea.Cmd {
 	if m.app.summarizer == nil {
 		m.app.summaryStatus = SummaryNoConfig
-		m.app.status = "Summarizer not configured"
+		m.app.status = tr(msgSummarizerMissing)
 		return nil
 	}
-	if summary, ok := m.app.store.FindSummary(article.ID); ok {
+	if summary, ok := m.app.store.FindSummary(article.ID); ok && m.app.summaryIsCurrent(summary, article) {
 		m.app.current = summary
+		m.app.summaryVersion = 0
 		m.app.summaryStatus = SummaryGenerated
 		return nil
 	}
@@ -282,24 +828,475 @@ func (m *tuiModel) startSummary(article Article) tea.Cmd {
 	if selected := m.app.SelectedArticle(); selected != nil && selected.ID == article.ID {
 		m.app.summaryStatus = SummaryGenerating
 	}
-	title := article.Title
+	return summaryCmd(article, m.app.summaryOptions(article), m.app.summaryHash(article), m.summarizer())
+}
+
+func (m tuiModel) commitRegenerate() (tea.Model, tea.Cmd) {
+	model := strings.TrimSpace(m.input.Value())
+	m.inputMode = inputNone
+	m.input.Blur()
+	m.input.SetValue("")
+	article := m.app.SelectedArticle()
+	if article == nil || m.app.summaryPending[article.ID] {
+		return m, nil
+	}
+	opts := m.app.summaryOptions(*article)
+	if model != "" {
+		opts.Model = model
+	}
+	m.app.summaryPending[article.ID] = true
+	m.app.summaryStatus = SummaryGenerating
+	m.app.status = tr(msgSummaryRegenerating, valueOrFallback(opts.Model, m.app.summarizer.model))
+	return m, summaryCmd(*article, opts, m.app.summaryHash(*article), m.summarizer())
+}
+
+func (m tuiModel) openChat() tuiModel {
+	article := m.app.SelectedArticle()
+	if article == nil {
+		return m
+	}
+	if m.app.summarizer == nil {
+		m.app.status = tr(msgSummarizerMissing)
+		return m
+	}
+	if m.chatArticleID != article.ID {
+		m.chatArticleID = article.ID
+		m.chatHistory = nil
+		m.chatPending = false
+	}
+	m.showChat = true
+	m.input.Placeholder = "Ask about this article"
+	m.input.SetValue("")
+	m.input.Focus()
+	return m
+}
+
+func (m *tuiModel) askQuestion() tea.Cmd {
+	question := strings.TrimSpace(m.input.Value())
+	if question == "" || m.chatPending {
+		return nil
+	}
+	article := m.app.SelectedArticle()
+	if article == nil || article.ID != m.chatArticleID {
+		m.showChat = false
+		return nil
+	}
+	history := append([]chatMessage{}, m.chatHistory...)
+	m.chatHistory = append(m.chatHistory, chatMessage{Role: "user", Content: question})
+	m.chatPending = true
+	m.input.SetValue("")
+	return chatCmd(*article, history, question, m.summarizer())
+}
+
+func chatCmd(article Article, history []chatMessage, question string, summarizer *Summarizer) tea.Cmd {
+	return func() tea.Msg {
+		answer, err := summarizer.Ask(article.Title, firstNonEmpty(article.ContentText, article.Content), history, question)
+		return chatResultMsg{articleID: article.ID, answer: answer.Content, err: err}
+	}
+}
+
+func (m *tuiModel) startDigest() tea.Cmd {
+	if m.digestPending {
+		return nil
+	}
+	if m.app.summarizer == nil {
+		m.app.status = tr(msgSummarizerMissing)
+		return nil
+	}
+	articles, summaries := m.app.DigestCandidates()
+	if len(articles) == 0 {
+		m.app.status = tr(msgDigestNoUnread)
+		return nil
+	}
+	m.digestPending = true
+	m.app.status = tr(msgDigestGenerating, len(articles))
+	return digestCmd(articles, summaries, m.app.digestOptions(articles), m.summarizer(), m.app.clock)
+}
+
+func (m *tuiModel) startCollections() tea.Cmd {
+	m.app.status = tr(msgCollectionsLoading)
+	raindrop := m.app.raindrop
+	return func() tea.Msg {
+		collections, err := raindrop.ListCollections()
+		return collectionsResultMsg{collections: collections, err: err}
+	}
+}
+
+func (m *tuiModel) startSMTPEmail(message emailMessage) tea.Cmd {
+	m.app.status = tr(msgEmailSending, message.To)
+	cfg, now := m.app.config, m.app.now()
+	return func() tea.Msg {
+		return emailResultMsg{to: message.To, err: sendSMTP(cfg, message, now)}
+	}
+}
+
+func (m *tuiModel) showStoredDigest(index int) {
+	if index < 0 || index >= len(m.digests) {
+		return
+	}
+	m.digestIndex = index
+	m.digest = m.digests[index]
+	m.digestScroll = 0
+}
+
+func digestCmd(articles []Article, summaries map[int]string, opts DigestOptions, summarizer *Summarizer, clock Clock) tea.Cmd {
+	return func() tea.Msg {
+		result, err := summarizer.GenerateDigest(articles, summaries, opts)
+		if err != nil {
+			return digestResultMsg{err: err}
+		}
+		return digestResultMsg{digest: Digest{Content: result.Content, Model: result.Model, GeneratedAt: clock.Now().UTC(), Articles: articles}}
+	}
+}
+
+func summaryCmd(article Article, opts SummaryOptions, contentHash string, summarizer *Summarizer) tea.Cmd {
 	content := firstNonEmpty(article.ContentText, article.Content)
-	return summaryCmd(article.ID, title, content, m.app.summarizer)
+	return func() tea.Msg {
+		summary, err := summarizer.SummarizeWith(article.Title, content, opts)
+		return summaryResultMsg{
+			articleID:        article.ID,
+			contentHash:      contentHash,
+			summaryText:      summary.Content,
+			model:            summary.Model,
+			promptTokens:     summary.PromptTokens,
+			completionTokens: summary.CompletionTokens,
+			err:              err,
+		}
+	}
 }
 
-func summaryCmd(articleID int, title string, content string, summarizer *Summarizer) tea.Cmd {
+func topicsCmd(article Article, summary string, summarizer *Summarizer) tea.Cmd {
+	text := firstNonEmpty(summary, article.ContentText, article.Content)
 	return func() tea.Msg {
-		summaryText, model, err := summarizer.GenerateSummary(title, content)
-		return summaryResultMsg{articleID: articleID, summaryText: summaryText, model: model, err: err}
+		topics, err := summarizer.ExtractTopics(article.Title, text)
+		return topicsResultMsg{articleID: article.ID, topics: topics, err: err}
 	}
 }
 
-func refreshCmd(app *App) tea.Cmd {
+func scoreCmd(article Article, interests []string, summarizer *Summarizer) tea.Cmd {
+	content := firstNonEmpty(article.ContentText, article.Content)
 	return func() tea.Msg {
-		return refreshResultMsg{err: app.RefreshFeeds()}
+		score, err := summarizer.ScoreRelevance(article.Title, content, interests)
+		return scoreResultMsg{articleID: article.ID, score: score, err: err}
 	}
 }
 
+func refreshFeedCmd(app *App, ctx context.Context, feedID int) tea.Cmd {
+	worker := app.refreshWorker(ctx)
+	return func() tea.Msg {
+		err := worker.RefreshFeed(feedID)
+		if err == nil {
+			err = ctx.Err()
+		}
+		return refreshResultMsg{worker: worker, err: err}
+	}
+}
+
+func refreshCmd(app *App, ctx context.Context) tea.Cmd {
+	worker := app.refreshWorker(ctx)
+	return func() tea.Msg {
+		err := worker.RefreshFeeds()
+		if err == nil {
+			err = ctx.Err()
+		}
+		return refreshResultMsg{worker: worker, err: err}
+	}
+}
+
+func syncCmd(app *App, ctx context.Context) tea.Cmd {
+	worker := app.refreshWorker(ctx)
+	return func() tea.Msg {
+		err := worker.SyncAccounts()
+		if err == nil {
+			err = ctx.Err()
+		}
+		return refreshResultMsg{worker: worker, action: msgActionSync, err: err}
+	}
+}
+
+// reloadConfig swaps in the config file's settings. A refresh worker shares
+// the fetcher client and store with the app, so a reload that arrives while
+// one is running waits for its result.
+func (m *tuiModel) reloadConfig() {
+	_ = m.app.ReloadConfig()
+	m.keyRemap = buildKeyRemap(m.app.config.Keys)
+}
+
+func (m *tuiModel) beginShutdown() tea.Cmd {
+	if m.shuttingDown || len(m.app.summaryPending) == 0 {
+		m.cancel()
+		return tea.Quit
+	}
+	m.shuttingDown = true
+	m.summaryQueue = nil
+	m.batchActive = false
+	m.batchTotal = 0
+	m.app.status = tr(msgShutdownWaiting, len(m.app.summaryPending))
+	return tea.Tick(shutdownTimeout, func(time.Time) tea.Msg {
+		return shutdownTimeoutMsg{}
+	})
+}
+
+func (m tuiModel) summarizer() *Summarizer {
+	return m.app.summarizer.withContext(m.ctx)
+}
+
+func (m *tuiModel) cancelWork() {
+	if m.app.StopSpeaking() {
+		return
+	}
+	if !m.app.refreshPending && len(m.app.summaryPending) == 0 && !m.digestPending && !m.chatPending {
+		return
+	}
+	m.cancel()
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.summaryQueue = nil
+	m.batchActive = false
+	m.batchTotal = 0
+	m.app.status = tr(msgCancelling)
+}
+
+func (m tuiModel) selectedFeed() *Feed {
+	if m.feedIndex < 0 || m.feedIndex >= len(m.app.feeds) {
+		return nil
+	}
+	return &m.app.feeds[m.feedIndex]
+}
+
+func (m tuiModel) updateFeeds(key string) (tuiModel, tea.Cmd) {
+	confirm := m.feedConfirm
+	m.feedConfirm = 0
+	feed := m.selectedFeed()
+	switch key {
+	case "esc", "q", "F":
+		m.showFeeds = false
+	case "j", "down":
+		if m.feedIndex < len(m.app.feeds)-1 {
+			m.feedIndex++
+		}
+	case "k", "up":
+		if m.feedIndex > 0 {
+			m.feedIndex--
+		}
+	case "enter":
+		if feed != nil {
+			m.app.SetFeedScope(feedScope{FeedID: feed.ID})
+			m.showFeeds = false
+			m.detailScroll = 0
+		}
+	case "n":
+		if feed != nil {
+			m = m.startInput(inputRenameFeed, "New title")
+			m.input.SetValue(feed.Title)
+		}
+	case "u":
+		if feed != nil {
+			m = m.startInput(inputFeedURL, "New feed URL")
+			m.input.SetValue(feed.URL)
+		}
+	case "d":
+		if feed == nil {
+			break
+		}
+		if confirm != feed.ID {
+			m.feedConfirm = feed.ID
+			m.app.status = tr(msgFeedConfirmRemove, feed.Title)
+			break
+		}
+		if err := m.app.RemoveFeed(feed.ID); err != nil {
+			m.app.status = failureStatus(msgActionRemoveFeed, err)
+		}
+		m.feedIndex = clamp(m.feedIndex, 0, max(len(m.app.feeds)-1, 0))
+	case "x":
+		if feed != nil {
+			if err := m.app.DeleteReadInFeed(feed.ID); err != nil {
+				m.app.status = failureStatus(msgActionDeleteRead, err)
+			}
+		}
+	case "r":
+		if feed != nil && !m.app.refreshPending {
+			m.app.refreshPending = true
+			m.app.refreshStatus = tr(msgRefreshRunning)
+			return m, refreshFeedCmd(m.app, m.ctx, feed.ID)
+		}
+	}
+	return m, nil
+}
+
+func (m tuiModel) updateSaved(key string) tuiModel {
+	entries := m.app.SavedEntries()
+	m.savedIndex = clamp(m.savedIndex, 0, max(len(entries)-1, 0))
+	var entry *savedEntry
+	if m.savedIndex < len(entries) {
+		entry = &entries[m.savedIndex]
+	}
+	switch key {
+	case "esc", "q", "B":
+		m.showSaved = false
+	case "j", "down":
+		if m.savedIndex < len(entries)-1 {
+			m.savedIndex++
+		}
+	case "k", "up":
+		if m.savedIndex > 0 {
+			m.savedIndex--
+		}
+	case "o", "enter":
+		if entry != nil {
+			_ = m.app.OpenSaved(entry.ArticleID)
+		}
+	case "s":
+		if entry != nil {
+			if err := m.app.ResyncSavedTags(entry.ArticleID); err != nil {
+				m.app.status = failureStatus(msgActionResyncTags, err)
+			}
+		}
+	}
+	return m
+}
+
+func (m tuiModel) updateTrash(key string) tuiModel {
+	confirm := m.trashConfirm
+	m.trashConfirm = 0
+	entries := m.app.TrashEntries()
+	m.trashIndex = clamp(m.trashIndex, 0, max(len(entries)-1, 0))
+	var entry *Deleted
+	if m.trashIndex < len(entries) {
+		entry = &entries[m.trashIndex]
+	}
+	switch key {
+	case "esc", "q", "X":
+		m.showTrash = false
+	case "j", "down":
+		if m.trashIndex < len(entries)-1 {
+			m.trashIndex++
+		}
+	case "k", "up":
+		if m.trashIndex > 0 {
+			m.trashIndex--
+		}
+	case "u", "enter":
+		if entry != nil {
+			if err := m.app.RestoreDeleted(entry.ID); err != nil {
+				m.app.status = failureStatus(msgActionRestore, err)
+			}
+		}
+	case "d":
+		if entry == nil {
+			break
+		}
+		if confirm != entry.ID {
+			m.trashConfirm = entry.ID
+			m.app.status = tr(msgTrashConfirmPurge, valueOrFallback(entry.Article.Title, entry.Article.URL))
+			break
+		}
+		if err := m.app.PurgeDeleted([]int{entry.ID}); err != nil {
+			m.app.status = failureStatus(msgActionPurge, err)
+		}
+	case "D":
+		if len(entries) == 0 {
+			break
+		}
+		if confirm != -1 {
+			m.trashConfirm = -1
+			m.app.status = tr(msgTrashConfirmPurgeAll, len(entries))
+			break
+		}
+		ids := make([]int, 0, len(entries))
+		for _, item := range entries {
+			ids = append(ids, item.ID)
+		}
+		if err := m.app.PurgeDeleted(ids); err != nil {
+			m.app.status = failureStatus(msgActionPurge, err)
+		}
+	}
+	return m
+}
+
+func (m tuiModel) updateImportPlan(key string) tuiModel {
+	switch key {
+	case "esc", "q", "n":
+		m.importPlan = nil
+		m.app.status = tr(msgInputCancelled)
+	case "j", "down":
+		m.importScroll++
+	case "k", "up":
+		if m.importScroll > 0 {
+			m.importScroll--
+		}
+	case "enter", "y":
+		m.importPlan = nil
+		if err := m.app.ImportOPML(m.importPath); err != nil {
+			m.app.status = tr(msgOPMLImportFailed, err)
+		}
+	}
+	return m
+}
+
+func (m tuiModel) updateFeedChoices(key string) tuiModel {
+	m.choiceIndex = clamp(m.choiceIndex, 0, len(m.app.feedChoices)-1)
+	switch key {
+	case "esc", "q":
+		m.app.feedChoices = nil
+		m.app.status = tr(msgInputCancelled)
+	case "j", "down":
+		if m.choiceIndex < len(m.app.feedChoices)-1 {
+			m.choiceIndex++
+		}
+	case "k", "up":
+		if m.choiceIndex > 0 {
+			m.choiceIndex--
+		}
+	case "enter":
+		if err := m.app.AcceptFeedChoice(m.choiceIndex); err != nil {
+			m.app.status = tr(msgFeedAddFailed, err)
+		}
+	}
+	return m
+}
+
+func (m *tuiModel) openSidebar() {
+	m.showSidebar = true
+	m.sidebarIndex = 0
+	for i, entry := range m.app.sidebarEntries() {
+		if entry.scope == m.app.scope {
+			m.sidebarIndex = i
+		}
+	}
+}
+
+func (m *tuiModel) updateSidebar(key string) bool {
+	entries := m.app.sidebarEntries()
+	switch key {
+	case "tab", "esc":
+		m.showSidebar = false
+	case "j", "down":
+		if m.sidebarIndex < len(entries)-1 {
+			m.sidebarIndex++
+		}
+	case "k", "up":
+		if m.sidebarIndex > 0 {
+			m.sidebarIndex--
+		}
+	case "enter":
+		if m.sidebarIndex < len(entries) {
+			m.app.SetFeedScope(entries[m.sidebarIndex].scope)
+		}
+		m.showSidebar = false
+		m.detailScroll = 0
+	default:
+		return false
+	}
+	return true
+}
+
+func failureStatus(action messageID, err error) string {
+	if errors.Is(err, context.Canceled) {
+		return tr(msgActionCancelled, tr(action))
+	}
+	return tr(msgActionFailed, tr(action), err)
+}
+
 func (m tuiModel) View() string {
 	if m.width == 0 || m.height == 0 {
 		return "Loading..."
@@ -309,15 +1306,40 @@ func (m tuiModel) View() string {
 	if m.showHelp {
 		return m.renderHelpOverlay()
 	}
+	if m.showDigest {
+		return m.renderDigestOverlay()
+	}
+	if m.showChat {
+		return m.renderChatOverlay()
+	}
 	if m.inputMode != inputNone {
 		return m.renderInputOverlay(base)
 	}
+	if m.showFeeds {
+		return m.renderFeedsOverlay()
+	}
+	if m.showSaved {
+		return m.renderSavedOverlay()
+	}
+	if m.showTrash {
+		return m.renderTrashOverlay()
+	}
+	if m.importPlan != nil {
+		return m.renderImportOverlay()
+	}
+	if len(m.app.feedChoices) > 0 {
+		return m.renderFeedChoicesOverlay()
+	}
 	return base
 }
 
 func (m tuiModel) renderLayout() string {
 	leftWidth := clamp(int(float64(m.width)*0.32), 24, 40)
-	rightWidth := m.width - leftWidth - 2
+	sidebarWidth := 0
+	if m.showSidebar {
+		sidebarWidth = clamp(int(float64(m.width)*0.2), 18, 30)
+	}
+	rightWidth := m.width - sidebarWidth - leftWidth - 2
 	if rightWidth < 30 {
 		rightWidth = 30
 	}
@@ -329,28 +1351,92 @@ func (m tuiModel) renderLayout() string {
 	}
 	right := m.renderDetails(rightWidth, paneHeight)
 	body := lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	if m.showSidebar {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, m.renderSidebar(sidebarWidth), left, right)
+	}
 	status := m.renderStatusBar(m.width)
 	return lipgloss.JoinVertical(lipgloss.Top, body, status)
 }
 
+func (m tuiModel) renderSidebar(width int) string {
+	style := lipgloss.NewStyle().Width(width).Padding(1, 1, 0, 1)
+	lines := []string{lipgloss.NewStyle().Bold(true).Foreground(m.color("header")).Render("Feeds")}
+	entries := m.app.sidebarEntries()
+	max := m.height - 6
+	if max < 5 {
+		max = 5
+	}
+	offset := 0
+	if m.sidebarIndex >= max {
+		offset = m.sidebarIndex - max + 1
+	}
+	for i := offset; i < len(entries) && i < offset+max; i++ {
+		entry := entries[i]
+		prefix := " "
+		if i == m.sidebarIndex {
+			prefix = "▸"
+		}
+		label := entry.label
+		if entry.scope.Folder != "" {
+			label += "/"
+		}
+		badge := ""
+		if entry.unread > 0 {
+			badge = " " + strconv.Itoa(entry.unread)
+		}
+		line := prefix + " " + strings.Repeat("  ", entry.depth) + truncate(label, width-5-2*entry.depth-len(badge)) + badge
+		if i == m.sidebarIndex {
+			line = lipgloss.NewStyle().Foreground(m.color("selected")).Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
+
 func (m tuiModel) renderList(width int) string {
 	style := lipgloss.NewStyle().Width(width).Padding(1, 1, 0, 1)
-	header := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86")).Render("Greeder")
+	unread, total := m.app.scopeCounts()
+	counts := fmt.Sprintf(" — %d unread / %d total", unread, total)
+	title := "Greeder"
+	if m.app.queueView {
+		title += " · Queue"
+	}
+	if m.app.showMuted && len(m.app.config.MutedWords) > 0 {
+		title += " · Muted shown"
+	}
+	if !m.app.scope.empty() {
+		title += " · " + truncate(m.app.scopeLabel(), width-12-lipgloss.Width(counts))
+	}
+	title += counts
+	header := lipgloss.NewStyle().Bold(true).Foreground(m.color("header")).Render(title)
 	articles := m.app.FilteredArticles()
 	lines := []string{header}
 	max := m.height - 6
 	if max < 5 {
 		max = 5
 	}
-	if len(articles) < max {
-		max = len(articles)
+	start := 0
+	if m.app.selectedIndex >= max {
+		start = m.app.selectedIndex - max + 1
+	}
+	now := m.app.now()
+	end := start + max
+	if end > len(articles) {
+		end = len(articles)
 	}
-	for i := 0; i < max; i++ {
+	for i := start; i < end; i++ {
 		article := articles[i]
 		prefix := " "
 		if i == m.app.selectedIndex {
 			prefix = "▸"
 		}
+		if m.selecting {
+			mark := " "
+			if m.app.selection[article.ID] {
+				mark = "✓"
+			}
+			prefix += mark
+		}
 		flag := ""
 		if article.IsStarred {
 			flag = "★"
@@ -361,14 +1447,17 @@ func (m tuiModel) renderList(width int) string {
 		if m.app.summaryPending[article.ID] && len(m.spinnerFrames) > 0 {
 			spinner = m.spinnerFrames[m.spinnerIndex]
 		}
-		titleWidth := width - 8
+		titleWidth := width - 13
 		if titleWidth < 10 {
 			titleWidth = 10
 		}
 		title := truncate(article.Title, titleWidth)
-		line := fmt.Sprintf("%s %s%s %s", prefix, spinner, flag, title)
+		if m.app.sortMode == SortRanked {
+			title = truncate(fmt.Sprintf("%3d %s", article.Score, article.Title), titleWidth)
+		}
+		line := fmt.Sprintf("%s %s%s %4s %s", prefix, spinner, flag, articleAge(article, now), title)
 		if i == m.app.selectedIndex {
-			line = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render(line)
+			line = lipgloss.NewStyle().Foreground(m.color("selected")).Render(line)
 		}
 		lines = append(lines, line)
 	}
@@ -385,10 +1474,10 @@ func (m tuiModel) renderDetails(width int, height int) string {
 		return style.Render("Select an article to view details.")
 	}
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("33"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.color("title"))
 	contentStyle := lipgloss.NewStyle().Width(width - 2)
-	summaryStyle := lipgloss.NewStyle().Width(width - 2).Foreground(lipgloss.Color("214"))
-	metaStyle := lipgloss.NewStyle().Width(width - 2).Foreground(lipgloss.Color("245"))
+	summaryStyle := lipgloss.NewStyle().Width(width - 2).Foreground(m.color("summary"))
+	metaStyle := lipgloss.NewStyle().Width(width - 2).Foreground(m.color("meta"))
 
 	content := firstNonEmpty(article.ContentText, article.Content)
 	if content == "" {
@@ -422,6 +1511,37 @@ func (m tuiModel) renderDetails(width int, height int) string {
 		metaStyle.Render("Author: " + valueOrFallback(article.Author, "Unknown")),
 		metaStyle.Render("URL: " + valueOrFallback(article.URL, "Unknown")),
 	}
+	if accounts := formatSourceAccounts(sources); accounts != "" {
+		metaSections = append(metaSections, metaStyle.Render("Source: "+accounts))
+	}
+	if snapshot := m.app.store.WaybackSnapshot(article.ID); snapshot != "" {
+		metaSections = append(metaSections, metaStyle.Render("Archived: "+snapshot))
+	}
+	if tags := m.app.store.ArticleTagsFrom(article.ID, tagSourceUser); len(tags) > 0 {
+		metaSections = append(metaSections, metaStyle.Render("Tags: "+strings.Join(tags, ", ")))
+	}
+	if topics := m.app.store.ArticleTagsFrom(article.ID, tagSourceTopic); len(topics) > 0 {
+		metaSections = append(metaSections, metaStyle.Render("Topics: "+strings.Join(topics, ", ")))
+	}
+	if len(m.app.config.Interests) > 0 {
+		metaSections = append(metaSections, metaStyle.Render(fmt.Sprintf("Relevance: %d/100", article.Score)))
+	}
+	if related := m.app.RelatedArticles(article.ID, 3); len(related) > 0 {
+		metaSections = append(metaSections, lipgloss.NewStyle().Bold(true).Render("Related"))
+		for _, item := range related {
+			metaSections = append(metaSections, metaStyle.Render(formatRelatedArticle(item)))
+		}
+	}
+	if versions := m.app.store.SummaryVersions(article.ID); len(versions) > 1 {
+		index := m.app.summaryVersion
+		if index >= len(versions) {
+			index = 0
+		}
+		metaSections = append(metaSections, metaStyle.Render(formatSummaryVersion(index, len(versions), versions[index])+" ([/] to browse)"))
+	}
+	if m.app.summaryStatus == SummaryGenerated && m.app.current.PromptTokens+m.app.current.CompletionTokens > 0 {
+		metaSections = append(metaSections, metaStyle.Render(fmt.Sprintf("Tokens: %d prompt / %d completion", m.app.current.PromptTokens, m.app.current.CompletionTokens)))
+	}
 
 	topHeight := (height - 2) / 2
 	if topHeight < 6 {
@@ -446,16 +1566,18 @@ func (m tuiModel) renderDetails(width int, height int) string {
 }
 
 func (m tuiModel) renderStatusBar(width int) string {
-	style := lipgloss.NewStyle().Width(width).Padding(0, 1).Foreground(lipgloss.Color("241"))
+	style := lipgloss.NewStyle().Width(width).Padding(0, 1).Foreground(m.color("status"))
 	status := m.app.status
+	spinner := ""
+	if len(m.spinnerFrames) > 0 {
+		spinner = m.spinnerFrames[m.spinnerIndex] + " "
+	}
 	if m.app.refreshPending {
-		spinner := ""
-		if len(m.spinnerFrames) > 0 {
-			spinner = m.spinnerFrames[m.spinnerIndex] + " "
-		}
 		status = spinner + m.app.refreshStatus
+	} else if m.digestPending || m.batchActive {
+		status = spinner + status
 	} else if status == "" {
-		status = "Ready"
+		status = tr(msgStatusReady)
 	}
 	tip := m.tooltipText()
 	left := status
@@ -470,40 +1592,310 @@ func (m tuiModel) renderStatusBar(width int) string {
 
 func (m tuiModel) renderHelpOverlay() string {
 	style := lipgloss.NewStyle().Width(m.width).Height(m.height)
-	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(lipgloss.Color("63"))
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(m.color("border"))
 	content := []string{
 		"Quick Commands",
 		"",
 		"j/k or arrows  - navigate",
 		"enter          - summarize",
 		"G              - summarize all",
+		"D              - daily digest",
+		"H              - saved digests",
+		"c              - ask about article",
 		"r              - refresh",
+		"Y              - sync with server only",
 		"a              - add feed",
 		"i              - import OPML",
 		"w              - export OPML",
 		"I              - import state",
 		"E              - export state",
 		"b              - bookmark",
+		"C              - choose Raindrop collection",
+		"S              - share to a save target",
 		"s              - star",
+		"Q              - add to/remove from reading queue",
+		"L              - reading queue view",
+		"v / space      - select several (then m, d, b, t, enter act on all)",
+		"K / J          - move queued article up/down",
 		"m              - mark read",
+		"A              - mark all shown read",
+		"*              - star all shown matching text",
 		"o              - open",
 		"O              - open starred",
 		"e              - email",
 		"y              - copy url",
+		"ctrl+y         - copy summary",
+		"M              - copy markdown link with summary",
+		"alt+y          - copy article text",
+		"N              - write markdown note",
+		"x              - export article to notes_dir",
+		"V              - add article to vault note",
+		"p / P          - read summary/article aloud (p stops)",
 		"pgup/pgdn      - scroll details",
 		"f              - filter",
+		"W              - edit muted words",
+		"h              - show/hide muted articles",
+		"tab            - feeds/folders sidebar (enter shows one)",
+		"B              - saved articles (open, sync tags)",
+		"F              - manage feeds (rename, url, refresh, delete read, remove)",
+		"z              - toggle newest/ranked sort",
+		"R              - regenerate summary (pick a model to compare)",
+		"[ / ]          - older/newer summary version",
+		"t              - tag article",
+		"T              - filter by tag or topic (again clears)",
 		"d              - delete",
 		"u              - undelete",
 		"U              - bulk undelete (days)",
+		"X              - trash (restore or purge deleted articles)",
+		"esc            - cancel refresh/summaries, stop reading",
 		"/ or esc        - close",
 	}
+	if custom := renderKeyBindings(m.app.config.Keys); len(custom) > 0 {
+		content = append(append(content, "", "Custom keys"), custom...)
+	}
 	center := lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(strings.Join(content, "\n")))
 	return style.Render(center)
 }
 
+func (m tuiModel) renderDigestOverlay() string {
+	width := m.width - 8
+	if width < 20 {
+		width = 20
+	}
+	height := m.height - 8
+	if height < 5 {
+		height = 5
+	}
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(m.color("border")).Width(width)
+	title := digestTitle(m.digest)
+	if len(m.digests) > 1 {
+		title += fmt.Sprintf(" (%d/%d)", m.digestIndex+1, len(m.digests))
+	}
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(title), ""}
+	lines = append(lines, wrapText(m.digest.Content, width-6)...)
+	scroll := m.digestScroll
+	visible := visibleLines(lines, height, &scroll)
+	visible = append(visible, "", "j/k scroll, [/] older/newer, e email, esc close")
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(strings.Join(visible, "\n")))
+}
+
+func (m tuiModel) renderFeedsOverlay() string {
+	width := m.width - 8
+	if width < 20 {
+		width = 20
+	}
+	height := m.height - 10
+	if height < 3 {
+		height = 3
+	}
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(m.color("border")).Width(width)
+	errorStyle := lipgloss.NewStyle().Foreground(m.color("status"))
+	unread := m.app.unreadCounts()
+	lines := []string{}
+	for i, feed := range m.app.feeds {
+		prefix := " "
+		if i == m.feedIndex {
+			prefix = "▸"
+		}
+		line := truncate(prefix+" "+formatFeedLine(feed, unread[feed.ID]), width-6)
+		switch {
+		case i == m.feedIndex:
+			line = lipgloss.NewStyle().Foreground(m.color("selected")).Render(line)
+		case feed.LastError != "":
+			line = errorStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No feeds. Press 'a' to add a feed.")
+	}
+	scroll := m.feedIndex - height + 1
+	visible := visibleLines(lines, height, &scroll)
+	content := []string{lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Feeds (%d)", len(m.app.feeds))), ""}
+	content = append(content, visible...)
+	content = append(content, "", "enter show, n rename, u change url, r refresh, x delete read, d remove, esc close")
+	if m.app.status != "" {
+		content = append(content, truncate(m.app.status, width-6))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(strings.Join(content, "\n")))
+}
+
+func (m tuiModel) renderSavedOverlay() string {
+	width := m.width - 8
+	if width < 20 {
+		width = 20
+	}
+	height := m.height - 10
+	if height < 3 {
+		height = 3
+	}
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(m.color("border")).Width(width)
+	entries := m.app.SavedEntries()
+	lines := []string{}
+	for i, entry := range entries {
+		prefix := " "
+		if i == m.savedIndex {
+			prefix = "▸"
+		}
+		line := truncate(prefix+" "+formatSavedEntry(entry), width-6)
+		if i == m.savedIndex {
+			line = lipgloss.NewStyle().Foreground(m.color("selected")).Render(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, tr(msgSavedNone))
+	}
+	scroll := m.savedIndex - height + 1
+	visible := visibleLines(lines, height, &scroll)
+	content := []string{lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Saved (%d)", len(entries))), ""}
+	content = append(content, visible...)
+	content = append(content, "", "o open, s sync tags, esc close")
+	if m.app.status != "" {
+		content = append(content, truncate(m.app.status, width-6))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(strings.Join(content, "\n")))
+}
+
+func (m tuiModel) renderTrashOverlay() string {
+	width := m.width - 8
+	if width < 20 {
+		width = 20
+	}
+	height := m.height - 10
+	if height < 3 {
+		height = 3
+	}
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(m.color("border")).Width(width)
+	entries := m.app.TrashEntries()
+	lines := []string{}
+	for i, entry := range entries {
+		prefix := " "
+		if i == m.trashIndex {
+			prefix = "▸"
+		}
+		line := truncate(prefix+" "+formatTrashEntry(entry), width-6)
+		if i == m.trashIndex {
+			line = lipgloss.NewStyle().Foreground(m.color("selected")).Render(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, tr(msgTrashEmpty))
+	}
+	scroll := m.trashIndex - height + 1
+	visible := visibleLines(lines, height, &scroll)
+	content := []string{lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Trash (%d)", len(entries))), ""}
+	content = append(content, visible...)
+	content = append(content, "", "u restore, d purge, D purge all, esc close")
+	if m.app.status != "" {
+		content = append(content, truncate(m.app.status, width-6))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(strings.Join(content, "\n")))
+}
+
+func (m tuiModel) renderImportOverlay() string {
+	width := m.width - 8
+	if width < 20 {
+		width = 20
+	}
+	height := m.height - 12
+	if height < 3 {
+		height = 3
+	}
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(m.color("dialog_border")).Width(width)
+	lines := []string{}
+	for _, line := range m.importPlan.lines() {
+		lines = append(lines, truncate(line, width-6))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "Nothing to import.")
+	}
+	visible := visibleLines(lines, height, &m.importScroll)
+	content := []string{lipgloss.NewStyle().Bold(true).Render("Import " + m.importPath), ""}
+	content = append(content, visible...)
+	content = append(content, "", m.importPlan.summary(), "enter import, j/k scroll, esc cancel")
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(strings.Join(content, "\n")))
+}
+
+func (m tuiModel) renderFeedChoicesOverlay() string {
+	width := m.width - 8
+	if width < 20 {
+		width = 20
+	}
+	height := m.height - 10
+	if height < 3 {
+		height = 3
+	}
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(m.color("dialog_border")).Width(width)
+	lines := []string{}
+	for i, link := range m.app.feedChoices {
+		prefix := " "
+		if i == m.choiceIndex {
+			prefix = "▸"
+		}
+		line := truncate(prefix+" "+formatFeedLink(link), width-6)
+		if i == m.choiceIndex {
+			line = lipgloss.NewStyle().Foreground(m.color("selected")).Render(line)
+		}
+		lines = append(lines, line)
+	}
+	scroll := m.choiceIndex - height + 1
+	visible := visibleLines(lines, height, &scroll)
+	content := []string{lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Choose a feed (%d)", len(m.app.feedChoices))), ""}
+	content = append(content, visible...)
+	content = append(content, "", "enter add, j/k move, esc cancel")
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(strings.Join(content, "\n")))
+}
+
+func (m tuiModel) renderChatOverlay() string {
+	width := m.width - 8
+	if width < 20 {
+		width = 20
+	}
+	height := m.height - 12
+	if height < 3 {
+		height = 3
+	}
+	title := "Ask"
+	if article := m.app.SelectedArticle(); article != nil {
+		title = "Ask: " + article.Title
+	}
+	youStyle := lipgloss.NewStyle().Foreground(m.color("title"))
+	aiStyle := lipgloss.NewStyle().Foreground(m.color("summary"))
+	conversation := []string{}
+	for _, message := range m.chatHistory {
+		label, style := "You: ", youStyle
+		if message.Role == "assistant" {
+			label, style = "AI: ", aiStyle
+		}
+		for _, line := range wrapText(label+message.Content, width-6) {
+			conversation = append(conversation, style.Render(line))
+		}
+		conversation = append(conversation, "")
+	}
+	if m.chatPending {
+		spinner := ""
+		if len(m.spinnerFrames) > 0 {
+			spinner = m.spinnerFrames[m.spinnerIndex] + " "
+		}
+		conversation = append(conversation, spinner+"Thinking...")
+	}
+	if len(conversation) == 0 {
+		conversation = append(conversation, "Ask a question about the selected article.")
+	}
+	scroll := len(conversation)
+	visible := visibleLines(conversation, height, &scroll)
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(truncate(title, width-6)), ""}
+	lines = append(lines, visible...)
+	lines = append(lines, "", m.input.View(), "", "Enter to ask, Esc to close")
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(m.color("dialog_border")).Width(width)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(strings.Join(lines, "\n")))
+}
+
 func (m tuiModel) renderInputOverlay(base string) string {
 	label := m.inputPrompt()
-	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(lipgloss.Color("62"))
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2).BorderForeground(m.color("dialog_border"))
 	content := label + "\n\n" + m.input.View()
 	overlay := lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box.Render(content))
 	return overlay
@@ -525,15 +1917,40 @@ func (m tuiModel) inputPrompt() string {
 		return "Bookmark Tags"
 	case inputUndeleteDays:
 		return "Undelete Deleted Articles"
+	case inputTopicFilter:
+		return "Filter by Topic"
+	case inputTagArticle:
+		return "Tag Article"
+	case inputRaindropCollection:
+		return "Raindrop Collection"
+	case inputShareTarget:
+		return "Share To"
+	case inputBridgeFeed:
+		return "Add RSS-Bridge Feed"
+	case inputRenameFeed:
+		return "Rename Feed"
+	case inputFeedURL:
+		return "Change Feed URL"
+	case inputStarMatching:
+		return "Star Matching"
+	case inputMuteWords:
+		return "Muted Words"
 	default:
 		return "Input"
 	}
 }
 
+func (m tuiModel) color(name string) lipgloss.Color {
+	return themeColor(m.app.config, name)
+}
+
 func (m tuiModel) tooltipText() string {
 	if m.inputMode != inputNone {
 		return "Enter to confirm, Esc to cancel"
 	}
+	if m.selecting {
+		return tr(msgSelectionCount, m.app.SelectionCount())
+	}
 	return "Press / for help"
 }
 
@@ -550,6 +1967,8 @@ func (m tuiModel) summaryText() string {
 			return m.app.current.Content
 		}
 		return "No summary available."
+	case SummaryStale:
+		return m.app.current.Content + "\n\nArticle content changed since this summary. Press Enter to regenerate."
 	default:
 		return "Press Enter to generate a summary."
 	}
@@ -576,6 +1995,21 @@ func formatFeedTitles(sources []ArticleSource, fallback string) string {
 	return strings.Join(titles, ", ")
 }
 
+func formatSourceAccounts(sources []ArticleSource) string {
+	accounts := []string{}
+	remote := false
+	for _, source := range sources {
+		if source.Source != "" {
+			remote = true
+		}
+		accounts = append(accounts, valueOrFallback(source.Source, "local"))
+	}
+	if !remote {
+		return ""
+	}
+	return strings.Join(uniqueStrings(accounts), ", ")
+}
+
 func formatPublishedTimes(sources []ArticleSource, fallback time.Time) string {
 	times := []string{}
 	for _, source := range sources {
@@ -622,6 +2056,15 @@ func (m tuiModel) startInput(mode inputMode, placeholder string) tuiModel {
 	return m
 }
 
+func (m tuiModel) startBookmarkInput(placeholder string) tuiModel {
+	m = m.startInput(inputBookmarkTags, placeholder)
+	if tags := m.app.suggestedBookmarkTags(); len(tags) > 0 {
+		m.input.SetValue(strings.Join(tags, ", "))
+		m.input.CursorEnd()
+	}
+	return m
+}
+
 func (m tuiModel) commitInput() tuiModel {
 	mode := m.inputMode
 	value := strings.TrimSpace(m.input.Value())
@@ -630,46 +2073,117 @@ func (m tuiModel) commitInput() tuiModel {
 	m.input.SetValue("")
 
 	if value == "" {
-		m.app.status = "Input cancelled"
+		m.app.bridgeOffer = ""
+		m.app.status = tr(msgInputCancelled)
 		return m
 	}
 
 	switch mode {
+	case inputRenameFeed:
+		if feed := m.selectedFeed(); feed != nil {
+			if err := m.app.RenameFeed(feed.ID, value); err != nil {
+				m.app.status = failureStatus(msgActionRenameFeed, err)
+			}
+		}
+	case inputFeedURL:
+		if feed := m.selectedFeed(); feed != nil {
+			if err := m.app.SetFeedURL(feed.ID, value); err != nil {
+				m.app.status = failureStatus(msgActionChangeFeedURL, err)
+			}
+		}
+	case inputStarMatching:
+		if err := m.app.StarMatching(value); err != nil {
+			m.app.status = failureStatus(msgActionStarMatching, err)
+		}
 	case inputAddFeed:
+		m.choiceIndex = 0
 		if err := m.app.AddFeed(value); err != nil {
-			m.app.status = "Add feed failed: " + err.Error()
+			m.app.status = tr(msgFeedAddFailed, err)
+		} else if m.app.bridgeOffer != "" {
+			m = m.startInput(inputBridgeFeed, "Add "+m.app.bridgeOffer+"? (y/n)")
+		}
+	case inputBridgeFeed:
+		if !strings.EqualFold(value, "y") && !strings.EqualFold(value, "yes") {
+			m.app.bridgeOffer = ""
+			m.app.status = tr(msgRSSBridgeNotAdded)
+			return m
+		}
+		if err := m.app.AcceptBridgeOffer(); err != nil {
+			m.app.status = tr(msgFeedAddFailed, err)
 		}
 	case inputImportOPML:
-		if err := m.app.ImportOPML(value); err != nil {
-			m.app.status = "Import failed: " + err.Error()
+		report, err := m.app.PlanOPMLImport(value)
+		if err != nil {
+			m.app.status = tr(msgOPMLImportFailed, err)
+			return m
 		}
+		m.importPlan = &report
+		m.importPath = value
+		m.importScroll = 0
+		m.app.status = report.summary()
 	case inputExportOPML:
 		if err := m.app.ExportOPML(value); err != nil {
-			m.app.status = "Export failed: " + err.Error()
+			m.app.status = tr(msgOPMLExportFailed, err)
 		}
 	case inputImportState:
 		if err := m.app.ImportState(value); err != nil {
-			m.app.status = "State import failed: " + err.Error()
+			m.app.status = tr(msgStateImportFailed, err)
 		}
 	case inputExportState:
 		if err := m.app.ExportState(value); err != nil {
-			m.app.status = "State export failed: " + err.Error()
+			m.app.status = tr(msgStateExportFailed, err)
 		}
 	case inputBookmarkTags:
 		tags := strings.Split(value, ",")
 		for i := range tags {
 			tags[i] = strings.TrimSpace(tags[i])
 		}
-		if err := m.app.SaveToRaindrop(tags); err != nil {
-			m.app.status = "Bookmark failed: " + err.Error()
+		target := firstNonEmpty(m.shareTarget, m.app.config.SaveTarget)
+		m.shareTarget = ""
+		if m.selecting {
+			_ = m.app.BookmarkSelection(target, tags)
+			m.endSelection()
+		} else if err := m.app.SaveBookmarkTo(target, tags); err != nil {
+			m.app.status = tr(msgBookmarkFailed, err)
+		}
+	case inputShareTarget:
+		target, ok := m.app.resolveShareTarget(value)
+		if !ok {
+			m.app.status = tr(msgSaveUnknownTarget, value)
+			return m
 		}
+		m.shareTarget = target
+		m = m.startBookmarkInput(m.app.targetName(target) + " tags (comma separated)")
 	case inputUndeleteDays:
 		days, err := strconv.Atoi(value)
 		if err != nil || days <= 0 {
-			m.app.status = "Invalid days value"
+			m.app.status = tr(msgInputInvalidDays)
 			return m
 		}
 		_ = m.app.UndeleteByPublishedDays(days)
+	case inputTagArticle:
+		var err error
+		if m.selecting {
+			err = m.app.TagSelection(value)
+			m.endSelection()
+		} else {
+			err = m.app.TagSelected(value)
+		}
+		if err != nil {
+			m.app.status = failureStatus(msgActionTag, err)
+		}
+	case inputTopicFilter:
+		m.app.SetTagFilter(m.app.resolveTagChoice(value))
+		m.detailScroll = 0
+	case inputMuteWords:
+		if err := m.app.SetMutedWords(value); err != nil {
+			m.app.status = failureStatus(msgActionMute, err)
+		}
+		m.detailScroll = 0
+	case inputRaindropCollection:
+		if err := m.app.chooseRaindropCollection(value, m.collections); err != nil {
+			m.app.status = tr(msgCollectionFailed, err)
+		}
 	}
 	return m
 }
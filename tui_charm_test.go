@@ -1,11 +1,14 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -77,7 +80,7 @@ func TestRefreshCmdAndStatus(t *testing.T) {
 		t.Fatalf("expected refresh status")
 	}
 
-	msg := refreshCmd(app)()
+	msg := refreshCmd(app, context.Background())()
 	result, ok := msg.(refreshResultMsg)
 	if !ok || result.err != nil {
 		t.Fatalf("expected refresh result")
@@ -306,6 +309,10 @@ func TestTUIInputCommitFlows(t *testing.T) {
 	model = model.startInput(inputImportOPML, "Import")
 	model.input.SetValue(opmlPath)
 	model = model.commitInput()
+	if model.importPlan == nil {
+		t.Fatalf("expected import report")
+	}
+	model = model.updateImportPlan("enter")
 	if len(model.app.feeds) == 0 {
 		t.Fatalf("expected import feeds")
 	}
@@ -514,10 +521,11 @@ func TestSummaryCmdSuccess(t *testing.T) {
 		model:   "m",
 		client:  clientForResponse(http.StatusOK, `{"choices":[{"message":{"content":"- ok"}}]}`, map[string]string{"content-type": "application/json"}),
 	}
-	cmd := summaryCmd(7, "Title", "Content", summarizer)
+	article := Article{ID: 7, Title: "Title", ContentText: "Content"}
+	cmd := summaryCmd(article, SummaryOptions{}, articleContentHash(article), summarizer)
 	msg := cmd()
 	result := msg.(summaryResultMsg)
-	if result.articleID != 7 || result.err != nil || result.summaryText == "" {
+	if result.articleID != 7 || result.err != nil || result.summaryText == "" || result.contentHash != articleContentHash(article) {
 		t.Fatalf("expected summary result success")
 	}
 }
@@ -586,7 +594,7 @@ func TestTUIBatchQueue(t *testing.T) {
 	if len(model.summaryQueue) != 1 || !model.batchActive {
 		t.Fatalf("expected batch queue")
 	}
-	if cmd := model.startNextBatchSummary(); cmd == nil {
+	if cmd := model.startBatchSummaries(); cmd == nil {
 		t.Fatalf("expected batch command")
 	}
 }
@@ -931,3 +939,414 @@ func TestTUIViewWithStatus(t *testing.T) {
 		t.Fatalf("expected status")
 	}
 }
+
+func TestTUIStaleSummaryRegenerates(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1", ContentText: "first"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "- old", ContentHash: "outdated"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.syncSummaryForSelection()
+	app.summarizer = &Summarizer{baseURL: "http://example.test", model: "m", client: clientForResponse(http.StatusOK, `{"choices":[{"message":{"content":"- new"}}]}`, map[string]string{"content-type": "application/json"})}
+	model := newTUIModel(app)
+	if text := model.summaryText(); !strings.Contains(text, "- old") || !strings.Contains(text, "Press Enter to regenerate") {
+		t.Fatalf("expected stale summary text, got %q", text)
+	}
+	model.queueMissingSummaries()
+	if len(model.summaryQueue) != 1 {
+		t.Fatalf("expected stale summary to be queued")
+	}
+	cmd := model.startSummary(app.articles[0])
+	if cmd == nil {
+		t.Fatalf("expected regenerate command")
+	}
+	updated, _ := model.Update(cmd())
+	model = updated.(tuiModel)
+	stored, ok := app.store.FindSummary(articles[0].ID)
+	if !ok || stored.Content != "- new" || stored.ContentHash != articleContentHash(app.articles[0]) {
+		t.Fatalf("expected regenerated summary, got %+v", stored)
+	}
+}
+
+func TestTUIParallelBatchSummaries(t *testing.T) {
+	app := newTUIApp(t)
+	app.config.SummaryWorkers = 2
+	app.summarizer = &Summarizer{
+		baseURL: "http://example.test",
+		model:   "m",
+		client:  clientForResponse(http.StatusOK, `{"choices":[{"message":{"content":"- ok"}}]}`, map[string]string{"content-type": "application/json"}),
+	}
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	incoming := []Article{}
+	for i := 1; i <= 5; i++ {
+		incoming = append(incoming, Article{GUID: strconv.Itoa(i), Title: "Article " + strconv.Itoa(i), URL: "https://example.com/" + strconv.Itoa(i)})
+	}
+	if _, err := app.store.InsertArticles(feed, incoming); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	model := newTUIModel(app)
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	model = updated.(tuiModel)
+	if cmd == nil || len(model.batchRunning) != 2 || len(model.summaryQueue) != 3 || model.batchTotal != 5 {
+		t.Fatalf("expected two workers running, got running=%d queued=%d", len(model.batchRunning), len(model.summaryQueue))
+	}
+	if again := model.startBatchSummaries(); again != nil || len(model.batchRunning) != 2 {
+		t.Fatalf("expected worker limit to hold")
+	}
+
+	running := []int{}
+	for id := range model.batchRunning {
+		running = append(running, id)
+	}
+	updated, _ = model.Update(summaryResultMsg{articleID: running[0], err: errors.New("boom")})
+	model = updated.(tuiModel)
+	if len(model.batchRunning) != 2 || model.batchDone != 1 || model.batchFailed != 1 {
+		t.Fatalf("expected failed worker replaced, got running=%d done=%d", len(model.batchRunning), model.batchDone)
+	}
+	if !strings.Contains(model.renderStatusBar(120), "Summarizing 1/5 (2 running, 1 failed)") {
+		t.Fatalf("unexpected progress: %q", app.status)
+	}
+
+	for model.batchActive {
+		var id int
+		for running := range model.batchRunning {
+			id = running
+			break
+		}
+		updated, _ = model.Update(summaryResultMsg{articleID: id, summaryText: "- ok"})
+		model = updated.(tuiModel)
+	}
+	if app.status != "Batch summaries complete: 4/5 (1 failed)" || len(app.store.Summaries()) != 4 {
+		t.Fatalf("unexpected completion status: %q", app.status)
+	}
+}
+
+func TestTUISummaryVersionKeys(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1", ContentText: "body"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	for _, content := range []string{"- first take", "- second take"} {
+		if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: content, Model: "m", ContentHash: articleContentHash(articles[0])}); err != nil {
+			t.Fatalf("UpsertSummary error: %v", err)
+		}
+	}
+	app.articles = app.store.SortedArticles()
+	app.syncSummaryForSelection()
+	model := newTUIModel(app)
+	model.width = 120
+	model.height = 40
+	if view := model.View(); !strings.Contains(view, "Version 2/2 (latest)") {
+		t.Fatalf("expected version label in details")
+	}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")})
+	model = updated.(tuiModel)
+	if app.current.Content != "- first take" || !strings.Contains(model.View(), "Version 1/2") {
+		t.Fatalf("expected older version shown")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	model = updated.(tuiModel)
+	if app.current.Content != "- second take" {
+		t.Fatalf("expected newer version shown")
+	}
+
+	var out bytes.Buffer
+	if err := handleCommand(app, "older", &out); err != nil || app.current.Content != "- first take" {
+		t.Fatalf("expected older command to cycle")
+	}
+	if err := handleCommand(app, "]", &out); err != nil || app.current.Content != "- second take" {
+		t.Fatalf("expected ] command to cycle")
+	}
+}
+
+func TestTUIEscCancelsInFlightSummary(t *testing.T) {
+	app := newTUIApp(t)
+	started := make(chan struct{})
+	app.summarizer = &Summarizer{
+		baseURL: "http://example.test",
+		model:   "m",
+		client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			close(started)
+			<-r.Context().Done()
+			return nil, r.Context().Err()
+		})},
+	}
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "One", URL: "u1", ContentText: "text"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	model := newTUIModel(app)
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	if cmd == nil {
+		t.Fatalf("expected summary command")
+	}
+	result := make(chan tea.Msg, 1)
+	go func() { result <- cmd() }()
+	<-started
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(tuiModel)
+	if model.app.status != "Cancelling..." {
+		t.Fatalf("unexpected status %q", model.app.status)
+	}
+	var msg tea.Msg
+	select {
+	case msg = <-result:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("summary request was not cancelled")
+	}
+	updated, _ = model.Update(msg)
+	model = updated.(tuiModel)
+	if model.app.status != "Summary cancelled" || model.app.summaryStatus != SummaryNotGenerated {
+		t.Fatalf("unexpected state %q %q", model.app.status, model.app.summaryStatus)
+	}
+	if model.ctx.Err() != nil {
+		t.Fatalf("expected a fresh context after cancel")
+	}
+
+	model.app.status = "idle"
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(tuiModel).app.status != "idle" {
+		t.Fatalf("esc with nothing running should be a no-op")
+	}
+}
+
+func TestRefreshCmdReportsCancellation(t *testing.T) {
+	app := newTUIApp(t)
+	if _, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"}); err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	app.feeds = app.store.Feeds()
+	app.fetcher.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, r.Context().Err()
+	})}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	model := newTUIModel(app)
+	model.app.refreshPending = true
+	updated, _ := model.Update(refreshCmd(app, ctx)())
+	model = updated.(tuiModel)
+	if model.app.refreshPending || model.app.status != "Refresh cancelled" {
+		t.Fatalf("unexpected state %v %q", model.app.refreshPending, model.app.status)
+	}
+}
+
+func TestWithContextBindsClients(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var nilSummarizer *Summarizer
+	var nilRaindrop *RaindropClient
+	if nilSummarizer.withContext(ctx) != nil || nilRaindrop.withContext(ctx) != nil {
+		t.Fatalf("expected nil clients to stay nil")
+	}
+	fallback := &Summarizer{name: "fallback"}
+	summarizer := (&Summarizer{name: "primary", fallbacks: []*Summarizer{fallback}}).withContext(ctx)
+	for _, provider := range summarizer.providers() {
+		if provider.requestContext() != ctx {
+			t.Fatalf("provider %s not bound", provider.name)
+		}
+	}
+	if fallback.ctx != nil {
+		t.Fatalf("original fallback mutated")
+	}
+
+	contextClient := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if err := r.Context().Err(); err != nil {
+			return nil, err
+		}
+		return newResponse(http.StatusOK, `{"item":{"_id":1}}`, nil, r), nil
+	})}
+	raindrop := (&RaindropClient{baseURL: "http://example.test", client: contextClient}).withContext(ctx)
+	if _, err := raindrop.Save(RaindropItem{Link: "https://example.com"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled save, got %v", err)
+	}
+	fetcher := (&FeedFetcher{client: contextClient}).withContext(ctx)
+	if _, err := fetcher.DiscoverFeed("http://example.test"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled discover, got %v", err)
+	}
+	if NewFeedFetcher().requestContext() == nil {
+		t.Fatalf("expected background context")
+	}
+}
+
+func TestTUIFeedSidebar(t *testing.T) {
+	app, tech, _ := newFolderApp(t)
+	model := newTUIModel(app)
+	model.width, model.height = 120, 30
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model = updated.(tuiModel)
+	if !model.showSidebar || model.sidebarIndex != 0 || !strings.Contains(model.View(), "Work/") {
+		t.Fatalf("expected sidebar open:\n%s", model.View())
+	}
+	for i := 0; i < 2; i++ {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+		model = updated.(tuiModel)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(tuiModel)
+	if model.showSidebar || model.app.scope.FeedID != tech.ID || len(model.app.FilteredArticles()) != 1 {
+		t.Fatalf("expected feed scope, got %+v", model.app.scope)
+	}
+	if !strings.Contains(model.View(), "Greeder · Tech") {
+		t.Fatalf("expected scope in header:\n%s", model.View())
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model = updated.(tuiModel)
+	if model.sidebarIndex != 2 {
+		t.Fatalf("expected sidebar to open on current scope, got %d", model.sidebarIndex)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	model = updated.(tuiModel)
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	model = updated.(tuiModel)
+	if !model.showSidebar || model.sidebarIndex != 1 {
+		t.Fatalf("expected sidebar to stay open for other keys")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(tuiModel)
+	if model.showSidebar || model.app.scope.FeedID != tech.ID {
+		t.Fatalf("expected esc to close without changing scope")
+	}
+}
+
+func TestTUIFeedManagement(t *testing.T) {
+	app, tech, news := newFolderApp(t)
+	model := newTUIModel(app)
+	model.width, model.height = 120, 30
+	press := func(keys ...string) {
+		for _, key := range keys {
+			var msg tea.KeyMsg
+			switch key {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "esc":
+				msg = tea.KeyMsg{Type: tea.KeyEsc}
+			default:
+				msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+			}
+			updated, _ := model.Update(msg)
+			model = updated.(tuiModel)
+		}
+	}
+	press("F")
+	view := model.View()
+	if !model.showFeeds || !strings.Contains(view, "Feeds (2)") || !strings.Contains(view, "1 Tech (1 unread") {
+		t.Fatalf("expected feed screen:\n%s", view)
+	}
+	press("n")
+	if model.inputMode != inputRenameFeed || model.input.Value() != "Tech" {
+		t.Fatalf("expected rename input prefilled, got %q", model.input.Value())
+	}
+	model.input.SetValue("Engineering")
+	press("enter")
+	if app.findFeed(tech.ID).Title != "Engineering" || !model.showFeeds {
+		t.Fatalf("expected rename to stay on feed screen, got %+v", app.feeds)
+	}
+	press("j", "u")
+	if model.inputMode != inputFeedURL || model.input.Value() != news.URL {
+		t.Fatalf("expected url input prefilled, got %q", model.input.Value())
+	}
+	model.input.SetValue(tech.URL)
+	press("enter")
+	if !strings.HasPrefix(app.status, "URL change failed") {
+		t.Fatalf("expected duplicate url failure, got %q", app.status)
+	}
+	press("d")
+	if len(app.feeds) != 2 || app.status != "Press d again to remove News" {
+		t.Fatalf("expected remove confirmation, got %q", app.status)
+	}
+	press("j", "d")
+	if len(app.feeds) != 2 {
+		t.Fatalf("expected other keys to reset the confirmation")
+	}
+	press("d", "d")
+	if len(app.feeds) != 1 || model.feedIndex != 0 {
+		t.Fatalf("expected feed removed, got %+v index %d", app.feeds, model.feedIndex)
+	}
+	press("enter")
+	if model.showFeeds || app.scope.FeedID != tech.ID {
+		t.Fatalf("expected enter to show the feed's articles, got %+v", app.scope)
+	}
+	app.fetcher.client = clientForResponse(http.StatusOK, rssSample, map[string]string{"content-type": "application/rss+xml"})
+	press("F")
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	model = updated.(tuiModel)
+	if cmd == nil || !app.refreshPending {
+		t.Fatalf("expected single feed refresh command")
+	}
+	updated, _ = model.Update(cmd())
+	model = updated.(tuiModel)
+	if app.refreshPending || !strings.HasPrefix(app.status, "Refreshed Engineering; ") {
+		t.Fatalf("unexpected refresh status %q", app.status)
+	}
+	press("esc")
+	if model.showFeeds {
+		t.Fatalf("expected esc to close the feed screen")
+	}
+}
+
+func TestTUIRegenerateSummaryWithModel(t *testing.T) {
+	app := newTUIApp(t)
+	app.filter = FilterAll
+	feed, _ := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if _, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1", ContentText: "body"}}); err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.loadArticles()
+	app.summarizer = modelEchoSummarizer()
+	model := newTUIModel(app)
+	model.width = 120
+	model.height = 40
+	press := func(msg tea.KeyMsg) tea.Cmd {
+		updated, cmd := model.Update(msg)
+		model = updated.(tuiModel)
+		return cmd
+	}
+	model = runCmd(t, model, press(tea.KeyMsg{Type: tea.KeyEnter}))
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if model.inputMode != inputRegenerate || model.input.Value() != "default-model" {
+		t.Fatalf("expected model prompt prefilled, got %v %q", model.inputMode, model.input.Value())
+	}
+	model.input.SetValue("other-model")
+	cmd := press(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.status != "Regenerating summary with other-model" || app.summaryStatus != SummaryGenerating {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	model = runCmd(t, model, cmd)
+	if app.current.Content != "- by other-model" || !strings.Contains(model.View(), "Version 2/2 (latest): other-model") {
+		t.Fatalf("expected regenerated summary, got %q", app.current.Content)
+	}
+}
+
+func runCmd(t *testing.T, model tuiModel, cmd tea.Cmd) tuiModel {
+	t.Helper()
+	if cmd == nil {
+		t.Fatalf("expected a command")
+	}
+	updated, _ := model.Update(cmd())
+	return updated.(tuiModel)
+}
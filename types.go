@@ -3,14 +3,22 @@ package main
 import "time"
 
 type Feed struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	URL         string    `json:"url"`
-	SiteURL     string    `json:"site_url"`
-	Description string    `json:"description"`
-	LastFetched time.Time `json:"last_fetched"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           int       `json:"id"`
+	Title        string    `json:"title"`
+	URL          string    `json:"url"`
+	SiteURL      string    `json:"site_url"`
+	Description  string    `json:"description"`
+	LastFetched  time.Time `json:"last_fetched"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Source       string    `json:"source,omitempty"`
+	Folder       string    `json:"folder,omitempty"`
+	ETag         string    `json:"-"`
+	LastModified string    `json:"-"`
+	LastError    string    `json:"-"`
+	FailCount    int       `json:"-"`
+	LastSuccess  time.Time `json:"-"`
+	LastFailure  time.Time `json:"-"`
 }
 
 type Article struct {
@@ -28,31 +36,117 @@ type Article struct {
 	IsRead      bool      `json:"is_read"`
 	IsStarred   bool      `json:"is_starred"`
 	FeedTitle   string    `json:"feed_title"`
+	Score       int       `json:"score"`
 }
 
 type Summary struct {
-	ID          int       `json:"id"`
-	ArticleID   int       `json:"article_id"`
-	Content     string    `json:"content"`
-	Model       string    `json:"model"`
-	GeneratedAt time.Time `json:"generated_at"`
+	ID               int       `json:"id"`
+	ArticleID        int       `json:"article_id"`
+	Content          string    `json:"content"`
+	Model            string    `json:"model"`
+	GeneratedAt      time.Time `json:"generated_at"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	ContentHash      string    `json:"content_hash"`
+}
+
+type SummaryUsage struct {
+	WeekStart        time.Time
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+}
+
+type UsageRecord struct {
+	RecordedAt       time.Time
+	Provider         string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
 }
 
 type ArticleSource struct {
 	FeedTitle   string
+	Source      string
 	PublishedAt time.Time
 }
 
+type Session struct {
+	ArticleID int
+	Filter    FilterMode
+	SortMode  SortMode
+}
+
 type Saved struct {
 	ArticleID  int       `json:"article_id"`
+	Provider   string    `json:"provider,omitempty"`
 	RaindropID int       `json:"raindrop_id"`
 	Tags       []string  `json:"tags"`
 	SavedAt    time.Time `json:"saved_at"`
 }
 
+type ArticleTag struct {
+	ArticleID int    `json:"article_id"`
+	Tag       string `json:"tag"`
+	Source    string `json:"source"`
+}
+
 type Deleted struct {
+	ID        int       `json:"-"`
 	FeedID    int       `json:"feed_id"`
 	GUID      string    `json:"guid"`
 	DeletedAt time.Time `json:"deleted_at"`
 	Article   Article   `json:"article"`
 }
+
+type RetentionPolicy struct {
+	Days  int
+	Items int
+}
+
+type FeedAuth struct {
+	Username    string
+	Password    string
+	HeaderName  string
+	HeaderValue string
+}
+
+type AccountConfig struct {
+	Name     string
+	Backend  string
+	URL      string
+	Username string
+	Password string
+	Token    string
+}
+
+type ProviderConfig struct {
+	Name           string
+	Preset         string
+	API            string
+	BaseURL        string
+	Model          string
+	APIKey         string
+	TimeoutSeconds int
+	Temperature    *float64
+}
+
+type SummaryOptions struct {
+	Model   string
+	Prompt  string
+	Style   string
+	Profile string
+}
+
+type NotifierConfig struct {
+	Name        string
+	Type        string
+	WebhookURL  string
+	Homeserver  string
+	RoomID      string
+	AccessToken string
+	BotToken    string
+	ChatID      string
+	Feeds       []string
+	Events      []string
+}
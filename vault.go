@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	vaultNoteDaily          = "daily"
+	defaultVaultDailyFormat = "2006-01-02"
+)
+
+var (
+	vaultReadFile   = os.ReadFile
+	vaultAppendFile = defaultVaultAppendFile
+)
+
+func defaultVaultAppendFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func vaultNotePath(cfg Config, now time.Time) string {
+	dir := expandHome(strings.TrimSpace(cfg.VaultDir))
+	note := strings.TrimSpace(cfg.VaultNote)
+	if note == "" || note == vaultNoteDaily {
+		note = now.Format(firstNonEmpty(cfg.VaultDailyFormat, defaultVaultDailyFormat))
+	}
+	if !strings.HasSuffix(note, ".md") {
+		note += ".md"
+	}
+	return filepath.Join(dir, filepath.FromSlash(note))
+}
+
+func vaultHashtags(tags []string) string {
+	hashtags := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.Join(strings.Fields(tag), "-"); tag != "" {
+			hashtags = append(hashtags, "#"+tag)
+		}
+	}
+	return strings.Join(hashtags, " ")
+}
+
+func renderVaultEntry(article Article, summary string, tags []string, date string, daily bool, template string) string {
+	summary = strings.TrimSpace(summary)
+	if template != "" {
+		entry := expandEmailTemplate(template, map[string]string{
+			"title":    article.Title,
+			"url":      article.URL,
+			"feed":     article.FeedTitle,
+			"author":   article.Author,
+			"date":     date,
+			"tags":     strings.Join(tags, ", "),
+			"hashtags": vaultHashtags(tags),
+			"summary":  summary,
+		})
+		return strings.TrimRight(entry, "\n") + "\n"
+	}
+	line := "- "
+	if !daily {
+		line += "[[" + date + "]] "
+	}
+	line += "[" + article.Title + "](" + article.URL + ")"
+	if article.FeedTitle != "" {
+		line += " · [[" + article.FeedTitle + "]]"
+	}
+	if hashtags := vaultHashtags(tags); hashtags != "" {
+		line += " " + hashtags
+	}
+	lines := []string{line}
+	for _, point := range strings.Split(summary, "\n") {
+		point = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(point), "-*•"))
+		if point != "" {
+			lines = append(lines, "  - "+point)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (a *App) AppendToVault(article Article) (string, bool, error) {
+	if strings.TrimSpace(a.config.VaultDir) == "" {
+		return "", false, errors.New("vault dir not configured")
+	}
+	now := a.now()
+	path := vaultNotePath(a.config, now)
+	existing, err := vaultReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return path, false, err
+	}
+	if article.URL != "" && strings.Contains(string(existing), "("+article.URL+")") {
+		return path, false, nil
+	}
+	summary := ""
+	if stored, ok := a.store.FindSummary(article.ID); ok {
+		summary = stored.Content
+	}
+	note := strings.TrimSpace(a.config.VaultNote)
+	daily := note == "" || note == vaultNoteDaily
+	entry := renderVaultEntry(article, summary, a.noteTags(article), now.Format("2006-01-02"), daily, a.config.VaultTemplate)
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		entry = "\n" + entry
+	}
+	if err := notesMkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return path, false, err
+	}
+	if err := vaultAppendFile(path, []byte(entry)); err != nil {
+		return path, false, err
+	}
+	return path, true, nil
+}
+
+func (a *App) AppendSelectedToVault() error {
+	article := a.SelectedArticle()
+	if article == nil {
+		return nil
+	}
+	path, added, err := a.AppendToVault(*article)
+	switch {
+	case err != nil:
+		a.status = tr(msgVaultFailed, err)
+		return err
+	case added:
+		a.status = tr(msgVaultAdded, filepath.Base(path))
+	default:
+		a.status = tr(msgVaultPresent, filepath.Base(path))
+	}
+	return nil
+}
+
+func (a *App) appendToVaultOn(event string, article Article) {
+	if a.config.VaultDir == "" || !slices.Contains(a.config.VaultAuto, event) {
+		return
+	}
+	path, added, err := a.AppendToVault(article)
+	if err != nil {
+		a.status = strings.TrimPrefix(a.status+"; "+tr(msgVaultFailed, err), "; ")
+		return
+	}
+	if added {
+		a.status = strings.TrimPrefix(a.status+"; "+tr(msgVaultAdded, filepath.Base(path)), "; ")
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestVaultHelpers(t *testing.T) {
+	now := time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC)
+	if path := vaultNotePath(Config{VaultDir: "/v"}, now); path != filepath.Join("/v", "2024-03-05.md") {
+		t.Fatalf("unexpected daily path %q", path)
+	}
+	if path := vaultNotePath(Config{VaultDir: "/v", VaultNote: "daily", VaultDailyFormat: "journals/2006_01_02"}, now); path != filepath.Join("/v", "journals", "2024_03_05.md") {
+		t.Fatalf("unexpected logseq path %q", path)
+	}
+	if path := vaultNotePath(Config{VaultDir: "/v", VaultNote: "Reading.md"}, now); path != filepath.Join("/v", "Reading.md") {
+		t.Fatalf("unexpected note path %q", path)
+	}
+	if tags := vaultHashtags([]string{"go", "machine learning", " "}); tags != "#go #machine-learning" {
+		t.Fatalf("unexpected hashtags %q", tags)
+	}
+
+	article := Article{Title: "Post", URL: "https://example.com/1", FeedTitle: "Feed"}
+	daily := renderVaultEntry(article, "- one\n* two\n", []string{"go"}, "2024-03-05", true, "")
+	if daily != "- [Post](https://example.com/1) · [[Feed]] #go\n  - one\n  - two\n" {
+		t.Fatalf("unexpected daily entry %q", daily)
+	}
+	if entry := renderVaultEntry(Article{Title: "T", URL: "u"}, "", nil, "2024-03-05", false, ""); entry != "- [[2024-03-05]] [T](u)\n" {
+		t.Fatalf("unexpected reading entry %q", entry)
+	}
+	if entry := renderVaultEntry(article, "sum", []string{"go", "rss"}, "2024-03-05", true, "* [[{title}]] {tags} {hashtags}\n  {summary}\n\n"); entry != "* [[Post]] go, rss #go #rss\n  sum\n" {
+		t.Fatalf("unexpected templated entry %q", entry)
+	}
+
+	var cfg Config
+	if err := parseConfig("[vault]\ndir = \"~/vault\"\nnote = \"Reading\"\nauto = [\"star\"]", &cfg); err != nil || cfg.VaultDir != "~/vault" || cfg.VaultNote != "Reading" || len(cfg.VaultAuto) != 1 {
+		t.Fatalf("unexpected vault config: %+v %v", cfg, err)
+	}
+	if rendered := renderConfig(cfg); !strings.Contains(rendered, "[vault]\ndir = \"~/vault\"\nnote = \"Reading\"\nauto = [\"star\"]") {
+		t.Fatalf("expected vault section:\n%s", rendered)
+	}
+	if err := parseConfig("[vault]\nauto = [\"read\"]", &cfg); err == nil || !strings.Contains(err.Error(), "invalid vault.auto event") {
+		t.Fatalf("expected invalid event error, got %v", err)
+	}
+}
+
+func TestAppAppendToVault(t *testing.T) {
+	app := newTUIApp(t)
+	app.setClock(&testClock{now: time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC)})
+	app.location = time.UTC
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{
+		{GUID: "1", Title: "First", URL: "https://example.com/1", PublishedAt: time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)},
+		{GUID: "2", Title: "Second", URL: "https://example.com/2", PublishedAt: time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	if _, err := app.store.UpsertSummary(Summary{ArticleID: articles[0].ID, Content: "- point"}); err != nil {
+		t.Fatalf("UpsertSummary error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	app.filter = FilterAll
+
+	if err := app.AppendSelectedToVault(); err == nil || app.status != "Vault note failed: vault dir not configured" {
+		t.Fatalf("expected missing dir error, got %v %q", err, app.status)
+	}
+	dir := t.TempDir()
+	note := filepath.Join(dir, "2024-03-05.md")
+	if err := os.WriteFile(note, []byte("# Tuesday"), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+	app.config.VaultDir = dir
+	model := newTUIModel(app)
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("V")})
+	model = updated.(tuiModel)
+	if app.status != "Added to 2024-03-05.md" {
+		t.Fatalf("unexpected status %q", app.status)
+	}
+	var out bytes.Buffer
+	if err := handleCommand(app, "vault", &out); err != nil || app.status != "Already in 2024-03-05.md" {
+		t.Fatalf("expected duplicate to be skipped: %v %q", err, app.status)
+	}
+	data, _ := os.ReadFile(note)
+	if string(data) != "# Tuesday\n- [First](https://example.com/1) · [[Feed]] #rss\n  - point\n" {
+		t.Fatalf("unexpected daily note %q", data)
+	}
+
+	app.config.VaultNote = "Reading"
+	app.config.VaultAuto = []string{"star"}
+	app.selectedIndex = 1
+	app.status = ""
+	if err := app.ToggleStar(); err != nil || app.status != "Added to Reading.md" {
+		t.Fatalf("expected star to add to vault: %v %q", err, app.status)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "Reading.md"))
+	if string(data) != "- [[2024-03-05]] [Second](https://example.com/2) · [[Feed]] #rss\n" {
+		t.Fatalf("unexpected reading note %q", data)
+	}
+
+	app.status = ""
+	app.config.VaultAuto = nil
+	_ = app.ToggleStar()
+	_ = app.ToggleStar()
+	if app.status != "" {
+		t.Fatalf("expected no vault write without auto, got %q", app.status)
+	}
+
+	app.config.VaultAuto = []string{"star"}
+	app.selectedIndex = 0
+	origAppend := vaultAppendFile
+	vaultAppendFile = func(string, []byte) error { return errors.New("disk full") }
+	t.Cleanup(func() { vaultAppendFile = origAppend })
+	app.config.VaultNote = "Other"
+	app.status = ""
+	if err := app.ToggleStar(); err != nil || app.status != "Vault note failed: disk full" {
+		t.Fatalf("expected auto failure in status: %v %q", err, app.status)
+	}
+	origRead := vaultReadFile
+	vaultReadFile = func(string) ([]byte, error) { return nil, errors.New("denied") }
+	t.Cleanup(func() { vaultReadFile = origRead })
+	if _, _, err := app.AppendToVault(articles[0]); err == nil {
+		t.Fatalf("expected read error")
+	}
+
+	app.articles = nil
+	if err := app.AppendSelectedToVault(); err != nil {
+		t.Fatalf("expected no-op without selection: %v", err)
+	}
+}
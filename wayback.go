@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+type WaybackClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+const (
+	waybackEventBookmark = "bookmark"
+	waybackEventStar     = "star"
+)
+
+func NewWaybackClient(events []string) *WaybackClient {
+	if len(events) == 0 {
+		return nil
+	}
+	base := strings.TrimSpace(os.Getenv("WAYBACK_BASE_URL"))
+	if base == "" {
+		base = "https://web.archive.org"
+	}
+	return &WaybackClient{
+		baseURL: strings.TrimRight(base, "/"),
+		client:  &http.Client{Timeout: 90 * time.Second},
+	}
+}
+
+func (w *WaybackClient) Save(link string) (string, error) {
+	if w == nil {
+		return "", errors.New("wayback not configured")
+	}
+	if strings.TrimSpace(link) == "" {
+		return "", errors.New("missing url")
+	}
+	resp, err := w.client.Get(w.baseURL + "/save/" + link)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("wayback http %d", resp.StatusCode)
+	}
+	if location := resp.Header.Get("Content-Location"); strings.HasPrefix(location, "/web/") {
+		return w.baseURL + location, nil
+	}
+	if resp.Request != nil && strings.HasPrefix(resp.Request.URL.Path, "/web/") {
+		return resp.Request.URL.String(), nil
+	}
+	return w.baseURL + "/web/" + link, nil
+}
+
+func (a *App) archiveToWayback(event string, article Article) {
+	if a.wayback == nil || !slices.Contains(a.config.Wayback, event) {
+		return
+	}
+	if a.store.WaybackSnapshot(article.ID) != "" {
+		return
+	}
+	snapshot, err := a.wayback.Save(article.URL)
+	if err == nil {
+		err = a.store.SetWaybackSnapshot(article.ID, snapshot)
+	}
+	if err != nil {
+		a.status = strings.TrimPrefix(a.status+"; wayback failed: "+err.Error(), "; ")
+		return
+	}
+	a.status = strings.TrimPrefix(a.status+"; archived to Wayback Machine", "; ")
+}
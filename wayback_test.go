@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWaybackClient(t *testing.T) {
+	if NewWaybackClient(nil) != nil {
+		t.Fatalf("expected nil client without events")
+	}
+	t.Setenv("WAYBACK_BASE_URL", "http://wayback.test/")
+	client := NewWaybackClient([]string{"star"})
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/save/https://example.com/a" {
+			return newResponse(http.StatusNotFound, "", nil, r), nil
+		}
+		return newResponse(http.StatusOK, "", map[string]string{"Content-Location": "/web/20240305120000/https://example.com/a"}, r), nil
+	})}
+	if snapshot, err := client.Save("https://example.com/a"); err != nil || snapshot != "http://wayback.test/web/20240305120000/https://example.com/a" {
+		t.Fatalf("unexpected snapshot %q %v", snapshot, err)
+	}
+
+	client.client = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(r.URL.Path, "/save/") {
+			return newResponse(http.StatusFound, "", map[string]string{"Location": "/web/20240305120001/https://example.com/a"}, r), nil
+		}
+		return newResponse(http.StatusOK, "", nil, r), nil
+	})}
+	if snapshot, err := client.Save("https://example.com/a"); err != nil || snapshot != "http://wayback.test/web/20240305120001/https://example.com/a" {
+		t.Fatalf("unexpected redirected snapshot %q %v", snapshot, err)
+	}
+	client.client = clientForResponse(http.StatusOK, "", nil)
+	if snapshot, err := client.Save("https://example.com/a"); err != nil || snapshot != "http://wayback.test/web/https://example.com/a" {
+		t.Fatalf("unexpected fallback snapshot %q %v", snapshot, err)
+	}
+	client.client = clientForResponse(http.StatusTooManyRequests, "", nil)
+	if _, err := client.Save("https://example.com/a"); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	client.client = &http.Client{Transport: &errorRoundTripper{}}
+	if _, err := client.Save("https://example.com/a"); err == nil {
+		t.Fatalf("expected transport error")
+	}
+	if _, err := client.Save(" "); err == nil {
+		t.Fatalf("expected missing url error")
+	}
+	var nilClient *WaybackClient
+	if _, err := nilClient.Save("https://example.com"); err == nil {
+		t.Fatalf("expected nil client error")
+	}
+}
+
+func TestAppArchiveToWayback(t *testing.T) {
+	app := newTUIApp(t)
+	feed, err := app.store.InsertFeed(Feed{Title: "Feed", URL: "https://example.com/rss"})
+	if err != nil {
+		t.Fatalf("InsertFeed error: %v", err)
+	}
+	articles, err := app.store.InsertArticles(feed, []Article{{GUID: "1", Title: "Post", URL: "https://example.com/1"}})
+	if err != nil {
+		t.Fatalf("InsertArticles error: %v", err)
+	}
+	app.articles = app.store.SortedArticles()
+	id := articles[0].ID
+
+	calls := 0
+	wayback := &WaybackClient{baseURL: "http://wayback.test", client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return newResponse(http.StatusOK, "", map[string]string{"Content-Location": "/web/1/https://example.com/1"}, r), nil
+	})}}
+	app.wayback = wayback
+	app.config.Wayback = []string{waybackEventBookmark}
+	if err := app.ToggleStar(); err != nil || calls != 0 {
+		t.Fatalf("expected star to skip wayback when only bookmark is enabled")
+	}
+	_ = app.ToggleStar()
+
+	app.config.Wayback = []string{waybackEventStar}
+	app.status = ""
+	if err := app.ToggleStar(); err != nil || calls != 1 || app.status != "archived to Wayback Machine" {
+		t.Fatalf("expected star to archive, got %d %q", calls, app.status)
+	}
+	if app.store.WaybackSnapshot(id) != "http://wayback.test/web/1/https://example.com/1" {
+		t.Fatalf("expected snapshot stored")
+	}
+	_ = app.ToggleStar()
+	_ = app.ToggleStar()
+	if calls != 1 {
+		t.Fatalf("expected archived article to be skipped")
+	}
+
+	model := newTUIModel(app)
+	model.width = 160
+	model.height = 40
+	if !strings.Contains(model.View(), "Archived: http://wayback.test/web/1/") {
+		t.Fatalf("expected snapshot in metadata")
+	}
+
+	app.store.db.Exec(`DELETE FROM wayback_snapshots`)
+	app.config.Wayback = []string{waybackEventBookmark}
+	app.pinboard = &PinboardClient{baseURL: "http://pinboard.test", token: "t", client: clientForResponse(http.StatusOK, `{"result_code":"done"}`, nil)}
+	if err := app.SaveBookmarkTo("pinboard", nil); err != nil || calls != 2 || app.status != "Saved to Pinboard; archived to Wayback Machine" {
+		t.Fatalf("expected bookmark to archive, got %v %d %q", err, calls, app.status)
+	}
+
+	app.store.db.Exec(`DELETE FROM wayback_snapshots`)
+	wayback.client = clientForResponse(http.StatusServiceUnavailable, "", nil)
+	if err := app.SaveBookmarkTo("pinboard", nil); err != nil || app.status != "Saved to Pinboard; wayback failed: wayback http 503" {
+		t.Fatalf("expected wayback failure noted in status, got %v %q", err, app.status)
+	}
+	if err := app.SaveBookmarkTo("delicious", nil); err == nil {
+		t.Fatalf("expected unknown target error")
+	}
+}